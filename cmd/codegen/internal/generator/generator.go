@@ -5,8 +5,10 @@ import (
 	"embed"
 	"encoding/json"
 	"fmt"
+	"go/format"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -23,6 +25,11 @@ type Config struct {
 	OutDir    string
 	Package   string
 	Version   string
+
+	// ImportPath is the import path of OutDir. When set, Run also writes
+	// versions.go in OutDir, importing every generated version package so
+	// they all register themselves.
+	ImportPath string
 }
 
 type templateData struct {
@@ -150,6 +157,13 @@ func Run(cfg Config) error {
 			}
 			return templateData{Package: cfg.Package, Version: cfg.Version, Data: data}, nil
 		}},
+		{"protocol.json", "packet_ids.go.tmpl", "packet_ids.go", func(raw []byte) (templateData, error) {
+			data, err := loadPacketIDs(raw)
+			if err != nil {
+				return templateData{}, err
+			}
+			return templateData{Package: cfg.Package, Version: cfg.Version, Data: data}, nil
+		}},
 		{"protocol.json", "packets.go.tmpl", "packets.go", func(raw []byte) (templateData, error) {
 			data, err := loadPacketStructs(raw)
 			if err != nil {
@@ -190,15 +204,53 @@ func Run(cfg Config) error {
 
 	fmt.Printf("  generated gamedata.go\n")
 
+	if cfg.ImportPath != "" {
+		if err := writeVersions(tmpl, cfg.OutDir, cfg.ImportPath); err != nil {
+			return fmt.Errorf("generate versions.go: %w", err)
+		}
+		fmt.Printf("  generated %s\n", filepath.Join(cfg.OutDir, "versions.go"))
+	}
+
 	return nil
 }
 
+// writeVersions writes versions.go in outDir with a blank import of every
+// generated version package found there, so packages generated by separate
+// runs coexist and all register with gamedata.
+func writeVersions(tmpl *template.Template, outDir, importPath string) error {
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		return fmt.Errorf("read output directory: %w", err)
+	}
+
+	var imports []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(outDir, e.Name(), "gamedata.go")); err != nil {
+			continue
+		}
+		imports = append(imports, importPath+"/"+e.Name())
+	}
+	sort.Strings(imports)
+
+	return renderToFile(tmpl, "versions.go.tmpl", filepath.Join(outDir, "versions.go"), templateData{
+		Package: filepath.Base(importPath),
+		Data:    imports,
+	})
+}
+
 func renderToFile(tmpl *template.Template, name, outFile string, data any) error {
 	var buf bytes.Buffer
 	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
 		return fmt.Errorf("execute template %s: %w", name, err)
 	}
-	if err := os.WriteFile(outFile, buf.Bytes(), 0o644); err != nil {
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("format %s: %w", outFile, err)
+	}
+	if err := os.WriteFile(outFile, src, 0o644); err != nil {
 		return fmt.Errorf("write %s: %w", outFile, err)
 	}
 	return nil
@@ -508,6 +560,10 @@ type packetTmpl struct {
 type packetFieldTmpl struct {
 	Name string
 	Type string
+
+	// Raw is the field's type definition when it is not a plain type name,
+	// used to build struct fields for arrays and optional values.
+	Raw json.RawMessage
 }
 
 func loadProtocol(raw []byte) (*protocolTmpl, error) {
@@ -690,24 +746,84 @@ func extractPacketFields(raw json.RawMessage) []packetFieldTmpl {
 			continue
 		}
 		typeName := "complex"
+		var raw json.RawMessage
 		var simpleType string
 		if err := json.Unmarshal(f.Type, &simpleType); err == nil {
 			typeName = simpleType
 		} else if isBufferVarInt(f.Type) {
 			typeName = "ByteArray"
+		} else {
+			raw = f.Type
 		}
-		result = append(result, packetFieldTmpl{Name: f.Name, Type: typeName})
+		result = append(result, packetFieldTmpl{Name: f.Name, Type: typeName, Raw: raw})
 	}
 
 	return result
 }
 
+// Packet IDs — generates named constants per phase and direction.
+
+type packetIDGroupTmpl struct {
+	Comment string
+	Packets []packetIDTmpl
+}
+
+type packetIDTmpl struct {
+	Name string
+	ID   int
+}
+
+func loadPacketIDs(raw []byte) ([]packetIDGroupTmpl, error) {
+	proto, err := loadProtocol(raw)
+	if err != nil {
+		return nil, err
+	}
+	return packetIDGroups(proto), nil
+}
+
+// packetIDGroups groups the packets of each phase by direction and names
+// them <Phase><Direction><Packet>, e.g. PlayServerboundKeepAlive.
+func packetIDGroups(proto *protocolTmpl) []packetIDGroupTmpl {
+	var groups []packetIDGroupTmpl
+	for _, phase := range proto.Phases {
+		directions := []struct {
+			name, comment string
+			packets       []packetTmpl
+		}{
+			{"Clientbound", "server to client", phase.ToClient},
+			{"Serverbound", "client to server", phase.ToServer},
+		}
+		for _, d := range directions {
+			if len(d.packets) == 0 {
+				continue
+			}
+			prefix := snakeToPascal(phase.Name) + d.name
+			group := packetIDGroupTmpl{
+				Comment: fmt.Sprintf("%s phase, %s.", snakeToPascal(phase.Name), d.comment),
+			}
+			for _, p := range d.packets {
+				group.Packets = append(group.Packets, packetIDTmpl{
+					Name: prefix + snakeToPascal(p.Name),
+					ID:   p.ID,
+				})
+			}
+			groups = append(groups, group)
+		}
+	}
+
+	return groups
+}
+
 // Packet Structs — generates Go struct definitions with mc tags.
 
 type packetStructsTmpl struct {
+	Imports []string
 	Packets []packetStructDef
 }
 
+// itemImport is imported by generated packets with slot fields.
+const itemImport = "github.com/go-theft-craft/server/pkg/item"
+
 type packetStructDef struct {
 	StructName string
 	PacketID   int
@@ -742,6 +858,7 @@ var marshalableTypes = map[string]typeMapping{
 	"position":   {"int64", "position"},
 	"ByteArray":  {"[]byte", "bytearray"},
 	"restBuffer": {"[]byte", "rest"},
+	"slot":       {"item.Slot", "slot"},
 }
 
 func loadPacketStructs(raw []byte) (*packetStructsTmpl, error) {
@@ -783,7 +900,94 @@ func loadPacketStructs(raw []byte) (*packetStructsTmpl, error) {
 		return allPackets[i].StructName < allPackets[j].StructName
 	})
 
-	return &packetStructsTmpl{Packets: allPackets}, nil
+	var imports []string
+	for _, p := range allPackets {
+		if slices.ContainsFunc(p.Fields, func(f packetStructFieldDef) bool {
+			return strings.Contains(f.GoType, "item.")
+		}) {
+			imports = append(imports, itemImport)
+			break
+		}
+	}
+
+	return &packetStructsTmpl{Imports: imports, Packets: allPackets}, nil
+}
+
+// arrayCountTypes are the length prefixes an array or buffer may use.
+var arrayCountTypes = map[string]bool{
+	"varint": true,
+	"i8":     true,
+	"u8":     true,
+	"i16":    true,
+	"i32":    true,
+}
+
+// compositeType maps the container shapes the marshaler understands to a Go
+// type and mc tag:
+//
+//	["array", {"countType": C, "type": T}] -> []T  `mc:"array,C,T"`
+//	["buffer", {"countType": C}]           -> []byte `mc:"array,C,u8"`
+//	["option", T]                          -> *T   `mc:"option,T"`
+//
+// T must be a simple type. Arrays whose length comes from another field are
+// not supported.
+func compositeType(raw json.RawMessage) (typeMapping, bool) {
+	var def []json.RawMessage
+	if err := json.Unmarshal(raw, &def); err != nil || len(def) != 2 {
+		return typeMapping{}, false
+	}
+	var kind string
+	if err := json.Unmarshal(def[0], &kind); err != nil {
+		return typeMapping{}, false
+	}
+
+	switch kind {
+	case "array":
+		var opts struct {
+			CountType string          `json:"countType"`
+			Type      json.RawMessage `json:"type"`
+		}
+		if err := json.Unmarshal(def[1], &opts); err != nil || !arrayCountTypes[opts.CountType] {
+			return typeMapping{}, false
+		}
+		elem, ok := simpleElemType(opts.Type)
+		if !ok {
+			return typeMapping{}, false
+		}
+		return typeMapping{
+			goType: "[]" + elem.goType,
+			mcTag:  "array," + opts.CountType + "," + elem.mcTag,
+		}, true
+	case "buffer":
+		var opts struct {
+			CountType string `json:"countType"`
+		}
+		if err := json.Unmarshal(def[1], &opts); err != nil || !arrayCountTypes[opts.CountType] {
+			return typeMapping{}, false
+		}
+		return typeMapping{goType: "[]byte", mcTag: "array," + opts.CountType + ",u8"}, true
+	case "option":
+		elem, ok := simpleElemType(def[1])
+		if !ok {
+			return typeMapping{}, false
+		}
+		return typeMapping{goType: "*" + elem.goType, mcTag: "option," + elem.mcTag}, true
+	}
+	return typeMapping{}, false
+}
+
+// simpleElemType returns the mapping of an array or option element, which
+// must be a simple type (or a varint-prefixed buffer) that can be repeated.
+func simpleElemType(raw json.RawMessage) (typeMapping, bool) {
+	if isBufferVarInt(raw) {
+		return marshalableTypes["ByteArray"], true
+	}
+	var name string
+	if err := json.Unmarshal(raw, &name); err != nil || name == "restBuffer" {
+		return typeMapping{}, false
+	}
+	tm, ok := marshalableTypes[name]
+	return tm, ok
 }
 
 func buildPacketStructDef(p packetTmpl, suffix string) packetStructDef {
@@ -794,6 +998,9 @@ func buildPacketStructDef(p packetTmpl, suffix string) packetStructDef {
 
 	for _, f := range p.Fields {
 		tm, ok := marshalableTypes[f.Type]
+		if !ok && f.Raw != nil {
+			tm, ok = compositeType(f.Raw)
+		}
 		if !ok {
 			allMarshalable = false
 			break
@@ -841,9 +1048,18 @@ func fixAbbreviations(s string) string {
 	s = strings.ReplaceAll(s, "Nbt", "NBT")
 	s = strings.ReplaceAll(s, "Url", "URL")
 
-	// Fix "Id" at word boundaries (end of string or before uppercase letter).
+	// Fix "Id" and "Ids" at word boundaries (end of string or before
+	// uppercase letter).
 	var b strings.Builder
 	for i := 0; i < len(s); i++ {
+		if i+2 < len(s) && s[i] == 'I' && s[i+1] == 'd' && s[i+2] == 's' {
+			atEnd := i+3 >= len(s)
+			if atEnd || (s[i+3] >= 'A' && s[i+3] <= 'Z') {
+				b.WriteString("IDs")
+				i += 2 // skip "ds"
+				continue
+			}
+		}
 		if i+1 < len(s) && s[i] == 'I' && s[i+1] == 'd' {
 			atEnd := i+2 >= len(s)
 			beforeUpper := !atEnd && s[i+2] >= 'A' && s[i+2] <= 'Z'
@@ -0,0 +1,256 @@
+package generator
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// writeDummyScheme writes a scheme directory with empty data for every file
+// the generator reads.
+func writeDummyScheme(t *testing.T, dir string, protocol int) {
+	t.Helper()
+	files := map[string]string{
+		"blocks.json":               "[]",
+		"items.json":                "[]",
+		"entities.json":             "[]",
+		"biomes.json":               "[]",
+		"effects.json":              "[]",
+		"enchantments.json":         "[]",
+		"foods.json":                "[]",
+		"particles.json":            "[]",
+		"instruments.json":          "[]",
+		"attributes.json":           "[]",
+		"windows.json":              "[]",
+		"version.json":              `{"version":` + strconv.Itoa(protocol) + `,"minecraftVersion":"x","majorVersion":"x"}`,
+		"language.json":             "{}",
+		"materials.json":            "{}",
+		"recipes.json":              "{}",
+		"blockCollisionShapes.json": `{"blocks":{},"shapes":{}}`,
+		"protocol.json":             "{}",
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRun_MultipleVersionsRegister(t *testing.T) {
+	root := t.TempDir()
+	outDir := filepath.Join(root, "versions")
+	const importPath = "example.com/gamedata/versions"
+
+	schemes := []struct {
+		version, pkg string
+		protocol     int
+	}{
+		{"pc-1.8", "pc_1_8", 47},
+		{"pc-1.12", "pc_1_12", 335},
+	}
+	for _, s := range schemes {
+		schemeDir := filepath.Join(root, "scheme", s.version)
+		writeDummyScheme(t, schemeDir, s.protocol)
+		if err := Run(Config{
+			SchemeDir:  schemeDir,
+			OutDir:     outDir,
+			Package:    s.pkg,
+			Version:    s.version,
+			ImportPath: importPath,
+		}); err != nil {
+			t.Fatalf("Run(%s): %v", s.version, err)
+		}
+	}
+
+	// Every generated file must parse, and each package registers its own
+	// version.
+	fset := token.NewFileSet()
+	for _, s := range schemes {
+		pkgs, err := parser.ParseDir(fset, filepath.Join(outDir, s.pkg), nil, 0)
+		if err != nil {
+			t.Fatalf("parse %s: %v", s.pkg, err)
+		}
+		if _, ok := pkgs[s.pkg]; !ok {
+			t.Errorf("generated package name = %v, want %s", pkgs, s.pkg)
+		}
+
+		src, err := os.ReadFile(filepath.Join(outDir, s.pkg, "gamedata.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `gamedata.Register("` + s.version + `", New)`; !strings.Contains(string(src), want) {
+			t.Errorf("%s/gamedata.go does not contain %s", s.pkg, want)
+		}
+	}
+
+	// The second run must not drop the first version from versions.go.
+	f, err := parser.ParseFile(fset, filepath.Join(outDir, "versions.go"), nil, parser.ImportsOnly)
+	if err != nil {
+		t.Fatalf("parse versions.go: %v", err)
+	}
+	if f.Name.Name != "versions" {
+		t.Errorf("versions.go package = %s, want versions", f.Name.Name)
+	}
+	imported := map[string]bool{}
+	for _, imp := range f.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		imported[path] = imp.Name != nil && imp.Name.Name == "_"
+	}
+	for _, s := range schemes {
+		if path := importPath + "/" + s.pkg; !imported[path] {
+			t.Errorf("versions.go does not blank-import %s (imports: %v)", path, imported)
+		}
+	}
+}
+
+// playProtocol is a protocol.json with two serverbound and one clientbound
+// play packet.
+const playProtocol = `{
+  "play": {
+    "toClient": {"types": {
+      "packet": ["container", [
+        {"name": "name", "type": ["mapper", {"type": "varint", "mappings": {"0x00": "keep_alive"}}]},
+        {"name": "params", "type": "varint"}
+      ]],
+      "packet_keep_alive": ["container", [{"name": "keepAliveId", "type": "varint"}]]
+    }},
+    "toServer": {"types": {
+      "packet": ["container", [
+        {"name": "name", "type": ["mapper", {"type": "varint", "mappings": {"0x00": "keep_alive", "0x12": "update_sign"}}]},
+        {"name": "params", "type": "varint"}
+      ]],
+      "packet_keep_alive": ["container", [{"name": "keepAliveId", "type": "varint"}]],
+      "packet_update_sign": ["container", [{"name": "location", "type": "position"}]]
+    }}
+  }
+}`
+
+func TestRun_PacketIDConstants(t *testing.T) {
+	root := t.TempDir()
+	schemeDir := filepath.Join(root, "pc-1.8")
+	writeDummyScheme(t, schemeDir, 47)
+	if err := os.WriteFile(filepath.Join(schemeDir, "protocol.json"), []byte(playProtocol), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	outDir := filepath.Join(root, "versions")
+	if err := Run(Config{SchemeDir: schemeDir, OutDir: outDir, Package: "pc_1_8", Version: "pc-1.8"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	path := filepath.Join(outDir, "pc_1_8", "packet_ids.go")
+	if _, err := parser.ParseFile(token.NewFileSet(), path, nil, 0); err != nil {
+		t.Fatalf("parse packet_ids.go: %v", err)
+	}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"// Play phase, client to server.",
+		"PlayServerboundKeepAlive  int32 = 0x00",
+		"PlayServerboundUpdateSign int32 = 0x12",
+		"PlayClientboundKeepAlive int32 = 0x00",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("packet_ids.go does not contain %q:\n%s", want, src)
+		}
+	}
+}
+
+// compositeProtocol has packets with an array of varints, an optional
+// position, an i16-prefixed buffer, slots and an unsupported array of
+// containers.
+const compositeProtocol = `{
+  "play": {
+    "toClient": {"types": {
+      "packet": ["container", [
+        {"name": "name", "type": ["mapper", {"type": "varint", "mappings": {
+          "0x13": "entity_destroy", "0x27": "explosion", "0x2f": "set_slot",
+          "0x30": "window_items", "0x3a": "tab_complete"
+        }}]},
+        {"name": "params", "type": "varint"}
+      ]],
+      "packet_entity_destroy": ["container", [
+        {"name": "entityIds", "type": ["array", {"countType": "varint", "type": "varint"}]}
+      ]],
+      "packet_explosion": ["container", [
+        {"name": "x", "type": "f32"},
+        {"name": "affectedBlockOffsets", "type": ["array", {"countType": "i32", "type": ["container", [{"name": "x", "type": "i8"}]]}]}
+      ]],
+      "packet_set_slot": ["container", [
+        {"name": "windowId", "type": "i8"},
+        {"name": "slot", "type": "i16"},
+        {"name": "item", "type": "slot"}
+      ]],
+      "packet_window_items": ["container", [
+        {"name": "windowId", "type": "u8"},
+        {"name": "items", "type": ["array", {"countType": "i16", "type": "slot"}]}
+      ]],
+      "packet_tab_complete": ["container", [
+        {"name": "matches", "type": ["array", {"countType": "varint", "type": "string"}]}
+      ]]
+    }},
+    "toServer": {"types": {
+      "packet": ["container", [
+        {"name": "name", "type": ["mapper", {"type": "varint", "mappings": {
+          "0x14": "tab_complete", "0x17": "custom_payload"
+        }}]},
+        {"name": "params", "type": "varint"}
+      ]],
+      "packet_tab_complete": ["container", [
+        {"name": "text", "type": "string"},
+        {"name": "block", "type": ["option", "position"]}
+      ]],
+      "packet_custom_payload": ["container", [
+        {"name": "channel", "type": "string"},
+        {"name": "data", "type": ["buffer", {"countType": "i16"}]}
+      ]]
+    }}
+  }
+}`
+
+func TestRun_CompositePacketFields(t *testing.T) {
+	root := t.TempDir()
+	schemeDir := filepath.Join(root, "pc-1.8")
+	writeDummyScheme(t, schemeDir, 47)
+	if err := os.WriteFile(filepath.Join(schemeDir, "protocol.json"), []byte(compositeProtocol), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	outDir := filepath.Join(root, "versions")
+	if err := Run(Config{SchemeDir: schemeDir, OutDir: outDir, Package: "pc_1_8", Version: "pc-1.8"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	path := filepath.Join(outDir, "pc_1_8", "packets.go")
+	if _, err := parser.ParseFile(token.NewFileSet(), path, nil, 0); err != nil {
+		t.Fatalf("parse packets.go: %v", err)
+	}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"EntityIDs []int32 `mc:\"array,varint,varint\"`",
+		"Matches []string `mc:\"array,varint,string\"`",
+		"Block *int64 `mc:\"option,position\"`",
+		"Data    []byte `mc:\"array,i16,u8\"`",
+		"import \"github.com/go-theft-craft/server/pkg/item\"",
+		"Item     item.Slot `mc:\"slot\"`",
+		"Items    []item.Slot `mc:\"array,i16,slot\"`",
+		// Arrays of containers still fall back to the raw payload.
+		"type Explosion struct {\n\tData []byte `mc:\"rest\"`\n}",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("packets.go does not contain %q:\n%s", want, src)
+		}
+	}
+}
@@ -15,6 +15,7 @@ func main() {
 	schemeDir := flag.String("scheme", "", "path to the scheme directory (e.g. ./scheme/pc-1.8)")
 	outDir := flag.String("out", "./pkg/gamedata/versions", "output base directory for generated packages")
 	pkg := flag.String("pkg", "", "package name override (default: derived from scheme dir name)")
+	importPath := flag.String("import", "github.com/go-theft-craft/server/pkg/gamedata/versions", "import path of the output base directory (empty = skip versions.go)")
 
 	flag.Parse()
 
@@ -39,6 +40,8 @@ func main() {
 		OutDir:    *outDir,
 		Package:   pkgName,
 		Version:   version,
+
+		ImportPath: *importPath,
 	}
 
 	if err := generator.Run(cfg); err != nil {
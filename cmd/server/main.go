@@ -9,6 +9,7 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/go-theft-craft/server/internal/server"
@@ -28,9 +29,30 @@ func main() {
 	flag.IntVar(&cfg.ViewDistance, "view-distance", cfg.ViewDistance, "entity view distance in chunks")
 	flag.Int64Var(&cfg.Seed, "seed", cfg.Seed, "world generation seed")
 	flag.StringVar(&cfg.GeneratorType, "generator", cfg.GeneratorType, "world generator type (default, flat)")
+	flag.StringVar(&cfg.FlatLayers, "flat-layers", cfg.FlatLayers, "superflat layers from the bottom up, e.g. minecraft:bedrock,3*minecraft:stone,minecraft:grass")
 	flag.IntVar(&cfg.WorldRadius, "world-radius", cfg.WorldRadius, "world radius in chunks (0 = infinite)")
+	flag.IntVar(&cfg.SpawnChunkRadius, "spawn-chunk-radius", cfg.SpawnChunkRadius, "chunks around spawn to pre-generate and keep loaded (0 = none)")
 	flag.IntVar(&cfg.AutoSaveMinutes, "auto-save", cfg.AutoSaveMinutes, "auto-save interval in minutes (0 = disabled)")
 	flag.IntVar(&cfg.MaxBuildHeight, "max-build-height", cfg.MaxBuildHeight, "maximum Y axis (default 256)")
+	flag.StringVar(&cfg.DefaultGameMode, "default-gamemode", cfg.DefaultGameMode, "game mode for new players (survival, creative, adventure, spectator)")
+	flag.IntVar(&cfg.RandomTickSpeed, "random-tick-speed", cfg.RandomTickSpeed, "random block ticks per chunk section per tick (0 = disabled)")
+	flag.BoolVar(&cfg.SendItemNBT, "send-item-nbt", cfg.SendItemNBT, "include item NBT (enchantments, display names) in inventory slots")
+	flag.BoolVar(&cfg.KickFlyHackers, "kick-fly-hackers", cfg.KickFlyHackers, "kick survival players who repeatedly request flight")
+	flag.BoolVar(&cfg.Whitelist, "whitelist", cfg.Whitelist, "only allow players listed in whitelist.json to join")
+	flag.StringVar(&cfg.CompressSaves, "compress-saves", cfg.CompressSaves, "comma-separated file kinds to gzip (config, world, players, all)")
+	flag.StringVar(&cfg.GameVersion, "game-version", cfg.GameVersion, "game data version to load (e.g. pc-1.8); the network protocol is always 1.8")
+	flag.StringVar(&cfg.PlayerListHeader, "player-list-header", cfg.PlayerListHeader, "text shown above the tab list")
+	flag.StringVar(&cfg.PlayerListFooter, "player-list-footer", cfg.PlayerListFooter, "text shown below the tab list")
+	flag.IntVar(&cfg.CompressionThreshold, "compression-threshold", cfg.CompressionThreshold, "compress packets of at least this many bytes (-1 = disabled)")
+	flag.Func("ops", "comma-separated usernames allowed to run operator commands, in addition to ops.json", func(v string) error {
+		cfg.Ops = cfg.Ops[:0]
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				cfg.Ops = append(cfg.Ops, name)
+			}
+		}
+		return nil
+	})
 	flag.Parse()
 
 	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
@@ -55,6 +77,7 @@ func main() {
 		explicitFlags[f.Name] = true
 	})
 	config.Merge(cfg, fileCfg, explicitFlags)
+	store.SetCompressSaves(cfg.CompressSaves)
 
 	// Save effective config back to file.
 	if err := store.SaveConfig(cfg); err != nil {
@@ -79,7 +102,11 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
-	srv := server.New(cfg, log, store)
+	srv, err := server.New(cfg, log, store)
+	if err != nil {
+		log.Error("create server", "error", err)
+		os.Exit(1)
+	}
 	if err := srv.Start(ctx); err != nil {
 		log.Error("server error", "error", err)
 		os.Exit(1)
@@ -0,0 +1,205 @@
+// Package chat builds JSON chat components for chat messages, kick reasons
+// and other text the client renders.
+package chat
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Chat colors.
+const (
+	Black       = "black"
+	DarkBlue    = "dark_blue"
+	DarkGreen   = "dark_green"
+	DarkAqua    = "dark_aqua"
+	DarkRed     = "dark_red"
+	DarkPurple  = "dark_purple"
+	Gold        = "gold"
+	Gray        = "gray"
+	DarkGray    = "dark_gray"
+	Blue        = "blue"
+	Green       = "green"
+	Aqua        = "aqua"
+	Red         = "red"
+	LightPurple = "light_purple"
+	Yellow      = "yellow"
+	White       = "white"
+)
+
+// Click event actions.
+const (
+	ActionOpenURL        = "open_url"
+	ActionRunCommand     = "run_command"
+	ActionSuggestCommand = "suggest_command"
+)
+
+// ActionShowText is the hover event action that shows a tooltip.
+const ActionShowText = "show_text"
+
+// ClickEvent is run when the player clicks a component.
+type ClickEvent struct {
+	Action string `json:"action"`
+	Value  string `json:"value"`
+}
+
+// HoverEvent is shown when the player hovers over a component.
+type HoverEvent struct {
+	Action string    `json:"action"`
+	Value  Component `json:"value"`
+}
+
+// Component is a chat component. It renders Translate with its With
+// arguments when set, and Text otherwise, followed by its Extra children,
+// which inherit its style. Style flags are only ever sent as true.
+type Component struct {
+	Text      string
+	Translate string
+	With      []Component
+
+	Color         string
+	Bold          bool
+	Italic        bool
+	Underlined    bool
+	Strikethrough bool
+	Obfuscated    bool
+
+	ClickEvent *ClickEvent
+	HoverEvent *HoverEvent
+
+	Extra []Component
+}
+
+// Text returns a plain text component.
+func Text(s string) Component {
+	return Component{Text: s}
+}
+
+// Colored returns a text component in the given color.
+func Colored(s, color string) Component {
+	return Component{Text: s, Color: color}
+}
+
+// Translate returns a component rendering the client-side translation key
+// with the given arguments.
+func Translate(key string, with ...Component) Component {
+	return Component{Translate: key, With: with}
+}
+
+// OnClick returns a copy of c that runs action with value when clicked.
+func (c Component) OnClick(action, value string) Component {
+	c.ClickEvent = &ClickEvent{Action: action, Value: value}
+	return c
+}
+
+// OnHover returns a copy of c that shows text as a tooltip.
+func (c Component) OnHover(text Component) Component {
+	c.HoverEvent = &HoverEvent{Action: ActionShowText, Value: text}
+	return c
+}
+
+// Append returns a copy of c with children added to its extra components.
+func (c Component) Append(children ...Component) Component {
+	c.Extra = append(c.Extra[:len(c.Extra):len(c.Extra)], children...)
+	return c
+}
+
+// componentJSON is the wire form of a Component. Text is a pointer so that
+// an empty text is still sent when there is no translation key.
+type componentJSON struct {
+	Text      *string     `json:"text,omitempty"`
+	Translate string      `json:"translate,omitempty"`
+	With      []Component `json:"with,omitempty"`
+
+	Color         string `json:"color,omitempty"`
+	Bold          bool   `json:"bold,omitempty"`
+	Italic        bool   `json:"italic,omitempty"`
+	Underlined    bool   `json:"underlined,omitempty"`
+	Strikethrough bool   `json:"strikethrough,omitempty"`
+	Obfuscated    bool   `json:"obfuscated,omitempty"`
+
+	ClickEvent *ClickEvent `json:"clickEvent,omitempty"`
+	HoverEvent *HoverEvent `json:"hoverEvent,omitempty"`
+
+	Extra []Component `json:"extra,omitempty"`
+}
+
+// MarshalJSON encodes the component in the client's chat JSON format.
+func (c Component) MarshalJSON() ([]byte, error) {
+	out := componentJSON{
+		Translate:     c.Translate,
+		With:          c.With,
+		Color:         c.Color,
+		Bold:          c.Bold,
+		Italic:        c.Italic,
+		Underlined:    c.Underlined,
+		Strikethrough: c.Strikethrough,
+		Obfuscated:    c.Obfuscated,
+		ClickEvent:    c.ClickEvent,
+		HoverEvent:    c.HoverEvent,
+		Extra:         c.Extra,
+	}
+	if c.Translate == "" {
+		out.Text = &c.Text
+	}
+	return json.Marshal(out)
+}
+
+// String returns the component's JSON, ready to be sent in a packet.
+func (c Component) String() string {
+	b, _ := json.Marshal(c)
+	return string(b)
+}
+
+// PlainText returns the unformatted text of a chat JSON value sent by a
+// client: a string, a component with extra children, or an array of them.
+// Legacy § formatting codes are removed. Input that is not JSON is treated
+// as text.
+func PlainText(raw string) string {
+	var v any
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		return stripFormatting(raw)
+	}
+	var b strings.Builder
+	appendPlain(&b, v)
+	return stripFormatting(b.String())
+}
+
+// appendPlain writes the text of a decoded chat JSON value to b.
+func appendPlain(b *strings.Builder, v any) {
+	switch v := v.(type) {
+	case string:
+		b.WriteString(v)
+	case []any:
+		for _, e := range v {
+			appendPlain(b, e)
+		}
+	case map[string]any:
+		if text, ok := v["text"].(string); ok {
+			b.WriteString(text)
+		}
+		if extra, ok := v["extra"].([]any); ok {
+			appendPlain(b, extra)
+		}
+	}
+}
+
+// stripFormatting removes § color and style codes from s.
+func stripFormatting(s string) string {
+	if !strings.ContainsRune(s, '§') {
+		return s
+	}
+	var b strings.Builder
+	skip := false
+	for _, r := range s {
+		switch {
+		case skip:
+			skip = false
+		case r == '§':
+			skip = true
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
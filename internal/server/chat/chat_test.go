@@ -0,0 +1,81 @@
+package chat
+
+import "testing"
+
+func TestComponentJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Component
+		want string
+	}{
+		{"plain", Text("hi"), `{"text":"hi"}`},
+		{"empty text", Text(""), `{"text":""}`},
+		{"colored", Colored("Saved.", Green), `{"text":"Saved.","color":"green"}`},
+		{"escaped", Text(`say "hi" \o/`), `{"text":"say \"hi\" \\o/"}`},
+		{
+			"translate",
+			Translate("chat.type.text", Text("Alice"), Text("hello")),
+			`{"translate":"chat.type.text","with":[{"text":"Alice"},{"text":"hello"}]}`,
+		},
+		{
+			"styled",
+			Component{Text: "!", Bold: true, Italic: true, Underlined: true, Strikethrough: true, Obfuscated: true},
+			`{"text":"!","bold":true,"italic":true,"underlined":true,"strikethrough":true,"obfuscated":true}`,
+		},
+		{
+			"click and hover",
+			Colored("[Spawn]", Aqua).
+				OnClick(ActionRunCommand, "/spawn").
+				OnHover(Colored("Teleport to spawn", Yellow)),
+			`{"text":"[Spawn]","color":"aqua",` +
+				`"clickEvent":{"action":"run_command","value":"/spawn"},` +
+				`"hoverEvent":{"action":"show_text","value":{"text":"Teleport to spawn","color":"yellow"}}}`,
+		},
+		{
+			"extra",
+			Colored("Warps: ", Gold).Append(
+				Text("home").OnClick(ActionSuggestCommand, "/warp home"),
+				Colored(", ", Gray),
+			),
+			`{"text":"Warps: ","color":"gold","extra":[` +
+				`{"text":"home","clickEvent":{"action":"suggest_command","value":"/warp home"}},` +
+				`{"text":", ","color":"gray"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.String(); got != tt.want {
+				t.Errorf("String() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendDoesNotAlias(t *testing.T) {
+	base := Text("a").Append(Text("b"))
+	one := base.Append(Text("c"))
+	two := base.Append(Text("d"))
+
+	if one.String() == two.String() || len(base.Extra) != 1 {
+		t.Errorf("Append shared children between copies: %s, %s", one, two)
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		raw, want string
+	}{
+		{`{"text":"Hello"}`, "Hello"},
+		{`"quoted"`, "quoted"},
+		{`{"text":"a","extra":[{"text":"b","color":"red"},"c"]}`, "abc"},
+		{`["x",{"text":"y"}]`, "xy"},
+		{`{"text":"§cRed"}`, "Red"},
+		{`not json`, "not json"},
+		{``, ""},
+	}
+	for _, tt := range tests {
+		if got := PlainText(tt.raw); got != tt.want {
+			t.Errorf("PlainText(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
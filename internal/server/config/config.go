@@ -8,36 +8,88 @@ const (
 	GeneratorFlat    = "flat"
 )
 
+// DefaultGameVersion is the game data version the server speaks by default.
+const DefaultGameVersion = "pc-1.8"
+
 // Config holds the server configuration.
 type Config struct {
-	Port            int    `json:"port"`
-	OnlineMode      bool   `json:"online_mode"`
-	MOTD            string `json:"motd"`
-	MaxPlayers      int    `json:"max_players"`
-	ViewDistance    int    `json:"view_distance"`
-	Seed            int64  `json:"seed"`
-	GeneratorType   string `json:"generator_type"`    // "default" or "flat"
-	WorldRadius     int    `json:"world_radius"`      // world boundary in chunks (0 = infinite)
-	AutoSaveMinutes int    `json:"auto_save_minutes"` // auto-save interval in minutes (0 = disabled)
-	MaxBuildHeight  int    `json:"max_build_height"`  // maximum Y axis (default 256)
+	Port             int    `json:"port"`
+	OnlineMode       bool   `json:"online_mode"`
+	MOTD             string `json:"motd"`
+	MaxPlayers       int    `json:"max_players"`
+	ViewDistance     int    `json:"view_distance"`
+	Seed             int64  `json:"seed"`
+	GeneratorType    string `json:"generator_type"`     // "default" or "flat"
+	WorldRadius      int    `json:"world_radius"`       // world boundary in chunks (0 = infinite)
+	SpawnChunkRadius int    `json:"spawn_chunk_radius"` // chunks around spawn kept generated and loaded (0 = none)
+	AutoSaveMinutes  int    `json:"auto_save_minutes"`  // auto-save interval in minutes (0 = disabled)
+	MaxBuildHeight   int    `json:"max_build_height"`   // maximum Y axis (default 256)
+	DefaultGameMode  string `json:"default_gamemode"`   // game mode for players without saved data
+	RandomTickSpeed  int    `json:"random_tick_speed"`  // random block ticks per chunk section per tick (0 = disabled)
+	SendItemNBT      bool   `json:"send_item_nbt"`      // include item NBT (enchantments, names) in slots
+	KickFlyHackers   bool   `json:"kick_fly_hackers"`   // kick survival players who repeatedly request flight
+	Whitelist        bool   `json:"whitelist"`          // only players in whitelist.json may join (an empty list allows everyone)
+	CompressSaves    string `json:"compress_saves"`     // comma-separated file kinds to gzip: config, world, players, all
+	GameVersion      string `json:"game_version"`       // registered game data version, e.g. "pc-1.8"
+
+	// Text shown above and below the tab list (empty = none).
+	PlayerListHeader string `json:"player_list_header"`
+	PlayerListFooter string `json:"player_list_footer"`
+
+	// Superflat layers from the bottom up for the flat generator, e.g.
+	// "minecraft:bedrock,2*minecraft:dirt,minecraft:grass" (empty = classic).
+	FlatLayers string `json:"flat_layers"`
+
+	// Packets of at least this many bytes are zlib-compressed once login
+	// completes (-1 = disabled).
+	CompressionThreshold int `json:"compression_threshold"`
+
+	// Disconnect message overrides keyed by kick reason (e.g. "timeout").
+	KickMessages map[string]KickMessage `json:"kick_messages"`
+
+	// Entity lifetimes in seconds keyed by despawn kind ("item", "mob" or a
+	// mob name such as "pig"). 0 means never despawn; unset kinds keep the
+	// built-in lifetime.
+	DespawnSeconds map[string]int `json:"despawn_seconds"`
+
+	// Usernames allowed to run operator commands in addition to the players
+	// in ops.json.
+	Ops []string `json:"ops"`
 
 	// RSA keypair for online-mode encryption handshake.
 	PrivateKey   *rsa.PrivateKey `json:"-"`
 	PublicKeyDER []byte          `json:"-"`
 }
 
+// KickMessage overrides the text and chat color of a disconnect message.
+// Empty fields keep the default.
+type KickMessage struct {
+	Text  string `json:"text"`
+	Color string `json:"color,omitempty"`
+}
+
 // DefaultConfig returns a Config with sensible defaults.
 func DefaultConfig() *Config {
 	return &Config{
-		Port:            25565,
-		OnlineMode:      false,
-		MOTD:            "A go-theft-craft server",
-		MaxPlayers:      20,
-		ViewDistance:    12,
-		GeneratorType:   GeneratorDefault,
-		AutoSaveMinutes: 5,
-		WorldRadius:     500,
-		MaxBuildHeight:  256,
+		Port:             25565,
+		OnlineMode:       false,
+		MOTD:             "A go-theft-craft server",
+		MaxPlayers:       20,
+		ViewDistance:     12,
+		GeneratorType:    GeneratorDefault,
+		GameVersion:      DefaultGameVersion,
+		AutoSaveMinutes:  5,
+		WorldRadius:      500,
+		SpawnChunkRadius: 4,
+		MaxBuildHeight:   256,
+		DefaultGameMode:  "creative",
+		RandomTickSpeed:  3,
+		SendItemNBT:      true,
+		KickMessages:     map[string]KickMessage{},
+		DespawnSeconds:   map[string]int{},
+		Ops:              []string{},
+
+		CompressionThreshold: 256,
 	}
 }
 
@@ -69,10 +121,53 @@ func Merge(cfg *Config, fromFile *Config, explicitFlags map[string]bool) {
 	if !explicitFlags["world-radius"] {
 		cfg.WorldRadius = fromFile.WorldRadius
 	}
+	if !explicitFlags["spawn-chunk-radius"] {
+		cfg.SpawnChunkRadius = fromFile.SpawnChunkRadius
+	}
 	if !explicitFlags["auto-save"] {
 		cfg.AutoSaveMinutes = fromFile.AutoSaveMinutes
 	}
 	if !explicitFlags["max-build-height"] {
 		cfg.MaxBuildHeight = fromFile.MaxBuildHeight
 	}
+	if !explicitFlags["default-gamemode"] {
+		cfg.DefaultGameMode = fromFile.DefaultGameMode
+	}
+	if !explicitFlags["random-tick-speed"] {
+		cfg.RandomTickSpeed = fromFile.RandomTickSpeed
+	}
+	if !explicitFlags["send-item-nbt"] {
+		cfg.SendItemNBT = fromFile.SendItemNBT
+	}
+	if !explicitFlags["kick-fly-hackers"] {
+		cfg.KickFlyHackers = fromFile.KickFlyHackers
+	}
+	if !explicitFlags["whitelist"] {
+		cfg.Whitelist = fromFile.Whitelist
+	}
+	if !explicitFlags["compress-saves"] {
+		cfg.CompressSaves = fromFile.CompressSaves
+	}
+	if !explicitFlags["game-version"] {
+		cfg.GameVersion = fromFile.GameVersion
+	}
+	if !explicitFlags["player-list-header"] {
+		cfg.PlayerListHeader = fromFile.PlayerListHeader
+	}
+	if !explicitFlags["player-list-footer"] {
+		cfg.PlayerListFooter = fromFile.PlayerListFooter
+	}
+	if !explicitFlags["flat-layers"] {
+		cfg.FlatLayers = fromFile.FlatLayers
+	}
+	if !explicitFlags["compression-threshold"] {
+		cfg.CompressionThreshold = fromFile.CompressionThreshold
+	}
+	if !explicitFlags["ops"] {
+		cfg.Ops = fromFile.Ops
+	}
+	// Kick messages and despawn lifetimes have no flag; they are only set in
+	// the config file.
+	cfg.KickMessages = fromFile.KickMessages
+	cfg.DespawnSeconds = fromFile.DespawnSeconds
 }
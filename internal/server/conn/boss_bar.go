@@ -0,0 +1,43 @@
+package conn
+
+import (
+	"github.com/go-theft-craft/server/internal/server/player"
+)
+
+// ShowBossBar shows p a boss bar with the given title, filled to percent
+// (0-1), or updates the bar already shown.
+func (c *Connection) ShowBossBar(p *player.Player, title string, percent float32) {
+	percent = min(max(percent, 0), 1)
+
+	bar, ok := p.BossBar()
+	if !ok {
+		bar = player.BossBar{EntityID: c.players.AllocateEntityID(), Title: title, Percent: percent}
+		p.SetBossBar(bar)
+		_ = p.WritePacket(bar.SpawnPacket(p.GetPosition()))
+		return
+	}
+	if bar.Title == title && bar.Percent == percent {
+		return
+	}
+	bar.Title, bar.Percent = title, percent
+	p.SetBossBar(bar)
+	_ = p.WritePacket(bar.MetadataPacket())
+}
+
+// HideBossBar removes p's boss bar, if one is shown.
+func (c *Connection) HideBossBar(p *player.Player) {
+	bar, ok := p.BossBar()
+	if !ok {
+		return
+	}
+	p.ClearBossBar()
+	_ = p.WritePacket(bar.DestroyPacket())
+}
+
+// followBossBar keeps the player's boss bar wither in front of them as they
+// move and look around.
+func (c *Connection) followBossBar() {
+	if bar, ok := c.self.BossBar(); ok {
+		_ = c.self.WritePacket(bar.TeleportPacket(c.self.GetPosition()))
+	}
+}
@@ -0,0 +1,102 @@
+package conn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/go-theft-craft/server/internal/server/player"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+)
+
+// witherHealth extracts the float health entry (index 6) from boss bar
+// metadata.
+func witherHealth(t *testing.T, data []byte) float32 {
+	t.Helper()
+	i := bytes.IndexByte(data, 6|3<<5)
+	if i < 0 || i+5 > len(data) {
+		t.Fatalf("no health entry in metadata %v", data)
+	}
+	return math.Float32frombits(binary.BigEndian.Uint32(data[i+1 : i+5]))
+}
+
+func TestShowBossBar_SpawnsWither(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	sp.reset()
+
+	c.ShowBossBar(c.self, "Dragon", 0.5)
+
+	bar, ok := c.self.BossBar()
+	if !ok {
+		t.Fatal("no boss bar recorded")
+	}
+	sent := sp.get()
+	if len(sent) != 1 {
+		t.Fatalf("got %d packets, want 1", len(sent))
+	}
+	spawn, ok := sent[0].(*pkt.SpawnEntityLiving)
+	if !ok {
+		t.Fatalf("packet = %T, want SpawnEntityLiving", sent[0])
+	}
+	if spawn.Data[0] != byte(bar.EntityID) || spawn.Data[1] != 64 {
+		t.Errorf("spawned entity %d of type %d, want wither %d", spawn.Data[0], spawn.Data[1], bar.EntityID)
+	}
+	if !bytes.Contains(spawn.Data, []byte("Dragon")) {
+		t.Error("spawn metadata does not carry the title")
+	}
+	if h := witherHealth(t, spawn.Data); h != 150 {
+		t.Errorf("wither health = %v, want 150", h)
+	}
+}
+
+func TestShowBossBar_UpdatesPercent(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	c.ShowBossBar(c.self, "Dragon", 1)
+	sp.reset()
+
+	c.ShowBossBar(c.self, "Dragon", 0.25)
+
+	sent := sp.get()
+	if len(sent) != 1 {
+		t.Fatalf("got %d packets, want 1", len(sent))
+	}
+	meta, ok := sent[0].(*pkt.EntityMetadata)
+	if !ok {
+		t.Fatalf("packet = %T, want EntityMetadata", sent[0])
+	}
+	if h := witherHealth(t, meta.Data); h != 75 {
+		t.Errorf("wither health = %v, want 75", h)
+	}
+}
+
+func TestBossBar_FollowsAndHides(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	c.ShowBossBar(c.self, "Dragon", 1)
+	bar, _ := c.self.BossBar()
+	sp.reset()
+
+	c.handleLookUpdate(90, 0, true) // facing -X
+
+	var moved *pkt.EntityTeleport
+	for _, p := range sp.get() {
+		if tp, ok := p.(*pkt.EntityTeleport); ok && tp.EntityID == bar.EntityID {
+			moved = tp
+		}
+	}
+	pos := c.self.GetPosition()
+	if moved == nil || moved.X != player.FixedPoint(pos.X-32) {
+		t.Errorf("teleport = %+v, want the wither 32 blocks toward -X", moved)
+	}
+
+	sp.reset()
+	c.HideBossBar(c.self)
+	if _, ok := c.self.BossBar(); ok {
+		t.Error("boss bar still recorded after hiding")
+	}
+	if sent := sp.get(); len(sent) != 1 {
+		t.Errorf("got %d packets on hide, want one EntityDestroy", len(sent))
+	} else if _, ok := sent[0].(*pkt.EntityDestroy); !ok {
+		t.Errorf("packet = %T, want EntityDestroy", sent[0])
+	}
+}
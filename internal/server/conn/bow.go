@@ -0,0 +1,63 @@
+package conn
+
+import (
+	"time"
+
+	"github.com/go-theft-craft/server/internal/server/packet"
+	"github.com/go-theft-craft/server/internal/server/player"
+)
+
+// Item IDs used when shooting a bow.
+const (
+	itemBow   = 261
+	itemArrow = 262
+)
+
+// minBowPower is the least power a released bow shoots with, as in vanilla;
+// shorter draws shoot nothing.
+const minBowPower = 0.1
+
+// bowPower returns the fraction of full power a bow drawn for the given time
+// shoots with. A full draw takes one second.
+func bowPower(drawn time.Duration) float64 {
+	f := drawn.Seconds()
+	return min((f*f+2*f)/3, 1)
+}
+
+// shootBow fires an arrow along the player's look from a bow drawn for the
+// given time; the arrow is faster, and hits harder, the longer the draw.
+// Survival and adventure players need an arrow, which is consumed; creative
+// players shoot for free and spectators cannot shoot. Arrows hitting other
+// players deal damage reduced by the target's armor.
+func (c *Connection) shootBow(drawn time.Duration) error {
+	held := c.self.Inventory.HeldItem()
+	if held.BlockID != itemBow {
+		return nil
+	}
+	power := bowPower(drawn)
+	if power < minBowPower {
+		return nil
+	}
+
+	switch c.self.GetGameMode() {
+	case packet.GameModeSpectator:
+		return nil
+	case packet.GameModeCreative:
+	default:
+		if c.self.Inventory.RemoveItem(itemArrow, 1) == 0 {
+			return nil
+		}
+		if err := c.sendWindowItems(); err != nil {
+			return err
+		}
+	}
+
+	c.players.ShootArrow(c.self, power*player.ArrowSpeed, func(target *player.Player, damage float32) {
+		mode := target.GetGameMode()
+		if mode == packet.GameModeCreative || mode == packet.GameModeSpectator {
+			return
+		}
+		c.hurtPlayer(target, applyArmor(damage, c.totalArmorPoints(target)))
+	})
+	return nil
+}
@@ -0,0 +1,108 @@
+package conn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-theft-craft/server/internal/server/packet"
+	"github.com/go-theft-craft/server/internal/server/player"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+)
+
+// drawAndRelease right-clicks with a bow, pretends it was held for drawn and
+// releases it.
+func drawAndRelease(t *testing.T, c *Connection, drawn time.Duration) {
+	t.Helper()
+	if err := c.handleBlockPlace(blockPlaceData(-1, -1, -1, -1, itemBow)); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+	c.drawStart = time.Now().Add(-drawn)
+	if err := c.handleBlockDig(blockDigData(5, 0, 0, 0)); err != nil {
+		t.Fatalf("handleBlockDig: %v", err)
+	}
+}
+
+func TestUseItem_BowShootsArrow(t *testing.T) {
+	c, _, m := newTestConn("Alice")
+	c.self.SetGameMode(packet.GameModeSurvival)
+	c.self.Inventory.SetSlot(0, player.Slot{BlockID: itemBow, ItemCount: 1})
+	c.self.Inventory.SetSlot(1, player.Slot{BlockID: itemArrow, ItemCount: 2})
+	c.self.Inventory.SetHeldSlot(0)
+	c.world.GetOrGenerateChunk(0, 0)
+
+	bob, _ := addTestPlayer(m, "Bob")
+	bob.SetGameMode(packet.GameModeSurvival)
+	bob.SetPosition(0.5, 4, 6.5, 0, 0, true)
+
+	drawAndRelease(t, c, time.Second)
+	if got := c.self.Inventory.GetSlot(1).ItemCount; got != 1 {
+		t.Errorf("arrows left = %d, want 1", got)
+	}
+
+	for range 3 {
+		m.TickArrows(c.world)
+	}
+	if got := bob.GetHealth(); got >= 20 {
+		t.Errorf("Bob's health = %v, want the arrow to hurt him", got)
+	}
+}
+
+func TestUseItem_BowWithoutArrows(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	c.self.SetGameMode(packet.GameModeSurvival)
+	c.self.Inventory.SetSlot(0, player.Slot{BlockID: itemBow, ItemCount: 1})
+	c.self.Inventory.SetHeldSlot(0)
+	sp.reset()
+
+	drawAndRelease(t, c, time.Second)
+	for _, p := range sp.get() {
+		if _, ok := p.(*pkt.SpawnEntity); ok {
+			t.Fatal("a bow without arrows should not shoot")
+		}
+	}
+}
+
+func TestUseItem_BowNeedsMinimumDraw(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	c.self.SetGameMode(packet.GameModeSurvival)
+	c.self.Inventory.SetSlot(0, player.Slot{BlockID: itemBow, ItemCount: 1})
+	c.self.Inventory.SetSlot(1, player.Slot{BlockID: itemArrow, ItemCount: 2})
+	c.self.Inventory.SetHeldSlot(0)
+	sp.reset()
+
+	drawAndRelease(t, c, 50*time.Millisecond)
+	if got := c.self.Inventory.GetSlot(1).ItemCount; got != 2 {
+		t.Errorf("arrows left = %d, want 2 after releasing at once", got)
+	}
+	for _, p := range sp.get() {
+		if _, ok := p.(*pkt.SpawnEntity); ok {
+			t.Fatal("a bow released at once should not shoot")
+		}
+	}
+
+	// Releasing without drawing first shoots nothing either.
+	if err := c.handleBlockDig(blockDigData(5, 0, 0, 0)); err != nil {
+		t.Fatalf("handleBlockDig: %v", err)
+	}
+	if got := c.self.Inventory.GetSlot(1).ItemCount; got != 2 {
+		t.Errorf("arrows left = %d, want 2 after a release without a draw", got)
+	}
+}
+
+func TestBowPower(t *testing.T) {
+	tests := []struct {
+		drawn time.Duration
+		want  float64
+	}{
+		{0, 0},
+		{100 * time.Millisecond, 0.07},
+		{500 * time.Millisecond, 0.4166666666666667},
+		{time.Second, 1},
+		{3 * time.Second, 1},
+	}
+	for _, tt := range tests {
+		if got := bowPower(tt.drawn); got < tt.want-1e-9 || got > tt.want+1e-9 {
+			t.Errorf("bowPower(%v) = %v, want %v", tt.drawn, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package conn
+
+import (
+	"github.com/go-theft-craft/server/internal/server/player"
+)
+
+// fistDamage is the damage of an attack with an empty hand or a non-weapon.
+const fistDamage = 1
+
+// weaponDamage is the melee damage of weapons and tools in half-hearts,
+// keyed by gameData item name (MC 1.8 values).
+var weaponDamage = map[string]float32{
+	"wooden_sword":  5,
+	"golden_sword":  5,
+	"stone_sword":   6,
+	"iron_sword":    7,
+	"diamond_sword": 8,
+
+	"wooden_axe":  4,
+	"golden_axe":  4,
+	"stone_axe":   5,
+	"iron_axe":    6,
+	"diamond_axe": 7,
+
+	"wooden_pickaxe":  3,
+	"golden_pickaxe":  3,
+	"stone_pickaxe":   4,
+	"iron_pickaxe":    5,
+	"diamond_pickaxe": 6,
+
+	"wooden_shovel":  2,
+	"golden_shovel":  2,
+	"stone_shovel":   3,
+	"iron_shovel":    4,
+	"diamond_shovel": 5,
+}
+
+// armorPoints is the defense of each armor piece, keyed by gameData item name.
+var armorPoints = map[string]int{
+	"leather_helmet":     1,
+	"leather_chestplate": 3,
+	"leather_leggings":   2,
+	"leather_boots":      1,
+
+	"golden_helmet":     2,
+	"golden_chestplate": 5,
+	"golden_leggings":   3,
+	"golden_boots":      1,
+
+	"chainmail_helmet":     2,
+	"chainmail_chestplate": 5,
+	"chainmail_leggings":   4,
+	"chainmail_boots":      1,
+
+	"iron_helmet":     2,
+	"iron_chestplate": 6,
+	"iron_leggings":   5,
+	"iron_boots":      2,
+
+	"diamond_helmet":     3,
+	"diamond_chestplate": 8,
+	"diamond_leggings":   6,
+	"diamond_boots":      3,
+}
+
+// maxArmorPoints is the defense of a full diamond set.
+const maxArmorPoints = 20
+
+// itemName returns the gameData name of an item ID, or "" if unknown.
+func (c *Connection) itemName(id int16) string {
+	if c.gameData == nil || c.gameData.Items == nil {
+		return ""
+	}
+	item, ok := c.gameData.Items.ByID(int(id))
+	if !ok {
+		return ""
+	}
+	return item.Name
+}
+
+// meleeDamage returns the damage dealt by an attack with the given held item.
+func (c *Connection) meleeDamage(held player.Slot) float32 {
+	if held.IsEmpty() {
+		return fistDamage
+	}
+	if d, ok := weaponDamage[c.itemName(held.BlockID)]; ok {
+		return d
+	}
+	return fistDamage
+}
+
+// totalArmorPoints sums the defense of the armor p is wearing.
+func (c *Connection) totalArmorPoints(p *player.Player) int {
+	points := 0
+	for i := 0; i < 4; i++ {
+		if piece := p.Inventory.GetArmor(i); !piece.IsEmpty() {
+			points += armorPoints[c.itemName(piece.BlockID)]
+		}
+	}
+	return min(points, maxArmorPoints)
+}
+
+// applyArmor reduces damage by 4% per armor point, as in MC 1.8.
+func applyArmor(damage float32, points int) float32 {
+	return damage * float32(25-points) / 25
+}
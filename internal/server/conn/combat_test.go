@@ -0,0 +1,151 @@
+package conn
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-theft-craft/server/internal/server/packet"
+	"github.com/go-theft-craft/server/internal/server/player"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+)
+
+func attackData(targetID int32) []byte {
+	var buf bytes.Buffer
+	_, _ = mcnet.WriteVarInt(&buf, targetID)
+	_, _ = mcnet.WriteVarInt(&buf, 1) // attack
+	return buf.Bytes()
+}
+
+// newCombatTest returns an attacker connection holding a diamond sword and
+// an unarmored survival target in the same manager.
+func newCombatTest(t *testing.T) (*Connection, *player.Player, *sentPackets) {
+	t.Helper()
+	c, _, m := newTestConn("Alice")
+	c.gameData = pkt.New()
+	c.self.Inventory.SetSlot(int(c.self.Inventory.GetHeldSlot()), sword())
+
+	sp := &sentPackets{}
+	target := player.NewPlayer(m.AllocateEntityID(), "target-uuid", [16]byte{0xBB}, "Bob", nil, sp.write)
+	target.SetPosition(1.5, 4, 0.5, 0, 0, true)
+	for i := 0; i < 4; i++ {
+		target.Inventory.SetArmor(i, player.EmptySlot)
+	}
+	m.Add(target)
+	return c, target, sp
+}
+
+func TestAttack_UnarmoredDamage(t *testing.T) {
+	c, target, sp := newCombatTest(t)
+
+	if err := c.handleUseEntity(attackData(target.EntityID)); err != nil {
+		t.Fatalf("handleUseEntity: %v", err)
+	}
+
+	// A diamond sword deals 8 half-hearts.
+	if got := target.GetHealth(); got != player.MaxHealth-8 {
+		t.Errorf("target health = %v, want %v", got, player.MaxHealth-8)
+	}
+	if got, ok := lastHealth(sp); !ok || got != player.MaxHealth-8 {
+		t.Errorf("UpdateHealth = %v (sent %v), want %v", got, ok, player.MaxHealth-8)
+	}
+}
+
+func TestAttack_ArmorReducesDamage(t *testing.T) {
+	c, target, _ := newCombatTest(t)
+	// Full iron armor: 2+6+5+2 = 15 points, 60% reduction.
+	for i, id := range []int16{306, 307, 308, 309} {
+		target.Inventory.SetArmor(i, player.Slot{BlockID: id, ItemCount: 1})
+	}
+
+	if err := c.handleUseEntity(attackData(target.EntityID)); err != nil {
+		t.Fatalf("handleUseEntity: %v", err)
+	}
+
+	want := float32(player.MaxHealth) - 8*float32(25-15)/25
+	if got := target.GetHealth(); got != want {
+		t.Errorf("target health = %v, want %v", got, want)
+	}
+}
+
+func TestAttack_InvulnerabilityWindow(t *testing.T) {
+	c, target, _ := newCombatTest(t)
+
+	_ = c.handleUseEntity(attackData(target.EntityID))
+	_ = c.handleUseEntity(attackData(target.EntityID))
+	if got := target.GetHealth(); got != player.MaxHealth-8 {
+		t.Errorf("health after double hit = %v, want %v", got, player.MaxHealth-8)
+	}
+
+	for i := 0; i < 10; i++ {
+		target.TickInvulnerability()
+	}
+	_ = c.handleUseEntity(attackData(target.EntityID))
+	if got := target.GetHealth(); got != player.MaxHealth-16 {
+		t.Errorf("health after window = %v, want %v", got, player.MaxHealth-16)
+	}
+}
+
+func TestAttack_KillsTarget(t *testing.T) {
+	c, target, _ := newCombatTest(t)
+	target.SetHealth(3)
+
+	_ = c.handleUseEntity(attackData(target.EntityID))
+
+	if !target.IsDead() {
+		t.Errorf("target health = %v, want dead", target.GetHealth())
+	}
+}
+
+func TestAttack_FistAndUnknownItems(t *testing.T) {
+	c, _, _ := newCombatTest(t)
+	if got := c.meleeDamage(player.EmptySlot); got != fistDamage {
+		t.Errorf("fist damage = %v, want %v", got, fistDamage)
+	}
+	if got := c.meleeDamage(stone(1)); got != fistDamage {
+		t.Errorf("stone damage = %v, want %v", got, fistDamage)
+	}
+}
+
+func interactData(targetID int32) []byte {
+	var buf bytes.Buffer
+	_, _ = mcnet.WriteVarInt(&buf, targetID)
+	_, _ = mcnet.WriteVarInt(&buf, 0) // interact
+	return buf.Bytes()
+}
+
+func TestNameTag_NamesMobAndConsumesTag(t *testing.T) {
+	c, _, m := newTestConn("Alice")
+	c.gameData = pkt.New()
+	c.self.SetGameMode(packet.GameModeSurvival)
+	pig, _ := c.gameData.Entities.ByName("pig")
+	mob := m.SpawnMob(pig, 1.5, 4, 0.5)
+
+	tag := player.Slot{BlockID: 421, ItemCount: 2, NBT: &player.ItemNBT{DisplayName: "Wilbur"}}
+	c.self.Inventory.SetSlot(int(c.self.Inventory.GetHeldSlot()), tag)
+
+	if err := c.handleUseEntity(interactData(mob.EntityID)); err != nil {
+		t.Fatalf("handleUseEntity: %v", err)
+	}
+	if !mob.Persistent || mob.CustomName != "Wilbur" {
+		t.Errorf("mob = %q persistent %v, want %q persistent", mob.CustomName, mob.Persistent, "Wilbur")
+	}
+	if got := c.self.Inventory.HeldItem().ItemCount; got != 1 {
+		t.Errorf("name tags left = %d, want 1", got)
+	}
+}
+
+func TestNameTag_UnnamedTagDoesNothing(t *testing.T) {
+	c, _, m := newTestConn("Alice")
+	c.gameData = pkt.New()
+	pig, _ := c.gameData.Entities.ByName("pig")
+	mob := m.SpawnMob(pig, 1.5, 4, 0.5)
+	c.self.Inventory.SetSlot(int(c.self.Inventory.GetHeldSlot()), player.Slot{BlockID: 421, ItemCount: 1})
+
+	if err := c.handleUseEntity(interactData(mob.EntityID)); err != nil {
+		t.Fatalf("handleUseEntity: %v", err)
+	}
+	if mob.Persistent {
+		t.Error("a name tag without a name should not make the mob persistent")
+	}
+}
@@ -1,36 +1,96 @@
 package conn
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"math"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/go-theft-craft/server/internal/server/chat"
 	"github.com/go-theft-craft/server/internal/server/packet"
 	"github.com/go-theft-craft/server/internal/server/player"
+	"github.com/go-theft-craft/server/internal/server/storage"
+	"github.com/go-theft-craft/server/pkg/gamedata"
 	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+	"github.com/go-theft-craft/server/pkg/world"
+	"github.com/go-theft-craft/server/pkg/world/gen"
 )
 
 type command struct {
 	name    string
+	aliases []string
 	usage   string
 	desc    string
+	maxLen  int // maximum length of the full command line; 0 = maxCommandLength
 	handler func(c *Connection, args []string)
+
+	// requiresOp restricts the command to operators.
+	requiresOp bool
 }
 
+// maxCommandLength caps the length of any command line at 256 bytes, above
+// every per-command maxLen, so oversized input is rejected before it is
+// split into arguments.
+const maxCommandLength = 256
+
 var commands []command
 
 func init() {
 	commands = []command{
-		{name: "help", usage: "/help", desc: "Show available commands", handler: cmdHelp},
-		{name: "list", usage: "/list", desc: "Show online players", handler: cmdList},
-		{name: "tp", usage: "/tp <player> | /tp <x> <y> <z>", desc: "Teleport to a player or coordinates", handler: cmdTp},
-		{name: "gamemode", usage: "/gamemode <survival|creative|adventure|spectator>", desc: "Change game mode", handler: cmdGamemode},
-		{name: "time", usage: "/time set <day|night|noon|midnight|number>", desc: "Set world time", handler: cmdTime},
-		{name: "say", usage: "/say <message>", desc: "Broadcast an announcement", handler: cmdSay},
+		{name: "help", usage: "/help", desc: "Show available commands", maxLen: 32, handler: cmdHelp},
+		{name: "list", usage: "/list", desc: "Show online players", maxLen: 32, handler: cmdList},
+		{name: "tp", usage: "/tp <player> | /tp <x> <y> <z>", desc: "Teleport to a player or coordinates", maxLen: 96, handler: cmdTp, requiresOp: true},
+		{name: "gamemode", usage: "/gamemode <survival|creative|adventure|spectator> [player]", desc: "Change game mode", maxLen: 64, handler: cmdGamemode, requiresOp: true},
+		{name: "time", usage: "/time <set|add> <value> | /time query <daytime|gametime>", desc: "Set, advance or show world time", maxLen: 64, handler: cmdTime, requiresOp: true},
+		{name: "weather", usage: "/weather <clear|rain|thunder> [duration]", desc: "Change the weather", maxLen: 64, handler: cmdWeather, requiresOp: true},
+		{name: "gamerule", usage: "/gamerule [rule] [value]", desc: "Show or change a game rule", maxLen: 64, handler: cmdGamerule, requiresOp: true},
+		{name: "worldborder", usage: "/worldborder <set <size>|center <x> <z>|get>", desc: "Change the world border", maxLen: 96, handler: cmdWorldborder, requiresOp: true},
+		{name: "say", usage: "/say <message>", desc: "Broadcast an announcement", handler: cmdSay, requiresOp: true},
 		{name: "me", usage: "/me <action>", desc: "Send an action message", handler: cmdMe},
-		{name: "kill", usage: "/kill", desc: "Kill yourself", handler: cmdKill},
-		{name: "seed", usage: "/seed", desc: "Show world seed", handler: cmdSeed},
-		{name: "save", usage: "/save", desc: "Save world and player data", handler: cmdSave},
+		{name: "msg", usage: "/msg <player> <message>", desc: "Send a private message", handler: cmdMsg},
+		{name: "reply", aliases: []string{"r"}, usage: "/reply <message>", desc: "Reply to your last private message", handler: cmdReply},
+		{name: "kill", usage: "/kill", desc: "Kill yourself", maxLen: 32, handler: cmdKill, requiresOp: true},
+		{name: "setworldspawn", usage: "/setworldspawn [x y z]", desc: "Set the world spawn point", maxLen: 96, handler: cmdSetworldspawn, requiresOp: true},
+		{name: "spawn", usage: "/spawn", desc: "Teleport to the world spawn", maxLen: 32, handler: cmdSpawn},
+		{name: "sethome", usage: "/sethome", desc: "Set your home to your position", maxLen: 32, handler: cmdSethome},
+		{name: "home", usage: "/home", desc: "Teleport to your home", maxLen: 32, handler: cmdHome},
+		{name: "warp", usage: "/warp [name]", desc: "Teleport to a warp, or list warps", maxLen: 64, handler: cmdWarp},
+		{name: "setwarp", usage: "/setwarp <name>", desc: "Create a warp at your position", maxLen: 64, handler: cmdSetwarp, requiresOp: true},
+		{name: "delwarp", usage: "/delwarp <name>", desc: "Delete a warp", maxLen: 64, handler: cmdDelwarp, requiresOp: true},
+		{name: "seed", usage: "/seed", desc: "Show world seed", maxLen: 32, handler: cmdSeed},
+		{name: "save", usage: "/save", desc: "Save world and player data", maxLen: 32, handler: cmdSave, requiresOp: true},
+		{name: "tps", aliases: []string{"lag"}, usage: "/tps", desc: "Show server ticks per second", maxLen: 32, handler: cmdTPS},
+		{name: "setbiome", usage: "/setbiome <biome> [radius]", desc: "Change the biome around you", maxLen: 64, handler: cmdSetbiome, requiresOp: true},
+		{name: "regenerate", usage: "/regenerate [radius] [confirm]", desc: "Regenerate the chunks around you", maxLen: 48, handler: cmdRegenerate, requiresOp: true},
+		{name: "give", usage: "/give <player|@s> <item> [count] [damage]", desc: "Give items to a player", maxLen: 96, handler: cmdGive, requiresOp: true},
+		{name: "kick", usage: "/kick <player> [reason]", desc: "Disconnect a player", maxLen: 128, handler: cmdKick, requiresOp: true},
+		{name: "ban", usage: "/ban <player> [reason]", desc: "Ban a player", maxLen: 128, handler: cmdBan, requiresOp: true},
+		{name: "pardon", usage: "/pardon <player>", desc: "Remove a player's ban", maxLen: 32, handler: cmdPardon, requiresOp: true},
+		{name: "banlist", usage: "/banlist", desc: "Show banned players", maxLen: 32, handler: cmdBanlist, requiresOp: true},
+		{name: "op", usage: "/op <player>", desc: "Make a player an operator", maxLen: 32, handler: cmdOp, requiresOp: true},
+		{name: "deop", usage: "/deop <player>", desc: "Remove a player's operator status", maxLen: 32, handler: cmdDeop, requiresOp: true},
+		{name: "whitelist", usage: "/whitelist <add|remove> <player> | /whitelist list", desc: "Manage the whitelist", maxLen: 64, handler: cmdWhitelist, requiresOp: true},
+		{name: "whois", usage: "/whois <player>", desc: "Show information about a player", maxLen: 32, handler: cmdWhois, requiresOp: true},
+		{name: "effect", usage: "/effect <player> <effect> [seconds] [amplifier] | /effect <player> clear", desc: "Give or clear potion effects", maxLen: 96, handler: cmdEffect, requiresOp: true},
+		{name: "speed", usage: "/speed <fly|walk> <0-10>", desc: "Change your flying or walking speed", maxLen: 32, handler: cmdSpeed, requiresOp: true},
+		{name: "xp", usage: "/xp <amount>[L] [player]", desc: "Give experience points or levels", maxLen: 64, handler: cmdXP, requiresOp: true},
+		{name: "title", usage: "/title <player> <text>", desc: "Show a title on a player's screen", maxLen: 128, handler: cmdTitle, requiresOp: true},
+		{name: "summon", usage: "/summon item <item> [count] | /summon xp <amount>", desc: "Spawn an item or experience orbs", maxLen: 64, handler: cmdSummon, requiresOp: true},
+		{name: "scoreboard", usage: "/scoreboard objectives <add|setdisplay> ... | /scoreboard players set <entry> <objective> <score>", desc: "Manage scoreboard objectives and scores", maxLen: 128, handler: cmdScoreboard, requiresOp: true},
+		{name: "clear", usage: "/clear [player] [item] [count]", desc: "Remove items from a player's inventory", maxLen: 96, handler: cmdClear, requiresOp: true},
+		{name: "enchant", usage: "/enchant <enchantment> [level]", desc: "Enchant the held item", maxLen: 64, handler: cmdEnchant, requiresOp: true},
+		{name: "setblock", usage: "/setblock <x> <y> <z> <block[:meta]>", desc: "Place a block", maxLen: 96, handler: cmdSetblock, requiresOp: true},
+		{name: "fill", usage: "/fill <x1> <y1> <z1> <x2> <y2> <z2> <block[:meta]>", desc: "Fill a region with a block", maxLen: 128, handler: cmdFill, requiresOp: true},
+		{name: "export", usage: "/export <x1> <y1> <z1> <x2> <y2> <z2> <name>", desc: "Save a region as a schematic", maxLen: 128, handler: cmdExport, requiresOp: true},
+		{name: "import", usage: "/import <name>", desc: "Paste a schematic at your position", maxLen: 64, handler: cmdImport, requiresOp: true},
 	}
 }
 
@@ -41,29 +101,63 @@ func (c *Connection) handleCommand(msg string) bool {
 		return false
 	}
 
-	parts := strings.Fields(msg)
-	if len(parts) == 0 {
+	if len(msg) > maxCommandLength {
+		c.sendErrorMsg("Command too long.")
 		return true
 	}
 
-	name := strings.ToLower(strings.TrimPrefix(parts[0], "/"))
-	args := parts[1:]
+	head, _, _ := strings.Cut(msg, " ")
+	name := strings.ToLower(strings.TrimPrefix(head, "/"))
 
-	for _, cmd := range commands {
-		if cmd.name == name {
-			cmd.handler(c, args)
+	if cmd, ok := lookupCommand(name); ok {
+		if cmd.maxLen > 0 && len(msg) > cmd.maxLen {
+			c.sendErrorMsg(fmt.Sprintf("Command too long. Usage: %s", cmd.usage))
+			return true
+		}
+		if cmd.requiresOp && !c.isOp() {
+			c.sendErrorMsg("You do not have permission to use this command.")
 			return true
 		}
+		cmd.handler(c, strings.Fields(msg)[1:])
+		return true
 	}
 
 	c.sendErrorMsg(fmt.Sprintf("Unknown command: /%s. Type /help for a list of commands.", name))
 	return true
 }
 
+// isOp reports whether the player may run operator commands: players named
+// in the config's ops and those in ops.json may. If ops.json cannot be read,
+// only the config's operators may.
+func (c *Connection) isOp() bool {
+	if slices.ContainsFunc(c.cfg.Ops, func(name string) bool { return strings.EqualFold(name, c.self.Username) }) {
+		return true
+	}
+	if c.storage == nil {
+		return false
+	}
+	ops, err := c.storage.Ops()
+	if err != nil {
+		c.log.Error("load ops", "error", err)
+		return false
+	}
+	return storage.IsOp(ops, c.self.UUID)
+}
+
+// lookupCommand finds a registered command by its name or one of its aliases.
+func lookupCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name || slices.Contains(cmd.aliases, name) {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
 // sendSystemMsg sends a chat message (position=1, system) to this connection only.
 func (c *Connection) sendSystemMsg(text, color string) {
 	_ = c.writePacket(&pkt.ChatCB{
-		Message:  fmt.Sprintf(`{"text":%s,"color":%s}`, escapeJSON(text), escapeJSON(color)),
+		Message:  chat.Colored(text, color).String(),
 		Position: 1,
 	})
 }
@@ -79,10 +173,14 @@ func (c *Connection) sendSuccessMsg(text string) {
 }
 
 // teleportSelf moves the connection's player to the given coordinates,
-// broadcasting the teleport to trackers and updating tracking.
+// broadcasting the teleport to trackers and updating tracking. A player riding
+// a vehicle is dismounted first.
 func (c *Connection) teleportSelf(x, y, z float64) {
+	c.dismount()
+
 	pos := c.self.GetPosition()
-	c.setPositionAndUpdateChunks(x, y, z, pos.Yaw, pos.Pitch, false)
+	_, _, _, fx, fy, fz := c.setPositionAndUpdateChunks(x, y, z, pos.Yaw, pos.Pitch, false)
+	c.resetFall(y)
 
 	_ = c.writePacket(&pkt.PositionCB{
 		X:     x,
@@ -95,9 +193,9 @@ func (c *Connection) teleportSelf(x, y, z float64) {
 
 	c.players.BroadcastToTrackers(&pkt.EntityTeleport{
 		EntityID: c.self.EntityID,
-		X:        player.FixedPoint(x),
-		Y:        player.FixedPoint(y),
-		Z:        player.FixedPoint(z),
+		X:        fx,
+		Y:        fy,
+		Z:        fz,
 		Yaw:      player.DegreesToAngle(pos.Yaw),
 		Pitch:    player.DegreesToAngle(pos.Pitch),
 		OnGround: false,
@@ -106,9 +204,37 @@ func (c *Connection) teleportSelf(x, y, z float64) {
 	c.players.UpdateTracking(c.self)
 }
 
+// checkInBorder reports whether (x, z) is inside the world border, telling
+// the player if it is not.
+func (c *Connection) checkInBorder(x, z float64) bool {
+	if !c.world.Border().Contains(x, z) {
+		c.sendErrorMsg("That position is outside the world border.")
+		return false
+	}
+	return true
+}
+
+// dismount detaches the player from their vehicle, if any, notifying the
+// player and everyone tracking them.
+func (c *Connection) dismount() {
+	if _, riding := c.self.Dismount(); !riding {
+		return
+	}
+	detach := &pkt.AttachEntity{
+		EntityID:  c.self.EntityID,
+		VehicleID: -1,
+	}
+	_ = c.writePacket(detach)
+	c.players.BroadcastToTrackers(detach, c.self.EntityID)
+}
+
 func cmdHelp(c *Connection, _ []string) {
+	op := c.isOp()
 	c.sendSystemMsg("--- Available Commands ---", "yellow")
 	for _, cmd := range commands {
+		if cmd.requiresOp && !op {
+			continue
+		}
 		c.sendSystemMsg(fmt.Sprintf("%s - %s", cmd.usage, cmd.desc), "yellow")
 	}
 }
@@ -130,6 +256,9 @@ func cmdTp(c *Connection, args []string) {
 			return
 		}
 		pos := target.GetPosition()
+		if !c.checkInBorder(pos.X, pos.Z) {
+			return
+		}
 		c.teleportSelf(pos.X, pos.Y, pos.Z)
 		c.sendSuccessMsg(fmt.Sprintf("Teleported to %s.", target.Username))
 
@@ -141,6 +270,9 @@ func cmdTp(c *Connection, args []string) {
 			c.sendErrorMsg("Usage: /tp <x> <y> <z> (numbers)")
 			return
 		}
+		if !c.checkInBorder(x, z) {
+			return
+		}
 		c.teleportSelf(x, y, z)
 		c.sendSuccessMsg(fmt.Sprintf("Teleported to %.1f, %.1f, %.1f.", x, y, z))
 
@@ -150,64 +282,105 @@ func cmdTp(c *Connection, args []string) {
 }
 
 func cmdGamemode(c *Connection, args []string) {
-	if len(args) != 1 {
-		c.sendErrorMsg("Usage: /gamemode <survival|creative|adventure|spectator>")
+	if len(args) < 1 || len(args) > 2 {
+		c.sendErrorMsg("Usage: /gamemode <survival|creative|adventure|spectator> [player]")
+		return
+	}
+
+	mode, modeName, ok := parseGameMode(args[0])
+	if !ok {
+		c.sendErrorMsg("Unknown game mode. Use: survival, creative, adventure, spectator")
 		return
 	}
 
-	var mode uint8
-	var abilities int8
-	modeName := strings.ToLower(args[0])
+	if len(args) == 1 {
+		c.applyGameMode(c.self, c.writePacket, mode)
+		c.sendSuccessMsg(fmt.Sprintf("Game mode set to %s.", modeName))
+		return
+	}
+
+	target := c.players.GetByName(args[1])
+	if target == nil {
+		c.sendErrorMsg(fmt.Sprintf("Player %q not found.", args[1]))
+		return
+	}
+	write := target.WritePacket
+	if target == c.self {
+		write = c.writePacket
+	}
+	c.applyGameMode(target, write, mode)
+	if target != c.self {
+		_ = write(&pkt.ChatCB{
+			Message:  chat.Colored(fmt.Sprintf("Your game mode has been set to %s.", modeName), chat.Gold).String(),
+			Position: 1,
+		})
+	}
+	c.sendSuccessMsg(fmt.Sprintf("Set %s's game mode to %s.", target.Username, modeName))
+}
 
-	switch modeName {
+// parseGameMode resolves a game mode name, abbreviation or number.
+func parseGameMode(s string) (mode uint8, name string, ok bool) {
+	switch strings.ToLower(s) {
 	case "survival", "s", "0":
-		mode = packet.GameModeSurvival
-		abilities = 0
-		modeName = "survival"
+		return packet.GameModeSurvival, "survival", true
 	case "creative", "c", "1":
-		mode = packet.GameModeCreative
-		abilities = packet.AbilityInvulnerable | packet.AbilityAllowFlight | packet.AbilityCreativeMode
-		modeName = "creative"
+		return packet.GameModeCreative, "creative", true
 	case "adventure", "a", "2":
-		mode = packet.GameModeAdventure
-		abilities = 0
-		modeName = "adventure"
+		return packet.GameModeAdventure, "adventure", true
 	case "spectator", "sp", "3":
-		mode = packet.GameModeSpectator
-		abilities = packet.AbilityInvulnerable | packet.AbilityAllowFlight
-		modeName = "spectator"
-	default:
-		c.sendErrorMsg("Unknown game mode. Use: survival, creative, adventure, spectator")
-		return
+		return packet.GameModeSpectator, "spectator", true
+	}
+	return 0, "", false
+}
+
+// gameModeName returns the lowercase name of a game mode number.
+func gameModeName(mode uint8) string {
+	if _, name, ok := parseGameMode(strconv.Itoa(int(mode))); ok {
+		return name
 	}
+	return "unknown"
+}
 
-	_ = c.writePacket(&pkt.GameStateChange{
-		Reason:   3, // Change game mode
+// applyGameMode switches p to mode, sending the game state change and
+// abilities through write and broadcasting the tab-list update. The mode is
+// stored on the player, so it is persisted with the rest of their data.
+func (c *Connection) applyGameMode(p *player.Player, write func(mcnet.Packet) error, mode uint8) {
+	_ = write(&pkt.GameStateChange{
+		Reason:   packet.GameStateGameMode,
 		GameMode: float32(mode),
 	})
 
-	c.self.SetGameMode(mode)
+	p.SetGameMode(mode)
 
-	_ = c.writePacket(&pkt.AbilitiesCB{
-		Flags:        abilities,
-		FlyingSpeed:  0.05,
-		WalkingSpeed: 0.1,
-	})
+	_ = write(abilitiesPacket(p))
 
 	// Broadcast gamemode change to all players (tab list update).
-	c.players.BroadcastGameMode(c.self)
-
-	c.sendSuccessMsg(fmt.Sprintf("Game mode set to %s.", modeName))
+	c.players.BroadcastGameMode(p)
 }
 
+// timeUsage is the usage message for /time.
+const timeUsage = "Usage: /time set <day|night|noon|midnight|number> | /time add <ticks> | /time query <daytime|gametime>"
+
 func cmdTime(c *Connection, args []string) {
-	if len(args) != 2 || strings.ToLower(args[0]) != "set" {
-		c.sendErrorMsg("Usage: /time set <day|night|noon|midnight|number>")
+	if len(args) != 2 {
+		c.sendErrorMsg(timeUsage)
 		return
 	}
+	switch strings.ToLower(args[0]) {
+	case "set":
+		timeSet(c, args[1])
+	case "add":
+		timeAdd(c, args[1])
+	case "query":
+		timeQuery(c, args[1])
+	default:
+		c.sendErrorMsg(timeUsage)
+	}
+}
 
+func timeSet(c *Connection, value string) {
 	var ticks int64
-	switch strings.ToLower(args[1]) {
+	switch strings.ToLower(value) {
 	case "day":
 		ticks = 1000
 	case "noon":
@@ -217,21 +390,69 @@ func cmdTime(c *Connection, args []string) {
 	case "midnight":
 		ticks = 18000
 	default:
-		v, err := strconv.ParseInt(args[1], 10, 64)
+		v, err := strconv.ParseInt(value, 10, 64)
 		if err != nil {
-			c.sendErrorMsg("Usage: /time set <day|night|noon|midnight|number>")
+			c.sendErrorMsg(timeUsage)
 			return
 		}
 		ticks = v
 	}
 
+	c.setTimeOfDay(ticks)
+	c.sendSuccessMsg(fmt.Sprintf("Time set to %d.", ticks))
+}
+
+// timeAdd advances the time of day, wrapping within the day. Frozen time
+// (negative) stays frozen at the new time.
+func timeAdd(c *Connection, value string) {
+	delta, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		c.sendErrorMsg(timeUsage)
+		return
+	}
+
+	_, tod := c.world.GetTime()
+	frozen := tod < 0
+	if frozen {
+		tod = -tod
+	}
+	ticks := ((tod+delta)%24000 + 24000) % 24000
+	if frozen {
+		ticks = -ticks
+	}
+
+	c.setTimeOfDay(ticks)
+	c.sendSuccessMsg(fmt.Sprintf("Added %d to the time; it is now %d.", delta, ticks))
+}
+
+func timeQuery(c *Connection, what string) {
+	age, tod := c.world.GetTime()
+	switch strings.ToLower(what) {
+	case "daytime":
+		if tod < 0 {
+			tod = -tod
+		}
+		c.sendSuccessMsg(fmt.Sprintf("The time is %d.", tod%24000))
+	case "gametime":
+		c.sendSuccessMsg(fmt.Sprintf("The time is %d.", age))
+	default:
+		c.sendErrorMsg(timeUsage)
+	}
+}
+
+// setTimeOfDay changes the world's time of day and broadcasts it.
+func (c *Connection) setTimeOfDay(ticks int64) {
 	c.world.SetTimeOfDay(ticks)
-	age, _ := c.world.GetTime()
+	c.broadcastTime()
+}
+
+// broadcastTime sends the world time to every player.
+func (c *Connection) broadcastTime() {
+	age, timeOfDay := c.world.ClientTime()
 	c.players.Broadcast(&pkt.UpdateTime{
 		Age:  age,
-		Time: ticks,
+		Time: timeOfDay,
 	})
-	c.sendSuccessMsg(fmt.Sprintf("Time set to %d.", ticks))
 }
 
 func cmdSay(c *Connection, args []string) {
@@ -240,12 +461,8 @@ func cmdSay(c *Connection, args []string) {
 		return
 	}
 	msg := strings.Join(args, " ")
-	chatJSON := fmt.Sprintf(
-		`{"text":"[Server] %s","color":"light_purple"}`,
-		strings.ReplaceAll(strings.ReplaceAll(msg, `\`, `\\`), `"`, `\"`),
-	)
 	c.players.Broadcast(&pkt.ChatCB{
-		Message:  chatJSON,
+		Message:  chat.Colored("[Server] "+msg, chat.LightPurple).String(),
 		Position: 0,
 	})
 }
@@ -256,30 +473,334 @@ func cmdMe(c *Connection, args []string) {
 		return
 	}
 	action := strings.Join(args, " ")
-	chatJSON := fmt.Sprintf(
-		`{"translate":"chat.type.emote","with":[%s,%s]}`,
-		escapeJSON(c.self.Username), escapeJSON(action),
-	)
 	c.players.Broadcast(&pkt.ChatCB{
-		Message:  chatJSON,
+		Message:  chat.Translate("chat.type.emote", chat.Text(c.self.Username), chat.Text(action)).String(),
 		Position: 0,
 	})
 }
 
+func cmdMsg(c *Connection, args []string) {
+	if len(args) < 2 {
+		c.sendErrorMsg("Usage: /msg <player> <message>")
+		return
+	}
+	c.whisper(args[0], strings.Join(args[1:], " "))
+}
+
+func cmdReply(c *Connection, args []string) {
+	if len(args) == 0 {
+		c.sendErrorMsg("Usage: /reply <message>")
+		return
+	}
+	name := c.self.LastMessaged()
+	if name == "" {
+		c.sendErrorMsg("You have nobody to reply to.")
+		return
+	}
+	c.whisper(name, strings.Join(args, " "))
+}
+
+// whisper sends a private message to the named player and echoes it back
+// to the sender. Both remember each other as their /reply target.
+func (c *Connection) whisper(name, msg string) {
+	target := c.players.GetByName(name)
+	if target == nil {
+		c.sendErrorMsg(fmt.Sprintf("Player %q not found.", name))
+		return
+	}
+	if target == c.self {
+		c.sendErrorMsg("You can't send a private message to yourself!")
+		return
+	}
+
+	incoming := chat.Translate("commands.message.display.incoming", chat.Text(c.self.Username), chat.Text(msg))
+	incoming.Color, incoming.Italic = chat.Gray, true
+	_ = target.WritePacket(&pkt.ChatCB{Message: incoming.String()})
+
+	outgoing := chat.Translate("commands.message.display.outgoing", chat.Text(target.Username), chat.Text(msg))
+	outgoing.Color, outgoing.Italic = chat.Gray, true
+	_ = c.writePacket(&pkt.ChatCB{Message: outgoing.String()})
+
+	target.SetLastMessaged(c.self.Username)
+	c.self.SetLastMessaged(target.Username)
+}
+
 func cmdKill(c *Connection, _ []string) {
-	c.dead = true
-	_ = c.writePacket(&pkt.UpdateHealth{
-		Health:         0,
-		Food:           0,
-		FoodSaturation: 0,
-	})
-	c.players.BroadcastToTrackers(&pkt.EntityStatus{
-		EntityID:     c.self.EntityID,
-		EntityStatus: 3, // death animation
-	}, c.self.EntityID)
+	c.self.SetHealth(0)
+	c.sendHealth()
+	c.killPlayer(c.self)
 	c.sendSuccessMsg("You killed yourself.")
 }
 
+// weatherCommandDuration is how long /weather lasts without a duration, in
+// seconds: five minutes, as in vanilla.
+const weatherCommandDuration = 300
+
+// maxWeatherDuration caps the /weather duration at vanilla's one million
+// seconds.
+const maxWeatherDuration = 1000000
+
+func cmdWeather(c *Connection, args []string) {
+	const usage = "Usage: /weather <clear|rain|thunder> [duration]"
+	if len(args) < 1 || len(args) > 2 {
+		c.sendErrorMsg(usage)
+		return
+	}
+	wt, ok := world.ParseWeather(strings.ToLower(args[0]))
+	if !ok {
+		c.sendErrorMsg(usage)
+		return
+	}
+	seconds := weatherCommandDuration
+	if len(args) == 2 {
+		v, err := strconv.Atoi(args[1])
+		if err != nil || v < 1 || v > maxWeatherDuration {
+			c.sendErrorMsg(fmt.Sprintf("Duration must be between 1 and %d seconds.", maxWeatherDuration))
+			return
+		}
+		seconds = v
+	}
+
+	c.world.SetWeather(wt, int64(seconds)*20)
+	c.players.BroadcastWeather(wt)
+	c.sendSuccessMsg(fmt.Sprintf("Weather set to %s for %d seconds.", wt, seconds))
+}
+
+func cmdGamerule(c *Connection, args []string) {
+	switch len(args) {
+	case 0:
+		rules := world.GameRuleNames()
+		for i, name := range rules {
+			v, _ := c.world.GameRule(name)
+			rules[i] = name + " = " + v
+		}
+		c.sendSystemMsg("Game rules: "+strings.Join(rules, ", "), "yellow")
+	case 1:
+		v, ok := c.world.GameRule(args[0])
+		if !ok {
+			c.sendErrorMsg(fmt.Sprintf("No game rule called %q is available.", args[0]))
+			return
+		}
+		c.sendSuccessMsg(fmt.Sprintf("%s = %s", args[0], v))
+	case 2:
+		if _, ok := c.world.GameRule(args[0]); !ok {
+			c.sendErrorMsg(fmt.Sprintf("No game rule called %q is available.", args[0]))
+			return
+		}
+		value := strings.ToLower(args[1])
+		if err := c.world.SetGameRule(args[0], value); err != nil {
+			c.sendErrorMsg("Value must be true or false.")
+			return
+		}
+		if args[0] == world.RuleDoDaylightCycle {
+			c.broadcastTime() // start or stop the sun on clients
+		}
+		c.sendSuccessMsg(fmt.Sprintf("Game rule %s has been updated to %s.", args[0], value))
+	default:
+		c.sendErrorMsg("Usage: /gamerule [rule] [value]")
+	}
+}
+
+func cmdWorldborder(c *Connection, args []string) {
+	const usage = "Usage: /worldborder <set <size>|center <x> <z>|get>"
+	if len(args) == 0 {
+		c.sendErrorMsg(usage)
+		return
+	}
+	border := c.world.Border()
+	limit := c.borderLimit()
+	switch {
+	case strings.EqualFold(args[0], "get") && len(args) == 1:
+		c.sendSuccessMsg(fmt.Sprintf("The world border is %.0f blocks wide, centered on %.1f, %.1f.",
+			border.Diameter, border.CenterX, border.CenterZ))
+		return
+	case strings.EqualFold(args[0], "set") && len(args) == 2:
+		size, err := strconv.ParseFloat(args[1], 64)
+		if err != nil || size < 1 || size > limit.Diameter {
+			c.sendErrorMsg(fmt.Sprintf("Size must be between 1 and %.0f blocks.", limit.Diameter))
+			return
+		}
+		border.Diameter = size
+	case strings.EqualFold(args[0], "center") && len(args) == 3:
+		x, errX := strconv.ParseFloat(args[1], 64)
+		z, errZ := strconv.ParseFloat(args[2], 64)
+		if errX != nil || errZ != nil {
+			c.sendErrorMsg(usage)
+			return
+		}
+		border.CenterX, border.CenterZ = x, z
+	default:
+		c.sendErrorMsg(usage)
+		return
+	}
+	if !border.Within(limit) {
+		c.sendErrorMsg(fmt.Sprintf("The world border must fit inside the world: %.0f blocks wide, centered on %.1f, %.1f.",
+			limit.Diameter, limit.CenterX, limit.CenterZ))
+		return
+	}
+
+	c.world.SetBorder(border)
+	c.players.BroadcastWorldBorder(border)
+	c.sendSuccessMsg(fmt.Sprintf("Set the world border to %.0f blocks wide, centered on %.1f, %.1f.",
+		border.Diameter, border.CenterX, border.CenterZ))
+}
+
+// borderLimit returns the largest border /worldborder may set: the world
+// radius in a bounded world, else the vanilla maximum.
+func (c *Connection) borderLimit() world.Border {
+	if c.cfg.WorldRadius > 0 {
+		return world.RadiusBorder(c.cfg.WorldRadius)
+	}
+	return world.DefaultBorder()
+}
+
+func cmdSetworldspawn(c *Connection, args []string) {
+	var x, y, z int
+	switch len(args) {
+	case 0:
+		pos := c.self.GetPosition()
+		x, y, z = int(math.Floor(pos.X)), int(math.Floor(pos.Y)), int(math.Floor(pos.Z))
+	case 3:
+		var ok bool
+		if x, y, z, ok = c.parseBlockPos(args); !ok {
+			return
+		}
+	default:
+		c.sendErrorMsg("Usage: /setworldspawn [x y z]")
+		return
+	}
+	if y < 0 || y > 255 {
+		c.sendErrorMsg("Y coordinate must be between 0 and 255.")
+		return
+	}
+
+	c.world.SetSpawn(world.BlockPos{X: x, Y: y, Z: z})
+	// Compasses point at the spawn.
+	c.players.Broadcast(&pkt.SpawnPosition{Location: mcnet.EncodePosition(x, y, z)})
+	c.sendSuccessMsg(fmt.Sprintf("World spawn set to %d, %d, %d.", x, y, z))
+}
+
+func cmdSpawn(c *Connection, _ []string) {
+	spawn := c.world.Spawn()
+	x, z := float64(spawn.X)+0.5, float64(spawn.Z)+0.5
+	if !c.checkInBorder(x, z) {
+		return
+	}
+	c.teleportSelf(x, float64(spawn.Y), z)
+	c.sendSuccessMsg("Teleported to spawn.")
+}
+
+func cmdSethome(c *Connection, _ []string) {
+	pos := c.self.GetPosition()
+	c.self.SetHome(pos)
+	c.sendSuccessMsg(fmt.Sprintf("Home set to %.1f, %.1f, %.1f.", pos.X, pos.Y, pos.Z))
+}
+
+func cmdHome(c *Connection, _ []string) {
+	home, ok := c.self.Home()
+	if !ok {
+		c.sendSystemMsg("You have no home set; teleporting to spawn. Use /sethome to set one.", "yellow")
+		cmdSpawn(c, nil)
+		return
+	}
+	if !c.checkInBorder(home.X, home.Z) {
+		return
+	}
+	c.teleportSelf(home.X, home.Y, home.Z)
+	c.sendSuccessMsg("Teleported home.")
+}
+
+// warps returns the warps, reporting failures to the player.
+func (c *Connection) warps() (map[string]storage.PositionData, bool) {
+	if c.storage == nil {
+		c.sendErrorMsg("Warps are not available.")
+		return nil, false
+	}
+	warps, err := c.storage.Warps()
+	if err != nil {
+		c.log.Error("load warps", "error", err)
+		c.sendErrorMsg("Failed to read the warps.")
+		return nil, false
+	}
+	return warps, true
+}
+
+func cmdWarp(c *Connection, args []string) {
+	if len(args) > 1 {
+		c.sendErrorMsg("Usage: /warp [name]")
+		return
+	}
+	warps, ok := c.warps()
+	if !ok {
+		return
+	}
+
+	if len(args) == 0 {
+		names := make([]string, 0, len(warps))
+		for name := range warps {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		c.sendSuccessMsg(fmt.Sprintf("Warps (%d): %s", len(names), strings.Join(names, ", ")))
+		return
+	}
+
+	w, ok := warps[strings.ToLower(args[0])]
+	if !ok {
+		c.sendErrorMsg(fmt.Sprintf("Warp %q not found.", args[0]))
+		return
+	}
+	if !c.checkInBorder(w.X, w.Z) {
+		return
+	}
+	c.teleportSelf(w.X, w.Y, w.Z)
+	c.sendSuccessMsg(fmt.Sprintf("Warped to %s.", strings.ToLower(args[0])))
+}
+
+func cmdSetwarp(c *Connection, args []string) {
+	if len(args) != 1 {
+		c.sendErrorMsg("Usage: /setwarp <name>")
+		return
+	}
+	if c.storage == nil {
+		c.sendErrorMsg("Warps are not available.")
+		return
+	}
+
+	name := strings.ToLower(args[0])
+	pos := c.self.GetPosition()
+	warp := storage.PositionData{X: pos.X, Y: pos.Y, Z: pos.Z, Yaw: pos.Yaw, Pitch: pos.Pitch}
+	if err := c.storage.SetWarp(name, warp); err != nil {
+		c.log.Error("save warps", "error", err)
+		c.sendErrorMsg("Failed to save the warps.")
+		return
+	}
+	c.sendSuccessMsg(fmt.Sprintf("Warp %s set to %.1f, %.1f, %.1f.", name, pos.X, pos.Y, pos.Z))
+}
+
+func cmdDelwarp(c *Connection, args []string) {
+	if len(args) != 1 {
+		c.sendErrorMsg("Usage: /delwarp <name>")
+		return
+	}
+	if c.storage == nil {
+		c.sendErrorMsg("Warps are not available.")
+		return
+	}
+
+	name := strings.ToLower(args[0])
+	found, err := c.storage.DeleteWarp(name)
+	switch {
+	case err != nil:
+		c.log.Error("save warps", "error", err)
+		c.sendErrorMsg("Failed to save the warps.")
+	case !found:
+		c.sendErrorMsg(fmt.Sprintf("Warp %q not found.", args[0]))
+	default:
+		c.sendSuccessMsg(fmt.Sprintf("Deleted warp %s.", name))
+	}
+}
+
 func cmdSeed(c *Connection, _ []string) {
 	c.sendSuccessMsg(fmt.Sprintf("Seed: [%d]", c.cfg.Seed))
 }
@@ -295,3 +816,1243 @@ func cmdSave(c *Connection, _ []string) {
 		c.sendSuccessMsg("Save complete.")
 	}()
 }
+
+func cmdTPS(c *Connection, _ []string) {
+	if c.TickStats == nil {
+		c.sendErrorMsg("Tick statistics are not available.")
+		return
+	}
+	tps, ms := c.TickStats()
+	color := "green"
+	switch {
+	case tps < 15:
+		color = "red"
+	case tps < 19:
+		color = "yellow"
+	}
+	c.sendSystemMsg(fmt.Sprintf("TPS: %.1f (%.2f ms/tick)", tps, ms), color)
+}
+
+// maxSetBiomeRadius bounds the region form of /setbiome.
+const maxSetBiomeRadius = 32
+
+func cmdSetbiome(c *Connection, args []string) {
+	if len(args) < 1 || len(args) > 2 {
+		c.sendErrorMsg("Usage: /setbiome <biome> [radius]")
+		return
+	}
+	if c.gameData == nil || c.gameData.Biomes == nil {
+		c.sendErrorMsg("Biome data is not available.")
+		return
+	}
+	biome, ok := c.gameData.Biomes.ByName(strings.ToLower(args[0]))
+	if !ok {
+		c.sendErrorMsg(fmt.Sprintf("Unknown biome: %s", args[0]))
+		return
+	}
+	radius := 0
+	if len(args) == 2 {
+		r, err := strconv.Atoi(args[1])
+		if err != nil || r < 0 || r > maxSetBiomeRadius {
+			c.sendErrorMsg(fmt.Sprintf("Radius must be between 0 and %d.", maxSetBiomeRadius))
+			return
+		}
+		radius = r
+	}
+
+	pos := c.self.GetPosition()
+	px, pz := int(math.Floor(pos.X)), int(math.Floor(pos.Z))
+	chunks := make(map[gen.ChunkPos]struct{})
+	for x := px - radius; x <= px+radius; x++ {
+		for z := pz - radius; z <= pz+radius; z++ {
+			c.world.SetBiome(x, z, byte(biome.ID))
+			chunks[gen.ChunkPos{X: x >> 4, Z: z >> 4}] = struct{}{}
+		}
+	}
+	c.resendChunks(chunks)
+
+	side := 2*radius + 1
+	c.sendSuccessMsg(fmt.Sprintf("Set biome to %s for %d columns.", biome.DisplayName, side*side))
+}
+
+// maxRegenerateRadius bounds /regenerate, in chunks.
+const maxRegenerateRadius = 8
+
+func cmdRegenerate(c *Connection, args []string) {
+	radius := 0
+	confirm := false
+	for _, arg := range args {
+		if strings.EqualFold(arg, "confirm") {
+			confirm = true
+			continue
+		}
+		r, err := strconv.Atoi(arg)
+		if err != nil || r < 0 || r > maxRegenerateRadius {
+			c.sendErrorMsg(fmt.Sprintf("Usage: /regenerate [radius 0-%d] [confirm]", maxRegenerateRadius))
+			return
+		}
+		radius = r
+	}
+
+	pcx, pcz := c.self.ChunkX(), c.self.ChunkZ()
+	chunks := make(map[gen.ChunkPos]struct{})
+	edited := 0
+	for cx := pcx - radius; cx <= pcx+radius; cx++ {
+		for cz := pcz - radius; cz <= pcz+radius; cz++ {
+			chunks[gen.ChunkPos{X: cx, Z: cz}] = struct{}{}
+			edited += len(c.world.OverridesForChunk(cx, cz))
+		}
+	}
+
+	// Player builds live only in the overrides, so require confirmation
+	// before throwing them away.
+	if edited > 0 && !confirm {
+		c.sendErrorMsg(fmt.Sprintf("%d edited blocks would be lost. Run /regenerate %d confirm to proceed.", edited, radius))
+		return
+	}
+
+	removed := 0
+	for pos := range chunks {
+		removed += c.world.RegenerateChunk(pos.X, pos.Z)
+	}
+	c.resendChunks(chunks)
+	c.log.Info("regenerated chunks", "chunks", len(chunks), "overridesRemoved", removed)
+	c.sendSuccessMsg(fmt.Sprintf("Regenerated %d chunks, removed %d edited blocks.", len(chunks), removed))
+}
+
+// resendChunks sends fresh copies of the given chunks to every player in
+// view of them, e.g. after a biome change.
+func (c *Connection) resendChunks(chunks map[gen.ChunkPos]struct{}) {
+	for pos := range chunks {
+		chunk := c.world.EncodeChunk(pos.X, pos.Z)
+		signs := c.world.SignsInChunk(pos.X, pos.Z)
+		c.players.ForEach(func(p *player.Player) {
+			if player.InViewDistance(pos.X, pos.Z, p.ChunkX(), p.ChunkZ(), c.cfg.ViewDistance) {
+				_ = p.WritePacket(&chunk)
+				for bpos, lines := range signs {
+					_ = p.WritePacket(signPacket(bpos, lines))
+				}
+			}
+		})
+	}
+}
+
+func cmdKick(c *Connection, args []string) {
+	if len(args) < 1 {
+		c.sendErrorMsg("Usage: /kick <player> [reason]")
+		return
+	}
+	target := c.players.GetByName(args[0])
+	if target == nil {
+		c.sendErrorMsg(fmt.Sprintf("Player %q not found.", args[0]))
+		return
+	}
+	if target.Disconnect == nil {
+		c.sendErrorMsg(fmt.Sprintf("%s cannot be kicked.", target.Username))
+		return
+	}
+
+	reason := strings.Join(args[1:], " ")
+	target.Disconnect(reason)
+	if reason == "" {
+		c.sendSuccessMsg(fmt.Sprintf("Kicked %s.", target.Username))
+	} else {
+		c.sendSuccessMsg(fmt.Sprintf("Kicked %s: %s", target.Username, reason))
+	}
+}
+
+func cmdWhois(c *Connection, args []string) {
+	if len(args) != 1 {
+		c.sendErrorMsg("Usage: /whois <player>")
+		return
+	}
+	target := c.players.GetByName(args[0])
+	if target == nil {
+		c.sendErrorMsg(fmt.Sprintf("Player %q not found.", args[0]))
+		return
+	}
+
+	pos := target.GetPosition()
+	addr := target.RemoteAddr
+	if addr == "" {
+		addr = "unknown"
+	}
+	online := "unknown"
+	if !target.JoinedAt.IsZero() {
+		online = time.Since(target.JoinedAt).Truncate(time.Second).String()
+	}
+
+	c.sendSystemMsg(fmt.Sprintf("--- %s ---", target.Username), "yellow")
+	c.sendSystemMsg(fmt.Sprintf("UUID: %s", target.UUID), "yellow")
+	c.sendSystemMsg(fmt.Sprintf("IP: %s", addr), "yellow")
+	c.sendSystemMsg(fmt.Sprintf("Game mode: %s", gameModeName(target.GetGameMode())), "yellow")
+	c.sendSystemMsg(fmt.Sprintf("Position: %.1f, %.1f, %.1f", pos.X, pos.Y, pos.Z), "yellow")
+	c.sendSystemMsg(fmt.Sprintf("Ping: %d ms", target.Ping().Milliseconds()), "yellow")
+	c.sendSystemMsg(fmt.Sprintf("Online for: %s", online), "yellow")
+}
+
+func cmdGive(c *Connection, args []string) {
+	if len(args) < 2 || len(args) > 4 {
+		c.sendErrorMsg("Usage: /give <player|@s> <item> [count] [damage]")
+		return
+	}
+	if c.gameData == nil || c.gameData.Items == nil {
+		c.sendErrorMsg("Item data is not available.")
+		return
+	}
+
+	target := c.self
+	if args[0] != "@s" {
+		target = c.players.GetByName(args[0])
+		if target == nil {
+			c.sendErrorMsg(fmt.Sprintf("Player %q not found.", args[0]))
+			return
+		}
+	}
+
+	item, ok := c.lookupItem(args[1])
+	if !ok {
+		c.sendErrorMsg(fmt.Sprintf("Unknown item: %s", args[1]))
+		return
+	}
+
+	// A single /give hands out at most one full stack of the item.
+	stackSize := max(item.StackSize, 1)
+	count := 1
+	if len(args) >= 3 {
+		n, err := strconv.Atoi(args[2])
+		if err != nil || n < 1 {
+			c.sendErrorMsg("Count must be a positive number.")
+			return
+		}
+		count = min(n, stackSize)
+	}
+	var damage int16
+	if len(args) == 4 {
+		d, err := strconv.ParseInt(args[3], 10, 16)
+		if err != nil || d < 0 {
+			c.sendErrorMsg("Damage must be a non-negative number.")
+			return
+		}
+		damage = int16(d)
+	}
+
+	before := target.Inventory.ToProtocolSlots()
+	left := target.Inventory.AddItemStack(player.Slot{BlockID: int16(item.ID), ItemCount: int8(count), ItemDamage: damage}, stackSize)
+	given := count - int(left.ItemCount)
+
+	if target == c.self {
+		_ = c.sendWindowItems()
+	} else {
+		syncInventorySlots(target, before)
+	}
+
+	if given == 0 {
+		c.sendErrorMsg(fmt.Sprintf("%s's inventory is full.", target.Username))
+		return
+	}
+	c.sendSuccessMsg(fmt.Sprintf("Gave %d %s to %s.", given, item.DisplayName, target.Username))
+}
+
+// maxSpeed is the largest multiplier accepted by /speed.
+const maxSpeed = 10
+
+func cmdSpeed(c *Connection, args []string) {
+	const usage = "Usage: /speed <fly|walk> <0-10>"
+	if len(args) != 2 {
+		c.sendErrorMsg(usage)
+		return
+	}
+	m, err := strconv.ParseFloat(args[1], 32)
+	if err != nil || math.IsNaN(m) || m < 0 || m > maxSpeed {
+		c.sendErrorMsg(fmt.Sprintf("Speed must be between 0 and %d.", maxSpeed))
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "fly":
+		c.self.SetFlySpeed(float32(m))
+	case "walk":
+		c.self.SetWalkSpeed(float32(m))
+		_ = c.writePacket(c.self.AttributesPacket())
+	default:
+		c.sendErrorMsg(usage)
+		return
+	}
+	_ = c.writePacket(abilitiesPacket(c.self))
+	c.sendSuccessMsg(fmt.Sprintf("Set %s speed to %g.", strings.ToLower(args[0]), m))
+}
+
+// maxXPAmount caps a single /xp grant, as in vanilla.
+const maxXPAmount = 1 << 30
+
+func cmdXP(c *Connection, args []string) {
+	const usage = "Usage: /xp <amount>[L] [player]"
+	if len(args) < 1 || len(args) > 2 {
+		c.sendErrorMsg(usage)
+		return
+	}
+
+	target := c.self
+	if len(args) == 2 && args[1] != "@s" {
+		target = c.players.GetByName(args[1])
+		if target == nil {
+			c.sendErrorMsg(fmt.Sprintf("Player %q not found.", args[1]))
+			return
+		}
+	}
+
+	amount, levels := strings.CutSuffix(strings.ToLower(args[0]), "l")
+	n, err := strconv.Atoi(amount)
+	if err != nil || n < -maxXPAmount || n > maxXPAmount {
+		c.sendErrorMsg(usage)
+		return
+	}
+
+	if levels {
+		target.AddExperienceLevels(int32(n))
+	} else {
+		if n < 0 {
+			c.sendErrorMsg("Cannot give a player negative experience points.")
+			return
+		}
+		target.AddExperience(int32(n))
+	}
+	_ = target.WritePacket(target.ExperiencePacket())
+
+	switch {
+	case !levels:
+		c.sendSuccessMsg(fmt.Sprintf("Gave %d experience to %s.", n, target.Username))
+	case n < 0:
+		c.sendSuccessMsg(fmt.Sprintf("Took %d levels from %s.", -n, target.Username))
+	default:
+		c.sendSuccessMsg(fmt.Sprintf("Gave %d levels to %s.", n, target.Username))
+	}
+}
+
+// maxSummonXP caps the experience a single /summon xp drops.
+const maxSummonXP = 100000
+
+func cmdSummon(c *Connection, args []string) {
+	const usage = "Usage: /summon item <item> [count] | /summon xp <amount>"
+	if len(args) < 2 {
+		c.sendErrorMsg(usage)
+		return
+	}
+	pos := c.self.GetPosition()
+
+	switch strings.ToLower(args[0]) {
+	case "item":
+		if len(args) > 3 {
+			c.sendErrorMsg(usage)
+			return
+		}
+		if c.gameData == nil || c.gameData.Items == nil {
+			c.sendErrorMsg("Item data is not available.")
+			return
+		}
+		item, ok := c.lookupItem(args[1])
+		if !ok {
+			c.sendErrorMsg(fmt.Sprintf("Unknown item: %s", args[1]))
+			return
+		}
+		count := 1
+		if len(args) == 3 {
+			n, err := strconv.Atoi(args[2])
+			if err != nil || n < 1 {
+				c.sendErrorMsg("Count must be a positive number.")
+				return
+			}
+			count = min(n, max(item.StackSize, 1))
+		}
+		slot := player.Slot{BlockID: int16(item.ID), ItemCount: int8(count)}
+		c.players.SpawnItemEntity(c.self.EntityID, slot, pos.X, pos.Y+1.3, pos.Z, pos.Yaw, c.groundAtFunc())
+		c.sendSuccessMsg(fmt.Sprintf("Summoned %d %s.", count, item.Name))
+
+	case "xp":
+		if len(args) != 2 {
+			c.sendErrorMsg(usage)
+			return
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 || n > maxSummonXP {
+			c.sendErrorMsg(fmt.Sprintf("Amount must be between 1 and %d.", maxSummonXP))
+			return
+		}
+		orbs := c.players.SpawnExperienceOrbs(pos.X, pos.Y, pos.Z, int32(n))
+		c.sendSuccessMsg(fmt.Sprintf("Summoned %d experience in %d orbs.", n, orbs))
+
+	default:
+		c.sendErrorMsg(usage)
+	}
+}
+
+func cmdTitle(c *Connection, args []string) {
+	if len(args) < 2 {
+		c.sendErrorMsg("Usage: /title <player> <text>")
+		return
+	}
+	target := c.players.GetByName(args[0])
+	if target == nil {
+		c.sendErrorMsg(fmt.Sprintf("Player %q not found.", args[0]))
+		return
+	}
+
+	sendTitle(target, strings.Join(args[1:], " "), "",
+		player.DefaultTitleFadeIn, player.DefaultTitleStay, player.DefaultTitleFadeOut)
+	c.sendSuccessMsg(fmt.Sprintf("Showed a title to %s.", target.Username))
+}
+
+// sendTitle shows p a title and optional subtitle in the middle of the
+// screen. Timings are in ticks.
+func sendTitle(p *player.Player, title, subtitle string, fadeIn, stay, fadeOut int) {
+	for _, pk := range player.TitlePackets(title, subtitle, int32(fadeIn), int32(stay), int32(fadeOut)) {
+		_ = p.WritePacket(pk)
+	}
+}
+
+// scoreboardSlots maps /scoreboard display slot names to their IDs.
+var scoreboardSlots = map[string]int8{
+	"list":      player.DisplayList,
+	"sidebar":   player.DisplaySidebar,
+	"belowname": player.DisplayBelowName,
+}
+
+func cmdScoreboard(c *Connection, args []string) {
+	const usage = "Usage: /scoreboard objectives add <name> [display name] | " +
+		"/scoreboard objectives setdisplay <list|sidebar|belowName> [objective] | " +
+		"/scoreboard players set <entry> <objective> <score>"
+	if len(args) < 2 {
+		c.sendErrorMsg(usage)
+		return
+	}
+	sb := c.players.Scoreboard()
+
+	switch group, sub := strings.ToLower(args[0]), strings.ToLower(args[1]); {
+	case group == "objectives" && sub == "add" && len(args) >= 3:
+		name := args[2]
+		if len(name) > player.MaxObjectiveNameLength {
+			c.sendErrorMsg(fmt.Sprintf("Objective names can be at most %d characters.", player.MaxObjectiveNameLength))
+			return
+		}
+		display := name
+		if len(args) > 3 {
+			display = strings.Join(args[3:], " ")
+		}
+		if len(display) > player.MaxObjectiveDisplayLength {
+			c.sendErrorMsg(fmt.Sprintf("Display names can be at most %d characters.", player.MaxObjectiveDisplayLength))
+			return
+		}
+		if !sb.AddObjective(name, display) {
+			c.sendErrorMsg(fmt.Sprintf("An objective named %s already exists.", name))
+			return
+		}
+		c.sendSuccessMsg(fmt.Sprintf("Added objective %s.", name))
+
+	case group == "objectives" && sub == "setdisplay" && (len(args) == 3 || len(args) == 4):
+		slot, ok := scoreboardSlots[strings.ToLower(args[2])]
+		if !ok {
+			c.sendErrorMsg(fmt.Sprintf("Unknown display slot: %s", args[2]))
+			return
+		}
+		var name string
+		if len(args) == 4 {
+			name = args[3]
+		}
+		if !sb.SetDisplay(slot, name) {
+			c.sendErrorMsg(fmt.Sprintf("Unknown objective: %s", name))
+			return
+		}
+		if name == "" {
+			c.sendSuccessMsg(fmt.Sprintf("Cleared the %s display slot.", args[2]))
+		} else {
+			c.sendSuccessMsg(fmt.Sprintf("Showing %s in the %s display slot.", name, args[2]))
+		}
+
+	case group == "players" && sub == "set" && len(args) == 5:
+		entry := args[2]
+		if len(entry) > player.MaxScoreEntryLength {
+			c.sendErrorMsg(fmt.Sprintf("Entry names can be at most %d characters.", player.MaxScoreEntryLength))
+			return
+		}
+		score, err := strconv.ParseInt(args[4], 10, 32)
+		if err != nil {
+			c.sendErrorMsg("Score must be a whole number.")
+			return
+		}
+		if !sb.SetScore(args[3], entry, int32(score)) {
+			c.sendErrorMsg(fmt.Sprintf("Unknown objective: %s", args[3]))
+			return
+		}
+		c.sendSuccessMsg(fmt.Sprintf("Set %s's score in %s to %d.", entry, args[3], score))
+
+	default:
+		c.sendErrorMsg(usage)
+	}
+}
+
+func cmdClear(c *Connection, args []string) {
+	if len(args) > 3 {
+		c.sendErrorMsg("Usage: /clear [player] [item] [count]")
+		return
+	}
+
+	target := c.self
+	if len(args) >= 1 && args[0] != "@s" {
+		target = c.players.GetByName(args[0])
+		if target == nil {
+			c.sendErrorMsg(fmt.Sprintf("Player %q not found.", args[0]))
+			return
+		}
+	}
+
+	blockID := int16(-1)
+	if len(args) >= 2 {
+		if c.gameData == nil || c.gameData.Items == nil {
+			c.sendErrorMsg("Item data is not available.")
+			return
+		}
+		item, ok := c.lookupItem(args[1])
+		if !ok {
+			c.sendErrorMsg(fmt.Sprintf("Unknown item: %s", args[1]))
+			return
+		}
+		blockID = int16(item.ID)
+	}
+	limit := -1
+	if len(args) == 3 {
+		n, err := strconv.Atoi(args[2])
+		if err != nil || n < 1 {
+			c.sendErrorMsg("Count must be a positive number.")
+			return
+		}
+		limit = n
+	}
+
+	before := target.Inventory.ToProtocolSlots()
+	removed := target.Inventory.RemoveItem(blockID, limit)
+	if removed == 0 {
+		c.sendErrorMsg(fmt.Sprintf("No items were found on %s.", target.Username))
+		return
+	}
+
+	if target == c.self {
+		_ = c.sendWindowItems()
+		after := c.self.Inventory.ToProtocolSlots()
+		for i := range after {
+			if after[i] != before[i] {
+				c.broadcastEquipmentIfNeeded(int16(i))
+			}
+		}
+	} else {
+		syncInventorySlots(target, before)
+	}
+	c.sendSuccessMsg(fmt.Sprintf("Cleared the inventory of %s, removing %d items.", target.Username, removed))
+}
+
+// lookupItem resolves an item by name (with or without the "minecraft:"
+// prefix) or numeric ID.
+func (c *Connection) lookupItem(s string) (gamedata.Item, bool) {
+	if id, err := strconv.Atoi(s); err == nil {
+		return c.gameData.Items.ByID(id)
+	}
+	return c.gameData.Items.ByName(strings.TrimPrefix(strings.ToLower(s), "minecraft:"))
+}
+
+// syncInventorySlots sends a SetSlot for every inventory slot of p that
+// changed since before, for players other than the connection's own.
+func syncInventorySlots(p *player.Player, before [45]player.Slot) {
+	after := p.Inventory.ToProtocolSlots()
+	for i := range after {
+		if after[i] == before[i] {
+			continue
+		}
+		_ = p.WritePacket(&pkt.SetSlot{Slot: int16(i), Item: after[i]})
+	}
+}
+
+// Limits for /effect, as in vanilla: durations default to 30 seconds and
+// are capped at one million.
+const (
+	defaultEffectSeconds = 30
+	maxEffectSeconds     = 1000000
+	maxEffectAmplifier   = 127
+)
+
+func cmdEffect(c *Connection, args []string) {
+	const usage = "Usage: /effect <player> <effect> [seconds] [amplifier] | /effect <player> clear"
+	if len(args) < 2 || len(args) > 4 {
+		c.sendErrorMsg(usage)
+		return
+	}
+	if c.gameData == nil || c.gameData.Effects == nil {
+		c.sendErrorMsg("Effect data is not available.")
+		return
+	}
+	target := c.players.GetByName(args[0])
+	if target == nil {
+		c.sendErrorMsg(fmt.Sprintf("Player %q not found.", args[0]))
+		return
+	}
+
+	if strings.EqualFold(args[1], "clear") && len(args) == 2 {
+		c.players.ClearEffects(target)
+		c.sendSuccessMsg(fmt.Sprintf("Cleared the effects of %s.", target.Username))
+		return
+	}
+
+	effect, ok := c.lookupEffect(args[1])
+	if !ok {
+		c.sendErrorMsg(fmt.Sprintf("Unknown effect: %s", args[1]))
+		return
+	}
+	seconds := defaultEffectSeconds
+	if len(args) >= 3 {
+		n, err := strconv.Atoi(args[2])
+		if err != nil || n < 1 || n > maxEffectSeconds {
+			c.sendErrorMsg(fmt.Sprintf("Seconds must be between 1 and %d.", maxEffectSeconds))
+			return
+		}
+		seconds = n
+	}
+	var amplifier int
+	if len(args) == 4 {
+		n, err := strconv.Atoi(args[3])
+		if err != nil || n < 0 || n > maxEffectAmplifier {
+			c.sendErrorMsg(fmt.Sprintf("Amplifier must be between 0 and %d.", maxEffectAmplifier))
+			return
+		}
+		amplifier = n
+	}
+
+	c.players.ApplyEffect(target, player.ActiveEffect{
+		ID:        int8(effect.ID),
+		Amplifier: int8(amplifier),
+		Ticks:     int32(seconds) * 20,
+	})
+	c.sendSuccessMsg(fmt.Sprintf("Gave %s %s %d for %d seconds.", target.Username, effect.DisplayName, amplifier+1, seconds))
+}
+
+// lookupEffect resolves an effect by numeric ID or by name, ignoring case,
+// spaces and underscores ("night_vision" matches NightVision).
+func (c *Connection) lookupEffect(s string) (gamedata.Effect, bool) {
+	if id, err := strconv.Atoi(s); err == nil {
+		return c.gameData.Effects.ByID(id)
+	}
+	norm := func(name string) string {
+		return strings.ToLower(strings.NewReplacer("_", "", " ", "").Replace(name))
+	}
+	want := norm(strings.TrimPrefix(strings.ToLower(s), "minecraft:"))
+	for _, e := range c.gameData.Effects.All() {
+		if norm(e.Name) == want {
+			return e, true
+		}
+	}
+	return gamedata.Effect{}, false
+}
+
+func cmdEnchant(c *Connection, args []string) {
+	if len(args) < 1 || len(args) > 2 {
+		c.sendErrorMsg("Usage: /enchant <enchantment> [level]")
+		return
+	}
+	if c.gameData == nil || c.gameData.Enchantments == nil {
+		c.sendErrorMsg("Enchantment data is not available.")
+		return
+	}
+	ench, ok := c.lookupEnchantment(args[0])
+	if !ok {
+		c.sendErrorMsg(fmt.Sprintf("Unknown enchantment: %s", args[0]))
+		return
+	}
+	level := 1
+	if len(args) == 2 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 || n > ench.MaxLevel {
+			c.sendErrorMsg(fmt.Sprintf("Level must be between 1 and %d for %s.", ench.MaxLevel, ench.DisplayName))
+			return
+		}
+		level = n
+	}
+
+	heldIdx := int16(slotHotbarStart) + c.self.Inventory.GetHeldSlot()
+	held := c.self.Inventory.GetProtocolSlot(int(heldIdx))
+	if held.IsEmpty() {
+		c.sendErrorMsg("You are not holding an item.")
+		return
+	}
+	item, ok := c.gameData.Items.ByID(int(held.BlockID))
+	if !ok || !slices.Contains(item.EnchantCategories, ench.Category) {
+		c.sendErrorMsg(fmt.Sprintf("%s cannot be applied to this item.", ench.DisplayName))
+		return
+	}
+
+	held.NBT = withEnchantment(held.NBT, player.Enchantment{ID: int16(ench.ID), Level: int16(level)})
+	c.setInventorySlot(heldIdx, held)
+	_ = c.sendSetSlot(0, heldIdx, held)
+	c.sendSuccessMsg(fmt.Sprintf("Enchanted %s with %s %d.", item.DisplayName, ench.DisplayName, level))
+}
+
+// lookupEnchantment resolves an enchantment by numeric ID or by name,
+// ignoring case and an optional "minecraft:" prefix.
+func (c *Connection) lookupEnchantment(s string) (gamedata.Enchantment, bool) {
+	if id, err := strconv.Atoi(s); err == nil {
+		return c.gameData.Enchantments.ByID(id)
+	}
+	return c.gameData.Enchantments.ByName(strings.TrimPrefix(strings.ToLower(s), "minecraft:"))
+}
+
+// withEnchantment returns a copy of n with e added, replacing any existing
+// enchantment of the same ID. The original tag is left untouched since slots
+// are copied by value and may share it.
+func withEnchantment(n *player.ItemNBT, e player.Enchantment) *player.ItemNBT {
+	out := &player.ItemNBT{}
+	if n != nil {
+		out.DisplayName = n.DisplayName
+		for _, old := range n.Enchantments {
+			if old.ID != e.ID {
+				out.Enchantments = append(out.Enchantments, old)
+			}
+		}
+	}
+	out.Enchantments = append(out.Enchantments, e)
+	return out
+}
+
+func cmdSetblock(c *Connection, args []string) {
+	if len(args) != 4 {
+		c.sendErrorMsg("Usage: /setblock <x> <y> <z> <block[:meta]>")
+		return
+	}
+	x, y, z, ok := c.parseBlockPos(args[:3])
+	if !ok {
+		return
+	}
+	if maxY := c.cfg.MaxBuildHeight - 1; y < 0 || y > maxY {
+		c.sendErrorMsg(fmt.Sprintf("Y coordinate must be between 0 and %d.", maxY))
+		return
+	}
+	if !c.world.Border().ContainsBlock(x, z) {
+		c.sendErrorMsg("That position is outside the world border.")
+		return
+	}
+	state, ok := c.parseBlockState(args[3])
+	if !ok {
+		return
+	}
+
+	c.replaceBlock(x, y, z, state)
+	c.sendBlockChange(x, y, z, state)
+	c.applyGravity(x, y, z)
+	c.applyGravity(x, y+1, z)
+	c.sendSuccessMsg(fmt.Sprintf("Block placed at %d, %d, %d.", x, y, z))
+}
+
+// replaceBlock sets a block for a command and cleans up after the block it
+// replaces the way breaking it would: a chest or furnace spills its contents,
+// leaves held up by a log may decay, and nearby water flows. The caller sends
+// the block change and applies gravity once the whole edit is in place.
+func (c *Connection) replaceBlock(x, y, z int, state int32) {
+	old := c.world.GetBlock(x, y, z)
+	c.world.SetBlock(x, y, z, state)
+	c.world.ScheduleFlow(x, y, z)
+	if old == state {
+		return
+	}
+	if world.IsLog(old) {
+		c.world.ScheduleLeafDecay(x, y, z)
+	}
+	c.dropContainer(x, y, z, old)
+}
+
+// Limits for /fill: the maximum region size, and the size above which the
+// affected chunks are resent instead of sending block changes.
+const (
+	maxFillVolume    = 32768
+	fillResendVolume = 4096
+)
+
+func cmdFill(c *Connection, args []string) {
+	if len(args) != 7 {
+		c.sendErrorMsg("Usage: /fill <x1> <y1> <z1> <x2> <y2> <z2> <block[:meta]>")
+		return
+	}
+	x1, y1, z1, ok := c.parseBlockPos(args[0:3])
+	if !ok {
+		return
+	}
+	x2, y2, z2, ok := c.parseBlockPos(args[3:6])
+	if !ok {
+		return
+	}
+	volume, ok := c.checkRegion(x1, y1, z1, x2, y2, z2, maxFillVolume)
+	if !ok {
+		return
+	}
+	state, ok := c.parseBlockState(args[6])
+	if !ok {
+		return
+	}
+
+	positions := make([]world.BlockPos, 0, volume)
+	chunks := make(map[gen.ChunkPos]struct{})
+	for y := min(y1, y2); y <= max(y1, y2); y++ {
+		for z := min(z1, z2); z <= max(z1, z2); z++ {
+			for x := min(x1, x2); x <= max(x1, x2); x++ {
+				c.replaceBlock(x, y, z, state)
+				positions = append(positions, world.BlockPos{X: x, Y: y, Z: z})
+				chunks[gen.ChunkPos{X: x >> 4, Z: z >> 4}] = struct{}{}
+			}
+		}
+	}
+
+	if volume > fillResendVolume {
+		c.resendChunks(chunks)
+	} else {
+		c.sendBlockChanges(positions)
+	}
+	// Sand and gravel in the region fall from its floor, and any resting on
+	// top of it fall if the fill left air below them.
+	for z := min(z1, z2); z <= max(z1, z2); z++ {
+		for x := min(x1, x2); x <= max(x1, x2); x++ {
+			c.applyGravity(x, min(y1, y2), z)
+			c.applyGravity(x, max(y1, y2)+1, z)
+		}
+	}
+	c.sendSuccessMsg(fmt.Sprintf("Filled %d blocks.", volume))
+}
+
+// parseBlockPos parses three block coordinates, reporting an invalid one to
+// the player. A "~" prefix makes a coordinate relative to the player.
+func (c *Connection) parseBlockPos(args []string) (x, y, z int, ok bool) {
+	pos := c.self.GetPosition()
+	var coords [3]int
+	for i, base := range [3]float64{pos.X, pos.Y, pos.Z} {
+		if coords[i], ok = parseCoord(args[i], base); !ok {
+			c.sendErrorMsg(fmt.Sprintf("Invalid coordinate: %s", args[i]))
+			return 0, 0, 0, false
+		}
+	}
+	return coords[0], coords[1], coords[2], true
+}
+
+// parseCoord parses an absolute block coordinate or, with a "~" prefix, an
+// offset from the block containing base.
+func parseCoord(s string, base float64) (int, bool) {
+	rel, relative := strings.CutPrefix(s, "~")
+	if !relative {
+		v, err := strconv.Atoi(s)
+		return v, err == nil
+	}
+	off := 0
+	if rel != "" {
+		v, err := strconv.Atoi(rel)
+		if err != nil {
+			return 0, false
+		}
+		off = v
+	}
+	return int(math.Floor(base)) + off, true
+}
+
+// parseBlockState resolves "<block>[:meta]", where block is a name or
+// numeric ID from the game data, to a block state ID. Invalid input is
+// reported to the player.
+func (c *Connection) parseBlockState(s string) (int32, bool) {
+	if c.gameData == nil || c.gameData.Blocks == nil {
+		c.sendErrorMsg("Block data is not available.")
+		return 0, false
+	}
+	name, metaStr, hasMeta := strings.Cut(strings.TrimPrefix(strings.ToLower(s), "minecraft:"), ":")
+
+	var block gamedata.Block
+	var ok bool
+	if id, err := strconv.Atoi(name); err == nil {
+		block, ok = c.gameData.Blocks.ByID(id)
+	} else {
+		block, ok = c.gameData.Blocks.ByName(name)
+	}
+	if !ok {
+		c.sendErrorMsg(fmt.Sprintf("Unknown block: %s", s))
+		return 0, false
+	}
+
+	meta := 0
+	if hasMeta {
+		m, err := strconv.Atoi(metaStr)
+		if err != nil || m < 0 || m > 15 {
+			c.sendErrorMsg(fmt.Sprintf("Block metadata must be between 0 and 15: %s", s))
+			return 0, false
+		}
+		meta = m
+	}
+	return int32(block.ID)<<4 | int32(meta), true
+}
+
+// sendBlockChange sends a BlockChange to every player in view of the block.
+func (c *Connection) sendBlockChange(x, y, z int, state int32) {
+	c.broadcastInView(x>>4, z>>4, &pkt.BlockChange{Location: mcnet.EncodePosition(x, y, z), Type: state})
+}
+
+// maxSchematicVolume caps the number of blocks in an exported or imported
+// schematic.
+const maxSchematicVolume = 32768
+
+func cmdExport(c *Connection, args []string) {
+	if len(args) != 7 {
+		c.sendErrorMsg("Usage: /export <x1> <y1> <z1> <x2> <y2> <z2> <name>")
+		return
+	}
+	if c.storage == nil {
+		c.sendErrorMsg("Schematics are not available.")
+		return
+	}
+	var coords [6]int
+	for i := range coords {
+		v, err := strconv.Atoi(args[i])
+		if err != nil {
+			c.sendErrorMsg(fmt.Sprintf("Invalid coordinate: %s", args[i]))
+			return
+		}
+		coords[i] = v
+	}
+	x1, y1, z1, x2, y2, z2 := coords[0], coords[1], coords[2], coords[3], coords[4], coords[5]
+	if _, ok := c.checkRegion(x1, y1, z1, x2, y2, z2, maxSchematicVolume); !ok {
+		return
+	}
+
+	sch := c.world.ExportSchematic(x1, y1, z1, x2, y2, z2)
+	name := args[6]
+	if err := c.storage.SaveSchematic(name, sch); err != nil {
+		if errors.Is(err, storage.ErrInvalidSchematicName) {
+			c.sendErrorMsg(err.Error())
+			return
+		}
+		c.log.Error("save schematic", "name", name, "error", err)
+		c.sendErrorMsg("Failed to save the schematic.")
+		return
+	}
+	c.sendSuccessMsg(fmt.Sprintf("Exported %dx%dx%d region as %s.", sch.Width, sch.Height, sch.Length, name))
+}
+
+func cmdImport(c *Connection, args []string) {
+	if len(args) != 1 {
+		c.sendErrorMsg("Usage: /import <name>")
+		return
+	}
+	if c.storage == nil {
+		c.sendErrorMsg("Schematics are not available.")
+		return
+	}
+	name := args[0]
+	sch, err := c.storage.LoadSchematic(name)
+	switch {
+	case errors.Is(err, storage.ErrInvalidSchematicName):
+		c.sendErrorMsg(err.Error())
+		return
+	case os.IsNotExist(err):
+		c.sendErrorMsg(fmt.Sprintf("Schematic %q not found.", name))
+		return
+	case err != nil:
+		c.log.Error("load schematic", "name", name, "error", err)
+		c.sendErrorMsg("Failed to load the schematic.")
+		return
+	}
+	if sch.Volume() > maxSchematicVolume {
+		c.sendErrorMsg(fmt.Sprintf("Schematic has %d blocks; the limit is %d.", sch.Volume(), maxSchematicVolume))
+		return
+	}
+
+	pos := c.self.GetPosition()
+	changed := c.world.PasteSchematic(sch, int(math.Floor(pos.X)), int(math.Floor(pos.Y)), int(math.Floor(pos.Z)), c.cfg.MaxBuildHeight)
+	c.sendBlockChanges(changed)
+	c.sendSuccessMsg(fmt.Sprintf("Pasted %s (%d blocks changed).", name, len(changed)))
+}
+
+// checkRegion validates the cuboid between two corners for a region
+// command and returns its volume. Both corners must be inside the world
+// border and the build height, and the region may hold at most limit
+// blocks; problems are reported to the player. The extents are checked one
+// at a time so that the volume cannot overflow.
+func (c *Connection) checkRegion(x1, y1, z1, x2, y2, z2, limit int) (int, bool) {
+	maxY := c.cfg.MaxBuildHeight - 1
+	if min(y1, y2) < 0 || max(y1, y2) > maxY {
+		c.sendErrorMsg(fmt.Sprintf("Y coordinates must be between 0 and %d.", maxY))
+		return 0, false
+	}
+	border := c.world.Border()
+	if !border.ContainsBlock(x1, z1) || !border.ContainsBlock(x2, z2) {
+		c.sendErrorMsg("The region must be inside the world border.")
+		return 0, false
+	}
+	volume := 1
+	for _, extent := range [3]int{gen.Abs(x2-x1) + 1, gen.Abs(y2-y1) + 1, gen.Abs(z2-z1) + 1} {
+		if extent > limit || volume*extent > limit {
+			c.sendErrorMsg(fmt.Sprintf("Region is too large; the limit is %d blocks.", limit))
+			return 0, false
+		}
+		volume *= extent
+	}
+	return volume, true
+}
+
+// sendBlockChanges sends the current state of the given blocks, one
+// MultiBlockChange per chunk, to every player in view of that chunk.
+func (c *Connection) sendBlockChanges(positions []world.BlockPos) {
+	byChunk := make(map[gen.ChunkPos][]world.BlockPos)
+	for _, p := range positions {
+		cp := gen.ChunkPos{X: p.X >> 4, Z: p.Z >> 4}
+		byChunk[cp] = append(byChunk[cp], p)
+	}
+
+	for cp, blocks := range byChunk {
+		var buf bytes.Buffer
+		_ = binary.Write(&buf, binary.BigEndian, int32(cp.X))
+		_ = binary.Write(&buf, binary.BigEndian, int32(cp.Z))
+		_, _ = mcnet.WriteVarInt(&buf, int32(len(blocks)))
+		for _, b := range blocks {
+			buf.WriteByte(byte((b.X&0xF)<<4 | b.Z&0xF))
+			buf.WriteByte(byte(b.Y))
+			_, _ = mcnet.WriteVarInt(&buf, c.world.GetBlock(b.X, b.Y, b.Z))
+		}
+		change := &pkt.MultiBlockChange{Data: buf.Bytes()}
+		c.players.ForEach(func(p *player.Player) {
+			if player.InViewDistance(cp.X, cp.Z, p.ChunkX(), p.ChunkZ(), c.cfg.ViewDistance) {
+				_ = p.WritePacket(change)
+			}
+		})
+	}
+}
+
+// accessListMu serializes whitelist and ban list updates so concurrent
+// commands do not lose each other's edits.
+var accessListMu sync.Mutex
+
+func cmdWhitelist(c *Connection, args []string) {
+	if len(args) == 0 {
+		c.sendErrorMsg("Usage: /whitelist <add|remove> <player> | /whitelist list")
+		return
+	}
+	if c.storage == nil {
+		c.sendErrorMsg("The whitelist is not available.")
+		return
+	}
+
+	accessListMu.Lock()
+	defer accessListMu.Unlock()
+
+	names, err := c.storage.LoadWhitelist()
+	if err != nil {
+		c.log.Error("load whitelist", "error", err)
+		c.sendErrorMsg("Failed to read the whitelist.")
+		return
+	}
+
+	switch sub := strings.ToLower(args[0]); {
+	case sub == "list" && len(args) == 1:
+		list := make([]string, 0, len(names))
+		for name := range names {
+			list = append(list, name)
+		}
+		slices.Sort(list)
+		c.sendSuccessMsg(fmt.Sprintf("Whitelisted players (%d): %s", len(list), strings.Join(list, ", ")))
+
+	case (sub == "add" || sub == "remove") && len(args) == 2:
+		name := strings.ToLower(args[1])
+		if sub == "add" {
+			if names[name] {
+				c.sendErrorMsg(fmt.Sprintf("%s is already whitelisted.", args[1]))
+				return
+			}
+			names[name] = true
+		} else {
+			if !names[name] {
+				c.sendErrorMsg(fmt.Sprintf("%s is not whitelisted.", args[1]))
+				return
+			}
+			delete(names, name)
+		}
+		if err := c.storage.SaveWhitelist(names); err != nil {
+			c.log.Error("save whitelist", "error", err)
+			c.sendErrorMsg("Failed to save the whitelist.")
+			return
+		}
+		if sub == "add" {
+			c.sendSuccessMsg(fmt.Sprintf("Added %s to the whitelist.", args[1]))
+		} else {
+			c.sendSuccessMsg(fmt.Sprintf("Removed %s from the whitelist.", args[1]))
+		}
+
+	default:
+		c.sendErrorMsg("Usage: /whitelist <add|remove> <player> | /whitelist list")
+	}
+}
+
+func cmdOp(c *Connection, args []string) {
+	if len(args) != 1 {
+		c.sendErrorMsg("Usage: /op <player>")
+		return
+	}
+	if c.storage == nil {
+		c.sendErrorMsg("The operator list is not available.")
+		return
+	}
+	target := c.players.GetByName(args[0])
+	if target == nil {
+		c.sendErrorMsg(fmt.Sprintf("Player %q not found.", args[0]))
+		return
+	}
+
+	accessListMu.Lock()
+	ops, err := c.storage.LoadOps()
+	if err == nil {
+		if storage.IsOp(ops, target.UUID) {
+			accessListMu.Unlock()
+			c.sendErrorMsg(fmt.Sprintf("%s is already an operator.", target.Username))
+			return
+		}
+		err = c.storage.SaveOps(append(ops, storage.OpEntry{UUID: target.UUID, Name: target.Username}))
+	}
+	accessListMu.Unlock()
+	if err != nil {
+		c.log.Error("update ops", "error", err)
+		c.sendErrorMsg("Failed to update the operator list.")
+		return
+	}
+
+	if target != c.self {
+		_ = target.WritePacket(&pkt.ChatCB{Message: chat.Colored("You are now an operator.", chat.Yellow).String(), Position: 1})
+	}
+	c.sendSuccessMsg(fmt.Sprintf("Made %s an operator.", target.Username))
+}
+
+func cmdDeop(c *Connection, args []string) {
+	if len(args) != 1 {
+		c.sendErrorMsg("Usage: /deop <player>")
+		return
+	}
+	if c.storage == nil {
+		c.sendErrorMsg("The operator list is not available.")
+		return
+	}
+
+	accessListMu.Lock()
+	ops, err := c.storage.LoadOps()
+	i := -1
+	if err == nil {
+		if i = storage.FindOp(ops, args[0]); i >= 0 {
+			err = c.storage.SaveOps(slices.Delete(ops, i, i+1))
+		}
+	}
+	accessListMu.Unlock()
+	if err != nil {
+		c.log.Error("update ops", "error", err)
+		c.sendErrorMsg("Failed to update the operator list.")
+		return
+	}
+	if i < 0 {
+		c.sendErrorMsg(fmt.Sprintf("%s is not an operator.", args[0]))
+		return
+	}
+	c.sendSuccessMsg(fmt.Sprintf("%s is no longer an operator.", args[0]))
+}
+
+func cmdBan(c *Connection, args []string) {
+	if len(args) < 1 {
+		c.sendErrorMsg("Usage: /ban <player> [reason]")
+		return
+	}
+	if c.storage == nil {
+		c.sendErrorMsg("The ban list is not available.")
+		return
+	}
+
+	entry := storage.BanEntry{
+		Name:    args[0],
+		Reason:  strings.Join(args[1:], " "),
+		Created: time.Now().UTC().Truncate(time.Second),
+	}
+	target := c.players.GetByName(args[0])
+	if target != nil {
+		entry.UUID = target.UUID
+		entry.Name = target.Username
+	}
+
+	accessListMu.Lock()
+	bans, err := c.storage.LoadBans()
+	if err == nil {
+		if _, ok := storage.FindBan(bans, entry.UUID, entry.Name); ok {
+			accessListMu.Unlock()
+			c.sendErrorMsg(fmt.Sprintf("%s is already banned.", entry.Name))
+			return
+		}
+		err = c.storage.SaveBans(append(bans, entry))
+	}
+	accessListMu.Unlock()
+	if err != nil {
+		c.log.Error("update ban list", "error", err)
+		c.sendErrorMsg("Failed to update the ban list.")
+		return
+	}
+
+	if target != nil && target.Disconnect != nil {
+		target.Disconnect(c.banMessage(entry))
+	}
+	c.sendSuccessMsg(fmt.Sprintf("Banned %s.", entry.Name))
+}
+
+func cmdPardon(c *Connection, args []string) {
+	if len(args) != 1 {
+		c.sendErrorMsg("Usage: /pardon <player>")
+		return
+	}
+	if c.storage == nil {
+		c.sendErrorMsg("The ban list is not available.")
+		return
+	}
+
+	accessListMu.Lock()
+	defer accessListMu.Unlock()
+
+	bans, err := c.storage.LoadBans()
+	if err != nil {
+		c.log.Error("load ban list", "error", err)
+		c.sendErrorMsg("Failed to read the ban list.")
+		return
+	}
+	kept := slices.DeleteFunc(bans, func(b storage.BanEntry) bool {
+		return strings.EqualFold(b.Name, args[0])
+	})
+	if len(kept) == len(bans) {
+		c.sendErrorMsg(fmt.Sprintf("%s is not banned.", args[0]))
+		return
+	}
+	if err := c.storage.SaveBans(kept); err != nil {
+		c.log.Error("save ban list", "error", err)
+		c.sendErrorMsg("Failed to save the ban list.")
+		return
+	}
+	c.sendSuccessMsg(fmt.Sprintf("Unbanned %s.", args[0]))
+}
+
+func cmdBanlist(c *Connection, _ []string) {
+	if c.storage == nil {
+		c.sendErrorMsg("The ban list is not available.")
+		return
+	}
+	bans, err := c.storage.LoadBans()
+	if err != nil {
+		c.log.Error("load ban list", "error", err)
+		c.sendErrorMsg("Failed to read the ban list.")
+		return
+	}
+
+	c.sendSystemMsg(fmt.Sprintf("Banned players (%d):", len(bans)), "yellow")
+	for _, b := range bans {
+		line := fmt.Sprintf("%s (since %s)", b.Name, b.Created.Format(time.DateOnly))
+		if b.Reason != "" {
+			line += ": " + b.Reason
+		}
+		c.sendSystemMsg(line, "yellow")
+	}
+}
@@ -2,16 +2,25 @@ package conn
 
 import (
 	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"math/rand"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
 
 	"github.com/go-theft-craft/server/internal/server/config"
+	"github.com/go-theft-craft/server/internal/server/packet"
 	"github.com/go-theft-craft/server/internal/server/player"
+	"github.com/go-theft-craft/server/internal/server/storage"
 	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
 	mcnet "github.com/go-theft-craft/server/pkg/protocol"
 	"github.com/go-theft-craft/server/pkg/world"
 	"github.com/go-theft-craft/server/pkg/world/gen"
+	"github.com/go-theft-craft/server/pkg/world/nbt"
 )
 
 // packetRecorder captures packets written via mcnet.WritePacket.
@@ -56,7 +65,8 @@ func (s *sentPackets) reset() {
 }
 
 // newTestConn creates a minimal Connection suitable for testing commands.
-// The returned sentPackets captures packets sent to the connection's player.
+// The returned sentPackets captures packets sent to the connection's player,
+// who is an operator through the config.
 func newTestConn(username string) (*Connection, *sentPackets, *player.Manager) {
 	m := player.NewManager(8)
 	sp := &sentPackets{}
@@ -68,19 +78,26 @@ func newTestConn(username string) (*Connection, *sentPackets, *player.Manager) {
 
 	w := world.NewWorld(gen.NewFlatGenerator(0))
 	rec := &packetRecorder{}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cfg := config.DefaultConfig()
+	cfg.Ops = []string{username}
 
 	c := &Connection{
+		ctx:            ctx,
+		cancel:         cancel,
 		rw:             rec,
-		cfg:            config.DefaultConfig(),
+		cfg:            cfg,
+		log:            slog.New(slog.NewTextHandler(io.Discard, nil)),
 		self:           p,
 		players:        m,
 		world:          w,
 		loadedChunks:   make(map[gen.ChunkPos]struct{}),
 		keepAliveAcked: true,
 		cursorSlot:     player.EmptySlot,
-		craftingOutput: player.EmptySlot,
-		craftingGrid:   [4]player.Slot{player.EmptySlot, player.EmptySlot, player.EmptySlot, player.EmptySlot},
+		DropRNG:        rand.New(rand.NewSource(1)),
 	}
+	c.openWindow(0, windowTypePlayer)
 	return c, sp, m
 }
 
@@ -179,6 +196,40 @@ func TestCmdTp_Player(t *testing.T) {
 	}
 }
 
+func TestCmdTp_DismountsRider(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.self.Mount(99) // riding a boat
+	rec := c.rw.(*packetRecorder)
+	rec.buf.Reset()
+
+	c.handleCommand("/tp 100 10 100")
+
+	if _, riding := c.self.Vehicle(); riding {
+		t.Error("expected player to be dismounted after teleport")
+	}
+	pos := c.self.GetPosition()
+	if pos.X != 100 || pos.Y != 10 || pos.Z != 100 {
+		t.Errorf("expected position 100,10,100, got %.1f,%.1f,%.1f", pos.X, pos.Y, pos.Z)
+	}
+
+	var detached bool
+	for _, p := range recordedPackets(t, c) {
+		if p.id != (&pkt.AttachEntity{}).PacketID() {
+			continue
+		}
+		var ae pkt.AttachEntity
+		if err := mcnet.Unmarshal(p.data, &ae); err != nil {
+			t.Fatalf("unmarshal attach entity: %v", err)
+		}
+		if ae.EntityID == c.self.EntityID && ae.VehicleID == -1 {
+			detached = true
+		}
+	}
+	if !detached {
+		t.Error("expected AttachEntity detach packet")
+	}
+}
+
 func TestCmdTp_PlayerNotFound(t *testing.T) {
 	c, _, _ := newTestConn("Alice")
 	rec := c.rw.(*packetRecorder)
@@ -216,6 +267,83 @@ func TestCmdGamemode(t *testing.T) {
 	}
 }
 
+func TestCmdGamemode_OtherPlayer(t *testing.T) {
+	c, sp, m := newTestConn("Alice")
+	c.self.SetGameMode(packet.GameModeCreative)
+
+	sp2 := &sentPackets{}
+	eid2 := m.AllocateEntityID()
+	uuid2 := [16]byte{byte(eid2)}
+	bob := player.NewPlayer(eid2, "test-uuid-2", uuid2, "Bob", nil, sp2.write)
+	bob.SetGameMode(packet.GameModeCreative)
+	m.Add(bob)
+	sp.reset()
+	sp2.reset()
+
+	c.handleCommand("/gamemode survival Bob")
+
+	if got := bob.GetGameMode(); got != packet.GameModeSurvival {
+		t.Fatalf("Bob's game mode = %d, want survival", got)
+	}
+	if got := c.self.GetGameMode(); got != packet.GameModeCreative {
+		t.Error("Alice's game mode should be unchanged")
+	}
+
+	// Bob receives the game state change directly.
+	var gotStateChange bool
+	for _, p := range sp2.get() {
+		if gs, ok := p.(*pkt.GameStateChange); ok && gs.Reason == 3 && gs.GameMode == float32(packet.GameModeSurvival) {
+			gotStateChange = true
+		}
+	}
+	if !gotStateChange {
+		t.Error("Bob did not receive GameStateChange")
+	}
+
+	// Everyone receives the PlayerInfo update-gamemode action for Bob.
+	var gotInfo bool
+	for _, p := range sp.get() {
+		if info, ok := p.(*pkt.PlayerInfo); ok && len(info.Data) > 0 && info.Data[0] == 1 && bytes.Contains(info.Data, uuid2[:]) {
+			gotInfo = true
+		}
+	}
+	if !gotInfo {
+		t.Error("Alice did not receive PlayerInfo update-gamemode for Bob")
+	}
+
+	// The change is persisted with Bob's player data.
+	store, err := storage.New(t.TempDir(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	if err := store.SavePlayer(bob); err != nil {
+		t.Fatalf("SavePlayer: %v", err)
+	}
+	data, err := store.LoadPlayer(bob.UUID)
+	if err != nil || data == nil {
+		t.Fatalf("LoadPlayer: %v, %v", data, err)
+	}
+	if data.GameMode != packet.GameModeSurvival {
+		t.Errorf("saved game mode = %d, want survival", data.GameMode)
+	}
+}
+
+func TestCmdGamemode_UnknownPlayer(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	before := c.self.GetGameMode()
+	rec := c.rw.(*packetRecorder)
+	rec.buf.Reset()
+
+	c.handleCommand("/gamemode creative NoOne")
+
+	if c.self.GetGameMode() != before {
+		t.Error("game mode should be unchanged for unknown target")
+	}
+	if rec.buf.Len() == 0 {
+		t.Error("expected error message for missing player")
+	}
+}
+
 func TestCmdGamemode_Invalid(t *testing.T) {
 	c, _, _ := newTestConn("Alice")
 	rec := c.rw.(*packetRecorder)
@@ -273,6 +401,209 @@ func TestCmdTime(t *testing.T) {
 	}
 }
 
+func TestCmdWeather_BroadcastsRain(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	sp.reset()
+
+	c.handleCommand("/weather thunder 60")
+
+	if got := c.world.Weather(); got != world.WeatherThunder {
+		t.Fatalf("weather = %v, want thunder", got)
+	}
+	levels := map[uint8]float32{}
+	for _, p := range sp.get() {
+		if gs, ok := p.(*pkt.GameStateChange); ok {
+			levels[gs.Reason] = gs.GameMode
+		}
+	}
+	if _, ok := levels[packet.GameStateBeginRain]; !ok {
+		t.Error("expected a begin-rain GameStateChange")
+	}
+	if levels[packet.GameStateRainLevel] != 1 || levels[packet.GameStateThunderLevel] != 1 {
+		t.Errorf("rain level %v, thunder level %v, want 1 and 1",
+			levels[packet.GameStateRainLevel], levels[packet.GameStateThunderLevel])
+	}
+
+	sp.reset()
+	c.handleCommand("/weather clear")
+	var ended bool
+	for _, p := range sp.get() {
+		if gs, ok := p.(*pkt.GameStateChange); ok && gs.Reason == packet.GameStateEndRain {
+			ended = true
+		}
+	}
+	if !ended {
+		t.Error("expected an end-rain GameStateChange")
+	}
+}
+
+func TestCmdWeather_InvalidArgs(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	sp.reset()
+
+	for _, cmd := range []string{"/weather", "/weather snow", "/weather rain 0", "/weather rain soon"} {
+		c.handleCommand(cmd)
+	}
+
+	if got := c.world.Weather(); got != world.WeatherClear {
+		t.Errorf("weather = %v after invalid commands, want clear", got)
+	}
+	if len(sp.get()) != 0 {
+		t.Errorf("invalid commands broadcast %d packets", len(sp.get()))
+	}
+}
+
+// recordedChat returns the text of every chat message written to c.
+func recordedChat(t *testing.T, c *Connection) []string {
+	t.Helper()
+	var out []string
+	for _, p := range recordedPackets(t, c) {
+		if p.id != (&pkt.ChatCB{}).PacketID() {
+			continue
+		}
+		var chat pkt.ChatCB
+		if err := mcnet.Unmarshal(p.data, &chat); err != nil {
+			t.Fatalf("unmarshal chat: %v", err)
+		}
+		out = append(out, chat.Message)
+	}
+	return out
+}
+
+func TestCmdTime_Query(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.world.SetTime(123456, 13000)
+
+	tests := []struct {
+		cmd, want string
+	}{
+		{"/time query daytime", "The time is 13000."},
+		{"/time query gametime", "The time is 123456."},
+	}
+	for _, tt := range tests {
+		c.rw.(*packetRecorder).buf.Reset()
+		c.handleCommand(tt.cmd)
+		chat := recordedChat(t, c)
+		if len(chat) != 1 || !strings.Contains(chat[0], tt.want) {
+			t.Errorf("%s: chat = %q, want %q", tt.cmd, chat, tt.want)
+		}
+	}
+}
+
+func TestCmdTime_AddWrapsAround(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	c.world.SetTimeOfDay(23000)
+	sp.reset()
+
+	c.handleCommand("/time add 2000")
+
+	if _, tod := c.world.GetTime(); tod != 1000 {
+		t.Errorf("timeOfDay = %d, want 1000", tod)
+	}
+	var sent bool
+	for _, p := range sp.get() {
+		if ut, ok := p.(*pkt.UpdateTime); ok && ut.Time == 1000 {
+			sent = true
+		}
+	}
+	if !sent {
+		t.Error("expected UpdateTime broadcast with the new time")
+	}
+
+	c.handleCommand("/time add -3000")
+	if _, tod := c.world.GetTime(); tod != 22000 {
+		t.Errorf("timeOfDay after subtracting = %d, want 22000", tod)
+	}
+}
+
+// addTestPlayer adds a player to m whose packets are returned.
+func addTestPlayer(m *player.Manager, name string) (*player.Player, *sentPackets) {
+	sp := &sentPackets{}
+	eid := m.AllocateEntityID()
+	p := player.NewPlayer(eid, "uuid-"+name, [16]byte{byte(eid)}, name, nil, sp.write)
+	p.SetPosition(0.5, 4, 0.5, 0, 0, true)
+	m.Add(p)
+	sp.reset()
+	return p, sp
+}
+
+// whispers returns the private messages among sent packets.
+func whispers(sp *sentPackets) []string {
+	var out []string
+	for _, p := range sp.get() {
+		if chat, ok := p.(*pkt.ChatCB); ok && strings.Contains(chat.Message, "commands.message.display") {
+			out = append(out, chat.Message)
+		}
+	}
+	return out
+}
+
+func TestCmdMsg_OnlyReachesTarget(t *testing.T) {
+	c, sp, m := newTestConn("Alice")
+	_, bob := addTestPlayer(m, "Bob")
+	_, carol := addTestPlayer(m, "Carol")
+	sp.reset()
+	c.rw.(*packetRecorder).buf.Reset()
+
+	c.handleCommand("/msg bob meet at spawn")
+
+	got := whispers(bob)
+	if len(got) != 1 || !strings.Contains(got[0], "incoming") || !strings.Contains(got[0], `"Alice"`) ||
+		!strings.Contains(got[0], "meet at spawn") {
+		t.Errorf("Bob's whispers = %q, want one incoming from Alice", got)
+	}
+	if n := len(whispers(carol)); n != 0 {
+		t.Errorf("Carol received %d whispers, want 0", n)
+	}
+	if n := len(whispers(sp)); n != 0 {
+		t.Errorf("Alice's broadcast stream received %d whispers, want 0", n)
+	}
+	echo := recordedChat(t, c)
+	if len(echo) != 1 || !strings.Contains(echo[0], "outgoing") || !strings.Contains(echo[0], `"Bob"`) {
+		t.Errorf("Alice's echo = %q, want one outgoing to Bob", echo)
+	}
+}
+
+func TestCmdMsg_UnknownTarget(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.rw.(*packetRecorder).buf.Reset()
+
+	c.handleCommand("/msg nobody hello")
+
+	chat := recordedChat(t, c)
+	if len(chat) != 1 || !strings.Contains(chat[0], `"red"`) {
+		t.Errorf("chat = %q, want a red error", chat)
+	}
+}
+
+func TestCmdReply_RoutesToLastCorrespondent(t *testing.T) {
+	c, _, m := newTestConn("Alice")
+	bobP, bob := addTestPlayer(m, "Bob")
+	_, carol := addTestPlayer(m, "Carol")
+
+	c.handleCommand("/msg Bob hi")
+	if got := bobP.LastMessaged(); got != "Alice" {
+		t.Errorf("Bob's last correspondent = %q, want Alice", got)
+	}
+	bob.reset()
+
+	c.handleCommand("/r still there?")
+	if n := len(whispers(bob)); n != 1 {
+		t.Errorf("Bob received %d replies, want 1", n)
+	}
+
+	// Carol whispers Alice, so Alice's next reply goes to her.
+	c.self.SetLastMessaged("Carol")
+	bob.reset()
+	c.handleCommand("/reply hey Carol")
+	if n := len(whispers(carol)); n != 1 {
+		t.Errorf("Carol received %d replies, want 1", n)
+	}
+	if n := len(whispers(bob)); n != 0 {
+		t.Errorf("Bob received %d replies meant for Carol", n)
+	}
+}
+
 func TestCmdKill(t *testing.T) {
 	c, _, _ := newTestConn("Alice")
 	rec := c.rw.(*packetRecorder)
@@ -363,3 +694,1461 @@ func TestCmdTime_BadUsage(t *testing.T) {
 		t.Error("expected error for bad /time usage")
 	}
 }
+
+func TestHandleCommand_RejectsOverlongInput(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	sp.reset()
+
+	if !c.handleCommand("/say " + strings.Repeat("x", maxCommandLength)) {
+		t.Fatal("expected over-long command to be treated as a command")
+	}
+
+	for _, p := range sp.get() {
+		if _, ok := p.(*pkt.ChatCB); ok {
+			t.Fatal("over-long /say should be rejected before dispatch")
+		}
+	}
+}
+
+func TestHandleCommand_RejectsOverPerCommandLimit(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	before := c.self.GetPosition()
+
+	c.handleCommand("/tp " + strings.Repeat("9", 120) + " 10 10")
+
+	if after := c.self.GetPosition(); after != before {
+		t.Errorf("over-long /tp should not teleport, moved to %+v", after)
+	}
+}
+
+func TestHandleCommand_NormalLengthUnaffected(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	sp.reset()
+
+	c.handleCommand("/say " + strings.Repeat("x", 80))
+
+	var found bool
+	for _, p := range sp.get() {
+		if _, ok := p.(*pkt.ChatCB); ok {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected normal-length /say to broadcast")
+	}
+}
+
+func TestCmdSetbiome_ResendsChunk(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	sp.reset()
+
+	c.handleCommand("/setbiome desert 1")
+
+	for x := -1; x <= 1; x++ {
+		for z := -1; z <= 1; z++ {
+			if got := c.world.GetBiome(x, z); got != 2 {
+				t.Errorf("GetBiome(%d, %d) = %d, want desert (2)", x, z, got)
+			}
+		}
+	}
+	// Columns -1..1 span four chunks around the origin.
+	var chunks int
+	for _, p := range sp.get() {
+		if _, ok := p.(*pkt.MapChunk); ok {
+			chunks++
+		}
+	}
+	if chunks != 4 {
+		t.Errorf("resent %d chunks, want 4", chunks)
+	}
+}
+
+func TestCmdSetbiome_UnknownBiome(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	before := c.world.GetBiome(0, 0)
+
+	c.handleCommand("/setbiome narnia")
+
+	if got := c.world.GetBiome(0, 0); got != before {
+		t.Errorf("biome changed to %d for an unknown name", got)
+	}
+}
+
+func TestCmdKick(t *testing.T) {
+	c, _, m := newTestConn("Alice")
+	eid2 := m.AllocateEntityID()
+	sp2 := &sentPackets{}
+	bob := player.NewPlayer(eid2, "bob-uuid", [16]byte{byte(eid2)}, "Bob", nil, sp2.write)
+	var kicked []string
+	bob.Disconnect = func(reason string) { kicked = append(kicked, reason) }
+	m.Add(bob)
+	rec := c.rw.(*packetRecorder)
+	rec.buf.Reset()
+
+	c.handleCommand("/kick Bob stop griefing")
+
+	if len(kicked) != 1 || kicked[0] != "stop griefing" {
+		t.Fatalf("Disconnect calls = %q, want [\"stop griefing\"]", kicked)
+	}
+	if !strings.Contains(rec.buf.String(), "Kicked Bob") {
+		t.Errorf("expected a success message, got %q", rec.buf.String())
+	}
+	if c.ctx.Err() != nil {
+		t.Error("the kicking player should stay connected")
+	}
+}
+
+func TestCmdKick_UnknownPlayer(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	rec := c.rw.(*packetRecorder)
+	rec.buf.Reset()
+
+	c.handleCommand("/kick NoOne")
+
+	if out := rec.buf.String(); !strings.Contains(out, "not found") {
+		t.Errorf("expected a not-found error, got %q", out)
+	}
+}
+
+func TestCmdWhois(t *testing.T) {
+	c, _, m := newTestConn("Alice")
+	eid2 := m.AllocateEntityID()
+	uuid2 := [16]byte{byte(eid2)}
+	sp2 := &sentPackets{}
+	bob := player.NewPlayer(eid2, "bob-uuid-1234", uuid2, "Bob", nil, sp2.write)
+	bob.RemoteAddr = "203.0.113.7:51234"
+	bob.SetGameMode(packet.GameModeSurvival)
+	m.Add(bob)
+	rec := c.rw.(*packetRecorder)
+	rec.buf.Reset()
+
+	c.handleCommand("/whois Bob")
+
+	out := rec.buf.String()
+	for _, want := range []string{"bob-uuid-1234", "survival", "203.0.113.7"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("whois output missing %q", want)
+		}
+	}
+}
+
+func TestCmdWhois_UnknownPlayer(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	rec := c.rw.(*packetRecorder)
+	rec.buf.Reset()
+
+	c.handleCommand("/whois NoOne")
+
+	out := rec.buf.String()
+	if !strings.Contains(out, "not found") || !strings.Contains(out, "red") {
+		t.Errorf("expected a red not-found error, got %q", out)
+	}
+}
+
+func TestCmdRegenerate_RequiresConfirmForEdits(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.world.SetBlock(2, 10, 2, 4<<4)
+
+	c.handleCommand("/regenerate")
+	if got := c.world.GetBlock(2, 10, 2); got != 4<<4 {
+		t.Fatalf("edited block removed without confirmation: %d", got)
+	}
+
+	c.handleCommand("/regenerate 0 confirm")
+	if got := c.world.GetBlock(2, 10, 2); got != 0 {
+		t.Errorf("GetBlock after regenerate = %d, want 0", got)
+	}
+	if n := len(c.world.OverridesForChunk(0, 0)); n != 0 {
+		t.Errorf("chunk still has %d overrides", n)
+	}
+}
+
+func TestCmdRegenerate_ResendsChunks(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	sp.reset()
+
+	c.handleCommand("/regenerate 1")
+
+	var chunks int
+	for _, p := range sp.get() {
+		if _, ok := p.(*pkt.MapChunk); ok {
+			chunks++
+		}
+	}
+	if chunks != 9 {
+		t.Errorf("resent %d chunks, want 9", chunks)
+	}
+}
+
+// clearInventory empties every main and hotbar slot of p.
+func clearInventory(p *player.Player) {
+	for i := 0; i < 36; i++ {
+		p.Inventory.SetSlot(i, player.EmptySlot)
+	}
+}
+
+func TestCmdGive_SelfByName(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	clearInventory(c.self)
+
+	c.handleCommand("/give @s stone 10")
+
+	if got := c.self.Inventory.GetSlot(0); got != (player.Slot{BlockID: 1, ItemCount: 10}) {
+		t.Errorf("hotbar 0 = %+v, want 10 stone", got)
+	}
+	if countPackets(t, c, 0x30) == 0 {
+		t.Error("expected WindowItems resync for the caller")
+	}
+}
+
+func TestCmdGive_ClampsToStackSize(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	clearInventory(c.self)
+
+	c.handleCommand("/give Alice ender_pearl 40")
+
+	if got := c.self.Inventory.GetSlot(0); got.BlockID != 368 || got.ItemCount != 16 {
+		t.Errorf("slot 0 = %+v, want 16 ender pearls", got)
+	}
+	if got := c.self.Inventory.GetSlot(1); !got.IsEmpty() {
+		t.Errorf("slot 1 = %+v, want empty", got)
+	}
+}
+
+func TestCmdGive_ByIDWithDamageToOtherPlayer(t *testing.T) {
+	c, _, m := newTestConn("Alice")
+	c.gameData = pkt.New()
+	eid2 := m.AllocateEntityID()
+	sp2 := &sentPackets{}
+	bob := player.NewPlayer(eid2, "test-uuid-2", [16]byte{byte(eid2)}, "Bob", nil, sp2.write)
+	clearInventory(bob)
+	m.Add(bob)
+	sp2.reset()
+
+	c.handleCommand("/give Bob 35 3 14") // red wool
+
+	if got := bob.Inventory.GetSlot(0); got != (player.Slot{BlockID: 35, ItemCount: 3, ItemDamage: 14}) {
+		t.Errorf("Bob's hotbar 0 = %+v, want 3 red wool", got)
+	}
+	var synced bool
+	for _, p := range sp2.get() {
+		if _, ok := p.(*pkt.SetSlot); ok {
+			synced = true
+		}
+	}
+	if !synced {
+		t.Error("Bob did not receive a SetSlot for the given item")
+	}
+}
+
+func TestCmdGive_UnknownItem(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	before := c.self.Inventory.ToProtocolSlots()
+	rec := c.rw.(*packetRecorder)
+	rec.buf.Reset()
+
+	c.handleCommand("/give @s unobtainium")
+
+	if c.self.Inventory.ToProtocolSlots() != before {
+		t.Error("inventory changed for an unknown item")
+	}
+	if out := rec.buf.String(); !strings.Contains(out, "Unknown item") || !strings.Contains(out, "red") {
+		t.Errorf("expected a red unknown-item error, got %q", out)
+	}
+}
+
+func TestCmdExportImport_RoundTrip(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	store, err := storage.New(t.TempDir(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	c.storage = store
+	c.world.SetBlock(1, 10, 1, 1<<4)
+	c.world.SetBlock(2, 11, 2, 35<<4|3)
+	c.world.SetBlock(3, 12, 1, 4<<4)
+
+	c.handleCommand("/export 1 10 1 3 12 2 tower")
+	if _, err := store.LoadSchematic("tower"); err != nil {
+		t.Fatalf("schematic not saved: %v", err)
+	}
+
+	sp.reset()
+	c.self.SetPosition(40.5, 30, 40.5, 0, 0, true)
+	c.handleCommand("/import tower")
+
+	for y := 0; y < 3; y++ {
+		for z := 0; z < 2; z++ {
+			for x := 0; x < 3; x++ {
+				want := c.world.GetBlock(1+x, 10+y, 1+z)
+				if got := c.world.GetBlock(40+x, 30+y, 40+z); got != want {
+					t.Errorf("block (%d,%d,%d) = %d, want %d", x, y, z, got, want)
+				}
+			}
+		}
+	}
+	changes := 0
+	for _, p := range sp.get() {
+		if _, ok := p.(*pkt.MultiBlockChange); ok {
+			changes++
+		}
+	}
+	if changes != 1 {
+		t.Errorf("sent %d MultiBlockChange packets, want 1", changes)
+	}
+}
+
+func TestCmdExport_VolumeLimit(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	store, err := storage.New(t.TempDir(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	c.storage = store
+
+	c.handleCommand("/export 0 0 0 99 99 99 huge")
+	if _, err := store.LoadSchematic("huge"); err == nil {
+		t.Error("oversized region was exported")
+	}
+}
+
+func TestCmdExport_HugeExtentsDoNotOverflow(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	store := withTestStorage(t, c)
+
+	// Inside the default border, but the volume does not fit in an int.
+	c.handleCommand("/export -29000000 0 -29000000 29000000 255 29000000 huge")
+	if _, err := store.LoadSchematic("huge"); err == nil {
+		t.Error("region with overflowing volume was exported")
+	}
+}
+
+func TestCmdExport_OutsideBorder(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	store := withTestStorage(t, c)
+	c.world.SetBorder(world.RadiusBorder(1))
+
+	c.handleCommand("/export 30 10 0 40 10 0 outside")
+	if _, err := store.LoadSchematic("outside"); err == nil {
+		t.Error("region outside the border was exported")
+	}
+}
+
+// withTestStorage gives c a storage rooted in a temporary directory.
+func withTestStorage(t *testing.T, c *Connection) *storage.Storage {
+	t.Helper()
+	store, err := storage.New(t.TempDir(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	c.storage = store
+	return store
+}
+
+func TestCmdWhitelist_AddRemove(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	store := withTestStorage(t, c)
+
+	c.handleCommand("/whitelist add Bob")
+	c.handleCommand("/whitelist add carol")
+	names, err := store.LoadWhitelist()
+	if err != nil {
+		t.Fatalf("LoadWhitelist: %v", err)
+	}
+	if !names["bob"] || !names["carol"] || len(names) != 2 {
+		t.Fatalf("whitelist after add = %v, want bob and carol", names)
+	}
+
+	c.handleCommand("/whitelist remove BOB")
+	names, _ = store.LoadWhitelist()
+	if names["bob"] || !names["carol"] {
+		t.Errorf("whitelist after remove = %v, want only carol", names)
+	}
+
+	rec := c.rw.(*packetRecorder)
+	rec.buf.Reset()
+	c.handleCommand("/whitelist list")
+	if out := rec.buf.String(); !strings.Contains(out, "carol") || strings.Contains(out, "bob") {
+		t.Errorf("whitelist list output = %q", out)
+	}
+}
+
+func TestWhitelisted(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	store := withTestStorage(t, c)
+	c.cfg.Whitelist = true
+
+	if !c.whitelisted("Mallory") {
+		t.Error("an empty whitelist should allow everyone")
+	}
+	if err := store.SaveWhitelist(map[string]bool{"bob": true}); err != nil {
+		t.Fatal(err)
+	}
+	if !c.whitelisted("Bob") {
+		t.Error("whitelisted player was refused")
+	}
+	if c.whitelisted("Mallory") {
+		t.Error("player missing from the whitelist was allowed")
+	}
+
+	c.cfg.Whitelist = false
+	if !c.whitelisted("Mallory") {
+		t.Error("a disabled whitelist should allow everyone")
+	}
+}
+
+func TestOfflineLogin_RejectsNonWhitelisted(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	store := withTestStorage(t, c)
+	c.cfg.Whitelist = true
+	if err := store.SaveWhitelist(map[string]bool{"bob": true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.handleOfflineLogin("Mallory"); err != nil {
+		t.Fatalf("handleOfflineLogin: %v", err)
+	}
+	if c.ctx.Err() == nil {
+		t.Error("expected the connection to be closed")
+	}
+	if n := countPackets(t, c, 0x00); n != 1 {
+		t.Errorf("sent %d Disconnect packets, want 1", n)
+	}
+}
+
+func TestCmdBan_KicksAndPersists(t *testing.T) {
+	c, _, m := newTestConn("Alice")
+	store := withTestStorage(t, c)
+	eid2 := m.AllocateEntityID()
+	bob := player.NewPlayer(eid2, "bob-uuid", [16]byte{byte(eid2)}, "Bob", nil, (&sentPackets{}).write)
+	var kicked string
+	bob.Disconnect = func(reason string) { kicked = reason }
+	m.Add(bob)
+
+	c.handleCommand("/ban bob spamming chat")
+
+	bans, err := store.LoadBans()
+	if err != nil || len(bans) != 1 {
+		t.Fatalf("LoadBans = %+v, %v; want one entry", bans, err)
+	}
+	if b := bans[0]; b.UUID != "bob-uuid" || b.Name != "Bob" || b.Reason != "spamming chat" {
+		t.Errorf("ban entry = %+v", b)
+	}
+	if !strings.Contains(kicked, "spamming chat") {
+		t.Errorf("Disconnect reason = %q, want the ban reason", kicked)
+	}
+
+	c.handleCommand("/pardon BOB")
+	if bans, _ := store.LoadBans(); len(bans) != 0 {
+		t.Errorf("ban list after pardon = %+v, want empty", bans)
+	}
+}
+
+func TestLogin_RejectsBannedPlayer(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	store := withTestStorage(t, c)
+	ban := storage.BanEntry{UUID: formatUUID(offlineUUID("Mallory")), Name: "Mallory", Reason: "griefing"}
+	if err := store.SaveBans([]storage.BanEntry{ban}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.handleOfflineLogin("Mallory"); err != nil {
+		t.Fatalf("handleOfflineLogin: %v", err)
+	}
+	if c.ctx.Err() == nil {
+		t.Error("expected the connection to be closed")
+	}
+	var reason string
+	for _, p := range recordedPackets(t, c) {
+		if p.id != 0x00 {
+			continue
+		}
+		var d pkt.Disconnect
+		if err := mcnet.Unmarshal(p.data, &d); err != nil {
+			t.Fatalf("unmarshal disconnect: %v", err)
+		}
+		reason = d.Reason
+	}
+	if !strings.Contains(reason, "banned") || !strings.Contains(reason, "griefing") {
+		t.Errorf("disconnect reason = %q, want the ban message and reason", reason)
+	}
+}
+
+func TestLogin_RefusedWhenBanListUnreadable(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "banned-players.json"), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store, err := storage.New(dir, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	c.storage = store
+
+	if err := c.handleOfflineLogin("Mallory"); err != nil {
+		t.Fatalf("handleOfflineLogin: %v", err)
+	}
+	if c.ctx.Err() == nil {
+		t.Error("expected the login to be refused")
+	}
+	if c.state == StatePlay {
+		t.Error("player entered play despite the unreadable ban list")
+	}
+}
+
+func TestLogin_SeesBanAddedByCommand(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	store := withTestStorage(t, c)
+	if _, err := store.Bans(); err != nil {
+		t.Fatal(err)
+	}
+
+	c.handleCommand("/ban Mallory")
+
+	login, _, _ := newTestConn("Bob")
+	login.storage = store
+	if err := login.handleOfflineLogin("Mallory"); err != nil {
+		t.Fatalf("handleOfflineLogin: %v", err)
+	}
+	if login.ctx.Err() == nil {
+		t.Error("expected the banned player to be refused")
+	}
+}
+
+func TestCmdSetblock(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+
+	c.handleCommand("/setblock 3 10 -2 wool:14")
+
+	if got := c.world.GetBlock(3, 10, -2); got != 35<<4|14 {
+		t.Errorf("GetBlock = %d, want %d", got, 35<<4|14)
+	}
+	var change *pkt.BlockChange
+	for _, p := range sp.get() {
+		if bc, ok := p.(*pkt.BlockChange); ok {
+			change = bc
+		}
+	}
+	if change == nil {
+		t.Fatal("expected a BlockChange")
+	}
+	if change.Location != mcnet.EncodePosition(3, 10, -2) || change.Type != 35<<4|14 {
+		t.Errorf("BlockChange = %+v", change)
+	}
+}
+
+func TestCmdSetblock_RelativeCoordinates(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	c.self.SetPosition(10.7, 20, -4.2, 0, 0, true)
+
+	c.handleCommand("/setblock ~ ~-1 ~2 1")
+
+	if got := c.world.GetBlock(10, 19, -3); got != 1<<4 {
+		t.Errorf("GetBlock(10, 19, -3) = %d, want stone", got)
+	}
+}
+
+func TestCmdSetblock_OutOfRangeY(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	rec := c.rw.(*packetRecorder)
+	rec.buf.Reset()
+
+	c.handleCommand("/setblock 0 256 0 stone")
+
+	if out := rec.buf.String(); !strings.Contains(out, "between 0 and 255") || !strings.Contains(out, "red") {
+		t.Errorf("expected a red range error, got %q", out)
+	}
+}
+
+func TestCmdSetblock_UsesMaxBuildHeight(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	c.cfg.MaxBuildHeight = 128
+	rec := c.rw.(*packetRecorder)
+	rec.buf.Reset()
+
+	c.handleCommand("/setblock 0 200 0 stone")
+
+	if out := rec.buf.String(); !strings.Contains(out, "between 0 and 127") {
+		t.Errorf("expected a range error for the build height, got %q", out)
+	}
+	if got := c.world.GetBlock(0, 200, 0); got != 0 {
+		t.Errorf("setblock above the build height changed the block: %d", got)
+	}
+}
+
+func TestCmdSetblock_SandAboveFalls(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	ground := c.findGroundLevel(5, 20, 5)
+	c.world.SetBlock(5, 10, 5, 1<<4)
+	c.world.SetBlock(5, 11, 5, blockSand<<4)
+
+	c.handleCommand("/setblock 5 10 5 air")
+
+	if got := c.world.GetBlock(5, ground, 5); got != blockSand<<4 {
+		t.Errorf("GetBlock(5, %d, 5) = %d, want the sand that was above", ground, got)
+	}
+	if got := c.world.GetBlock(5, 11, 5); got != 0 {
+		t.Errorf("sand stayed in place over air: GetBlock = %d", got)
+	}
+}
+
+func TestCmdSetblock_ReplacedChestDropsContents(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	c.world.SetBlock(2, 4, 2, blockChest<<4)
+	c.players.Container(world.BlockPos{X: 2, Y: 4, Z: 2}, chestSize).Set(0, dirt(5), 0)
+	sp.reset()
+
+	c.handleCommand("/setblock 2 4 2 stone")
+
+	if got := c.players.ContainerContents(); len(got) != 0 {
+		t.Errorf("containers after setblock = %v, want none", got)
+	}
+	spawned := 0
+	for _, p := range sp.get() {
+		if _, ok := p.(*pkt.SpawnEntity); ok {
+			spawned++
+		}
+	}
+	if spawned != 1 {
+		t.Errorf("spawned %d item entities, want the chest's one stack", spawned)
+	}
+}
+
+func TestCmdSetblock_UnknownBlock(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	before := c.world.GetBlock(0, 10, 0)
+
+	c.handleCommand("/setblock 0 10 0 unobtainium")
+
+	if got := c.world.GetBlock(0, 10, 0); got != before {
+		t.Errorf("block changed to %d for an unknown block", got)
+	}
+}
+
+func TestCmdFill_SmallRegion(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+
+	c.handleCommand("/fill 5 11 5 4 10 4 glass")
+
+	for y := 10; y <= 11; y++ {
+		for z := 4; z <= 5; z++ {
+			for x := 4; x <= 5; x++ {
+				if got := c.world.GetBlock(x, y, z); got != 20<<4 {
+					t.Errorf("GetBlock(%d, %d, %d) = %d, want glass", x, y, z, got)
+				}
+			}
+		}
+	}
+	changes := 0
+	for _, p := range sp.get() {
+		if _, ok := p.(*pkt.MultiBlockChange); ok {
+			changes++
+		}
+	}
+	if changes != 1 {
+		t.Errorf("sent %d MultiBlockChange packets, want 1", changes)
+	}
+}
+
+func TestCmdFill_FloatingSandFalls(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	ground := c.findGroundLevel(5, 20, 5)
+
+	c.handleCommand("/fill 5 10 5 6 12 5 sand")
+
+	for x := 5; x <= 6; x++ {
+		for y := ground; y < ground+3; y++ {
+			if got := c.world.GetBlock(x, y, 5); got != blockSand<<4 {
+				t.Errorf("GetBlock(%d, %d, 5) = %d, want landed sand", x, y, got)
+			}
+		}
+		for y := 10; y <= 12; y++ {
+			if got := c.world.GetBlock(x, y, 5); got != 0 {
+				t.Errorf("GetBlock(%d, %d, 5) = %d, want air after the sand fell", x, y, got)
+			}
+		}
+	}
+}
+
+func TestCmdFill_ClearsReplacedFurnace(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	pos := world.BlockPos{X: 6, Y: 5, Z: 5}
+	c.world.SetBlock(pos.X, pos.Y, pos.Z, blockFurnace<<4)
+	c.players.Furnace(pos)
+
+	c.handleCommand("/fill 5 5 5 7 5 5 stone")
+
+	if got := c.players.FurnaceContents(); len(got) != 0 {
+		t.Errorf("furnaces after fill = %v, want none", got)
+	}
+}
+
+func TestCmdFill_VolumeCap(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	rec := c.rw.(*packetRecorder)
+	rec.buf.Reset()
+
+	c.handleCommand("/fill 0 0 0 99 99 99 stone")
+
+	if out := rec.buf.String(); !strings.Contains(out, "limit") {
+		t.Errorf("expected a volume limit error, got %q", out)
+	}
+	if got := c.world.GetBlock(50, 50, 50); got != 0 {
+		t.Errorf("oversized fill changed blocks: %d", got)
+	}
+}
+
+func TestCmdFill_HugeExtentsDoNotOverflow(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	rec := c.rw.(*packetRecorder)
+	rec.buf.Reset()
+
+	c.handleCommand("/fill -29000000 0 -29000000 29000000 255 29000000 stone")
+
+	if out := rec.buf.String(); !strings.Contains(out, "limit") {
+		t.Errorf("expected a volume limit error, got %q", out)
+	}
+}
+
+func TestCmdFill_OutsideBorder(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	c.world.SetBorder(world.RadiusBorder(1))
+
+	c.handleCommand("/fill 40 10 0 50 10 0 stone")
+
+	if got := c.world.GetBlock(45, 10, 0); got != 0 {
+		t.Errorf("fill outside the border changed blocks: %d", got)
+	}
+}
+
+func TestCmdHome_ReturnsToSetHome(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.self.SetPosition(20.5, 4, -7.5, 0, 0, true)
+	c.handleCommand("/sethome")
+
+	c.self.SetPosition(-50.5, 4, 3.5, 0, 0, true)
+	c.handleCommand("/home")
+
+	if pos := c.self.GetPosition(); pos.X != 20.5 || pos.Y != 4 || pos.Z != -7.5 {
+		t.Errorf("teleported to (%v, %v, %v), want (20.5, 4, -7.5)", pos.X, pos.Y, pos.Z)
+	}
+}
+
+func TestCmdHome_FallsBackToSpawn(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.world.SetSpawn(world.BlockPos{X: 8, Y: 4, Z: 8})
+	c.self.SetPosition(-50.5, 4, 3.5, 0, 0, true)
+	c.rw.(*packetRecorder).buf.Reset()
+
+	c.handleCommand("/home")
+
+	if pos := c.self.GetPosition(); pos.X != 8.5 || pos.Z != 8.5 {
+		t.Errorf("teleported to (%v, %v, %v), want the world spawn", pos.X, pos.Y, pos.Z)
+	}
+	chat := recordedChat(t, c)
+	if len(chat) == 0 || !strings.Contains(chat[0], "no home set") {
+		t.Errorf("chat = %q, want a no-home notice", chat)
+	}
+}
+
+func TestCmdWarp_CreateListTeleportDelete(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	store := withTestStorage(t, c)
+	rec := c.rw.(*packetRecorder)
+
+	c.self.SetPosition(100.5, 4, -20.5, 0, 0, true)
+	c.handleCommand("/setwarp Market")
+	warps, err := store.LoadWarps()
+	if err != nil {
+		t.Fatalf("LoadWarps: %v", err)
+	}
+	if w, ok := warps["market"]; !ok || w.X != 100.5 || w.Z != -20.5 {
+		t.Fatalf("warps = %+v, want market at (100.5, -20.5)", warps)
+	}
+
+	rec.buf.Reset()
+	c.handleCommand("/warp")
+	if chat := recordedChat(t, c); len(chat) != 1 || !strings.Contains(chat[0], "market") {
+		t.Errorf("warp list = %q, want it to name market", chat)
+	}
+
+	c.self.SetPosition(0.5, 4, 0.5, 0, 0, true)
+	c.handleCommand("/warp MARKET")
+	if pos := c.self.GetPosition(); pos.X != 100.5 || pos.Z != -20.5 {
+		t.Errorf("warped to (%v, %v), want (100.5, -20.5)", pos.X, pos.Z)
+	}
+
+	c.handleCommand("/delwarp market")
+	if warps, _ := store.LoadWarps(); len(warps) != 0 {
+		t.Errorf("warps after delete = %+v, want none", warps)
+	}
+	rec.buf.Reset()
+	c.handleCommand("/warp market")
+	if chat := recordedChat(t, c); len(chat) != 1 || !strings.Contains(chat[0], "not found") {
+		t.Errorf("chat = %q, want a not-found error", chat)
+	}
+}
+
+func TestCmdEffect_AppliesAndClears(t *testing.T) {
+	c, _, m := newTestConn("Alice")
+	c.gameData = pkt.New()
+	bobP, bob := addTestPlayer(m, "Bob")
+
+	c.handleCommand("/effect Bob night_vision 10 1")
+
+	var applied *pkt.EntityEffect
+	for _, p := range bob.get() {
+		if e, ok := p.(*pkt.EntityEffect); ok && e.EntityID == bobP.EntityID {
+			applied = e
+		}
+	}
+	if applied == nil || applied.EffectID != 16 || applied.Amplifier != 1 || applied.Duration != 200 {
+		t.Fatalf("EntityEffect = %+v, want night vision II for 200 ticks", applied)
+	}
+	if effects := bobP.Effects(); len(effects) != 1 {
+		t.Fatalf("Bob has %d effects, want 1", len(effects))
+	}
+
+	bob.reset()
+	c.handleCommand("/effect Bob clear")
+	if len(bobP.Effects()) != 0 {
+		t.Error("effects remain after clear")
+	}
+	var removed bool
+	for _, p := range bob.get() {
+		if r, ok := p.(*pkt.RemoveEntityEffect); ok && r.EffectID == 16 {
+			removed = true
+		}
+	}
+	if !removed {
+		t.Error("expected RemoveEntityEffect for night vision")
+	}
+}
+
+func TestCmdEffect_RejectsUnknownEffect(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	sp.reset()
+
+	c.handleCommand("/effect Alice flying")
+
+	if len(c.self.Effects()) != 0 || len(sp.get()) != 0 {
+		t.Error("unknown effect should not be applied")
+	}
+}
+
+func TestCmdEnchant_WritesEnchantmentToHeldSlot(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	c.self.Inventory.SetSlot(0, player.Slot{BlockID: 276, ItemCount: 1}) // diamond sword
+
+	c.handleCommand("/enchant unbreaking 3")
+
+	held := c.self.Inventory.HeldItem()
+	if held.NBT == nil || len(held.NBT.Enchantments) != 1 {
+		t.Fatalf("held NBT = %+v, want one enchantment", held.NBT)
+	}
+	if e := held.NBT.Enchantments[0]; e.ID != 34 || e.Level != 3 {
+		t.Errorf("enchantment = %+v, want unbreaking (34) level 3", e)
+	}
+
+	var slotData []byte
+	for _, p := range recordedPackets(t, c) {
+		if p.id == (pkt.SetSlot{}).PacketID() {
+			slotData = p.data
+		}
+	}
+	if slotData == nil {
+		t.Fatal("no SetSlot sent")
+	}
+	// "ench" list of compounds, followed by id=34 and lvl=3 shorts.
+	want := []byte{nbt.TagList, 0, 4, 'e', 'n', 'c', 'h', nbt.TagCompound, 0, 0, 0, 1,
+		nbt.TagShort, 0, 2, 'i', 'd', 0, 34, nbt.TagShort, 0, 3, 'l', 'v', 'l', 0, 3}
+	if !bytes.Contains(slotData, want) {
+		t.Errorf("SetSlot data %x does not contain ench list %x", slotData, want)
+	}
+
+	// Enchanting again with the same enchantment replaces the level.
+	c.handleCommand("/enchant 34 1")
+	if e := c.self.Inventory.HeldItem().NBT.Enchantments; len(e) != 1 || e[0].Level != 1 {
+		t.Errorf("enchantments after re-enchant = %+v, want unbreaking 1 only", e)
+	}
+}
+
+func TestCmdEnchant_RejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  string
+	}{
+		{"wrong category", "/enchant protection"},
+		{"level too high", "/enchant sharpness 6"},
+		{"unknown", "/enchant speed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, _ := newTestConn("Alice")
+			c.gameData = pkt.New()
+			c.self.Inventory.SetSlot(0, player.Slot{BlockID: 276, ItemCount: 1})
+
+			c.handleCommand(tt.cmd)
+
+			if n := c.self.Inventory.HeldItem().NBT; n != nil {
+				t.Errorf("held NBT = %+v, want none", n)
+			}
+			if n := countPackets(t, c, pkt.SetSlot{}.PacketID()); n != 0 {
+				t.Errorf("sent %d SetSlot packets, want 0", n)
+			}
+		})
+	}
+}
+
+func TestCmdClear_EmptiesInventory(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	for i := range 36 {
+		c.self.Inventory.SetSlot(i, player.Slot{BlockID: 1, ItemCount: 64})
+	}
+	c.self.Inventory.DefaultLoadout()
+
+	c.handleCommand("/clear")
+
+	c.self.Inventory.ReadSlots(func(slots [36]player.Slot, armor [4]player.Slot) {
+		for i, s := range slots {
+			if !s.IsEmpty() {
+				t.Errorf("slot %d = %+v, want empty", i, s)
+			}
+		}
+		for i, s := range armor {
+			if !s.IsEmpty() {
+				t.Errorf("armor %d = %+v, want empty", i, s)
+			}
+		}
+	})
+	if n := countPackets(t, c, pkt.WindowItems{}.PacketID()); n != 1 {
+		t.Errorf("sent %d WindowItems packets, want 1", n)
+	}
+	// 35 stone stacks, the sword that replaced slot 0, and 4 armor pieces.
+	chat := recordedChat(t, c)
+	if len(chat) != 1 || !strings.Contains(chat[0], "removing 2245 items") {
+		t.Errorf("chat = %q, want a message reporting 2245 removed items", chat)
+	}
+}
+
+func TestCmdClear_RemovesItemCount(t *testing.T) {
+	c, _, m := newTestConn("Alice")
+	c.gameData = pkt.New()
+	bobP, bob := addTestPlayer(m, "Bob")
+	bobP.Inventory.SetSlot(0, player.Slot{BlockID: 1, ItemCount: 10})
+	bobP.Inventory.SetSlot(5, player.Slot{BlockID: 1, ItemCount: 10})
+	bobP.Inventory.SetSlot(9, player.Slot{BlockID: 4, ItemCount: 10})
+
+	c.handleCommand("/clear Bob stone 15")
+
+	if s := bobP.Inventory.GetSlot(0); !s.IsEmpty() {
+		t.Errorf("slot 0 = %+v, want empty", s)
+	}
+	if s := bobP.Inventory.GetSlot(5); s.BlockID != 1 || s.ItemCount != 5 {
+		t.Errorf("slot 5 = %+v, want 5 stone", s)
+	}
+	if s := bobP.Inventory.GetSlot(9); s.BlockID != 4 || s.ItemCount != 10 {
+		t.Errorf("slot 9 = %+v, want untouched cobblestone", s)
+	}
+	var setSlots int
+	for _, p := range bob.get() {
+		if _, ok := p.(*pkt.SetSlot); ok {
+			setSlots++
+		}
+	}
+	if setSlots != 2 {
+		t.Errorf("Bob got %d SetSlot packets, want 2", setSlots)
+	}
+	if chat := recordedChat(t, c); len(chat) != 1 || !strings.Contains(chat[0], "removing 15 items") {
+		t.Errorf("chat = %q, want a message reporting 15 removed items", chat)
+	}
+}
+
+func TestCmdXP_PointsAndLevels(t *testing.T) {
+	c, sp, m := newTestConn("Alice")
+	bobP, bob := addTestPlayer(m, "Bob")
+
+	c.handleCommand("/xp 20")
+	if level, points, total := c.self.Experience(); level != 2 || points != 4 || total != 20 {
+		t.Errorf("Alice experience = level %d, %d points, total %d; want level 2, 4 points, total 20", level, points, total)
+	}
+	var sent *pkt.Experience
+	for _, p := range sp.get() {
+		if e, ok := p.(*pkt.Experience); ok {
+			sent = e
+		}
+	}
+	if sent == nil || sent.Level != 2 || sent.TotalExperience != 20 {
+		t.Errorf("Experience packet = %+v, want level 2 with 20 total", sent)
+	}
+
+	c.handleCommand("/xp 5L Bob")
+	if level, _, _ := bobP.Experience(); level != 5 {
+		t.Errorf("Bob level = %d, want 5", level)
+	}
+	sent = nil
+	for _, p := range bob.get() {
+		if e, ok := p.(*pkt.Experience); ok {
+			sent = e
+		}
+	}
+	if sent == nil || sent.Level != 5 {
+		t.Errorf("Bob Experience packet = %+v, want level 5", sent)
+	}
+}
+
+func TestCmdXP_RejectsNegativePoints(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	sp.reset()
+
+	c.handleCommand("/xp -5")
+
+	if _, _, total := c.self.Experience(); total != 0 {
+		t.Errorf("total = %d, want 0", total)
+	}
+	if len(sp.get()) != 0 {
+		t.Error("Experience packet sent for a rejected command")
+	}
+}
+
+func TestCmdSpeed_SendsScaledAbilities(t *testing.T) {
+	tests := []struct {
+		cmd      string
+		wantFly  float32
+		wantWalk float32
+	}{
+		{"/speed fly 2", 0.1, 0.1},
+		{"/speed walk 0.5", 0.05, 0.05},
+		{"/speed FLY 10", 0.5, 0.1},
+		{"/speed walk 0", 0.05, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cmd, func(t *testing.T) {
+			c, _, _ := newTestConn("Alice")
+
+			c.handleCommand(tt.cmd)
+
+			var got *pkt.AbilitiesCB
+			for _, p := range recordedPackets(t, c) {
+				if p.id != (pkt.AbilitiesCB{}).PacketID() {
+					continue
+				}
+				got = &pkt.AbilitiesCB{}
+				if err := mcnet.Unmarshal(p.data, got); err != nil {
+					t.Fatalf("unmarshal abilities: %v", err)
+				}
+			}
+			if got == nil {
+				t.Fatal("no AbilitiesCB sent")
+			}
+			if got.FlyingSpeed != tt.wantFly || got.WalkingSpeed != tt.wantWalk {
+				t.Errorf("speeds = fly %v, walk %v; want fly %v, walk %v", got.FlyingSpeed, got.WalkingSpeed, tt.wantFly, tt.wantWalk)
+			}
+		})
+	}
+}
+
+func TestCmdSpeed_KeptAcrossGameModeChange(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.handleCommand("/speed fly 3")
+	c.rw.(*packetRecorder).buf.Reset()
+
+	c.handleCommand("/gamemode creative")
+
+	var got pkt.AbilitiesCB
+	for _, p := range recordedPackets(t, c) {
+		if p.id == (pkt.AbilitiesCB{}).PacketID() {
+			if err := mcnet.Unmarshal(p.data, &got); err != nil {
+				t.Fatalf("unmarshal abilities: %v", err)
+			}
+		}
+	}
+	if got.FlyingSpeed != baseFlySpeed*3 {
+		t.Errorf("flying speed after gamemode change = %v, want %v", got.FlyingSpeed, baseFlySpeed*3)
+	}
+}
+
+func TestCmdSpeed_RejectsOutOfRange(t *testing.T) {
+	for _, cmd := range []string{"/speed fly 11", "/speed walk -1", "/speed run 2", "/speed fly NaN"} {
+		c, _, _ := newTestConn("Alice")
+		c.handleCommand(cmd)
+		if n := countPackets(t, c, pkt.AbilitiesCB{}.PacketID()); n != 0 {
+			t.Errorf("%s: sent %d AbilitiesCB packets, want 0", cmd, n)
+		}
+		if fly, walk := c.self.Speeds(); fly != 1 || walk != 1 {
+			t.Errorf("%s: speeds = %v, %v; want 1, 1", cmd, fly, walk)
+		}
+	}
+}
+
+func TestCmdTPS(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.TickStats = func() (float64, float64) { return 18.25, 12.5 }
+
+	c.handleCommand("/lag")
+
+	chat := recordedChat(t, c)
+	if len(chat) != 1 || !strings.Contains(chat[0], "TPS: 18.2 (12.50 ms/tick)") || !strings.Contains(chat[0], "yellow") {
+		t.Errorf("chat = %q, want a yellow TPS report", chat)
+	}
+}
+
+func TestCmdScoreboard(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+
+	c.handleCommand("/scoreboard objectives add kills Player Kills")
+	c.handleCommand("/scoreboard objectives setdisplay sidebar kills")
+	c.handleCommand("/scoreboard players set Alice kills 7")
+
+	sb := c.players.Scoreboard()
+	if !sb.HasObjective("kills") {
+		t.Fatal("objective kills was not created")
+	}
+	if score, ok := sb.Score("kills", "Alice"); !ok || score != 7 {
+		t.Errorf("score = %d, %v; want 7", score, ok)
+	}
+	var objectives, displays, scores int
+	for _, p := range sp.get() {
+		switch p := p.(type) {
+		case *pkt.ScoreboardObjective:
+			objectives++
+		case *pkt.ScoreboardDisplayObjective:
+			if p.Position == player.DisplaySidebar && p.Name == "kills" {
+				displays++
+			}
+		case *pkt.ScoreboardScore:
+			scores++
+		}
+	}
+	if objectives != 1 || displays != 1 || scores != 1 {
+		t.Errorf("got %d objective, %d display and %d score packets; want one of each", objectives, displays, scores)
+	}
+}
+
+func TestCmdScoreboard_Rejects(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	c.handleCommand("/scoreboard objectives add kills")
+	sp.reset()
+
+	for _, cmd := range []string{
+		"/scoreboard objectives add kills",
+		"/scoreboard objectives add waytoolongobjectivename",
+		"/scoreboard objectives setdisplay sidebar deaths",
+		"/scoreboard objectives setdisplay nowhere kills",
+		"/scoreboard players set Alice deaths 1",
+		"/scoreboard players set Alice kills lots",
+	} {
+		c.handleCommand(cmd)
+	}
+	if n := len(sp.get()); n != 0 {
+		t.Errorf("rejected commands sent %d packets", n)
+	}
+}
+
+func TestCmdTitle(t *testing.T) {
+	c, sp, m := newTestConn("Alice")
+	_, bob := addTestPlayer(m, "Bob")
+	sp.reset()
+
+	c.handleCommand("/title Bob Round one")
+
+	var titles []*pkt.Title
+	for _, p := range bob.get() {
+		if tp, ok := p.(*pkt.Title); ok {
+			titles = append(titles, tp)
+		}
+	}
+	if len(titles) != 2 {
+		t.Fatalf("Bob got %d Title packets, want times and title", len(titles))
+	}
+	if last := titles[1].Data; last[0] != 0 || !bytes.Contains(last, []byte(`{"text":"Round one"}`)) {
+		t.Errorf("title packet = %q, want set title to \"Round one\"", last)
+	}
+	if len(sp.get()) != 0 {
+		t.Error("the sender was also shown the title")
+	}
+
+	c.handleCommand("/title Carol hi")
+	if chat := recordedChat(t, c); len(chat) != 2 || !strings.Contains(chat[1], "not found") {
+		t.Errorf("chat = %q, want a not found error for Carol", chat)
+	}
+}
+
+func TestCmdSummon_Item(t *testing.T) {
+	c, _, m := newTestConn("Alice")
+	c.gameData = pkt.New()
+	_, bob := addTestPlayer(m, "Bob")
+	bob.reset()
+
+	c.handleCommand("/summon item diamond 3")
+
+	var spawn *pkt.SpawnEntity
+	for _, p := range bob.get() {
+		if s, ok := p.(*pkt.SpawnEntity); ok {
+			spawn = s
+		}
+	}
+	if spawn == nil {
+		t.Fatal("Bob should see the summoned item entity")
+	}
+	if spawn.Data[1] != 2 {
+		t.Errorf("object type = %d, want item (2)", spawn.Data[1])
+	}
+	if chat := recordedChat(t, c); len(chat) != 1 || !strings.Contains(chat[0], "Summoned 3 diamond") {
+		t.Errorf("chat = %q, want a summon confirmation", chat)
+	}
+
+	c.rw.(*packetRecorder).buf.Reset()
+	c.handleCommand("/summon item nosuchitem")
+	if chat := recordedChat(t, c); len(chat) != 1 || !strings.Contains(chat[0], "Unknown item") {
+		t.Errorf("chat = %q, want an unknown item error", chat)
+	}
+}
+
+func TestCmdSummon_XP(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	sp.reset()
+
+	c.handleCommand("/summon xp 20")
+
+	var total int16
+	for _, p := range sp.get() {
+		if orb, ok := p.(*pkt.SpawnEntityExperienceOrb); ok {
+			total += orb.Count
+		}
+	}
+	if total != 20 {
+		t.Errorf("orbs worth %d, want 20", total)
+	}
+}
+
+func TestCmdSummon_OpsOnly(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	c.cfg.Ops = []string{"Bob"}
+	sp.reset()
+
+	c.handleCommand("/summon xp 20")
+	if len(sp.get()) != 0 {
+		t.Error("a non-operator summoned orbs")
+	}
+	if chat := recordedChat(t, c); len(chat) != 1 || !strings.Contains(chat[0], "permission") {
+		t.Errorf("chat = %q, want a permission error", chat)
+	}
+
+	c.cfg.Ops = []string{"alice"}
+	c.handleCommand("/summon xp 20")
+	if len(sp.get()) == 0 {
+		t.Error("an operator could not summon orbs")
+	}
+}
+
+func TestIsOp_FailsClosedWithoutOps(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.cfg.Ops = nil
+	withTestStorage(t, c)
+
+	if c.isOp() {
+		t.Error("a player is an operator although no operators are configured")
+	}
+}
+
+func TestIsOp_CachesOpsFile(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.cfg.Ops = nil
+	dir := t.TempDir()
+	store, err := storage.New(dir, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	c.storage = store
+	if err := store.SaveOps([]storage.OpEntry{{UUID: "test-uuid", Name: "Alice"}}); err != nil {
+		t.Fatalf("SaveOps: %v", err)
+	}
+	if !c.isOp() {
+		t.Fatal("a player in ops.json is not an operator")
+	}
+
+	// Editing the file by hand takes effect on the next /op or /deop, not
+	// on every command.
+	if err := os.Remove(filepath.Join(dir, "ops.json")); err != nil {
+		t.Fatalf("remove ops.json: %v", err)
+	}
+	if !c.isOp() {
+		t.Error("isOp re-read ops.json")
+	}
+}
+
+func TestCmdOp_GrantsAccess(t *testing.T) {
+	c, _, m := newTestConn("Alice")
+	store := withTestStorage(t, c)
+	addTestPlayer(m, "Bob")
+
+	// Alice is an operator through the config and appoints herself and Bob.
+	c.handleCommand("/op Alice")
+	c.handleCommand("/op Bob")
+	c.cfg.Ops = nil
+	ops, err := store.LoadOps()
+	if err != nil {
+		t.Fatalf("LoadOps: %v", err)
+	}
+	if len(ops) != 2 || !storage.IsOp(ops, "test-uuid") || !storage.IsOp(ops, "uuid-Bob") {
+		t.Fatalf("ops = %+v, want Alice and Bob", ops)
+	}
+
+	c.handleCommand("/gamemode survival")
+	if got := c.self.GetGameMode(); got != packet.GameModeSurvival {
+		t.Errorf("an operator could not change game mode, got %d", got)
+	}
+
+	c.handleCommand("/deop alice")
+	c.rw.(*packetRecorder).buf.Reset()
+	c.handleCommand("/gamemode creative")
+	if got := c.self.GetGameMode(); got != packet.GameModeSurvival {
+		t.Error("a player who lost operator status changed game mode")
+	}
+	if chat := recordedChat(t, c); len(chat) != 1 || !strings.Contains(chat[0], "permission") {
+		t.Errorf("chat = %q, want a permission error", chat)
+	}
+
+	// Open commands stay available.
+	c.rw.(*packetRecorder).buf.Reset()
+	c.handleCommand("/list")
+	if chat := recordedChat(t, c); len(chat) != 1 || !strings.Contains(chat[0], "Online players") {
+		t.Errorf("chat = %q, want the player list", chat)
+	}
+}
+
+func TestCmdHelp_HidesOpCommands(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.cfg.Ops = nil
+	store := withTestStorage(t, c)
+	if err := store.SaveOps([]storage.OpEntry{{UUID: "uuid-Bob", Name: "Bob"}}); err != nil {
+		t.Fatalf("SaveOps: %v", err)
+	}
+
+	c.handleCommand("/help")
+	for _, line := range recordedChat(t, c) {
+		if strings.Contains(line, "/gamemode") {
+			t.Errorf("help shows an operator command to a non-operator: %q", line)
+		}
+	}
+}
+
+func TestCmdGamerule(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+
+	c.handleCommand("/gamerule keepInventory")
+	if chat := recordedChat(t, c); len(chat) != 1 || !strings.Contains(chat[0], "keepInventory = false") {
+		t.Errorf("chat = %q, want the default value", chat)
+	}
+
+	c.rw.(*packetRecorder).buf.Reset()
+	c.handleCommand("/gamerule keepInventory TRUE")
+	if !c.world.GameRuleBool(world.RuleKeepInventory) {
+		t.Error("keepInventory should be on")
+	}
+	if chat := recordedChat(t, c); len(chat) != 1 || !strings.Contains(chat[0], "updated to true") {
+		t.Errorf("chat = %q, want an update confirmation", chat)
+	}
+
+	c.rw.(*packetRecorder).buf.Reset()
+	c.handleCommand("/gamerule keepInventory maybe")
+	if chat := recordedChat(t, c); len(chat) != 1 || !strings.Contains(chat[0], "true or false") {
+		t.Errorf("chat = %q, want an invalid value error", chat)
+	}
+	if !c.world.GameRuleBool(world.RuleKeepInventory) {
+		t.Error("an invalid value should leave the rule unchanged")
+	}
+
+	c.rw.(*packetRecorder).buf.Reset()
+	c.handleCommand("/gamerule noSuchRule true")
+	if chat := recordedChat(t, c); len(chat) != 1 || !strings.Contains(chat[0], "No game rule") {
+		t.Errorf("chat = %q, want an unknown rule error", chat)
+	}
+
+	c.rw.(*packetRecorder).buf.Reset()
+	c.handleCommand("/gamerule")
+	if chat := recordedChat(t, c); len(chat) != 1 || !strings.Contains(chat[0], "doDaylightCycle = true") {
+		t.Errorf("chat = %q, want a list of rules", chat)
+	}
+}
+
+func TestCmdGamerule_DaylightCycleFreezesClients(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	c.world.SetTime(0, 6000)
+	sp.reset()
+
+	c.handleCommand("/gamerule doDaylightCycle false")
+
+	var update *pkt.UpdateTime
+	for _, p := range sp.get() {
+		if u, ok := p.(*pkt.UpdateTime); ok {
+			update = u
+		}
+	}
+	if update == nil || update.Time != -6000 {
+		t.Errorf("time update = %+v, want a frozen time of -6000", update)
+	}
+}
+
+func TestCmdKill_DropsInventory(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.self.Inventory.SetSlot(0, player.Slot{BlockID: 1, ItemCount: 5})
+	c.self.Inventory.SetArmor(0, player.Slot{BlockID: 301, ItemCount: 1})
+
+	c.handleCommand("/kill")
+
+	if s := c.self.Inventory.GetSlot(0); !s.IsEmpty() {
+		t.Errorf("slot 0 = %+v, want it dropped", s)
+	}
+	if s := c.self.Inventory.GetArmor(0); !s.IsEmpty() {
+		t.Errorf("boots = %+v, want them dropped", s)
+	}
+}
+
+func TestCmdKill_CreativeKeepsInventory(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.self.SetGameMode(packet.GameModeCreative)
+	c.self.Inventory.SetSlot(0, player.Slot{BlockID: 1, ItemCount: 5})
+
+	c.handleCommand("/kill")
+
+	if s := c.self.Inventory.GetSlot(0); s.BlockID != 1 {
+		t.Errorf("slot 0 = %+v, want creative players to keep items", s)
+	}
+}
+
+func TestCmdKill_KeepInventory(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	if err := c.world.SetGameRule(world.RuleKeepInventory, "true"); err != nil {
+		t.Fatal(err)
+	}
+	c.self.Inventory.SetSlot(0, player.Slot{BlockID: 1, ItemCount: 5})
+
+	c.handleCommand("/kill")
+
+	if s := c.self.Inventory.GetSlot(0); s.BlockID != 1 || s.ItemCount != 5 {
+		t.Errorf("slot 0 = %+v, want it kept", s)
+	}
+}
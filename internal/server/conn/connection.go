@@ -2,9 +2,11 @@ package conn
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"math/rand"
 	"net"
 	"sync"
 	"time"
@@ -13,11 +15,16 @@ import (
 	"github.com/go-theft-craft/server/internal/server/player"
 	"github.com/go-theft-craft/server/internal/server/storage"
 	"github.com/go-theft-craft/server/pkg/gamedata"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
 	mcnet "github.com/go-theft-craft/server/pkg/protocol"
 	"github.com/go-theft-craft/server/pkg/world"
 	"github.com/go-theft-craft/server/pkg/world/gen"
 )
 
+// defaultWriteTimeout bounds how long a single packet write may block on an
+// unresponsive peer before the connection is torn down.
+const defaultWriteTimeout = 10 * time.Second
+
 // State represents the connection state.
 type State int
 
@@ -39,8 +46,14 @@ type Connection struct {
 	world   *world.World
 	storage *storage.Storage
 
-	mu    sync.Mutex
-	state State
+	mu           sync.Mutex
+	state        State
+	writeTimeout time.Duration
+
+	// Packet compression, enabled at the end of login. Written under mu by
+	// the Handle goroutine, so that goroutine may read it without locking.
+	compression          bool
+	compressionThreshold int
 
 	// Player management
 	players *player.Manager
@@ -60,28 +73,67 @@ type Connection struct {
 
 	// Inventory state (only accessed from Handle goroutine)
 	cursorSlot     player.Slot
-	craftingGrid   [4]player.Slot
+	craftingGrid   []player.Slot
 	craftingOutput player.Slot
 
+	// Open window (0 = player inventory) and its slot layout
+	windowID uint8
+	window   windowLayout
+
+	// Block container shown in the open window, if any, and the last
+	// window ID handed out. furnace is set when the container is a furnace.
+	container    *player.Container
+	furnace      *player.Furnace
+	lastWindowID uint8
+
 	// Drag state for mode 5 (paint/drag click)
 	dragMode   int8
 	dragSlots  []int16
 	dragActive bool
 
-	// Death state (only accessed from Handle goroutine)
-	dead bool
+	// Fall tracking (only accessed from Handle goroutine)
+	fallPeakY float64 // highest Y reached since last on the ground
+	falling   bool
+
+	// Block being dug in survival and when digging began (only accessed
+	// from Handle goroutine). digTicks is the computed break time.
+	digging  bool
+	digPos   int64
+	digStart time.Time
+	digTicks int
+
+	// Whether the player is drawing a bow and since when (only accessed
+	// from Handle goroutine).
+	drawingBow bool
+	drawStart  time.Time
+
+	// Sign the player may write on, set when it is placed (only accessed
+	// from Handle goroutine).
+	signEditing bool
+	signEditPos world.BlockPos
+
+	// Disallowed flight requests in the current window (only accessed from Handle goroutine)
+	flyViolations     int
+	flyViolationStart time.Time
 
 	// Game data registries (blocks, materials, recipes, etc.)
 	gameData *gamedata.GameData
 
 	// SaveAll triggers a server-wide save (set by Server).
 	SaveAll func()
+
+	// DropRNG decides item drops from the world seed; it is shared by all
+	// connections and safe for concurrent use (set by Server).
+	DropRNG *rand.Rand
+
+	// TickStats reports ticks per second and average ms per tick (set by Server).
+	TickStats func() (tps, msPerTick float64)
 }
 
 // NewConnection creates a new Connection from a raw TCP connection.
 func NewConnection(ctx context.Context, conn net.Conn, cfg *config.Config, log *slog.Logger, w *world.World, players *player.Manager, store *storage.Storage, gd *gamedata.GameData) *Connection {
 	ctx, cancel := context.WithCancel(ctx)
-	return &Connection{
+	c := &Connection{
 		conn:           conn,
 		rw:             conn,
 		cfg:            cfg,
@@ -89,16 +141,17 @@ func NewConnection(ctx context.Context, conn net.Conn, cfg *config.Config, log *
 		ctx:            ctx,
 		cancel:         cancel,
 		state:          StateHandshake,
+		writeTimeout:   defaultWriteTimeout,
 		world:          w,
 		storage:        store,
 		players:        players,
 		loadedChunks:   make(map[gen.ChunkPos]struct{}),
 		keepAliveAcked: true,
 		cursorSlot:     player.EmptySlot,
-		craftingOutput: player.EmptySlot,
-		craftingGrid:   [4]player.Slot{player.EmptySlot, player.EmptySlot, player.EmptySlot, player.EmptySlot},
 		gameData:       gd,
 	}
+	c.openWindow(0, windowTypePlayer)
+	return c
 }
 
 // Handle runs the connection lifecycle. It reads packets and dispatches
@@ -111,7 +164,11 @@ func (c *Connection) Handle() {
 					c.log.Error("save player on disconnect", "error", err)
 				}
 			}
+			c.closeContainer()
 			c.players.Remove(c.self)
+			for pos := range c.loadedChunks {
+				c.releaseChunk(pos)
+			}
 		}
 		c.cancel()
 		c.conn.Close()
@@ -131,21 +188,56 @@ func (c *Connection) Handle() {
 			if c.ctx.Err() != nil {
 				return
 			}
-			if err == io.EOF {
-				return
-			}
-			c.log.Error("handling packet", "state", c.state, "error", err)
+			c.logCloseError(err)
 			return
 		}
 	}
 }
 
+// logCloseError logs the error that ended the connection according to its
+// kind: client disconnects are silent, protocol violations and malformed
+// packets are counted, and anything else is treated as a server error.
+func (c *Connection) logCloseError(err error) {
+	attrs := []any{"state", c.state, "error", err}
+	var pe *PacketError
+	if errors.As(err, &pe) {
+		attrs = append(attrs, "packetID", fmt.Sprintf("0x%02X", pe.PacketID))
+	}
+
+	switch {
+	case errors.Is(err, ErrClientDisconnect):
+		c.log.Debug("client disconnected", attrs...)
+	case errors.Is(err, ErrProtocolViolation), errors.Is(err, mcnet.ErrMalformedPacket):
+		n := protocolViolations.Add(1)
+		c.log.Warn("protocol violation", append(attrs, "violations", n)...)
+	default:
+		c.log.Error("handling packet", attrs...)
+	}
+}
+
 func (c *Connection) handleNextPacket() error {
-	packetID, data, err := mcnet.ReadRawPacket(c.rw)
+	var packetID int32
+	var data []byte
+	var err error
+	if c.compression {
+		packetID, data, err = mcnet.ReadCompressedPacket(c.rw, c.compressionThreshold)
+	} else {
+		packetID, data, err = mcnet.ReadRawPacket(c.rw)
+	}
 	if err != nil {
+		if isDisconnectErr(err) {
+			return fmt.Errorf("%w: %w", ErrClientDisconnect, err)
+		}
 		return err
 	}
 
+	if err := c.dispatchPacket(packetID, data); err != nil {
+		return &PacketError{State: c.state, PacketID: packetID, Err: err}
+	}
+	return nil
+}
+
+func (c *Connection) dispatchPacket(packetID int32, data []byte) error {
 	switch c.state {
 	case StateHandshake:
 		return c.handleHandshake(packetID, data)
@@ -161,10 +253,32 @@ func (c *Connection) handleNextPacket() error {
 }
 
 // writePacket writes a packet to the connection under the write lock.
+// Writes are bounded by the write timeout so a dead peer cannot hold the lock
+// indefinitely; a failed write leaves the stream unusable, so the connection
+// is closed.
 func (c *Connection) writePacket(p mcnet.Packet) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	return mcnet.WritePacket(c.rw, p)
+	if c.conn != nil && c.writeTimeout > 0 {
+		_ = c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout))
+	}
+	data, err := mcnet.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("marshal packet 0x%02X: %w", p.PacketID(), err)
+	}
+	if c.compression {
+		err = mcnet.WriteCompressedPacket(c.rw, p.PacketID(), data, c.compressionThreshold)
+	} else {
+		err = mcnet.WriteRawPacket(c.rw, p.PacketID(), data)
+	}
+	if err != nil {
+		if c.conn != nil {
+			c.cancel()
+			c.conn.Close()
+		}
+		return fmt.Errorf("write packet 0x%02X: %w", p.PacketID(), err)
+	}
+	return nil
 }
 
 // disconnect sends a disconnect packet and closes the connection.
@@ -173,6 +287,24 @@ func (c *Connection) disconnect(reason string) {
 	c.cancel()
 }
 
+// enableCompression sends SetCompression and switches the connection to the
+// compressed framing, unless compression is disabled in the config. It must
+// be called during login, before any other goroutine writes to c.
+func (c *Connection) enableCompression() error {
+	threshold := c.cfg.CompressionThreshold
+	if threshold < 0 {
+		return nil
+	}
+	if err := c.writePacket(&pkt.Compress{Threshold: int32(threshold)}); err != nil {
+		return fmt.Errorf("write set compression: %w", err)
+	}
+	c.mu.Lock()
+	c.compression = true
+	c.compressionThreshold = threshold
+	c.mu.Unlock()
+	return nil
+}
+
 // enableEncryption wraps the connection with AES/CFB8 encryption.
 func (c *Connection) enableEncryption(sharedSecret []byte) error {
 	enc, err := newEncryptedConn(c.conn, sharedSecret)
@@ -0,0 +1,260 @@
+package conn
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-theft-craft/server/internal/server/config"
+	"github.com/go-theft-craft/server/internal/server/player"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+	"github.com/go-theft-craft/server/pkg/world"
+	"github.com/go-theft-craft/server/pkg/world/gen"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent log writes.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// startPipeConn runs Handle on one end of an in-memory pipe and returns the
+// client end, the captured log, and a channel closed when Handle returns.
+func startPipeConn(t *testing.T) (net.Conn, *syncBuffer, <-chan struct{}) {
+	t.Helper()
+	server, client := net.Pipe()
+	logBuf := &syncBuffer{}
+	log := slog.New(slog.NewTextHandler(logBuf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	c := NewConnection(context.Background(), server, config.DefaultConfig(), log,
+		world.NewWorld(gen.NewFlatGenerator(0)), player.NewManager(8), nil, nil)
+
+	done := make(chan struct{})
+	go func() {
+		c.Handle()
+		close(done)
+	}()
+	t.Cleanup(func() { client.Close() })
+	return client, logBuf, done
+}
+
+func waitClosed(t *testing.T, done <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("connection was not closed")
+	}
+}
+
+func TestHandle_ProtocolViolationLoggedAndClosed(t *testing.T) {
+	client, logBuf, done := startPipeConn(t)
+	before := ProtocolViolations()
+
+	err := mcnet.WritePacket(client, &pkt.SetProtocol{
+		ProtocolVersion: pkt.ProtocolVersion,
+		ServerHost:      "localhost",
+		ServerPort:      25565,
+		NextState:       5, // invalid
+	})
+	if err != nil {
+		t.Fatalf("write handshake: %v", err)
+	}
+
+	waitClosed(t, done)
+
+	if !strings.Contains(logBuf.String(), "protocol violation") {
+		t.Errorf("expected protocol violation to be logged, got:\n%s", logBuf.String())
+	}
+	if got := ProtocolViolations(); got != before+1 {
+		t.Errorf("ProtocolViolations() = %d, want %d", got, before+1)
+	}
+	if _, err := client.Read(make([]byte, 1)); err == nil {
+		t.Error("expected connection to be closed")
+	}
+}
+
+func TestHandle_EOFClosesSilently(t *testing.T) {
+	client, logBuf, done := startPipeConn(t)
+
+	client.Close()
+	waitClosed(t, done)
+
+	out := logBuf.String()
+	if strings.Contains(out, "level=ERROR") || strings.Contains(out, "level=WARN") {
+		t.Errorf("expected clean EOF to close silently, got:\n%s", out)
+	}
+}
+
+func TestPacketError_Unwraps(t *testing.T) {
+	err := error(&PacketError{
+		State:    StateLogin,
+		PacketID: 0x05,
+		Err:      fmt.Errorf("%w: unexpected login packet 0x05", ErrProtocolViolation),
+	})
+
+	if !errors.Is(err, ErrProtocolViolation) {
+		t.Error("expected PacketError to unwrap to ErrProtocolViolation")
+	}
+	var pe *PacketError
+	if !errors.As(fmt.Errorf("wrapped: %w", err), &pe) || pe.PacketID != 0x05 {
+		t.Error("expected errors.As to recover the PacketError")
+	}
+	if errors.Is(err, ErrClientDisconnect) || errors.Is(err, io.EOF) {
+		t.Error("protocol violation should not look like a disconnect")
+	}
+}
+
+// blockingConn is a net.Conn whose writes block until the write deadline
+// passes, like a socket whose peer has vanished without a FIN.
+type blockingConn struct {
+	net.Conn
+	mu       sync.Mutex
+	deadline time.Time
+	closed   chan struct{}
+	once     sync.Once
+}
+
+func newBlockingConn() *blockingConn {
+	return &blockingConn{closed: make(chan struct{})}
+}
+
+func (b *blockingConn) Read([]byte) (int, error) {
+	<-b.closed
+	return 0, net.ErrClosed
+}
+
+func (b *blockingConn) Write([]byte) (int, error) {
+	b.mu.Lock()
+	deadline := b.deadline
+	b.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		timeout = time.After(time.Until(deadline))
+	}
+	select {
+	case <-timeout:
+		return 0, os.ErrDeadlineExceeded
+	case <-b.closed:
+		return 0, net.ErrClosed
+	}
+}
+
+func (b *blockingConn) SetWriteDeadline(t time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.deadline = t
+	return nil
+}
+
+func (b *blockingConn) Close() error {
+	b.once.Do(func() { close(b.closed) })
+	return nil
+}
+
+func (b *blockingConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 25565}
+}
+
+func TestWritePacket_DeadlineTearsDownConnection(t *testing.T) {
+	bc := newBlockingConn()
+	c := NewConnection(context.Background(), bc, config.DefaultConfig(),
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		world.NewWorld(gen.NewFlatGenerator(0)), player.NewManager(8), nil, nil)
+	c.writeTimeout = 50 * time.Millisecond
+
+	done := make(chan struct{})
+	go func() {
+		c.Handle()
+		close(done)
+	}()
+
+	errc := make(chan error, 1)
+	go func() { errc <- c.writePacket(&pkt.KeepAliveCB{KeepAliveID: 1}) }()
+
+	select {
+	case err := <-errc:
+		if !errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Errorf("writePacket error = %v, want deadline exceeded", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("writePacket blocked past its deadline")
+	}
+
+	waitClosed(t, done)
+	select {
+	case <-bc.closed:
+	default:
+		t.Error("expected the underlying conn to be closed")
+	}
+}
+
+func TestEnableCompression_SwitchesFraming(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.cfg.CompressionThreshold = 64
+
+	if err := c.enableCompression(); err != nil {
+		t.Fatalf("enableCompression: %v", err)
+	}
+	msg := &pkt.ChatCB{Message: fmt.Sprintf(`{"text":%q}`, strings.Repeat("a", 500))}
+	if err := c.writePacket(msg); err != nil {
+		t.Fatalf("writePacket: %v", err)
+	}
+
+	rec := c.rw.(*packetRecorder)
+	r := bytes.NewReader(rec.buf.Bytes())
+
+	// SetCompression itself is sent in the uncompressed framing.
+	id, data, err := mcnet.ReadRawPacket(r)
+	if err != nil || id != 0x03 {
+		t.Fatalf("first packet = 0x%02X, %v; want SetCompression", id, err)
+	}
+	var sc pkt.Compress
+	if err := mcnet.Unmarshal(data, &sc); err != nil || sc.Threshold != 64 {
+		t.Errorf("SetCompression threshold = %d, %v; want 64", sc.Threshold, err)
+	}
+
+	id, data, err = mcnet.ReadCompressedPacket(r, 64)
+	if err != nil || id != msg.PacketID() {
+		t.Fatalf("second packet = 0x%02X, %v; want compressed chat", id, err)
+	}
+	var got pkt.ChatCB
+	if err := mcnet.Unmarshal(data, &got); err != nil || got.Message != msg.Message {
+		t.Errorf("decoded chat mismatch: %v", err)
+	}
+}
+
+func TestEnableCompression_Disabled(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.cfg.CompressionThreshold = -1
+
+	if err := c.enableCompression(); err != nil {
+		t.Fatalf("enableCompression: %v", err)
+	}
+	if c.compression || len(recordedPackets(t, c)) != 0 {
+		t.Error("compression should stay off when the threshold is negative")
+	}
+}
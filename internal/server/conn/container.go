@@ -0,0 +1,234 @@
+package conn
+
+import (
+	"bytes"
+
+	"github.com/go-theft-craft/server/internal/server/player"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+	"github.com/go-theft-craft/server/pkg/world"
+)
+
+// Block IDs of blocks that open a window when used.
+const (
+	blockChest         = 54
+	blockCraftingTable = 58
+	blockFurnace       = 61
+	blockLitFurnace    = 62
+)
+
+// maxWindowID is the highest window ID handed out before wrapping back to 1,
+// matching the vanilla server.
+const maxWindowID = 100
+
+// nextWindowID allocates the ID for a newly opened window. ID 0 is the
+// player inventory and is never returned.
+func (c *Connection) nextWindowID() uint8 {
+	c.lastWindowID = c.lastWindowID%maxWindowID + 1
+	return c.lastWindowID
+}
+
+// openBlockWindow opens the window of the block at x, y, z. It returns
+// false if the block has no window or is out of the player's reach.
+func (c *Connection) openBlockWindow(x, y, z int) (bool, error) {
+	if !c.withinReach(x, y, z) {
+		return false, nil
+	}
+	pos := world.BlockPos{X: x, Y: y, Z: z}
+	switch c.world.GetBlock(x, y, z) >> 4 {
+	case blockChest:
+		return true, c.openChest(pos)
+	case blockCraftingTable:
+		return true, c.openCraftingTable()
+	case blockFurnace, blockLitFurnace:
+		return true, c.openFurnace(pos)
+	default:
+		return false, nil
+	}
+}
+
+// openCraftingTable opens a 3x3 crafting window. Its grid belongs to the
+// connection and is emptied back into the inventory when closed.
+func (c *Connection) openCraftingTable() error {
+	c.closeContainer()
+
+	windowID := c.nextWindowID()
+	if !c.openWindow(windowID, windowTypeCraftingTable) {
+		return nil
+	}
+	if err := c.sendOpenWindow(windowID, windowTypeCraftingTable, "container.crafting", 0); err != nil {
+		return err
+	}
+	return c.sendWindowItems()
+}
+
+// openChest opens the chest at pos. Its inventory is shared with every
+// other player who has the same chest open.
+func (c *Connection) openChest(pos world.BlockPos) error {
+	ct := c.players.Container(pos, chestSize)
+	return c.showContainer(windowTypeChest, "container.chest", ct, nil)
+}
+
+// openFurnace opens the furnace at pos and keeps its fire and progress
+// arrows updated while it is open.
+func (c *Connection) openFurnace(pos world.BlockPos) error {
+	f := c.players.Furnace(pos)
+	return c.showContainer(windowTypeFurnace, "container.furnace", f.Container, f)
+}
+
+// showContainer opens a new window showing ct, closing any container that
+// was open before. f is the furnace ct belongs to, if any.
+func (c *Connection) showContainer(windowType, title string, ct *player.Container, f *player.Furnace) error {
+	c.closeContainer()
+
+	windowID := c.nextWindowID()
+	if !c.openContainer(windowID, windowType, ct.Size()) {
+		return nil
+	}
+	c.container = ct
+	ct.AddViewer(c.self.EntityID, func(index int, item player.Slot) {
+		_ = c.sendSetSlot(int8(windowID), int16(index), item)
+	}, func() {
+		// The block was broken. The connection notices on the next click.
+		_ = c.writePacket(&pkt.CloseWindowCB{WindowID: windowID})
+	})
+	if f != nil {
+		c.furnace = f
+		f.AddPropertyViewer(c.self.EntityID, func(property, value int16) {
+			_ = c.sendWindowProperty(windowID, property, value)
+		})
+	}
+
+	if err := c.sendOpenWindow(windowID, windowType, title, ct.Size()); err != nil {
+		return err
+	}
+	if err := c.sendWindowItems(); err != nil {
+		return err
+	}
+	if f != nil {
+		for property, value := range f.Properties() {
+			if err := c.sendWindowProperty(windowID, int16(property), value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sendOpenWindow tells the client to show a window. title is a translation
+// key and slots the number of slots the window has of its own.
+func (c *Connection) sendOpenWindow(windowID uint8, windowType, title string, slots int) error {
+	var buf bytes.Buffer
+	buf.WriteByte(windowID)
+	_, _ = mcnet.WriteString(&buf, windowType)
+	_, _ = mcnet.WriteString(&buf, `{"translate":"`+title+`"}`)
+	buf.WriteByte(byte(slots))
+	return c.writePacket(&pkt.OpenWindow{Data: buf.Bytes()})
+}
+
+// sendWindowProperty sends a CraftProgressBar update for a window property.
+func (c *Connection) sendWindowProperty(windowID uint8, property, value int16) error {
+	return c.writePacket(&pkt.CraftProgressBar{
+		WindowID: windowID,
+		Property: property,
+		Value:    value,
+	})
+}
+
+// closeContainer stops showing the open container, if any, so changes made
+// by other players or the furnace are no longer sent to this connection.
+func (c *Connection) closeContainer() {
+	if c.container == nil {
+		return
+	}
+	c.container.RemoveViewer(c.self.EntityID)
+	c.container = nil
+	if c.furnace != nil {
+		c.furnace.RemovePropertyViewer(c.self.EntityID)
+		c.furnace = nil
+	}
+}
+
+// isContainerSlot reports whether a window slot belongs to the open
+// container. Container slots come first in the window.
+func (c *Connection) isContainerSlot(s int16) bool {
+	return c.container != nil && s >= 0 && int(s) < c.container.Size()
+}
+
+// isFurnaceResult reports whether a window slot is the result slot of an
+// open furnace, which items can be taken from but not put into.
+func (c *Connection) isFurnaceResult(s int16) bool {
+	return c.furnace != nil && s == player.FurnaceResult
+}
+
+// takeFromResult moves items from a take-only slot onto the cursor: the
+// whole stack, or half of it with a right click on an empty cursor. A
+// cursor holding something else takes nothing.
+func (c *Connection) takeFromResult(slot int16, button int8) {
+	current := c.getWindowSlot(slot)
+	if current.IsEmpty() {
+		return
+	}
+	take := int(current.ItemCount)
+	switch {
+	case c.cursorSlot.IsEmpty():
+		if button != 0 {
+			take = (take + 1) / 2
+		}
+	case canStack(c.cursorSlot, current):
+		take = min(take, c.maxStackSize(current.BlockID)-int(c.cursorSlot.ItemCount))
+	default:
+		return
+	}
+	if take <= 0 {
+		return
+	}
+
+	if c.cursorSlot.IsEmpty() {
+		c.cursorSlot = current
+		c.cursorSlot.ItemCount = int8(take)
+	} else {
+		c.cursorSlot.ItemCount += int8(take)
+	}
+	current.ItemCount -= int8(take)
+	if current.ItemCount <= 0 {
+		current = player.EmptySlot
+	}
+	c.setWindowSlot(slot, current)
+}
+
+// containerTarget returns the container slots [lo, hi] that a shift-click
+// moves item into from the player inventory. A furnace takes smeltable items
+// as input and fuel into its fuel slot, and nothing into its result slot.
+func (c *Connection) containerTarget(item player.Slot) (lo, hi int16, ok bool) {
+	if c.furnace == nil {
+		return 0, int16(c.container.Size()) - 1, true
+	}
+	if _, smeltable := player.SmeltingResult(item); smeltable {
+		return player.FurnaceInput, player.FurnaceInput, true
+	}
+	if player.FuelTicks(item.BlockID) > 0 {
+		return player.FurnaceFuel, player.FurnaceFuel, true
+	}
+	return 0, 0, false
+}
+
+// dropContainer spills the contents of the container block at x, y, z, for
+// when it is broken with the given state.
+func (c *Connection) dropContainer(x, y, z int, state int32) {
+	pos := world.BlockPos{X: x, Y: y, Z: z}
+	var items []player.Slot
+	switch state >> 4 {
+	case blockChest:
+		items = c.players.RemoveContainer(pos)
+	case blockFurnace, blockLitFurnace:
+		items = c.players.RemoveFurnace(pos)
+	}
+	if len(items) == 0 {
+		return
+	}
+	groundY := c.findGroundLevel(x, y, z)
+	for _, item := range items {
+		c.players.SpawnBlockDrop(item, float64(x)+0.5, float64(groundY)+0.1, float64(z)+0.5, float64(y)+0.5)
+	}
+}
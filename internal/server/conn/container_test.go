@@ -0,0 +1,280 @@
+package conn
+
+import (
+	"testing"
+
+	"github.com/go-theft-craft/server/internal/server/packet"
+	"github.com/go-theft-craft/server/internal/server/player"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	"github.com/go-theft-craft/server/pkg/world"
+)
+
+// newChestTestConn returns a connection with a cleared inventory and a chest
+// at 2, 4, 2.
+func newChestTestConn(t *testing.T) *Connection {
+	t.Helper()
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	c.world.SetBlock(2, 4, 2, blockChest<<4)
+	return c
+}
+
+func TestBlockPlace_OpensChest(t *testing.T) {
+	c := newChestTestConn(t)
+
+	if err := c.handleBlockPlace(blockPlaceData(2, 4, 2, 1, -1)); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+
+	if c.windowID == 0 || c.container == nil {
+		t.Fatalf("window %d container %v, want an open chest", c.windowID, c.container)
+	}
+	if c.window.mainStart != chestSize {
+		t.Errorf("mainStart = %d, want %d", c.window.mainStart, chestSize)
+	}
+	if n := countPackets(t, c, pkt.OpenWindow{}.PacketID()); n != 1 {
+		t.Errorf("sent %d OpenWindow packets, want 1", n)
+	}
+	if got := c.world.GetBlock(2, 5, 2); got != 0 {
+		t.Errorf("block above chest = %d, want nothing placed", got)
+	}
+}
+
+func TestChest_StoreAndRetrieveThroughClicks(t *testing.T) {
+	c := newChestTestConn(t)
+	c.self.Inventory.SetProtocolSlot(slotMainStart, stone(20))
+	if err := c.openChest(world.BlockPos{X: 2, Y: 4, Z: 2}); err != nil {
+		t.Fatalf("openChest: %v", err)
+	}
+	id := c.windowID
+
+	// Pick up the stone from the first inventory slot and drop it into
+	// chest slot 5.
+	for _, slot := range []int16{chestSize, 5} {
+		if err := c.handleWindowClick(windowClickData(id, slot)); err != nil {
+			t.Fatalf("click %d: %v", slot, err)
+		}
+	}
+	if err := c.handleCloseWindow([]byte{id}); err != nil {
+		t.Fatalf("handleCloseWindow: %v", err)
+	}
+
+	ct := c.players.Container(world.BlockPos{X: 2, Y: 4, Z: 2}, chestSize)
+	if got := ct.Get(5); got != stone(20) {
+		t.Fatalf("chest slot 5 = %+v, want %+v", got, stone(20))
+	}
+	if n := countItems(c, 1); n != 0 {
+		t.Fatalf("inventory holds %d stone, want 0", n)
+	}
+
+	// Reopening the chest shows the stored stone; shift-click takes it back.
+	if err := c.openChest(world.BlockPos{X: 2, Y: 4, Z: 2}); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got := c.getWindowSlot(5); got != stone(20) {
+		t.Fatalf("reopened slot 5 = %+v, want %+v", got, stone(20))
+	}
+	c.dispatchClick(5, 0, 1)
+	if got := ct.Get(5); !got.IsEmpty() {
+		t.Errorf("chest slot 5 after shift-click = %+v, want empty", got)
+	}
+	if n := c.self.Inventory.GetProtocolSlot(slotMainStart); n != stone(20) {
+		t.Errorf("first inventory slot = %+v, want %+v", n, stone(20))
+	}
+}
+
+func TestChest_ShiftClickIntoFullChestMovesWhatFits(t *testing.T) {
+	c := newChestTestConn(t)
+	ct := c.players.Container(world.BlockPos{X: 2, Y: 4, Z: 2}, chestSize)
+	for i := range chestSize {
+		ct.Set(i, sword(), 0)
+	}
+	ct.Set(3, stone(60), 0)
+	c.self.Inventory.SetProtocolSlot(slotHotbarStart, stone(10))
+	if err := c.openChest(world.BlockPos{X: 2, Y: 4, Z: 2}); err != nil {
+		t.Fatalf("openChest: %v", err)
+	}
+
+	c.dispatchClick(c.window.hotbarStart, 0, 1)
+
+	if got := ct.Get(3); got != stone(64) {
+		t.Errorf("chest slot 3 = %+v, want %+v", got, stone(64))
+	}
+	if got := c.self.Inventory.GetProtocolSlot(slotHotbarStart); got != stone(6) {
+		t.Errorf("hotbar slot = %+v, want the %+v that did not fit", got, stone(6))
+	}
+}
+
+func TestBreakBlock_ChestDropsContents(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	c.self.SetGameMode(packet.GameModeCreative)
+	c.world.SetBlock(2, 4, 2, blockChest<<4)
+	pos := world.BlockPos{X: 2, Y: 4, Z: 2}
+	ct := c.players.Container(pos, chestSize)
+	ct.Set(0, dirt(5), 0)
+	ct.Set(9, stone(3), 0)
+	sp.reset()
+
+	c.breakBlock(2, 4, 2, 0)
+
+	if got := c.players.ContainerContents(); len(got) != 0 {
+		t.Errorf("containers after break = %v, want none", got)
+	}
+	spawned := 0
+	for _, p := range sp.get() {
+		if _, ok := p.(*pkt.SpawnEntity); ok {
+			spawned++
+		}
+	}
+	if spawned != 2 {
+		t.Errorf("spawned %d item entities, want one per chest stack (2)", spawned)
+	}
+}
+
+func TestBlockPlace_ChestOutOfReachStaysClosed(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	c.world.SetBlock(40, 4, 2, blockChest<<4)
+
+	if err := c.handleBlockPlace(blockPlaceData(40, 4, 2, 1, -1)); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+	if c.windowID != 0 || c.container != nil {
+		t.Errorf("window %d container %v, want the chest left closed", c.windowID, c.container)
+	}
+}
+
+func TestChest_BrokenWhileOpenCannotBeEmptied(t *testing.T) {
+	c := newChestTestConn(t)
+	pos := world.BlockPos{X: 2, Y: 4, Z: 2}
+	c.players.Container(pos, chestSize).Set(0, stone(20), 0)
+	if err := c.openChest(pos); err != nil {
+		t.Fatalf("openChest: %v", err)
+	}
+	id := c.windowID
+
+	// Another player breaks the chest and its contents drop.
+	if items := c.players.RemoveContainer(pos); len(items) != 1 {
+		t.Fatalf("RemoveContainer returned %d items, want 1", len(items))
+	}
+	if n := countPackets(t, c, pkt.CloseWindowCB{}.PacketID()); n != 1 {
+		t.Errorf("sent %d CloseWindow packets, want 1", n)
+	}
+
+	if err := c.handleWindowClick(windowClickData(id, 0)); err != nil {
+		t.Fatalf("click: %v", err)
+	}
+	if !c.cursorSlot.IsEmpty() {
+		t.Errorf("cursor = %+v, want nothing taken from the broken chest", c.cursorSlot)
+	}
+	if c.windowID != 0 || c.container != nil {
+		t.Errorf("window %d container %v, want back in the player inventory", c.windowID, c.container)
+	}
+}
+
+func TestFurnace_SmeltThroughWindow(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	c.world.SetBlock(2, 4, 2, blockFurnace<<4)
+	c.self.Inventory.SetProtocolSlot(slotMainStart, player.Slot{BlockID: 15, ItemCount: 1})
+	c.self.Inventory.SetProtocolSlot(slotHotbarStart, player.Slot{BlockID: 263, ItemCount: 1})
+
+	if err := c.handleBlockPlace(blockPlaceData(2, 4, 2, 1, -1)); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+	if c.furnace == nil {
+		t.Fatal("right-clicking a furnace did not open it")
+	}
+
+	// Shift-click sends the ore to the input slot and the coal to the fuel slot.
+	c.dispatchClick(c.window.mainStart, 0, 1)
+	c.dispatchClick(c.window.hotbarStart, 0, 1)
+	if got := c.getWindowSlot(player.FurnaceInput); got.BlockID != 15 {
+		t.Fatalf("input slot = %+v, want iron ore", got)
+	}
+	if got := c.getWindowSlot(player.FurnaceFuel); got.BlockID != 263 {
+		t.Fatalf("fuel slot = %+v, want coal", got)
+	}
+
+	for range 200 {
+		c.players.Tick()
+	}
+	if got := c.getWindowSlot(player.FurnaceResult); got != (player.Slot{BlockID: 265, ItemCount: 1}) {
+		t.Fatalf("result slot = %+v, want an iron ingot", got)
+	}
+	if n := countPackets(t, c, pkt.CraftProgressBar{}.PacketID()); n == 0 {
+		t.Error("no CraftProgressBar updates sent while smelting")
+	}
+
+	c.dispatchClick(player.FurnaceResult, 0, 1)
+	if err := c.handleCloseWindow([]byte{c.windowID}); err != nil {
+		t.Fatalf("handleCloseWindow: %v", err)
+	}
+	if n := countItems(c, 265); n != 1 {
+		t.Errorf("inventory holds %d iron ingots, want 1", n)
+	}
+}
+
+func TestFurnace_ResultSlotIsTakeOnly(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	pos := world.BlockPos{X: 2, Y: 4, Z: 2}
+	c.world.SetBlock(2, 4, 2, blockFurnace<<4)
+	f := c.players.Furnace(pos)
+	f.Set(player.FurnaceResult, player.Slot{BlockID: 265, ItemCount: 3}, 0)
+	if err := c.openFurnace(pos); err != nil {
+		t.Fatalf("openFurnace: %v", err)
+	}
+
+	// Placing stone into the result slot is refused, by click or drag.
+	c.cursorSlot = stone(10)
+	c.dispatchClick(player.FurnaceResult, 0, 0)
+	c.dispatchClick(slotOutside, 0, 5)
+	c.dispatchClick(player.FurnaceResult, 1, 5)
+	c.dispatchClick(slotOutside, 2, 5)
+	if got := f.Get(player.FurnaceResult); got != (player.Slot{BlockID: 265, ItemCount: 3}) {
+		t.Fatalf("result slot = %+v, want the ingots untouched", got)
+	}
+	if c.cursorSlot != stone(10) {
+		t.Fatalf("cursor = %+v, want the stone kept", c.cursorSlot)
+	}
+
+	// With an empty cursor the ingots are taken.
+	c.cursorSlot = player.EmptySlot
+	c.dispatchClick(player.FurnaceResult, 0, 0)
+	if c.cursorSlot != (player.Slot{BlockID: 265, ItemCount: 3}) {
+		t.Errorf("cursor = %+v, want the ingots", c.cursorSlot)
+	}
+	if got := f.Get(player.FurnaceResult); !got.IsEmpty() {
+		t.Errorf("result slot = %+v, want empty", got)
+	}
+}
+
+func TestFurnace_BrokenWhileOpenCannotBeEmptied(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	pos := world.BlockPos{X: 2, Y: 4, Z: 2}
+	c.world.SetBlock(2, 4, 2, blockFurnace<<4)
+	c.players.Furnace(pos).Set(player.FurnaceInput, player.Slot{BlockID: 15, ItemCount: 4}, 0)
+	if err := c.openFurnace(pos); err != nil {
+		t.Fatalf("openFurnace: %v", err)
+	}
+	id := c.windowID
+
+	if items := c.players.RemoveFurnace(pos); len(items) != 1 {
+		t.Fatalf("RemoveFurnace returned %d items, want 1", len(items))
+	}
+	if n := countPackets(t, c, pkt.CloseWindowCB{}.PacketID()); n != 1 {
+		t.Errorf("sent %d CloseWindow packets, want 1", n)
+	}
+
+	if err := c.handleWindowClick(windowClickData(id, player.FurnaceInput)); err != nil {
+		t.Fatalf("click: %v", err)
+	}
+	if !c.cursorSlot.IsEmpty() {
+		t.Errorf("cursor = %+v, want nothing taken from the broken furnace", c.cursorSlot)
+	}
+	if c.windowID != 0 || c.furnace != nil {
+		t.Errorf("window %d furnace %v, want back in the player inventory", c.windowID, c.furnace)
+	}
+}
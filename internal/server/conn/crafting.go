@@ -5,18 +5,19 @@ import (
 	"github.com/go-theft-craft/server/pkg/gamedata"
 )
 
-// matchRecipe2x2 tries to match a 2x2 crafting grid against all known recipes.
-// The grid layout is: [0]=top-left, [1]=top-right, [2]=bottom-left, [3]=bottom-right.
-func matchRecipe2x2(grid [4]player.Slot, recipes gamedata.RecipeRegistry) player.Slot {
+// matchRecipe tries to match a square crafting grid of the given width
+// against all known recipes. The grid is stored row by row, starting at the
+// top-left cell.
+func matchRecipe(grid []player.Slot, width int, recipes gamedata.RecipeRegistry) player.Slot {
 	all := recipes.All()
 	for _, recipeList := range all {
 		for _, recipe := range recipeList {
 			if len(recipe.InShape) > 0 {
-				if matchShaped2x2(grid, recipe) {
+				if matchShaped(grid, width, recipe) {
 					return recipeResultToSlot(recipe.Result)
 				}
 			} else if len(recipe.Ingredients) > 0 {
-				if matchShapeless2x2(grid, recipe) {
+				if matchShapeless(grid, recipe) {
 					return recipeResultToSlot(recipe.Result)
 				}
 			}
@@ -25,11 +26,11 @@ func matchRecipe2x2(grid [4]player.Slot, recipes gamedata.RecipeRegistry) player
 	return player.EmptySlot
 }
 
-// matchShaped2x2 checks if the grid matches a shaped recipe at any valid position.
-func matchShaped2x2(grid [4]player.Slot, recipe gamedata.Recipe) bool {
+// matchShaped checks if the grid matches a shaped recipe at any valid position.
+func matchShaped(grid []player.Slot, width int, recipe gamedata.Recipe) bool {
 	shape := recipe.InShape
 	rows := len(shape)
-	if rows == 0 || rows > 2 {
+	if rows == 0 || rows > width {
 		return false
 	}
 	cols := 0
@@ -38,14 +39,14 @@ func matchShaped2x2(grid [4]player.Slot, recipe gamedata.Recipe) bool {
 			cols = len(row)
 		}
 	}
-	if cols > 2 {
+	if cols > width {
 		return false
 	}
 
-	// Try placing the shape at all valid offsets in the 2x2 grid.
-	for rowOff := 0; rowOff <= 2-rows; rowOff++ {
-		for colOff := 0; colOff <= 2-cols; colOff++ {
-			if checkShapedAt(grid, shape, rowOff, colOff) {
+	// Try placing the shape at all valid offsets in the grid.
+	for rowOff := 0; rowOff <= width-rows; rowOff++ {
+		for colOff := 0; colOff <= width-cols; colOff++ {
+			if checkShapedAt(grid, width, shape, rowOff, colOff) {
 				return true
 			}
 		}
@@ -53,9 +54,9 @@ func matchShaped2x2(grid [4]player.Slot, recipe gamedata.Recipe) bool {
 
 	// Try mirrored (horizontally flipped) shape.
 	mirrored := mirrorShape(shape)
-	for rowOff := 0; rowOff <= 2-rows; rowOff++ {
-		for colOff := 0; colOff <= 2-cols; colOff++ {
-			if checkShapedAt(grid, mirrored, rowOff, colOff) {
+	for rowOff := 0; rowOff <= width-rows; rowOff++ {
+		for colOff := 0; colOff <= width-cols; colOff++ {
+			if checkShapedAt(grid, width, mirrored, rowOff, colOff) {
 				return true
 			}
 		}
@@ -64,11 +65,11 @@ func matchShaped2x2(grid [4]player.Slot, recipe gamedata.Recipe) bool {
 	return false
 }
 
-// checkShapedAt checks if the shape matches at the given offset in the 2x2 grid.
-func checkShapedAt(grid [4]player.Slot, shape [][]gamedata.Ingredient, rowOff, colOff int) bool {
-	for r := 0; r < 2; r++ {
-		for c := 0; c < 2; c++ {
-			gridSlot := grid[r*2+c]
+// checkShapedAt checks if the shape matches at the given offset in the grid.
+func checkShapedAt(grid []player.Slot, width int, shape [][]gamedata.Ingredient, rowOff, colOff int) bool {
+	for r := 0; r < width; r++ {
+		for c := 0; c < width; c++ {
+			gridSlot := grid[r*width+c]
 			shapeR := r - rowOff
 			shapeC := c - colOff
 
@@ -113,10 +114,12 @@ func mirrorShape(shape [][]gamedata.Ingredient) [][]gamedata.Ingredient {
 	return mirrored
 }
 
-// matchShapeless2x2 checks if the grid contains exactly the required ingredients
-// (in any order) for a shapeless recipe.
-func matchShapeless2x2(grid [4]player.Slot, recipe gamedata.Recipe) bool {
-	if len(recipe.Ingredients) > 4 {
+// matchShapeless checks if the grid contains exactly the required ingredients
+// (in any order) for a shapeless recipe: every ingredient matches one grid
+// item and no grid item is left over. An ingredient metadata of -1 matches
+// any damage value.
+func matchShapeless(grid []player.Slot, recipe gamedata.Recipe) bool {
+	if len(recipe.Ingredients) > len(grid) {
 		return false
 	}
 
@@ -132,9 +135,22 @@ func matchShapeless2x2(grid [4]player.Slot, recipe gamedata.Recipe) bool {
 		return false
 	}
 
-	// Try to match each ingredient to a grid item.
-	used := make([]bool, len(gridItems))
+	// Match exact ingredients before wildcards, so that a wildcard never
+	// takes the only item an exact ingredient could use.
+	ingredients := make([]gamedata.Ingredient, 0, len(recipe.Ingredients))
+	for _, ing := range recipe.Ingredients {
+		if ing.Metadata >= 0 {
+			ingredients = append(ingredients, ing)
+		}
+	}
 	for _, ing := range recipe.Ingredients {
+		if ing.Metadata < 0 {
+			ingredients = append(ingredients, ing)
+		}
+	}
+
+	used := make([]bool, len(gridItems))
+	for _, ing := range ingredients {
 		found := false
 		for j, gs := range gridItems {
 			if used[j] {
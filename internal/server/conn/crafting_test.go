@@ -0,0 +1,45 @@
+package conn
+
+import (
+	"testing"
+
+	"github.com/go-theft-craft/server/internal/server/player"
+	"github.com/go-theft-craft/server/pkg/gamedata"
+)
+
+func TestMatchShapeless(t *testing.T) {
+	// Any wool plus white wool plus a stick.
+	recipe := gamedata.Recipe{
+		Ingredients: []gamedata.Ingredient{
+			{ID: 35, Metadata: -1},
+			{ID: 35, Metadata: 0},
+			{ID: 280, Metadata: 0},
+		},
+	}
+	white := player.Slot{BlockID: 35, ItemCount: 1}
+	red := player.Slot{BlockID: 35, ItemCount: 1, ItemDamage: 14}
+	stick := player.Slot{BlockID: 280, ItemCount: 1}
+	empty := player.EmptySlot
+
+	tests := []struct {
+		name string
+		grid []player.Slot
+		want bool
+	}{
+		{"white wool before red", []player.Slot{white, red, stick, empty}, true},
+		{"scattered", []player.Slot{empty, stick, empty, empty, red, empty, empty, empty, white}, true},
+		{"red wool before white", []player.Slot{red, white, stick, empty}, true},
+		{"two white wool", []player.Slot{white, white, stick, empty}, true},
+		{"missing exact ingredient", []player.Slot{red, red, stick, empty}, false},
+		{"missing item", []player.Slot{white, stick, empty, empty}, false},
+		{"extra item", []player.Slot{white, red, stick, stick}, false},
+		{"empty grid", []player.Slot{empty, empty, empty, empty}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchShapeless(tt.grid, recipe); got != tt.want {
+				t.Errorf("matchShapeless = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package conn
+
+import (
+	"github.com/go-theft-craft/server/internal/server/packet"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+)
+
+// Block IDs of wooden doors, trapdoors and fence gates. Iron doors and iron
+// trapdoors only open with redstone, so right-clicking them does nothing.
+var (
+	doorBlocks      = map[int32]bool{64: true, 193: true, 194: true, 195: true, 196: true, 197: true}
+	trapdoorBlocks  = map[int32]bool{96: true}
+	fenceGateBlocks = map[int32]bool{107: true, 183: true, 184: true, 185: true, 186: true, 187: true}
+)
+
+// Metadata bits of openable blocks. A door keeps its open bit in the lower
+// half; the upper half holds the hinge side instead.
+const (
+	metaOpen      = 0x4
+	metaDoorUpper = 0x8
+)
+
+// World events playing the open and close sounds.
+const (
+	eventDoorOpen  = 1003
+	eventDoorClose = 1006
+)
+
+// toggleBlock opens or closes the wooden door, trapdoor or fence gate at
+// (x, y, z), broadcasting the change and its sound. It returns false if the
+// block cannot be opened by hand, is out of the player's reach or in a chunk
+// that is not loaded, or the player is a spectator.
+func (c *Connection) toggleBlock(x, y, z int) bool {
+	if !c.withinReach(x, y, z) {
+		return false
+	}
+	state, ok := c.world.LoadedBlock(x, y, z)
+	if !ok {
+		return false
+	}
+	id := state >> 4
+
+	switch {
+	case doorBlocks[id]:
+		// The open bit lives in the lower half, whichever half was clicked.
+		if state&metaDoorUpper != 0 {
+			y--
+			state, _ = c.world.LoadedBlock(x, y, z)
+			if state>>4 != id || state&metaDoorUpper != 0 {
+				return false
+			}
+		}
+	case trapdoorBlocks[id], fenceGateBlocks[id]:
+	default:
+		return false
+	}
+
+	if c.self.GetGameMode() == packet.GameModeSpectator {
+		return false
+	}
+
+	state ^= metaOpen
+	c.world.SetBlock(x, y, z, state)
+	c.sendBlockChange(x, y, z, state)
+	if doorBlocks[id] {
+		// Resend the upper half so clients redraw the whole door.
+		upper, _ := c.world.LoadedBlock(x, y+1, z)
+		c.sendBlockChange(x, y+1, z, upper)
+	}
+
+	event := int32(eventDoorClose)
+	if state&metaOpen != 0 {
+		event = eventDoorOpen
+	}
+	c.players.BroadcastToTrackers(&pkt.WorldEvent{
+		EffectID: event,
+		Location: mcnet.EncodePosition(x, y, z),
+	}, c.self.EntityID)
+	return true
+}
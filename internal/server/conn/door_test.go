@@ -0,0 +1,123 @@
+package conn
+
+import (
+	"testing"
+
+	"github.com/go-theft-craft/server/internal/server/packet"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+)
+
+// placeDoor puts a closed oak door facing east with its lower half at (x, y, z).
+func placeDoor(c *Connection, x, y, z int) {
+	c.world.SetBlock(x, y, z, 64<<4)
+	c.world.SetBlock(x, y+1, z, 64<<4|metaDoorUpper)
+}
+
+func TestToggleDoor_FromEitherHalf(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	placeDoor(c, 2, 4, 2)
+	sp.reset()
+
+	if err := c.handleBlockPlace(blockPlaceData(2, 4, 2, 4, -1)); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+	if got := c.world.GetBlock(2, 4, 2); got != 64<<4|metaOpen {
+		t.Errorf("lower half = %#x, want open", got)
+	}
+	if got := c.world.GetBlock(2, 5, 2); got != 64<<4|metaDoorUpper {
+		t.Errorf("upper half = %#x, want unchanged", got)
+	}
+
+	changed := map[int64]int32{}
+	for _, p := range sp.get() {
+		if bc, ok := p.(*pkt.BlockChange); ok {
+			changed[bc.Location] = bc.Type
+		}
+	}
+	if changed[mcnet.EncodePosition(2, 4, 2)] != 64<<4|metaOpen {
+		t.Error("expected a block change opening the lower half")
+	}
+	if _, ok := changed[mcnet.EncodePosition(2, 5, 2)]; !ok {
+		t.Error("expected the upper half to be resent")
+	}
+
+	// Clicking the upper half closes the door again.
+	if err := c.handleBlockPlace(blockPlaceData(2, 5, 2, 4, -1)); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+	if got := c.world.GetBlock(2, 4, 2); got != 64<<4 {
+		t.Errorf("lower half = %#x, want closed", got)
+	}
+}
+
+func TestToggleDoor_PlaysSoundForOthers(t *testing.T) {
+	c, _, m := newTestConn("Alice")
+	bob, bobPackets := addTestPlayer(m, "Bob")
+	bob.Track(c.self.EntityID)
+	placeDoor(c, 2, 4, 2)
+
+	if err := c.handleBlockPlace(blockPlaceData(2, 4, 2, 4, -1)); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+	var event *pkt.WorldEvent
+	for _, p := range bobPackets.get() {
+		if e, ok := p.(*pkt.WorldEvent); ok {
+			event = e
+		}
+	}
+	if event == nil || event.EffectID != eventDoorOpen {
+		t.Fatalf("Bob got event %+v, want the door open sound", event)
+	}
+}
+
+func TestToggleTrapdoorAndFenceGate(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.world.SetBlock(1, 4, 1, 96<<4|2)
+	c.world.SetBlock(3, 4, 1, 107<<4|1)
+
+	for _, x := range []int{1, 3} {
+		if err := c.handleBlockPlace(blockPlaceData(x, 4, 1, 1, -1)); err != nil {
+			t.Fatalf("handleBlockPlace: %v", err)
+		}
+	}
+	if got := c.world.GetBlock(1, 4, 1); got != 96<<4|2|metaOpen {
+		t.Errorf("trapdoor = %#x, want open with its facing kept", got)
+	}
+	if got := c.world.GetBlock(3, 4, 1); got != 107<<4|1|metaOpen {
+		t.Errorf("fence gate = %#x, want open with its facing kept", got)
+	}
+}
+
+func TestToggleDoor_IronAndSpectator(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.world.SetBlock(2, 4, 2, 71<<4) // iron door
+	placeDoor(c, 4, 4, 2)
+
+	if err := c.handleBlockPlace(blockPlaceData(2, 4, 2, 4, -1)); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+	if got := c.world.GetBlock(2, 4, 2); got != 71<<4 {
+		t.Errorf("iron door = %#x, want it to stay closed", got)
+	}
+
+	c.self.SetGameMode(packet.GameModeSpectator)
+	if err := c.handleBlockPlace(blockPlaceData(4, 4, 2, 4, -1)); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+	if got := c.world.GetBlock(4, 4, 2); got != 64<<4 {
+		t.Errorf("door = %#x, want spectators unable to open it", got)
+	}
+}
+
+func TestToggleDoor_OutOfReach(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	placeDoor(c, 10, 4, 2)
+
+	if err := c.handleBlockPlace(blockPlaceData(10, 4, 2, 4, -1)); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+	if got := c.world.GetBlock(10, 4, 2); got != 64<<4 {
+		t.Errorf("door out of reach = %#x, want closed", got)
+	}
+}
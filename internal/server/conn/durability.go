@@ -0,0 +1,78 @@
+package conn
+
+import (
+	"strings"
+
+	"github.com/go-theft-craft/server/internal/server/packet"
+	"github.com/go-theft-craft/server/internal/server/player"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+)
+
+// toolWear returns how much durability the named item loses for one use.
+// Swords are made for fighting and wear twice as fast when mining; digging
+// tools wear twice as fast when used as a weapon. Other items do not wear
+// from either.
+func toolWear(name string, attack bool) int {
+	switch {
+	case strings.HasSuffix(name, "_sword"):
+		if attack {
+			return 1
+		}
+		return 2
+	case strings.HasSuffix(name, "_pickaxe"), strings.HasSuffix(name, "_axe"), strings.HasSuffix(name, "_shovel"):
+		if attack {
+			return 2
+		}
+		return 1
+	case name == "shears" && !attack:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// wearHeldItem uses up durability of the held tool after mining a block or,
+// if attack is set, hitting an entity. A tool worn past its maximum
+// durability breaks. Creative players' tools do not wear.
+func (c *Connection) wearHeldItem(attack bool) {
+	if c.gameData == nil || c.gameData.Items == nil || c.self.GetGameMode() == packet.GameModeCreative {
+		return
+	}
+	heldIdx := int16(slotHotbarStart) + c.self.Inventory.GetHeldSlot()
+	held := c.self.Inventory.GetProtocolSlot(int(heldIdx))
+	if held.IsEmpty() {
+		return
+	}
+	item, ok := c.gameData.Items.ByID(int(held.BlockID))
+	if !ok || item.MaxDurability == 0 {
+		return
+	}
+	wear := toolWear(item.Name, attack)
+	if wear == 0 {
+		return
+	}
+
+	held.ItemDamage += int16(wear)
+	if int(held.ItemDamage) > item.MaxDurability {
+		held = player.EmptySlot
+		c.playItemBreak()
+	}
+	c.setInventorySlot(heldIdx, held)
+	_ = c.sendSetSlot(0, heldIdx, held)
+}
+
+// playItemBreak plays the item break sound at the player for them and
+// everyone tracking them.
+func (c *Connection) playItemBreak() {
+	pos := c.self.GetPosition()
+	sound := &pkt.NamedSoundEffect{
+		SoundName: "random.break",
+		X:         int32(pos.X * 8),
+		Y:         int32(pos.Y * 8),
+		Z:         int32(pos.Z * 8),
+		Volume:    0.8,
+		Pitch:     63,
+	}
+	_ = c.writePacket(sound)
+	c.players.BroadcastToTrackers(sound, c.self.EntityID)
+}
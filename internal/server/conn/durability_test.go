@@ -0,0 +1,99 @@
+package conn
+
+import (
+	"testing"
+
+	"github.com/go-theft-craft/server/internal/server/packet"
+	"github.com/go-theft-craft/server/internal/server/player"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+)
+
+const itemWoodenPickaxe = 270 // max durability 59
+
+// newDurabilityTestConn returns a survival player holding item in hotbar
+// slot 0.
+func newDurabilityTestConn(item player.Slot) *Connection {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	c.self.SetGameMode(packet.GameModeSurvival)
+	c.self.Inventory.SetProtocolSlot(slotHotbarStart, item)
+	return c
+}
+
+func TestBreakBlock_WearsAndBreaksPickaxe(t *testing.T) {
+	c := newDurabilityTestConn(player.Slot{BlockID: itemWoodenPickaxe, ItemCount: 1})
+
+	for i := range 59 {
+		c.world.SetBlock(5, 3, 5, 1<<4)
+		c.breakBlock(5, 3, 5, 0)
+		if got := c.self.Inventory.HeldItem(); got.BlockID != itemWoodenPickaxe || int(got.ItemDamage) != i+1 {
+			t.Fatalf("after %d blocks held = %+v, want a pickaxe with damage %d", i+1, got, i+1)
+		}
+	}
+
+	// Dirt is not a pickaxe block but still wears it at the normal rate.
+	c.world.SetBlock(5, 3, 5, 3<<4)
+	c.breakBlock(5, 3, 5, 0)
+	if got := c.self.Inventory.HeldItem(); !got.IsEmpty() {
+		t.Errorf("after 60 blocks held = %+v, want the pickaxe broken", got)
+	}
+}
+
+func TestBreakBlock_InstantBlocksDoNotWear(t *testing.T) {
+	c := newDurabilityTestConn(player.Slot{BlockID: itemWoodenPickaxe, ItemCount: 1})
+	c.world.SetBlock(5, 4, 5, 31<<4|1) // tall grass
+
+	c.breakBlock(5, 4, 5, 0)
+
+	if got := c.self.Inventory.HeldItem().ItemDamage; got != 0 {
+		t.Errorf("pickaxe damage = %d, want 0", got)
+	}
+}
+
+func TestBreakBlock_CreativeDoesNotWear(t *testing.T) {
+	c := newDurabilityTestConn(player.Slot{BlockID: itemWoodenPickaxe, ItemCount: 1})
+	c.self.SetGameMode(packet.GameModeCreative)
+	c.world.SetBlock(5, 3, 5, 1<<4)
+
+	c.breakBlock(5, 3, 5, 0)
+
+	if got := c.self.Inventory.HeldItem().ItemDamage; got != 0 {
+		t.Errorf("pickaxe damage = %d, want 0", got)
+	}
+}
+
+func TestToolWear(t *testing.T) {
+	tests := []struct {
+		name   string
+		attack bool
+		want   int
+	}{
+		{"diamond_sword", true, 1},
+		{"diamond_sword", false, 2},
+		{"iron_pickaxe", false, 1},
+		{"iron_pickaxe", true, 2},
+		{"stone_axe", false, 1},
+		{"golden_shovel", true, 2},
+		{"shears", false, 1},
+		{"bow", false, 0},
+		{"wooden_hoe", true, 0},
+	}
+	for _, tt := range tests {
+		if got := toolWear(tt.name, tt.attack); got != tt.want {
+			t.Errorf("toolWear(%q, %v) = %d, want %d", tt.name, tt.attack, got, tt.want)
+		}
+	}
+}
+
+func TestAttack_WearsSword(t *testing.T) {
+	c, target, _ := newCombatTest(t)
+	c.self.SetGameMode(packet.GameModeSurvival)
+
+	if err := c.handleUseEntity(attackData(target.EntityID)); err != nil {
+		t.Fatalf("handleUseEntity: %v", err)
+	}
+
+	if got := c.self.Inventory.HeldItem().ItemDamage; got != 1 {
+		t.Errorf("sword damage = %d, want 1", got)
+	}
+}
@@ -0,0 +1,52 @@
+package conn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync/atomic"
+	"syscall"
+)
+
+var (
+	// ErrClientDisconnect indicates the client closed or reset the connection.
+	ErrClientDisconnect = errors.New("client disconnected")
+
+	// ErrProtocolViolation indicates the client sent a packet that is not
+	// valid in the current connection state.
+	ErrProtocolViolation = errors.New("protocol violation")
+)
+
+// protocolViolations counts connections closed because of protocol violations
+// or malformed packets.
+var protocolViolations atomic.Int64
+
+// ProtocolViolations returns the number of connections closed because of
+// protocol violations or malformed packets since startup.
+func ProtocolViolations() int64 {
+	return protocolViolations.Load()
+}
+
+// PacketError records the state and packet ID of a packet whose handler failed.
+type PacketError struct {
+	State    State
+	PacketID int32
+	Err      error
+}
+
+func (e *PacketError) Error() string {
+	return fmt.Sprintf("packet 0x%02X in state %d: %v", e.PacketID, e.State, e.Err)
+}
+
+func (e *PacketError) Unwrap() error {
+	return e.Err
+}
+
+// isDisconnectErr reports whether a read error means the peer went away.
+func isDisconnectErr(err error) bool {
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
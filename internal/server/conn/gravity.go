@@ -0,0 +1,116 @@
+package conn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+
+	"github.com/go-theft-craft/server/internal/server/player"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+)
+
+// Block IDs that fall when unsupported.
+const (
+	blockSand   = 12
+	blockGravel = 13
+)
+
+// maxFallingBlocks bounds how many blocks one block update can drop, so a
+// tall column cannot stall the connection.
+const maxFallingBlocks = 64
+
+// objectFallingBlock is the SpawnEntity object type of a falling block.
+const objectFallingBlock = 70
+
+// hasGravity reports whether a block state is sand or gravel.
+func hasGravity(state int32) bool {
+	id := state >> 4
+	return id == blockSand || id == blockGravel
+}
+
+// applyGravity drops unsupported sand and gravel in the column at (x, z),
+// starting at y and working upward through the stack of falling blocks. It
+// stops at the first air gap or block that does not fall, so sand resting
+// higher up in the column is left alone until something next to it changes.
+// Each falling block is moved to its landing position in the world right
+// away; clients see a falling block entity that is replaced by the landed
+// block once it would have hit the ground.
+func (c *Connection) applyGravity(x, y, z int) {
+	dropped := 0
+	for ; y <= 255 && dropped < maxFallingBlocks; y++ {
+		state := c.world.GetBlock(x, y, z)
+		if !hasGravity(state) {
+			return
+		}
+		if y == 0 || c.world.GetBlock(x, y-1, z) != 0 {
+			return
+		}
+
+		landY := c.findGroundLevel(x, y, z)
+		c.world.SetBlock(x, y, z, 0)
+		c.world.SetBlock(x, landY, z, state)
+		c.sendBlockChange(x, y, z, 0)
+		c.spawnFallingBlock(x, y, z, landY, state)
+		dropped++
+	}
+}
+
+// spawnFallingBlock shows a block falling from y to landY in the column at
+// (x, z), then replaces the entity with the landed block.
+func (c *Connection) spawnFallingBlock(x, y, z, landY int, state int32) {
+	entityID := c.players.AllocateEntityID()
+	c.broadcastInView(x>>4, z>>4, &pkt.SpawnEntity{Data: buildFallingBlockData(entityID, x, y, z, state)})
+
+	destroy := &pkt.EntityDestroy{EntityIDs: []int32{entityID}}
+	time.AfterFunc(fallDuration(float64(y-landY)), func() {
+		c.broadcastInView(x>>4, z>>4, destroy)
+		c.sendBlockChange(x, landY, z, c.world.GetBlock(x, landY, z))
+	})
+}
+
+// broadcastInView sends p to every player in view of chunk (cx, cz).
+func (c *Connection) broadcastInView(cx, cz int, p mcnet.Packet) {
+	c.players.ForEach(func(pl *player.Player) {
+		if player.InViewDistance(cx, cz, pl.ChunkX(), pl.ChunkZ(), c.cfg.ViewDistance) {
+			_ = pl.WritePacket(p)
+		}
+	})
+}
+
+// fallDuration estimates how long an entity takes to fall the given number
+// of blocks, using vanilla entity gravity and drag.
+func fallDuration(blocks float64) time.Duration {
+	const (
+		gravity  = 0.04
+		drag     = 0.98
+		maxTicks = 200
+	)
+	var fallen, vy float64
+	ticks := 0
+	for fallen < blocks && ticks < maxTicks {
+		vy = (vy + gravity) * drag
+		fallen += vy
+		ticks++
+	}
+	return time.Duration(ticks) * 50 * time.Millisecond
+}
+
+// buildFallingBlockData encodes the SpawnEntity data for a falling block at
+// the center of block (x, y, z). The object data carries the block ID and
+// metadata; a non-zero value is followed by a velocity.
+func buildFallingBlockData(entityID int32, x, y, z int, state int32) []byte {
+	var buf bytes.Buffer
+
+	_, _ = mcnet.WriteVarInt(&buf, entityID)
+	_ = binary.Write(&buf, binary.BigEndian, int8(objectFallingBlock))
+	_ = binary.Write(&buf, binary.BigEndian, player.FixedPoint(float64(x)+0.5))
+	_ = binary.Write(&buf, binary.BigEndian, player.FixedPoint(float64(y)))
+	_ = binary.Write(&buf, binary.BigEndian, player.FixedPoint(float64(z)+0.5))
+	_ = binary.Write(&buf, binary.BigEndian, int8(0)) // pitch
+	_ = binary.Write(&buf, binary.BigEndian, int8(0)) // yaw
+	_ = binary.Write(&buf, binary.BigEndian, state>>4|(state&0xF)<<12)
+	_ = binary.Write(&buf, binary.BigEndian, [3]int16{}) // velocity
+
+	return buf.Bytes()
+}
@@ -0,0 +1,95 @@
+package conn
+
+import (
+	"testing"
+	"time"
+
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+)
+
+func TestBreakBlock_SandColumnCollapses(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	ground := c.findGroundLevel(5, 20, 5)
+	c.world.SetBlock(5, 10, 5, 3<<4) // dirt support
+	for y := 11; y <= 13; y++ {
+		c.world.SetBlock(5, y, 5, blockSand<<4)
+	}
+	c.world.SetBlock(5, 14, 5, blockGravel<<4)
+
+	c.breakBlock(5, 10, 5, mcnet.EncodePosition(5, 10, 5))
+
+	for i, want := range []int32{blockSand << 4, blockSand << 4, blockSand << 4, blockGravel << 4} {
+		if got := c.world.GetBlock(5, ground+i, 5); got != want {
+			t.Errorf("GetBlock(5, %d, 5) = %d, want %d", ground+i, got, want)
+		}
+	}
+	for y := ground + 4; y <= 14; y++ {
+		if got := c.world.GetBlock(5, y, 5); got != 0 {
+			t.Errorf("GetBlock(5, %d, 5) = %d, want air", y, got)
+		}
+	}
+
+	spawned := 0
+	for _, p := range sp.get() {
+		if _, ok := p.(*pkt.SpawnEntity); ok {
+			spawned++
+		}
+	}
+	if spawned != 4 {
+		t.Errorf("spawned %d falling blocks, want 4", spawned)
+	}
+}
+
+func TestApplyGravity_SupportedSandStays(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.world.SetBlock(5, 10, 5, 1<<4)
+	c.world.SetBlock(5, 11, 5, blockSand<<4)
+
+	c.applyGravity(5, 10, 5)
+
+	if got := c.world.GetBlock(5, 11, 5); got != blockSand<<4 {
+		t.Errorf("supported sand moved: GetBlock = %d", got)
+	}
+}
+
+func TestApplyGravity_StopsAtNonFallingBlock(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	// Stone floating above air holds up the sand on top of it.
+	c.world.SetBlock(5, 12, 5, 1<<4)
+	c.world.SetBlock(5, 13, 5, blockSand<<4)
+
+	c.applyGravity(5, 11, 5)
+
+	if got := c.world.GetBlock(5, 13, 5); got != blockSand<<4 {
+		t.Errorf("sand above a non-falling block moved: GetBlock = %d", got)
+	}
+}
+
+func TestBreakBlock_FloatingSandHigherUpStays(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	ground := c.findGroundLevel(5, 20, 5)
+	c.world.SetBlock(5, 10, 5, 3<<4)
+	c.world.SetBlock(5, 11, 5, blockSand<<4)
+	// Sand left hanging above an air gap is not part of the falling stack.
+	c.world.SetBlock(5, 15, 5, blockSand<<4)
+
+	c.breakBlock(5, 10, 5, mcnet.EncodePosition(5, 10, 5))
+
+	if got := c.world.GetBlock(5, ground, 5); got != blockSand<<4 {
+		t.Errorf("GetBlock(5, %d, 5) = %d, want the sand from y=11", ground, got)
+	}
+	if got := c.world.GetBlock(5, 15, 5); got != blockSand<<4 {
+		t.Errorf("floating sand moved: GetBlock(5, 15, 5) = %d", got)
+	}
+}
+
+func TestFallDuration(t *testing.T) {
+	if d := fallDuration(0); d != 0 {
+		t.Errorf("fallDuration(0) = %v, want 0", d)
+	}
+	// Vanilla entities fall about 20 blocks in the first 1.5 seconds.
+	if d := fallDuration(20); d < time.Second || d > 2*time.Second {
+		t.Errorf("fallDuration(20) = %v, want about 1.5s", d)
+	}
+}
@@ -8,8 +8,8 @@ import (
 )
 
 func (c *Connection) handleHandshake(packetID int32, data []byte) error {
-	if packetID != 0x00 {
-		return fmt.Errorf("expected handshake packet 0x00, got 0x%02X", packetID)
+	if packetID != pkt.HandshakingServerboundSetProtocol {
+		return fmt.Errorf("%w: expected handshake packet 0x00, got 0x%02X", ErrProtocolViolation, packetID)
 	}
 
 	var hs pkt.SetProtocol
@@ -33,7 +33,7 @@ func (c *Connection) handleHandshake(packetID int32, data []byte) error {
 		}
 		c.state = StateLogin
 	default:
-		return fmt.Errorf("invalid next state: %d", hs.NextState)
+		return fmt.Errorf("%w: invalid next state: %d", ErrProtocolViolation, hs.NextState)
 	}
 
 	return nil
@@ -5,20 +5,22 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"fmt"
+	"strings"
 
 	"github.com/go-theft-craft/server/internal/server/player"
+	"github.com/go-theft-craft/server/internal/server/storage"
 	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
 	mcnet "github.com/go-theft-craft/server/pkg/protocol"
 )
 
 func (c *Connection) handleLogin(packetID int32, data []byte) error {
 	switch packetID {
-	case 0x00: // LoginStart
+	case pkt.LoginServerboundLoginStart:
 		return c.handleLoginStart(data)
-	case 0x01: // EncryptionResponse
+	case pkt.LoginServerboundEncryptionBegin:
 		return c.handleEncryptionResponse(data)
 	default:
-		return fmt.Errorf("unexpected login packet 0x%02X", packetID)
+		return fmt.Errorf("%w: unexpected login packet 0x%02X", ErrProtocolViolation, packetID)
 	}
 }
 
@@ -41,8 +43,16 @@ func (c *Connection) handleOfflineLogin(username string) error {
 	uuid := offlineUUID(username)
 	uuidStr := formatUUID(uuid)
 
+	if !c.admit(uuidStr, username) {
+		return nil
+	}
+
 	c.log.Info("offline login success", "username", username, "uuid", uuidStr)
 
+	if err := c.enableCompression(); err != nil {
+		return err
+	}
+
 	if err := c.writePacket(&pkt.Success{
 		UUID:     uuidStr,
 		Username: username,
@@ -104,11 +114,11 @@ func (c *Connection) handleEncryptionResponse(data []byte) error {
 	}
 
 	if len(verifyToken) != len(c.loginVerifyToken) {
-		return fmt.Errorf("verify token length mismatch")
+		return fmt.Errorf("%w: verify token length mismatch", ErrProtocolViolation)
 	}
 	for i := range verifyToken {
 		if verifyToken[i] != c.loginVerifyToken[i] {
-			return fmt.Errorf("verify token mismatch")
+			return fmt.Errorf("%w: verify token mismatch", ErrProtocolViolation)
 		}
 	}
 
@@ -122,16 +132,22 @@ func (c *Connection) handleEncryptionResponse(data []byte) error {
 	serverHash := minecraftSHA1HexDigest("", sharedSecret, c.cfg.PublicKeyDER)
 	profile, err := verifyWithMojang(c.ctx, c.loginUsername, serverHash)
 	if err != nil {
-		reason := `{"text":"Failed to verify with Mojang."}`
-		_ = c.writePacket(&pkt.Disconnect{Reason: reason})
-		c.disconnect("mojang auth failed")
+		c.rejectLogin(kickAuthFailed, "")
 		return fmt.Errorf("mojang verify: %w", err)
 	}
 
 	uuidStr := formatMojangUUID(profile.ID)
 
+	if !c.admit(uuidStr, profile.Name) {
+		return nil
+	}
+
 	c.log.Info("online login success", "username", profile.Name, "uuid", uuidStr)
 
+	if err := c.enableCompression(); err != nil {
+		return err
+	}
+
 	if err := c.writePacket(&pkt.Success{
 		UUID:     uuidStr,
 		Username: profile.Name,
@@ -152,6 +168,65 @@ func (c *Connection) handleEncryptionResponse(data []byte) error {
 	return c.startPlay(profile.Name, uuidStr, skinProps)
 }
 
+// admit checks the ban list and whitelist for a logging-in player. Refused
+// players are sent a Disconnect and admit returns false.
+func (c *Connection) admit(uuid, username string) bool {
+	ban, ok, err := c.banned(uuid, username)
+	if err != nil {
+		c.log.Error("load ban list", "error", err)
+		c.rejectLogin(kickUnavailable, "")
+		return false
+	}
+	if ok {
+		c.rejectLogin(kickBanned, c.banMessage(ban))
+		return false
+	}
+	if !c.whitelisted(username) {
+		c.rejectLogin(kickWhitelist, "")
+		return false
+	}
+	return true
+}
+
+// banned returns the ban that applies to the player, if any. The ban list is
+// read once and then served from the storage cache, which /ban and /pardon
+// keep current.
+func (c *Connection) banned(uuid, username string) (storage.BanEntry, bool, error) {
+	if c.storage == nil {
+		return storage.BanEntry{}, false, nil
+	}
+	bans, err := c.storage.Bans()
+	if err != nil {
+		return storage.BanEntry{}, false, err
+	}
+	ban, ok := storage.FindBan(bans, uuid, username)
+	return ban, ok, nil
+}
+
+// banMessage returns the disconnect text for a ban, including its reason.
+func (c *Connection) banMessage(ban storage.BanEntry) string {
+	text := c.kickMessageFor(kickBanned).text
+	if ban.Reason != "" {
+		text += " Reason: " + ban.Reason
+	}
+	return text
+}
+
+// whitelisted reports whether username may join. Everyone may join when the
+// whitelist is disabled or empty. If the whitelist cannot be read, players
+// are turned away rather than letting everyone in.
+func (c *Connection) whitelisted(username string) bool {
+	if !c.cfg.Whitelist || c.storage == nil {
+		return true
+	}
+	names, err := c.storage.LoadWhitelist()
+	if err != nil {
+		c.log.Error("load whitelist", "error", err)
+		return false
+	}
+	return len(names) == 0 || names[strings.ToLower(username)]
+}
+
 // offlineUUID generates UUID v3 from "OfflinePlayer:<username>" using the MD5 namespace.
 func offlineUUID(username string) [16]byte {
 	h := md5.Sum([]byte("OfflinePlayer:" + username))
@@ -4,19 +4,20 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"math"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/go-theft-craft/server/internal/server/chat"
 	"github.com/go-theft-craft/server/internal/server/packet"
 	"github.com/go-theft-craft/server/internal/server/player"
 	"github.com/go-theft-craft/server/internal/server/storage"
 	"github.com/go-theft-craft/server/pkg/gamedata"
 	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
 	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+	"github.com/go-theft-craft/server/pkg/world"
 	"github.com/go-theft-craft/server/pkg/world/gen"
 )
 
@@ -26,6 +27,10 @@ func (c *Connection) startPlay(username, uuid string, skinProps []player.SkinPro
 	uuidBytes := parseUUID(uuid)
 	entityID := c.players.AllocateEntityID()
 	c.self = player.NewPlayer(entityID, uuid, uuidBytes, username, skinProps, c.writePacket)
+	c.self.JoinedAt = time.Now()
+	if c.conn != nil {
+		c.self.RemoteAddr = c.conn.RemoteAddr().String()
+	}
 
 	// Try to load saved player data.
 	var savedData *storage.PlayerData
@@ -38,8 +43,11 @@ func (c *Connection) startPlay(username, uuid string, skinProps []player.SkinPro
 	}
 
 	gameMode := uint8(packet.GameModeCreative)
-	spawnY := c.world.SpawnHeight()
-	posX, posY, posZ := 0.5, float64(spawnY), 0.5
+	if mode, _, ok := parseGameMode(c.cfg.DefaultGameMode); ok {
+		gameMode = mode
+	}
+	spawn := c.world.Spawn()
+	posX, posY, posZ := float64(spawn.X)+0.5, float64(spawn.Y), float64(spawn.Z)+0.5
 	var posYaw float32
 	var posPitch float32
 
@@ -55,10 +63,10 @@ func (c *Connection) startPlay(username, uuid string, skinProps []player.SkinPro
 		var slots [36]player.Slot
 		var armor [4]player.Slot
 		for i, s := range savedData.Inventory.Slots {
-			slots[i] = player.Slot{BlockID: s.BlockID, ItemCount: s.ItemCount, ItemDamage: s.ItemDamage}
+			slots[i] = s.Slot()
 		}
 		for i, s := range savedData.Inventory.Armor {
-			armor[i] = player.Slot{BlockID: s.BlockID, ItemCount: s.ItemCount, ItemDamage: s.ItemDamage}
+			armor[i] = s.Slot()
 		}
 
 		c.self.ApplyData(player.Position{
@@ -66,6 +74,23 @@ func (c *Connection) startPlay(username, uuid string, skinProps []player.SkinPro
 			Yaw: posYaw, Pitch: posPitch,
 		}, gameMode, slots, armor, savedData.Inventory.HeldSlot)
 
+		health, food, saturation := savedData.Vitals()
+		c.self.SetHealth(health)
+		c.self.SetFood(food, saturation)
+		c.self.SetExperience(savedData.XPLevel, savedData.XPPoints, savedData.XPTotal)
+		if h := savedData.Home; h != nil {
+			c.self.SetHome(player.Position{X: h.X, Y: h.Y, Z: h.Z, Yaw: h.Yaw, Pitch: h.Pitch})
+		}
+
+		// Terrain may have changed since the player logged out; don't
+		// place them inside a solid block.
+		if x, y, z := c.safeSpawnPosition(posX, posY, posZ); x != posX || y != posY || z != posZ {
+			c.log.Warn("saved position obstructed, moving player",
+				"from", fmt.Sprintf("%.1f,%.1f,%.1f", posX, posY, posZ),
+				"to", fmt.Sprintf("%.1f,%.1f,%.1f", x, y, z))
+			posX, posY, posZ = x, y, z
+		}
+
 		c.log.Info("restored saved player data")
 	}
 
@@ -73,6 +98,7 @@ func (c *Connection) startPlay(username, uuid string, skinProps []player.SkinPro
 	// For returning players ApplyData already did this, but for new players
 	// the NewPlayer default (0.5, 4.0, 0.5) would be stale.
 	c.self.SetPosition(posX, posY, posZ, posYaw, posPitch, true)
+	c.self.SetGameMode(gameMode)
 
 	// 1. Join Game
 	if err := c.writePacket(&pkt.Login{
@@ -89,18 +115,13 @@ func (c *Connection) startPlay(username, uuid string, skinProps []player.SkinPro
 
 	// 2. Spawn Position
 	if err := c.writePacket(&pkt.SpawnPosition{
-		Location: mcnet.EncodePosition(0, spawnY, 0),
+		Location: mcnet.EncodePosition(spawn.X, spawn.Y, spawn.Z),
 	}); err != nil {
 		return fmt.Errorf("write spawn position: %w", err)
 	}
 
 	// 3. Player Abilities (based on actual game mode)
-	abilities := abilitiesForGameMode(gameMode)
-	if err := c.writePacket(&pkt.AbilitiesCB{
-		Flags:        abilities,
-		FlyingSpeed:  0.05,
-		WalkingSpeed: 0.1,
-	}); err != nil {
+	if err := c.writePacket(abilitiesPacket(c.self)); err != nil {
 		return fmt.Errorf("write player abilities: %w", err)
 	}
 
@@ -122,37 +143,102 @@ func (c *Connection) startPlay(username, uuid string, skinProps []player.SkinPro
 	}
 
 	// 6. Update Time (send current world time)
-	worldAge, worldTime := c.world.GetTime()
+	worldAge, worldTime := c.world.ClientTime()
 	if err := c.writePacket(&pkt.UpdateTime{
 		Age:  worldAge,
 		Time: worldTime,
 	}); err != nil {
 		return fmt.Errorf("write update time: %w", err)
 	}
+	if err := c.writePacket(player.WorldBorderPacket(c.world.Border())); err != nil {
+		return fmt.Errorf("write world border: %w", err)
+	}
+	if wt := c.world.Weather(); wt != world.WeatherClear {
+		for _, p := range player.WeatherPackets(wt) {
+			if err := c.writePacket(p); err != nil {
+				return fmt.Errorf("write weather: %w", err)
+			}
+		}
+	}
 
 	// 7. Window Items (inventory sync)
 	if err := c.sendWindowItems(); err != nil {
 		return fmt.Errorf("send window items: %w", err)
 	}
+	if err := c.writePacket(c.self.HealthPacket()); err != nil {
+		return fmt.Errorf("write update health: %w", err)
+	}
+	if err := c.writePacket(c.self.ExperiencePacket()); err != nil {
+		return fmt.Errorf("write experience: %w", err)
+	}
+	if c.cfg.PlayerListHeader != "" || c.cfg.PlayerListFooter != "" {
+		if err := c.writePacket(&pkt.PlayerlistHeader{
+			Header: chat.Text(c.cfg.PlayerListHeader).String(),
+			Footer: chat.Text(c.cfg.PlayerListFooter).String(),
+		}); err != nil {
+			return fmt.Errorf("write player list header: %w", err)
+		}
+	}
 
 	// 8. Chat Message — "Hello, world!"
 	if err := c.writePacket(&pkt.ChatCB{
-		Message:  `{"text":"Hello, world!","color":"gold"}`,
+		Message:  chat.Colored("Hello, world!", chat.Gold).String(),
 		Position: 0,
 	}); err != nil {
 		return fmt.Errorf("write chat message: %w", err)
 	}
 
 	// 9. Register with player manager (sends cross-wise PlayerInfo + spawns).
+	c.self.Disconnect = func(reason string) { c.kickWithText(kickCommand, reason) }
 	c.players.Add(c.self)
 
-	// 10. Start KeepAlive goroutine
+	// 10. Select the saved hotbar slot and show the matching held item.
+	if err := c.syncHeldSlot(); err != nil {
+		return fmt.Errorf("sync held slot: %w", err)
+	}
+
+	// 11. Start KeepAlive goroutine
 	go c.keepAliveLoop()
 
 	c.log.Info("join sequence complete", "entityID", entityID)
 	return nil
 }
 
+// syncHeldSlot tells the client which hotbar slot is selected (e.g. after
+// restoring saved data) and broadcasts the matching held item to trackers.
+func (c *Connection) syncHeldSlot() error {
+	if err := c.writePacket(&pkt.HeldItemSlotCB{
+		Slot: int8(c.self.Inventory.GetHeldSlot()),
+	}); err != nil {
+		return err
+	}
+	c.broadcastHeldItem()
+	return nil
+}
+
+// broadcastHeldItem sends the player's held item as equipment slot 0 to trackers.
+func (c *Connection) broadcastHeldItem() {
+	eq := player.BuildSingleEquipment(c.self.EntityID, 0, c.self.Inventory.HeldItem())
+	c.players.BroadcastToTrackers(eq, c.self.EntityID)
+}
+
+// Vanilla base speeds sent in PlayerAbilities, scaled by /speed.
+const (
+	baseFlySpeed  = 0.05
+	baseWalkSpeed = 0.1
+)
+
+// abilitiesPacket returns the PlayerAbilities packet for p's game mode and
+// speed multipliers.
+func abilitiesPacket(p *player.Player) *pkt.AbilitiesCB {
+	fly, walk := p.Speeds()
+	return &pkt.AbilitiesCB{
+		Flags:        abilitiesForGameMode(p.GetGameMode()),
+		FlyingSpeed:  baseFlySpeed * fly,
+		WalkingSpeed: baseWalkSpeed * walk,
+	}
+}
+
 // abilitiesForGameMode returns the ability flags for a given game mode.
 func abilitiesForGameMode(mode uint8) int8 {
 	switch mode {
@@ -179,10 +265,7 @@ func (c *Connection) keepAliveLoop() {
 			if !c.keepAliveAcked && id > 0 {
 				if time.Since(c.lastKeepAliveSent) > 30*time.Second {
 					c.mu.Unlock()
-					_ = c.writePacket(&pkt.KickDisconnect{
-						Reason: `{"text":"Timed out"}`,
-					})
-					c.disconnect("keepalive timeout")
+					c.kick(kickTimeout)
 					return
 				}
 			}
@@ -205,18 +288,19 @@ func (c *Connection) keepAliveLoop() {
 
 func (c *Connection) handlePlay(packetID int32, data []byte) error {
 	switch packetID {
-	case 0x00: // KeepAlive
+	case pkt.PlayServerboundKeepAlive:
 		var p pkt.KeepAliveSB
 		if err := mcnet.Unmarshal(data, &p); err != nil {
 			return fmt.Errorf("unmarshal keep alive: %w", err)
 		}
 		c.mu.Lock()
-		if p.KeepAliveID == c.lastKeepAliveID {
+		if p.KeepAliveID == c.lastKeepAliveID && !c.keepAliveAcked {
 			c.keepAliveAcked = true
+			c.self.SetPing(time.Since(c.lastKeepAliveSent))
 		}
 		c.mu.Unlock()
 
-	case 0x01: // Chat Message
+	case pkt.PlayServerboundChat:
 		var p pkt.ChatSB
 		if err := mcnet.Unmarshal(data, &p); err != nil {
 			return fmt.Errorf("unmarshal chat: %w", err)
@@ -225,49 +309,46 @@ func (c *Connection) handlePlay(packetID int32, data []byte) error {
 		if c.handleCommand(p.Message) {
 			break
 		}
-		chatJSON := fmt.Sprintf(
-			`{"translate":"chat.type.text","with":[%s,%s]}`,
-			escapeJSON(c.self.Username), escapeJSON(p.Message),
-		)
 		c.players.Broadcast(&pkt.ChatCB{
-			Message:  chatJSON,
+			Message:  chat.Translate("chat.type.text", chat.Text(c.self.Username), chat.Text(p.Message)).String(),
 			Position: 0,
 		})
 
-	case 0x02: // Use Entity
+	case pkt.PlayServerboundUseEntity:
 		return c.handleUseEntity(data)
 
-	case 0x03: // Player (ground state)
-		// heartbeat, ignore
+	case pkt.PlayServerboundFlying: // ground state only
+		// Sent every tick by a client standing still.
+		c.applyBorderDamage()
 
-	case 0x04: // Player Position
+	case pkt.PlayServerboundPosition:
 		var p pkt.PositionSB
 		if err := mcnet.Unmarshal(data, &p); err != nil {
 			return fmt.Errorf("unmarshal player position: %w", err)
 		}
 		c.handlePositionUpdate(p.X, p.Y, p.Z, 0, 0, p.OnGround, true, false)
 
-	case 0x05: // Player Look
+	case pkt.PlayServerboundLook:
 		var p pkt.Look
 		if err := mcnet.Unmarshal(data, &p); err != nil {
 			return fmt.Errorf("unmarshal player look: %w", err)
 		}
 		c.handleLookUpdate(p.Yaw, p.Pitch, p.OnGround)
 
-	case 0x06: // Player Position And Look
+	case pkt.PlayServerboundPositionLook:
 		var p pkt.PositionLook
 		if err := mcnet.Unmarshal(data, &p); err != nil {
 			return fmt.Errorf("unmarshal player position and look: %w", err)
 		}
 		c.handlePositionUpdate(p.X, p.Y, p.Z, p.Yaw, p.Pitch, p.OnGround, true, true)
 
-	case 0x07: // Block Dig
+	case pkt.PlayServerboundBlockDig:
 		return c.handleBlockDig(data)
 
-	case 0x08: // Block Place
+	case pkt.PlayServerboundBlockPlace:
 		return c.handleBlockPlace(data)
 
-	case 0x09: // Held Item Change
+	case pkt.PlayServerboundHeldItemSlot:
 		var p pkt.HeldItemSlotSB
 		if err := mcnet.Unmarshal(data, &p); err != nil {
 			return fmt.Errorf("unmarshal held item slot: %w", err)
@@ -276,17 +357,15 @@ func (c *Connection) handlePlay(packetID int32, data []byte) error {
 			return nil
 		}
 		c.self.Inventory.SetHeldSlot(p.SlotID)
-		heldItem := c.self.Inventory.HeldItem()
-		eqData := player.BuildSingleEquipment(c.self.EntityID, 0, heldItem)
-		c.players.BroadcastToTrackers(&pkt.EntityEquipment{Data: eqData}, c.self.EntityID)
+		c.broadcastHeldItem()
 
-	case 0x0A: // Animation (arm swing)
+	case pkt.PlayServerboundArmAnimation:
 		c.players.BroadcastToTrackers(&pkt.Animation{
 			EntityID:  c.self.EntityID,
 			Animation: 0, // swing arm
 		}, c.self.EntityID)
 
-	case 0x0B: // Entity Action
+	case pkt.PlayServerboundEntityAction:
 		var p pkt.EntityAction
 		if err := mcnet.Unmarshal(data, &p); err != nil {
 			return fmt.Errorf("unmarshal entity action: %w", err)
@@ -295,6 +374,10 @@ func (c *Connection) handlePlay(packetID int32, data []byte) error {
 		case 0: // start sneak
 			c.self.SetSneaking(true)
 			c.players.BroadcastEntityMetadata(c.self)
+			if c.self.GetGameMode() == packet.GameModeSpectator {
+				// Sneaking leaves a spectated entity's view.
+				_ = c.writePacket(&pkt.Camera{CameraID: c.self.EntityID})
+			}
 		case 1: // stop sneak
 			c.self.SetSneaking(false)
 			c.players.BroadcastEntityMetadata(c.self)
@@ -306,44 +389,48 @@ func (c *Connection) handlePlay(packetID int32, data []byte) error {
 			c.players.BroadcastEntityMetadata(c.self)
 		}
 
-	case 0x0C: // Steer Vehicle — no vehicle support, ignore
-		// consume and discard
+	case pkt.PlayServerboundSteerVehicle:
+		var p pkt.SteerVehicle
+		if err := mcnet.Unmarshal(data, &p); err != nil {
+			return fmt.Errorf("unmarshal steer vehicle: %w", err)
+		}
+		if p.Jump&0x02 != 0 { // unmount
+			c.dismount()
+		}
 
-	case 0x0D: // Close Window
+	case pkt.PlayServerboundCloseWindow:
 		return c.handleCloseWindow(data)
 
-	case 0x0E: // Window Click
+	case pkt.PlayServerboundWindowClick:
 		return c.handleWindowClick(data)
 
-	case 0x0F: // Transaction
+	case pkt.PlayServerboundTransaction:
 		return c.handleTransaction(data)
 
-	case 0x10: // Set Creative Slot
+	case pkt.PlayServerboundSetCreativeSlot:
 		return c.handleCreativeSlot(data)
 
-	case 0x11: // Enchant Item — no enchanting support, ignore
+	case pkt.PlayServerboundEnchantItem: // no enchanting support, ignore
 		// consume and discard
 
-	case 0x12: // Update Sign
+	case pkt.PlayServerboundUpdateSign:
 		var p pkt.UpdateSignSB
 		if err := mcnet.Unmarshal(data, &p); err != nil {
 			return fmt.Errorf("unmarshal update sign: %w", err)
 		}
-		x, y, z := mcnet.DecodePosition(p.Location)
-		c.log.Info("update sign", "x", x, "y", y, "z", z,
-			"line1", p.Text1, "line2", p.Text2, "line3", p.Text3, "line4", p.Text4)
+		c.handleUpdateSign(&p)
 
-	case 0x13: // Player Abilities (SB)
+	case pkt.PlayServerboundAbilities:
 		var p pkt.AbilitiesSB
 		if err := mcnet.Unmarshal(data, &p); err != nil {
 			return fmt.Errorf("unmarshal abilities sb: %w", err)
 		}
 		c.handleAbilitiesUpdate(p)
 
-	case 0x14: // Tab Complete
+	case pkt.PlayServerboundTabComplete:
 		return c.handleTabComplete(data)
 
-	case 0x15: // Client Settings
+	case pkt.PlayServerboundSettings:
 		var p pkt.Settings
 		if err := mcnet.Unmarshal(data, &p); err != nil {
 			return fmt.Errorf("unmarshal client settings: %w", err)
@@ -352,28 +439,20 @@ func (c *Connection) handlePlay(packetID int32, data []byte) error {
 		c.self.SetSkinParts(p.SkinParts)
 		c.players.BroadcastEntityMetadata(c.self)
 
-	case 0x16: // Client Status (respawn / stats request)
+	case pkt.PlayServerboundClientCommand: // respawn / stats request
 		return c.handleRespawn()
 
-	case 0x17: // Custom Payload (plugin channel)
+	case pkt.PlayServerboundCustomPayload: // plugin channel
 		return c.handleCustomPayload(data)
 
-	case 0x18: // Spectate
+	case pkt.PlayServerboundSpectate:
 		var p pkt.Spectate
 		if err := mcnet.Unmarshal(data, &p); err != nil {
 			return fmt.Errorf("unmarshal spectate: %w", err)
 		}
-		if c.self.GetGameMode() != packet.GameModeSpectator {
-			break
-		}
-		targetUUID := formatUUID(p.Target)
-		target := c.players.GetByUUID(targetUUID)
-		if target != nil {
-			pos := target.GetPosition()
-			c.teleportSelf(pos.X, pos.Y, pos.Z)
-		}
+		c.spectate(p.Target)
 
-	case 0x19: // Resource Pack Status
+	case pkt.PlayServerboundResourcePackReceive:
 		var p pkt.ResourcePackReceive
 		if err := mcnet.Unmarshal(data, &p); err != nil {
 			return fmt.Errorf("unmarshal resource pack status: %w", err)
@@ -392,10 +471,8 @@ func (c *Connection) handlePositionUpdate(x, y, z float64, yaw, pitch float32, o
 		return
 	}
 
-	// Clamp to world boundary if configured.
-	if c.cfg.WorldRadius > 0 {
-		x, z = c.clampToWorldBounds(x, y, z, yaw, pitch)
-	}
+	// Keep the player inside the world border.
+	x, z = c.clampToWorldBounds(x, y, z, yaw, pitch)
 
 	// Preserve current look if only position changed.
 	if !lookChanged {
@@ -405,6 +482,10 @@ func (c *Connection) handlePositionUpdate(x, y, z float64, yaw, pitch float32, o
 	}
 
 	oldFX, oldFY, oldFZ, newFX, newFY, newFZ := c.setPositionAndUpdateChunks(x, y, z, yaw, pitch, onGround)
+	if posChanged {
+		c.updateFall(x, y, z, onGround)
+	}
+	c.applyBorderDamage()
 
 	dx := newFX - oldFX
 	dy := newFY - oldFY
@@ -464,7 +545,7 @@ func (c *Connection) handlePositionUpdate(x, y, z float64, yaw, pitch float32, o
 	c.players.UpdateTracking(c.self)
 
 	// Try to pick up nearby item entities.
-	if c.players.TryPickupItems(c.self) > 0 {
+	if c.players.TryPickupItems(c.self, c.maxStackSize) > 0 {
 		_ = c.sendWindowItems()
 	}
 }
@@ -475,6 +556,7 @@ func (c *Connection) handleLookUpdate(yaw, pitch float32, onGround bool) {
 	}
 
 	c.self.UpdateLook(yaw, pitch, onGround)
+	c.followBossBar()
 
 	yawAngle := player.DegreesToAngle(yaw)
 	pitchAngle := player.DegreesToAngle(pitch)
@@ -521,7 +603,7 @@ func (c *Connection) handleBlockDig(data []byte) error {
 				if c.gameData != nil {
 					materials = c.gameData.Materials
 				}
-				breakTicks := calcBreakTime(block, heldItem.BlockID, materials)
+				breakTicks := calcBreakTime(block, heldItem, materials)
 				if breakTicks == 0 {
 					// Instant break even in survival (e.g. tall grass, torches).
 					c.breakBlock(x, y, z, posVal)
@@ -531,6 +613,10 @@ func (c *Connection) handleBlockDig(data []byte) error {
 					// Unbreakable block, don't start animation.
 					return nil
 				}
+				c.digging = true
+				c.digPos = posVal
+				c.digStart = time.Now()
+				c.digTicks = breakTicks
 			}
 			// Broadcast dig start animation to other players.
 			c.players.BroadcastToTrackers(&pkt.BlockBreakAnimation{
@@ -542,6 +628,7 @@ func (c *Connection) handleBlockDig(data []byte) error {
 		return nil
 
 	case 1: // Cancelled digging
+		c.digging = false
 		// Reset block break animation for other players.
 		c.players.BroadcastToTrackers(&pkt.BlockBreakAnimation{
 			EntityID:     c.self.EntityID,
@@ -559,6 +646,15 @@ func (c *Connection) handleBlockDig(data []byte) error {
 				_ = c.writePacket(&pkt.BlockChange{Location: posVal, Type: stateID})
 				return nil
 			}
+
+			// The client must have been digging this block for long enough.
+			started := c.digging && c.digPos == posVal
+			c.digging = false
+			if !started || time.Since(c.digStart) < minDigDuration(c.digTicks) {
+				c.log.Debug("rejected early block break", "x", x, "y", y, "z", z)
+				_ = c.writePacket(&pkt.BlockChange{Location: posVal, Type: stateID})
+				return nil
+			}
 		}
 
 		// Reset animation and break the block.
@@ -571,6 +667,15 @@ func (c *Connection) handleBlockDig(data []byte) error {
 		return nil
 	}
 
+	// status 5 = release the item in use, which shoots a drawn bow
+	if status == 5 {
+		if !c.drawingBow {
+			return nil
+		}
+		c.drawingBow = false
+		return c.shootBow(time.Since(c.drawStart))
+	}
+
 	// status 3 = drop stack, status 4 = drop single item
 	if status == 3 || status == 4 {
 		heldSlot := c.self.Inventory.GetHeldSlot()
@@ -598,15 +703,15 @@ func (c *Connection) handleBlockDig(data []byte) error {
 
 		// Update held item for trackers.
 		newHeld := c.self.Inventory.HeldItem()
-		eqData := player.BuildSingleEquipment(c.self.EntityID, 0, newHeld)
-		c.players.BroadcastToTrackers(&pkt.EntityEquipment{Data: eqData}, c.self.EntityID)
+		eq := player.BuildSingleEquipment(c.self.EntityID, 0, newHeld)
+		c.players.BroadcastToTrackers(eq, c.self.EntityID)
 	}
 
 	return nil
 }
 
 // breakBlock removes a block from the world, broadcasts the change + break effect,
-// and spawns item drops in survival mode.
+// spawns item drops in survival mode, and drops any sand or gravel above it.
 func (c *Connection) breakBlock(x, y, z int, posVal int64) {
 	oldBlockState := c.world.GetBlock(x, y, z)
 	c.world.SetBlock(x, y, z, 0)
@@ -631,13 +736,29 @@ func (c *Connection) breakBlock(x, y, z int, posVal int64) {
 	if c.self.GetGameMode() != packet.GameModeCreative {
 		if block, ok := c.lookupBlock(oldBlockState); ok {
 			heldItem := c.self.Inventory.HeldItem()
-			drops := blockDrops(block, heldItem.BlockID)
+			drops := blockDrops(block, oldBlockState, heldItem.BlockID, c.DropRNG)
 			for _, drop := range drops {
 				groundY := c.findGroundLevel(x, y, z)
 				c.players.SpawnBlockDrop(drop, float64(x)+0.5, float64(groundY)+0.1, float64(z)+0.5, float64(y)+0.5)
 			}
+			// Blocks that break instantly do not wear the tool.
+			if block.Hardness != nil && *block.Hardness > 0 {
+				c.wearHeldItem(false)
+			}
 		}
 	}
+
+	// Sand and gravel resting on the broken block fall, and water next to
+	// it flows in.
+	c.applyGravity(x, y+1, z)
+	c.world.ScheduleFlow(x, y, z)
+
+	// Leaves that were held up by this log may decay.
+	if world.IsLog(oldBlockState) {
+		c.world.ScheduleLeafDecay(x, y, z)
+	}
+
+	c.dropContainer(x, y, z, oldBlockState)
 }
 
 // findGroundLevel scans downward from startY to find the first non-air block,
@@ -655,6 +776,46 @@ func (c *Connection) findGroundLevel(x, startY, z int) int {
 	return 0
 }
 
+// isSolidBlock reports whether the block at (x, y, z) has a full collision box.
+// Without game data, any non-air block counts as solid.
+func (c *Connection) isSolidBlock(x, y, z int) bool {
+	state := c.world.GetBlock(x, y, z)
+	if state == 0 {
+		return false
+	}
+	if c.gameData != nil && c.gameData.Blocks != nil {
+		if b, ok := c.gameData.Blocks.ByID(int(state >> 4)); ok {
+			return b.BoundingBox == "block"
+		}
+	}
+	return true
+}
+
+// hasRoomAt reports whether a player's feet and head fit at block (x, y, z).
+func (c *Connection) hasRoomAt(x, y, z int) bool {
+	if y < 0 || y+1 >= c.cfg.MaxBuildHeight {
+		return false
+	}
+	return !c.isSolidBlock(x, y, z) && !c.isSolidBlock(x, y+1, z)
+}
+
+// safeSpawnPosition returns (x, y, z) unchanged if a player fits there.
+// Otherwise it scans upward for the first Y with room, and falls back to
+// the world spawn if the column has none.
+func (c *Connection) safeSpawnPosition(x, y, z float64) (float64, float64, float64) {
+	bx, by, bz := int(math.Floor(x)), int(math.Floor(y)), int(math.Floor(z))
+	if c.hasRoomAt(bx, by, bz) {
+		return x, y, z
+	}
+	for ny := max(by+1, 0); ny+1 < c.cfg.MaxBuildHeight; ny++ {
+		if c.hasRoomAt(bx, ny, bz) {
+			return x, float64(ny), z
+		}
+	}
+	spawn := c.world.Spawn()
+	return float64(spawn.X) + 0.5, float64(spawn.Y), float64(spawn.Z) + 0.5
+}
+
 // playerGroundY returns the ground level (as float64) below the player's current position.
 func (c *Connection) playerGroundY(pos player.Position) float64 {
 	return float64(c.findGroundLevel(int(math.Floor(pos.X)), int(pos.Y), int(math.Floor(pos.Z))))
@@ -669,49 +830,111 @@ func (c *Connection) groundAtFunc() func(x, y, z int) float64 {
 	}
 }
 
-func (c *Connection) handleBlockPlace(data []byte) error {
-	r := bytes.NewReader(data)
-
-	posVal, err := mcnet.ReadI64(r)
-	if err != nil {
-		return fmt.Errorf("read place position: %w", err)
+// useNameTag names the target mob with the held name tag, which makes it
+// persistent. As in vanilla, a name tag without a custom name does nothing,
+// and creative players keep the tag.
+func (c *Connection) useNameTag(targetID int32) error {
+	heldIdx := int16(slotHotbarStart) + c.self.Inventory.GetHeldSlot()
+	held := c.self.Inventory.GetProtocolSlot(int(heldIdx))
+	if held.BlockID < 0 || c.itemName(held.BlockID) != "name_tag" || held.NBT == nil || held.NBT.DisplayName == "" {
+		return nil
+	}
+	if !c.players.NameMob(targetID, held.NBT.DisplayName) {
+		return nil
 	}
 
-	face, err := mcnet.ReadI8(r)
-	if err != nil {
-		return fmt.Errorf("read place face: %w", err)
+	if c.self.GetGameMode() == packet.GameModeCreative {
+		return nil
+	}
+	held.ItemCount--
+	if held.ItemCount <= 0 {
+		held = player.EmptySlot
 	}
+	c.setInventorySlot(heldIdx, held)
+	return nil
+}
 
-	slot, err := readSlot(r)
-	if err != nil {
-		return fmt.Errorf("read place slot: %w", err)
+// itemCooldowns maps throwable item IDs to the ticks before they can be used again.
+var itemCooldowns = map[int16]int{
+	368: 20, // ender pearl
+}
+
+// useCooldownItem handles using a throwable item from the hotbar. While the
+// item is on cooldown the use is refused with a click sound; otherwise one
+// item is consumed, as the client already assumed, and the cooldown starts.
+// Thrown entities are not simulated yet.
+func (c *Connection) useCooldownItem(itemID int16, cooldown int) error {
+	heldIdx := int16(slotHotbarStart) + c.self.Inventory.GetHeldSlot()
+	held := c.self.Inventory.GetProtocolSlot(int(heldIdx))
+	if held.BlockID != itemID {
+		return c.sendSetSlot(0, heldIdx, held)
 	}
 
-	// Read cursor position (3 x u8) - we don't use these but must consume them.
-	if _, err := mcnet.ReadU8(r); err != nil {
-		return fmt.Errorf("read cursor x: %w", err)
+	if c.self.OnCooldown(itemID) {
+		pos := c.self.GetPosition()
+		_ = c.writePacket(&pkt.NamedSoundEffect{
+			SoundName: "random.click",
+			X:         int32(pos.X * 8),
+			Y:         int32(pos.Y * 8),
+			Z:         int32(pos.Z * 8),
+			Volume:    0.5,
+			Pitch:     40,
+		})
+		// Undo the client's predicted use.
+		return c.sendSetSlot(0, heldIdx, held)
 	}
-	if _, err := mcnet.ReadU8(r); err != nil {
-		return fmt.Errorf("read cursor y: %w", err)
+
+	c.self.SetItemCooldown(itemID, cooldown)
+	held.ItemCount--
+	if held.ItemCount <= 0 {
+		held = player.EmptySlot
 	}
-	if _, err := mcnet.ReadU8(r); err != nil {
-		return fmt.Errorf("read cursor z: %w", err)
+	c.setInventorySlot(heldIdx, held)
+	return nil
+}
+
+func (c *Connection) handleBlockPlace(data []byte) error {
+	// The cursor position on the block face is not used.
+	var place pkt.BlockPlace
+	if err := mcnet.Unmarshal(data, &place); err != nil {
+		return fmt.Errorf("unmarshal block place: %w", err)
 	}
+	posVal, face, slot := place.Location, place.Direction, place.HeldItem
 
 	// Special position -1,-1,-1 means the player is using an item (not placing a block).
 	if posVal == -1 {
+		if cooldown, ok := itemCooldowns[slot.BlockID]; ok {
+			return c.useCooldownItem(slot.BlockID, cooldown)
+		}
+		if slot.BlockID == itemBow {
+			c.drawingBow = true
+			c.drawStart = time.Now()
+			return nil
+		}
 		// Try to equip armor from hotbar via right-click.
 		if armorProtoSlot := armorSlotForItem(slot.BlockID); armorProtoSlot >= 0 {
 			heldIdx := int16(slotHotbarStart) + int16(c.self.Inventory.GetHeldSlot())
-			heldItem := c.getWindowSlot(heldIdx)
-			armorItem := c.getWindowSlot(armorProtoSlot)
-			c.setWindowSlot(armorProtoSlot, heldItem)
-			c.setWindowSlot(heldIdx, armorItem)
+			heldItem := c.self.Inventory.GetProtocolSlot(int(heldIdx))
+			armorItem := c.self.Inventory.GetProtocolSlot(int(armorProtoSlot))
+			c.setInventorySlot(armorProtoSlot, heldItem)
+			c.setInventorySlot(heldIdx, armorItem)
 			_ = c.sendWindowItems()
 		}
 		return nil
 	}
 
+	// Right-clicking a chest, crafting table or furnace opens it, and
+	// right-clicking a door, trapdoor or fence gate opens or closes it,
+	// unless the player is sneaking with an item in hand to place against it.
+	if !c.self.IsSneaking() || slot.BlockID <= 0 {
+		if opened, err := c.openBlockWindow(mcnet.DecodePosition(posVal)); opened || err != nil {
+			return err
+		}
+		if c.toggleBlock(mcnet.DecodePosition(posVal)) {
+			return nil
+		}
+	}
+
 	// Empty slot means no block to place.
 	if slot.BlockID <= 0 {
 		return nil
@@ -719,25 +942,38 @@ func (c *Connection) handleBlockPlace(data []byte) error {
 
 	x, y, z := mcnet.DecodePosition(posVal)
 
-	// Compute target position from face direction.
-	switch face {
-	case 0: // -Y
-		y--
-	case 1: // +Y
-		y++
-	case 2: // -Z
-		z--
-	case 3: // +Z
-		z++
-	case 4: // -X
-		x--
-	case 5: // +X
-		x++
-	default:
-		return nil
+	// A block placed against tall grass, a thin snow layer or similar
+	// replaces it; otherwise the target is the cell next to the clicked face.
+	if !c.replaceable(c.world.GetBlock(x, y, z)) {
+		switch face {
+		case 0: // -Y
+			y--
+		case 1: // +Y
+			y++
+		case 2: // -Z
+			z--
+		case 3: // +Z
+			z++
+		case 4: // -X
+			x--
+		case 5: // +X
+			x++
+		default:
+			return nil
+		}
 	}
 
 	stateID := int32(slot.BlockID) << 4
+	if slot.BlockID == itemSign {
+		var ok bool
+		if stateID, ok = signState(face, c.self.GetPosition().Yaw); !ok {
+			return nil
+		}
+	}
+	if !c.canPlaceAt(x, y, z, stateID) {
+		return c.resyncBlock(x, y, z)
+	}
+
 	c.world.SetBlock(x, y, z, stateID)
 
 	blockChange := &pkt.BlockChange{
@@ -745,7 +981,150 @@ func (c *Connection) handleBlockPlace(data []byte) error {
 		Type:     stateID,
 	}
 	c.players.BroadcastExcept(blockChange, c.self.EntityID)
-	return c.writePacket(blockChange)
+	if err := c.writePacket(blockChange); err != nil {
+		return err
+	}
+
+	if world.IsSign(stateID) {
+		return c.openSignEditor(world.BlockPos{X: x, Y: y, Z: z})
+	}
+
+	// A placed sand or gravel block falls if there is nothing below it, and
+	// placed water starts to flow.
+	c.applyGravity(x, y, z)
+	c.world.ScheduleFlow(x, y, z)
+	return nil
+}
+
+// maxInteractDistanceSq is the squared distance from the player's feet to
+// a block's center within which the block can be used, as in vanilla.
+const maxInteractDistanceSq = 64
+
+// withinReach reports whether the block at x, y, z is close enough to the
+// player to interact with.
+func (c *Connection) withinReach(x, y, z int) bool {
+	pos := c.self.GetPosition()
+	dx, dy, dz := float64(x)+0.5-pos.X, float64(y)+0.5-pos.Y, float64(z)+0.5-pos.Z
+	return dx*dx+dy*dy+dz*dz < maxInteractDistanceSq
+}
+
+// canPlaceAt reports whether a block state may be placed at the given
+// position. Placement is refused outside the vertical range, outside the
+// world border, where a block that cannot be replaced is already in the
+// way, and where the block's collision boxes would intersect any player's
+// bounding box. Blocks without collision, such as torches and flowers, may
+// be placed inside players.
+func (c *Connection) canPlaceAt(x, y, z int, state int32) bool {
+	if y < 0 || y > 255 {
+		return false
+	}
+	if !c.world.Border().ContainsBlock(x, z) {
+		return false
+	}
+	if !c.replaceable(c.world.GetBlock(x, y, z)) {
+		return false
+	}
+
+	boxes := c.collisionBoxes(state)
+	if len(boxes) == 0 {
+		return true
+	}
+	blocked := false
+	c.players.ForEach(func(p *player.Player) {
+		if !blocked && playerIntersectsBoxes(p, x, y, z, boxes) {
+			blocked = true
+		}
+	})
+	return !blocked
+}
+
+// replaceableBlocks names the blocks a placed block replaces. The gameData
+// transparency and material flags cannot tell these apart from torches and
+// flowers, so they are listed explicitly.
+var replaceableBlocks = map[string]bool{
+	"water":         true,
+	"flowing_water": true,
+	"lava":          true,
+	"flowing_lava":  true,
+	"fire":          true,
+	"tallgrass":     true,
+	"deadbush":      true,
+	"vine":          true,
+	"snow_layer":    true,
+}
+
+// replaceable reports whether a block state may be overwritten by placing a
+// block into it. Air always may; snow only while it is a single layer.
+// Without gameData only air is replaceable.
+func (c *Connection) replaceable(state int32) bool {
+	if state == 0 {
+		return true
+	}
+	block, ok := c.lookupBlock(state)
+	if !ok || !replaceableBlocks[block.Name] {
+		return false
+	}
+	return block.Name != "snow_layer" || state&0xF == 0
+}
+
+// fullBlock is the collision box of a solid cube.
+var fullBlock = []gamedata.BoundingBox{{MaxX: 1, MaxY: 1, MaxZ: 1}}
+
+// collisionBoxes returns a block state's collision boxes relative to its
+// block position, from gameData.CollisionShapes. Blocks with no shape data
+// are treated as solid cubes.
+func (c *Connection) collisionBoxes(state int32) []gamedata.BoundingBox {
+	if c.gameData == nil || c.gameData.CollisionShapes == nil {
+		return fullBlock
+	}
+	block, ok := c.lookupBlock(state)
+	if !ok {
+		return fullBlock
+	}
+	shapes := c.gameData.CollisionShapes.Blocks[block.Name]
+	var shapeID int
+	switch meta := int(state & 0xF); {
+	case len(shapes) == 1:
+		shapeID = shapes[0]
+	case meta < len(shapes):
+		shapeID = shapes[meta]
+	default:
+		return fullBlock
+	}
+	boxes, ok := c.gameData.CollisionShapes.Shapes[shapeID]
+	if !ok {
+		return fullBlock
+	}
+	return boxes
+}
+
+// playerIntersectsBoxes reports whether the player's hitbox (0.6 wide,
+// Height tall) overlaps any of the collision boxes of a block at the given
+// position.
+func playerIntersectsBoxes(p *player.Player, x, y, z int, boxes []gamedata.BoundingBox) bool {
+	const halfWidth = 0.3
+	pos := p.GetPosition()
+	bx, by, bz := float64(x), float64(y), float64(z)
+	for _, b := range boxes {
+		if pos.X+halfWidth > bx+b.MinX && pos.X-halfWidth < bx+b.MaxX &&
+			pos.Y+p.Height > by+b.MinY && pos.Y < by+b.MaxY &&
+			pos.Z+halfWidth > bz+b.MinZ && pos.Z-halfWidth < bz+b.MaxZ {
+			return true
+		}
+	}
+	return false
+}
+
+// resyncBlock sends the world's current block state at a position to the
+// client, reverting any client-side prediction.
+func (c *Connection) resyncBlock(x, y, z int) error {
+	if y < 0 || y > 255 {
+		return nil
+	}
+	return c.writePacket(&pkt.BlockChange{
+		Location: mcnet.EncodePosition(x, y, z),
+		Type:     c.world.GetBlock(x, y, z),
+	})
 }
 
 // parseUUID parses a hyphenated UUID string into 16 bytes.
@@ -757,12 +1136,6 @@ func parseUUID(s string) [16]byte {
 	return uuid
 }
 
-// escapeJSON marshals a string to a JSON string literal (with quotes).
-func escapeJSON(s string) string {
-	b, _ := json.Marshal(s)
-	return string(b)
-}
-
 // setPositionAndUpdateChunks wraps SetPosition and triggers chunk loading if the player crossed a chunk boundary.
 func (c *Connection) setPositionAndUpdateChunks(x, y, z float64, yaw, pitch float32, onGround bool) (oldFX, oldFY, oldFZ, newFX, newFY, newFZ int32) {
 	oldCX, oldCZ := c.self.ChunkX(), c.self.ChunkZ()
@@ -771,6 +1144,7 @@ func (c *Connection) setPositionAndUpdateChunks(x, y, z float64, yaw, pitch floa
 	if oldCX != newCX || oldCZ != newCZ {
 		c.updateLoadedChunks(newCX, newCZ)
 	}
+	c.followBossBar()
 	return
 }
 
@@ -799,10 +1173,17 @@ func (c *Connection) sendInitialChunks() error {
 	})
 
 	for _, pos := range chunks {
+		// Stop encoding for a player who has already gone.
+		if err := c.ctx.Err(); err != nil {
+			return fmt.Errorf("%w: %w", ErrClientDisconnect, err)
+		}
 		chunk := c.world.EncodeChunk(pos.X, pos.Z)
 		if err := c.writePacket(&chunk); err != nil {
 			return err
 		}
+		if err := c.sendChunkSigns(pos.X, pos.Z); err != nil {
+			return err
+		}
 		c.loadedChunks[pos] = struct{}{}
 	}
 	return nil
@@ -822,11 +1203,18 @@ func (c *Connection) updateLoadedChunks(newCX, newCZ int) {
 			if !c.isChunkInBounds(cx, cz) {
 				continue
 			}
+			if c.ctx.Err() != nil {
+				return
+			}
 			chunk := c.world.EncodeChunk(cx, cz)
 			if err := c.writePacket(&chunk); err != nil {
 				c.log.Error("send chunk", "cx", cx, "cz", cz, "error", err)
 				return
 			}
+			if err := c.sendChunkSigns(cx, cz); err != nil {
+				c.log.Error("send signs", "cx", cx, "cz", cz, "error", err)
+				return
+			}
 			c.loadedChunks[pos] = struct{}{}
 		}
 	}
@@ -847,49 +1235,56 @@ func (c *Connection) updateLoadedChunks(newCX, newCZ int) {
 			c.log.Error("unload chunk", "cx", pos.X, "cz", pos.Z, "error", err)
 		}
 		delete(c.loadedChunks, pos)
+		c.releaseChunk(pos)
 	}
 }
 
-// clampToWorldBounds clamps player position to world boundary.
-// Returns (possibly clamped) x and z. Sends a position correction if clamped.
-func (c *Connection) clampToWorldBounds(x, y, z float64, yaw, pitch float32) (float64, float64) {
-	r := c.cfg.WorldRadius
-	minBlock := float64(-r * 16)
-	maxBlock := float64(r*16 + 16)
-
-	clampedX, clampedZ := x, z
-	if clampedX < minBlock {
-		clampedX = minBlock
-	} else if clampedX >= maxBlock {
-		clampedX = maxBlock - 0.01
-	}
-	if clampedZ < minBlock {
-		clampedZ = minBlock
-	} else if clampedZ >= maxBlock {
-		clampedZ = maxBlock - 0.01
-	}
-
-	if clampedX != x || clampedZ != z {
-		_ = c.writePacket(&pkt.PositionCB{
-			X:     clampedX,
-			Y:     y,
-			Z:     clampedZ,
-			Yaw:   yaw,
-			Pitch: pitch,
-			Flags: 0x00,
-		})
+// releaseChunk frees the world's copy of a chunk this player stopped seeing,
+// unless it is a spawn chunk or another player still sees it. Unsaved
+// changes are written to the chunk's region first; a chunk that cannot be
+// saved stays loaded.
+func (c *Connection) releaseChunk(pos gen.ChunkPos) {
+	if c.world.IsSpawnChunk(pos.X, pos.Z) || c.players.ChunkInView(pos.X, pos.Z, c.self.EntityID) {
+		return
 	}
+	if c.storage != nil {
+		if err := c.storage.SaveChunk(c.world, pos.X, pos.Z); err != nil {
+			c.log.Error("save chunk before unloading", "cx", pos.X, "cz", pos.Z, "error", err)
+			return
+		}
+	}
+	c.world.UnloadChunk(pos.X, pos.Z)
+}
 
+// clampToWorldBounds clamps player position to the world border.
+// Returns (possibly clamped) x and z. Sends a position correction if clamped.
+func (c *Connection) clampToWorldBounds(x, y, z float64, yaw, pitch float32) (float64, float64) {
+	border := c.world.Border()
+	if border.Contains(x, z) {
+		return x, z
+	}
+	if pos := c.self.GetPosition(); !border.Contains(pos.X, pos.Z) {
+		// Already outside, e.g. after the border shrank: as in vanilla the
+		// border only stops players crossing it outwards, and they take
+		// border damage until they walk back in.
+		return x, z
+	}
+	clampedX, clampedZ := border.Clamp(x, z)
+	_ = c.writePacket(&pkt.PositionCB{
+		X:     clampedX,
+		Y:     y,
+		Z:     clampedZ,
+		Yaw:   yaw,
+		Pitch: pitch,
+		Flags: 0x00,
+	})
 	return clampedX, clampedZ
 }
 
-// isChunkInBounds returns whether a chunk is within the world boundary.
+// isChunkInBounds returns whether any part of a chunk is inside the world
+// border.
 func (c *Connection) isChunkInBounds(cx, cz int) bool {
-	r := c.cfg.WorldRadius
-	if r <= 0 {
-		return true
-	}
-	return cx >= -r && cx <= r && cz >= -r && cz <= r
+	return c.world.Border().ContainsChunk(cx, cz)
 }
 
 // buildSprintParticles builds WorldParticles raw data for sprint block-crack particles.
@@ -940,26 +1335,31 @@ func (c *Connection) handleUseEntity(data []byte) error {
 		}
 	}
 
-	// mouse=1 is attack.
+	// mouse=0 is interact; mouse=1 is attack.
+	if mouse == 0 {
+		return c.useNameTag(targetID)
+	}
 	if mouse != 1 {
 		return nil
 	}
 
 	target := c.players.GetByEntityID(targetID)
-	if target == nil {
+	if target == nil || target == c.self {
 		return nil
 	}
 
-	// Broadcast hurt animation to all trackers of the target.
-	c.players.BroadcastToTrackers(&pkt.EntityStatus{
-		EntityID:     targetID,
-		EntityStatus: 2, // hurt animation
-	}, targetID)
-	// Also send to the target itself.
-	_ = target.WritePacket(&pkt.EntityStatus{
-		EntityID:     targetID,
-		EntityStatus: 2,
-	})
+	// Creative and spectator players cannot be hurt.
+	mode := target.GetGameMode()
+	if mode == packet.GameModeCreative || mode == packet.GameModeSpectator {
+		return nil
+	}
+
+	damage := applyArmor(c.meleeDamage(c.self.Inventory.HeldItem()), c.totalArmorPoints(target))
+	if !c.hurtPlayer(target, damage) {
+		// Still invulnerable from the previous hit: no damage or knockback.
+		return nil
+	}
+	c.wearHeldItem(true)
 
 	// Compute knockback direction from attacker to target.
 	attackerPos := c.self.GetPosition()
@@ -989,6 +1389,39 @@ func (c *Connection) handleUseEntity(data []byte) error {
 	return nil
 }
 
+// spectate teleports a spectator to the player or entity with the given UUID
+// and attaches their camera to it. Non-spectators are ignored.
+func (c *Connection) spectate(target [16]byte) {
+	if c.self.GetGameMode() != packet.GameModeSpectator {
+		return
+	}
+
+	var entityID int32
+	var x, y, z float64
+	if p := c.players.GetByUUID(formatUUID(target)); p != nil {
+		if p == c.self {
+			return
+		}
+		pos := p.GetPosition()
+		entityID, x, y, z = p.EntityID, pos.X, pos.Y, pos.Z
+	} else if e, ok := c.players.EntityByUUID(target); ok {
+		entityID = e.ID()
+		x, y, z = e.EntityPosition()
+	} else {
+		return
+	}
+
+	c.teleportSelf(x, y, z)
+	_ = c.writePacket(&pkt.Camera{CameraID: entityID})
+}
+
+// Disallowed flight requests tolerated within flyViolationWindow before a
+// player is kicked (only when cfg.KickFlyHackers is set).
+const (
+	flyViolationLimit  = 3
+	flyViolationWindow = 10 * time.Second
+)
+
 // handleAbilitiesUpdate processes a PlayerAbilities (0x13) server-bound packet.
 func (c *Connection) handleAbilitiesUpdate(p pkt.AbilitiesSB) {
 	wantsFlying := p.Flags&int8(packet.AbilityFlying) != 0
@@ -996,25 +1429,36 @@ func (c *Connection) handleAbilitiesUpdate(p pkt.AbilitiesSB) {
 
 	// Only creative and spectator may fly.
 	if wantsFlying && mode != packet.GameModeCreative && mode != packet.GameModeSpectator {
+		if c.cfg.KickFlyHackers && c.recordFlyViolation() {
+			c.kick(kickFlying)
+			return
+		}
 		// Send corrective abilities back.
-		_ = c.writePacket(&pkt.AbilitiesCB{
-			Flags:        abilitiesForGameMode(mode),
-			FlyingSpeed:  0.05,
-			WalkingSpeed: 0.1,
-		})
+		_ = c.writePacket(abilitiesPacket(c.self))
 		return
 	}
 
 	c.self.SetFlying(wantsFlying)
 }
 
+// recordFlyViolation counts a disallowed flight request and reports whether
+// the limit has been reached within the current window.
+func (c *Connection) recordFlyViolation() bool {
+	now := time.Now()
+	if now.Sub(c.flyViolationStart) > flyViolationWindow {
+		c.flyViolationStart = now
+		c.flyViolations = 0
+	}
+	c.flyViolations++
+	return c.flyViolations >= flyViolationLimit
+}
+
 // handleRespawn processes a ClientStatus (0x16) packet.
 // ActionID 0 = perform respawn, ActionID 1 = request stats.
 func (c *Connection) handleRespawn() error {
-	if !c.dead {
+	if !c.self.IsDead() {
 		return nil
 	}
-	c.dead = false
 
 	// Send Respawn packet.
 	if err := c.writePacket(&pkt.Respawn{
@@ -1027,8 +1471,10 @@ func (c *Connection) handleRespawn() error {
 	}
 
 	// Reset position to spawn.
-	spawnY := c.world.SpawnHeight()
-	c.self.SetPosition(0.5, float64(spawnY), 0.5, 0, 0, true)
+	spawn := c.world.Spawn()
+	x, y, z := float64(spawn.X)+0.5, float64(spawn.Y), float64(spawn.Z)+0.5
+	c.self.SetPosition(x, y, z, 0, 0, true)
+	c.resetFall(y)
 
 	// Clear and resend chunks.
 	c.loadedChunks = make(map[gen.ChunkPos]struct{})
@@ -1036,11 +1482,16 @@ func (c *Connection) handleRespawn() error {
 		return fmt.Errorf("respawn send chunks: %w", err)
 	}
 
+	// The respawned client may have dropped the boss bar's wither.
+	if bar, ok := c.self.BossBar(); ok {
+		_ = c.self.WritePacket(bar.SpawnPacket(c.self.GetPosition()))
+	}
+
 	// Send position.
 	if err := c.writePacket(&pkt.PositionCB{
-		X:     0.5,
-		Y:     float64(spawnY),
-		Z:     0.5,
+		X:     x,
+		Y:     y,
+		Z:     z,
 		Yaw:   0,
 		Pitch: 0,
 		Flags: 0x00,
@@ -1048,19 +1499,14 @@ func (c *Connection) handleRespawn() error {
 		return fmt.Errorf("write respawn position: %w", err)
 	}
 
-	// Restore health.
-	_ = c.writePacket(&pkt.UpdateHealth{
-		Health:         20,
-		Food:           20,
-		FoodSaturation: 5,
-	})
+	// Restore health; effects do not survive death.
+	c.players.ClearEffects(c.self)
+	c.self.SetHealth(player.MaxHealth)
+	c.self.SetFood(player.MaxFood, player.DefaultSaturation)
+	c.sendHealth()
 
 	// Send abilities.
-	_ = c.writePacket(&pkt.AbilitiesCB{
-		Flags:        abilitiesForGameMode(c.self.GetGameMode()),
-		FlyingSpeed:  0.05,
-		WalkingSpeed: 0.1,
-	})
+	_ = c.writePacket(abilitiesPacket(c.self))
 
 	// Resync inventory.
 	_ = c.sendWindowItems()
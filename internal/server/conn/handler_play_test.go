@@ -0,0 +1,669 @@
+package conn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-theft-craft/server/internal/server/packet"
+	"github.com/go-theft-craft/server/internal/server/player"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+	"github.com/go-theft-craft/server/pkg/world"
+	"github.com/go-theft-craft/server/pkg/world/gen"
+)
+
+// rawPacket is a packet read back from a packetRecorder.
+type rawPacket struct {
+	id   int32
+	data []byte
+}
+
+// recordedPackets decodes every packet written to the connection's recorder.
+func recordedPackets(t *testing.T, c *Connection) []rawPacket {
+	t.Helper()
+	rec := c.rw.(*packetRecorder)
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	r := bytes.NewReader(rec.buf.Bytes())
+	var out []rawPacket
+	for r.Len() > 0 {
+		id, data, err := mcnet.ReadRawPacket(r)
+		if err != nil {
+			t.Fatalf("read recorded packet: %v", err)
+		}
+		out = append(out, rawPacket{id: id, data: data})
+	}
+	return out
+}
+
+// blockPlaceData builds a Block Placement payload for the given target block,
+// face and held block ID.
+func blockPlaceData(x, y, z int, face int8, blockID int16) []byte {
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.BigEndian, mcnet.EncodePosition(x, y, z))
+	buf.WriteByte(byte(face))
+	_ = binary.Write(&buf, binary.BigEndian, blockID)
+	buf.WriteByte(1)                                   // count
+	_ = binary.Write(&buf, binary.BigEndian, int16(0)) // damage
+	buf.WriteByte(0x00)                                // no NBT
+	buf.Write([]byte{8, 8, 8})                         // cursor
+	return buf.Bytes()
+}
+
+func TestBlockPlace_RejectsAboveBuildLimit(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.self.SetPosition(10.5, 250, 10.5, 0, 0, false)
+
+	// Clicking the top face of y=255 targets y=256.
+	if err := c.handleBlockPlace(blockPlaceData(0, 255, 0, 1, 1)); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+
+	if got := c.world.GetBlock(0, 256, 0); got != 0 {
+		t.Errorf("block at y=256 = %d, want 0", got)
+	}
+}
+
+func TestBlockPlace_RejectsInsidePlayer(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	// Player stands on the grass of the flat world; their feet occupy (0, 5, 0).
+	c.self.SetPosition(0.5, 5, 0.5, 0, 0, true)
+	before := c.world.GetBlock(0, 5, 0)
+
+	// Clicking the top face of (0, 4, 0) targets (0, 5, 0).
+	if err := c.handleBlockPlace(blockPlaceData(0, 4, 0, 1, 1)); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+
+	if got := c.world.GetBlock(0, 5, 0); got != before {
+		t.Errorf("block at player feet = %d, want %d", got, before)
+	}
+
+	var resynced bool
+	for _, p := range recordedPackets(t, c) {
+		if p.id != (&pkt.BlockChange{}).PacketID() {
+			continue
+		}
+		var bc pkt.BlockChange
+		if err := mcnet.Unmarshal(p.data, &bc); err != nil {
+			t.Fatalf("unmarshal block change: %v", err)
+		}
+		if bc.Location == mcnet.EncodePosition(0, 5, 0) && bc.Type == before {
+			resynced = true
+		}
+	}
+	if !resynced {
+		t.Error("expected BlockChange resync with the original block")
+	}
+}
+
+func TestBlockPlace_AllowsAdjacentToPlayer(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+
+	// Clicking the top face of (3, 4, 3) targets (3, 5, 3), well clear of the player.
+	if err := c.handleBlockPlace(blockPlaceData(3, 4, 3, 1, 1)); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+
+	if got := c.world.GetBlock(3, 5, 3); got != 1<<4 {
+		t.Errorf("block at (3,5,3) = %d, want %d", got, 1<<4)
+	}
+}
+
+func TestBlockPlace_CollisionShapes(t *testing.T) {
+	c, _, m := newTestConn("Alice")
+	c.gameData = pkt.New()
+	bob, _ := addTestPlayer(m, "Bob")
+	bob.SetPosition(5.5, 4, 5.5, 0, 0, true)
+
+	tests := []struct {
+		name    string
+		x, z    int
+		blockID int16
+		placed  bool
+	}{
+		{"stone in own feet", 0, 0, 1, false},
+		{"stone in another player's feet", 5, 5, 1, false},
+		{"torch in own feet", 0, 0, 50, true},
+		{"flower in another player's feet", 5, 5, 38, true},
+		{"stone one block away", 1, 0, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c.world.SetBlock(tt.x, 4, tt.z, 0)
+			if err := c.handleBlockPlace(blockPlaceData(tt.x, 3, tt.z, 1, tt.blockID)); err != nil {
+				t.Fatalf("handleBlockPlace: %v", err)
+			}
+			placed := c.world.GetBlock(tt.x, 4, tt.z) == int32(tt.blockID)<<4
+			if placed != tt.placed {
+				t.Errorf("placed = %v, want %v", placed, tt.placed)
+			}
+		})
+	}
+}
+
+func TestBlockPlace_ReplacesTallGrass(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	c.world.SetBlock(3, 5, 3, 31<<4|1) // tall grass on the surface
+
+	// Clicking the side of the tall grass places into it rather than beside it.
+	if err := c.handleBlockPlace(blockPlaceData(3, 5, 3, 5, 1)); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+	if got := c.world.GetBlock(3, 5, 3); got != 1<<4 {
+		t.Errorf("block at the tall grass = %#x, want stone", got)
+	}
+	if got := c.world.GetBlock(4, 5, 3); got != 0 {
+		t.Errorf("block beside the tall grass = %#x, want air", got)
+	}
+}
+
+func TestBlockPlace_OffsetsFromSolidFace(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	c.world.SetBlock(3, 5, 3, 1<<4)
+
+	if err := c.handleBlockPlace(blockPlaceData(3, 5, 3, 5, 4)); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+	if got := c.world.GetBlock(3, 5, 3); got != 1<<4 {
+		t.Errorf("clicked block = %#x, want the stone kept", got)
+	}
+	if got := c.world.GetBlock(4, 5, 3); got != 4<<4 {
+		t.Errorf("block beside the face = %#x, want cobblestone", got)
+	}
+}
+
+func TestBlockPlace_RejectsOccupiedCell(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	c.world.SetBlock(3, 5, 3, 1<<4)
+	c.world.SetBlock(3, 6, 3, 5<<4)
+
+	// The top face of the stone leads into the planks above it.
+	if err := c.handleBlockPlace(blockPlaceData(3, 5, 3, 1, 4)); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+	if got := c.world.GetBlock(3, 6, 3); got != 5<<4 {
+		t.Errorf("occupied cell = %#x, want the planks kept", got)
+	}
+}
+
+// testMob is a minimal non-player entity for spectate tests.
+type testMob struct {
+	id      int32
+	uuid    [16]byte
+	x, y, z float64
+}
+
+func (m *testMob) ID() int32                         { return m.id }
+func (m *testMob) EntityUUID() [16]byte              { return m.uuid }
+func (m *testMob) EntityPosition() (x, y, z float64) { return m.x, m.y, m.z }
+
+func spectateData(t *testing.T, target [16]byte) []byte {
+	t.Helper()
+	data, err := mcnet.Marshal(&pkt.Spectate{Target: target})
+	if err != nil {
+		t.Fatalf("marshal spectate: %v", err)
+	}
+	return data
+}
+
+func TestSpectate_TeleportsToMob(t *testing.T) {
+	c, _, m := newTestConn("Alice")
+	c.self.SetGameMode(packet.GameModeSpectator)
+	mob := &testMob{id: m.AllocateEntityID(), uuid: [16]byte{0xAB, 0xCD}, x: 40.5, y: 12, z: -8.5}
+	m.RegisterEntity(mob)
+
+	if err := c.handlePlay(0x18, spectateData(t, mob.uuid)); err != nil {
+		t.Fatalf("handlePlay spectate: %v", err)
+	}
+
+	pos := c.self.GetPosition()
+	if pos.X != mob.x || pos.Y != mob.y || pos.Z != mob.z {
+		t.Errorf("spectator at %.1f,%.1f,%.1f, want %.1f,%.1f,%.1f", pos.X, pos.Y, pos.Z, mob.x, mob.y, mob.z)
+	}
+
+	var attached bool
+	for _, p := range recordedPackets(t, c) {
+		if p.id != (&pkt.Camera{}).PacketID() {
+			continue
+		}
+		var cam pkt.Camera
+		if err := mcnet.Unmarshal(p.data, &cam); err != nil {
+			t.Fatalf("unmarshal camera: %v", err)
+		}
+		if cam.CameraID == mob.id {
+			attached = true
+		}
+	}
+	if !attached {
+		t.Error("expected Camera packet attaching to the mob")
+	}
+}
+
+func TestSpectate_IgnoredForNonSpectator(t *testing.T) {
+	c, _, m := newTestConn("Alice")
+	c.self.SetGameMode(packet.GameModeCreative)
+	mob := &testMob{id: m.AllocateEntityID(), uuid: [16]byte{0xAB, 0xCD}, x: 40.5, y: 12, z: -8.5}
+	m.RegisterEntity(mob)
+	before := c.self.GetPosition()
+
+	if err := c.handlePlay(0x18, spectateData(t, mob.uuid)); err != nil {
+		t.Fatalf("handlePlay spectate: %v", err)
+	}
+
+	if after := c.self.GetPosition(); after != before {
+		t.Errorf("non-spectator moved to %+v", after)
+	}
+	if len(recordedPackets(t, c)) != 0 {
+		t.Error("expected no packets for a non-spectator's spectate request")
+	}
+}
+
+func TestSyncHeldSlot_RestoresSavedSlot(t *testing.T) {
+	c, _, m := newTestConn("Alice")
+	sword := player.Slot{BlockID: 276, ItemCount: 1}
+	c.self.Inventory.SetSlot(3, sword)
+	c.self.Inventory.SetHeldSlot(3)
+
+	// Bob joins next to Alice and starts tracking her.
+	bob := &sentPackets{}
+	eid := m.AllocateEntityID()
+	p2 := player.NewPlayer(eid, "test-uuid-2", [16]byte{byte(eid)}, "Bob", nil, bob.write)
+	p2.SetPosition(1.5, 4, 0.5, 0, 0, true)
+	m.Add(p2)
+	bob.reset()
+
+	if err := c.syncHeldSlot(); err != nil {
+		t.Fatalf("syncHeldSlot: %v", err)
+	}
+
+	var selected bool
+	for _, p := range recordedPackets(t, c) {
+		if p.id != (&pkt.HeldItemSlotCB{}).PacketID() {
+			continue
+		}
+		var held pkt.HeldItemSlotCB
+		if err := mcnet.Unmarshal(p.data, &held); err != nil {
+			t.Fatalf("unmarshal held item slot: %v", err)
+		}
+		if held.Slot == 3 {
+			selected = true
+		}
+	}
+	if !selected {
+		t.Error("expected HeldItemSlot packet selecting slot 3")
+	}
+
+	want := player.BuildSingleEquipment(c.self.EntityID, 0, sword)
+	var equipped bool
+	for _, p := range bob.get() {
+		if eq, ok := p.(*pkt.EntityEquipment); ok && reflect.DeepEqual(eq, want) {
+			equipped = true
+		}
+	}
+	if !equipped {
+		t.Error("expected trackers to receive the held sword as equipment")
+	}
+}
+
+func TestSafeSpawnPosition_NudgesOutOfTerrain(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	surface := c.findGroundLevel(10, 64, 10)
+
+	// Saved inside the ground.
+	x, y, z := c.safeSpawnPosition(10.5, float64(surface-2), 10.5)
+	if x != 10.5 || y != float64(surface) || z != 10.5 {
+		t.Errorf("safeSpawnPosition = %.1f,%.1f,%.1f, want 10.5,%d,10.5", x, y, z, surface)
+	}
+
+	// A stone pillar built over the saved spot pushes the player above it.
+	c.world.SetBlock(10, surface, 10, 1<<4)
+	c.world.SetBlock(10, surface+1, 10, 1<<4)
+	if _, y, _ := c.safeSpawnPosition(10.5, float64(surface), 10.5); y != float64(surface+2) {
+		t.Errorf("safeSpawnPosition Y = %.1f, want %d", y, surface+2)
+	}
+}
+
+func TestSafeSpawnPosition_ValidPositionUnchanged(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	surface := c.findGroundLevel(10, 64, 10)
+
+	// Standing on the surface, and inside a non-solid tall grass block.
+	c.world.SetBlock(-5, surface, 3, 31<<4|1)
+	for _, pos := range [][3]float64{{10.5, float64(surface), 10.5}, {-4.5, float64(surface) + 0.5, 3.5}} {
+		x, y, z := c.safeSpawnPosition(pos[0], pos[1], pos[2])
+		if x != pos[0] || y != pos[1] || z != pos[2] {
+			t.Errorf("safeSpawnPosition(%v) = %.1f,%.1f,%.1f, want unchanged", pos, x, y, z)
+		}
+	}
+}
+
+// flyRequest is an abilities packet asking to start flying.
+var flyRequest = pkt.AbilitiesSB{Flags: int8(packet.AbilityFlying)}
+
+// countPackets returns how many recorded packets have the given ID.
+func countPackets(t *testing.T, c *Connection, id int32) int {
+	t.Helper()
+	n := 0
+	for _, p := range recordedPackets(t, c) {
+		if p.id == id {
+			n++
+		}
+	}
+	return n
+}
+
+func TestAbilities_SingleFlightRequestSnapsBack(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.self.SetGameMode(packet.GameModeSurvival)
+
+	c.handleAbilitiesUpdate(flyRequest)
+
+	if c.self.IsFlying() {
+		t.Error("survival player should not be allowed to fly")
+	}
+	if n := countPackets(t, c, (&pkt.AbilitiesCB{}).PacketID()); n != 1 {
+		t.Errorf("sent %d corrective abilities packets, want 1", n)
+	}
+	if c.ctx.Err() != nil {
+		t.Error("lenient default should not disconnect the player")
+	}
+}
+
+func TestAbilities_RepeatedFlightRequestsKickWhenStrict(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.cfg.KickFlyHackers = true
+	c.self.SetGameMode(packet.GameModeSurvival)
+
+	for i := 0; i < flyViolationLimit-1; i++ {
+		c.handleAbilitiesUpdate(flyRequest)
+	}
+	if c.ctx.Err() != nil {
+		t.Fatalf("kicked after %d requests, limit is %d", flyViolationLimit-1, flyViolationLimit)
+	}
+
+	c.handleAbilitiesUpdate(flyRequest)
+
+	if c.ctx.Err() == nil {
+		t.Error("expected the connection to be closed")
+	}
+	var reason string
+	for _, p := range recordedPackets(t, c) {
+		if p.id != (&pkt.KickDisconnect{}).PacketID() {
+			continue
+		}
+		var kick pkt.KickDisconnect
+		if err := mcnet.Unmarshal(p.data, &kick); err != nil {
+			t.Fatalf("unmarshal kick: %v", err)
+		}
+		reason = kick.Reason
+	}
+	if !strings.Contains(reason, "Flying is not enabled on this server.") {
+		t.Errorf("kick reason = %q, want the flying message", reason)
+	}
+}
+
+func TestUseItem_EnderPearlCooldown(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.self.Inventory.SetSlot(0, player.Slot{BlockID: 368, ItemCount: 4})
+	c.self.Inventory.SetHeldSlot(0)
+	use := blockPlaceData(-1, -1, -1, -1, 368)
+
+	if err := c.handleBlockPlace(use); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+	if got := c.self.Inventory.GetSlot(0).ItemCount; got != 3 {
+		t.Fatalf("pearls after first throw = %d, want 3", got)
+	}
+
+	// A second throw during the cooldown is ignored.
+	if err := c.handleBlockPlace(use); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+	if got := c.self.Inventory.GetSlot(0).ItemCount; got != 3 {
+		t.Errorf("pearls after throw on cooldown = %d, want 3", got)
+	}
+	if countPackets(t, c, 0x29) != 1 {
+		t.Error("expected a feedback sound for the refused throw")
+	}
+
+	for i := 0; i < itemCooldowns[368]; i++ {
+		c.self.TickCooldowns()
+	}
+	if err := c.handleBlockPlace(use); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+	if got := c.self.Inventory.GetSlot(0).ItemCount; got != 2 {
+		t.Errorf("pearls after cooldown expired = %d, want 2", got)
+	}
+}
+
+// cancelAfterWriter counts packet writes and cancels the connection after
+// the given number, like a client dropping mid-send.
+type cancelAfterWriter struct {
+	packetRecorder
+	limit  int
+	writes int
+	cancel func()
+}
+
+func (w *cancelAfterWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.writes == w.limit {
+		w.cancel()
+	}
+	return w.packetRecorder.Write(p)
+}
+
+func TestSendInitialChunks_StopsOnDisconnect(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.cfg.ViewDistance = 4
+	w := &cancelAfterWriter{limit: 3, cancel: c.cancel}
+	c.rw = w
+
+	err := c.sendInitialChunks()
+
+	if !errors.Is(err, ErrClientDisconnect) {
+		t.Errorf("sendInitialChunks error = %v, want ErrClientDisconnect", err)
+	}
+	if w.writes != 3 {
+		t.Errorf("wrote %d chunks after cancelling at 3", w.writes)
+	}
+}
+
+func TestUpdateLoadedChunks_StopsOnDisconnect(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.cfg.ViewDistance = 4
+	w := &cancelAfterWriter{limit: -1, cancel: c.cancel}
+	c.rw = w
+	c.cancel()
+
+	c.updateLoadedChunks(10, 10)
+
+	if w.writes != 0 {
+		t.Errorf("wrote %d packets for a closed connection", w.writes)
+	}
+}
+
+func TestUpdateLoadedChunks_ReleasesUnseenChunks(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.cfg.ViewDistance = 1
+	c.updateLoadedChunks(0, 0)
+
+	c.self.SetPosition(320.5, 4, 0.5, 0, 0, true)
+	c.updateLoadedChunks(20, 0)
+
+	for _, pos := range c.world.LoadedChunkPositions() {
+		if pos.X < 10 {
+			t.Errorf("chunk %v is still loaded after Alice left it", pos)
+		}
+	}
+}
+
+func TestUpdateLoadedChunks_SavesChangedChunksBeforeRelease(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	store := withTestStorage(t, c)
+	c.cfg.ViewDistance = 1
+	c.updateLoadedChunks(0, 0)
+	c.world.SetBlock(2, 5, 2, 4<<4) // cobblestone in chunk (0, 0)
+
+	c.self.SetPosition(320.5, 4, 0.5, 0, 0, true)
+	c.updateLoadedChunks(20, 0)
+
+	if slices.Contains(c.world.LoadedChunkPositions(), gen.ChunkPos{X: 0, Z: 0}) {
+		t.Fatal("chunk 0, 0 is still loaded after Alice left it")
+	}
+	saved := store.ChunkLoader()(0, 0)
+	if saved == nil {
+		t.Fatal("chunk 0, 0 was unloaded without being saved")
+	}
+	if got := saved.GetBlock(2, 5, 2); got != 4<<4 {
+		t.Errorf("saved block = %#x, want cobblestone", got)
+	}
+}
+
+func TestUpdateLoadedChunks_KeepsChunksOthersSee(t *testing.T) {
+	c, _, m := newTestConn("Alice")
+	c.cfg.ViewDistance = 1
+	addTestPlayer(m, "Bob") // at the origin
+	c.updateLoadedChunks(0, 0)
+
+	c.self.SetPosition(320.5, 4, 0.5, 0, 0, true)
+	c.updateLoadedChunks(20, 0)
+
+	if !slices.Contains(c.world.LoadedChunkPositions(), gen.ChunkPos{X: 0, Z: 0}) {
+		t.Error("chunk 0, 0 was unloaded while Bob stands in it")
+	}
+}
+
+func TestStartPlay_SyncsWeather(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	c.cfg.ViewDistance = 1
+	defer c.cancel()
+	c.world.SetWeather(world.WeatherRain, 1200)
+
+	if err := c.startPlay("Bob", "00000000-0000-0000-0000-000000000002", nil); err != nil {
+		t.Fatalf("startPlay: %v", err)
+	}
+
+	var began bool
+	for _, p := range recordedPackets(t, c) {
+		if p.id != (&pkt.GameStateChange{}).PacketID() {
+			continue
+		}
+		var gs pkt.GameStateChange
+		if err := mcnet.Unmarshal(p.data, &gs); err != nil {
+			t.Fatalf("unmarshal game state change: %v", err)
+		}
+		if gs.Reason == packet.GameStateBeginRain {
+			began = true
+		}
+	}
+	if !began {
+		t.Error("joining player was not told it is raining")
+	}
+}
+
+func TestRespawn_UsesWorldSpawn(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.cfg.ViewDistance = 1
+	c.world.SetSpawn(world.BlockPos{X: 10, Y: 4, Z: -5})
+	c.self.SetHealth(0)
+
+	if err := c.handleRespawn(); err != nil {
+		t.Fatalf("handleRespawn: %v", err)
+	}
+
+	if pos := c.self.GetPosition(); pos.X != 10.5 || pos.Y != 4 || pos.Z != -4.5 {
+		t.Errorf("respawned at (%v, %v, %v), want (10.5, 4, -4.5)", pos.X, pos.Y, pos.Z)
+	}
+}
+
+func TestCmdSpawn_TeleportsToWorldSpawn(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.self.SetPosition(30.5, 4, 30.5, 0, 0, true)
+
+	c.handleCommand("/setworldspawn ~ ~ ~")
+	if pos, ok := c.world.SpawnPoint(); !ok || pos != (world.BlockPos{X: 30, Y: 4, Z: 30}) {
+		t.Fatalf("spawn = %v (set %v), want (30, 4, 30)", pos, ok)
+	}
+
+	c.self.SetPosition(-100.5, 4, 7.5, 0, 0, true)
+	c.handleCommand("/spawn")
+	if pos := c.self.GetPosition(); pos.X != 30.5 || pos.Y != 4 || pos.Z != 30.5 {
+		t.Errorf("teleported to (%v, %v, %v), want (30.5, 4, 30.5)", pos.X, pos.Y, pos.Z)
+	}
+}
+
+func TestStartPlay_SendsPlayerListHeader(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	c.cfg.ViewDistance = 1
+	c.cfg.PlayerListHeader = "Welcome"
+	c.cfg.PlayerListFooter = "go-theft-craft"
+	defer c.cancel()
+
+	if err := c.startPlay("Bob", "00000000-0000-0000-0000-000000000002", nil); err != nil {
+		t.Fatalf("startPlay: %v", err)
+	}
+
+	var got *pkt.PlayerlistHeader
+	for _, p := range recordedPackets(t, c) {
+		if p.id != (pkt.PlayerlistHeader{}).PacketID() {
+			continue
+		}
+		got = &pkt.PlayerlistHeader{}
+		if err := mcnet.Unmarshal(p.data, got); err != nil {
+			t.Fatalf("unmarshal player list header: %v", err)
+		}
+	}
+	if got == nil || got.Header != `{"text":"Welcome"}` || got.Footer != `{"text":"go-theft-craft"}` {
+		t.Errorf("player list header = %+v, want the configured header and footer", got)
+	}
+}
+
+func TestStartPlay_NoPlayerListHeaderByDefault(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	c.cfg.ViewDistance = 1
+	defer c.cancel()
+
+	if err := c.startPlay("Bob", "00000000-0000-0000-0000-000000000002", nil); err != nil {
+		t.Fatalf("startPlay: %v", err)
+	}
+	if n := countPackets(t, c, pkt.PlayerlistHeader{}.PacketID()); n != 0 {
+		t.Errorf("sent %d player list header packets without a configured header", n)
+	}
+}
+
+func TestKeepAlive_RecordsPing(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.lastKeepAliveID = 42
+	c.keepAliveAcked = false
+	c.lastKeepAliveSent = time.Now().Add(-80 * time.Millisecond)
+
+	data, err := mcnet.Marshal(&pkt.KeepAliveSB{KeepAliveID: 42})
+	if err != nil {
+		t.Fatalf("marshal keep alive: %v", err)
+	}
+	if err := c.handlePlay(0x00, data); err != nil {
+		t.Fatalf("handlePlay: %v", err)
+	}
+
+	if ping := c.self.Ping(); ping < 80*time.Millisecond || ping > time.Second {
+		t.Errorf("ping = %v, want about 80ms", ping)
+	}
+}
@@ -0,0 +1,165 @@
+package conn
+
+import (
+	"math"
+
+	"github.com/go-theft-craft/server/internal/server/packet"
+	"github.com/go-theft-craft/server/internal/server/player"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	"github.com/go-theft-craft/server/pkg/world"
+)
+
+// fallDamageGrace is the distance in blocks a player can fall unharmed.
+const fallDamageGrace = 3
+
+// Block IDs that break a fall: liquids, climbables and cobweb.
+var fallBreakingBlocks = map[int32]bool{
+	8: true, 9: true, // water
+	10: true, 11: true, // lava
+	30:  true, // cobweb
+	65:  true, // ladder
+	106: true, // vine
+}
+
+// updateFall tracks the highest point reached while airborne and applies fall
+// damage when the player lands. Flying, riding, swimming and climbing reset it.
+func (c *Connection) updateFall(x, y, z float64, onGround bool) {
+	_, riding := c.self.Vehicle()
+	feet := c.world.GetBlock(int(math.Floor(x)), int(math.Floor(y)), int(math.Floor(z))) >> 4
+	if c.self.IsFlying() || riding || fallBreakingBlocks[feet] {
+		c.resetFall(y)
+		return
+	}
+
+	if !onGround {
+		if !c.falling || y > c.fallPeakY {
+			c.fallPeakY = y
+		}
+		c.falling = true
+		return
+	}
+	if c.falling {
+		c.applyFallDamage(c.fallPeakY - y)
+	}
+	c.resetFall(y)
+}
+
+// resetFall forgets any fall in progress, e.g. after a teleport.
+func (c *Connection) resetFall(y float64) {
+	c.fallPeakY = y
+	c.falling = false
+}
+
+// applyFallDamage deals one half-heart per whole block fallen beyond the
+// grace distance. Creative and spectator players take no fall damage.
+func (c *Connection) applyFallDamage(distance float64) {
+	mode := c.self.GetGameMode()
+	if mode == packet.GameModeCreative || mode == packet.GameModeSpectator {
+		return
+	}
+	damage := math.Floor(distance - fallDamageGrace)
+	if damage <= 0 {
+		return
+	}
+	c.hurtPlayer(c.self, float32(damage))
+}
+
+// applyBorderDamage hurts the player for standing too far outside the world
+// border. Invulnerability after each hit limits it to twice a second.
+// Creative and spectator players are unaffected.
+func (c *Connection) applyBorderDamage() {
+	mode := c.self.GetGameMode()
+	if mode == packet.GameModeCreative || mode == packet.GameModeSpectator {
+		return
+	}
+	pos := c.self.GetPosition()
+	beyond := c.world.Border().DistanceOutside(pos.X, pos.Z) - world.BorderDamageBuffer
+	if beyond <= 0 {
+		return
+	}
+	c.hurtPlayer(c.self, float32(max(1, math.Floor(beyond*world.BorderDamagePerBlock))))
+}
+
+// hurtPlayer deals damage to target, plays the hurt animation for the target
+// and everyone tracking it, and kills the target when its health reaches
+// zero. It returns false if the target is dead or still invulnerable from a
+// recent hit.
+func (c *Connection) hurtPlayer(target *player.Player, amount float32) bool {
+	if !target.Damage(amount) {
+		return false
+	}
+
+	hurt := &pkt.EntityStatus{EntityID: target.EntityID, EntityStatus: 2} // hurt animation
+	c.players.BroadcastToTrackers(hurt, target.EntityID)
+	_ = target.WritePacket(hurt)
+	_ = target.WritePacket(target.HealthPacket())
+
+	if target.IsDead() {
+		c.killPlayer(target)
+	}
+	return true
+}
+
+// killPlayer shows the death animation of target to everyone tracking it
+// and, outside creative, drops its inventory unless the keepInventory rule
+// is on. The target's client opens the respawn screen on its own once it
+// receives zero health.
+func (c *Connection) killPlayer(target *player.Player) {
+	if target == c.self {
+		c.resetFall(target.GetPosition().Y)
+	}
+	c.players.BroadcastToTrackers(&pkt.EntityStatus{
+		EntityID:     target.EntityID,
+		EntityStatus: 3, // death animation
+	}, target.EntityID)
+	if target.GetGameMode() != packet.GameModeCreative && !c.world.GameRuleBool(world.RuleKeepInventory) {
+		c.dropInventory(target)
+	}
+}
+
+// dropInventory empties target's inventory and armor, scattering the items
+// around it as dropped item entities.
+func (c *Connection) dropInventory(target *player.Player) {
+	var items []player.Slot
+	target.Inventory.ReadSlots(func(slots [36]player.Slot, armor [4]player.Slot) {
+		for _, s := range append(slots[:], armor[:]...) {
+			if !s.IsEmpty() {
+				items = append(items, s)
+			}
+		}
+	})
+	if len(items) == 0 {
+		return
+	}
+
+	before := target.Inventory.ToProtocolSlots()
+	var slots [36]player.Slot
+	var armor [4]player.Slot
+	for i := range slots {
+		slots[i] = player.EmptySlot
+	}
+	for i := range armor {
+		armor[i] = player.EmptySlot
+	}
+	target.Inventory.ApplyState(slots, armor, target.Inventory.GetHeldSlot())
+
+	pos := target.GetPosition()
+	for _, item := range items {
+		yaw := c.DropRNG.Float32() * 360
+		c.players.SpawnItemEntity(target.EntityID, item, pos.X, pos.Y+1.3, pos.Z, yaw, c.groundAtFunc())
+	}
+
+	if target == c.self {
+		_ = c.sendWindowItems()
+	} else {
+		syncInventorySlots(target, before)
+	}
+	for _, eq := range player.BuildEquipmentPackets(target.EntityID, target.Inventory) {
+		c.players.BroadcastToTrackers(eq, target.EntityID)
+	}
+}
+
+// sendHealth sends the player's current health and food.
+func (c *Connection) sendHealth() {
+	_ = c.writePacket(c.self.HealthPacket())
+}
@@ -0,0 +1,158 @@
+package conn
+
+import (
+	"testing"
+
+	"github.com/go-theft-craft/server/internal/server/packet"
+	"github.com/go-theft-craft/server/internal/server/player"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+)
+
+// fall moves the player straight down from y=from to y=to, landing at the end.
+func fall(c *Connection, from, to float64) {
+	for y := from; y > to; y -= 2 {
+		c.handlePositionUpdate(0.5, y, 0.5, 0, 0, false, true, false)
+	}
+	c.handlePositionUpdate(0.5, to, 0.5, 0, 0, true, true, false)
+}
+
+// lastHealth returns the health in the last UpdateHealth sent to the player.
+func lastHealth(sp *sentPackets) (float32, bool) {
+	var health float32
+	found := false
+	for _, p := range sp.get() {
+		if u, ok := p.(*pkt.UpdateHealth); ok {
+			health, found = u.Health, true
+		}
+	}
+	return health, found
+}
+
+func TestFallDamage(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	c.self.SetGameMode(packet.GameModeSurvival)
+
+	fall(c, 80, 64)
+
+	// 16 blocks minus 3 blocks of grace = 13 half-hearts.
+	if got := c.self.GetHealth(); got != player.MaxHealth-13 {
+		t.Errorf("health = %v, want %v", got, player.MaxHealth-13)
+	}
+	if got, ok := lastHealth(sp); !ok || got != player.MaxHealth-13 {
+		t.Errorf("UpdateHealth = %v (sent %v), want %v", got, ok, player.MaxHealth-13)
+	}
+	if c.self.IsDead() {
+		t.Error("player should survive a 16 block fall")
+	}
+}
+
+func TestFallDamage_ShortFallHarmless(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	c.self.SetGameMode(packet.GameModeSurvival)
+
+	fall(c, 67, 64)
+
+	if got := c.self.GetHealth(); got != player.MaxHealth {
+		t.Errorf("health = %v after a 3 block fall, want %v", got, player.MaxHealth)
+	}
+	if _, ok := lastHealth(sp); ok {
+		t.Error("UpdateHealth sent for a harmless fall")
+	}
+}
+
+func TestFallDamage_Kills(t *testing.T) {
+	c, sp, _ := newTestConn("Alice")
+	c.self.SetGameMode(packet.GameModeSurvival)
+
+	fall(c, 100, 64)
+
+	if !c.self.IsDead() {
+		t.Error("expected a 36 block fall to kill the player")
+	}
+	if got, _ := lastHealth(sp); got != 0 {
+		t.Errorf("UpdateHealth = %v, want 0", got)
+	}
+}
+
+func TestFallDamage_CreativeAndSpectatorExempt(t *testing.T) {
+	for _, mode := range []uint8{packet.GameModeCreative, packet.GameModeSpectator} {
+		c, _, _ := newTestConn("Alice")
+		c.self.SetGameMode(mode)
+
+		fall(c, 80, 64)
+
+		if got := c.self.GetHealth(); got != player.MaxHealth {
+			t.Errorf("mode %d: health = %v, want %v", mode, got, player.MaxHealth)
+		}
+	}
+}
+
+func TestFallDamage_TeleportResetsFall(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.self.SetGameMode(packet.GameModeSurvival)
+
+	c.handlePositionUpdate(0.5, 120, 0.5, 0, 0, false, true, false)
+	c.teleportSelf(0.5, 65, 0.5)
+	c.handlePositionUpdate(0.5, 64, 0.5, 0, 0, true, true, false)
+
+	if got := c.self.GetHealth(); got != player.MaxHealth {
+		t.Errorf("health = %v after teleport, want %v", got, player.MaxHealth)
+	}
+}
+
+// itemSpawns counts the dropped item entities among sent packets.
+func itemSpawns(sp *sentPackets) int {
+	n := 0
+	for _, p := range sp.get() {
+		if s, ok := p.(*pkt.SpawnEntity); ok && s.Data[1] == 2 { // object type 2 = item
+			n++
+		}
+	}
+	return n
+}
+
+func TestDeath_ScattersEveryStack(t *testing.T) {
+	c, _, m := newTestConn("Alice")
+	c.self.SetGameMode(packet.GameModeSurvival)
+	clearInventory(c.self)
+	for i := range 4 {
+		c.self.Inventory.SetArmor(i, player.EmptySlot)
+	}
+	c.self.Inventory.SetSlot(0, player.Slot{BlockID: 1, ItemCount: 5})
+	c.self.Inventory.SetSlot(20, player.Slot{BlockID: 264, ItemCount: 2})
+	c.self.Inventory.SetArmor(3, player.Slot{BlockID: 298, ItemCount: 1})
+	_, bob := addTestPlayer(m, "Bob")
+
+	c.hurtPlayer(c.self, 100)
+
+	if n := itemSpawns(bob); n != 3 {
+		t.Errorf("spawned %d item entities, want one for each of the 3 stacks", n)
+	}
+	for _, i := range []int{0, 20} {
+		if s := c.self.Inventory.GetSlot(i); !s.IsEmpty() {
+			t.Errorf("slot %d = %+v, want it dropped", i, s)
+		}
+	}
+	if s := c.self.Inventory.GetArmor(3); !s.IsEmpty() {
+		t.Errorf("helmet = %+v, want it dropped", s)
+	}
+}
+
+func TestDeath_KeepInventoryDropsNothing(t *testing.T) {
+	c, _, m := newTestConn("Alice")
+	c.self.SetGameMode(packet.GameModeSurvival)
+	if err := c.world.SetGameRule("keepInventory", "true"); err != nil {
+		t.Fatal(err)
+	}
+	c.self.Inventory.SetArmor(0, player.Slot{BlockID: 301, ItemCount: 1})
+	_, bob := addTestPlayer(m, "Bob")
+
+	c.hurtPlayer(c.self, 100)
+
+	if n := itemSpawns(bob); n != 0 {
+		t.Errorf("spawned %d item entities, want none with keepInventory", n)
+	}
+	if s := c.self.Inventory.GetArmor(0); s.BlockID != 301 {
+		t.Errorf("boots = %+v, want them kept", s)
+	}
+}
@@ -2,11 +2,11 @@ package conn
 
 import (
 	"bytes"
-	"encoding/binary"
 	"fmt"
 
 	"github.com/go-theft-craft/server/internal/server/player"
 	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	"github.com/go-theft-craft/server/pkg/item"
 	mcnet "github.com/go-theft-craft/server/pkg/protocol"
 )
 
@@ -31,76 +31,43 @@ const (
 	slotHotbarStart = 36
 	slotHotbarEnd   = 44
 
-	slotTotal = 45
-
 	slotOutside = -999 // click outside window
 )
 
-// sendWindowItems sends the full Window 0 (player inventory) to the client.
+// sendWindowItems sends the full contents of the open window to the client.
 func (c *Connection) sendWindowItems() error {
-	proto := c.self.Inventory.ToProtocolSlots()
-
-	// Overlay crafting slots from connection state.
-	proto[slotCraftOutput] = c.craftingOutput
-	for i := 0; i < slotCraftCount; i++ {
-		proto[slotCraftStart+i] = c.craftingGrid[i]
-	}
+	total := c.window.total()
 
-	var buf bytes.Buffer
-	buf.WriteByte(0) // window ID = 0
-	_ = binary.Write(&buf, binary.BigEndian, int16(slotTotal))
-	for _, s := range proto {
-		_ = player.WriteSlot(&buf, s)
+	items := make([]player.Slot, total)
+	for s := range total {
+		items[s] = c.getWindowSlot(s)
 	}
-	return c.writePacket(&pkt.WindowItems{Data: buf.Bytes()})
+	return c.writePacket(&pkt.WindowItems{WindowID: c.windowID, Items: items})
 }
 
 // sendSetSlot sends a single slot update to the client.
 func (c *Connection) sendSetSlot(windowID int8, slotIndex int16, slot player.Slot) error {
-	var buf bytes.Buffer
-	buf.WriteByte(byte(windowID))
-	_ = binary.Write(&buf, binary.BigEndian, slotIndex)
-	_ = player.WriteSlot(&buf, slot)
-	return c.writePacket(&pkt.SetSlot{Data: buf.Bytes()})
+	return c.writePacket(&pkt.SetSlot{WindowID: windowID, Slot: slotIndex, Item: slot})
 }
 
 // handleWindowClick processes a WindowClick (0x0E) packet.
 func (c *Connection) handleWindowClick(data []byte) error {
-	r := bytes.NewReader(data)
-
-	windowID, err := mcnet.ReadU8(r)
-	if err != nil {
-		return fmt.Errorf("read window id: %w", err)
+	// The clicked item is not used for validation.
+	var click pkt.WindowClick
+	if err := mcnet.Unmarshal(data, &click); err != nil {
+		return fmt.Errorf("unmarshal window click: %w", err)
 	}
+	windowID, slotIndex, button, actionID, mode := click.WindowID, click.Slot, click.MouseButton, click.Action, click.Mode
 
-	slotIndex, err := mcnet.ReadI16(r)
-	if err != nil {
-		return fmt.Errorf("read slot: %w", err)
+	// The open container's block was broken and the client told to close
+	// its window, so the player is back in their inventory.
+	if c.container != nil && c.container.Removed() {
+		c.closeWindow()
 	}
 
-	button, err := mcnet.ReadI8(r)
-	if err != nil {
-		return fmt.Errorf("read button: %w", err)
-	}
-
-	actionID, err := mcnet.ReadI16(r)
-	if err != nil {
-		return fmt.Errorf("read action id: %w", err)
-	}
-
-	mode, _, err := mcnet.ReadVarInt(r)
-	if err != nil {
-		return fmt.Errorf("read mode: %w", err)
-	}
-
-	// Read clicked item (we don't use it for validation, but must consume it).
-	if _, err := readSlot(r); err != nil {
-		return fmt.Errorf("read clicked item: %w", err)
-	}
-
-	// Only handle player inventory (window 0) for now.
-	if windowID != 0 {
-		return c.sendTransaction(0, actionID, false)
+	// Reject clicks for a window that is not open.
+	if windowID != c.windowID {
+		return c.sendTransaction(int8(windowID), actionID, false)
 	}
 
 	c.log.Info("window click", "slot", slotIndex, "button", button, "mode", mode, "craftOutput", c.craftingOutput, "cursor", c.cursorSlot)
@@ -113,7 +80,7 @@ func (c *Connection) handleWindowClick(data []byte) error {
 	_ = c.sendSetSlot(-1, -1, c.cursorSlot)
 
 	// Always accept the transaction.
-	return c.sendTransaction(0, actionID, true)
+	return c.sendTransaction(int8(windowID), actionID, true)
 }
 
 func (c *Connection) sendTransaction(windowID int8, actionID int16, accepted bool) error {
@@ -143,34 +110,49 @@ func (c *Connection) dispatchClick(slot int16, button int8, mode int) {
 	}
 }
 
-// getWindowSlot reads a slot from the player inventory window (0-44).
+// getWindowSlot reads a slot from the open window, using its layout to
+// locate the crafting grid and the player inventory sections.
 func (c *Connection) getWindowSlot(slot int16) player.Slot {
+	l := c.window
 	switch {
-	case slot == slotCraftOutput:
+	case c.isContainerSlot(slot):
+		return c.container.Get(int(slot))
+	case l.isCraftOutput(slot):
 		return c.craftingOutput
-	case slot >= slotCraftStart && slot <= slotCraftEnd:
-		return c.craftingGrid[slot-slotCraftStart]
-	case slot >= slotArmorStart && slot <= slotHotbarEnd:
-		return c.self.Inventory.GetProtocolSlot(int(slot))
-	default:
-		return player.EmptySlot
+	case l.isCraftSlot(slot):
+		return c.craftingGrid[slot-l.craftStart]
 	}
+	if invSlot, ok := l.inventorySlot(slot); ok {
+		return c.self.Inventory.GetProtocolSlot(int(invSlot))
+	}
+	return player.EmptySlot
 }
 
-// setWindowSlot writes a slot to the player inventory window (0-44)
-// and broadcasts equipment changes to trackers if needed.
+// setWindowSlot writes a slot to the open window and broadcasts equipment
+// changes to trackers if needed.
 func (c *Connection) setWindowSlot(slot int16, item player.Slot) {
+	l := c.window
 	switch {
-	case slot == slotCraftOutput:
+	case c.isContainerSlot(slot):
+		c.container.Set(int(slot), item, c.self.EntityID)
+	case l.isCraftOutput(slot):
 		c.craftingOutput = item
-	case slot >= slotCraftStart && slot <= slotCraftEnd:
-		c.craftingGrid[slot-slotCraftStart] = item
-	case slot >= slotArmorStart && slot <= slotHotbarEnd:
-		c.self.Inventory.SetProtocolSlot(int(slot), item)
-		c.broadcastEquipmentIfNeeded(slot)
+	case l.isCraftSlot(slot):
+		c.craftingGrid[slot-l.craftStart] = item
+	default:
+		if invSlot, ok := l.inventorySlot(slot); ok {
+			c.setInventorySlot(invSlot, item)
+		}
 	}
 }
 
+// setInventorySlot writes a player inventory slot by its window 0 index
+// (armor, main or hotbar) regardless of which window is open.
+func (c *Connection) setInventorySlot(invSlot int16, item player.Slot) {
+	c.self.Inventory.SetProtocolSlot(int(invSlot), item)
+	c.broadcastEquipmentIfNeeded(invSlot)
+}
+
 // broadcastEquipmentIfNeeded sends equipment updates to trackers when
 // armor or held item slots change.
 func (c *Connection) broadcastEquipmentIfNeeded(protoSlot int16) {
@@ -178,22 +160,22 @@ func (c *Connection) broadcastEquipmentIfNeeded(protoSlot int16) {
 	switch {
 	case protoSlot == slotHelmet:
 		slot := c.self.Inventory.GetArmor(3)
-		c.players.BroadcastToTrackers(&pkt.EntityEquipment{Data: player.BuildSingleEquipment(eid, 4, slot)}, eid)
+		c.players.BroadcastToTrackers(player.BuildSingleEquipment(eid, 4, slot), eid)
 	case protoSlot == slotChestplate:
 		slot := c.self.Inventory.GetArmor(2)
-		c.players.BroadcastToTrackers(&pkt.EntityEquipment{Data: player.BuildSingleEquipment(eid, 3, slot)}, eid)
+		c.players.BroadcastToTrackers(player.BuildSingleEquipment(eid, 3, slot), eid)
 	case protoSlot == slotLeggings:
 		slot := c.self.Inventory.GetArmor(1)
-		c.players.BroadcastToTrackers(&pkt.EntityEquipment{Data: player.BuildSingleEquipment(eid, 2, slot)}, eid)
+		c.players.BroadcastToTrackers(player.BuildSingleEquipment(eid, 2, slot), eid)
 	case protoSlot == slotBoots:
 		slot := c.self.Inventory.GetArmor(0)
-		c.players.BroadcastToTrackers(&pkt.EntityEquipment{Data: player.BuildSingleEquipment(eid, 1, slot)}, eid)
+		c.players.BroadcastToTrackers(player.BuildSingleEquipment(eid, 1, slot), eid)
 	case protoSlot >= slotHotbarStart && protoSlot <= slotHotbarEnd:
 		// Check if this is the active hotbar slot.
 		hotbarIdx := protoSlot - slotHotbarStart
 		if hotbarIdx == int16(c.self.Inventory.GetHeldSlot()) {
 			heldItem := c.self.Inventory.HeldItem()
-			c.players.BroadcastToTrackers(&pkt.EntityEquipment{Data: player.BuildSingleEquipment(eid, 0, heldItem)}, eid)
+			c.players.BroadcastToTrackers(player.BuildSingleEquipment(eid, 0, heldItem), eid)
 		}
 	}
 }
@@ -216,12 +198,13 @@ func (c *Connection) handleNormalClick(slot int16, button int8) {
 		return
 	}
 
-	if slot < 0 || slot > slotHotbarEnd {
+	l := c.window
+	if slot < 0 || slot > l.hotbarEnd() {
 		return
 	}
 
 	// Clicking crafting output.
-	if slot == slotCraftOutput {
+	if l.isCraftOutput(slot) {
 		if c.craftingOutput.IsEmpty() {
 			return
 		}
@@ -231,7 +214,7 @@ func (c *Connection) handleNormalClick(slot int16, button int8) {
 				return
 			}
 			newCount := int(c.cursorSlot.ItemCount) + int(c.craftingOutput.ItemCount)
-			if newCount > 64 {
+			if newCount > c.maxStackSize(c.cursorSlot.BlockID) {
 				return
 			}
 			c.cursorSlot.ItemCount = int8(newCount)
@@ -243,6 +226,12 @@ func (c *Connection) handleNormalClick(slot int16, button int8) {
 		return
 	}
 
+	// A furnace's result slot only gives items out.
+	if c.isFurnaceResult(slot) {
+		c.takeFromResult(slot, button)
+		return
+	}
+
 	current := c.getWindowSlot(slot)
 
 	if button == 0 { // Left click
@@ -259,7 +248,7 @@ func (c *Connection) handleNormalClick(slot int16, button int8) {
 			c.cursorSlot = player.EmptySlot
 		} else if canStack(c.cursorSlot, current) {
 			// Merge cursor into slot.
-			space := 64 - int(current.ItemCount)
+			space := c.maxStackSize(current.BlockID) - int(current.ItemCount)
 			if space <= 0 {
 				// Swap.
 				c.cursorSlot, current = current, c.cursorSlot
@@ -300,7 +289,7 @@ func (c *Connection) handleNormalClick(slot int16, button int8) {
 			if c.cursorSlot.ItemCount <= 0 {
 				c.cursorSlot = player.EmptySlot
 			}
-		} else if !c.cursorSlot.IsEmpty() && canStack(c.cursorSlot, current) && current.ItemCount < 64 {
+		} else if !c.cursorSlot.IsEmpty() && canStack(c.cursorSlot, current) && int(current.ItemCount) < c.maxStackSize(current.BlockID) {
 			// Place one from cursor onto existing stack.
 			current.ItemCount++
 			c.setWindowSlot(slot, current)
@@ -316,21 +305,18 @@ func (c *Connection) handleNormalClick(slot int16, button int8) {
 	}
 
 	// Update crafting output if a crafting slot was modified.
-	if slot >= slotCraftStart && slot <= slotCraftEnd {
+	if l.isCraftSlot(slot) {
 		c.updateCraftingOutput()
 	}
 }
 
 // handleShiftClick handles mode 1: shift-click to move items between sections.
 func (c *Connection) handleShiftClick(slot int16, _ int8) {
-	if slot < 0 || slot > slotHotbarEnd || slot == slotCraftOutput {
-		// Shift-click crafting output: take result and auto-move.
-		if slot == slotCraftOutput && !c.craftingOutput.IsEmpty() {
-			result := c.craftingOutput
-			if c.tryAddToSection(result, slotMainStart, slotHotbarEnd) {
-				c.consumeCraftingIngredients()
-				c.updateCraftingOutput()
-			}
+	l := c.window
+	if slot < 0 || slot > l.hotbarEnd() || l.isCraftOutput(slot) {
+		// Shift-click crafting output: craft repeatedly and auto-move.
+		if l.isCraftOutput(slot) && !c.craftingOutput.IsEmpty() {
+			c.shiftCraft()
 		}
 		return
 	}
@@ -340,14 +326,28 @@ func (c *Connection) handleShiftClick(slot int16, _ int8) {
 		return
 	}
 
+	// With a container open, items move between it and the inventory.
+	if c.container != nil {
+		switch {
+		case c.isContainerSlot(slot):
+			c.moveToSection(slot, item, l.mainStart, l.hotbarEnd())
+			return
+		case slot >= l.mainStart && slot <= l.hotbarEnd():
+			if lo, hi, ok := c.containerTarget(item); ok {
+				c.moveToSection(slot, item, lo, hi)
+				return
+			}
+		}
+	}
+
 	moved := false
 	switch {
-	case slot >= slotArmorStart && slot <= slotArmorEnd:
+	case l.isArmorSlot(slot):
 		// Armor → main inventory or hotbar.
-		moved = c.tryAddToSection(item, slotMainStart, slotHotbarEnd)
-	case slot >= slotMainStart && slot <= slotMainEnd:
+		moved = c.tryAddToSection(item, l.mainStart, l.hotbarEnd())
+	case slot >= l.mainStart && slot <= l.mainEnd():
 		// Main inventory → try armor first if applicable, then hotbar.
-		if armorSlot := armorSlotForItem(item.BlockID); armorSlot >= 0 {
+		if armorSlot, ok := l.windowSlot(armorSlotForItem(item.BlockID)); ok {
 			existing := c.getWindowSlot(armorSlot)
 			if existing.IsEmpty() {
 				c.setWindowSlot(armorSlot, item)
@@ -355,11 +355,11 @@ func (c *Connection) handleShiftClick(slot int16, _ int8) {
 			}
 		}
 		if !moved {
-			moved = c.tryAddToSection(item, slotHotbarStart, slotHotbarEnd)
+			moved = c.tryAddToSection(item, l.hotbarStart, l.hotbarEnd())
 		}
-	case slot >= slotHotbarStart && slot <= slotHotbarEnd:
+	case slot >= l.hotbarStart && slot <= l.hotbarEnd():
 		// Hotbar → try armor first if applicable, then main inventory.
-		if armorSlot := armorSlotForItem(item.BlockID); armorSlot >= 0 {
+		if armorSlot, ok := l.windowSlot(armorSlotForItem(item.BlockID)); ok {
 			existing := c.getWindowSlot(armorSlot)
 			if existing.IsEmpty() {
 				c.setWindowSlot(armorSlot, item)
@@ -367,30 +367,69 @@ func (c *Connection) handleShiftClick(slot int16, _ int8) {
 			}
 		}
 		if !moved {
-			moved = c.tryAddToSection(item, slotMainStart, slotMainEnd)
+			moved = c.tryAddToSection(item, l.mainStart, l.mainEnd())
 		}
-	case slot >= slotCraftStart && slot <= slotCraftEnd:
+	case l.isCraftSlot(slot):
 		// Crafting grid → main or hotbar.
-		moved = c.tryAddToSection(item, slotMainStart, slotHotbarEnd)
+		moved = c.tryAddToSection(item, l.mainStart, l.hotbarEnd())
 	}
 
 	if moved {
 		c.setWindowSlot(slot, player.EmptySlot)
-		if slot >= slotCraftStart && slot <= slotCraftEnd {
+		if l.isCraftSlot(slot) {
 			c.updateCraftingOutput()
 		}
 	}
 }
 
+// maxShiftCrafts bounds repeat-crafting on a single shift-click. Each craft
+// consumes at least one item per occupied grid slot, so a grid of full
+// stacks can never match more often than this.
+const maxShiftCrafts = 64
+
+// shiftCraft crafts the current output repeatedly into the main inventory and
+// hotbar until an ingredient runs out, the grid stops matching, or the result
+// no longer fits.
+func (c *Connection) shiftCraft() {
+	l := c.window
+	for i := 0; i < maxShiftCrafts; i++ {
+		result := c.craftingOutput
+		if result.IsEmpty() || c.sectionSpace(result, l.mainStart, l.hotbarEnd()) < int(result.ItemCount) {
+			break
+		}
+		c.tryAddToSection(result, l.mainStart, l.hotbarEnd())
+		c.consumeCraftingIngredients()
+		c.craftingOutput = c.matchCraftingRecipe()
+	}
+	c.updateCraftingOutput()
+}
+
+// sectionSpace returns how many of item would fit into slots [lo, hi].
+func (c *Connection) sectionSpace(item player.Slot, lo, hi int16) int {
+	stackSize := c.maxStackSize(item.BlockID)
+	space := 0
+	for s := lo; s <= hi; s++ {
+		existing := c.getWindowSlot(s)
+		switch {
+		case existing.IsEmpty():
+			space += stackSize
+		case canStack(existing, item):
+			space += max(stackSize-int(existing.ItemCount), 0)
+		}
+	}
+	return space
+}
+
 // tryAddToSection tries to add an item into slots [lo, hi]. Returns true if fully placed.
 func (c *Connection) tryAddToSection(item player.Slot, lo, hi int16) bool {
 	remaining := int(item.ItemCount)
+	stackSize := c.maxStackSize(item.BlockID)
 
 	// First pass: try to merge into existing stacks.
 	for s := lo; s <= hi && remaining > 0; s++ {
 		existing := c.getWindowSlot(s)
-		if !existing.IsEmpty() && canStack(existing, item) && existing.ItemCount < 64 {
-			space := 64 - int(existing.ItemCount)
+		if !existing.IsEmpty() && canStack(existing, item) && int(existing.ItemCount) < stackSize {
+			space := stackSize - int(existing.ItemCount)
 			transfer := remaining
 			if transfer > space {
 				transfer = space
@@ -406,8 +445,8 @@ func (c *Connection) tryAddToSection(item player.Slot, lo, hi int16) bool {
 		existing := c.getWindowSlot(s)
 		if existing.IsEmpty() {
 			place := remaining
-			if place > 64 {
-				place = 64
+			if place > stackSize {
+				place = stackSize
 			}
 			c.setWindowSlot(s, player.Slot{BlockID: item.BlockID, ItemCount: int8(place), ItemDamage: item.ItemDamage})
 			remaining -= place
@@ -417,36 +456,84 @@ func (c *Connection) tryAddToSection(item player.Slot, lo, hi int16) bool {
 	return remaining == 0
 }
 
+// moveToSection moves as much of item, taken from slot, as fits into slots
+// [lo, hi] and leaves the rest behind.
+func (c *Connection) moveToSection(slot int16, item player.Slot, lo, hi int16) {
+	n := min(c.sectionSpace(item, lo, hi), int(item.ItemCount))
+	if n == 0 {
+		return
+	}
+	moving := item
+	moving.ItemCount = int8(n)
+	c.tryAddToSection(moving, lo, hi)
+
+	item.ItemCount -= int8(n)
+	if item.ItemCount <= 0 {
+		item = player.EmptySlot
+	}
+	c.setWindowSlot(slot, item)
+}
+
 // handleNumberKey handles mode 2: pressing number keys 1-9 to swap with hotbar.
+// Over the crafting output it crafts once into the chosen hotbar slot.
 func (c *Connection) handleNumberKey(slot int16, button int8) {
-	if slot < 0 || slot > slotHotbarEnd {
+	l := c.window
+	if slot < 0 || slot > l.hotbarEnd() {
 		return
 	}
-	hotbarSlot := int16(slotHotbarStart) + int16(button)
-	if hotbarSlot < slotHotbarStart || hotbarSlot > slotHotbarEnd {
+	if button < 0 || int(button) >= hotbarSize {
+		return
+	}
+	hotbarSlot := l.hotbarStart + int16(button)
+
+	if l.isCraftOutput(slot) {
+		c.craftInto(hotbarSlot)
 		return
 	}
 
 	slotItem := c.getWindowSlot(slot)
 	hotbarItem := c.getWindowSlot(hotbarSlot)
+	if c.isFurnaceResult(slot) && !hotbarItem.IsEmpty() {
+		return
+	}
 	c.setWindowSlot(slot, hotbarItem)
 	c.setWindowSlot(hotbarSlot, slotItem)
 
-	if slot >= slotCraftStart && slot <= slotCraftEnd {
+	if l.isCraftSlot(slot) {
 		c.updateCraftingOutput()
 	}
 }
 
-// handleMiddleClick handles mode 3: middle-click in creative mode (clone to cursor).
+// craftInto crafts the current output once into slot, provided the slot is
+// empty or holds a stack the result can merge into.
+func (c *Connection) craftInto(slot int16) {
+	result := c.craftingOutput
+	if result.IsEmpty() {
+		return
+	}
+	existing := c.getWindowSlot(slot)
+	if !existing.IsEmpty() {
+		if !canStack(existing, result) || int(existing.ItemCount)+int(result.ItemCount) > c.maxStackSize(result.BlockID) {
+			return
+		}
+		result.ItemCount += existing.ItemCount
+	}
+	c.setWindowSlot(slot, result)
+	c.consumeCraftingIngredients()
+	c.updateCraftingOutput()
+}
+
+// handleMiddleClick handles mode 3: middle-click in creative mode (clone a
+// full stack to the cursor).
 func (c *Connection) handleMiddleClick(slot int16) {
-	if slot < 0 || slot > slotHotbarEnd {
+	if slot < 0 || slot > c.window.hotbarEnd() {
 		return
 	}
 	item := c.getWindowSlot(slot)
 	if item.IsEmpty() {
 		return
 	}
-	c.cursorSlot = player.Slot{BlockID: item.BlockID, ItemCount: 64, ItemDamage: item.ItemDamage}
+	c.cursorSlot = player.Slot{BlockID: item.BlockID, ItemCount: int8(c.maxStackSize(item.BlockID)), ItemDamage: item.ItemDamage}
 }
 
 // handleDropClick handles mode 4: Q key drop.
@@ -456,7 +543,7 @@ func (c *Connection) handleDropClick(slot int16, button int8) {
 		// In practice this shouldn't happen, but handle gracefully.
 		return
 	}
-	if slot < 0 || slot > slotHotbarEnd {
+	if slot < 0 || slot > c.window.hotbarEnd() {
 		return
 	}
 
@@ -483,7 +570,7 @@ func (c *Connection) handleDropClick(slot int16, button int8) {
 		c.players.SpawnItemEntity(c.self.EntityID, item, pos.X, pos.Y+1.3, pos.Z, pos.Yaw, c.groundAtFunc())
 	}
 
-	if slot >= slotCraftStart && slot <= slotCraftEnd {
+	if c.window.isCraftSlot(slot) {
 		c.updateCraftingOutput()
 	}
 }
@@ -503,7 +590,7 @@ func (c *Connection) handleDragClick(slot int16, button int8) {
 		c.dragMode = 1
 		c.dragSlots = nil
 	case 1, 5: // Add slot
-		if c.dragActive && slot >= 0 && slot <= slotHotbarEnd {
+		if c.dragActive && slot >= 0 && slot <= c.window.hotbarEnd() && !c.isFurnaceResult(slot) {
 			c.dragSlots = append(c.dragSlots, slot)
 		}
 	case 2: // End left drag
@@ -542,7 +629,7 @@ func (c *Connection) finishDrag() {
 			if !existing.IsEmpty() {
 				current = existing.ItemCount
 			}
-			space := 64 - int(current)
+			space := c.maxStackSize(c.cursorSlot.BlockID) - int(current)
 			give := perSlot
 			if give > remaining {
 				give = remaining
@@ -580,7 +667,7 @@ func (c *Connection) finishDrag() {
 			if !existing.IsEmpty() {
 				current = existing.ItemCount
 			}
-			if current >= 64 {
+			if int(current) >= c.maxStackSize(c.cursorSlot.BlockID) {
 				continue
 			}
 			c.setWindowSlot(s, player.Slot{
@@ -604,9 +691,13 @@ func (c *Connection) handleDoubleClick(_ int16) {
 		return
 	}
 
-	needed := 64 - int(c.cursorSlot.ItemCount)
-	// Scan all inventory slots (skip crafting output).
-	for s := int16(slotCraftStart); s <= slotHotbarEnd && needed > 0; s++ {
+	l := c.window
+	needed := c.maxStackSize(c.cursorSlot.BlockID) - int(c.cursorSlot.ItemCount)
+	// Scan all window slots (skip crafting output).
+	for s := int16(0); s <= l.hotbarEnd() && needed > 0; s++ {
+		if l.isCraftOutput(s) {
+			continue
+		}
 		item := c.getWindowSlot(s)
 		if item.IsEmpty() || !canStack(item, c.cursorSlot) {
 			continue
@@ -628,24 +719,25 @@ func (c *Connection) handleDoubleClick(_ int16) {
 
 // handleCreativeSlot processes a SetCreativeSlot (0x10) packet.
 func (c *Connection) handleCreativeSlot(data []byte) error {
-	r := bytes.NewReader(data)
-
-	slotIndex, err := mcnet.ReadI16(r)
-	if err != nil {
-		return fmt.Errorf("read creative slot index: %w", err)
+	// Slot index, item header and at most MaxNBTSize bytes of NBT.
+	if len(data) > 7+item.MaxNBTSize {
+		return fmt.Errorf("%w: creative slot exceeds %d bytes of nbt", ErrProtocolViolation, item.MaxNBTSize)
 	}
 
-	item, err := readSlot(r)
-	if err != nil {
-		return fmt.Errorf("read creative slot item: %w", err)
+	var creative pkt.SetCreativeSlot
+	if err := mcnet.Unmarshal(data, &creative); err != nil {
+		// Creative clients choose the item, so bad slot data is deliberate.
+		return fmt.Errorf("%w: unmarshal creative slot: %w", ErrProtocolViolation, err)
 	}
+	slotIndex, requested := creative.Slot, creative.Item
+
+	pSlot, ok := c.sanitizeCreativeItem(requested)
 
 	// Slot -1: drop item.
 	if slotIndex == -1 {
-		if item.BlockID > 0 {
+		if ok && !pSlot.IsEmpty() {
 			pos := c.self.GetPosition()
-			dropped := player.Slot{BlockID: item.BlockID, ItemCount: item.ItemCount, ItemDamage: item.ItemDamage}
-			c.players.SpawnItemEntity(c.self.EntityID, dropped, pos.X, pos.Y+1.3, pos.Z, pos.Yaw, c.groundAtFunc())
+			c.players.SpawnItemEntity(c.self.EntityID, pSlot, pos.X, pos.Y+1.3, pos.Z, pos.Yaw, c.groundAtFunc())
 		}
 		return nil
 	}
@@ -654,44 +746,86 @@ func (c *Connection) handleCreativeSlot(data []byte) error {
 		return nil
 	}
 
-	// Convert conn.Slot to player.Slot.
-	pSlot := player.EmptySlot
-	if item.BlockID != -1 {
-		pSlot = player.Slot{BlockID: item.BlockID, ItemCount: item.ItemCount, ItemDamage: item.ItemDamage}
+	if !ok {
+		c.log.Debug("rejected creative item", "blockID", requested.BlockID, "count", requested.ItemCount)
+		return c.sendSetSlot(0, slotIndex, c.getWindowSlot(slotIndex))
 	}
 	c.setWindowSlot(slotIndex, pSlot)
+	if pSlot.ItemCount != requested.ItemCount {
+		// The stack was clamped; correct the client's copy.
+		return c.sendSetSlot(0, slotIndex, pSlot)
+	}
 	return nil
 }
 
-// handleCloseWindow processes a CloseWindow (0x0D) packet.
+// sanitizeCreativeItem converts an item sent by a creative client into an
+// inventory slot. Unknown or invalid item IDs and non-positive counts are
+// rejected; counts above the item's stack size are clamped.
+func (c *Connection) sanitizeCreativeItem(item player.Slot) (player.Slot, bool) {
+	if item.BlockID == -1 {
+		return player.EmptySlot, true
+	}
+	if item.BlockID <= 0 || item.ItemCount <= 0 {
+		return player.EmptySlot, false
+	}
+	if c.gameData != nil && c.gameData.Items != nil {
+		if _, ok := c.gameData.Items.ByID(int(item.BlockID)); !ok {
+			return player.EmptySlot, false
+		}
+	}
+	count := min(int(item.ItemCount), c.maxStackSize(item.BlockID))
+	return player.Slot{BlockID: item.BlockID, ItemCount: int8(count), ItemDamage: item.ItemDamage}, true
+}
+
+// handleCloseWindow processes a CloseWindow (0x0D) packet. Crafting grid and
+// cursor items are returned to the inventory, or dropped if it is full, and
+// the connection goes back to the player inventory window.
 func (c *Connection) handleCloseWindow(data []byte) error {
 	r := bytes.NewReader(data)
-	if _, err := mcnet.ReadU8(r); err != nil {
+	windowID, err := mcnet.ReadU8(r)
+	if err != nil {
 		return fmt.Errorf("read close window id: %w", err)
 	}
+	if windowID != c.windowID {
+		c.log.Debug("close for a window that is not open", "windowID", windowID, "open", c.windowID)
+		return nil
+	}
 
-	// Return crafting grid items to inventory or drop them.
-	pos := c.self.GetPosition()
-	groundAt := c.groundAtFunc()
-	for i := 0; i < slotCraftCount; i++ {
-		if c.craftingGrid[i].IsEmpty() {
-			continue
-		}
-		if !c.tryAddToSection(c.craftingGrid[i], slotMainStart, slotHotbarEnd) {
-			// Inventory full, drop the item.
-			c.players.SpawnItemEntity(c.self.EntityID, c.craftingGrid[i], pos.X, pos.Y+1.3, pos.Z, pos.Yaw, groundAt)
-		}
+	// Closing any window returns to the player inventory. Resend it so the
+	// client shows the returned items instead of its own prediction.
+	c.closeWindow()
+	return c.sendWindowItems()
+}
+
+// closeWindow returns crafting grid and cursor items to the inventory, or
+// drops them if it is full, and switches back to the player inventory
+// window.
+func (c *Connection) closeWindow() {
+	// Return crafting grid and cursor items to inventory or drop them.
+	for i := range c.craftingGrid {
+		c.returnToInventory(c.craftingGrid[i])
 		c.craftingGrid[i] = player.EmptySlot
 	}
 	c.craftingOutput = player.EmptySlot
 
-	// Drop cursor item.
-	if !c.cursorSlot.IsEmpty() {
-		c.players.SpawnItemEntity(c.self.EntityID, c.cursorSlot, pos.X, pos.Y+1.3, pos.Z, pos.Yaw, groundAt)
-		c.cursorSlot = player.EmptySlot
-	}
+	c.returnToInventory(c.cursorSlot)
+	c.cursorSlot = player.EmptySlot
 
-	return nil
+	c.closeContainer()
+	c.openWindow(0, windowTypePlayer)
+}
+
+// returnToInventory puts item back into the player's main inventory or
+// hotbar, dropping it at the player's feet if there is no room.
+func (c *Connection) returnToInventory(item player.Slot) {
+	if item.IsEmpty() {
+		return
+	}
+	if c.tryAddToSection(item, c.window.mainStart, c.window.hotbarEnd()) {
+		return
+	}
+	pos := c.self.GetPosition()
+	c.players.SpawnItemEntity(c.self.EntityID, item, pos.X, pos.Y+1.3, pos.Z, pos.Yaw, c.groundAtFunc())
 }
 
 // handleTransaction processes a Transaction (0x0F) packet. No-op for now.
@@ -714,9 +848,26 @@ func (c *Connection) dropItem(item player.Slot, fullStack bool) {
 	}
 }
 
-// canStack returns true if two slots can be merged (same block ID and damage).
+// canStack returns true if two slots can be merged (same block ID, damage
+// and NBT).
 func canStack(a, b player.Slot) bool {
-	return a.BlockID == b.BlockID && a.ItemDamage == b.ItemDamage
+	return a.BlockID == b.BlockID && a.ItemDamage == b.ItemDamage && a.NBT.Equal(b.NBT)
+}
+
+// defaultStackSize is the stack limit used when an item is not in the game data.
+const defaultStackSize = 64
+
+// maxStackSize returns how many of an item fit into one slot: 64 for most
+// blocks, 16 for items such as ender pearls, and 1 for tools and armor.
+func (c *Connection) maxStackSize(blockID int16) int {
+	if c.gameData == nil || c.gameData.Items == nil {
+		return defaultStackSize
+	}
+	item, ok := c.gameData.Items.ByID(int(blockID))
+	if !ok || item.StackSize <= 0 {
+		return defaultStackSize
+	}
+	return item.StackSize
 }
 
 // armorSlotForItem returns the protocol armor slot (5-8) for the given item ID,
@@ -742,7 +893,7 @@ func armorSlotForItem(blockID int16) int16 {
 
 // consumeCraftingIngredients removes one item from each occupied crafting grid slot.
 func (c *Connection) consumeCraftingIngredients() {
-	for i := 0; i < slotCraftCount; i++ {
+	for i := range c.craftingGrid {
 		if c.craftingGrid[i].IsEmpty() {
 			continue
 		}
@@ -757,15 +908,15 @@ func (c *Connection) consumeCraftingIngredients() {
 func (c *Connection) updateCraftingOutput() {
 	result := c.matchCraftingRecipe()
 	c.craftingOutput = result
-	_ = c.sendSetSlot(0, slotCraftOutput, result)
+	_ = c.sendSetSlot(int8(c.windowID), c.window.craftOutput, result)
 }
 
-// matchCraftingRecipe tries to match the 2x2 crafting grid against known recipes.
-// This is a placeholder that will be replaced by the full crafting system.
+// matchCraftingRecipe matches the open window's crafting grid against known
+// recipes.
 func (c *Connection) matchCraftingRecipe() player.Slot {
 	// Check if crafting grid is empty.
 	allEmpty := true
-	for i := 0; i < slotCraftCount; i++ {
+	for i := range c.craftingGrid {
 		if !c.craftingGrid[i].IsEmpty() {
 			allEmpty = false
 			break
@@ -779,5 +930,5 @@ func (c *Connection) matchCraftingRecipe() player.Slot {
 		return player.EmptySlot
 	}
 
-	return matchRecipe2x2(c.craftingGrid, c.gameData.Recipes)
+	return matchRecipe(c.craftingGrid, c.window.gridWidth(), c.gameData.Recipes)
 }
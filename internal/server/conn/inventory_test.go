@@ -1,9 +1,14 @@
 package conn
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
 	"testing"
 
 	"github.com/go-theft-craft/server/internal/server/player"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	"github.com/go-theft-craft/server/pkg/item"
 )
 
 func stone(count int8) player.Slot {
@@ -18,6 +23,10 @@ func sword() player.Slot {
 	return player.Slot{BlockID: 276, ItemCount: 1, ItemDamage: 0}
 }
 
+func enderPearl(count int8) player.Slot {
+	return player.Slot{BlockID: 368, ItemCount: count, ItemDamage: 0}
+}
+
 func ironHelmet() player.Slot {
 	return player.Slot{BlockID: 306, ItemCount: 1, ItemDamage: 0}
 }
@@ -350,3 +359,316 @@ func TestArmorSlotForItem(t *testing.T) {
 		}
 	}
 }
+
+func oakLog(count int8) player.Slot {
+	return player.Slot{BlockID: 17, ItemCount: count, ItemDamage: 0}
+}
+
+// countItems sums the count of blockID across the main inventory and hotbar.
+func countItems(c *Connection, blockID int16) int {
+	total := 0
+	for s := int16(slotMainStart); s <= slotHotbarEnd; s++ {
+		if item := c.getWindowSlot(s); item.BlockID == blockID {
+			total += int(item.ItemCount)
+		}
+	}
+	return total
+}
+
+func TestShiftClick_CraftOutputRepeats(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	c.craftingGrid[0] = oakLog(64)
+	c.updateCraftingOutput()
+	if c.craftingOutput.IsEmpty() {
+		t.Fatal("expected planks recipe to match a single log")
+	}
+	perCraft := int(c.craftingOutput.ItemCount)
+
+	c.handleShiftClick(slotCraftOutput, 0)
+
+	if !c.craftingOutput.IsEmpty() {
+		t.Errorf("crafting output should be empty once logs run out, got %+v", c.craftingOutput)
+	}
+	if !c.craftingGrid[0].IsEmpty() {
+		t.Errorf("crafting grid should be depleted, got %+v", c.craftingGrid[0])
+	}
+	if got, want := countItems(c, 5), 64*perCraft; got != want {
+		t.Errorf("planks in inventory = %d, want %d", got, want)
+	}
+}
+
+func TestShiftClick_CraftOutputStopsWhenFull(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	for s := int16(slotMainStart); s <= slotHotbarEnd; s++ {
+		c.setWindowSlot(s, stone(64))
+	}
+	c.craftingGrid[0] = oakLog(10)
+	c.updateCraftingOutput()
+
+	c.handleShiftClick(slotCraftOutput, 0)
+
+	if c.craftingGrid[0] != oakLog(10) {
+		t.Errorf("no logs should be consumed when the inventory is full, got %+v", c.craftingGrid[0])
+	}
+	if c.craftingOutput.IsEmpty() {
+		t.Error("crafting output should still be available")
+	}
+}
+
+func TestNumberKey_CraftOutputIntoHotbar(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	c.craftingGrid[0] = oakLog(3)
+	c.updateCraftingOutput()
+	planks := c.craftingOutput
+	if planks.IsEmpty() {
+		t.Fatal("expected planks recipe to match a single log")
+	}
+
+	c.handleNumberKey(slotCraftOutput, 4)
+
+	if got := c.getWindowSlot(slotHotbarStart + 4); got != planks {
+		t.Errorf("hotbar slot 5 = %+v, want %+v", got, planks)
+	}
+	if c.craftingGrid[0] != oakLog(2) {
+		t.Errorf("crafting grid = %+v, want one log consumed", c.craftingGrid[0])
+	}
+	if c.craftingOutput != planks {
+		t.Errorf("crafting output = %+v, want the recipe to still match", c.craftingOutput)
+	}
+}
+
+func TestNumberKey_CraftOutputBlockedByOtherItem(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	c.setWindowSlot(slotHotbarStart, dirt(5))
+	c.craftingGrid[0] = oakLog(3)
+	c.updateCraftingOutput()
+
+	c.handleNumberKey(slotCraftOutput, 0)
+
+	if got := c.getWindowSlot(slotHotbarStart); got != dirt(5) {
+		t.Errorf("occupied hotbar slot changed to %+v", got)
+	}
+	if c.craftingGrid[0] != oakLog(3) {
+		t.Errorf("ingredients consumed without crafting: %+v", c.craftingGrid[0])
+	}
+}
+
+func TestNumberKey_InvalidButton(t *testing.T) {
+	c := newInventoryTestConn()
+	c.setWindowSlot(9, stone(10))
+
+	c.handleNumberKey(9, 9)
+	c.handleNumberKey(9, -1)
+
+	if c.getWindowSlot(9) != stone(10) {
+		t.Errorf("slot 9 changed to %+v for an out-of-range button", c.getWindowSlot(9))
+	}
+}
+
+// --- Stack Size Tests ---
+
+func TestMaxStackSize(t *testing.T) {
+	c := newInventoryTestConn()
+	if got := c.maxStackSize(368); got != 64 {
+		t.Errorf("without game data maxStackSize = %d, want 64", got)
+	}
+
+	c.gameData = pkt.New()
+	tests := []struct {
+		name string
+		id   int16
+		want int
+	}{
+		{"stone", 1, 64},
+		{"ender pearl", 368, 16},
+		{"diamond sword", 276, 1},
+		{"iron helmet", 306, 1},
+		{"unknown", 32000, 64},
+	}
+	for _, tt := range tests {
+		if got := c.maxStackSize(tt.id); got != tt.want {
+			t.Errorf("maxStackSize(%s) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNormalClick_MergeStopsAtStackSize(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	c.setWindowSlot(36, enderPearl(10))
+	c.cursorSlot = enderPearl(10)
+
+	c.handleNormalClick(36, 0)
+	if c.getWindowSlot(36) != enderPearl(16) {
+		t.Errorf("slot 36 = %+v, want enderPearl(16)", c.getWindowSlot(36))
+	}
+	if c.cursorSlot != enderPearl(4) {
+		t.Errorf("cursor = %+v, want enderPearl(4)", c.cursorSlot)
+	}
+
+	// Right-click cannot add to a full stack either; it swaps instead.
+	c.handleNormalClick(36, 1)
+	if c.getWindowSlot(36) != enderPearl(4) || c.cursorSlot != enderPearl(16) {
+		t.Errorf("right-click on full stack: slot=%+v cursor=%+v", c.getWindowSlot(36), c.cursorSlot)
+	}
+}
+
+func TestTryAddToSection_SplitsAtStackSize(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	c.setWindowSlot(36, enderPearl(12))
+
+	if !c.tryAddToSection(enderPearl(24), 36, 44) {
+		t.Fatal("expected all pearls to fit in the hotbar")
+	}
+	want := []player.Slot{enderPearl(16), enderPearl(16), enderPearl(4)}
+	for i, w := range want {
+		if got := c.getWindowSlot(36 + int16(i)); got != w {
+			t.Errorf("slot %d = %+v, want %+v", 36+i, got, w)
+		}
+	}
+	if got := c.sectionSpace(enderPearl(1), 36, 38); got != 12 {
+		t.Errorf("sectionSpace = %d, want 12", got)
+	}
+}
+
+func TestDoubleClick_CollectStopsAtStackSize(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	c.setWindowSlot(36, enderPearl(10))
+	c.setWindowSlot(37, enderPearl(10))
+	c.cursorSlot = enderPearl(1)
+
+	c.handleDoubleClick(36)
+	if c.cursorSlot.ItemCount != 16 {
+		t.Errorf("cursor count = %d, want 16", c.cursorSlot.ItemCount)
+	}
+	if got := c.getWindowSlot(37).ItemCount + c.getWindowSlot(36).ItemCount; got != 5 {
+		t.Errorf("left %d pearls in slots, want 5", got)
+	}
+}
+
+func TestToolsDoNotStack(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	c.setWindowSlot(36, sword())
+	c.cursorSlot = sword()
+
+	// Double-click leaves the other sword in place.
+	c.handleDoubleClick(36)
+	if c.cursorSlot != sword() || c.getWindowSlot(36) != sword() {
+		t.Errorf("double-click stacked swords: cursor=%+v slot=%+v", c.cursorSlot, c.getWindowSlot(36))
+	}
+
+	// Right-drag over an existing sword places nothing.
+	c.handleDragClick(slotOutside, 4)
+	c.handleDragClick(36, 5)
+	c.handleDragClick(slotOutside, 6)
+	if c.getWindowSlot(36) != sword() || c.cursorSlot != sword() {
+		t.Errorf("right-drag stacked swords: cursor=%+v slot=%+v", c.cursorSlot, c.getWindowSlot(36))
+	}
+}
+
+func TestMiddleClick_CloneUsesStackSize(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	c.setWindowSlot(36, enderPearl(1))
+
+	c.handleMiddleClick(36)
+	if c.cursorSlot != enderPearl(16) {
+		t.Errorf("cursor = %+v, want enderPearl(16)", c.cursorSlot)
+	}
+}
+
+// --- Creative Slot Tests ---
+
+func creativeSlotData(slot, blockID int16, count int8, nbtData ...byte) []byte {
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.BigEndian, slot)
+	_ = binary.Write(&buf, binary.BigEndian, blockID)
+	buf.WriteByte(byte(count))
+	_ = binary.Write(&buf, binary.BigEndian, int16(0)) // damage
+	if len(nbtData) == 0 {
+		nbtData = []byte{0x00} // no NBT
+	}
+	buf.Write(nbtData)
+	return buf.Bytes()
+}
+
+func TestCreativeSlot_ClampsStackSize(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+
+	if err := c.handleCreativeSlot(creativeSlotData(36, 368, 64)); err != nil {
+		t.Fatalf("handleCreativeSlot: %v", err)
+	}
+	if got := c.getWindowSlot(36); got != enderPearl(16) {
+		t.Errorf("slot 36 = %+v, want enderPearl(16)", got)
+	}
+	if n := countPackets(t, c, pkt.SetSlot{}.PacketID()); n != 1 {
+		t.Errorf("sent %d SetSlot packets, want 1 correcting the count", n)
+	}
+}
+
+func TestCreativeSlot_RejectsInvalidItem(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	c.setWindowSlot(36, stone(5))
+
+	for _, tt := range []struct {
+		name    string
+		blockID int16
+		count   int8
+	}{
+		{"unknown id", 32000, 1},
+		{"negative id", -5, 1},
+		{"air", 0, 1},
+		{"negative count", 1, -3},
+	} {
+		if err := c.handleCreativeSlot(creativeSlotData(36, tt.blockID, tt.count)); err != nil {
+			t.Fatalf("%s: handleCreativeSlot: %v", tt.name, err)
+		}
+		if got := c.getWindowSlot(36); got != stone(5) {
+			t.Errorf("%s: slot 36 = %+v, want it unchanged", tt.name, got)
+		}
+	}
+}
+
+func TestCreativeSlot_RejectsIllegalNBT(t *testing.T) {
+	c := newInventoryTestConn()
+
+	// A root tag other than a compound.
+	err := c.handleCreativeSlot(creativeSlotData(36, 1, 1, 0x08, 0x00, 0x00))
+	if !errors.Is(err, ErrProtocolViolation) {
+		t.Errorf("non-compound NBT: err = %v, want protocol violation", err)
+	}
+
+	// An oversized compound.
+	big := append([]byte{0x0A}, make([]byte, item.MaxNBTSize+1)...)
+	err = c.handleCreativeSlot(creativeSlotData(36, 1, 1, big...))
+	if !errors.Is(err, ErrProtocolViolation) {
+		t.Errorf("oversized NBT: err = %v, want protocol violation", err)
+	}
+	if !c.getWindowSlot(36).IsEmpty() {
+		t.Errorf("slot 36 = %+v, want it unchanged", c.getWindowSlot(36))
+	}
+}
+
+func TestCanStack_ComparesNBT(t *testing.T) {
+	plain := player.Slot{BlockID: 1, ItemCount: 1}
+	named := player.Slot{BlockID: 1, ItemCount: 1, NBT: &player.ItemNBT{DisplayName: "Lucky"}}
+
+	if !canStack(plain, player.Slot{BlockID: 1, ItemCount: 5}) {
+		t.Error("plain stone should stack with plain stone")
+	}
+	if canStack(plain, named) {
+		t.Error("named stone should not stack with plain stone")
+	}
+	if !canStack(named, player.Slot{BlockID: 1, NBT: &player.ItemNBT{DisplayName: "Lucky"}}) {
+		t.Error("stones with the same name should stack")
+	}
+}
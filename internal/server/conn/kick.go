@@ -0,0 +1,96 @@
+package conn
+
+import (
+	"fmt"
+
+	"github.com/go-theft-craft/server/internal/server/chat"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+)
+
+// kickReason identifies a disconnect message. The string value is the key
+// used for overrides in cfg.KickMessages.
+type kickReason string
+
+const (
+	kickTimeout     kickReason = "timeout"
+	kickAuthFailed  kickReason = "auth_failed"
+	kickFlying      kickReason = "flying"
+	kickCommand     kickReason = "kicked"
+	kickWhitelist   kickReason = "not_whitelisted"
+	kickBanned      kickReason = "banned"
+	kickUnavailable kickReason = "unavailable"
+)
+
+// kickMessage is the text and chat color shown to a disconnected player.
+type kickMessage struct {
+	text  string
+	color string // empty = client default
+}
+
+// kickMessages holds the default message for every kick reason.
+var kickMessages = map[kickReason]kickMessage{
+	kickTimeout:     {text: "Timed out"},
+	kickAuthFailed:  {text: "Failed to verify with Mojang.", color: "red"},
+	kickFlying:      {text: "Flying is not enabled on this server."},
+	kickCommand:     {text: "Kicked by an operator."},
+	kickWhitelist:   {text: "You are not whitelisted on this server."},
+	kickBanned:      {text: "You are banned from this server."},
+	kickUnavailable: {text: "The server cannot accept logins right now. Try again later."},
+}
+
+// kickMessageJSON returns the chat component for reason, applying any
+// override from the config.
+func (c *Connection) kickMessageJSON(reason kickReason) string {
+	return c.kickMessageFor(reason).json()
+}
+
+// kickMessageFor returns the message for reason with any config override applied.
+func (c *Connection) kickMessageFor(reason kickReason) kickMessage {
+	msg, ok := kickMessages[reason]
+	if !ok {
+		msg = kickMessage{text: string(reason)}
+	}
+	if o, ok := c.cfg.KickMessages[string(reason)]; ok {
+		if o.Text != "" {
+			msg.text = o.Text
+		}
+		if o.Color != "" {
+			msg.color = o.Color
+		}
+	}
+	return msg
+}
+
+// json encodes the message as a chat component.
+func (m kickMessage) json() string {
+	return chat.Colored(m.text, m.color).String()
+}
+
+// kick sends a KickDisconnect with the message for reason and closes the connection.
+func (c *Connection) kick(reason kickReason) {
+	_ = c.writePacket(&pkt.KickDisconnect{Reason: c.kickMessageJSON(reason)})
+	c.disconnect(string(reason))
+}
+
+// rejectLogin sends a login-state Disconnect for reason and closes the
+// connection. A non-empty text replaces the reason's message text.
+func (c *Connection) rejectLogin(reason kickReason, text string) {
+	msg := c.kickMessageFor(reason)
+	if text != "" {
+		msg.text = text
+	}
+	_ = c.writePacket(&pkt.Disconnect{Reason: msg.json()})
+	c.disconnect(string(reason))
+}
+
+// kickWithText is like kick but replaces the message text with text when it
+// is non-empty. It is used as the player's Disconnect callback, so it may be
+// called from another connection's goroutine.
+func (c *Connection) kickWithText(reason kickReason, text string) {
+	msg := c.kickMessageFor(reason)
+	if text != "" {
+		msg.text = text
+	}
+	_ = c.writePacket(&pkt.KickDisconnect{Reason: msg.json()})
+	c.disconnect(fmt.Sprintf("%s: %s", reason, msg.text))
+}
@@ -0,0 +1,88 @@
+package conn
+
+import (
+	"testing"
+
+	"github.com/go-theft-craft/server/internal/server/config"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+)
+
+// kickReasonSent returns the reason of the last KickDisconnect written to c.
+func kickReasonSent(t *testing.T, c *Connection) string {
+	t.Helper()
+	reason := ""
+	for _, p := range recordedPackets(t, c) {
+		if p.id != 0x40 {
+			continue
+		}
+		var kick pkt.KickDisconnect
+		if err := mcnet.Unmarshal(p.data, &kick); err != nil {
+			t.Fatalf("unmarshal kick: %v", err)
+		}
+		reason = kick.Reason
+	}
+	return reason
+}
+
+func TestKick_TimeoutUsesCentralMessage(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+
+	c.kick(kickTimeout)
+
+	if got, want := kickReasonSent(t, c), `{"text":"Timed out"}`; got != want {
+		t.Errorf("kick reason = %s, want %s", got, want)
+	}
+	if c.ctx.Err() == nil {
+		t.Error("expected the connection to be closed")
+	}
+}
+
+func TestKick_ConfigOverride(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.cfg.KickMessages = map[string]config.KickMessage{
+		string(kickTimeout): {Text: "Lost connection, see you soon!", Color: "yellow"},
+	}
+
+	c.kick(kickTimeout)
+
+	want := `{"text":"Lost connection, see you soon!","color":"yellow"}`
+	if got := kickReasonSent(t, c); got != want {
+		t.Errorf("kick reason = %s, want %s", got, want)
+	}
+}
+
+func TestKickMessageJSON_ColorOnlyOverride(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.cfg.KickMessages = map[string]config.KickMessage{
+		string(kickFlying): {Color: "gold"},
+	}
+
+	want := `{"text":"Flying is not enabled on this server.","color":"gold"}`
+	if got := c.kickMessageJSON(kickFlying); got != want {
+		t.Errorf("kickMessageJSON = %s, want %s", got, want)
+	}
+}
+
+func TestKickWithText_ReplacesMessage(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+
+	c.kickWithText(kickCommand, "Go to bed")
+
+	if got, want := kickReasonSent(t, c), `{"text":"Go to bed"}`; got != want {
+		t.Errorf("kick reason = %s, want %s", got, want)
+	}
+	if c.ctx.Err() == nil {
+		t.Error("expected the connection to be closed")
+	}
+}
+
+func TestKickWithText_EmptyUsesDefault(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+
+	c.kickWithText(kickCommand, "")
+
+	if got, want := kickReasonSent(t, c), `{"text":"Kicked by an operator."}`; got != want {
+		t.Errorf("kick reason = %s, want %s", got, want)
+	}
+}
@@ -1,7 +1,9 @@
 package conn
 
 import (
+	"math"
 	"math/rand"
+	"time"
 
 	"github.com/go-theft-craft/server/internal/server/player"
 	"github.com/go-theft-craft/server/pkg/gamedata"
@@ -16,10 +18,18 @@ func canHarvest(block gamedata.Block, heldItemID int16) bool {
 	return block.HarvestTools[int(heldItemID)]
 }
 
+// enchantEfficiency is the enchantment ID of Efficiency.
+const enchantEfficiency = 32
+
+// digTolerance is the fraction of the computed break time a "finished
+// digging" packet must wait for, leaving room for latency. Vanilla 1.8
+// accepts a finish once the block is at least 70% broken.
+const digTolerance = 0.7
+
 // calcBreakTime returns the expected break time in ticks for a block given the
-// player's held item and game mode. Returns -1 for unbreakable blocks.
+// player's held item. Returns -1 for unbreakable blocks and 0 for instant breaks.
 // Based on https://minecraft.wiki/w/Breaking#Speed
-func calcBreakTime(block gamedata.Block, heldItemID int16, materials gamedata.MaterialRegistry) int {
+func calcBreakTime(block gamedata.Block, held player.Slot, materials gamedata.MaterialRegistry) int {
 	if block.Hardness == nil {
 		return -1 // unbreakable (e.g. bedrock)
 	}
@@ -37,21 +47,25 @@ func calcBreakTime(block gamedata.Block, heldItemID int16, materials gamedata.Ma
 	if materials != nil && block.Material != "" {
 		mat, ok := materials.ByName(block.Material)
 		if ok {
-			if speed, hasSpeed := mat.ToolSpeeds[int(heldItemID)]; hasSpeed {
+			if speed, hasSpeed := mat.ToolSpeeds[int(held.BlockID)]; hasSpeed {
 				speedMultiplier = speed
 			}
 		}
 	}
 
-	// Determine if this is the "best" tool (can harvest = gets drops).
-	canHarv := canHarvest(block, heldItemID)
+	// Efficiency only helps a tool that is already faster than a hand.
+	if speedMultiplier > 1 {
+		if lvl := enchantmentLevel(held, enchantEfficiency); lvl > 0 {
+			speedMultiplier += float64(lvl*lvl + 1)
+		}
+	}
 
-	// Base damage per tick.
+	// Base damage per tick: hardness x1.5 seconds with a tool that can
+	// harvest the block, x5 seconds otherwise (no drops either).
 	var damage float64
-	if canHarv {
+	if canHarvest(block, held.BlockID) {
 		damage = speedMultiplier / hardness / 30.0
 	} else {
-		// Wrong tool: 5x slower, no drops.
 		damage = speedMultiplier / hardness / 100.0
 	}
 
@@ -59,16 +73,39 @@ func calcBreakTime(block gamedata.Block, heldItemID int16, materials gamedata.Ma
 		return 0 // instant break
 	}
 
-	ticks := int(1.0 / damage)
-	return ticks
+	// The block breaks on the first tick its accumulated damage reaches 1.
+	return int(math.Ceil(1.0/damage - 1e-9))
+}
+
+// enchantmentLevel returns the level of the given enchantment on an item, or 0.
+func enchantmentLevel(s player.Slot, id int16) int {
+	if s.NBT == nil {
+		return 0
+	}
+	for _, e := range s.NBT.Enchantments {
+		if e.ID == id {
+			return int(e.Level)
+		}
+	}
+	return 0
+}
+
+// minDigDuration returns the shortest time a player may take to break a
+// block that needs the given number of ticks.
+func minDigDuration(ticks int) time.Duration {
+	return time.Duration(float64(ticks) * digTolerance * float64(50*time.Millisecond))
 }
 
-// blockDrops returns the item slots that should be dropped when a block is broken.
-// Returns nil if the tool can't harvest this block.
-func blockDrops(block gamedata.Block, heldItemID int16) []player.Slot {
+// blockDrops returns the item slots that should be dropped when the block
+// state is broken, with random counts drawn from rng. Returns nil if the tool
+// can't harvest this block.
+func blockDrops(block gamedata.Block, state int32, heldItemID int16, rng *rand.Rand) []player.Slot {
 	if !canHarvest(block, heldItemID) {
 		return nil
 	}
+	if player.IsLeaves(state) {
+		return player.LeafDrops(state, rng)
+	}
 
 	if len(block.Drops) == 0 {
 		return nil
@@ -87,7 +124,7 @@ func blockDrops(block gamedata.Block, heldItemID int16) []player.Slot {
 		}
 		count := minC
 		if maxC > minC {
-			count = minC + rand.Intn(maxC-minC+1)
+			count = minC + rng.Intn(maxC-minC+1)
 		}
 		if count <= 0 {
 			continue
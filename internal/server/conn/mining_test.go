@@ -0,0 +1,165 @@
+package conn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/go-theft-craft/server/internal/server/packet"
+	"github.com/go-theft-craft/server/internal/server/player"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+)
+
+const itemDiamondPickaxe = 278
+
+func blockDigData(status int32, x, y, z int) []byte {
+	var buf bytes.Buffer
+	_, _ = mcnet.WriteVarInt(&buf, status)
+	_ = binary.Write(&buf, binary.BigEndian, mcnet.EncodePosition(x, y, z))
+	buf.WriteByte(1) // face
+	return buf.Bytes()
+}
+
+func TestCalcBreakTime_StoneHandVsDiamondPickaxe(t *testing.T) {
+	gd := pkt.New()
+	stone, ok := gd.Blocks.ByName("stone")
+	if !ok {
+		t.Fatal("stone not in block registry")
+	}
+
+	hand := calcBreakTime(stone, player.EmptySlot, gd.Materials)
+	pick := calcBreakTime(stone, player.Slot{BlockID: itemDiamondPickaxe, ItemCount: 1}, gd.Materials)
+
+	// Stone has hardness 1.5: 7.5s by hand, 0.3s with a diamond pickaxe.
+	if hand != 150 {
+		t.Errorf("hand break time = %d ticks, want 150", hand)
+	}
+	if pick != 6 {
+		t.Errorf("diamond pickaxe break time = %d ticks, want 6", pick)
+	}
+}
+
+func TestCalcBreakTime_Efficiency(t *testing.T) {
+	gd := pkt.New()
+	stone, _ := gd.Blocks.ByName("stone")
+
+	pick := player.Slot{BlockID: itemDiamondPickaxe, ItemCount: 1}
+	pick.NBT = &player.ItemNBT{Enchantments: []player.Enchantment{{ID: enchantEfficiency, Level: 1}}}
+	if got := calcBreakTime(stone, pick, gd.Materials); got != 5 {
+		t.Errorf("efficiency I break time = %d ticks, want 5", got)
+	}
+
+	pick.NBT = &player.ItemNBT{Enchantments: []player.Enchantment{{ID: enchantEfficiency, Level: 5}}}
+	if got := calcBreakTime(stone, pick, gd.Materials); got != 2 {
+		t.Errorf("efficiency V break time = %d ticks, want 2", got)
+	}
+
+	// An enchanted hand item gets no bonus.
+	stick := player.Slot{BlockID: 280, ItemCount: 1, NBT: pick.NBT}
+	if got := calcBreakTime(stone, stick, gd.Materials); got != 150 {
+		t.Errorf("enchanted stick break time = %d ticks, want 150", got)
+	}
+}
+
+func TestBlockDig_RejectsEarlyFinish(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	c.self.SetGameMode(packet.GameModeSurvival)
+	c.world.SetBlock(2, 4, 2, 1<<4)
+
+	if err := c.handleBlockDig(blockDigData(0, 2, 4, 2)); err != nil {
+		t.Fatalf("start dig: %v", err)
+	}
+	if err := c.handleBlockDig(blockDigData(2, 2, 4, 2)); err != nil {
+		t.Fatalf("finish dig: %v", err)
+	}
+
+	if got := c.world.GetBlock(2, 4, 2); got != 1<<4 {
+		t.Errorf("block = %d, want stone to survive an instant finish", got)
+	}
+	if n := countPackets(t, c, 0x23); n != 1 {
+		t.Errorf("block change packets = %d, want 1 resend", n)
+	}
+}
+
+func TestBlockDig_RejectsFinishWithoutStart(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	c.self.SetGameMode(packet.GameModeSurvival)
+	c.world.SetBlock(2, 4, 2, 1<<4)
+
+	if err := c.handleBlockDig(blockDigData(2, 2, 4, 2)); err != nil {
+		t.Fatalf("finish dig: %v", err)
+	}
+	if got := c.world.GetBlock(2, 4, 2); got != 1<<4 {
+		t.Errorf("block = %d, want stone to survive", got)
+	}
+}
+
+func TestBlockDig_AcceptsFinishAfterBreakTime(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	c.self.SetGameMode(packet.GameModeSurvival)
+	c.self.Inventory.SetSlot(0, player.Slot{BlockID: itemDiamondPickaxe, ItemCount: 1})
+	c.self.Inventory.SetHeldSlot(0)
+	c.world.SetBlock(2, 4, 2, 1<<4)
+
+	if err := c.handleBlockDig(blockDigData(0, 2, 4, 2)); err != nil {
+		t.Fatalf("start dig: %v", err)
+	}
+	if c.digTicks != 6 {
+		t.Fatalf("digTicks = %d, want 6", c.digTicks)
+	}
+	c.digStart = time.Now().Add(-300 * time.Millisecond)
+
+	if err := c.handleBlockDig(blockDigData(2, 2, 4, 2)); err != nil {
+		t.Fatalf("finish dig: %v", err)
+	}
+	if got := c.world.GetBlock(2, 4, 2); got != 0 {
+		t.Errorf("block = %d, want air after a full-length dig", got)
+	}
+}
+
+func TestBlockDrops_StoneNeedsPickaxe(t *testing.T) {
+	gd := pkt.New()
+	stone, _ := gd.Blocks.ByName("stone")
+
+	if drops := blockDrops(stone, 1<<4, player.EmptySlot.BlockID, rand.New(rand.NewSource(1))); drops != nil {
+		t.Errorf("hand drops = %+v, want none", drops)
+	}
+	drops := blockDrops(stone, 1<<4, itemDiamondPickaxe, rand.New(rand.NewSource(1)))
+	if len(drops) != 1 || drops[0].BlockID != 4 || drops[0].ItemCount != 1 {
+		t.Errorf("pickaxe drops = %+v, want one cobblestone", drops)
+	}
+}
+
+func TestBreakBlock_DropsOnlyWithHarvestTool(t *testing.T) {
+	spawned := func(held player.Slot) bool {
+		c, _, m := newTestConn("Alice")
+		c.gameData = pkt.New()
+		c.self.SetGameMode(packet.GameModeSurvival)
+		c.self.Inventory.SetSlot(0, held)
+		c.self.Inventory.SetHeldSlot(0)
+		_, bob := addTestPlayer(m, "Bob")
+		c.world.SetBlock(2, 4, 2, 1<<4)
+		bob.reset()
+
+		c.breakBlock(2, 4, 2, mcnet.EncodePosition(2, 4, 2))
+		for _, p := range bob.get() {
+			if s, ok := p.(*pkt.SpawnEntity); ok && s.Data[1] == 2 {
+				return true
+			}
+		}
+		return false
+	}
+
+	if spawned(player.EmptySlot) {
+		t.Error("stone broken by hand should not drop an item")
+	}
+	if !spawned(player.Slot{BlockID: itemDiamondPickaxe, ItemCount: 1}) {
+		t.Error("stone broken with a pickaxe should drop cobblestone")
+	}
+}
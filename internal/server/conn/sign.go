@@ -0,0 +1,86 @@
+package conn
+
+import (
+	"math"
+
+	"github.com/go-theft-craft/server/internal/server/chat"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+	"github.com/go-theft-craft/server/pkg/world"
+)
+
+// itemSign is the item ID of a sign, placed as a standing or wall sign.
+const itemSign = 323
+
+// maxSignLineLength caps each line of sign text, well above what the
+// client's sign editor allows.
+const maxSignLineLength = 64
+
+// signState returns the block state of a sign placed against face by a
+// player looking along yaw: a standing sign rotated towards the player on
+// top of a block, or a wall sign facing away from the clicked side. Signs
+// cannot hang from the bottom of a block.
+func signState(face int8, yaw float32) (int32, bool) {
+	switch face {
+	case 1: // +Y
+		rotation := int32(math.Floor(float64(yaw+180)*16/360+0.5)) & 0xF
+		return world.BlockStandingSign<<4 | rotation, true
+	case 2, 3, 4, 5:
+		return world.BlockWallSign<<4 | int32(face), true
+	default:
+		return 0, false
+	}
+}
+
+// openSignEditor opens the sign editor for a freshly placed sign. Only that
+// sign accepts the player's next UpdateSign.
+func (c *Connection) openSignEditor(pos world.BlockPos) error {
+	c.signEditing = true
+	c.signEditPos = pos
+	return c.writePacket(&pkt.OpenSignEntity{Location: mcnet.EncodePosition(pos.X, pos.Y, pos.Z)})
+}
+
+// handleUpdateSign stores the text the player wrote on the sign they just
+// placed and shows it to everyone. Formatting is stripped, as in vanilla.
+func (c *Connection) handleUpdateSign(p *pkt.UpdateSignSB) {
+	x, y, z := mcnet.DecodePosition(p.Location)
+	pos := world.BlockPos{X: x, Y: y, Z: z}
+	if !c.signEditing || c.signEditPos != pos || !world.IsSign(c.world.GetBlock(x, y, z)) {
+		c.log.Debug("ignored sign update", "x", x, "y", y, "z", z)
+		return
+	}
+	c.signEditing = false
+
+	var lines [4]string
+	for i, raw := range [4]string{p.Text1, p.Text2, p.Text3, p.Text4} {
+		line := []rune(chat.PlainText(raw))
+		if len(line) > maxSignLineLength {
+			line = line[:maxSignLineLength]
+		}
+		lines[i] = string(line)
+	}
+	c.world.SetSign(pos, lines)
+	c.players.Broadcast(signPacket(pos, lines))
+}
+
+// signPacket returns the UpdateSign packet that shows lines on the sign at pos.
+func signPacket(pos world.BlockPos, lines [4]string) *pkt.UpdateSignCB {
+	return &pkt.UpdateSignCB{
+		Location: mcnet.EncodePosition(pos.X, pos.Y, pos.Z),
+		Text1:    chat.Text(lines[0]).String(),
+		Text2:    chat.Text(lines[1]).String(),
+		Text3:    chat.Text(lines[2]).String(),
+		Text4:    chat.Text(lines[3]).String(),
+	}
+}
+
+// sendChunkSigns sends the text of every sign in chunk (cx, cz), which the
+// chunk data itself does not carry.
+func (c *Connection) sendChunkSigns(cx, cz int) error {
+	for pos, lines := range c.world.SignsInChunk(cx, cz) {
+		if err := c.writePacket(signPacket(pos, lines)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
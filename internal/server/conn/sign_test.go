@@ -0,0 +1,117 @@
+package conn
+
+import (
+	"testing"
+
+	"github.com/go-theft-craft/server/internal/server/packet"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+	"github.com/go-theft-craft/server/pkg/world"
+)
+
+func TestBlockPlace_SignOpensEditor(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.self.SetPosition(0.5, 5, 0.5, 0, 0, true)
+
+	// Click the top of the grass at (3, 4, 3).
+	if err := c.handleBlockPlace(blockPlaceData(3, 4, 3, 1, itemSign)); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+
+	if got := c.world.GetBlock(3, 5, 3); got>>4 != world.BlockStandingSign {
+		t.Fatalf("block = %d, want a standing sign", got)
+	}
+	if n := countPackets(t, c, pkt.OpenSignEntity{}.PacketID()); n != 1 {
+		t.Errorf("sent %d sign editor packets, want 1", n)
+	}
+}
+
+func TestSignState(t *testing.T) {
+	if state, ok := signState(3, 0); !ok || state != world.BlockWallSign<<4|3 {
+		t.Errorf("signState(south face) = %d (%v), want a wall sign facing south", state, ok)
+	}
+	// A player facing south (yaw 0) sees the sign face north towards them.
+	if state, ok := signState(1, 0); !ok || state != world.BlockStandingSign<<4|8 {
+		t.Errorf("signState(top, yaw 0) = %d (%v), want rotation 8", state, ok)
+	}
+	if _, ok := signState(0, 0); ok {
+		t.Error("signs cannot be placed under a block")
+	}
+}
+
+func TestUpdateSign_StoresAndBroadcastsText(t *testing.T) {
+	c, sp, m := newTestConn("Alice")
+	c.self.SetGameMode(packet.GameModeCreative)
+	_, bob := addTestPlayer(m, "Bob")
+	c.self.SetPosition(0.5, 5, 0.5, 0, 0, true)
+	if err := c.handleBlockPlace(blockPlaceData(3, 4, 3, 1, itemSign)); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+	sp.reset()
+	bob.reset()
+
+	c.handleUpdateSign(&pkt.UpdateSignSB{
+		Location: mcnet.EncodePosition(3, 5, 3),
+		Text1:    `{"text":"§cHello"}`,
+		Text2:    `""`,
+		Text3:    `{"text":"world"}`,
+		Text4:    `""`,
+	})
+
+	want := [4]string{"Hello", "", "world", ""}
+	if got, ok := c.world.Sign(world.BlockPos{X: 3, Y: 5, Z: 3}); !ok || got != want {
+		t.Errorf("sign = %q (%v), want %q", got, ok, want)
+	}
+	var update *pkt.UpdateSignCB
+	for _, p := range bob.get() {
+		if u, ok := p.(*pkt.UpdateSignCB); ok {
+			update = u
+		}
+	}
+	if update == nil || update.Text1 != `{"text":"Hello"}` {
+		t.Errorf("Bob got sign update %+v, want the plain text", update)
+	}
+
+	// The sign cannot be edited a second time.
+	c.handleUpdateSign(&pkt.UpdateSignSB{Location: mcnet.EncodePosition(3, 5, 3), Text1: `{"text":"changed"}`})
+	if got, _ := c.world.Sign(world.BlockPos{X: 3, Y: 5, Z: 3}); got != want {
+		t.Errorf("sign = %q after a second update, want it unchanged", got)
+	}
+}
+
+func TestUpdateSign_IgnoresUnplacedSign(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.world.SetBlock(3, 5, 3, world.BlockStandingSign<<4)
+
+	c.handleUpdateSign(&pkt.UpdateSignSB{Location: mcnet.EncodePosition(3, 5, 3), Text1: `{"text":"spoofed"}`})
+
+	if _, ok := c.world.Sign(world.BlockPos{X: 3, Y: 5, Z: 3}); ok {
+		t.Error("a sign the player did not place should not be editable")
+	}
+}
+
+func TestSendInitialChunks_IncludesSigns(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.cfg.ViewDistance = 1
+	c.world.SetBlock(3, 5, 3, world.BlockStandingSign<<4)
+	c.world.SetSign(world.BlockPos{X: 3, Y: 5, Z: 3}, [4]string{"Spawn"})
+
+	if err := c.sendInitialChunks(); err != nil {
+		t.Fatalf("sendInitialChunks: %v", err)
+	}
+
+	var found bool
+	for _, p := range recordedPackets(t, c) {
+		if p.id != (pkt.UpdateSignCB{}).PacketID() {
+			continue
+		}
+		var u pkt.UpdateSignCB
+		if err := mcnet.Unmarshal(p.data, &u); err != nil {
+			t.Fatalf("unmarshal update sign: %v", err)
+		}
+		found = u.Text1 == `{"text":"Spawn"}`
+	}
+	if !found {
+		t.Error("chunk send did not include the sign text")
+	}
+}
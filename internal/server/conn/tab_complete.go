@@ -1,32 +1,29 @@
 package conn
 
 import (
-	"bytes"
+	"fmt"
 	"strings"
 
 	"github.com/go-theft-craft/server/internal/server/player"
 	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
 	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+	"github.com/go-theft-craft/server/pkg/world"
 )
 
+// maxTabCompleteLength caps tab-complete input; longer requests get no completions.
+const maxTabCompleteLength = maxCommandLength
+
 // handleTabComplete processes a TabComplete (0x14) packet and sends completions back.
 func (c *Connection) handleTabComplete(data []byte) error {
-	r := bytes.NewReader(data)
-
-	text, err := mcnet.ReadString(r)
-	if err != nil {
-		return err
+	// The looked-at block position is not used.
+	var req pkt.TabCompleteSB
+	if err := mcnet.Unmarshal(data, &req); err != nil {
+		return fmt.Errorf("unmarshal tab complete: %w", err)
 	}
+	text := req.Text
 
-	hasPosition, err := mcnet.ReadBool(r)
-	if err != nil {
-		return err
-	}
-	if hasPosition {
-		// Consume the looked-at block position (i64), we don't use it.
-		if _, err := mcnet.ReadI64(r); err != nil {
-			return err
-		}
+	if len(text) > maxTabCompleteLength {
+		return nil
 	}
 
 	matches := computeCompletions(text, c.players)
@@ -69,6 +66,9 @@ func completeCommand(text string, players *player.Manager) []string {
 		return nil
 	}
 	cmdName := strings.ToLower(strings.TrimPrefix(parts[0], "/"))
+	if cmd, ok := lookupCommand(cmdName); ok {
+		cmdName = cmd.name // complete aliases like their command
+	}
 	var argPartial string
 	if !trailingSpace && len(parts) > 1 {
 		argPartial = parts[len(parts)-1]
@@ -87,15 +87,74 @@ func completeCommand(text string, players *player.Manager) []string {
 		if argIndex == 1 {
 			return filterStrings(argPartial, []string{"survival", "creative", "adventure", "spectator"})
 		}
+		if argIndex == 2 {
+			return matchPlayerNames(argPartial, players)
+		}
 	case "time":
 		if argIndex == 1 {
+			return filterStrings(argPartial, []string{"set", "add", "query"})
+		}
+		if argIndex == 2 {
+			switch strings.ToLower(parts[1]) {
+			case "set":
+				return filterStrings(argPartial, []string{"day", "night", "noon", "midnight"})
+			case "query":
+				return filterStrings(argPartial, []string{"daytime", "gametime"})
+			}
+		}
+	case "weather":
+		if argIndex == 1 {
+			return filterStrings(argPartial, []string{"clear", "rain", "thunder"})
+		}
+	case "msg", "clear", "title", "op", "deop", "kick", "ban", "whois", "give", "effect":
+		if argIndex == 1 {
+			return matchPlayerNames(argPartial, players)
+		}
+	case "whitelist":
+		if argIndex == 1 {
+			return filterStrings(argPartial, []string{"add", "remove", "list"})
+		}
+		if argIndex == 2 && strings.ToLower(parts[1]) != "list" {
+			return matchPlayerNames(argPartial, players)
+		}
+	case "summon":
+		if argIndex == 1 {
+			return filterStrings(argPartial, []string{"item", "xp"})
+		}
+	case "scoreboard":
+		switch {
+		case argIndex == 1:
+			return filterStrings(argPartial, []string{"objectives", "players"})
+		case argIndex == 2 && strings.ToLower(parts[1]) == "objectives":
+			return filterStrings(argPartial, []string{"add", "setdisplay"})
+		case argIndex == 2 && strings.ToLower(parts[1]) == "players":
 			return filterStrings(argPartial, []string{"set"})
+		case argIndex == 3 && strings.ToLower(parts[2]) == "setdisplay":
+			return filterStrings(argPartial, []string{"list", "sidebar", "belowname"})
+		case argIndex == 3 && strings.ToLower(parts[2]) == "set":
+			return matchPlayerNames(argPartial, players)
+		}
+	case "speed":
+		if argIndex == 1 {
+			return filterStrings(argPartial, []string{"fly", "walk"})
 		}
+	case "xp":
 		if argIndex == 2 {
-			return filterStrings(argPartial, []string{"day", "night", "noon", "midnight"})
+			return matchPlayerNames(argPartial, players)
 		}
-	case "help", "list", "kill", "seed":
+	case "help", "list", "kill", "seed", "tps", "lag":
 		// No arguments to complete.
+	case "gamerule":
+		if argIndex == 1 {
+			return filterStrings(argPartial, world.GameRuleNames())
+		}
+		if argIndex == 2 {
+			return filterStrings(argPartial, []string{"true", "false"})
+		}
+	case "worldborder":
+		if argIndex == 1 {
+			return filterStrings(argPartial, []string{"set", "center", "get"})
+		}
 	case "say", "me":
 		// Free-form text, complete player names.
 		return matchPlayerNames(argPartial, players)
@@ -127,10 +186,5 @@ func filterStrings(partial string, options []string) []string {
 }
 
 func (c *Connection) sendTabCompleteResponse(matches []string) error {
-	var buf bytes.Buffer
-	_, _ = mcnet.WriteVarInt(&buf, int32(len(matches)))
-	for _, m := range matches {
-		_, _ = mcnet.WriteString(&buf, m)
-	}
-	return c.writePacket(&pkt.TabCompleteCB{Data: buf.Bytes()})
+	return c.writePacket(&pkt.TabCompleteCB{Matches: matches})
 }
@@ -1,10 +1,14 @@
 package conn
 
 import (
+	"bytes"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/go-theft-craft/server/internal/server/player"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
 )
 
 func testManager(names ...string) *player.Manager {
@@ -44,7 +48,7 @@ func assertMatches(t *testing.T, got, want []string) {
 func TestCompleteCommandName(t *testing.T) {
 	m := testManager("Alice")
 	matches := computeCompletions("/t", m)
-	assertMatches(t, matches, []string{"/tp", "/time"})
+	assertMatches(t, matches, []string{"/tp", "/tps", "/time", "/title"})
 }
 
 func TestCompleteCommandNameFull(t *testing.T) {
@@ -88,7 +92,13 @@ func TestCompleteGamemodeAll(t *testing.T) {
 func TestCompleteTimeSet(t *testing.T) {
 	m := testManager("Alice")
 	matches := computeCompletions("/time ", m)
-	assertMatches(t, matches, []string{"set"})
+	assertMatches(t, matches, []string{"set", "add", "query"})
+}
+
+func TestCompleteTimeQuery(t *testing.T) {
+	m := testManager("Alice")
+	matches := computeCompletions("/time query ", m)
+	assertMatches(t, matches, []string{"daytime", "gametime"})
 }
 
 func TestCompleteTimeSetValues(t *testing.T) {
@@ -117,3 +127,80 @@ func TestCompleteSlash(t *testing.T) {
 		t.Errorf("expected %d matches, got %d", len(commands), len(matches))
 	}
 }
+
+func TestHandleTabComplete_RejectsOversizedInput(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	rec := c.rw.(*packetRecorder)
+	rec.buf.Reset()
+
+	var buf bytes.Buffer
+	_, _ = mcnet.WriteString(&buf, "/tp "+strings.Repeat("A", maxTabCompleteLength))
+	buf.WriteByte(0) // no looked-at block
+
+	if err := c.handleTabComplete(buf.Bytes()); err != nil {
+		t.Fatalf("handleTabComplete: %v", err)
+	}
+	if rec.buf.Len() != 0 {
+		t.Error("expected no tab-complete response for oversized input")
+	}
+}
+
+func TestCompletePlayerArguments(t *testing.T) {
+	m := testManager("Alice", "Bob", "Bella")
+	for _, text := range []string{"/kick B", "/ban b", "/whois B", "/give B", "/effect B", "/whitelist add B"} {
+		assertMatches(t, computeCompletions(text, m), []string{"Bob", "Bella"})
+	}
+	assertMatches(t, computeCompletions("/whitelist r", m), []string{"remove"})
+	if got := computeCompletions("/whitelist list ", m); len(got) != 0 {
+		t.Errorf("/whitelist list completed %v, want nothing", got)
+	}
+}
+
+func TestCompleteAliasArguments(t *testing.T) {
+	m := testManager("Alice")
+	// /lag is an alias of /tps, which takes no arguments.
+	if got := computeCompletions("/lag x", m); len(got) != 0 {
+		t.Errorf("/lag completed %v, want nothing", got)
+	}
+}
+
+func TestHandleTabComplete_SendsMatches(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"/ga", []string{"/gamemode", "/gamerule"}},
+		{"/msg A", []string{"Alice"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			c, _, _ := newTestConn("Alice")
+
+			var buf bytes.Buffer
+			_, _ = mcnet.WriteString(&buf, tt.text)
+			buf.WriteByte(0) // no looked-at block
+			if err := c.handleTabComplete(buf.Bytes()); err != nil {
+				t.Fatalf("handleTabComplete: %v", err)
+			}
+
+			pkts := recordedPackets(t, c)
+			if len(pkts) != 1 || pkts[0].id != (&pkt.TabCompleteCB{}).PacketID() {
+				t.Fatalf("packets = %+v, want one TabComplete", pkts)
+			}
+			r := bytes.NewReader(pkts[0].data)
+			n, _, err := mcnet.ReadVarInt(r)
+			if err != nil {
+				t.Fatalf("read count: %v", err)
+			}
+			var got []string
+			for range n {
+				s, err := mcnet.ReadString(r)
+				if err != nil {
+					t.Fatalf("read match: %v", err)
+				}
+				got = append(got, s)
+			}
+			assertMatches(t, got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package conn
+
+import (
+	"github.com/go-theft-craft/server/internal/server/player"
+	"github.com/go-theft-craft/server/pkg/gamedata"
+)
+
+// Window type IDs as named in gameData.Windows.
+const (
+	windowTypePlayer        = ""
+	windowTypeCraftingTable = "minecraft:crafting_table"
+	windowTypeChest         = "minecraft:chest"
+	windowTypeFurnace       = "minecraft:furnace"
+)
+
+// chestSize is the number of slots in a single chest.
+const chestSize = 27
+
+// Sizes of the player inventory sections appended after a window's own slots.
+const (
+	mainInventorySize = 27
+	hotbarSize        = 9
+	armorSectionSize  = 4
+)
+
+// windowLayout maps the protocol slot indices of a window onto its crafting
+// grid and the player inventory sections that follow the window's own slots.
+type windowLayout struct {
+	craftOutput int16 // -1 if the window has no crafting output
+	craftStart  int16
+	craftSize   int   // number of crafting grid cells (0, 4 or 9)
+	armorStart  int16 // -1 if the window does not show armor
+	mainStart   int16
+	hotbarStart int16
+}
+
+// playerWindowLayout is the layout of window 0, used when no game data is
+// available. It matches the gameData "Player" window.
+var playerWindowLayout = windowLayout{
+	craftOutput: slotCraftOutput,
+	craftStart:  slotCraftStart,
+	craftSize:   slotCraftCount,
+	armorStart:  slotArmorStart,
+	mainStart:   slotMainStart,
+	hotbarStart: slotHotbarStart,
+}
+
+// newWindowLayout derives a layout from a gameData window definition. The
+// player's main inventory and hotbar follow the last slot the window defines.
+func newWindowLayout(w gamedata.Window) windowLayout {
+	l := windowLayout{craftOutput: -1, armorStart: -1}
+	end := 0
+	for _, s := range w.Slots {
+		switch s.Name {
+		case "craft result":
+			l.craftOutput = int16(s.Index)
+		case "craft grid":
+			l.craftStart = int16(s.Index)
+			l.craftSize = s.Size
+		case "armor":
+			l.armorStart = int16(s.Index)
+		}
+		if n := s.Index + max(s.Size, 1); n > end {
+			end = n
+		}
+	}
+	l.mainStart = int16(end)
+	l.hotbarStart = l.mainStart + mainInventorySize
+	return l
+}
+
+// windowLayoutFor looks up the layout for a window type in the game data.
+func windowLayoutFor(gd *gamedata.GameData, windowType string) (windowLayout, bool) {
+	if gd == nil || gd.Windows == nil {
+		if windowType == windowTypePlayer {
+			return playerWindowLayout, true
+		}
+		return windowLayout{}, false
+	}
+	w, ok := gd.Windows.ByID(windowType)
+	if !ok {
+		return windowLayout{}, false
+	}
+	return newWindowLayout(w), true
+}
+
+func (l windowLayout) craftEnd() int16  { return l.craftStart + int16(l.craftSize) - 1 }
+func (l windowLayout) mainEnd() int16   { return l.hotbarStart - 1 }
+func (l windowLayout) hotbarEnd() int16 { return l.hotbarStart + hotbarSize - 1 }
+func (l windowLayout) total() int16     { return l.hotbarEnd() + 1 }
+
+// gridWidth returns the side length of the square crafting grid.
+func (l windowLayout) gridWidth() int {
+	w := 0
+	for w*w < l.craftSize {
+		w++
+	}
+	return w
+}
+
+func (l windowLayout) isCraftOutput(s int16) bool {
+	return l.craftOutput >= 0 && s == l.craftOutput
+}
+
+func (l windowLayout) isCraftSlot(s int16) bool {
+	return l.craftSize > 0 && s >= l.craftStart && s <= l.craftEnd()
+}
+
+func (l windowLayout) isArmorSlot(s int16) bool {
+	return l.armorStart >= 0 && s >= l.armorStart && s < l.armorStart+armorSectionSize
+}
+
+// inventorySlot converts a window slot index into the player inventory's
+// window 0 index, or returns false if the slot is not part of the inventory.
+func (l windowLayout) inventorySlot(s int16) (int16, bool) {
+	switch {
+	case l.isArmorSlot(s):
+		return slotArmorStart + (s - l.armorStart), true
+	case s >= l.mainStart && s <= l.hotbarEnd():
+		return slotMainStart + (s - l.mainStart), true
+	default:
+		return 0, false
+	}
+}
+
+// windowSlot converts a player inventory window 0 index into this window's
+// index, or returns false if the window does not show that slot.
+func (l windowLayout) windowSlot(invSlot int16) (int16, bool) {
+	switch {
+	case invSlot >= slotArmorStart && invSlot <= slotArmorEnd:
+		if l.armorStart < 0 {
+			return 0, false
+		}
+		return l.armorStart + (invSlot - slotArmorStart), true
+	case invSlot >= slotMainStart && invSlot <= slotHotbarEnd:
+		return l.mainStart + (invSlot - slotMainStart), true
+	default:
+		return 0, false
+	}
+}
+
+// openWindow switches the connection to a window of the given type, sizing
+// the crafting grid from its layout. It returns false for unknown types.
+func (c *Connection) openWindow(windowID uint8, windowType string) bool {
+	l, ok := windowLayoutFor(c.gameData, windowType)
+	if !ok {
+		return false
+	}
+	c.windowID = windowID
+	c.window = l
+	c.craftingGrid = newCraftingGrid(l.craftSize)
+	c.craftingOutput = player.EmptySlot
+	return true
+}
+
+// openContainer opens a window of the given type with size container slots
+// of its own, for windows such as chests whose size is not fixed by the game
+// data. The player inventory follows the container slots.
+func (c *Connection) openContainer(windowID uint8, windowType string, size int) bool {
+	if !c.openWindow(windowID, windowType) {
+		return false
+	}
+	if start := int16(size); start > c.window.mainStart {
+		c.window.mainStart = start
+		c.window.hotbarStart = start + mainInventorySize
+	}
+	return true
+}
+
+// newCraftingGrid returns n empty crafting cells.
+func newCraftingGrid(n int) []player.Slot {
+	grid := make([]player.Slot, n)
+	for i := range grid {
+		grid[i] = player.EmptySlot
+	}
+	return grid
+}
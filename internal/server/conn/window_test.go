@@ -0,0 +1,242 @@
+package conn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/go-theft-craft/server/internal/server/player"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+)
+
+// windowClickData builds a left-click (mode 0) Click Window payload.
+func windowClickData(windowID uint8, slot int16) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte(windowID)
+	_ = binary.Write(&buf, binary.BigEndian, slot)
+	buf.WriteByte(0)                                    // button: left
+	_ = binary.Write(&buf, binary.BigEndian, int16(1))  // action number
+	buf.WriteByte(0)                                    // mode: normal click
+	_ = binary.Write(&buf, binary.BigEndian, int16(-1)) // clicked item: empty
+	return buf.Bytes()
+}
+
+func TestWindowLayout_FromGameData(t *testing.T) {
+	gd := pkt.New()
+
+	table, ok := windowLayoutFor(gd, windowTypeCraftingTable)
+	if !ok {
+		t.Fatal("crafting table window not found in game data")
+	}
+	want := windowLayout{craftOutput: 0, craftStart: 1, craftSize: 9, armorStart: -1, mainStart: 10, hotbarStart: 37}
+	if table != want {
+		t.Errorf("crafting table layout = %+v, want %+v", table, want)
+	}
+	if table.gridWidth() != 3 || table.craftEnd() != 9 || table.total() != 46 {
+		t.Errorf("gridWidth=%d craftEnd=%d total=%d, want 3, 9, 46", table.gridWidth(), table.craftEnd(), table.total())
+	}
+
+	inv, ok := windowLayoutFor(gd, windowTypePlayer)
+	if !ok {
+		t.Fatal("player window not found in game data")
+	}
+	if inv != playerWindowLayout {
+		t.Errorf("player layout = %+v, want %+v", inv, playerWindowLayout)
+	}
+}
+
+func TestWindowClick_CraftingTableGridCells(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	if !c.openWindow(1, windowTypeCraftingTable) {
+		t.Fatal("openWindow failed")
+	}
+	if len(c.craftingGrid) != 9 {
+		t.Fatalf("crafting grid has %d cells, want 9", len(c.craftingGrid))
+	}
+
+	// Slot 9 is the bottom-right cell of the 3x3 grid.
+	c.cursorSlot = stone(5)
+	if err := c.handleWindowClick(windowClickData(1, 9)); err != nil {
+		t.Fatalf("handleWindowClick: %v", err)
+	}
+	if c.craftingGrid[8] != stone(5) {
+		t.Errorf("grid cell 8 = %+v, want stone(5)", c.craftingGrid[8])
+	}
+
+	// Slot 10 is the first main inventory slot, shifted past the grid.
+	c.cursorSlot = dirt(3)
+	if err := c.handleWindowClick(windowClickData(1, 10)); err != nil {
+		t.Fatalf("handleWindowClick: %v", err)
+	}
+	if got := c.self.Inventory.GetProtocolSlot(slotMainStart); got != dirt(3) {
+		t.Errorf("main inventory slot = %+v, want dirt(3)", got)
+	}
+
+	// Slot 45 is the last hotbar slot.
+	c.self.Inventory.SetSlot(8, sword())
+	if got := c.getWindowSlot(45); got != sword() {
+		t.Errorf("window slot 45 = %+v, want the sword in hotbar slot 8", got)
+	}
+}
+
+func TestWindowClick_RejectsClosedWindow(t *testing.T) {
+	c := newInventoryTestConn()
+	c.cursorSlot = stone(5)
+
+	if err := c.handleWindowClick(windowClickData(3, 9)); err != nil {
+		t.Fatalf("handleWindowClick: %v", err)
+	}
+	if c.cursorSlot != stone(5) {
+		t.Error("click on a window that is not open should be ignored")
+	}
+}
+
+func TestCloseWindow_ReturnsToPlayerLayout(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	c.openWindow(1, windowTypeCraftingTable)
+	c.craftingGrid[8] = stone(5)
+
+	_ = c.handleCloseWindow([]byte{1})
+
+	if c.windowID != 0 || c.window != playerWindowLayout || len(c.craftingGrid) != slotCraftCount {
+		t.Errorf("after close: window %d layout %+v grid %d cells, want the player inventory", c.windowID, c.window, len(c.craftingGrid))
+	}
+	if got := c.self.Inventory.GetProtocolSlot(slotMainStart); got != stone(5) {
+		t.Errorf("grid item not returned to inventory, main slot = %+v", got)
+	}
+}
+
+func TestOpenContainer_ChestLayout(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	if !c.openContainer(2, windowTypeChest, chestSize) {
+		t.Fatal("openContainer(chest) failed")
+	}
+	if c.window.mainStart != chestSize || c.window.total() != chestSize+36 {
+		t.Errorf("chest layout mainStart=%d total=%d, want %d, %d", c.window.mainStart, c.window.total(), chestSize, chestSize+36)
+	}
+	if inv, ok := c.window.inventorySlot(chestSize); !ok || inv != slotMainStart {
+		t.Errorf("inventorySlot(%d) = %d, %v, want %d", chestSize, inv, ok, slotMainStart)
+	}
+}
+
+func TestCloseWindow_ChestClearsStateAndReturnsCursor(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	c.openContainer(2, windowTypeChest, chestSize)
+	c.cursorSlot = dirt(7)
+
+	if err := c.handleCloseWindow([]byte{2}); err != nil {
+		t.Fatalf("handleCloseWindow: %v", err)
+	}
+
+	if c.windowID != 0 || c.window != playerWindowLayout {
+		t.Errorf("after close: window %d layout %+v, want the player inventory", c.windowID, c.window)
+	}
+	if !c.cursorSlot.IsEmpty() {
+		t.Errorf("cursor = %+v, want empty", c.cursorSlot)
+	}
+	if got := c.self.Inventory.GetProtocolSlot(slotMainStart); got != dirt(7) {
+		t.Errorf("cursor item not returned to inventory, main slot = %+v", got)
+	}
+
+	// A click aimed at the closed chest is rejected.
+	if err := c.handleWindowClick(windowClickData(2, slotMainStart)); err != nil {
+		t.Fatalf("handleWindowClick: %v", err)
+	}
+	if !c.cursorSlot.IsEmpty() {
+		t.Error("click on the closed chest window should be ignored")
+	}
+}
+
+func TestCloseWindow_IgnoresStaleWindowID(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	c.openContainer(3, windowTypeChest, chestSize)
+
+	_ = c.handleCloseWindow([]byte{2})
+
+	if c.windowID != 3 {
+		t.Errorf("windowID = %d, want the chest (3) to stay open", c.windowID)
+	}
+}
+
+func TestBlockPlace_OpensCraftingTable(t *testing.T) {
+	c := newInventoryTestConn()
+	c.gameData = pkt.New()
+	c.world.SetBlock(2, 4, 2, blockCraftingTable<<4)
+
+	if err := c.handleBlockPlace(blockPlaceData(2, 4, 2, 1, -1)); err != nil {
+		t.Fatalf("handleBlockPlace: %v", err)
+	}
+
+	if c.windowID == 0 || len(c.craftingGrid) != 9 {
+		t.Fatalf("window %d with %d grid cells, want a crafting table", c.windowID, len(c.craftingGrid))
+	}
+	if n := countPackets(t, c, pkt.OpenWindow{}.PacketID()); n != 1 {
+		t.Errorf("sent %d OpenWindow packets, want 1", n)
+	}
+}
+
+func TestCraftingTable_CraftsThreeByThreeRecipes(t *testing.T) {
+	tests := []struct {
+		name   string
+		ring   player.Slot
+		result int16
+	}{
+		{"chest", player.Slot{BlockID: 5, ItemCount: 1}, 54},
+		{"furnace", player.Slot{BlockID: 4, ItemCount: 1}, 61},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newInventoryTestConn()
+			c.gameData = pkt.New()
+			c.openWindow(1, windowTypeCraftingTable)
+
+			// Fill the grid through window clicks, leaving the center empty.
+			for cell := int16(1); cell <= 9; cell++ {
+				if cell == 5 {
+					continue
+				}
+				c.cursorSlot = tt.ring
+				if err := c.handleWindowClick(windowClickData(1, cell)); err != nil {
+					t.Fatalf("click cell %d: %v", cell, err)
+				}
+			}
+			if c.craftingOutput.BlockID != tt.result {
+				t.Fatalf("crafting output = %+v, want item %d", c.craftingOutput, tt.result)
+			}
+
+			if err := c.handleWindowClick(windowClickData(1, 0)); err != nil {
+				t.Fatalf("click output: %v", err)
+			}
+			if c.cursorSlot.BlockID != tt.result {
+				t.Errorf("cursor = %+v, want item %d", c.cursorSlot, tt.result)
+			}
+			for i, s := range c.craftingGrid {
+				if !s.IsEmpty() {
+					t.Errorf("grid cell %d = %+v, want consumed", i, s)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchRecipe_ThreeByThreeNotInPlayerGrid(t *testing.T) {
+	recipes := pkt.New().Recipes
+	planks := player.Slot{BlockID: 5, ItemCount: 1}
+
+	// Two planks stacked craft sticks in either grid size.
+	grid := []player.Slot{planks, player.EmptySlot, planks, player.EmptySlot}
+	if got := matchRecipe(grid, 2, recipes); got.BlockID != 280 {
+		t.Errorf("2x2 sticks = %+v, want sticks", got)
+	}
+
+	// A full 2x2 of planks is a crafting table, never a chest.
+	grid = []player.Slot{planks, planks, planks, planks}
+	if got := matchRecipe(grid, 2, recipes); got.BlockID == 54 {
+		t.Error("2x2 grid crafted a chest")
+	}
+}
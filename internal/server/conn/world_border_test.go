@@ -0,0 +1,181 @@
+package conn
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/go-theft-craft/server/internal/server/packet"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+	"github.com/go-theft-craft/server/pkg/world"
+)
+
+// decodeWorldBorder reads the center and new diameter from an initialize
+// WorldBorder packet.
+func decodeWorldBorder(t *testing.T, data []byte) (x, z, diameter float64) {
+	t.Helper()
+	r := bytes.NewReader(data)
+	action, _, err := mcnet.ReadVarInt(r)
+	if err != nil || action != 3 {
+		t.Fatalf("action = %d (%v), want initialize (3)", action, err)
+	}
+	var vals [4]float64
+	for i := range vals {
+		if vals[i], err = mcnet.ReadF64(r); err != nil {
+			t.Fatalf("read border field %d: %v", i, err)
+		}
+	}
+	return vals[0], vals[1], vals[3]
+}
+
+func TestCmdWorldborder_BroadcastsBorder(t *testing.T) {
+	c, sp, m := newTestConn("Alice")
+	_, bob := addTestPlayer(m, "Bob")
+	sp.reset()
+	bob.reset()
+
+	c.handleCommand("/worldborder set 200")
+	c.handleCommand("/worldborder center 50 -25")
+
+	if b := c.world.Border(); b.Diameter != 200 || b.CenterX != 50 || b.CenterZ != -25 {
+		t.Errorf("border = %+v, want 200 wide at (50, -25)", b)
+	}
+	var last *pkt.WorldBorder
+	for _, p := range bob.get() {
+		if wb, ok := p.(*pkt.WorldBorder); ok {
+			last = wb
+		}
+	}
+	if last == nil {
+		t.Fatal("Bob was not sent the new border")
+	}
+	if x, z, d := decodeWorldBorder(t, last.Data); x != 50 || z != -25 || d != 200 {
+		t.Errorf("packet border = %v wide at (%v, %v), want 200 at (50, -25)", d, x, z)
+	}
+
+	c.rw.(*packetRecorder).buf.Reset()
+	c.handleCommand("/worldborder set 0")
+	if chat := recordedChat(t, c); len(chat) != 1 || !strings.Contains(chat[0], "Size must be") {
+		t.Errorf("chat = %q, want a size error", chat)
+	}
+}
+
+func TestStartPlay_SyncsWorldBorder(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	c.cfg.ViewDistance = 1
+	defer c.cancel()
+	b := c.world.Border()
+	b.Diameter = 64
+	c.world.SetBorder(b)
+
+	if err := c.startPlay("Bob", "00000000-0000-0000-0000-000000000002", nil); err != nil {
+		t.Fatalf("startPlay: %v", err)
+	}
+
+	var found bool
+	for _, p := range recordedPackets(t, c) {
+		if p.id != (pkt.WorldBorder{}).PacketID() {
+			continue
+		}
+		found = true
+		if _, _, d := decodeWorldBorder(t, p.data); d != 64 {
+			t.Errorf("joined with a %v wide border, want 64", d)
+		}
+	}
+	if !found {
+		t.Error("joining player was not sent the world border")
+	}
+}
+
+func TestPositionUpdate_ClampsToBorder(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	b := c.world.Border()
+	b.Diameter = 20 // -10 to 10
+	c.world.SetBorder(b)
+
+	c.handlePositionUpdate(14.5, 5, 0.5, 0, 0, true, true, false)
+
+	if pos := c.self.GetPosition(); pos.X >= 10 {
+		t.Errorf("x = %v, want the player kept inside the border", pos.X)
+	}
+}
+
+func TestBorderDamage_HurtsPlayersFarOutside(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.self.SetGameMode(packet.GameModeSurvival)
+	b := c.world.Border()
+	b.Diameter = 20 // -10 to 10
+	c.world.SetBorder(b)
+
+	c.self.SetPosition(13, 5, 0, 0, 0, true) // within the damage buffer
+	c.applyBorderDamage()
+	if h := c.self.GetHealth(); h != 20 {
+		t.Fatalf("health = %v, want no damage within the buffer", h)
+	}
+
+	c.self.SetPosition(40, 5, 0, 0, 0, true) // 25 blocks past the buffer
+	c.applyBorderDamage()
+	if h := c.self.GetHealth(); h != 15 {
+		t.Errorf("health = %v, want 15 after 5 border damage", h)
+	}
+}
+
+func TestCmdWorldborder_LimitedToWorldRadius(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.cfg.WorldRadius = 1 // chunks -1 to 1: 48 blocks wide, centered on 8, 8
+	c.world.SetDefaultBorder(world.RadiusBorder(1))
+
+	c.handleCommand("/worldborder set 100")
+	c.handleCommand("/worldborder center 100 0")
+	if b := c.world.Border(); b != world.RadiusBorder(1) {
+		t.Errorf("border = %+v, want the world radius border kept", b)
+	}
+
+	c.handleCommand("/worldborder set 32")
+	if b := c.world.Border(); b.Diameter != 32 {
+		t.Errorf("diameter = %v, want 32", b.Diameter)
+	}
+}
+
+func TestCanPlaceAt_ChecksBorderPerBlock(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	b := c.world.Border()
+	b.Diameter = 20 // -10 to 10, inside chunk 0's 0 to 15
+	c.world.SetBorder(b)
+
+	if !c.canPlaceAt(9, 10, 0, 1<<4) {
+		t.Error("placement just inside the border was refused")
+	}
+	if c.canPlaceAt(10, 10, 0, 1<<4) {
+		t.Error("placement just outside the border was allowed")
+	}
+}
+
+func TestCmdTp_OutsideBorder(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.world.SetBorder(world.RadiusBorder(1))
+
+	c.handleCommand("/tp 100 10 0")
+
+	if pos := c.self.GetPosition(); pos.X != 0.5 {
+		t.Errorf("x = %v, want the player left at 0.5", pos.X)
+	}
+	if chat := recordedChat(t, c); len(chat) == 0 || !strings.Contains(chat[len(chat)-1], "outside the world border") {
+		t.Errorf("chat = %q, want a border error", chat)
+	}
+}
+
+func TestCmdSetblock_OutsideBorder(t *testing.T) {
+	c, _, _ := newTestConn("Alice")
+	c.gameData = pkt.New()
+	c.world.SetBorder(world.RadiusBorder(1))
+
+	c.handleCommand("/setblock 40 10 0 stone")
+
+	if got := c.world.GetBlock(40, 10, 0); got != 0 {
+		t.Errorf("setblock outside the border placed %d", got)
+	}
+}
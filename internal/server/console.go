@@ -0,0 +1,110 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-theft-craft/server/internal/server/chat"
+	"github.com/go-theft-craft/server/internal/server/player"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+)
+
+type consoleCommand struct {
+	name    string
+	usage   string
+	desc    string
+	handler func(s *Server, args []string, out io.Writer)
+}
+
+var consoleCommands []consoleCommand
+
+func init() {
+	consoleCommands = []consoleCommand{
+		{name: "help", usage: "help", desc: "Show available commands", handler: consoleHelp},
+		{name: "list", usage: "list", desc: "Show online players", handler: consoleList},
+		{name: "say", usage: "say <message>", desc: "Broadcast an announcement", handler: consoleSay},
+		{name: "save", usage: "save", desc: "Save world and player data", handler: consoleSave},
+		{name: "stop", usage: "stop", desc: "Save and shut down the server", handler: consoleStop},
+	}
+}
+
+// readConsole reads command lines from r and runs them until r is exhausted
+// or ctx is cancelled. On EOF (e.g. no TTY attached) the reader is disabled.
+func (s *Server) readConsole(ctx context.Context, r io.Reader, out io.Writer) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return
+		}
+		s.runConsoleCommand(scanner.Text(), out)
+	}
+	if err := scanner.Err(); err != nil {
+		s.log.Error("read console input", "error", err)
+		return
+	}
+	s.log.Info("console input closed, disabling console reader")
+}
+
+// runConsoleCommand dispatches a single console line. A leading slash is
+// accepted for parity with in-game commands.
+func (s *Server) runConsoleCommand(line string, out io.Writer) {
+	parts := strings.Fields(line)
+	if len(parts) == 0 {
+		return
+	}
+
+	name := strings.ToLower(strings.TrimPrefix(parts[0], "/"))
+	args := parts[1:]
+
+	for _, cmd := range consoleCommands {
+		if cmd.name == name {
+			cmd.handler(s, args, out)
+			return
+		}
+	}
+
+	fmt.Fprintf(out, "Unknown command: %s. Type help for a list of commands.\n", name)
+}
+
+func consoleHelp(_ *Server, _ []string, out io.Writer) {
+	fmt.Fprintln(out, "--- Available Commands ---")
+	for _, cmd := range consoleCommands {
+		fmt.Fprintf(out, "%s - %s\n", cmd.usage, cmd.desc)
+	}
+}
+
+func consoleList(s *Server, _ []string, out io.Writer) {
+	var names []string
+	s.players.ForEach(func(p *player.Player) {
+		names = append(names, p.Username)
+	})
+	fmt.Fprintf(out, "Online players (%d): %s\n", len(names), strings.Join(names, ", "))
+}
+
+func consoleSay(s *Server, args []string, out io.Writer) {
+	if len(args) == 0 {
+		fmt.Fprintln(out, "Usage: say <message>")
+		return
+	}
+	msg := strings.Join(args, " ")
+	s.players.Broadcast(&pkt.ChatCB{
+		Message:  chat.Colored("[Server] "+msg, chat.LightPurple).String(),
+		Position: 0,
+	})
+}
+
+func consoleSave(s *Server, _ []string, out io.Writer) {
+	fmt.Fprintln(out, "Saving world and player data...")
+	s.saveAll()
+	fmt.Fprintln(out, "Save complete.")
+}
+
+func consoleStop(s *Server, _ []string, out io.Writer) {
+	fmt.Fprintln(out, "Stopping server...")
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
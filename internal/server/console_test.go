@@ -0,0 +1,83 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-theft-craft/server/internal/server/config"
+	"github.com/go-theft-craft/server/internal/server/player"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+)
+
+func newTestServer() *Server {
+	cfg := config.DefaultConfig()
+	cfg.GeneratorType = config.GeneratorFlat
+	return mustNew(cfg)
+}
+
+func addTestPlayer(s *Server, name string) *player.Player {
+	eid := s.players.AllocateEntityID()
+	p := player.NewPlayer(eid, "uuid-"+name, [16]byte{byte(eid)}, name, nil, func(mcnet.Packet) error { return nil })
+	p.SetPosition(0.5, 5, 0.5, 0, 0, true)
+	s.players.Add(p)
+	return p
+}
+
+func TestConsoleList(t *testing.T) {
+	s := newTestServer()
+	addTestPlayer(s, "Alice")
+	addTestPlayer(s, "Bob")
+
+	var out bytes.Buffer
+	s.readConsole(context.Background(), strings.NewReader("list\n"), &out)
+
+	got := out.String()
+	if !strings.Contains(got, "Online players (2)") {
+		t.Errorf("list output = %q, want player count", got)
+	}
+	if !strings.Contains(got, "Alice") || !strings.Contains(got, "Bob") {
+		t.Errorf("list output = %q, want both player names", got)
+	}
+}
+
+func TestConsoleStopCancelsContext(t *testing.T) {
+	s := newTestServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.cancel = cancel
+
+	var out bytes.Buffer
+	s.readConsole(ctx, strings.NewReader("stop\nlist\n"), &out)
+
+	if ctx.Err() == nil {
+		t.Fatal("expected stop to cancel the server context")
+	}
+	if strings.Contains(out.String(), "Online players") {
+		t.Error("expected commands after stop to be ignored")
+	}
+}
+
+func TestConsoleUnknownCommand(t *testing.T) {
+	s := newTestServer()
+
+	var out bytes.Buffer
+	s.readConsole(context.Background(), strings.NewReader("frobnicate\n"), &out)
+
+	if !strings.Contains(out.String(), "Unknown command") {
+		t.Errorf("output = %q, want unknown command message", out.String())
+	}
+}
+
+func TestConsoleEOF(t *testing.T) {
+	s := newTestServer()
+
+	// An empty reader behaves like a closed stdin; the reader must return.
+	var out bytes.Buffer
+	s.readConsole(context.Background(), strings.NewReader(""), &out)
+
+	if out.Len() != 0 {
+		t.Errorf("expected no output on EOF, got %q", out.String())
+	}
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"github.com/go-theft-craft/server/internal/server/player"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+	"github.com/go-theft-craft/server/pkg/world"
+)
+
+// breakBlocks broadcasts blocks removed by world simulation, such as decayed
+// leaves, with the break effect, and spawns their drops.
+func (s *Server) breakBlocks(broken []world.BlockBreak) {
+	for _, b := range broken {
+		loc := mcnet.EncodePosition(b.Pos.X, b.Pos.Y, b.Pos.Z)
+		s.players.Broadcast(&pkt.BlockChange{Location: loc, Type: 0})
+		s.players.Broadcast(&pkt.WorldEvent{EffectID: 2001, Location: loc, Data: b.State})
+
+		for _, drop := range s.decayDrops(b.State) {
+			groundY := s.groundLevel(b.Pos.X, b.Pos.Y, b.Pos.Z)
+			s.players.SpawnBlockDrop(drop, float64(b.Pos.X)+0.5, float64(groundY)+0.1, float64(b.Pos.Z)+0.5, float64(b.Pos.Y)+0.5)
+		}
+	}
+}
+
+// decayDrops rolls the drop table of a block removed without a tool. Leaves
+// use their own odds rather than the table.
+func (s *Server) decayDrops(state int32) []player.Slot {
+	if player.IsLeaves(state) {
+		return player.LeafDrops(state, s.scheduledRNG)
+	}
+	block, ok := s.gameData.Blocks.ByID(int(state >> 4))
+	if !ok {
+		return nil
+	}
+
+	var drops []player.Slot
+	for _, d := range block.Drops {
+		if d.ID <= 0 {
+			continue
+		}
+		count := d.MinCount
+		if d.MinCount == 0 && d.MaxCount == 0 {
+			count = 1
+		} else if d.MaxCount > d.MinCount {
+			count += s.scheduledRNG.Intn(d.MaxCount - d.MinCount + 1)
+		}
+		if count <= 0 {
+			continue
+		}
+		drops = append(drops, player.Slot{BlockID: int16(d.ID), ItemCount: int8(count), ItemDamage: int16(d.Metadata)})
+	}
+	return drops
+}
+
+// groundLevel returns the Y coordinate an item dropped at (x, y, z) comes to
+// rest at: the top of the first non-air block below it.
+func (s *Server) groundLevel(x, y, z int) int {
+	for y--; y >= 0; y-- {
+		if s.world.GetBlock(x, y, z) != 0 {
+			return y + 1
+		}
+	}
+	return 0
+}
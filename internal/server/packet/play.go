@@ -30,3 +30,13 @@ const (
 	AbilityAllowFlight  int8 = 0x04
 	AbilityCreativeMode int8 = 0x08
 )
+
+// GameStateChange reasons. The 1.8 client starts rain on reason 1 and stops
+// it on reason 2.
+const (
+	GameStateBeginRain    uint8 = 1
+	GameStateEndRain      uint8 = 2
+	GameStateGameMode     uint8 = 3
+	GameStateRainLevel    uint8 = 7
+	GameStateThunderLevel uint8 = 8
+)
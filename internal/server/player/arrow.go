@@ -0,0 +1,269 @@
+package player
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"slices"
+	"sort"
+
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+	"github.com/go-theft-craft/server/pkg/world"
+)
+
+// objectArrow is the SpawnEntity object type of an arrow.
+const objectArrow = 60
+
+// Arrow flight, as in vanilla: arrows move, then slow by drag and fall.
+const (
+	ArrowSpeed   = 3.0  // blocks per tick when fired from a fully drawn bow
+	arrowGravity = 0.05 // blocks/tick² downward
+	arrowDrag    = 0.99 // velocity multiplier per tick
+
+	// arrowStepLength is the longest distance checked for a hit in one go;
+	// each tick's movement is split into steps so fast arrows cannot pass
+	// through a block or player.
+	arrowStepLength = 0.25
+
+	// arrowLifetimeTicks is how long an arrow exists, in flight or stuck,
+	// before it is removed (1 minute).
+	arrowLifetimeTicks = 1200
+
+	// arrowDamagePerSpeed is the damage per block/tick of speed on impact.
+	arrowDamagePerSpeed = 2
+
+	// maxArrowsPerShooter caps the arrows one player has in the world; a
+	// new shot removes that player's oldest arrow.
+	maxArrowsPerShooter = 32
+)
+
+// Player hitbox used for arrow hits.
+const (
+	playerHalfWidth = 0.3
+	playerHeight    = 1.8
+	playerEyeHeight = 1.62
+)
+
+// Arrow is an arrow in flight or stuck in a block.
+type Arrow struct {
+	EntityID   int32
+	ShooterID  int32
+	X, Y, Z    float64
+	VX, VY, VZ float64 // blocks per tick
+	Stuck      bool
+
+	age int
+	hit func(target *Player, damage float32)
+}
+
+// ArrowVelocity returns the velocity, in blocks per tick, of an arrow fired
+// at speed by a player looking along yaw and pitch (degrees).
+func ArrowVelocity(yaw, pitch float32, speed float64) (vx, vy, vz float64) {
+	yawRad := float64(yaw) * math.Pi / 180
+	pitchRad := float64(pitch) * math.Pi / 180
+	vx = -math.Sin(yawRad) * math.Cos(pitchRad) * speed
+	vy = -math.Sin(pitchRad) * speed
+	vz = math.Cos(yawRad) * math.Cos(pitchRad) * speed
+	return vx, vy, vz
+}
+
+// ShootArrow fires an arrow from the shooter's eyes along their look and
+// broadcasts it. hit is called from the tick loop with the damage when the
+// arrow hits another player. A shooter at maxArrowsPerShooter loses their
+// oldest arrow.
+func (m *Manager) ShootArrow(shooter *Player, speed float64, hit func(target *Player, damage float32)) *Arrow {
+	pos := shooter.GetPosition()
+	a := &Arrow{
+		EntityID:  m.AllocateEntityID(),
+		ShooterID: shooter.EntityID,
+		X:         pos.X,
+		Y:         pos.Y + playerEyeHeight - 0.1,
+		Z:         pos.Z,
+		hit:       hit,
+	}
+	a.VX, a.VY, a.VZ = ArrowVelocity(pos.Yaw, pos.Pitch, speed)
+
+	m.arrowMu.Lock()
+	var own []int32
+	for id, other := range m.arrows {
+		if other.ShooterID == a.ShooterID {
+			own = append(own, id)
+		}
+	}
+	var removed []int32
+	if len(own) >= maxArrowsPerShooter {
+		// Entity IDs are allocated in order, so the lowest are the oldest.
+		slices.Sort(own)
+		removed = own[:len(own)-maxArrowsPerShooter+1]
+		for _, id := range removed {
+			delete(m.arrows, id)
+		}
+	}
+	m.arrows[a.EntityID] = a
+	m.arrowMu.Unlock()
+
+	m.broadcastDestroy(removed)
+	m.Broadcast(&pkt.SpawnEntity{Data: buildSpawnArrowData(a, pos.Yaw, pos.Pitch)})
+	m.Broadcast(a.velocityPacket())
+	return a
+}
+
+// TickArrows moves every arrow in flight by one tick. An arrow that reaches
+// a block stops there; one that reaches a player other than its shooter
+// damages them and is removed. Arrows are removed once their lifetime ends.
+func (m *Manager) TickArrows(w *world.World) {
+	var players []*Player
+	m.ForEach(func(p *Player) { players = append(players, p) })
+
+	type arrowHit struct {
+		target *Player
+		damage float32
+		hit    func(*Player, float32)
+	}
+	var (
+		hits    []arrowHit
+		removed []int32
+		packets []mcnet.Packet
+	)
+
+	m.arrowMu.Lock()
+	ids := make([]int32, 0, len(m.arrows))
+	for id := range m.arrows {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		a := m.arrows[id]
+		a.age++
+		if a.age > arrowLifetimeTicks {
+			removed = append(removed, id)
+			continue
+		}
+		if a.Stuck {
+			continue
+		}
+
+		target, stuck := a.step(w, players)
+		switch {
+		case target != nil:
+			speed := math.Sqrt(a.VX*a.VX + a.VY*a.VY + a.VZ*a.VZ)
+			hits = append(hits, arrowHit{target, float32(math.Ceil(speed * arrowDamagePerSpeed)), a.hit})
+			removed = append(removed, id)
+		case stuck:
+			a.VX, a.VY, a.VZ = 0, 0, 0
+			packets = append(packets, a.velocityPacket(), &pkt.EntityTeleport{
+				EntityID: a.EntityID,
+				X:        FixedPoint(a.X),
+				Y:        FixedPoint(a.Y),
+				Z:        FixedPoint(a.Z),
+			})
+		case a.Y < 0:
+			removed = append(removed, id)
+		default:
+			a.VX *= arrowDrag
+			a.VY = a.VY*arrowDrag - arrowGravity
+			a.VZ *= arrowDrag
+		}
+	}
+	for _, id := range removed {
+		delete(m.arrows, id)
+	}
+	m.arrowMu.Unlock()
+
+	for _, p := range packets {
+		m.Broadcast(p)
+	}
+	m.broadcastDestroy(removed)
+	for _, h := range hits {
+		if h.hit != nil {
+			h.hit(h.target, h.damage)
+		}
+	}
+}
+
+// step moves the arrow along its velocity in short steps. It stops at the
+// first player hit, which it returns, or the first block entered, in which
+// case the arrow is left at that point and marked stuck.
+func (a *Arrow) step(w *world.World, players []*Player) (target *Player, stuck bool) {
+	speed := math.Sqrt(a.VX*a.VX + a.VY*a.VY + a.VZ*a.VZ)
+	steps := max(int(math.Ceil(speed/arrowStepLength)), 1)
+	dx, dy, dz := a.VX/float64(steps), a.VY/float64(steps), a.VZ/float64(steps)
+
+	for range steps {
+		x, y, z := a.X+dx, a.Y+dy, a.Z+dz
+		if arrowBlocked(w, x, y, z) {
+			a.Stuck = true
+			return nil, true
+		}
+		a.X, a.Y, a.Z = x, y, z
+		for _, p := range players {
+			if p.EntityID != a.ShooterID && inPlayerHitbox(p.GetPosition(), x, y, z) {
+				return p, false
+			}
+		}
+	}
+	return nil, false
+}
+
+// arrowBlocked reports whether the point lies inside a block an arrow sticks
+// in: anything other than air and liquids. Arrows stop at unloaded chunks
+// rather than loading or generating them.
+func arrowBlocked(w *world.World, x, y, z float64) bool {
+	if y < 0 || y >= 256 {
+		return false
+	}
+	state, loaded := w.LoadedBlock(int(math.Floor(x)), int(math.Floor(y)), int(math.Floor(z)))
+	if !loaded {
+		return true
+	}
+	switch state >> 4 {
+	case 0, 8, 9, 10, 11: // air, water, lava
+		return false
+	}
+	return true
+}
+
+// inPlayerHitbox reports whether the point lies inside the hitbox of a
+// player standing at pos.
+func inPlayerHitbox(pos Position, x, y, z float64) bool {
+	return math.Abs(x-pos.X) <= playerHalfWidth && math.Abs(z-pos.Z) <= playerHalfWidth &&
+		y >= pos.Y && y <= pos.Y+playerHeight
+}
+
+// velocityPacket returns the EntityVelocity packet for the arrow's current
+// velocity.
+func (a *Arrow) velocityPacket() *pkt.EntityVelocity {
+	vx, vy, vz := protocolVelocity(a.VX, a.VY, a.VZ)
+	return &pkt.EntityVelocity{EntityID: a.EntityID, VelocityX: vx, VelocityY: vy, VelocityZ: vz}
+}
+
+// protocolVelocity converts a velocity in blocks per tick to protocol units
+// (1/8000 block per tick), clamped to what the client accepts.
+func protocolVelocity(vx, vy, vz float64) (int16, int16, int16) {
+	const maxVelocity = 3.9
+	conv := func(v float64) int16 {
+		return int16(max(min(v, maxVelocity), -maxVelocity) * 8000)
+	}
+	return conv(vx), conv(vy), conv(vz)
+}
+
+// buildSpawnArrowData encodes the SpawnEntity (0x0E) data for an arrow. The
+// object data is the shooter's entity ID, followed by the velocity.
+func buildSpawnArrowData(a *Arrow, yaw, pitch float32) []byte {
+	var buf bytes.Buffer
+
+	_, _ = mcnet.WriteVarInt(&buf, a.EntityID)
+	_ = binary.Write(&buf, binary.BigEndian, int8(objectArrow))
+	_ = binary.Write(&buf, binary.BigEndian, FixedPoint(a.X))
+	_ = binary.Write(&buf, binary.BigEndian, FixedPoint(a.Y))
+	_ = binary.Write(&buf, binary.BigEndian, FixedPoint(a.Z))
+	_ = binary.Write(&buf, binary.BigEndian, DegreesToAngle(pitch))
+	_ = binary.Write(&buf, binary.BigEndian, DegreesToAngle(yaw))
+	_ = binary.Write(&buf, binary.BigEndian, a.ShooterID)
+	vx, vy, vz := protocolVelocity(a.VX, a.VY, a.VZ)
+	_ = binary.Write(&buf, binary.BigEndian, [3]int16{vx, vy, vz})
+
+	return buf.Bytes()
+}
@@ -0,0 +1,180 @@
+package player
+
+import (
+	"math"
+	"testing"
+
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	"github.com/go-theft-craft/server/pkg/world"
+)
+
+func TestArrowVelocity(t *testing.T) {
+	tests := []struct {
+		name       string
+		yaw, pitch float32
+		vx, vy, vz float64
+	}{
+		{"south", 0, 0, 0, 0, 1},
+		{"west", 90, 0, -1, 0, 0},
+		{"north", 180, 0, 0, 0, -1},
+		{"east", -90, 0, 1, 0, 0},
+		{"straight up", 0, -90, 0, 1, 0},
+		{"down", 0, 90, 0, -1, 0},
+		{"south, 45 up", 0, -45, 0, math.Sqrt2 / 2, math.Sqrt2 / 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vx, vy, vz := ArrowVelocity(tt.yaw, tt.pitch, 1)
+			if math.Abs(vx-tt.vx) > 1e-9 || math.Abs(vy-tt.vy) > 1e-9 || math.Abs(vz-tt.vz) > 1e-9 {
+				t.Errorf("velocity = (%.3f, %.3f, %.3f), want (%.3f, %.3f, %.3f)", vx, vy, vz, tt.vx, tt.vy, tt.vz)
+			}
+		})
+	}
+
+	vx, vy, vz := ArrowVelocity(30, -20, ArrowSpeed)
+	if speed := math.Sqrt(vx*vx + vy*vy + vz*vz); math.Abs(speed-ArrowSpeed) > 1e-9 {
+		t.Errorf("speed = %v, want %v", speed, ArrowSpeed)
+	}
+}
+
+func TestShootArrow_SendsSpawnAndVelocity(t *testing.T) {
+	m := NewManager(8)
+	shooter, pc := newTestPlayer(m, 0.5, 0.5)
+	m.Add(shooter)
+	pc.reset()
+
+	a := m.ShootArrow(shooter, ArrowSpeed, nil)
+
+	spawns := pc.countByType(pkt.SpawnEntity{}.PacketID())
+	if spawns != 1 || pc.countByType(pkt.EntityVelocity{}.PacketID()) != 1 {
+		t.Fatalf("got %d spawns and %d velocities, want one of each", spawns, pc.countByType(pkt.EntityVelocity{}.PacketID()))
+	}
+	for _, p := range pc.get() {
+		if s, ok := p.(*pkt.SpawnEntity); ok && s.Data[1] != objectArrow {
+			t.Errorf("object type = %d, want arrow (%d)", s.Data[1], objectArrow)
+		}
+	}
+	if a.VZ != ArrowSpeed {
+		t.Errorf("arrow VZ = %v, want %v looking south", a.VZ, ArrowSpeed)
+	}
+}
+
+func TestTickArrows_StopsAtBlock(t *testing.T) {
+	m := NewManager(8)
+	shooter, pc := newTestPlayer(m, 0.5, 0.5)
+	m.Add(shooter)
+	w := plainsWorld()
+	for y := 5; y < 10; y++ {
+		w.SetBlock(0, y, 7, grassState)
+	}
+
+	a := m.ShootArrow(shooter, ArrowSpeed, nil)
+	pc.reset()
+	for range 5 {
+		m.TickArrows(w)
+	}
+
+	if !a.Stuck {
+		t.Fatal("arrow should be stuck in the wall")
+	}
+	if a.Z < 6.75 || a.Z >= 7 {
+		t.Errorf("arrow stopped at Z = %v, want just in front of the wall at 7", a.Z)
+	}
+	if a.VX != 0 || a.VY != 0 || a.VZ != 0 {
+		t.Errorf("stuck arrow velocity = (%v, %v, %v), want zero", a.VX, a.VY, a.VZ)
+	}
+	if pc.countByType(pkt.EntityTeleport{}.PacketID()) != 1 {
+		t.Error("expected the stuck arrow's position to be sent once")
+	}
+
+	z := a.Z
+	m.TickArrows(w)
+	if a.Z != z {
+		t.Error("a stuck arrow moved")
+	}
+}
+
+func TestTickArrows_HitsPlayer(t *testing.T) {
+	m := NewManager(8)
+	shooter, _ := newTestPlayer(m, 0.5, 0.5)
+	target, pc := newTestPlayer(m, 0.5, 6.5)
+	m.Add(shooter)
+	m.Add(target)
+
+	w := plainsWorld()
+	w.GetOrGenerateChunk(0, 0)
+
+	var hit *Player
+	var damage float32
+	a := m.ShootArrow(shooter, ArrowSpeed, func(p *Player, d float32) { hit, damage = p, d })
+	pc.reset()
+	for range 3 {
+		m.TickArrows(w)
+	}
+
+	if hit != target {
+		t.Fatal("arrow should hit the target")
+	}
+	if damage != 6 {
+		t.Errorf("damage = %v, want 6 from a full-speed arrow", damage)
+	}
+	if _, ok := m.arrows[a.EntityID]; ok {
+		t.Error("an arrow that hit a player should be removed")
+	}
+	if pc.countByType(pkt.EntityDestroy{}.PacketID()) != 1 {
+		t.Error("expected the arrow to be destroyed for clients")
+	}
+}
+
+func TestTickArrows_RemovedAfterLifetime(t *testing.T) {
+	m := NewManager(8)
+	shooter, _ := newTestPlayer(m, 0.5, 0.5)
+	shooter.SetPosition(0.5, 4, 0.5, 0, 90, true) // looking down
+	a := m.ShootArrow(shooter, ArrowSpeed, nil)
+
+	w := world.NewWorld(singleBiomeGenerator{biome: 1})
+	for range arrowLifetimeTicks + 1 {
+		m.TickArrows(w)
+	}
+	if _, ok := m.arrows[a.EntityID]; ok {
+		t.Error("arrow should be removed after its lifetime")
+	}
+}
+
+func TestTickArrows_StopsAtUnloadedChunk(t *testing.T) {
+	m := NewManager(8)
+	shooter, _ := newTestPlayer(m, 0.5, 0.5)
+	a := m.ShootArrow(shooter, ArrowSpeed, nil)
+
+	w := plainsWorld()
+	m.TickArrows(w)
+	if !a.Stuck {
+		t.Error("an arrow entering an unloaded chunk should stop")
+	}
+	if got := len(w.LoadedChunkPositions()); got != 0 {
+		t.Errorf("arrow flight loaded %d chunks, want none", got)
+	}
+}
+
+func TestShootArrow_CapsArrowsPerShooter(t *testing.T) {
+	m := NewManager(8)
+	shooter, pc := newTestPlayer(m, 0.5, 0.5)
+	m.Add(shooter)
+
+	first := m.ShootArrow(shooter, ArrowSpeed, nil)
+	for range maxArrowsPerShooter - 1 {
+		m.ShootArrow(shooter, ArrowSpeed, nil)
+	}
+	pc.reset()
+	m.ShootArrow(shooter, ArrowSpeed, nil)
+
+	if len(m.arrows) != maxArrowsPerShooter {
+		t.Errorf("%d arrows in the world, want %d", len(m.arrows), maxArrowsPerShooter)
+	}
+	if _, ok := m.arrows[first.EntityID]; ok {
+		t.Error("the oldest arrow should be removed")
+	}
+	if pc.countByType(pkt.EntityDestroy{}.PacketID()) != 1 {
+		t.Error("expected the oldest arrow to be destroyed for clients")
+	}
+}
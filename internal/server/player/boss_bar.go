@@ -0,0 +1,122 @@
+package player
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+)
+
+// The 1.8 client has no boss bar packet: it draws the bar for a boss mob it
+// renders. A boss bar is therefore an invisible wither that exists only on
+// one client, kept in front of the player, whose health fills the bar.
+const (
+	witherTypeID    = 64
+	witherMaxHealth = 300
+
+	// bossBarDistance is how far ahead of the player the wither is kept,
+	// close enough to be rendered but out of reach.
+	bossBarDistance = 32
+)
+
+// BossBar is a boss health bar shown to a single player.
+type BossBar struct {
+	EntityID int32   // client-side wither entity
+	Title    string  // shown above the bar
+	Percent  float32 // bar fill, 0-1
+}
+
+// BossBar returns the player's boss bar, if one is shown.
+func (p *Player) BossBar() (BossBar, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.bossBar == nil {
+		return BossBar{}, false
+	}
+	return *p.bossBar, true
+}
+
+// SetBossBar records the boss bar shown to the player.
+func (p *Player) SetBossBar(bar BossBar) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bossBar = &bar
+}
+
+// ClearBossBar forgets the player's boss bar.
+func (p *Player) ClearBossBar() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bossBar = nil
+}
+
+// bossBarPosition returns where the wither is kept for a player at pos.
+func bossBarPosition(pos Position) (x, y, z float64) {
+	yaw := float64(pos.Yaw) * math.Pi / 180
+	pitch := float64(pos.Pitch) * math.Pi / 180
+	x = pos.X - math.Sin(yaw)*math.Cos(pitch)*bossBarDistance
+	y = pos.Y - math.Sin(pitch)*bossBarDistance
+	z = pos.Z + math.Cos(yaw)*math.Cos(pitch)*bossBarDistance
+	return x, y, z
+}
+
+// SpawnPacket returns the SpawnEntityLiving packet creating the bar's wither
+// in front of a player at pos.
+func (b BossBar) SpawnPacket(pos Position) *pkt.SpawnEntityLiving {
+	x, y, z := bossBarPosition(pos)
+
+	var buf bytes.Buffer
+	_, _ = mcnet.WriteVarInt(&buf, b.EntityID)
+	buf.WriteByte(witherTypeID)
+	_ = binary.Write(&buf, binary.BigEndian, FixedPoint(x))
+	_ = binary.Write(&buf, binary.BigEndian, FixedPoint(y))
+	_ = binary.Write(&buf, binary.BigEndian, FixedPoint(z))
+	_ = binary.Write(&buf, binary.BigEndian, int8(0))  // yaw
+	_ = binary.Write(&buf, binary.BigEndian, int8(0))  // pitch
+	_ = binary.Write(&buf, binary.BigEndian, int8(0))  // head pitch
+	_ = binary.Write(&buf, binary.BigEndian, int16(0)) // velocity X
+	_ = binary.Write(&buf, binary.BigEndian, int16(0)) // velocity Y
+	_ = binary.Write(&buf, binary.BigEndian, int16(0)) // velocity Z
+	buf.Write(b.metadata())
+
+	return &pkt.SpawnEntityLiving{Data: buf.Bytes()}
+}
+
+// MetadataPacket returns the EntityMetadata packet updating the bar's title
+// and fill.
+func (b BossBar) MetadataPacket() *pkt.EntityMetadata {
+	return &pkt.EntityMetadata{Data: buildEntityMetadataData(b.EntityID, b.metadata())}
+}
+
+// TeleportPacket returns the EntityTeleport packet moving the wither in
+// front of a player at pos.
+func (b BossBar) TeleportPacket(pos Position) *pkt.EntityTeleport {
+	x, y, z := bossBarPosition(pos)
+	return &pkt.EntityTeleport{
+		EntityID: b.EntityID,
+		X:        FixedPoint(x),
+		Y:        FixedPoint(y),
+		Z:        FixedPoint(z),
+	}
+}
+
+// DestroyPacket returns the EntityDestroy packet removing the wither.
+func (b BossBar) DestroyPacket() *pkt.EntityDestroy {
+	return &pkt.EntityDestroy{EntityIDs: []int32{b.EntityID}}
+}
+
+// metadata builds the wither's metadata: invisible (index 0), the title as
+// its custom name (index 2) and the fill as its health (index 6). Health
+// never drops to zero, which would play the death animation.
+func (b BossBar) metadata() []byte {
+	health := max(min(b.Percent, 1)*witherMaxHealth, 1)
+
+	var buf bytes.Buffer
+	writeMetaByte(&buf, 0, 0x20) // invisible
+	writeMetaString(&buf, 2, b.Title)
+	writeMetaFloat(&buf, 6, health)
+	buf.WriteByte(pkt.MetadataEnd)
+	return buf.Bytes()
+}
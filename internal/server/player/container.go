@@ -0,0 +1,177 @@
+package player
+
+import (
+	"sync"
+
+	"github.com/go-theft-craft/server/pkg/world"
+)
+
+// Container is the inventory of a block such as a chest. Every player who
+// opens the block shares the same Container, and a change made by one of
+// them is pushed to the others.
+type Container struct {
+	mu      sync.Mutex
+	slots   []Slot
+	viewers map[int32]containerViewer // by entity ID
+
+	// removed is set once the block is broken. A removed container is
+	// empty and ignores changes, so players who still have it open cannot
+	// take items that were already dropped.
+	removed bool
+}
+
+// containerViewer is a player who has a container open.
+type containerViewer struct {
+	onChange func(index int, item Slot)
+	onRemove func()
+}
+
+func newContainer(size int) *Container {
+	ct := &Container{
+		slots:   make([]Slot, size),
+		viewers: make(map[int32]containerViewer),
+	}
+	for i := range ct.slots {
+		ct.slots[i] = EmptySlot
+	}
+	return ct
+}
+
+// Size returns the number of slots.
+func (ct *Container) Size() int {
+	return len(ct.slots)
+}
+
+// Get returns the item in slot index.
+func (ct *Container) Get(index int) Slot {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	return ct.slots[index]
+}
+
+// Set stores item in slot index and notifies every viewer except the
+// player with entity ID from. It does nothing once the container is removed.
+func (ct *Container) Set(index int, item Slot, from int32) {
+	ct.mu.Lock()
+	if ct.removed {
+		ct.mu.Unlock()
+		return
+	}
+	ct.slots[index] = item
+	var notify []func(int, Slot)
+	for id, v := range ct.viewers {
+		if id != from {
+			notify = append(notify, v.onChange)
+		}
+	}
+	ct.mu.Unlock()
+
+	for _, fn := range notify {
+		fn(index, item)
+	}
+}
+
+// Slots returns a copy of the contents.
+func (ct *Container) Slots() []Slot {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	return append([]Slot(nil), ct.slots...)
+}
+
+// AddViewer registers onChange to be called with slot changes made by other
+// players while the player with the given entity ID has the container open,
+// and onRemove to be called if the container's block is broken meanwhile.
+func (ct *Container) AddViewer(entityID int32, onChange func(index int, item Slot), onRemove func()) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	ct.viewers[entityID] = containerViewer{onChange: onChange, onRemove: onRemove}
+}
+
+// RemoveViewer unregisters a player added with AddViewer.
+func (ct *Container) RemoveViewer(entityID int32) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	delete(ct.viewers, entityID)
+}
+
+// Removed reports whether the container's block has been broken.
+func (ct *Container) Removed() bool {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	return ct.removed
+}
+
+// remove empties the container, marks it removed and tells every viewer to
+// close it. It returns the items the container held.
+func (ct *Container) remove() []Slot {
+	ct.mu.Lock()
+	var items []Slot
+	for i, s := range ct.slots {
+		if !s.IsEmpty() {
+			items = append(items, s)
+		}
+		ct.slots[i] = EmptySlot
+	}
+	ct.removed = true
+	viewers := ct.viewers
+	ct.viewers = make(map[int32]containerViewer)
+	ct.mu.Unlock()
+
+	for _, v := range viewers {
+		if v.onRemove != nil {
+			v.onRemove()
+		}
+	}
+	return items
+}
+
+// Container returns the container of the block at pos, creating an empty one
+// with size slots if the block has none yet.
+func (m *Manager) Container(pos world.BlockPos, size int) *Container {
+	m.containerMu.Lock()
+	defer m.containerMu.Unlock()
+	ct, ok := m.containers[pos]
+	if !ok {
+		ct = newContainer(size)
+		m.containers[pos] = ct
+	}
+	return ct
+}
+
+// RemoveContainer deletes the container of the block at pos, for when the
+// block is broken, closes it for every player who has it open and returns
+// the items it held.
+func (m *Manager) RemoveContainer(pos world.BlockPos) []Slot {
+	m.containerMu.Lock()
+	ct, ok := m.containers[pos]
+	delete(m.containers, pos)
+	m.containerMu.Unlock()
+	if !ok {
+		return nil
+	}
+	return ct.remove()
+}
+
+// ContainerContents returns a snapshot of every container, for saving.
+func (m *Manager) ContainerContents() map[world.BlockPos][]Slot {
+	m.containerMu.Lock()
+	defer m.containerMu.Unlock()
+	contents := make(map[world.BlockPos][]Slot, len(m.containers))
+	for pos, ct := range m.containers {
+		contents[pos] = ct.Slots()
+	}
+	return contents
+}
+
+// SetContainerContents replaces all containers with the given contents, as
+// loaded from disk.
+func (m *Manager) SetContainerContents(contents map[world.BlockPos][]Slot) {
+	m.containerMu.Lock()
+	defer m.containerMu.Unlock()
+	m.containers = make(map[world.BlockPos]*Container, len(contents))
+	for pos, slots := range contents {
+		ct := newContainer(len(slots))
+		copy(ct.slots, slots)
+		m.containers[pos] = ct
+	}
+}
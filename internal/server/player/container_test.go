@@ -0,0 +1,62 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/go-theft-craft/server/pkg/world"
+)
+
+func TestContainer_SharedBetweenViewers(t *testing.T) {
+	m := NewManager(8)
+	pos := world.BlockPos{X: 1, Y: 64, Z: 1}
+	ct := m.Container(pos, 27)
+	if m.Container(pos, 27) != ct {
+		t.Fatal("Container returned a different container for the same block")
+	}
+
+	var aliceSeen, bobSeen []int
+	ct.AddViewer(1, func(i int, _ Slot) { aliceSeen = append(aliceSeen, i) }, nil)
+	ct.AddViewer(2, func(i int, _ Slot) { bobSeen = append(bobSeen, i) }, nil)
+
+	ct.Set(3, Slot{BlockID: 1, ItemCount: 8}, 1)
+	if len(aliceSeen) != 0 || len(bobSeen) != 1 || bobSeen[0] != 3 {
+		t.Errorf("after Alice's change: Alice saw %v, Bob saw %v, want only Bob to see slot 3", aliceSeen, bobSeen)
+	}
+
+	ct.RemoveViewer(2)
+	ct.Set(4, Slot{BlockID: 1, ItemCount: 1}, 1)
+	if len(bobSeen) != 1 {
+		t.Errorf("Bob saw %v after closing, want no further updates", bobSeen)
+	}
+
+	items := m.RemoveContainer(pos)
+	if len(items) != 2 {
+		t.Errorf("RemoveContainer returned %d items, want 2", len(items))
+	}
+	if len(m.ContainerContents()) != 0 {
+		t.Error("container still present after RemoveContainer")
+	}
+}
+
+func TestRemoveContainer_ClosesViewersAndIgnoresChanges(t *testing.T) {
+	m := NewManager(8)
+	pos := world.BlockPos{X: 1, Y: 64, Z: 1}
+	ct := m.Container(pos, 27)
+	ct.Set(0, Slot{BlockID: 264, ItemCount: 5}, 0)
+	closed := false
+	ct.AddViewer(1, func(int, Slot) {}, func() { closed = true })
+
+	if items := m.RemoveContainer(pos); len(items) != 1 {
+		t.Fatalf("RemoveContainer returned %d items, want 1", len(items))
+	}
+	if !closed || !ct.Removed() {
+		t.Errorf("closed = %v, removed = %v, want the viewer closed and the container removed", closed, ct.Removed())
+	}
+	if got := ct.Get(0); !got.IsEmpty() {
+		t.Errorf("slot 0 after removal = %+v, want empty", got)
+	}
+	ct.Set(1, Slot{BlockID: 1, ItemCount: 1}, 1)
+	if got := ct.Get(1); !got.IsEmpty() {
+		t.Errorf("slot 1 set after removal = %+v, want empty", got)
+	}
+}
@@ -0,0 +1,39 @@
+package player
+
+// SetItemCooldown blocks use of itemID for the given number of ticks.
+// 1.8 clients have no cooldown overlay, so use attempts during the cooldown
+// are simply refused by the server.
+func (p *Player) SetItemCooldown(itemID int16, ticks int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if ticks <= 0 {
+		delete(p.cooldowns, itemID)
+		return
+	}
+	if p.cooldowns == nil {
+		p.cooldowns = make(map[int16]int)
+	}
+	p.cooldowns[itemID] = ticks
+}
+
+// OnCooldown reports whether itemID is still cooling down.
+func (p *Player) OnCooldown(itemID int16) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.cooldowns[itemID] > 0
+}
+
+// TickCooldowns advances all item cooldowns by one tick.
+func (p *Player) TickCooldowns() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for id, left := range p.cooldowns {
+		if left <= 1 {
+			delete(p.cooldowns, id)
+		} else {
+			p.cooldowns[id] = left - 1
+		}
+	}
+}
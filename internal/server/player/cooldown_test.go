@@ -0,0 +1,22 @@
+package player
+
+import "testing"
+
+func TestItemCooldown_Expires(t *testing.T) {
+	p, _ := newTestPlayer(NewManager(8), 0, 0)
+	p.SetItemCooldown(368, 3)
+
+	for i := 0; i < 2; i++ {
+		p.TickCooldowns()
+		if !p.OnCooldown(368) {
+			t.Fatalf("cooldown expired after %d ticks, want 3", i+1)
+		}
+	}
+	p.TickCooldowns()
+	if p.OnCooldown(368) {
+		t.Error("cooldown should have expired after 3 ticks")
+	}
+	if p.OnCooldown(332) {
+		t.Error("unrelated item reported on cooldown")
+	}
+}
@@ -0,0 +1,56 @@
+package player
+
+import pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+
+// Despawn policy kinds. A mob's gameData entity name (e.g. "pig") can also be
+// used as a kind and takes precedence over DespawnMob.
+const (
+	DespawnItem = "item"
+	DespawnMob  = "mob"
+)
+
+// mobExpiryTicks is the default lifetime of an unnamed mob in ticks (12000 ticks = 10 minutes at 20 TPS).
+const mobExpiryTicks int64 = 12000
+
+// despawnCheckInterval is how often, in ticks, expired entities are removed.
+const despawnCheckInterval = 600
+
+// SetDespawnSeconds overrides entity lifetimes, keyed by despawn kind. A
+// lifetime of 0 or less means entities of that kind never despawn. It must
+// be called before the manager starts ticking.
+func (m *Manager) SetDespawnSeconds(lifetimes map[string]int) {
+	for kind, secs := range lifetimes {
+		m.despawnTicks[kind] = int64(secs) * 20
+	}
+}
+
+// lifetime returns the despawn lifetime in ticks for the first kind with a
+// policy, or false if entities of that kind never despawn.
+func (m *Manager) lifetime(kinds ...string) (int64, bool) {
+	for _, kind := range kinds {
+		if t, ok := m.despawnTicks[kind]; ok {
+			return t, t > 0
+		}
+	}
+	return 0, false
+}
+
+// defaultDespawnTicks returns the built-in despawn policy.
+func defaultDespawnTicks() map[string]int64 {
+	return map[string]int64{
+		DespawnItem: itemExpiryTicks,
+		DespawnMob:  mobExpiryTicks,
+	}
+}
+
+// broadcastDestroy tells every player to remove the given entities.
+func (m *Manager) broadcastDestroy(ids []int32) {
+	if len(ids) == 0 {
+		return
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, pl := range m.players {
+		_ = pl.WritePacket(&pkt.EntityDestroy{EntityIDs: ids})
+	}
+}
@@ -0,0 +1,77 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/go-theft-craft/server/pkg/gamedata"
+)
+
+var testPig = gamedata.Entity{ID: 90, Name: "pig"}
+
+func TestMobDespawnsAfterLifetime(t *testing.T) {
+	m := NewManager(8)
+	m.SetDespawnSeconds(map[string]int{DespawnMob: 10})
+	me := m.SpawnMob(testPig, 0.5, 4, 0.5)
+
+	m.cleanupExpiredMobs(me.SpawnTick + 10*20)
+	if m.MobByEntityID(me.EntityID) == nil {
+		t.Fatal("mob despawned before its lifetime")
+	}
+
+	m.cleanupExpiredMobs(me.SpawnTick + 10*20 + 1)
+	if m.MobByEntityID(me.EntityID) != nil {
+		t.Error("mob should despawn after its lifetime")
+	}
+	if _, ok := m.EntityByUUID(me.UUID); ok {
+		t.Error("despawned mob should be removed from the entity map")
+	}
+}
+
+func TestNamedMobNeverDespawns(t *testing.T) {
+	m := NewManager(8)
+	m.SetDespawnSeconds(map[string]int{DespawnMob: 10})
+	me := m.SpawnMob(testPig, 0.5, 4, 0.5)
+
+	if !m.NameMob(me.EntityID, "Wilbur") {
+		t.Fatal("NameMob returned false for a live mob")
+	}
+	if !me.Persistent || me.CustomName != "Wilbur" {
+		t.Errorf("named mob = %q persistent %v, want %q persistent", me.CustomName, me.Persistent, "Wilbur")
+	}
+
+	for range 4 * despawnCheckInterval {
+		m.Tick()
+	}
+	if m.MobByEntityID(me.EntityID) == nil {
+		t.Error("name-tagged mob should never despawn")
+	}
+}
+
+func TestDespawnPolicyPerKind(t *testing.T) {
+	m := NewManager(8)
+	m.SetDespawnSeconds(map[string]int{DespawnMob: 10, "pig": 0})
+	pig := m.SpawnMob(testPig, 0.5, 4, 0.5)
+	cow := m.SpawnMob(gamedata.Entity{ID: 92, Name: "cow"}, 2.5, 4, 0.5)
+
+	m.cleanupExpiredMobs(pig.SpawnTick + mobExpiryTicks + 1)
+	if m.MobByEntityID(pig.EntityID) == nil {
+		t.Error("pig with lifetime 0 should never despawn")
+	}
+	if m.MobByEntityID(cow.EntityID) != nil {
+		t.Error("cow should fall back to the generic mob lifetime")
+	}
+}
+
+func TestItemDespawnConfigurable(t *testing.T) {
+	m := NewManager(8)
+	m.SetDespawnSeconds(map[string]int{DespawnItem: 0})
+	m.SpawnBlockDrop(Slot{BlockID: 1, ItemCount: 1}, 3.5, 5, 7.5, 5.5)
+
+	m.cleanupExpiredItems(itemExpiryTicks * 10)
+	m.itemMu.Lock()
+	n := len(m.itemEntities)
+	m.itemMu.Unlock()
+	if n != 1 {
+		t.Errorf("item entities = %d, want 1 with item despawn disabled", n)
+	}
+}
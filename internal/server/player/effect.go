@@ -0,0 +1,180 @@
+package player
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+)
+
+// Potion effect IDs whose gameplay the server applies.
+const (
+	EffectSpeed        int8 = 1
+	EffectRegeneration int8 = 10
+)
+
+// Movement speed attribute and the modifiers vanilla applies to it.
+const (
+	movementSpeedKey  = "generic.movementSpeed"
+	baseMovementSpeed = 0.10000000149011612
+
+	speedPerLevel  = 0.2 // Speed effect, per amplifier level
+	sprintModifier = 0.3
+
+	// modifierMultiplyTotal multiplies the attribute by (1 + amount).
+	modifierMultiplyTotal = 2
+)
+
+var (
+	speedModifierUUID  = [16]byte{0x91, 0xAE, 0xAA, 0x56, 0x37, 0x6B, 0x44, 0x98, 0x93, 0x5B, 0x2F, 0x7F, 0x68, 0x07, 0x06, 0x35}
+	sprintModifierUUID = [16]byte{0x66, 0x2A, 0x6B, 0x8D, 0xDA, 0x3E, 0x4C, 0x1C, 0x88, 0x13, 0x96, 0xEA, 0x60, 0x97, 0x27, 0x8D}
+)
+
+// ActiveEffect is a potion effect applied to a player.
+type ActiveEffect struct {
+	ID        int8
+	Amplifier int8  // level - 1
+	Ticks     int32 // remaining duration
+}
+
+// AddEffect applies an effect, replacing any active effect with the same ID.
+func (p *Player) AddEffect(e ActiveEffect) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.effects == nil {
+		p.effects = make(map[int8]ActiveEffect)
+	}
+	p.effects[e.ID] = e
+}
+
+// Effects returns the player's active effects ordered by ID.
+func (p *Player) Effects() []ActiveEffect {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	out := make([]ActiveEffect, 0, len(p.effects))
+	for _, e := range p.effects {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// ClearEffects removes every active effect and returns the removed IDs in
+// ascending order.
+func (p *Player) ClearEffects() []int8 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ids := make([]int8, 0, len(p.effects))
+	for id := range p.effects {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	p.effects = nil
+	return ids
+}
+
+// TickEffects advances active effects by one tick, applying regeneration.
+// It returns the IDs of effects that ran out and whether health changed.
+func (p *Player) TickEffects() (expired []int8, healed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for id, e := range p.effects {
+		if id == EffectRegeneration && p.health > 0 && p.health < MaxHealth {
+			// Vanilla heals a half-heart every 50 ticks, halved per level.
+			if interval := int32(50) >> e.Amplifier; interval <= 0 || e.Ticks%interval == 0 {
+				p.health = min(p.health+1, MaxHealth)
+				healed = true
+			}
+		}
+		e.Ticks--
+		if e.Ticks <= 0 {
+			delete(p.effects, id)
+			expired = append(expired, id)
+			continue
+		}
+		p.effects[id] = e
+	}
+	sort.Slice(expired, func(i, j int) bool { return expired[i] < expired[j] })
+	return expired, healed
+}
+
+// AttributesPacket returns the player's movement speed attribute, scaled by
+// their walk speed, with the speed effect and sprinting modifiers, so the
+// client moves at the right pace.
+func (p *Player) AttributesPacket() *pkt.UpdateAttributes {
+	p.mu.RLock()
+	type modifier struct {
+		uuid   [16]byte
+		amount float64
+	}
+	var mods []modifier
+	if e, ok := p.effects[EffectSpeed]; ok {
+		mods = append(mods, modifier{speedModifierUUID, speedPerLevel * float64(int(e.Amplifier)+1)})
+	}
+	if p.entityFlags&0x08 != 0 {
+		mods = append(mods, modifier{sprintModifierUUID, sprintModifier})
+	}
+	base := baseMovementSpeed * float64(p.walkSpeed)
+	p.mu.RUnlock()
+
+	var buf bytes.Buffer
+	_, _ = mcnet.WriteVarInt(&buf, p.EntityID)
+	_ = binary.Write(&buf, binary.BigEndian, int32(1)) // one attribute
+	_, _ = mcnet.WriteString(&buf, movementSpeedKey)
+	_ = binary.Write(&buf, binary.BigEndian, base)
+	_, _ = mcnet.WriteVarInt(&buf, int32(len(mods)))
+	for _, m := range mods {
+		buf.Write(m.uuid[:])
+		_ = binary.Write(&buf, binary.BigEndian, m.amount)
+		buf.WriteByte(modifierMultiplyTotal)
+	}
+	return &pkt.UpdateAttributes{Data: buf.Bytes()}
+}
+
+// ApplyEffect gives p an effect and shows it to p and the players tracking p.
+func (m *Manager) ApplyEffect(p *Player, e ActiveEffect) {
+	p.AddEffect(e)
+	effect := &pkt.EntityEffect{
+		EntityID:  p.EntityID,
+		EffectID:  e.ID,
+		Amplifier: e.Amplifier,
+		Duration:  e.Ticks,
+	}
+	_ = p.WritePacket(effect)
+	m.BroadcastToTrackers(effect, p.EntityID)
+	if e.ID == EffectSpeed {
+		_ = p.WritePacket(p.AttributesPacket())
+	}
+}
+
+// ClearEffects removes all of p's effects and tells p and its trackers.
+func (m *Manager) ClearEffects(p *Player) {
+	m.removeEffects(p, p.ClearEffects())
+}
+
+// removeEffects sends RemoveEntityEffect for each of p's removed effects.
+func (m *Manager) removeEffects(p *Player, ids []int8) {
+	for _, id := range ids {
+		remove := &pkt.RemoveEntityEffect{EntityID: p.EntityID, EffectID: id}
+		_ = p.WritePacket(remove)
+		m.BroadcastToTrackers(remove, p.EntityID)
+		if id == EffectSpeed {
+			_ = p.WritePacket(p.AttributesPacket())
+		}
+	}
+}
+
+// tickEffects advances every player's effects, removing expired ones and
+// sending health changed by regeneration.
+func (m *Manager) tickEffects() {
+	m.ForEach(func(p *Player) {
+		expired, healed := p.TickEffects()
+		m.removeEffects(p, expired)
+		if healed {
+			_ = p.WritePacket(p.HealthPacket())
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package player
+
+import (
+	"bytes"
+	"testing"
+
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+)
+
+func TestTickEffects_Expire(t *testing.T) {
+	p, _ := newTestPlayer(NewManager(8), 0, 0)
+	p.AddEffect(ActiveEffect{ID: EffectSpeed, Ticks: 3})
+
+	for i := 1; i < 3; i++ {
+		if expired, _ := p.TickEffects(); len(expired) != 0 {
+			t.Fatalf("tick %d: expired %v, want none", i, expired)
+		}
+	}
+	expired, _ := p.TickEffects()
+	if len(expired) != 1 || expired[0] != EffectSpeed {
+		t.Errorf("expired = %v, want [speed]", expired)
+	}
+	if n := len(p.Effects()); n != 0 {
+		t.Errorf("%d effects left, want 0", n)
+	}
+}
+
+func TestTickEffects_RegenerationHeals(t *testing.T) {
+	p, _ := newTestPlayer(NewManager(8), 0, 0)
+	p.SetHealth(10)
+	// Level II heals every 25 ticks.
+	p.AddEffect(ActiveEffect{ID: EffectRegeneration, Amplifier: 1, Ticks: 100})
+
+	heals := 0
+	for range 100 {
+		if _, healed := p.TickEffects(); healed {
+			heals++
+		}
+	}
+	if heals != 4 || p.GetHealth() != 14 {
+		t.Errorf("healed %d times to %v, want 4 times to 14", heals, p.GetHealth())
+	}
+}
+
+func TestAttributesPacket_SpeedModifier(t *testing.T) {
+	p, _ := newTestPlayer(NewManager(8), 0, 0)
+	if bytes.Contains(p.AttributesPacket().Data, speedModifierUUID[:]) {
+		t.Fatal("speed modifier sent without the effect")
+	}
+
+	p.AddEffect(ActiveEffect{ID: EffectSpeed, Ticks: 100})
+	if !bytes.Contains(p.AttributesPacket().Data, speedModifierUUID[:]) {
+		t.Error("speed modifier missing from attributes")
+	}
+}
+
+func TestManagerTick_RemovesExpiredEffect(t *testing.T) {
+	m := NewManager(8)
+	p, pc := newTestPlayer(m, 0, 0)
+	m.Add(p)
+	m.ApplyEffect(p, ActiveEffect{ID: EffectSpeed, Ticks: 2})
+	if n := pc.countByType((&pkt.EntityEffect{}).PacketID()); n != 1 {
+		t.Fatalf("sent %d EntityEffect packets, want 1", n)
+	}
+	pc.reset()
+
+	m.Tick()
+	m.Tick()
+
+	var removed []*pkt.RemoveEntityEffect
+	for _, pk := range pc.get() {
+		if r, ok := pk.(*pkt.RemoveEntityEffect); ok {
+			removed = append(removed, r)
+		}
+	}
+	if len(removed) != 1 || removed[0].EffectID != EffectSpeed || removed[0].EntityID != p.EntityID {
+		t.Errorf("RemoveEntityEffect = %+v, want one for speed", removed)
+	}
+}
@@ -0,0 +1,42 @@
+package player
+
+import "crypto/rand"
+
+// Entity is a non-player entity tracked in the manager's unified entity map,
+// so it can be looked up by UUID (e.g. as a spectate target).
+type Entity interface {
+	ID() int32
+	EntityUUID() [16]byte
+	EntityPosition() (x, y, z float64)
+}
+
+// RegisterEntity adds e to the unified entity map.
+func (m *Manager) RegisterEntity(e Entity) {
+	m.entityMu.Lock()
+	defer m.entityMu.Unlock()
+	m.entities[e.EntityUUID()] = e
+}
+
+// UnregisterEntity removes the entity with the given UUID from the unified entity map.
+func (m *Manager) UnregisterEntity(uuid [16]byte) {
+	m.entityMu.Lock()
+	defer m.entityMu.Unlock()
+	delete(m.entities, uuid)
+}
+
+// EntityByUUID looks up a non-player entity by UUID.
+func (m *Manager) EntityByUUID(uuid [16]byte) (Entity, bool) {
+	m.entityMu.RLock()
+	defer m.entityMu.RUnlock()
+	e, ok := m.entities[uuid]
+	return e, ok
+}
+
+// newEntityUUID returns a random version 4 UUID for a server-spawned entity.
+func newEntityUUID() [16]byte {
+	var uuid [16]byte
+	_, _ = rand.Read(uuid[:])
+	uuid[6] = uuid[6]&0x0F | 0x40
+	uuid[8] = uuid[8]&0x3F | 0x80
+	return uuid
+}
@@ -0,0 +1,31 @@
+package player
+
+import "testing"
+
+func TestItemEntityRegisteredByUUID(t *testing.T) {
+	m := NewManager(8)
+	m.SpawnBlockDrop(Slot{BlockID: 1, ItemCount: 1}, 3.5, 5, 7.5, 5.5)
+
+	var ie *ItemEntity
+	m.itemMu.Lock()
+	for _, e := range m.itemEntities {
+		ie = e
+	}
+	m.itemMu.Unlock()
+	if ie == nil {
+		t.Fatal("expected an item entity")
+	}
+
+	e, ok := m.EntityByUUID(ie.UUID)
+	if !ok || e.ID() != ie.EntityID {
+		t.Fatalf("EntityByUUID(%x) = %v, %v; want item %d", ie.UUID, e, ok, ie.EntityID)
+	}
+	if x, y, z := e.EntityPosition(); x != 3.5 || y != 5 || z != 7.5 {
+		t.Errorf("EntityPosition() = %v,%v,%v, want 3.5,5,7.5", x, y, z)
+	}
+
+	m.cleanupExpiredItems(ie.SpawnTick + itemExpiryTicks + 1)
+	if _, ok := m.EntityByUUID(ie.UUID); ok {
+		t.Error("expired item should be removed from the entity map")
+	}
+}
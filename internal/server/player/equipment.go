@@ -1,40 +1,29 @@
 package player
 
 import (
-	"bytes"
-	"encoding/binary"
-
-	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
 )
 
-// BuildEquipmentPackets builds 5 EntityEquipment (0x04) raw data payloads:
+// BuildEquipmentPackets builds 5 EntityEquipment (0x04) packets:
 // slot 0 = held item, slots 1-4 = armor (boots, leggings, chestplate, helmet).
-func BuildEquipmentPackets(entityID int32, inv *Inventory) [][]byte {
+func BuildEquipmentPackets(entityID int32, inv *Inventory) []*pkt.EntityEquipment {
 	inv.mu.RLock()
 	defer inv.mu.RUnlock()
 
-	packets := make([][]byte, 5)
+	packets := make([]*pkt.EntityEquipment, 5)
 
 	// Slot 0: held item
-	packets[0] = buildEquipmentData(entityID, 0, inv.Slots[inv.HeldSlot])
+	packets[0] = BuildSingleEquipment(entityID, 0, inv.Slots[inv.HeldSlot])
 
 	// Slots 1-4: armor (boots=1, leggings=2, chestplate=3, helmet=4)
 	for i := 0; i < 4; i++ {
-		packets[i+1] = buildEquipmentData(entityID, int16(i+1), inv.Armor[i])
+		packets[i+1] = BuildSingleEquipment(entityID, int16(i+1), inv.Armor[i])
 	}
 
 	return packets
 }
 
-// BuildSingleEquipment builds a single EntityEquipment raw data payload.
-func BuildSingleEquipment(entityID int32, equipSlot int16, slot Slot) []byte {
-	return buildEquipmentData(entityID, equipSlot, slot)
-}
-
-func buildEquipmentData(entityID int32, equipSlot int16, slot Slot) []byte {
-	var buf bytes.Buffer
-	_, _ = mcnet.WriteVarInt(&buf, entityID)
-	_ = binary.Write(&buf, binary.BigEndian, equipSlot)
-	_ = WriteSlot(&buf, slot)
-	return buf.Bytes()
+// BuildSingleEquipment builds a single EntityEquipment packet.
+func BuildSingleEquipment(entityID int32, equipSlot int16, slot Slot) *pkt.EntityEquipment {
+	return &pkt.EntityEquipment{EntityID: entityID, Slot: equipSlot, Item: slot}
 }
@@ -0,0 +1,95 @@
+package player
+
+import (
+	"math"
+
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+)
+
+// maxXPLevel is the highest experience level: the last one whose total
+// experience still fits in an int32.
+const maxXPLevel = 21863
+
+// xpToNextLevel returns the experience points needed to advance from level
+// to level+1 (MC 1.8 curve).
+func xpToNextLevel(level int32) int32 {
+	switch {
+	case level >= 30:
+		return 112 + (level-30)*9
+	case level >= 15:
+		return 37 + (level-15)*5
+	default:
+		return 7 + level*2
+	}
+}
+
+// Experience returns the player's level, the points gathered toward the
+// next level and the total points collected.
+func (p *Player) Experience() (level, points, total int32) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.xpLevel, p.xpPoints, p.xpTotal
+}
+
+// SetExperience replaces the player's experience, as restored from saved
+// data. Points beyond the current level are carried into further levels.
+func (p *Player) SetExperience(level, points, total int32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.xpLevel = min(max(level, 0), maxXPLevel)
+	p.xpPoints = 0
+	p.xpTotal = max(total, 0)
+	p.addPoints(max(points, 0))
+}
+
+// AddExperience adds experience points, levelling up as the bar fills.
+// Negative amounts are ignored.
+func (p *Player) AddExperience(points int32) {
+	if points <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.xpTotal = int32(min(int64(p.xpTotal)+int64(points), math.MaxInt32))
+	p.addPoints(points)
+}
+
+// addPoints fills the bar with points, levelling up on overflow. At
+// maxXPLevel the bar stops just short of full. Caller must hold p.mu.
+func (p *Player) addPoints(points int32) {
+	bar := int64(p.xpPoints) + int64(points)
+	for p.xpLevel < maxXPLevel && bar >= int64(xpToNextLevel(p.xpLevel)) {
+		bar -= int64(xpToNextLevel(p.xpLevel))
+		p.xpLevel++
+	}
+	p.xpPoints = int32(min(bar, int64(xpToNextLevel(p.xpLevel))-1))
+}
+
+// AddExperienceLevels adds (or, if negative, removes) whole levels, up to
+// maxXPLevel. The bar keeps its fill fraction; dropping below level 0 resets
+// all experience.
+func (p *Player) AddExperienceLevels(levels int32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	from := int64(xpToNextLevel(p.xpLevel))
+	level := int64(p.xpLevel) + int64(levels)
+	if level < 0 {
+		p.xpLevel, p.xpPoints, p.xpTotal = 0, 0, 0
+		return
+	}
+	p.xpLevel = int32(min(level, maxXPLevel))
+	p.xpPoints = int32(int64(p.xpPoints) * int64(xpToNextLevel(p.xpLevel)) / from)
+}
+
+// ExperiencePacket returns an Experience packet with the player's current
+// bar fill, level and total experience.
+func (p *Player) ExperiencePacket() *pkt.Experience {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return &pkt.Experience{
+		ExperienceBar:   float32(p.xpPoints) / float32(xpToNextLevel(p.xpLevel)),
+		Level:           p.xpLevel,
+		TotalExperience: p.xpTotal,
+	}
+}
@@ -0,0 +1,40 @@
+package player
+
+import pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+
+// experienceOrbSizes are the values vanilla splits experience into when
+// dropping orbs, largest first.
+var experienceOrbSizes = []int32{2477, 1237, 617, 307, 149, 73, 37, 17, 7, 3, 1}
+
+// SplitExperience splits an amount of experience into orb values the way
+// vanilla does: each orb takes the largest size that still fits.
+func SplitExperience(amount int32) []int16 {
+	var orbs []int16
+	for amount > 0 {
+		for _, size := range experienceOrbSizes {
+			if size <= amount {
+				orbs = append(orbs, int16(size))
+				amount -= size
+				break
+			}
+		}
+	}
+	return orbs
+}
+
+// SpawnExperienceOrbs broadcasts experience orbs worth amount in total at
+// (x, y, z) and returns how many were spawned. Orbs are not tracked by the
+// server, so they cannot be picked up yet.
+func (m *Manager) SpawnExperienceOrbs(x, y, z float64, amount int32) int {
+	orbs := SplitExperience(amount)
+	for _, count := range orbs {
+		m.Broadcast(&pkt.SpawnEntityExperienceOrb{
+			EntityID: m.AllocateEntityID(),
+			X:        FixedPoint(x),
+			Y:        FixedPoint(y),
+			Z:        FixedPoint(z),
+			Count:    count,
+		})
+	}
+	return len(orbs)
+}
@@ -0,0 +1,24 @@
+package player
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitExperience(t *testing.T) {
+	tests := []struct {
+		amount int32
+		want   []int16
+	}{
+		{0, nil},
+		{1, []int16{1}},
+		{10, []int16{7, 3}},
+		{20, []int16{17, 3}},
+		{2500, []int16{2477, 17, 3, 3}},
+	}
+	for _, tt := range tests {
+		if got := SplitExperience(tt.amount); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitExperience(%d) = %v, want %v", tt.amount, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package player
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAddExperience_LevelCurve(t *testing.T) {
+	tests := []struct {
+		points     int32
+		wantLevel  int32
+		wantPoints int32
+	}{
+		{6, 0, 6},
+		{7, 1, 0},
+		{16, 2, 0},
+		{352, 16, 0},  // end of the 2l+7 segment
+		{1507, 31, 0}, // end of the 5l-38 segment
+		{1628, 32, 0}, // 1507 + 121
+		{1630, 32, 2},
+	}
+	for _, tt := range tests {
+		p := NewPlayer(1, "uuid", [16]byte{}, "Alice", nil, nil)
+		p.AddExperience(tt.points)
+		level, points, total := p.Experience()
+		if level != tt.wantLevel || points != tt.wantPoints || total != tt.points {
+			t.Errorf("AddExperience(%d) = level %d, %d points, total %d; want level %d, %d points, total %d",
+				tt.points, level, points, total, tt.wantLevel, tt.wantPoints, tt.points)
+		}
+	}
+}
+
+func TestAddExperienceLevels(t *testing.T) {
+	p := NewPlayer(1, "uuid", [16]byte{}, "Alice", nil, nil)
+	p.AddExperience(13) // level 1, 6 of 9 points
+
+	p.AddExperienceLevels(29) // level 30 needs 112 points, so 6/9 of that
+	level, points, _ := p.Experience()
+	if level != 30 || points != 74 {
+		t.Errorf("after +29 levels: level %d, %d points; want level 30, 74 points", level, points)
+	}
+
+	p.AddExperienceLevels(-31)
+	if level, points, total := p.Experience(); level != 0 || points != 0 || total != 0 {
+		t.Errorf("after dropping below 0: level %d, %d points, total %d; want all zero", level, points, total)
+	}
+}
+
+func TestExperiencePacket(t *testing.T) {
+	p := NewPlayer(1, "uuid", [16]byte{}, "Alice", nil, nil)
+	p.SetExperience(2, 5, 100)
+
+	got := p.ExperiencePacket()
+	if got.Level != 2 || got.TotalExperience != 100 || got.ExperienceBar != 5.0/11 {
+		t.Errorf("ExperiencePacket() = %+v, want level 2, total 100, bar 5/11", got)
+	}
+}
+
+func TestAddExperienceLevels_CapsLevel(t *testing.T) {
+	p := NewPlayer(1, "uuid", [16]byte{}, "Alice", nil, nil)
+	p.AddExperienceLevels(300000000)
+	p.AddExperience(1)
+
+	level, points, _ := p.Experience()
+	if level != maxXPLevel || points != 1 {
+		t.Errorf("after +300000000 levels and 1 point: level %d, %d points; want level %d, 1 point", level, points, maxXPLevel)
+	}
+	if bar := p.ExperiencePacket().ExperienceBar; bar < 0 || bar >= 1 {
+		t.Errorf("experience bar = %v, want within [0, 1)", bar)
+	}
+
+	p.AddExperience(1 << 30)
+	p.AddExperience(1 << 30)
+	level, points, total := p.Experience()
+	if level != maxXPLevel || points != 196608 || total != math.MaxInt32 {
+		t.Errorf("after 2^31 points: level %d, %d points, total %d; want level %d, 196608 points, total %d",
+			level, points, total, maxXPLevel, math.MaxInt32)
+	}
+}
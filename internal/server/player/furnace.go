@@ -0,0 +1,363 @@
+package player
+
+import "github.com/go-theft-craft/server/pkg/world"
+
+// Furnace slot indices.
+const (
+	FurnaceInput  = 0
+	FurnaceFuel   = 1
+	FurnaceResult = 2
+	furnaceSize   = 3
+)
+
+// Furnace window properties sent with CraftProgressBar.
+const (
+	FurnacePropFuel        = 0 // burn ticks left
+	FurnacePropFuelMax     = 1 // burn ticks of the current fuel item
+	FurnacePropProgress    = 2 // cook ticks of the current item
+	FurnacePropProgressMax = 3 // cook ticks needed per item
+	furnacePropCount       = 4
+)
+
+// smeltTicks is how long a furnace takes to smelt one item.
+const smeltTicks = 200
+
+const (
+	itemBucket     = 325
+	itemLavaBucket = 327
+)
+
+// smeltKey identifies a smelting input by item ID and damage.
+type smeltKey struct {
+	id     int16
+	damage int16
+}
+
+// smeltingRecipes maps smeltable items to their result.
+var smeltingRecipes = map[smeltKey]Slot{
+	{4, 0}:   {BlockID: 1, ItemCount: 1},                  // cobblestone → stone
+	{12, 0}:  {BlockID: 20, ItemCount: 1},                 // sand → glass
+	{12, 1}:  {BlockID: 20, ItemCount: 1},                 // red sand → glass
+	{14, 0}:  {BlockID: 266, ItemCount: 1},                // gold ore → gold ingot
+	{15, 0}:  {BlockID: 265, ItemCount: 1},                // iron ore → iron ingot
+	{16, 0}:  {BlockID: 263, ItemCount: 1},                // coal ore → coal
+	{17, 0}:  {BlockID: 263, ItemCount: 1, ItemDamage: 1}, // log → charcoal
+	{17, 1}:  {BlockID: 263, ItemCount: 1, ItemDamage: 1},
+	{17, 2}:  {BlockID: 263, ItemCount: 1, ItemDamage: 1},
+	{17, 3}:  {BlockID: 263, ItemCount: 1, ItemDamage: 1},
+	{162, 0}: {BlockID: 263, ItemCount: 1, ItemDamage: 1},
+	{162, 1}: {BlockID: 263, ItemCount: 1, ItemDamage: 1},
+	{21, 0}:  {BlockID: 351, ItemCount: 1, ItemDamage: 4}, // lapis ore → lapis lazuli
+	{56, 0}:  {BlockID: 264, ItemCount: 1},                // diamond ore → diamond
+	{73, 0}:  {BlockID: 331, ItemCount: 1},                // redstone ore → redstone
+	{81, 0}:  {BlockID: 351, ItemCount: 1, ItemDamage: 2}, // cactus → cactus green
+	{82, 0}:  {BlockID: 172, ItemCount: 1},                // clay → hardened clay
+	{87, 0}:  {BlockID: 405, ItemCount: 1},                // netherrack → nether brick
+	{129, 0}: {BlockID: 388, ItemCount: 1},                // emerald ore → emerald
+	{153, 0}: {BlockID: 406, ItemCount: 1},                // quartz ore → quartz
+	{319, 0}: {BlockID: 320, ItemCount: 1},                // porkchop
+	{337, 0}: {BlockID: 336, ItemCount: 1},                // clay ball → brick
+	{349, 0}: {BlockID: 350, ItemCount: 1},                // fish
+	{349, 1}: {BlockID: 350, ItemCount: 1, ItemDamage: 1}, // salmon
+	{363, 0}: {BlockID: 364, ItemCount: 1},                // beef
+	{365, 0}: {BlockID: 366, ItemCount: 1},                // chicken
+	{392, 0}: {BlockID: 393, ItemCount: 1},                // potato
+	{411, 0}: {BlockID: 412, ItemCount: 1},                // rabbit
+	{423, 0}: {BlockID: 424, ItemCount: 1},                // mutton
+}
+
+// fuelTicks maps fuel items to how many ticks one of them burns.
+var fuelTicks = map[int16]int{
+	5:   300,   // planks
+	6:   100,   // sapling
+	17:  300,   // log
+	162: 300,   // log2
+	173: 16000, // coal block
+	263: 1600,  // coal and charcoal
+	268: 200,   // wooden sword
+	269: 200,   // wooden shovel
+	270: 200,   // wooden pickaxe
+	271: 200,   // wooden axe
+	280: 100,   // stick
+	290: 200,   // wooden hoe
+	327: 20000, // lava bucket
+	369: 2400,  // blaze rod
+}
+
+// SmeltingResult returns what item smelts into, or false if it cannot be
+// smelted.
+func SmeltingResult(item Slot) (Slot, bool) {
+	result, ok := smeltingRecipes[smeltKey{item.BlockID, item.ItemDamage}]
+	return result, ok
+}
+
+// FuelTicks returns how many ticks one of the item burns in a furnace, or 0
+// if it is not a fuel.
+func FuelTicks(blockID int16) int {
+	return fuelTicks[blockID]
+}
+
+// Furnace is a furnace's inventory and smelting state. It keeps smelting
+// while no one has it open.
+type Furnace struct {
+	*Container
+
+	// Guarded by Container.mu.
+	burnTime    int // ticks left on the current fuel item
+	burnMax     int // burn ticks of the current fuel item
+	cookTime    int // ticks spent smelting the current input
+	sent        [furnacePropCount]int16
+	propViewers map[int32]func(property, value int16)
+}
+
+func newFurnace() *Furnace {
+	return &Furnace{
+		Container:   newContainer(furnaceSize),
+		propViewers: make(map[int32]func(int16, int16)),
+	}
+}
+
+// Properties returns the current window property values, indexed by the
+// FurnaceProp constants.
+func (f *Furnace) Properties() [furnacePropCount]int16 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.properties()
+}
+
+func (f *Furnace) properties() [furnacePropCount]int16 {
+	return [furnacePropCount]int16{
+		FurnacePropFuel:        int16(f.burnTime),
+		FurnacePropFuelMax:     int16(f.burnMax),
+		FurnacePropProgress:    int16(f.cookTime),
+		FurnacePropProgressMax: smeltTicks,
+	}
+}
+
+// AddPropertyViewer registers fn to be called with property changes while
+// the player with the given entity ID has the furnace open.
+func (f *Furnace) AddPropertyViewer(entityID int32, fn func(property, value int16)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.propViewers[entityID] = fn
+}
+
+// RemovePropertyViewer unregisters a player added with AddPropertyViewer.
+func (f *Furnace) RemovePropertyViewer(entityID int32) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.propViewers, entityID)
+}
+
+// Tick advances the furnace by one tick: it burns fuel, smelts the input
+// into the result slot and notifies viewers of what changed.
+func (f *Furnace) Tick() {
+	f.mu.Lock()
+	if f.removed {
+		f.mu.Unlock()
+		return
+	}
+	changed := f.tick()
+
+	type propChange struct{ property, value int16 }
+	var props []propChange
+	for i, v := range f.properties() {
+		if v != f.sent[i] {
+			f.sent[i] = v
+			props = append(props, propChange{int16(i), v})
+		}
+	}
+
+	var slotFns []func(int, Slot)
+	for _, v := range f.viewers {
+		slotFns = append(slotFns, v.onChange)
+	}
+	var propFns []func(int16, int16)
+	for _, fn := range f.propViewers {
+		propFns = append(propFns, fn)
+	}
+	items := make([]Slot, len(changed))
+	for i, idx := range changed {
+		items[i] = f.slots[idx]
+	}
+	f.mu.Unlock()
+
+	for i, idx := range changed {
+		for _, fn := range slotFns {
+			fn(idx, items[i])
+		}
+	}
+	for _, p := range props {
+		for _, fn := range propFns {
+			fn(p.property, p.value)
+		}
+	}
+}
+
+// tick runs the smelting logic and returns the slots it changed. The caller
+// holds mu.
+func (f *Furnace) tick() []int {
+	var changed []int
+	if f.burnTime > 0 {
+		f.burnTime--
+	}
+
+	input, fuel := f.slots[FurnaceInput], f.slots[FurnaceFuel]
+	if f.burnTime == 0 && (fuel.IsEmpty() || input.IsEmpty()) {
+		// Out of fuel: progress cools off.
+		f.cookTime = max(f.cookTime-2, 0)
+		return nil
+	}
+
+	if f.burnTime == 0 && f.canSmelt() {
+		if ticks := FuelTicks(fuel.BlockID); ticks > 0 {
+			f.burnTime, f.burnMax = ticks, ticks
+			f.slots[FurnaceFuel] = consumeFuel(fuel)
+			changed = append(changed, FurnaceFuel)
+		}
+	}
+
+	if f.burnTime > 0 && f.canSmelt() {
+		f.cookTime++
+		if f.cookTime == smeltTicks {
+			f.cookTime = 0
+			f.smelt()
+			changed = append(changed, FurnaceInput, FurnaceResult)
+		}
+	} else {
+		f.cookTime = 0
+	}
+	return changed
+}
+
+// canSmelt reports whether the input has a result that fits into the result
+// slot. The caller holds mu.
+func (f *Furnace) canSmelt() bool {
+	input := f.slots[FurnaceInput]
+	if input.IsEmpty() {
+		return false
+	}
+	result, ok := SmeltingResult(input)
+	if !ok {
+		return false
+	}
+	out := f.slots[FurnaceResult]
+	if out.IsEmpty() {
+		return true
+	}
+	return out.BlockID == result.BlockID && out.ItemDamage == result.ItemDamage &&
+		int(out.ItemCount)+int(result.ItemCount) <= 64
+}
+
+// smelt turns one input item into its result. The caller holds mu and has
+// checked canSmelt.
+func (f *Furnace) smelt() {
+	input := f.slots[FurnaceInput]
+	result, _ := SmeltingResult(input)
+
+	if out := f.slots[FurnaceResult]; out.IsEmpty() {
+		f.slots[FurnaceResult] = result
+	} else {
+		out.ItemCount += result.ItemCount
+		f.slots[FurnaceResult] = out
+	}
+
+	input.ItemCount--
+	if input.ItemCount <= 0 {
+		input = EmptySlot
+	}
+	f.slots[FurnaceInput] = input
+}
+
+// consumeFuel returns the fuel slot after burning one item from it. A lava
+// bucket leaves an empty bucket behind.
+func consumeFuel(fuel Slot) Slot {
+	if fuel.BlockID == itemLavaBucket {
+		return Slot{BlockID: itemBucket, ItemCount: 1}
+	}
+	fuel.ItemCount--
+	if fuel.ItemCount <= 0 {
+		return EmptySlot
+	}
+	return fuel
+}
+
+// Furnace returns the furnace at pos, creating an empty one if the block has
+// none yet.
+func (m *Manager) Furnace(pos world.BlockPos) *Furnace {
+	m.containerMu.Lock()
+	defer m.containerMu.Unlock()
+	f, ok := m.furnaces[pos]
+	if !ok {
+		f = newFurnace()
+		m.furnaces[pos] = f
+	}
+	return f
+}
+
+// RemoveFurnace deletes the furnace at pos, for when the block is broken,
+// closes it for every player who has it open and returns the items it held.
+func (m *Manager) RemoveFurnace(pos world.BlockPos) []Slot {
+	m.containerMu.Lock()
+	f, ok := m.furnaces[pos]
+	delete(m.furnaces, pos)
+	m.containerMu.Unlock()
+	if !ok {
+		return nil
+	}
+	return f.remove()
+}
+
+// tickFurnaces advances every furnace by one tick.
+func (m *Manager) tickFurnaces() {
+	m.containerMu.Lock()
+	furnaces := make([]*Furnace, 0, len(m.furnaces))
+	for _, f := range m.furnaces {
+		furnaces = append(furnaces, f)
+	}
+	m.containerMu.Unlock()
+
+	for _, f := range furnaces {
+		f.Tick()
+	}
+}
+
+// FurnaceState is a snapshot of a furnace, for saving.
+type FurnaceState struct {
+	Slots    []Slot
+	BurnTime int
+	BurnMax  int
+	CookTime int
+}
+
+// FurnaceContents returns a snapshot of every furnace, for saving.
+func (m *Manager) FurnaceContents() map[world.BlockPos]FurnaceState {
+	m.containerMu.Lock()
+	defer m.containerMu.Unlock()
+	contents := make(map[world.BlockPos]FurnaceState, len(m.furnaces))
+	for pos, f := range m.furnaces {
+		f.mu.Lock()
+		contents[pos] = FurnaceState{
+			Slots:    append([]Slot(nil), f.slots...),
+			BurnTime: f.burnTime,
+			BurnMax:  f.burnMax,
+			CookTime: f.cookTime,
+		}
+		f.mu.Unlock()
+	}
+	return contents
+}
+
+// SetFurnaceContents replaces all furnaces with the given states, as loaded
+// from disk.
+func (m *Manager) SetFurnaceContents(contents map[world.BlockPos]FurnaceState) {
+	m.containerMu.Lock()
+	defer m.containerMu.Unlock()
+	m.furnaces = make(map[world.BlockPos]*Furnace, len(contents))
+	for pos, st := range contents {
+		f := newFurnace()
+		copy(f.slots, st.Slots)
+		f.burnTime, f.burnMax, f.cookTime = st.BurnTime, st.BurnMax, st.CookTime
+		m.furnaces[pos] = f
+	}
+}
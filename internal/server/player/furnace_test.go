@@ -0,0 +1,81 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/go-theft-craft/server/pkg/world"
+)
+
+func TestFurnace_SmeltsIronOreWithCoal(t *testing.T) {
+	f := newFurnace()
+	f.Set(FurnaceInput, Slot{BlockID: 15, ItemCount: 2}, 0)
+	f.Set(FurnaceFuel, Slot{BlockID: 263, ItemCount: 1}, 0)
+
+	for range smeltTicks - 1 {
+		f.Tick()
+	}
+	if got := f.Get(FurnaceResult); !got.IsEmpty() {
+		t.Fatalf("result after %d ticks = %+v, want empty", smeltTicks-1, got)
+	}
+	if got := f.Get(FurnaceFuel); !got.IsEmpty() {
+		t.Errorf("fuel slot = %+v, want the coal consumed", got)
+	}
+
+	f.Tick()
+	if got := f.Get(FurnaceResult); got != (Slot{BlockID: 265, ItemCount: 1}) {
+		t.Fatalf("result after %d ticks = %+v, want 1 iron ingot", smeltTicks, got)
+	}
+	if got := f.Get(FurnaceInput); got.ItemCount != 1 {
+		t.Errorf("input count = %d, want 1", got.ItemCount)
+	}
+
+	for range smeltTicks {
+		f.Tick()
+	}
+	if got := f.Get(FurnaceResult); got != (Slot{BlockID: 265, ItemCount: 2}) {
+		t.Errorf("result after %d ticks = %+v, want 2 iron ingots", 2*smeltTicks, got)
+	}
+	// The coal was lit on the first tick and has burned on every tick since.
+	if p := f.Properties(); p[FurnacePropFuel] != 1201 || p[FurnacePropFuelMax] != 1600 {
+		t.Errorf("fuel properties = %d/%d, want 1201/1600", p[FurnacePropFuel], p[FurnacePropFuelMax])
+	}
+}
+
+func TestFurnace_NoFuelNoProgress(t *testing.T) {
+	f := newFurnace()
+	f.Set(FurnaceInput, Slot{BlockID: 15, ItemCount: 1}, 0)
+	f.Set(FurnaceFuel, Slot{BlockID: 1, ItemCount: 1}, 0) // stone does not burn
+
+	for range smeltTicks {
+		f.Tick()
+	}
+	if got := f.Get(FurnaceResult); !got.IsEmpty() {
+		t.Errorf("result = %+v, want nothing smelted without fuel", got)
+	}
+	if got := f.Properties()[FurnacePropProgress]; got != 0 {
+		t.Errorf("progress = %d, want 0", got)
+	}
+}
+
+func TestFurnace_SendsPropertyUpdates(t *testing.T) {
+	m := NewManager(8)
+	f := m.Furnace(world.BlockPos{X: 0, Y: 64, Z: 0})
+	f.Set(FurnaceInput, Slot{BlockID: 4, ItemCount: 1}, 0)
+	f.Set(FurnaceFuel, Slot{BlockID: 280, ItemCount: 1}, 0)
+
+	got := map[int16]int16{}
+	f.AddPropertyViewer(1, func(property, value int16) { got[property] = value })
+	m.Tick()
+
+	want := map[int16]int16{
+		FurnacePropFuel:        100,
+		FurnacePropFuelMax:     100,
+		FurnacePropProgress:    1,
+		FurnacePropProgressMax: smeltTicks,
+	}
+	for p, v := range want {
+		if got[p] != v {
+			t.Errorf("property %d = %d, want %d", p, got[p], v)
+		}
+	}
+}
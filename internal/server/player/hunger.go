@@ -0,0 +1,84 @@
+package player
+
+import (
+	"github.com/go-theft-craft/server/internal/server/packet"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+)
+
+// Food values and natural regeneration rules (MC 1.8).
+const (
+	MaxFood           = 20
+	DefaultSaturation = 5
+
+	regenFoodLevel     = 18  // minimum food level for natural regeneration
+	regenIntervalTicks = 80  // ticks per regenerated half-heart (4 seconds)
+	regenExhaustion    = 3.0 // exhaustion added per regenerated half-heart
+	exhaustionPerPoint = 4.0 // exhaustion that costs one saturation or food point
+)
+
+// GetFood returns the player's food level and saturation.
+func (p *Player) GetFood() (food int32, saturation float32) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.food, p.saturation
+}
+
+// SetFood sets the player's food level, clamped to [0, MaxFood], and
+// saturation, which never exceeds the food level.
+func (p *Player) SetFood(food int32, saturation float32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.food = min(max(food, 0), MaxFood)
+	p.saturation = min(max(saturation, 0), float32(p.food))
+	p.exhaustion = 0
+}
+
+// HealthPacket returns an UpdateHealth packet with the player's current
+// health, food and saturation.
+func (p *Player) HealthPacket() *pkt.UpdateHealth {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return &pkt.UpdateHealth{
+		Health:         p.health,
+		Food:           p.food,
+		FoodSaturation: p.saturation,
+	}
+}
+
+// TickRegen advances natural regeneration by one tick. A survival or
+// adventure player who is alive, hurt and well fed heals half a heart every
+// regenIntervalTicks, at the cost of exhaustion. It reports whether health
+// changed.
+func (p *Player) TickRegen() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.gameMode == packet.GameModeCreative || p.gameMode == packet.GameModeSpectator ||
+		p.health <= 0 || p.health >= MaxHealth || p.food < regenFoodLevel {
+		p.regenTimer = 0
+		return false
+	}
+
+	p.regenTimer++
+	if p.regenTimer < regenIntervalTicks {
+		return false
+	}
+	p.regenTimer = 0
+	p.health = min(p.health+1, MaxHealth)
+	p.exhaust(regenExhaustion)
+	return true
+}
+
+// exhaust adds exhaustion, draining saturation and then food for every
+// exhaustionPerPoint accumulated. Caller must hold p.mu.
+func (p *Player) exhaust(amount float32) {
+	p.exhaustion += amount
+	for p.exhaustion >= exhaustionPerPoint {
+		p.exhaustion -= exhaustionPerPoint
+		if p.saturation > 0 {
+			p.saturation = max(p.saturation-1, 0)
+		} else if p.food > 0 {
+			p.food--
+		}
+	}
+}
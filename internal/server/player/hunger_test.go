@@ -0,0 +1,86 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/go-theft-craft/server/internal/server/packet"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+)
+
+func TestTickRegen_HealsWhenFed(t *testing.T) {
+	m := NewManager(8)
+	p, pc := newTestPlayer(m, 0, 0)
+	other, otherPC := newTestPlayer(m, 0, 0)
+	m.Add(p)
+	m.Add(other)
+	p.SetHealth(10)
+
+	for i := 0; i < regenIntervalTicks*2; i++ {
+		m.Tick()
+	}
+
+	if got := p.GetHealth(); got != 12 {
+		t.Errorf("health = %v after %d ticks, want 12", got, regenIntervalTicks*2)
+	}
+	var updates []*pkt.UpdateHealth
+	for _, sent := range pc.get() {
+		if u, ok := sent.(*pkt.UpdateHealth); ok {
+			updates = append(updates, u)
+		}
+	}
+	if len(updates) != 2 || updates[1].Health != 12 {
+		t.Errorf("UpdateHealth packets = %+v, want two ending at 12", updates)
+	}
+	for _, sent := range otherPC.get() {
+		if _, ok := sent.(*pkt.UpdateHealth); ok {
+			t.Error("UpdateHealth was sent to another player")
+		}
+	}
+	if other.GetHealth() != MaxHealth {
+		t.Errorf("other player health = %v, want %v", other.GetHealth(), MaxHealth)
+	}
+}
+
+func TestTickRegen_ConsumesSaturationThenFood(t *testing.T) {
+	p, _ := newTestPlayer(NewManager(8), 0, 0)
+	p.SetHealth(1)
+	p.SetFood(MaxFood, 1)
+
+	for i := 0; i < regenIntervalTicks*4; i++ {
+		p.TickRegen()
+	}
+
+	// Four regenerations add 12 exhaustion: three points, one from
+	// saturation and two from food.
+	food, saturation := p.GetFood()
+	if food != MaxFood-2 || saturation != 0 {
+		t.Errorf("food = %d, saturation = %v, want %d and 0", food, saturation, MaxFood-2)
+	}
+}
+
+func TestTickRegen_Skipped(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(p *Player)
+	}{
+		{"creative", func(p *Player) { p.SetGameMode(packet.GameModeCreative) }},
+		{"spectator", func(p *Player) { p.SetGameMode(packet.GameModeSpectator) }},
+		{"dead", func(p *Player) { p.SetHealth(0) }},
+		{"hungry", func(p *Player) { p.SetFood(regenFoodLevel-1, 0) }},
+	}
+	for _, tt := range tests {
+		p, _ := newTestPlayer(NewManager(8), 0, 0)
+		p.SetHealth(10)
+		tt.setup(p)
+		before := p.GetHealth()
+
+		for i := 0; i < regenIntervalTicks*2; i++ {
+			if p.TickRegen() {
+				t.Fatalf("%s: TickRegen reported a heal", tt.name)
+			}
+		}
+		if got := p.GetHealth(); got != before {
+			t.Errorf("%s: health = %v, want %v", tt.name, got, before)
+		}
+	}
+}
@@ -1,25 +1,23 @@
 package player
 
 import (
-	"encoding/binary"
 	"io"
 	"sync"
+
+	"github.com/go-theft-craft/server/pkg/item"
 )
 
 // Slot represents a Minecraft inventory slot.
-type Slot struct {
-	BlockID    int16 // -1 = empty
-	ItemCount  int8
-	ItemDamage int16
-}
+type Slot = item.Slot
 
-// EmptySlot is a convenience value for an empty slot.
-var EmptySlot = Slot{BlockID: -1}
+// ItemNBT is the optional tag data carried by a slot.
+type ItemNBT = item.NBT
 
-// IsEmpty returns true if the slot contains no item.
-func (s Slot) IsEmpty() bool {
-	return s.BlockID == -1
-}
+// Enchantment is a single enchantment on an item.
+type Enchantment = item.Enchantment
+
+// EmptySlot is a convenience value for an empty slot.
+var EmptySlot = item.EmptySlot
 
 // Inventory holds a player's hotbar, main inventory, and armor.
 type Inventory struct {
@@ -96,6 +94,38 @@ func (inv *Inventory) RemoveOne(index int) Slot {
 	return removed
 }
 
+// RemoveItem removes up to limit items with the given ID from the hotbar,
+// main inventory and armor, in that order, and returns how many were
+// removed. A negative blockID matches any item and a negative limit removes
+// every match.
+func (inv *Inventory) RemoveItem(blockID int16, limit int) int {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	removed := 0
+	take := func(s *Slot) {
+		if s.IsEmpty() || (blockID >= 0 && s.BlockID != blockID) {
+			return
+		}
+		n := int(s.ItemCount)
+		if limit >= 0 {
+			n = min(n, limit-removed)
+		}
+		removed += n
+		s.ItemCount -= int8(n)
+		if s.ItemCount <= 0 {
+			*s = EmptySlot
+		}
+	}
+	for i := range inv.Slots {
+		take(&inv.Slots[i])
+	}
+	for i := range inv.Armor {
+		take(&inv.Armor[i])
+	}
+	return removed
+}
+
 // ReadSlots calls fn with copies of the current slots and armor under a read lock.
 func (inv *Inventory) ReadSlots(fn func(slots [36]Slot, armor [4]Slot)) {
 	inv.mu.RLock()
@@ -237,6 +267,12 @@ func (inv *Inventory) GetArmor(index int) Slot {
 // stacks first, then placing in empty slots. Scans hotbar (0-8) then main (9-35).
 // Returns the leftover that didn't fit (or EmptySlot if fully absorbed).
 func (inv *Inventory) AddItem(item Slot) Slot {
+	return inv.AddItemStack(item, 64)
+}
+
+// AddItemStack is like AddItem but caps every stack at maxStack items, for
+// items such as tools or ender pearls that stack below 64.
+func (inv *Inventory) AddItemStack(item Slot, maxStack int) Slot {
 	if item.IsEmpty() {
 		return EmptySlot
 	}
@@ -249,10 +285,10 @@ func (inv *Inventory) AddItem(item Slot) Slot {
 	// First pass: merge into existing stacks in hotbar, then main.
 	for _, i := range inv.addItemOrder() {
 		s := inv.Slots[i]
-		if s.IsEmpty() || s.BlockID != item.BlockID || s.ItemDamage != item.ItemDamage {
+		if s.IsEmpty() || s.BlockID != item.BlockID || s.ItemDamage != item.ItemDamage || !s.NBT.Equal(item.NBT) {
 			continue
 		}
-		space := 64 - int(s.ItemCount)
+		space := maxStack - int(s.ItemCount)
 		if space <= 0 {
 			continue
 		}
@@ -273,10 +309,11 @@ func (inv *Inventory) AddItem(item Slot) Slot {
 			continue
 		}
 		place := remaining
-		if place > 64 {
-			place = 64
+		if place > maxStack {
+			place = maxStack
 		}
-		inv.Slots[i] = Slot{BlockID: item.BlockID, ItemCount: int8(place), ItemDamage: item.ItemDamage}
+		inv.Slots[i] = item
+		inv.Slots[i].ItemCount = int8(place)
 		remaining -= place
 		if remaining == 0 {
 			return EmptySlot
@@ -303,19 +340,10 @@ func (inv *Inventory) addItemOrder() []int {
 
 // WriteSlot writes a slot in the Minecraft protocol format.
 func WriteSlot(w io.Writer, s Slot) error {
-	if err := binary.Write(w, binary.BigEndian, s.BlockID); err != nil {
-		return err
-	}
-	if s.BlockID == -1 {
-		return nil
-	}
-	if err := binary.Write(w, binary.BigEndian, s.ItemCount); err != nil {
-		return err
-	}
-	if err := binary.Write(w, binary.BigEndian, s.ItemDamage); err != nil {
-		return err
-	}
-	// No NBT data
-	_, err := w.Write([]byte{0x00})
-	return err
+	return item.WriteSlot(w, s)
+}
+
+// SetItemNBTEnabled controls whether WriteSlot includes item NBT.
+func SetItemNBTEnabled(enabled bool) {
+	item.SetNBTEnabled(enabled)
 }
@@ -151,3 +151,22 @@ func TestWriteSlotWithItem(t *testing.T) {
 		t.Errorf("expected NBT tag 0x00, got %02X", data[5])
 	}
 }
+
+func TestAddItem_KeepsNBTStacksApart(t *testing.T) {
+	inv := NewInventory()
+	for i := range inv.Slots {
+		inv.SetSlot(i, EmptySlot)
+	}
+	named := &ItemNBT{DisplayName: "Lucky"}
+	inv.SetSlot(0, Slot{BlockID: 1, ItemCount: 10})
+
+	if left := inv.AddItem(Slot{BlockID: 1, ItemCount: 5, NBT: named}); !left.IsEmpty() {
+		t.Fatalf("leftover = %+v, want none", left)
+	}
+	if got := inv.GetSlot(0); got.ItemCount != 10 {
+		t.Errorf("slot 0 has %d items, want the named stone kept out of it", got.ItemCount)
+	}
+	if got := inv.GetSlot(1); got.ItemCount != 5 || !got.NBT.Equal(named) {
+		t.Errorf("slot 1 = %+v, want 5 stone named Lucky", got)
+	}
+}
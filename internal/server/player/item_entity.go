@@ -12,12 +12,22 @@ import (
 // ItemEntity represents a dropped item in the world.
 type ItemEntity struct {
 	EntityID         int32
+	UUID             [16]byte
 	Item             Slot
 	X, Y, Z          float64
 	VelX, VelY, VelZ int16
 	SpawnTick        int64
 }
 
+// ID returns the item's entity ID.
+func (ie *ItemEntity) ID() int32 { return ie.EntityID }
+
+// EntityUUID returns the item's UUID.
+func (ie *ItemEntity) EntityUUID() [16]byte { return ie.UUID }
+
+// EntityPosition returns the item's resting position.
+func (ie *ItemEntity) EntityPosition() (x, y, z float64) { return ie.X, ie.Y, ie.Z }
+
 // SpawnItemEntity creates and broadcasts a dropped item entity.
 // groundAt returns the ground-level Y below a given block position (x, y, z),
 // used to estimate where the item will land for pickup distance checks.
@@ -37,6 +47,7 @@ func (m *Manager) SpawnItemEntity(dropperEID int32, item Slot, x, y, z float64,
 
 	ie := &ItemEntity{
 		EntityID:  entityID,
+		UUID:      newEntityUUID(),
 		Item:      item,
 		X:         landX,
 		Y:         landY,
@@ -50,6 +61,7 @@ func (m *Manager) SpawnItemEntity(dropperEID int32, item Slot, x, y, z float64,
 	m.itemMu.Lock()
 	m.itemEntities[entityID] = ie
 	m.itemMu.Unlock()
+	m.RegisterEntity(ie)
 
 	// Build SpawnEntity using the original throw position so the client
 	// animates the arc from the player's hand. The stored X/Y/Z (landing)
@@ -66,35 +78,35 @@ func (m *Manager) SpawnItemEntity(dropperEID int32, item Slot, x, y, z float64,
 	}
 }
 
-// cleanupExpiredItems removes item entities older than 5 minutes (6000 ticks).
+// cleanupExpiredItems removes item entities older than the item despawn
+// lifetime (5 minutes by default).
 func (m *Manager) cleanupExpiredItems(currentTick int64) {
+	lifetime, ok := m.lifetime(DespawnItem)
+	if !ok {
+		return
+	}
+
 	m.itemMu.Lock()
 	var expired []int32
 	for id, ie := range m.itemEntities {
-		if currentTick-ie.SpawnTick > itemExpiryTicks {
+		if currentTick-ie.SpawnTick > lifetime {
 			expired = append(expired, id)
 		}
 	}
 	for _, id := range expired {
+		m.UnregisterEntity(m.itemEntities[id].UUID)
 		delete(m.itemEntities, id)
 	}
 	m.itemMu.Unlock()
 
-	if len(expired) > 0 {
-		destroyData := buildDestroyEntities(expired)
-		m.mu.RLock()
-		for _, pl := range m.players {
-			_ = pl.WritePacket(&pkt.EntityDestroy{Data: destroyData})
-		}
-		m.mu.RUnlock()
-	}
+	m.broadcastDestroy(expired)
 }
 
 const (
 	// pickupDelayTicks is the minimum ticks after spawn before an item can be picked up (10 ticks = 500ms at 20 TPS).
 	pickupDelayTicks int64 = 10
 
-	// itemExpiryTicks is the lifetime of a dropped item in ticks (6000 ticks = 5 minutes at 20 TPS).
+	// itemExpiryTicks is the default lifetime of a dropped item in ticks (6000 ticks = 5 minutes at 20 TPS).
 	itemExpiryTicks int64 = 6000
 
 	// pickupRadius is the distance (in blocks) within which a player can pick up items.
@@ -105,8 +117,9 @@ const (
 
 // TryPickupItems checks for item entities near the player, attempts to add them
 // to the player's inventory, and broadcasts collect/destroy packets.
-// Returns the number of items collected.
-func (m *Manager) TryPickupItems(p *Player) int {
+// stackSize reports the per-slot limit for an item ID. Returns the number of
+// items collected.
+func (m *Manager) TryPickupItems(p *Player, stackSize func(blockID int16) int) int {
 	pos := p.GetPosition()
 	collected := 0
 
@@ -127,7 +140,7 @@ func (m *Manager) TryPickupItems(p *Player) int {
 			continue
 		}
 
-		leftover := p.Inventory.AddItem(ie.Item)
+		leftover := p.Inventory.AddItemStack(ie.Item, stackSize(ie.Item.BlockID))
 		if leftover.IsEmpty() {
 			// Fully absorbed.
 			toRemove = append(toRemove, id)
@@ -148,6 +161,7 @@ func (m *Manager) TryPickupItems(p *Player) int {
 	}
 
 	for _, id := range toRemove {
+		m.UnregisterEntity(m.itemEntities[id].UUID)
 		delete(m.itemEntities, id)
 	}
 	m.itemMu.Unlock()
@@ -170,9 +184,8 @@ func (m *Manager) TryPickupItems(p *Player) int {
 	}
 
 	if len(toRemove) > 0 {
-		destroyData := buildDestroyEntities(toRemove)
 		for _, pl := range m.players {
-			_ = pl.WritePacket(&pkt.EntityDestroy{Data: destroyData})
+			_ = pl.WritePacket(&pkt.EntityDestroy{EntityIDs: toRemove})
 		}
 	}
 
@@ -192,6 +205,7 @@ func (m *Manager) SpawnBlockDrop(item Slot, x, y, z, spawnY float64) {
 
 	ie := &ItemEntity{
 		EntityID:  entityID,
+		UUID:      newEntityUUID(),
 		Item:      item,
 		X:         x,
 		Y:         y,
@@ -205,6 +219,7 @@ func (m *Manager) SpawnBlockDrop(item Slot, x, y, z, spawnY float64) {
 	m.itemMu.Lock()
 	m.itemEntities[entityID] = ie
 	m.itemMu.Unlock()
+	m.RegisterEntity(ie)
 
 	// Visual spawn at block height; stored X/Y/Z at ground level for pickup.
 	spawnData := buildSpawnEntityDataAt(ie, x, spawnY, z)
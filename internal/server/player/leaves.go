@@ -0,0 +1,42 @@
+package player
+
+import "math/rand"
+
+// Block and item IDs involved in leaf drops.
+const (
+	blockLeaves  = 18
+	blockLeaves2 = 161 // acacia and dark oak leaves, wood types 4 and 5
+	itemSapling  = 6   // damage value is the wood type
+	itemApple    = 260
+)
+
+// Leaf drop odds, as one in n per leaf block removed.
+const (
+	saplingChance = 20
+	appleChance   = 200
+)
+
+// IsLeaves reports whether a block state is a leaf block.
+func IsLeaves(state int32) bool {
+	id := state >> 4
+	return id == blockLeaves || id == blockLeaves2
+}
+
+// LeafDrops rolls the drops of a leaf block that was broken by hand or
+// decayed: a sapling of its wood type one time in saplingChance and, for oak
+// leaves only, an apple one time in appleChance.
+func LeafDrops(state int32, rng *rand.Rand) []Slot {
+	wood := int16(state & 0x3)
+	if state>>4 == blockLeaves2 {
+		wood += 4
+	}
+
+	var drops []Slot
+	if rng.Intn(saplingChance) == 0 {
+		drops = append(drops, Slot{BlockID: itemSapling, ItemCount: 1, ItemDamage: wood})
+	}
+	if wood == 0 && rng.Intn(appleChance) == 0 {
+		drops = append(drops, Slot{BlockID: itemApple, ItemCount: 1})
+	}
+	return drops
+}
@@ -0,0 +1,67 @@
+package player
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestLeafDrops_Odds(t *testing.T) {
+	const rolls = 20000
+	rng := rand.New(rand.NewSource(1))
+
+	count := func(state int32) (saplings, apples int) {
+		for i := 0; i < rolls; i++ {
+			for _, d := range LeafDrops(state, rng) {
+				switch d.BlockID {
+				case itemSapling:
+					saplings++
+				case itemApple:
+					apples++
+				}
+			}
+		}
+		return saplings, apples
+	}
+
+	saplings, apples := count(blockLeaves << 4) // oak
+	if saplings < rolls/saplingChance/2 || saplings > rolls/saplingChance*2 {
+		t.Errorf("oak leaves dropped %d saplings in %d rolls, want about %d", saplings, rolls, rolls/saplingChance)
+	}
+	if apples < rolls/appleChance/2 || apples > rolls/appleChance*2 {
+		t.Errorf("oak leaves dropped %d apples in %d rolls, want about %d", apples, rolls, rolls/appleChance)
+	}
+
+	for _, state := range []int32{blockLeaves<<4 | 1, blockLeaves<<4 | 2, blockLeaves2 << 4} {
+		if _, apples := count(state); apples != 0 {
+			t.Errorf("state %d dropped %d apples, want none from non-oak leaves", state, apples)
+		}
+	}
+}
+
+func TestLeafDrops_SaplingWoodType(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tests := []struct {
+		state int32
+		want  int16
+	}{
+		{blockLeaves<<4 | 1, 1},     // spruce
+		{blockLeaves<<4 | 3 | 8, 3}, // jungle, check-decay bit set
+		{blockLeaves2<<4 | 1, 5},    // dark oak
+	}
+	for _, tt := range tests {
+		found := false
+		for i := 0; i < 1000 && !found; i++ {
+			for _, d := range LeafDrops(tt.state, rng) {
+				if d.BlockID == itemSapling {
+					if d.ItemDamage != tt.want {
+						t.Fatalf("state %d: sapling damage = %d, want %d", tt.state, d.ItemDamage, tt.want)
+					}
+					found = true
+				}
+			}
+		}
+		if !found {
+			t.Errorf("state %d: no sapling dropped in 1000 rolls", tt.state)
+		}
+	}
+}
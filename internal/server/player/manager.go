@@ -9,6 +9,7 @@ import (
 
 	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
 	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+	"github.com/go-theft-craft/server/pkg/world"
 )
 
 // Manager tracks all connected players and handles entity visibility.
@@ -22,6 +23,23 @@ type Manager struct {
 
 	itemMu       sync.Mutex
 	itemEntities map[int32]*ItemEntity
+
+	mobMu sync.Mutex
+	mobs  map[int32]*MobEntity
+
+	arrowMu sync.Mutex
+	arrows  map[int32]*Arrow
+
+	entityMu sync.RWMutex
+	entities map[[16]byte]Entity // non-player entities by UUID
+
+	despawnTicks map[string]int64 // entity lifetime by despawn kind
+
+	containerMu sync.Mutex
+	containers  map[world.BlockPos]*Container // block inventories such as chests
+	furnaces    map[world.BlockPos]*Furnace
+
+	scoreboard *Scoreboard
 }
 
 // NewManager creates a new player manager with the given view distance (in chunks).
@@ -31,7 +49,14 @@ func NewManager(viewDistance int) *Manager {
 		byUUID:       make(map[string]int32),
 		viewDistance: viewDistance,
 		itemEntities: make(map[int32]*ItemEntity),
+		mobs:         make(map[int32]*MobEntity),
+		arrows:       make(map[int32]*Arrow),
+		entities:     make(map[[16]byte]Entity),
+		despawnTicks: defaultDespawnTicks(),
+		containers:   make(map[world.BlockPos]*Container),
+		furnaces:     make(map[world.BlockPos]*Furnace),
 	}
+	mgr.scoreboard = newScoreboard(mgr.Broadcast)
 	return mgr
 }
 
@@ -44,9 +69,24 @@ func (m *Manager) AllocateEntityID() int32 {
 func (m *Manager) Tick() {
 	tick := m.currentTick.Add(1)
 
-	// Run item expiry cleanup every 600 ticks (~30 seconds).
-	if tick%600 == 0 {
+	m.ForEach((*Player).TickCooldowns)
+	m.ForEach((*Player).TickInvulnerability)
+	m.ForEach(func(p *Player) {
+		if p.TickRegen() {
+			_ = p.WritePacket(p.HealthPacket())
+		}
+	})
+	m.tickEffects()
+	m.tickFurnaces()
+
+	// Run item and mob expiry cleanup every 600 ticks (~30 seconds).
+	if tick%despawnCheckInterval == 0 {
 		m.cleanupExpiredItems(tick)
+		m.cleanupExpiredMobs(tick)
+	}
+
+	if tick%distantMobCheckInterval == 0 {
+		m.despawnDistantMobs()
 	}
 
 	// Resync absolute entity positions every 400 ticks (~20 seconds)
@@ -58,17 +98,23 @@ func (m *Manager) Tick() {
 
 // resyncPositions broadcasts absolute EntityTeleport packets for all players
 // to correct any client-side position drift from relative movement packets.
+// Riders are skipped: clients position them relative to their vehicle, and an
+// absolute teleport would visually detach them.
 func (m *Manager) resyncPositions() {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	for _, p := range m.players {
+		if _, riding := p.Vehicle(); riding {
+			continue
+		}
 		pos := p.GetPosition()
+		fx, fy, fz := p.FixedPosition()
 		tp := &pkt.EntityTeleport{
 			EntityID: p.EntityID,
-			X:        FixedPoint(pos.X),
-			Y:        FixedPoint(pos.Y),
-			Z:        FixedPoint(pos.Z),
+			X:        fx,
+			Y:        fy,
+			Z:        fz,
 			Yaw:      DegreesToAngle(pos.Yaw),
 			Pitch:    DegreesToAngle(pos.Pitch),
 			OnGround: pos.OnGround,
@@ -140,6 +186,14 @@ func (m *Manager) Add(p *Player) {
 		_ = p.WritePacket(&pkt.SpawnEntity{Data: it.spawnData})
 		_ = p.WritePacket(&pkt.EntityMetadata{Data: buildEntityMetadataData(it.entityID, it.metaData)})
 	}
+
+	m.sendMobsTo(p)
+	m.scoreboard.sendTo(p)
+}
+
+// Scoreboard returns the server-wide scoreboard.
+func (m *Manager) Scoreboard() *Scoreboard {
+	return m.scoreboard
 }
 
 // Remove unregisters a player and cleans up tracking/tab list for all others.
@@ -151,13 +205,12 @@ func (m *Manager) Remove(p *Player) {
 	delete(m.byUUID, p.UUID)
 
 	removeInfo := buildPlayerInfoRemove(p)
-	destroyData := buildDestroyEntities([]int32{p.EntityID})
 
 	for _, other := range m.players {
 		_ = other.WritePacket(&pkt.PlayerInfo{Data: removeInfo})
 
 		if other.IsTracking(p.EntityID) {
-			_ = other.WritePacket(&pkt.EntityDestroy{Data: destroyData})
+			_ = other.WritePacket(&pkt.EntityDestroy{EntityIDs: []int32{p.EntityID}})
 			other.Untrack(p.EntityID)
 		}
 	}
@@ -221,13 +274,11 @@ func (m *Manager) UpdateTracking(moved *Player) {
 			}
 		} else if !inRange && otherTracksMoved {
 			// Leave range: destroy for each other.
-			destroyMoved := buildDestroyEntities([]int32{moved.EntityID})
-			_ = other.WritePacket(&pkt.EntityDestroy{Data: destroyMoved})
+			_ = other.WritePacket(&pkt.EntityDestroy{EntityIDs: []int32{moved.EntityID}})
 			other.Untrack(moved.EntityID)
 
 			if movedTracksOther {
-				destroyOther := buildDestroyEntities([]int32{other.EntityID})
-				_ = moved.WritePacket(&pkt.EntityDestroy{Data: destroyOther})
+				_ = moved.WritePacket(&pkt.EntityDestroy{EntityIDs: []int32{other.EntityID}})
 				moved.Untrack(other.EntityID)
 			}
 		}
@@ -249,6 +300,19 @@ func (m *Manager) PlayerCount() int {
 	return len(m.players)
 }
 
+// ChunkInView reports whether chunk (cx, cz) is within view distance of any
+// player other than the one with entity ID except.
+func (m *Manager) ChunkInView(cx, cz int, except int32) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, p := range m.players {
+		if p.EntityID != except && InViewDistance(cx, cz, p.ChunkX(), p.ChunkZ(), m.viewDistance) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetByEntityID returns the player with the given entity ID, or nil.
 func (m *Manager) GetByEntityID(entityID int32) *Player {
 	m.mu.RLock()
@@ -301,11 +365,12 @@ func (m *Manager) spawnPlayerFor(viewer, target *Player) {
 		HeadYaw:  DegreesToAngle(pos.Yaw),
 	})
 
+	fx, fy, fz := target.FixedPosition()
 	_ = viewer.WritePacket(&pkt.EntityTeleport{
 		EntityID: target.EntityID,
-		X:        FixedPoint(pos.X),
-		Y:        FixedPoint(pos.Y),
-		Z:        FixedPoint(pos.Z),
+		X:        fx,
+		Y:        fy,
+		Z:        fz,
 		Yaw:      DegreesToAngle(pos.Yaw),
 		Pitch:    DegreesToAngle(pos.Pitch),
 		OnGround: pos.OnGround,
@@ -316,8 +381,16 @@ func (m *Manager) spawnPlayerFor(viewer, target *Player) {
 	_ = viewer.WritePacket(&pkt.EntityMetadata{Data: buildEntityMetadataData(target.EntityID, metaData)})
 
 	// Send 5 equipment packets (held item + 4 armor slots).
-	for _, eqData := range BuildEquipmentPackets(target.EntityID, target.Inventory) {
-		_ = viewer.WritePacket(&pkt.EntityEquipment{Data: eqData})
+	for _, eq := range BuildEquipmentPackets(target.EntityID, target.Inventory) {
+		_ = viewer.WritePacket(eq)
+	}
+
+	// Re-attach riders so new viewers don't see them beside their vehicle.
+	if vehicleID, riding := target.Vehicle(); riding {
+		_ = viewer.WritePacket(&pkt.AttachEntity{
+			EntityID:  target.EntityID,
+			VehicleID: vehicleID,
+		})
 	}
 
 	viewer.Track(target.EntityID)
@@ -337,9 +410,10 @@ func buildSpawnNamedEntity(p *Player, pos Position) []byte {
 
 	_, _ = mcnet.WriteVarInt(&buf, p.EntityID)
 	buf.Write(p.UUIDBytes[:])
-	_ = binary.Write(&buf, binary.BigEndian, FixedPoint(pos.X))
-	_ = binary.Write(&buf, binary.BigEndian, FixedPoint(pos.Y))
-	_ = binary.Write(&buf, binary.BigEndian, FixedPoint(pos.Z))
+	fx, fy, fz := p.FixedPosition()
+	_ = binary.Write(&buf, binary.BigEndian, fx)
+	_ = binary.Write(&buf, binary.BigEndian, fy)
+	_ = binary.Write(&buf, binary.BigEndian, fz)
 	buf.WriteByte(byte(DegreesToAngle(pos.Yaw)))
 	buf.WriteByte(byte(DegreesToAngle(pos.Pitch)))
 
@@ -378,9 +452,9 @@ func buildPlayerInfoAdd(p *Player) []byte {
 		}
 	}
 
-	_, _ = mcnet.WriteVarInt(&buf, int32(p.GetGameMode())) // gamemode
-	_, _ = mcnet.WriteVarInt(&buf, 0)                      // ping
-	buf.WriteByte(0)                                       // no display name
+	_, _ = mcnet.WriteVarInt(&buf, int32(p.GetGameMode()))         // gamemode
+	_, _ = mcnet.WriteVarInt(&buf, int32(p.Ping().Milliseconds())) // ping
+	buf.WriteByte(0)                                               // no display name
 
 	return buf.Bytes()
 }
@@ -398,6 +472,29 @@ func (m *Manager) BroadcastGameMode(p *Player) {
 	m.Broadcast(&pkt.PlayerInfo{Data: data})
 }
 
+// BroadcastLatency sends every player's current ping to all players in a
+// PlayerInfo Update Latency packet, refreshing the tab list signal bars.
+func (m *Manager) BroadcastLatency() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.players) == 0 {
+		return
+	}
+
+	var buf bytes.Buffer
+	_, _ = mcnet.WriteVarInt(&buf, 2) // action: Update Latency
+	_, _ = mcnet.WriteVarInt(&buf, int32(len(m.players)))
+	for _, p := range m.players {
+		buf.Write(p.UUIDBytes[:])
+		_, _ = mcnet.WriteVarInt(&buf, int32(p.Ping().Milliseconds()))
+	}
+
+	packet := &pkt.PlayerInfo{Data: buf.Bytes()}
+	for _, p := range m.players {
+		_ = p.WritePacket(packet)
+	}
+}
+
 // buildPlayerInfoRemove builds a PlayerInfo packet data with action=4 (Remove Player).
 func buildPlayerInfoRemove(p *Player) []byte {
 	var buf bytes.Buffer
@@ -408,15 +505,3 @@ func buildPlayerInfoRemove(p *Player) []byte {
 
 	return buf.Bytes()
 }
-
-// buildDestroyEntities encodes the DestroyEntities data fields.
-func buildDestroyEntities(ids []int32) []byte {
-	var buf bytes.Buffer
-
-	_, _ = mcnet.WriteVarInt(&buf, int32(len(ids)))
-	for _, id := range ids {
-		_, _ = mcnet.WriteVarInt(&buf, id)
-	}
-
-	return buf.Bytes()
-}
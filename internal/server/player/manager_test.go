@@ -1,8 +1,10 @@
 package player
 
 import (
+	"bytes"
 	"sync"
 	"testing"
+	"time"
 
 	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
 	mcnet "github.com/go-theft-craft/server/pkg/protocol"
@@ -209,3 +211,85 @@ func TestPlayerInfoOnRemove(t *testing.T) {
 		t.Errorf("p1 expected at least 1 PlayerInfo(remove), got %d", p1InfoCount)
 	}
 }
+
+func TestRelativeMovesMatchAbsoluteTeleport(t *testing.T) {
+	m := NewManager(8)
+	p, _ := newTestPlayer(m, -2.3, 1.7)
+	viewer, vc := newTestPlayer(m, 0, 0)
+	m.mu.Lock()
+	m.players[p.EntityID] = p
+	m.players[viewer.EntityID] = viewer
+	m.mu.Unlock()
+	viewer.Track(p.EntityID)
+
+	sumX, sumY, sumZ := p.FixedPosition()
+	x, y, z := -2.3, 4.0, 1.7
+	for i := 0; i < 100; i++ {
+		// Steps that are not representable exactly and cross zero.
+		x += 0.07
+		y += 0.013
+		z -= 0.031
+		oldFX, oldFY, oldFZ, newFX, newFY, newFZ := p.SetPosition(x, y, z, 0, 0, true)
+		sumX += newFX - oldFX
+		sumY += newFY - oldFY
+		sumZ += newFZ - oldFZ
+	}
+
+	if sumX != FixedPoint(x) || sumY != FixedPoint(y) || sumZ != FixedPoint(z) {
+		t.Errorf("summed deltas = (%d, %d, %d), want (%d, %d, %d)",
+			sumX, sumY, sumZ, FixedPoint(x), FixedPoint(y), FixedPoint(z))
+	}
+
+	vc.reset()
+	m.resyncPositions()
+	var tp *pkt.EntityTeleport
+	for _, pk := range vc.get() {
+		if e, ok := pk.(*pkt.EntityTeleport); ok && e.EntityID == p.EntityID {
+			tp = e
+		}
+	}
+	if tp == nil {
+		t.Fatal("expected a resync EntityTeleport")
+	}
+	if tp.X != sumX || tp.Y != sumY || tp.Z != sumZ {
+		t.Errorf("resync teleport = (%d, %d, %d), want summed position (%d, %d, %d)",
+			tp.X, tp.Y, tp.Z, sumX, sumY, sumZ)
+	}
+}
+
+func TestPlayerInfoCarriesPing(t *testing.T) {
+	m := NewManager(8)
+	p, _ := newTestPlayer(m, 0, 0)
+	p.SetPing(123 * time.Millisecond)
+
+	data := buildPlayerInfoAdd(p)
+
+	// ...gamemode, ping (123), no display name.
+	if tail := data[len(data)-3:]; tail[1] != 123 || tail[2] != 0 {
+		t.Errorf("PlayerInfo tail = %v, want ping 123 then no display name", tail)
+	}
+}
+
+func TestBroadcastLatency(t *testing.T) {
+	m := NewManager(8)
+	p, pc := newTestPlayer(m, 0, 0)
+	m.Add(p)
+	p.SetPing(200 * time.Millisecond)
+	pc.reset()
+
+	m.BroadcastLatency()
+
+	sent := pc.get()
+	if len(sent) != 1 {
+		t.Fatalf("got %d packets, want 1", len(sent))
+	}
+	info, ok := sent[0].(*pkt.PlayerInfo)
+	if !ok {
+		t.Fatalf("packet = %T, want PlayerInfo", sent[0])
+	}
+	want := append([]byte{2, 1}, p.UUIDBytes[:]...)
+	want = append(want, 0xC8, 0x01) // varint 200
+	if !bytes.Equal(info.Data, want) {
+		t.Errorf("PlayerInfo data = %v, want %v", info.Data, want)
+	}
+}
@@ -2,17 +2,20 @@ package player
 
 import (
 	"bytes"
+	"encoding/binary"
 
 	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
 )
 
 // Metadata type IDs for MC 1.8 entity metadata format.
 const (
-	metaTypeByte  = 0
-	metaTypeShort = 1
-	metaTypeInt   = 2
-	metaTypeFloat = 3
-	metaTypeSlot  = 5
+	metaTypeByte   = 0
+	metaTypeShort  = 1
+	metaTypeInt    = 2
+	metaTypeFloat  = 3
+	metaTypeString = 4
+	metaTypeSlot   = 5
 )
 
 // writeMetaByte writes a single byte-type metadata entry.
@@ -22,6 +25,18 @@ func writeMetaByte(buf *bytes.Buffer, index byte, val byte) {
 	buf.WriteByte(val)
 }
 
+// writeMetaString writes a single string-type metadata entry.
+func writeMetaString(buf *bytes.Buffer, index byte, val string) {
+	buf.WriteByte((index & 0x1F) | (metaTypeString << 5))
+	_, _ = mcnet.WriteString(buf, val)
+}
+
+// writeMetaFloat writes a single float-type metadata entry.
+func writeMetaFloat(buf *bytes.Buffer, index byte, val float32) {
+	buf.WriteByte((index & 0x1F) | (metaTypeFloat << 5))
+	_ = binary.Write(buf, binary.BigEndian, val)
+}
+
 // BuildEntityMetadata builds entity metadata bytes for broadcasting state changes.
 // Includes entityFlags (index 0) and skinParts (index 10).
 func BuildEntityMetadata(p *Player) []byte {
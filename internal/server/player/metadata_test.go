@@ -1,6 +1,7 @@
 package player
 
 import (
+	"bytes"
 	"testing"
 
 	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
@@ -91,6 +92,31 @@ func TestBuildSpawnMetadata(t *testing.T) {
 	}
 }
 
+func TestMetadataTerminator(t *testing.T) {
+	// Protocol 47 (1.8) ends metadata with 0x7F; 0xFF is only valid from 1.9.
+	if pkt.MetadataEnd != 0x7F {
+		t.Fatalf("pc_1_8 MetadataEnd = %02X, want 7F", pkt.MetadataEnd)
+	}
+
+	p := newTestPlayerSimple()
+	p.SetSneaking(true)
+	ie := &ItemEntity{Item: Slot{BlockID: 1, ItemCount: 3}}
+
+	for name, data := range map[string][]byte{
+		"BuildEntityMetadata": BuildEntityMetadata(p),
+		"BuildSpawnMetadata":  BuildSpawnMetadata(p),
+		"buildItemMetadata":   buildItemMetadata(ie),
+		"buildMobMetadata":    buildMobMetadata("Bob"),
+	} {
+		if len(data) == 0 || data[len(data)-1] != pkt.MetadataEnd {
+			t.Errorf("%s does not end with MetadataEnd: %X", name, data)
+		}
+		if bytes.Count(data, []byte{pkt.MetadataEnd}) != 1 {
+			t.Errorf("%s contains more than one terminator byte: %X", name, data)
+		}
+	}
+}
+
 func newTestPlayerSimple() *Player {
 	uuid := [16]byte{0x01}
 	return NewPlayer(1, "test-uuid", uuid, "testplayer", nil, func(p mcnet.Packet) error {
@@ -0,0 +1,173 @@
+package player
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/go-theft-craft/server/pkg/gamedata"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+)
+
+// MobEntity is a living non-player entity such as a pig or zombie.
+type MobEntity struct {
+	EntityID  int32
+	UUID      [16]byte
+	Kind      string // gameData entity name, e.g. "pig"
+	TypeID    int    // gameData entity ID sent in SpawnEntityLiving
+	X, Y, Z   float64
+	SpawnTick int64
+
+	// CustomName is set with a name tag. Named mobs are Persistent and
+	// never despawn.
+	CustomName string
+	Persistent bool
+
+	// Wandering state: the heading in degrees and the ticks left walking
+	// along it. A mob with no ticks left stands still.
+	Yaw         float32
+	wanderTicks int
+}
+
+// ID returns the mob's entity ID.
+func (me *MobEntity) ID() int32 { return me.EntityID }
+
+// EntityUUID returns the mob's UUID.
+func (me *MobEntity) EntityUUID() [16]byte { return me.UUID }
+
+// EntityPosition returns the mob's position.
+func (me *MobEntity) EntityPosition() (x, y, z float64) { return me.X, me.Y, me.Z }
+
+// SpawnMob creates a mob of the given entity type at (x, y, z) and
+// broadcasts it to all players.
+func (m *Manager) SpawnMob(e gamedata.Entity, x, y, z float64) *MobEntity {
+	me := &MobEntity{
+		EntityID:  m.AllocateEntityID(),
+		UUID:      newEntityUUID(),
+		Kind:      e.Name,
+		TypeID:    e.ID,
+		X:         x,
+		Y:         y,
+		Z:         z,
+		SpawnTick: m.currentTick.Load(),
+	}
+
+	m.mobMu.Lock()
+	m.mobs[me.EntityID] = me
+	spawnData := buildSpawnMobData(me)
+	m.mobMu.Unlock()
+	m.RegisterEntity(me)
+
+	m.Broadcast(&pkt.SpawnEntityLiving{Data: spawnData})
+	return me
+}
+
+// MobByEntityID returns the mob with the given entity ID, or nil.
+func (m *Manager) MobByEntityID(id int32) *MobEntity {
+	m.mobMu.Lock()
+	defer m.mobMu.Unlock()
+	return m.mobs[id]
+}
+
+// NameMob gives the mob with the given entity ID a custom name, as a name tag
+// does, and marks it persistent. It returns false if there is no such mob.
+func (m *Manager) NameMob(id int32, name string) bool {
+	m.mobMu.Lock()
+	me, ok := m.mobs[id]
+	if ok {
+		me.CustomName = name
+		me.Persistent = true
+	}
+	m.mobMu.Unlock()
+	if !ok {
+		return false
+	}
+
+	m.Broadcast(&pkt.EntityMetadata{Data: buildEntityMetadataData(id, buildMobMetadata(name))})
+	return true
+}
+
+// cleanupExpiredMobs removes unnamed mobs older than their despawn
+// lifetime. Per-type policies take precedence over the generic mob policy.
+func (m *Manager) cleanupExpiredMobs(currentTick int64) {
+	m.mobMu.Lock()
+	var expired []int32
+	for id, me := range m.mobs {
+		if me.Persistent {
+			continue
+		}
+		lifetime, ok := m.lifetime(me.Kind, DespawnMob)
+		if ok && currentTick-me.SpawnTick > lifetime {
+			expired = append(expired, id)
+		}
+	}
+	for _, id := range expired {
+		m.UnregisterEntity(m.mobs[id].UUID)
+		delete(m.mobs, id)
+	}
+	m.mobMu.Unlock()
+
+	m.broadcastDestroy(expired)
+}
+
+// sendMobsTo sends every existing mob to a newly joined player.
+func (m *Manager) sendMobsTo(p *Player) {
+	type mobSnapshot struct {
+		spawnData []byte
+		name      string
+		entityID  int32
+	}
+
+	m.mobMu.Lock()
+	mobs := make([]mobSnapshot, 0, len(m.mobs))
+	for _, me := range m.mobs {
+		mobs = append(mobs, mobSnapshot{
+			spawnData: buildSpawnMobData(me),
+			name:      me.CustomName,
+			entityID:  me.EntityID,
+		})
+	}
+	m.mobMu.Unlock()
+
+	for _, mob := range mobs {
+		_ = p.WritePacket(&pkt.SpawnEntityLiving{Data: mob.spawnData})
+		if mob.name != "" {
+			_ = p.WritePacket(&pkt.EntityMetadata{Data: buildEntityMetadataData(mob.entityID, buildMobMetadata(mob.name))})
+		}
+	}
+}
+
+// buildSpawnMobData encodes the SpawnEntityLiving (0x0F) data for a mob at
+// rest, with its custom name in the trailing metadata.
+func buildSpawnMobData(me *MobEntity) []byte {
+	var buf bytes.Buffer
+
+	_, _ = mcnet.WriteVarInt(&buf, me.EntityID)
+	buf.WriteByte(byte(me.TypeID))
+	_ = binary.Write(&buf, binary.BigEndian, FixedPoint(me.X))
+	_ = binary.Write(&buf, binary.BigEndian, FixedPoint(me.Y))
+	_ = binary.Write(&buf, binary.BigEndian, FixedPoint(me.Z))
+	_ = binary.Write(&buf, binary.BigEndian, int8(0))  // yaw
+	_ = binary.Write(&buf, binary.BigEndian, int8(0))  // pitch
+	_ = binary.Write(&buf, binary.BigEndian, int8(0))  // head pitch
+	_ = binary.Write(&buf, binary.BigEndian, int16(0)) // velocity X
+	_ = binary.Write(&buf, binary.BigEndian, int16(0)) // velocity Y
+	_ = binary.Write(&buf, binary.BigEndian, int16(0)) // velocity Z
+	buf.Write(buildMobMetadata(me.CustomName))
+
+	return buf.Bytes()
+}
+
+// buildMobMetadata builds entity metadata for a mob's custom name.
+// Index 2 (string) is the name and index 3 (byte) shows it at all times.
+func buildMobMetadata(name string) []byte {
+	var buf bytes.Buffer
+
+	if name != "" {
+		writeMetaString(&buf, 2, name)
+		writeMetaByte(&buf, 3, 1)
+	}
+	buf.WriteByte(pkt.MetadataEnd)
+
+	return buf.Bytes()
+}
@@ -0,0 +1,153 @@
+package player
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+	"github.com/go-theft-craft/server/pkg/world"
+)
+
+// Random-walk tuning for idle mobs.
+const (
+	mobWanderChance   = 120  // a standing mob starts walking with 1/mobWanderChance odds per tick
+	mobWanderMinTicks = 20   // shortest walk
+	mobWanderMaxTicks = 60   // longest walk
+	mobWalkSpeed      = 0.15 // blocks per tick
+)
+
+// mobDespawnDistance is how far, in blocks, an unnamed mob may be from every
+// player before it despawns.
+const mobDespawnDistance = 128
+
+// distantMobCheckInterval is how often, in ticks, distant mobs are removed.
+const distantMobCheckInterval = 20
+
+// TickMobs advances every mob's random walk by one tick and broadcasts the
+// resulting looks and moves. Mobs follow the terrain, stepping up or down at
+// most one block, and stop at cliffs, walls, liquids and unloaded chunks.
+func (m *Manager) TickMobs(w *world.World, rng *rand.Rand) {
+	var packets []mcnet.Packet
+
+	m.mobMu.Lock()
+	ids := make([]int32, 0, len(m.mobs))
+	for id := range m.mobs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		packets = append(packets, m.mobs[id].wander(w, rng)...)
+	}
+	m.mobMu.Unlock()
+
+	for _, p := range packets {
+		m.Broadcast(p)
+	}
+}
+
+// wander advances the mob's random walk by one tick and returns the packets
+// describing the change. Caller must hold m.mobMu.
+func (me *MobEntity) wander(w *world.World, rng *rand.Rand) []mcnet.Packet {
+	if me.wanderTicks <= 0 {
+		if rng.Intn(mobWanderChance) != 0 {
+			return nil
+		}
+		me.Yaw = rng.Float32() * 360
+		me.wanderTicks = mobWanderMinTicks + rng.Intn(mobWanderMaxTicks-mobWanderMinTicks+1)
+		yaw := DegreesToAngle(me.Yaw)
+		return []mcnet.Packet{
+			&pkt.EntityLook{EntityID: me.EntityID, Yaw: yaw, OnGround: true},
+			&pkt.EntityHeadRotation{EntityID: me.EntityID, HeadYaw: yaw},
+		}
+	}
+
+	yaw := float64(me.Yaw) * math.Pi / 180
+	nx := me.X - math.Sin(yaw)*mobWalkSpeed
+	nz := me.Z + math.Cos(yaw)*mobWalkSpeed
+	ny, ok := mobGroundY(w, nx, me.Y, nz)
+	if !ok {
+		me.wanderTicks = 0
+		return nil
+	}
+	me.wanderTicks--
+
+	move := &pkt.RelEntityMove{
+		EntityID: me.EntityID,
+		DX:       int8(FixedPoint(nx) - FixedPoint(me.X)),
+		DY:       int8(FixedPoint(ny) - FixedPoint(me.Y)),
+		DZ:       int8(FixedPoint(nz) - FixedPoint(me.Z)),
+		OnGround: true,
+	}
+	me.X, me.Y, me.Z = nx, ny, nz
+	return []mcnet.Packet{move}
+}
+
+// mobGroundY returns the height a mob at height y stands at after moving
+// into the column containing (x, z), or false if that needs a step of more
+// than one block up or down, lands on a liquid, or reaches into a chunk that
+// is not loaded. Only the few blocks around y are looked at.
+func mobGroundY(w *world.World, x, y, z float64) (float64, bool) {
+	bx, bz := int(math.Floor(x)), int(math.Floor(z))
+	feet := int(math.Floor(y))
+	for by := feet + 2; by >= feet-2 && by >= 0; by-- {
+		state, ok := w.LoadedBlock(bx, by, bz)
+		if !ok {
+			return 0, false
+		}
+		if state == 0 {
+			continue
+		}
+		switch state >> 4 {
+		case 8, 9, 10, 11: // water, lava
+			return 0, false
+		}
+		if by > feet {
+			return 0, false // a wall
+		}
+		return float64(by + 1), true
+	}
+	return 0, false
+}
+
+// despawnDistantMobs removes unnamed mobs farther than mobDespawnDistance
+// from every player, unless their despawn policy keeps them forever.
+func (m *Manager) despawnDistantMobs() {
+	var positions []Position
+	m.ForEach(func(p *Player) {
+		positions = append(positions, p.GetPosition())
+	})
+
+	m.mobMu.Lock()
+	var distant []int32
+	for id, me := range m.mobs {
+		if me.Persistent {
+			continue
+		}
+		if _, ok := m.lifetime(me.Kind, DespawnMob); !ok {
+			continue
+		}
+		if !nearAny(positions, me.X, me.Y, me.Z, mobDespawnDistance) {
+			distant = append(distant, id)
+		}
+	}
+	for _, id := range distant {
+		m.UnregisterEntity(m.mobs[id].UUID)
+		delete(m.mobs, id)
+	}
+	m.mobMu.Unlock()
+
+	m.broadcastDestroy(distant)
+}
+
+// nearAny reports whether (x, y, z) is within dist blocks of any position.
+func nearAny(positions []Position, x, y, z, dist float64) bool {
+	for _, pos := range positions {
+		dx, dy, dz := pos.X-x, pos.Y-y, pos.Z-z
+		if dx*dx+dy*dy+dz*dz <= dist*dist {
+			return true
+		}
+	}
+	return false
+}
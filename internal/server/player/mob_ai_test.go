@@ -0,0 +1,161 @@
+package player
+
+import (
+	"math/rand"
+	"testing"
+
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	"github.com/go-theft-craft/server/pkg/world"
+	"github.com/go-theft-craft/server/pkg/world/gen"
+)
+
+// plainsWorld returns a flat grass world in the plains biome.
+func plainsWorld() *world.World {
+	return world.NewWorld(singleBiomeGenerator{biome: 1})
+}
+
+func TestSpawnNaturalMob_RespectsCap(t *testing.T) {
+	m := NewManager(8)
+	p, _ := newTestPlayer(m, 0.5, 0.5)
+	m.Add(p)
+
+	w, gd := plainsWorld(), pkt.New()
+	chunks := []gen.ChunkPos{{X: 0, Z: 0}}
+	rng := rand.New(rand.NewSource(1))
+	for range 10 * MobCapPerPlayer {
+		m.SpawnNaturalMob(w, gd, chunks, rng)
+	}
+	if n := m.naturalMobCount(); n != MobCapPerPlayer {
+		t.Fatalf("mobs = %d, want the cap of %d for one player", n, MobCapPerPlayer)
+	}
+
+	// Named mobs do not count toward the cap.
+	for id := range m.mobs {
+		m.NameMob(id, "Named")
+		break
+	}
+	if m.SpawnNaturalMob(w, gd, chunks, rng) == nil {
+		t.Error("expected a spawn after a mob was named")
+	}
+}
+
+func TestSpawnNaturalMob_NoPlayersNoSpawn(t *testing.T) {
+	m := NewManager(8)
+	rng := rand.New(rand.NewSource(1))
+	if me := m.SpawnNaturalMob(plainsWorld(), pkt.New(), []gen.ChunkPos{{}}, rng); me != nil {
+		t.Errorf("spawned %s with nobody online", me.Kind)
+	}
+}
+
+func TestSpawnNaturalMob_SendsSpawnPacket(t *testing.T) {
+	m := NewManager(8)
+	p, pc := newTestPlayer(m, 0.5, 0.5)
+	m.Add(p)
+	pc.reset()
+
+	rng := rand.New(rand.NewSource(1))
+	me := m.SpawnNaturalMob(plainsWorld(), pkt.New(), []gen.ChunkPos{{}}, rng)
+	if me == nil {
+		t.Fatal("expected a mob to spawn on grass")
+	}
+	if me.Y != 5 {
+		t.Errorf("mob Y = %v, want 5 (on top of the grass)", me.Y)
+	}
+
+	var spawn *pkt.SpawnEntityLiving
+	for _, pk := range pc.get() {
+		if s, ok := pk.(*pkt.SpawnEntityLiving); ok {
+			spawn = s
+		}
+	}
+	if spawn == nil {
+		t.Fatal("expected a SpawnEntityLiving packet")
+	}
+	if got := spawn.Data[1]; int(got) != me.TypeID {
+		t.Errorf("spawned type = %d, want %d (%s)", got, me.TypeID, me.Kind)
+	}
+}
+
+func TestTickMobs_Wanders(t *testing.T) {
+	m := NewManager(8)
+	p, pc := newTestPlayer(m, 0.5, 0.5)
+	m.Add(p)
+	me := m.SpawnMob(testPig, 0.5, 5, 0.5)
+	pc.reset()
+
+	w := plainsWorld()
+	w.PreGenerateRadius(2) // mobs only walk through loaded chunks
+	rng := rand.New(rand.NewSource(1))
+	for range 20 * mobWanderChance {
+		m.TickMobs(w, rng)
+	}
+
+	if pc.countByType(pkt.EntityLook{}.PacketID()) == 0 {
+		t.Error("expected the mob to turn")
+	}
+	if pc.countByType(pkt.RelEntityMove{}.PacketID()) == 0 {
+		t.Error("expected the mob to move")
+	}
+	if me.Y != 5 {
+		t.Errorf("mob Y = %v, want 5 on flat ground", me.Y)
+	}
+}
+
+func TestTickMobs_StopsAtCliff(t *testing.T) {
+	m := NewManager(8)
+	w := plainsWorld()
+	w.GetOrGenerateChunk(0, 0)
+	for y := 5; y <= 7; y++ {
+		w.SetBlock(0, y, 1, grassState) // a wall three blocks high
+	}
+	me := m.SpawnMob(testPig, 0.5, 5, 0.5)
+	me.Yaw, me.wanderTicks = 0, 10 // walking toward +Z
+
+	rng := rand.New(rand.NewSource(1))
+	for range 10 {
+		m.TickMobs(w, rng)
+	}
+	if me.Z >= 1 {
+		t.Errorf("mob walked into the wall, Z = %v", me.Z)
+	}
+}
+
+func TestTickMobs_StopsAtUnloadedChunk(t *testing.T) {
+	m := NewManager(8)
+	w := plainsWorld()
+	w.GetOrGenerateChunk(0, 0)
+	me := m.SpawnMob(testPig, 0.5, 5, 15.5)
+	me.Yaw, me.wanderTicks = 0, 10 // walking toward +Z, into chunk (0, 1)
+
+	rng := rand.New(rand.NewSource(1))
+	for range 10 {
+		m.TickMobs(w, rng)
+	}
+	if me.Z >= 16 {
+		t.Errorf("mob walked into an unloaded chunk, Z = %v", me.Z)
+	}
+	if _, ok := w.LoadedBlock(0, 4, 16); ok {
+		t.Error("mob movement loaded chunk (0, 1)")
+	}
+}
+
+func TestDespawnDistantMobs(t *testing.T) {
+	m := NewManager(8)
+	p, _ := newTestPlayer(m, 0.5, 0.5)
+	m.Add(p)
+	near := m.SpawnMob(testPig, 10, 4, 10)
+	far := m.SpawnMob(testPig, 500, 4, 500)
+	named := m.SpawnMob(testPig, 500, 4, 500)
+	m.NameMob(named.EntityID, "Wilbur")
+
+	m.despawnDistantMobs()
+	if m.MobByEntityID(near.EntityID) == nil {
+		t.Error("a mob near the player despawned")
+	}
+	if m.MobByEntityID(far.EntityID) != nil {
+		t.Error("a mob far from every player should despawn")
+	}
+	if m.MobByEntityID(named.EntityID) == nil {
+		t.Error("a named mob should never despawn")
+	}
+}
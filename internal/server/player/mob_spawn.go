@@ -0,0 +1,127 @@
+package player
+
+import (
+	"math/rand"
+
+	"github.com/go-theft-craft/server/pkg/gamedata"
+	"github.com/go-theft-craft/server/pkg/world"
+	"github.com/go-theft-craft/server/pkg/world/gen"
+)
+
+// SpawnEntry is a weighted passive mob choice. Name matches gameData.Entities.
+type SpawnEntry struct {
+	Name   string
+	Weight int
+}
+
+// standardAnimals is the vanilla passive spawn list shared by most land biomes.
+var standardAnimals = []SpawnEntry{
+	{Name: "Sheep", Weight: 12},
+	{Name: "Pig", Weight: 10},
+	{Name: "Chicken", Weight: 10},
+	{Name: "Cow", Weight: 8},
+}
+
+// biomeSpawnTable maps gameData biome categories to passive spawn lists.
+// Categories without an entry (ocean, river, beach, nether, ...) spawn no
+// land animals.
+var biomeSpawnTable = map[string][]SpawnEntry{
+	"plains":        withStandardAnimals(SpawnEntry{Name: "EntityHorse", Weight: 5}),
+	"forest":        withStandardAnimals(SpawnEntry{Name: "Wolf", Weight: 5}),
+	"taiga":         withStandardAnimals(SpawnEntry{Name: "Wolf", Weight: 8}, SpawnEntry{Name: "Rabbit", Weight: 4}),
+	"savanna":       withStandardAnimals(SpawnEntry{Name: "EntityHorse", Weight: 1}),
+	"jungle":        withStandardAnimals(SpawnEntry{Name: "Ozelot", Weight: 2}),
+	"extreme_hills": withStandardAnimals(),
+	"swamp":         withStandardAnimals(),
+	"desert":        {{Name: "Rabbit", Weight: 4}},
+	"icy":           {{Name: "Rabbit", Weight: 10}},
+	"mushroom":      {{Name: "MushroomCow", Weight: 8}},
+}
+
+// withStandardAnimals returns a copy of standardAnimals followed by extra.
+func withStandardAnimals(extra ...SpawnEntry) []SpawnEntry {
+	entries := make([]SpawnEntry, 0, len(standardAnimals)+len(extra))
+	entries = append(entries, standardAnimals...)
+	return append(entries, extra...)
+}
+
+// SpawnCandidates returns the weighted passive spawn list for a biome ID,
+// resolved through the biome's gameData category.
+func SpawnCandidates(gd *gamedata.GameData, biomeID byte) []SpawnEntry {
+	if gd == nil || gd.Biomes == nil {
+		return nil
+	}
+	b, ok := gd.Biomes.ByID(int(biomeID))
+	if !ok {
+		return nil
+	}
+	return biomeSpawnTable[b.Category]
+}
+
+// PickSpawnMob chooses a passive mob to spawn in the block column (x, z),
+// weighted by the spawn list of the biome there. It returns false when the
+// biome allows no land animals.
+func PickSpawnMob(w *world.World, gd *gamedata.GameData, x, z int, rng *rand.Rand) (gamedata.Entity, bool) {
+	entries := SpawnCandidates(gd, w.GetBiome(x, z))
+
+	total := 0
+	for _, e := range entries {
+		total += e.Weight
+	}
+	if total == 0 {
+		return gamedata.Entity{}, false
+	}
+
+	n := rng.Intn(total)
+	for _, e := range entries {
+		if n < e.Weight {
+			return gd.Entities.ByName(e.Name)
+		}
+		n -= e.Weight
+	}
+	return gamedata.Entity{}, false
+}
+
+// MobCapPerPlayer is how many naturally spawned mobs each online player adds
+// to the spawn cap. Named mobs do not count toward it.
+const MobCapPerPlayer = 10
+
+// grassState is the block state mobs spawn on.
+const grassState int32 = 2 << 4
+
+// SpawnNaturalMob makes one spawn attempt in a random column of chunks: if
+// the mob count is below the cap and the column is topped by grass with room
+// above it, a mob picked for its biome is spawned there. It returns the mob,
+// or nil if the attempt failed.
+func (m *Manager) SpawnNaturalMob(w *world.World, gd *gamedata.GameData, chunks []gen.ChunkPos, rng *rand.Rand) *MobEntity {
+	if len(chunks) == 0 || m.naturalMobCount() >= MobCapPerPlayer*m.PlayerCount() {
+		return nil
+	}
+
+	c := chunks[rng.Intn(len(chunks))]
+	x, z := c.X*16+rng.Intn(16), c.Z*16+rng.Intn(16)
+	y := w.TopBlockY(x, z)
+	if y < 0 || y > 253 || w.GetBlock(x, y, z) != grassState ||
+		w.GetBlock(x, y+1, z) != 0 || w.GetBlock(x, y+2, z) != 0 {
+		return nil
+	}
+
+	e, ok := PickSpawnMob(w, gd, x, z, rng)
+	if !ok {
+		return nil
+	}
+	return m.SpawnMob(e, float64(x)+0.5, float64(y+1), float64(z)+0.5)
+}
+
+// naturalMobCount returns the number of mobs that count toward the spawn cap.
+func (m *Manager) naturalMobCount() int {
+	m.mobMu.Lock()
+	defer m.mobMu.Unlock()
+	n := 0
+	for _, me := range m.mobs {
+		if !me.Persistent {
+			n++
+		}
+	}
+	return n
+}
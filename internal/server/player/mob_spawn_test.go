@@ -0,0 +1,84 @@
+package player
+
+import (
+	"math/rand"
+	"testing"
+
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	"github.com/go-theft-craft/server/pkg/world"
+	"github.com/go-theft-craft/server/pkg/world/gen"
+)
+
+// singleBiomeGenerator produces grass-topped chunks of one biome.
+type singleBiomeGenerator struct{ biome byte }
+
+func (g singleBiomeGenerator) Generate(_, _ int) *gen.ChunkData {
+	c := &gen.ChunkData{}
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			c.SetBlock(x, 4, z, 2<<4)
+			c.SetBiome(x, z, g.biome)
+		}
+	}
+	return c
+}
+
+func (g singleBiomeGenerator) HeightAt(_, _ int) int         { return 4 }
+func (singleBiomeGenerator) HeightsFor(_, _ int) [16][16]int { return gen.UniformHeights(4) }
+
+// spawnedNames picks n mobs at (0, 0) and returns the set of entity names chosen.
+func spawnedNames(t *testing.T, biome byte, n int) map[string]bool {
+	t.Helper()
+	gd := pkt.New()
+	w := world.NewWorld(singleBiomeGenerator{biome: biome})
+	rng := rand.New(rand.NewSource(1))
+
+	names := make(map[string]bool)
+	for i := 0; i < n; i++ {
+		if e, ok := PickSpawnMob(w, gd, 0, 0, rng); ok {
+			names[e.Name] = true
+		}
+	}
+	return names
+}
+
+func TestPickSpawnMob_OceanHasNoLandAnimals(t *testing.T) {
+	names := spawnedNames(t, 0, 1000) // ocean
+	if names["Cow"] {
+		t.Error("cows must not spawn in ocean")
+	}
+	if len(names) != 0 {
+		t.Errorf("expected no land animals in ocean, got %v", names)
+	}
+}
+
+func TestPickSpawnMob_ForestAnimals(t *testing.T) {
+	names := spawnedNames(t, 4, 1000) // forest
+	want := []string{"Sheep", "Pig", "Chicken", "Cow", "Wolf"}
+	for _, name := range want {
+		if !names[name] {
+			t.Errorf("expected %s to spawn in forest", name)
+		}
+	}
+	if len(names) != len(want) {
+		t.Errorf("forest spawned %v, want exactly %v", names, want)
+	}
+}
+
+func TestPickSpawnMob_DesertRabbits(t *testing.T) {
+	names := spawnedNames(t, 2, 200) // desert
+	if len(names) != 1 || !names["Rabbit"] {
+		t.Errorf("desert spawned %v, want only Rabbit", names)
+	}
+}
+
+func TestSpawnCandidates_AllNamesResolve(t *testing.T) {
+	gd := pkt.New()
+	for category, entries := range biomeSpawnTable {
+		for _, e := range entries {
+			if _, ok := gd.Entities.ByName(e.Name); !ok {
+				t.Errorf("%s spawn entry %q is not a known entity", category, e.Name)
+			}
+		}
+	}
+}
@@ -3,6 +3,7 @@ package player
 import (
 	"math"
 	"sync"
+	"time"
 
 	mcnet "github.com/go-theft-craft/server/pkg/protocol"
 )
@@ -29,6 +30,12 @@ type Player struct {
 	UUIDBytes  [16]byte // for protocol encoding
 	Username   string
 	Properties []SkinProperty
+	RemoteAddr string    // client network address
+	JoinedAt   time.Time // when the player joined the server
+
+	// Disconnect kicks the player with the given message. It is set by the
+	// owning connection when play starts and is nil for detached players.
+	Disconnect func(reason string)
 
 	pos        Position
 	lastFixedX int32
@@ -40,12 +47,52 @@ type Player struct {
 	entityFlags byte    // bit 1 = sneaking, bit 3 = sprinting
 	skinParts   byte    // from ClientSettings
 	flying      bool    // currently flying (set by AbilitiesSB)
+	riding      bool    // attached to a vehicle entity
+	vehicleID   int32   // entity ID of the ridden vehicle when riding
 	Height      float64 // 1.8 normal, 1.65 sneaking
 
+	// Multipliers of the base fly and walk speeds, set by /speed.
+	flySpeed  float32
+	walkSpeed float32
+
+	ping      time.Duration // round trip of the last acknowledged keep-alive
+	cooldowns map[int16]int // item ID → ticks until it can be used again
+	health    float32       // in half-hearts, 0 = dead
+
+	// Ticks left during which further damage is ignored.
+	invulnerable int
+
+	// Active potion effects by effect ID.
+	effects map[int8]ActiveEffect
+
+	// Username of the last player this one exchanged private messages
+	// with, the target of /reply.
+	lastMessaged string
+
+	// Experience level, points toward the next level and total points.
+	xpLevel  int32
+	xpPoints int32
+	xpTotal  int32
+
+	// Boss bar shown to this player only, if any.
+	bossBar *BossBar
+
+	// Position saved by /sethome, if any.
+	home    Position
+	hasHome bool
+
+	food       int32   // 0-20 hunger points
+	saturation float32 // drained before food
+	exhaustion float32 // accumulates toward the next saturation/food point
+	regenTimer int     // ticks since the last natural regeneration
+
 	WritePacket    func(mcnet.Packet) error
 	trackedPlayers map[int32]struct{}
 }
 
+// MaxHealth is a player's full health in half-hearts.
+const MaxHealth = 20
+
 // NewPlayer creates a new Player with its initial spawn position.
 func NewPlayer(entityID int32, uuid string, uuidBytes [16]byte, username string, props []SkinProperty, writePacket func(mcnet.Packet) error) *Player {
 	spawnPos := Position{X: 0.5, Y: 4.0, Z: 0.5}
@@ -64,6 +111,11 @@ func NewPlayer(entityID int32, uuid string, uuidBytes [16]byte, username string,
 		lastFixedZ:     FixedPoint(spawnPos.Z),
 		Inventory:      inv,
 		Height:         1.8,
+		flySpeed:       1,
+		walkSpeed:      1,
+		health:         MaxHealth,
+		food:           MaxFood,
+		saturation:     DefaultSaturation,
 		WritePacket:    writePacket,
 		trackedPlayers: make(map[int32]struct{}),
 	}
@@ -103,6 +155,15 @@ func (p *Player) SetPosition(x, y, z float64, yaw, pitch float32, onGround bool)
 	return
 }
 
+// FixedPosition returns the fixed-point position last reported to trackers.
+// Absolute packets use it rather than re-rounding the float position, so they
+// always agree with the sum of the relative moves sent before them.
+func (p *Player) FixedPosition() (x, y, z int32) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.lastFixedX, p.lastFixedY, p.lastFixedZ
+}
+
 // UpdateLook updates only the player's look direction.
 func (p *Player) UpdateLook(yaw, pitch float32, onGround bool) {
 	p.mu.Lock()
@@ -211,6 +272,53 @@ func (p *Player) IsFlying() bool {
 	return p.flying
 }
 
+// Speeds returns the player's fly and walk speed multipliers.
+func (p *Player) Speeds() (fly, walk float32) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.flySpeed, p.walkSpeed
+}
+
+// SetFlySpeed sets the multiplier applied to the base flying speed.
+func (p *Player) SetFlySpeed(m float32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.flySpeed = m
+}
+
+// SetWalkSpeed sets the multiplier applied to the base walking speed.
+func (p *Player) SetWalkSpeed(m float32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.walkSpeed = m
+}
+
+// Mount records that the player is riding the given vehicle entity.
+func (p *Player) Mount(vehicleID int32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.riding = true
+	p.vehicleID = vehicleID
+}
+
+// Dismount clears the riding state. It returns the previous vehicle's entity
+// ID and whether the player was riding at all.
+func (p *Player) Dismount() (int32, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	vehicleID, wasRiding := p.vehicleID, p.riding
+	p.riding = false
+	p.vehicleID = 0
+	return vehicleID, wasRiding
+}
+
+// Vehicle returns the entity ID of the ridden vehicle and whether the player is riding.
+func (p *Player) Vehicle() (int32, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.vehicleID, p.riding
+}
+
 // SetSkinParts sets the skin parts bitmask from ClientSettings.
 func (p *Player) SetSkinParts(parts byte) {
 	p.mu.Lock()
@@ -246,6 +354,98 @@ func (p *Player) SetGameMode(mode uint8) {
 	p.gameMode = mode
 }
 
+// GetHealth returns the player's health in half-hearts.
+func (p *Player) GetHealth() float32 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.health
+}
+
+// SetHealth sets the player's health, clamped to [0, MaxHealth].
+func (p *Player) SetHealth(health float32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.health = min(max(health, 0), MaxHealth)
+}
+
+// IsDead reports whether the player's health has reached zero.
+func (p *Player) IsDead() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.health <= 0
+}
+
+// invulnerabilityTicks is how long a player ignores damage after being hurt.
+const invulnerabilityTicks = 10
+
+// Damage lowers the player's health by amount and starts a short
+// invulnerability window. It returns false, leaving health unchanged, if the
+// player is dead or still invulnerable from a previous hit.
+func (p *Player) Damage(amount float32) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.health <= 0 || p.invulnerable > 0 {
+		return false
+	}
+	p.health = max(p.health-amount, 0)
+	p.invulnerable = invulnerabilityTicks
+	return true
+}
+
+// TickInvulnerability counts down the invulnerability window by one tick.
+func (p *Player) TickInvulnerability() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.invulnerable > 0 {
+		p.invulnerable--
+	}
+}
+
+// Ping returns the round-trip time measured by the last keep-alive.
+func (p *Player) Ping() time.Duration {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.ping
+}
+
+// SetPing records a keep-alive round-trip time.
+func (p *Player) SetPing(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ping = d
+}
+
+// LastMessaged returns the username of the player's last private message
+// correspondent, or "" if there is none.
+func (p *Player) LastMessaged() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.lastMessaged
+}
+
+// SetLastMessaged records the player's last private message correspondent.
+func (p *Player) SetLastMessaged(username string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastMessaged = username
+}
+
+// Home returns the player's home position and whether one was set.
+func (p *Player) Home() (Position, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.home, p.hasHome
+}
+
+// SetHome sets the player's home position.
+func (p *Player) SetHome(pos Position) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.home = pos
+	p.hasHome = true
+}
+
 // ApplyData restores a player's saved state (position, game mode, inventory).
 func (p *Player) ApplyData(pos Position, gameMode uint8, slots [36]Slot, armor [4]Slot, heldSlot int16) {
 	p.mu.Lock()
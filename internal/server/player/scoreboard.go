@@ -0,0 +1,166 @@
+package player
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+)
+
+// Scoreboard display slots.
+const (
+	DisplayList      int8 = 0
+	DisplaySidebar   int8 = 1
+	DisplayBelowName int8 = 2
+)
+
+// ScoreboardObjective modes and ScoreboardScore actions.
+const (
+	objectiveCreate byte = 0
+	scoreUpdate     byte = 0
+)
+
+// Length limits the client enforces on scoreboard strings.
+const (
+	MaxObjectiveNameLength    = 16
+	MaxObjectiveDisplayLength = 32
+	MaxScoreEntryLength       = 40
+)
+
+// objective is a named set of scores shown with its display name.
+type objective struct {
+	displayName string
+	scores      map[string]int32 // entry name → score
+}
+
+// Scoreboard holds the server-wide objectives and scores and keeps every
+// player's client in sync with them.
+type Scoreboard struct {
+	mu         sync.Mutex
+	objectives map[string]*objective
+	display    map[int8]string // display slot → objective name
+	broadcast  func(mcnet.Packet)
+}
+
+func newScoreboard(broadcast func(mcnet.Packet)) *Scoreboard {
+	return &Scoreboard{
+		objectives: make(map[string]*objective),
+		display:    make(map[int8]string),
+		broadcast:  broadcast,
+	}
+}
+
+// AddObjective creates an objective and shows it to all players. It returns
+// false if an objective with that name already exists.
+func (sb *Scoreboard) AddObjective(name, displayName string) bool {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	if _, ok := sb.objectives[name]; ok {
+		return false
+	}
+	sb.objectives[name] = &objective{displayName: displayName, scores: make(map[string]int32)}
+	sb.broadcast(objectivePacket(name, displayName))
+	return true
+}
+
+// SetDisplay shows the named objective in a display slot, or clears the slot
+// if name is empty. It returns false if the objective does not exist.
+func (sb *Scoreboard) SetDisplay(slot int8, name string) bool {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	if name == "" {
+		delete(sb.display, slot)
+	} else {
+		if _, ok := sb.objectives[name]; !ok {
+			return false
+		}
+		sb.display[slot] = name
+	}
+	sb.broadcast(&pkt.ScoreboardDisplayObjective{Position: slot, Name: name})
+	return true
+}
+
+// SetScore sets an entry's score in the named objective. It returns false if
+// the objective does not exist.
+func (sb *Scoreboard) SetScore(objectiveName, entry string, score int32) bool {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	obj, ok := sb.objectives[objectiveName]
+	if !ok {
+		return false
+	}
+	obj.scores[entry] = score
+	sb.broadcast(scorePacket(objectiveName, entry, score))
+	return true
+}
+
+// Score returns an entry's score in the named objective.
+func (sb *Scoreboard) Score(objectiveName, entry string) (int32, bool) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	obj, ok := sb.objectives[objectiveName]
+	if !ok {
+		return 0, false
+	}
+	score, ok := obj.scores[entry]
+	return score, ok
+}
+
+// HasObjective reports whether an objective with the given name exists.
+func (sb *Scoreboard) HasObjective(name string) bool {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	_, ok := sb.objectives[name]
+	return ok
+}
+
+// sendTo sends every objective, score and display slot to a newly joined
+// player.
+func (sb *Scoreboard) sendTo(p *Player) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	names := make([]string, 0, len(sb.objectives))
+	for name := range sb.objectives {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		obj := sb.objectives[name]
+		_ = p.WritePacket(objectivePacket(name, obj.displayName))
+		for entry, score := range obj.scores {
+			_ = p.WritePacket(scorePacket(name, entry, score))
+		}
+	}
+	for slot, name := range sb.display {
+		_ = p.WritePacket(&pkt.ScoreboardDisplayObjective{Position: slot, Name: name})
+	}
+}
+
+// objectivePacket builds a ScoreboardObjective packet creating an integer
+// objective.
+func objectivePacket(name, displayName string) *pkt.ScoreboardObjective {
+	var buf bytes.Buffer
+	_, _ = mcnet.WriteString(&buf, name)
+	buf.WriteByte(objectiveCreate)
+	_, _ = mcnet.WriteString(&buf, displayName)
+	_, _ = mcnet.WriteString(&buf, "integer")
+	return &pkt.ScoreboardObjective{Data: buf.Bytes()}
+}
+
+// scorePacket builds a ScoreboardScore packet setting entry's score.
+func scorePacket(objectiveName, entry string, score int32) *pkt.ScoreboardScore {
+	var buf bytes.Buffer
+	_, _ = mcnet.WriteString(&buf, entry)
+	buf.WriteByte(scoreUpdate)
+	_, _ = mcnet.WriteString(&buf, objectiveName)
+	_, _ = mcnet.WriteVarInt(&buf, score)
+	return &pkt.ScoreboardScore{Data: buf.Bytes()}
+}
@@ -0,0 +1,92 @@
+package player
+
+import (
+	"bytes"
+	"testing"
+
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+)
+
+// scoreboardPackets returns the scoreboard packets among sent, in order.
+func scoreboardPackets(sent []mcnet.Packet) []mcnet.Packet {
+	var out []mcnet.Packet
+	for _, p := range sent {
+		switch p.(type) {
+		case *pkt.ScoreboardObjective, *pkt.ScoreboardScore, *pkt.ScoreboardDisplayObjective:
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func TestScoreboard_BroadcastsPackets(t *testing.T) {
+	m := NewManager(8)
+	p, pc := newTestPlayer(m, 0, 0)
+	m.Add(p)
+	pc.reset()
+	sb := m.Scoreboard()
+
+	if !sb.AddObjective("kills", "Kills") {
+		t.Fatal("AddObjective returned false")
+	}
+	if sb.AddObjective("kills", "Again") {
+		t.Error("AddObjective accepted a duplicate name")
+	}
+	if !sb.SetDisplay(DisplaySidebar, "kills") {
+		t.Fatal("SetDisplay returned false")
+	}
+	if !sb.SetScore("kills", "Alice", 300) {
+		t.Fatal("SetScore returned false")
+	}
+	if sb.SetScore("deaths", "Alice", 1) {
+		t.Error("SetScore accepted an unknown objective")
+	}
+
+	sent := scoreboardPackets(pc.get())
+	if len(sent) != 3 {
+		t.Fatalf("got %d scoreboard packets, want 3", len(sent))
+	}
+
+	obj, ok := sent[0].(*pkt.ScoreboardObjective)
+	wantObj := []byte{5, 'k', 'i', 'l', 'l', 's', 0, 5, 'K', 'i', 'l', 'l', 's', 7, 'i', 'n', 't', 'e', 'g', 'e', 'r'}
+	if !ok || !bytes.Equal(obj.Data, wantObj) {
+		t.Errorf("objective packet = %+v, want data %v", sent[0], wantObj)
+	}
+
+	display, ok := sent[1].(*pkt.ScoreboardDisplayObjective)
+	if !ok || display.Position != DisplaySidebar || display.Name != "kills" {
+		t.Errorf("display packet = %+v, want sidebar showing kills", sent[1])
+	}
+
+	score, ok := sent[2].(*pkt.ScoreboardScore)
+	wantScore := []byte{5, 'A', 'l', 'i', 'c', 'e', 0, 5, 'k', 'i', 'l', 'l', 's', 0xAC, 0x02}
+	if !ok || !bytes.Equal(score.Data, wantScore) {
+		t.Errorf("score packet = %+v, want data %v", sent[2], wantScore)
+	}
+}
+
+func TestScoreboard_SentOnJoin(t *testing.T) {
+	m := NewManager(8)
+	sb := m.Scoreboard()
+	sb.AddObjective("kills", "Kills")
+	sb.SetScore("kills", "Alice", 3)
+	sb.SetDisplay(DisplaySidebar, "kills")
+
+	p, pc := newTestPlayer(m, 0, 0)
+	m.Add(p)
+
+	sent := scoreboardPackets(pc.get())
+	if len(sent) != 3 {
+		t.Fatalf("got %d scoreboard packets on join, want 3", len(sent))
+	}
+	if _, ok := sent[0].(*pkt.ScoreboardObjective); !ok {
+		t.Errorf("first packet = %T, want the objective before its scores", sent[0])
+	}
+	if _, ok := sent[1].(*pkt.ScoreboardScore); !ok {
+		t.Errorf("second packet = %T, want the score", sent[1])
+	}
+	if _, ok := sent[2].(*pkt.ScoreboardDisplayObjective); !ok {
+		t.Errorf("third packet = %T, want the display slot", sent[2])
+	}
+}
@@ -0,0 +1,49 @@
+package player
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/go-theft-craft/server/internal/server/chat"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+)
+
+// Title packet actions.
+const (
+	titleSetTitle    = 0
+	titleSetSubtitle = 1
+	titleSetTimes    = 2
+)
+
+// Default title timings in ticks, as in vanilla.
+const (
+	DefaultTitleFadeIn  = 10
+	DefaultTitleStay    = 70
+	DefaultTitleFadeOut = 20
+)
+
+// TitlePackets returns the Title packets that show title and subtitle in
+// the middle of the screen: the timings, then the subtitle, then the title,
+// which makes the client display both. An empty subtitle is omitted.
+func TitlePackets(title, subtitle string, fadeIn, stay, fadeOut int32) []mcnet.Packet {
+	var times bytes.Buffer
+	_, _ = mcnet.WriteVarInt(&times, titleSetTimes)
+	_ = binary.Write(&times, binary.BigEndian, fadeIn)
+	_ = binary.Write(&times, binary.BigEndian, stay)
+	_ = binary.Write(&times, binary.BigEndian, fadeOut)
+	packets := []mcnet.Packet{&pkt.Title{Data: times.Bytes()}}
+
+	if subtitle != "" {
+		packets = append(packets, titleTextPacket(titleSetSubtitle, subtitle))
+	}
+	return append(packets, titleTextPacket(titleSetTitle, title))
+}
+
+// titleTextPacket builds a Title packet setting the title or subtitle text.
+func titleTextPacket(action int32, text string) *pkt.Title {
+	var buf bytes.Buffer
+	_, _ = mcnet.WriteVarInt(&buf, action)
+	_, _ = mcnet.WriteString(&buf, chat.Text(text).String())
+	return &pkt.Title{Data: buf.Bytes()}
+}
@@ -0,0 +1,40 @@
+package player
+
+import (
+	"bytes"
+	"testing"
+
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+)
+
+func TestTitlePackets(t *testing.T) {
+	packets := TitlePackets("Hello", "World", 10, 70, 20)
+	if len(packets) != 3 {
+		t.Fatalf("got %d packets, want times, subtitle and title", len(packets))
+	}
+
+	want := [][]byte{
+		{2, 0, 0, 0, 10, 0, 0, 0, 70, 0, 0, 0, 20},
+		append([]byte{1, 16}, `{"text":"World"}`...),
+		append([]byte{0, 16}, `{"text":"Hello"}`...),
+	}
+	for i, p := range packets {
+		title, ok := p.(*pkt.Title)
+		if !ok {
+			t.Fatalf("packet %d = %T, want Title", i, p)
+		}
+		if !bytes.Equal(title.Data, want[i]) {
+			t.Errorf("packet %d data = %v, want %v", i, title.Data, want[i])
+		}
+	}
+}
+
+func TestTitlePackets_NoSubtitle(t *testing.T) {
+	packets := TitlePackets("Hello", "", 10, 70, 20)
+	if len(packets) != 2 {
+		t.Fatalf("got %d packets, want times and title", len(packets))
+	}
+	if data := packets[1].(*pkt.Title).Data; data[0] != titleSetTitle {
+		t.Errorf("last packet action = %d, want set title", data[0])
+	}
+}
@@ -0,0 +1,32 @@
+package player
+
+import (
+	"github.com/go-theft-craft/server/internal/server/packet"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+	"github.com/go-theft-craft/server/pkg/world"
+)
+
+// WeatherPackets returns the GameStateChange packets that show wt on a
+// client: rain starting or stopping, then the rain and thunder levels.
+func WeatherPackets(wt world.Weather) []mcnet.Packet {
+	reason, rain, thunder := packet.GameStateEndRain, float32(0), float32(0)
+	if wt != world.WeatherClear {
+		reason, rain = packet.GameStateBeginRain, 1
+	}
+	if wt == world.WeatherThunder {
+		thunder = 1
+	}
+	return []mcnet.Packet{
+		&pkt.GameStateChange{Reason: reason},
+		&pkt.GameStateChange{Reason: packet.GameStateRainLevel, GameMode: rain},
+		&pkt.GameStateChange{Reason: packet.GameStateThunderLevel, GameMode: thunder},
+	}
+}
+
+// BroadcastWeather shows wt to every player.
+func (m *Manager) BroadcastWeather(wt world.Weather) {
+	for _, p := range WeatherPackets(wt) {
+		m.Broadcast(p)
+	}
+}
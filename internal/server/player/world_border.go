@@ -0,0 +1,37 @@
+package player
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+	"github.com/go-theft-craft/server/pkg/world"
+)
+
+// worldBorderInitialize is the WorldBorder action that sets every field.
+const worldBorderInitialize = 3
+
+// portalTeleportBoundary is the vanilla limit on where portals may place
+// players, sent alongside the border.
+const portalTeleportBoundary = 29999984
+
+// WorldBorderPacket returns the WorldBorder packet that shows b on a client.
+func WorldBorderPacket(b world.Border) *pkt.WorldBorder {
+	var buf bytes.Buffer
+	_, _ = mcnet.WriteVarInt(&buf, worldBorderInitialize)
+	_ = binary.Write(&buf, binary.BigEndian, b.CenterX)
+	_ = binary.Write(&buf, binary.BigEndian, b.CenterZ)
+	_ = binary.Write(&buf, binary.BigEndian, b.Diameter) // old diameter
+	_ = binary.Write(&buf, binary.BigEndian, b.Diameter) // new diameter
+	_, _ = mcnet.WriteVarLong(&buf, 0)                   // no resize in progress
+	_, _ = mcnet.WriteVarInt(&buf, portalTeleportBoundary)
+	_, _ = mcnet.WriteVarInt(&buf, b.WarningTime)
+	_, _ = mcnet.WriteVarInt(&buf, b.WarningBlocks)
+	return &pkt.WorldBorder{Data: buf.Bytes()}
+}
+
+// BroadcastWorldBorder shows b to every player.
+func (m *Manager) BroadcastWorldBorder(b world.Border) {
+	m.Broadcast(WorldBorderPacket(b))
+}
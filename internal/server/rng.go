@@ -0,0 +1,66 @@
+package server
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// rngStream identifies an independent random stream derived from the world
+// seed. Each subsystem draws from its own stream so that adding randomness in
+// one place does not shift the sequence seen by another.
+type rngStream uint64
+
+const (
+	streamWeather rngStream = iota + 1
+	streamRandomTick
+	streamSpawn
+	streamScheduled
+	streamWeatherCycle
+	streamMobAI
+	streamDrops
+)
+
+// newStreamRNG returns a deterministic random source for stream, derived from
+// the world seed. The same seed always yields the same sequence per stream.
+func newStreamRNG(seed int64, stream rngStream) *rand.Rand {
+	return rand.New(rand.NewSource(int64(splitMix64(uint64(seed) ^ uint64(stream)*0x9E3779B97F4A7C15))))
+}
+
+// newLockedStreamRNG is like newStreamRNG but safe for concurrent use, for
+// streams shared by every connection.
+func newLockedStreamRNG(seed int64, stream rngStream) *rand.Rand {
+	src := rand.NewSource(int64(splitMix64(uint64(seed) ^ uint64(stream)*0x9E3779B97F4A7C15)))
+	return rand.New(&lockedSource{src: src.(rand.Source64)})
+}
+
+// lockedSource serializes access to a random source.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source64
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
+// splitMix64 scrambles x so that nearby seeds produce unrelated streams.
+func splitMix64(x uint64) uint64 {
+	x += 0x9E3779B97F4A7C15
+	x = (x ^ (x >> 30)) * 0xBF58476D1CE4E5B9
+	x = (x ^ (x >> 27)) * 0x94D049BB133111EB
+	return x ^ (x >> 31)
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-theft-craft/server/internal/server/config"
+	"github.com/go-theft-craft/server/internal/server/player"
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+	"github.com/go-theft-craft/server/pkg/world"
+	"github.com/go-theft-craft/server/pkg/world/gen"
+)
+
+// snowyGenerator produces ice plains with alternating water and grass
+// columns, so rain turns into both ice and snow layers.
+type snowyGenerator struct{}
+
+func (snowyGenerator) Generate(_, _ int) *gen.ChunkData {
+	c := &gen.ChunkData{}
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			c.SetBlock(x, 3, z, 3<<4)
+			if x%2 == 0 {
+				c.SetBlock(x, 4, z, 9<<4)
+			} else {
+				c.SetBlock(x, 4, z, 2<<4)
+			}
+			c.SetBiome(x, z, 12) // ice plains
+		}
+	}
+	return c
+}
+
+func (snowyGenerator) HeightAt(_, _ int) int           { return 4 }
+func (snowyGenerator) HeightsFor(_, _ int) [16][16]int { return gen.UniformHeights(4) }
+
+// newSeededServer creates a server with the given seed over snowy terrain,
+// with one player whose received block changes are returned.
+func newSeededServer(seed int64) (*Server, *[]*pkt.BlockChange) {
+	cfg := config.DefaultConfig()
+	cfg.Seed = seed
+	cfg.ViewDistance = 2
+	s := mustNew(cfg)
+	s.world = world.NewWorld(snowyGenerator{})
+	s.world.SetRaining(true)
+
+	var changes []*pkt.BlockChange
+	eid := s.players.AllocateEntityID()
+	p := player.NewPlayer(eid, "uuid-Alice", [16]byte{byte(eid)}, "Alice", nil, func(p mcnet.Packet) error {
+		if bc, ok := p.(*pkt.BlockChange); ok {
+			changes = append(changes, bc)
+		}
+		return nil
+	})
+	p.SetPosition(0.5, 5, 0.5, 0, 0, true)
+	s.players.Add(p)
+	return s, &changes
+}
+
+// weatherTransitions runs n ticks and returns the block changes broadcast.
+func weatherTransitions(seed int64, n int) []*pkt.BlockChange {
+	s, changes := newSeededServer(seed)
+	for i := 1; i <= n; i++ {
+		s.tick(i)
+	}
+	return *changes
+}
+
+func TestSameSeedSameWeatherTransitions(t *testing.T) {
+	a := weatherTransitions(42, 200)
+	b := weatherTransitions(42, 200)
+	if len(a) == 0 {
+		t.Fatal("expected rain to produce block changes")
+	}
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("same seed produced different weather: %d vs %d changes", len(a), len(b))
+	}
+	if reflect.DeepEqual(a, weatherTransitions(43, 200)) {
+		t.Error("different seeds produced identical weather")
+	}
+}
+
+func TestSplitMix64(t *testing.T) {
+	// Reference outputs of SplitMix64 seeded with 0.
+	tests := []struct {
+		in, want uint64
+	}{
+		{0, 0xe220a8397b1dcdaf},
+		{0x9e3779b97f4a7c15, 0x6e789e6aa1b965f4},
+	}
+	for _, tt := range tests {
+		if got := splitMix64(tt.in); got != tt.want {
+			t.Errorf("splitMix64(%#x) = %#x, want %#x", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNewStreamRNG_FixedSequence(t *testing.T) {
+	tests := []struct {
+		stream rngStream
+		want   int64
+	}{
+		{streamWeather, 7239747565411737270},
+		{streamRandomTick, 776768108838398113},
+		{streamSpawn, 8437587652757058452},
+	}
+	for _, tt := range tests {
+		if got := newStreamRNG(42, tt.stream).Int63(); got != tt.want {
+			t.Errorf("stream %d: first Int63 = %d, want %d", tt.stream, got, tt.want)
+		}
+	}
+}
+
+func TestStreamsAreIndependent(t *testing.T) {
+	w := newStreamRNG(42, streamWeather)
+	r := newStreamRNG(42, streamRandomTick)
+	if w.Int63() == r.Int63() {
+		t.Error("weather and random tick streams should differ for the same seed")
+	}
+}
+
+// spawnPlacements makes n natural spawn attempts and returns where each
+// spawned mob was placed.
+func spawnPlacements(seed int64, n int) []string {
+	s, _ := newSeededServer(seed)
+	var placed []string
+	for i := 0; i < n; i++ {
+		if me := s.players.SpawnNaturalMob(s.world, s.gameData, s.activeChunks(), s.spawnRNG); me != nil {
+			placed = append(placed, fmt.Sprintf("%s@%.1f,%.1f,%.1f", me.Kind, me.X, me.Y, me.Z))
+		}
+	}
+	return placed
+}
+
+func TestSameSeedSameMobSpawns(t *testing.T) {
+	a := spawnPlacements(42, 100)
+	b := spawnPlacements(42, 100)
+	if len(a) == 0 {
+		t.Fatal("expected at least one mob to spawn")
+	}
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("same seed produced different spawns:\n%v\n%v", a, b)
+	}
+	if reflect.DeepEqual(a, spawnPlacements(43, 100)) {
+		t.Error("different seeds produced identical spawns")
+	}
+}
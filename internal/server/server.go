@@ -4,7 +4,11 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"math/rand"
 	"net"
+	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-theft-craft/server/internal/server/config"
@@ -12,11 +16,17 @@ import (
 	"github.com/go-theft-craft/server/internal/server/player"
 	"github.com/go-theft-craft/server/internal/server/storage"
 	"github.com/go-theft-craft/server/pkg/gamedata"
+	_ "github.com/go-theft-craft/server/pkg/gamedata/versions"
 	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
 	"github.com/go-theft-craft/server/pkg/world"
 	"github.com/go-theft-craft/server/pkg/world/gen"
 )
 
+// tcpKeepAlivePeriod is the interval between TCP keepalive probes on
+// accepted connections.
+const tcpKeepAlivePeriod = 15 * time.Second
+
 // Server is the main Minecraft server that accepts TCP connections.
 type Server struct {
 	cfg      *config.Config
@@ -25,33 +35,126 @@ type Server struct {
 	players  *player.Manager
 	storage  *storage.Storage
 	gameData *gamedata.GameData
+
+	// Per-subsystem random streams derived from cfg.Seed, so server
+	// behavior is reproducible for a given seed.
+	weatherRNG    *rand.Rand
+	randomTickRNG *rand.Rand
+	spawnRNG      *rand.Rand
+	dropRNG       *rand.Rand // shared by connections, safe for concurrent use
+	scheduledRNG  *rand.Rand
+	cycleRNG      *rand.Rand // weather cycle
+	mobRNG        *rand.Rand // mob wandering
+
+	// Tick timing, reported by /tps.
+	tickStats *tickStats
+
+	// cancel stops the server; set by Start.
+	cancel context.CancelFunc
 }
 
-// New creates a new Server with the given config, logger, and storage.
-func New(cfg *config.Config, log *slog.Logger, store *storage.Storage) *Server {
+// New creates a new Server with the given config, logger, and storage. It
+// fails if the configured game version is not registered. The server keeps
+// its own copy of cfg, so defaults it fills in do not leak back to the
+// caller.
+func New(cfg *config.Config, log *slog.Logger, store *storage.Storage) (*Server, error) {
+	own := *cfg
+	cfg = &own
+
+	gd, err := gamedata.Load(cfg.GameVersion)
+	if err != nil {
+		return nil, fmt.Errorf("load game data: %w (available: %s)",
+			err, strings.Join(gamedata.RegisteredVersions(), ", "))
+	}
+
 	var generator gen.Generator
 	switch cfg.GeneratorType {
 	case config.GeneratorFlat:
-		generator = gen.NewFlatGenerator(cfg.Seed)
+		generator = newFlatGenerator(cfg, gd, log)
 	default:
 		generator = gen.NewDefaultGenerator(cfg.Seed)
 	}
 
-	gd := pkt.New()
+	if cfg.RandomTickSpeed < 0 {
+		log.Warn("random tick speed must not be negative, using default",
+			"value", cfg.RandomTickSpeed, "default", world.DefaultRandomTickSpeed)
+		cfg.RandomTickSpeed = world.DefaultRandomTickSpeed
+	}
+
+	player.SetItemNBTEnabled(cfg.SendItemNBT)
+
+	w := world.NewWorld(generator)
+	w.SetGameData(gd)
+	if cfg.WorldRadius > 0 {
+		w.SetDefaultBorder(world.RadiusBorder(cfg.WorldRadius))
+	}
+	if store != nil {
+		w.SetChunkLoader(store.ChunkLoader())
+	}
+
+	players := player.NewManager(cfg.ViewDistance)
+	players.SetDespawnSeconds(cfg.DespawnSeconds)
 
 	return &Server{
 		cfg:      cfg,
 		log:      log,
-		world:    world.NewWorld(generator),
-		players:  player.NewManager(cfg.ViewDistance),
+		world:    w,
+		players:  players,
 		storage:  store,
 		gameData: gd,
+
+		weatherRNG:    newStreamRNG(cfg.Seed, streamWeather),
+		randomTickRNG: newStreamRNG(cfg.Seed, streamRandomTick),
+		spawnRNG:      newStreamRNG(cfg.Seed, streamSpawn),
+		dropRNG:       newLockedStreamRNG(cfg.Seed, streamDrops),
+		scheduledRNG:  newStreamRNG(cfg.Seed, streamScheduled),
+		cycleRNG:      newStreamRNG(cfg.Seed, streamWeatherCycle),
+		mobRNG:        newStreamRNG(cfg.Seed, streamMobAI),
+
+		tickStats: newTickStats(),
+	}, nil
+}
+
+// preGenerate generates the whole world when it is bounded and not yet saved,
+// then pins the spawn chunks so they stay loaded for fast joins and respawns.
+func (s *Server) preGenerate() {
+	if s.cfg.WorldRadius > 0 {
+		if s.storage != nil && s.storage.HasSavedWorld() {
+			s.log.Info("world already saved, skipping pre-generation")
+		} else {
+			total := (2*s.cfg.WorldRadius + 1) * (2*s.cfg.WorldRadius + 1)
+			s.log.Info("pre-generating world", "radius", s.cfg.WorldRadius, "chunks", total)
+			s.world.PreGenerateRadius(s.cfg.WorldRadius)
+			s.log.Info("world pre-generation complete")
+		}
+	}
+
+	if radius := s.cfg.SpawnChunkRadius; radius > 0 {
+		n := s.world.PreGenerateSpawnChunks(radius)
+		s.log.Info("spawn chunks loaded", "radius", radius, "chunks", n)
 	}
 }
 
+// newFlatGenerator returns a flat generator using the configured layers, or
+// the classic layers if none are configured or they do not parse.
+func newFlatGenerator(cfg *config.Config, gd *gamedata.GameData, log *slog.Logger) gen.Generator {
+	if cfg.FlatLayers == "" {
+		return gen.NewFlatGenerator(cfg.Seed)
+	}
+	layers, err := gen.ParseFlatLayers(cfg.FlatLayers, gd.Blocks)
+	if err != nil {
+		log.Warn("invalid flat layers, using default", "layers", cfg.FlatLayers, "error", err)
+		return gen.NewFlatGenerator(cfg.Seed)
+	}
+	return gen.NewFlatGeneratorLayers(layers)
+}
+
 // Start begins listening for connections and blocks until the context is cancelled.
 func (s *Server) Start(ctx context.Context) error {
-	// Load saved world data (time + block overrides).
+	ctx, s.cancel = context.WithCancel(ctx)
+	defer s.cancel()
+
+	// Load saved world data (time + block and biome overrides).
 	if s.storage != nil {
 		if err := s.storage.LoadWorld(s.world); err != nil {
 			s.log.Error("failed to load world data", "error", err)
@@ -59,6 +162,25 @@ func (s *Server) Start(ctx context.Context) error {
 		if err := s.storage.LoadBlockOverrides(s.world); err != nil {
 			s.log.Error("failed to load block overrides", "error", err)
 		}
+		if err := s.storage.LoadBiomeOverrides(s.world); err != nil {
+			s.log.Error("failed to load biome overrides", "error", err)
+		}
+		if err := s.storage.LoadSigns(s.world); err != nil {
+			s.log.Error("failed to load signs", "error", err)
+		}
+		if err := s.storage.LoadContainers(s.players); err != nil {
+			s.log.Error("failed to load containers", "error", err)
+		}
+		if err := s.storage.LoadFurnaces(s.players); err != nil {
+			s.log.Error("failed to load furnaces", "error", err)
+		}
+		ops, err := s.storage.LoadOps()
+		if err != nil {
+			s.log.Error("failed to load ops", "error", err)
+		}
+		if len(ops) == 0 && len(s.cfg.Ops) == 0 {
+			s.log.Warn("no operators configured; add a username to ops in config.json to run admin commands")
+		}
 	}
 
 	addr := fmt.Sprintf(":%d", s.cfg.Port)
@@ -70,16 +192,7 @@ func (s *Server) Start(ctx context.Context) error {
 	}
 	defer listener.Close()
 
-	if s.cfg.WorldRadius > 0 {
-		if s.storage != nil && s.storage.HasSavedWorld() {
-			s.log.Info("world already saved, skipping pre-generation")
-		} else {
-			total := (2*s.cfg.WorldRadius + 1) * (2*s.cfg.WorldRadius + 1)
-			s.log.Info("pre-generating world", "radius", s.cfg.WorldRadius, "chunks", total)
-			s.world.PreGenerateRadius(s.cfg.WorldRadius)
-			s.log.Info("world pre-generation complete")
-		}
-	}
+	s.preGenerate()
 
 	s.log.Info("server started",
 		"port", s.cfg.Port,
@@ -92,6 +205,9 @@ func (s *Server) Start(ctx context.Context) error {
 	// Start tick loop (20 TPS).
 	go s.tickLoop(ctx)
 
+	// Read operator commands from the terminal.
+	go s.readConsole(ctx, os.Stdin, os.Stdout)
+
 	// Start auto-save goroutine.
 	if s.storage != nil && s.cfg.AutoSaveMinutes > 0 {
 		go s.autoSave(ctx)
@@ -115,37 +231,80 @@ func (s *Server) Start(ctx context.Context) error {
 			continue
 		}
 
+		enableTCPKeepAlive(c)
 		connection := conn.NewConnection(ctx, c, s.cfg, s.log, s.world, s.players, s.storage, s.gameData)
 		connection.SaveAll = s.SaveAll
+		connection.DropRNG = s.dropRNG
+		connection.TickStats = s.TickStats
 		go connection.Handle()
 	}
 }
 
+// enableTCPKeepAlive turns on TCP keepalive probes so half-open connections
+// (peer gone without a FIN) are detected by the OS.
+func enableTCPKeepAlive(c net.Conn) {
+	tc, ok := c.(*net.TCPConn)
+	if !ok {
+		return
+	}
+	_ = tc.SetKeepAlive(true)
+	_ = tc.SetKeepAlivePeriod(tcpKeepAlivePeriod)
+}
+
+// latencyUpdateTicks is how often the tab list pings are refreshed.
+const latencyUpdateTicks = 5 * targetTPS
+
 // tickLoop runs the server tick at 20 TPS (50ms interval).
 func (s *Server) tickLoop(ctx context.Context) {
-	ticker := time.NewTicker(50 * time.Millisecond)
+	ticker := time.NewTicker(tickInterval)
 	defer ticker.Stop()
 
 	var tickCount int
+	last := time.Now()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
+		case start := <-ticker.C:
 			tickCount++
 			s.tick(tickCount)
+			s.tickStats.record(start.Sub(last), time.Since(start))
+			last = start
 		}
 	}
 }
 
+// TickStats is exposed for the /tps command to report the current ticks per
+// second and average milliseconds per tick.
+func (s *Server) TickStats() (tps, msPerTick float64) {
+	return s.tickStats.get()
+}
+
 // tick advances the world by one tick and broadcasts time every 20 ticks (~1 second).
 func (s *Server) tick(tickCount int) {
 	s.players.Tick()
-	age, timeOfDay := s.world.Tick()
+	s.world.Tick()
+	if wt, changed := s.world.TickWeatherCycle(s.cycleRNG); changed {
+		s.players.BroadcastWeather(wt)
+	}
+
+	chunks := s.activeChunks()
+	s.broadcastBlockUpdates(s.world.TickWeather(chunks, s.weatherRNG))
+	s.broadcastBlockUpdates(s.world.RandomTick(chunks, s.randomTickRNG, s.cfg.RandomTickSpeed))
+	s.breakBlocks(s.world.TickScheduled(s.scheduledRNG))
+	s.broadcastBlockUpdates(s.world.TickFlow())
+	s.players.SpawnNaturalMob(s.world, s.gameData, chunks, s.spawnRNG)
+	s.players.TickMobs(s.world, s.mobRNG)
+	s.players.TickArrows(s.world)
+
+	if tickCount%latencyUpdateTicks == 0 {
+		s.players.BroadcastLatency()
+	}
 
 	// Broadcast time update every 20 ticks (once per second).
 	if tickCount%20 == 0 {
+		age, timeOfDay := s.world.ClientTime()
 		s.players.Broadcast(&pkt.UpdateTime{
 			Age:  age,
 			Time: timeOfDay,
@@ -153,6 +312,43 @@ func (s *Server) tick(tickCount int) {
 	}
 }
 
+// broadcastBlockUpdates sends a BlockChange to every player for each update.
+func (s *Server) broadcastBlockUpdates(updates []world.BlockUpdate) {
+	for _, u := range updates {
+		s.players.Broadcast(&pkt.BlockChange{
+			Location: mcnet.EncodePosition(u.Pos.X, u.Pos.Y, u.Pos.Z),
+			Type:     u.State,
+		})
+	}
+}
+
+// activeChunks returns the chunks within view distance of any online player,
+// sorted so that per-tick world simulation visits them in a stable order.
+func (s *Server) activeChunks() []gen.ChunkPos {
+	seen := make(map[gen.ChunkPos]struct{})
+	r := s.cfg.ViewDistance
+	s.players.ForEach(func(p *player.Player) {
+		pcx, pcz := p.ChunkX(), p.ChunkZ()
+		for cx := pcx - r; cx <= pcx+r; cx++ {
+			for cz := pcz - r; cz <= pcz+r; cz++ {
+				seen[gen.ChunkPos{X: cx, Z: cz}] = struct{}{}
+			}
+		}
+	})
+
+	chunks := make([]gen.ChunkPos, 0, len(seen))
+	for pos := range seen {
+		chunks = append(chunks, pos)
+	}
+	sort.Slice(chunks, func(i, j int) bool {
+		if chunks[i].X != chunks[j].X {
+			return chunks[i].X < chunks[j].X
+		}
+		return chunks[i].Z < chunks[j].Z
+	})
+	return chunks
+}
+
 // autoSave periodically saves world and player data.
 func (s *Server) autoSave(ctx context.Context) {
 	ticker := time.NewTicker(time.Duration(s.cfg.AutoSaveMinutes) * time.Minute)
@@ -186,6 +382,30 @@ func (s *Server) saveAll() {
 		s.log.Info("block overrides saved")
 	}
 
+	if err := s.storage.SaveBiomeOverrides(s.world); err != nil {
+		s.log.Error("auto-save biome overrides failed", "error", err)
+	} else {
+		s.log.Info("biome overrides saved")
+	}
+
+	if err := s.storage.SaveSigns(s.world); err != nil {
+		s.log.Error("auto-save signs failed", "error", err)
+	} else {
+		s.log.Info("signs saved")
+	}
+
+	if err := s.storage.SaveContainers(s.players); err != nil {
+		s.log.Error("auto-save containers failed", "error", err)
+	} else {
+		s.log.Info("containers saved")
+	}
+
+	if err := s.storage.SaveFurnaces(s.players); err != nil {
+		s.log.Error("auto-save furnaces failed", "error", err)
+	} else {
+		s.log.Info("furnaces saved")
+	}
+
 	if err := s.storage.SaveWorldAnvil(s.world); err != nil {
 		s.log.Error("auto-save anvil failed", "error", err)
 	} else {
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/go-theft-craft/server/internal/server/config"
+	"github.com/go-theft-craft/server/pkg/world"
+)
+
+func TestNewRejectsNegativeRandomTickSpeed(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.RandomTickSpeed = -1
+
+	s := mustNew(cfg)
+
+	if s.cfg.RandomTickSpeed != world.DefaultRandomTickSpeed {
+		t.Errorf("RandomTickSpeed = %d, want default %d", s.cfg.RandomTickSpeed, world.DefaultRandomTickSpeed)
+	}
+	if cfg.RandomTickSpeed != -1 {
+		t.Errorf("caller's RandomTickSpeed = %d, want it left at -1", cfg.RandomTickSpeed)
+	}
+}
+
+func TestNewRejectsUnknownGameVersion(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.GameVersion = "pc-0.0"
+
+	s, err := New(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+
+	if err == nil || s != nil {
+		t.Fatalf("New() = %v, %v; want an error for an unknown game version", s, err)
+	}
+	if !strings.Contains(err.Error(), config.DefaultGameVersion) {
+		t.Errorf("error %q does not list the available versions", err)
+	}
+}
+
+// mustNew creates a server from cfg without storage, panicking if cfg is
+// invalid.
+func mustNew(cfg *config.Config) *Server {
+	s, err := New(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func TestPreGenerateSpawnChunks(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.GeneratorType = config.GeneratorFlat
+	cfg.WorldRadius = 0
+	cfg.SpawnChunkRadius = 2
+	s := mustNew(cfg)
+
+	s.preGenerate()
+
+	if generated := len(s.world.LoadedChunkPositions()); generated != 25 {
+		t.Errorf("generated %d chunks, want 25", generated)
+	}
+	for cx := -2; cx <= 2; cx++ {
+		for cz := -2; cz <= 2; cz++ {
+			if !s.world.IsSpawnChunk(cx, cz) {
+				t.Errorf("chunk (%d, %d) is not a spawn chunk", cx, cz)
+			}
+		}
+	}
+	if s.world.IsSpawnChunk(3, 0) {
+		t.Error("chunk (3, 0) is outside the spawn radius")
+	}
+}
+
+func TestPreGenerateSpawnChunks_ClampedToWorldRadius(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.GeneratorType = config.GeneratorFlat
+	cfg.WorldRadius = 1
+	cfg.SpawnChunkRadius = 4
+	s := mustNew(cfg)
+
+	s.preGenerate()
+
+	if s.world.IsSpawnChunk(2, 0) {
+		t.Error("spawn chunks extend past the world border")
+	}
+	if !s.world.IsSpawnChunk(1, 1) {
+		t.Error("chunk (1, 1) should be a spawn chunk")
+	}
+}
+
+func TestDecayDropsSaplingWoodType(t *testing.T) {
+	s := mustNew(config.DefaultConfig())
+
+	tests := []struct {
+		state int32
+		want  int16
+	}{
+		{18<<4 | 1, 1},     // spruce leaves
+		{18<<4 | 2 | 8, 2}, // birch leaves, check-decay bit set
+		{161<<4 | 1, 5},    // dark oak leaves
+	}
+	for _, tt := range tests {
+		found := false
+		for i := 0; i < 1000 && !found; i++ {
+			for _, d := range s.decayDrops(tt.state) {
+				if d.BlockID != 6 { // sapling
+					continue
+				}
+				if d.ItemDamage != tt.want {
+					t.Fatalf("state %d: sapling damage = %d, want %d", tt.state, d.ItemDamage, tt.want)
+				}
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("state %d: no sapling dropped in 1000 rolls", tt.state)
+		}
+	}
+}
+
+func TestNewFlatLayers(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.GeneratorType = config.GeneratorFlat
+	cfg.FlatLayers = "minecraft:bedrock,9*minecraft:stone"
+	s := mustNew(cfg)
+
+	if got := s.world.GetBlock(0, 9, 0); got != 1<<4 {
+		t.Errorf("block at y=9 = %d, want stone", got)
+	}
+	if got := s.world.GetBlock(0, 10, 0); got != 0 {
+		t.Errorf("block at y=10 = %d, want air", got)
+	}
+}
+
+func TestNewFlatLayersInvalidFallsBack(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.GeneratorType = config.GeneratorFlat
+	cfg.FlatLayers = "minecraft:bedrock,3*nope"
+	s := mustNew(cfg)
+
+	if got := s.world.GetBlock(0, 4, 0); got != 2<<4 {
+		t.Errorf("block at y=4 = %d, want the default layers' grass", got)
+	}
+}
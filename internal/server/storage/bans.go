@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// BanEntry is a single player ban in banned-players.json.
+type BanEntry struct {
+	UUID    string    `json:"uuid,omitempty"` // empty when banned by name only
+	Name    string    `json:"name"`
+	Reason  string    `json:"reason,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// Matches reports whether the ban applies to the player with the given UUID
+// or username. Names are compared case-insensitively.
+func (b BanEntry) Matches(uuid, name string) bool {
+	return (b.UUID != "" && b.UUID == uuid) || strings.EqualFold(b.Name, name)
+}
+
+// LoadBans reads banned-players.json and caches the list for Bans. A missing
+// file is an empty ban list.
+func (s *Storage) LoadBans() ([]BanEntry, error) {
+	path := filepath.Join(s.dir, "banned-players.json")
+	data, err := s.readData(KindConfig, path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read ban list: %w", err)
+	}
+
+	var bans []BanEntry
+	if err == nil {
+		if err := json.Unmarshal(data, &bans); err != nil {
+			return nil, fmt.Errorf("parse ban list: %w", err)
+		}
+	}
+
+	s.bansMu.Lock()
+	s.bans, s.bansLoaded = bans, true
+	s.bansMu.Unlock()
+	return slices.Clone(bans), nil
+}
+
+// Bans returns the ban list, reading banned-players.json only the first time.
+func (s *Storage) Bans() ([]BanEntry, error) {
+	s.bansMu.Lock()
+	if s.bansLoaded {
+		defer s.bansMu.Unlock()
+		return slices.Clone(s.bans), nil
+	}
+	s.bansMu.Unlock()
+	return s.LoadBans()
+}
+
+// SaveBans writes the ban list to banned-players.json atomically and caches
+// it for Bans.
+func (s *Storage) SaveBans(bans []BanEntry) error {
+	if bans == nil {
+		bans = []BanEntry{}
+	}
+	path := filepath.Join(s.dir, "banned-players.json")
+	if err := s.atomicWriteJSON(KindConfig, path, bans); err != nil {
+		return err
+	}
+
+	s.bansMu.Lock()
+	s.bans, s.bansLoaded = slices.Clone(bans), true
+	s.bansMu.Unlock()
+	return nil
+}
+
+// FindBan returns the first ban matching the given UUID or username.
+func FindBan(bans []BanEntry, uuid, name string) (BanEntry, bool) {
+	for _, b := range bans {
+		if b.Matches(uuid, name) {
+			return b, true
+		}
+	}
+	return BanEntry{}, false
+}
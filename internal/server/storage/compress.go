@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// File kinds that can be compressed independently via cfg.CompressSaves.
+const (
+	KindConfig  = "config"  // config.json
+	KindWorld   = "world"   // world/*.json
+	KindPlayers = "players" // players/*.json
+)
+
+// gzipExt is appended to the file name of compressed saves.
+const gzipExt = ".gz"
+
+// gzipMagic is the two-byte header every gzip stream starts with.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// SetCompressSaves configures which file kinds are gzip-compressed on write.
+// spec is a comma-separated list of kinds, "all", or empty for none. Reads
+// always accept both plain and compressed files.
+func (s *Storage) SetCompressSaves(spec string) {
+	s.compress = make(map[string]bool)
+	for _, kind := range strings.Split(spec, ",") {
+		kind = strings.TrimSpace(kind)
+		switch kind {
+		case "":
+		case "all":
+			s.compress[KindConfig] = true
+			s.compress[KindWorld] = true
+			s.compress[KindPlayers] = true
+		default:
+			s.compress[kind] = true
+		}
+	}
+}
+
+// readData reads the file at path, falling back to its .gz variant, and
+// decompresses the content if it is gzip-encoded. A missing file returns an
+// error satisfying os.IsNotExist.
+func (s *Storage) readData(kind, path string) ([]byte, error) {
+	first, second := path, path+gzipExt
+	if s.compress[kind] {
+		first, second = second, first
+	}
+
+	data, err := os.ReadFile(first)
+	if os.IsNotExist(err) {
+		data, err = os.ReadFile(second)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("open gzip: %w", err)
+	}
+	defer zr.Close()
+	data, err = io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("decompress: %w", err)
+	}
+	return data, nil
+}
+
+// exists reports whether the file at path or its .gz variant exists.
+func exists(path string) bool {
+	if _, err := os.Stat(path); err == nil {
+		return true
+	}
+	_, err := os.Stat(path + gzipExt)
+	return err == nil
+}
+
+// gzipData compresses data with gzip.
+func gzipData(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
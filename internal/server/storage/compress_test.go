@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-theft-craft/server/internal/server/config"
+)
+
+func TestConfig_GzipRoundTrip(t *testing.T) {
+	s := newTestStorage(t)
+	s.SetCompressSaves("config")
+
+	cfg := config.DefaultConfig()
+	cfg.MOTD = "compressed"
+	cfg.Seed = 42
+	if err := s.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, "config.json.gz"))
+	if err != nil {
+		t.Fatalf("read compressed config: %v", err)
+	}
+	if !bytes.HasPrefix(data, gzipMagic) {
+		t.Error("config.json.gz is not gzip-encoded")
+	}
+	if _, err := os.Stat(filepath.Join(s.dir, "config.json")); !os.IsNotExist(err) {
+		t.Error("expected no plain config.json alongside the compressed one")
+	}
+
+	loaded := config.DefaultConfig()
+	if err := s.LoadConfig(loaded); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if loaded.MOTD != "compressed" || loaded.Seed != 42 {
+		t.Errorf("loaded MOTD=%q Seed=%d, want %q 42", loaded.MOTD, loaded.Seed, "compressed")
+	}
+}
+
+func TestConfig_PlainStillLoads(t *testing.T) {
+	s := newTestStorage(t)
+	s.SetCompressSaves("all")
+
+	plain := []byte(`{"motd": "plain", "max_players": 7}`)
+	if err := os.WriteFile(filepath.Join(s.dir, "config.json"), plain, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	if err := s.LoadConfig(cfg); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.MOTD != "plain" || cfg.MaxPlayers != 7 {
+		t.Errorf("loaded MOTD=%q MaxPlayers=%d, want %q 7", cfg.MOTD, cfg.MaxPlayers, "plain")
+	}
+}
+
+func TestReadData_DetectsGzipByMagic(t *testing.T) {
+	s := newTestStorage(t)
+	data, err := gzipData([]byte(`{"motd": "magic"}`))
+	if err != nil {
+		t.Fatalf("gzipData: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.dir, "config.json"), data, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	if err := s.LoadConfig(cfg); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.MOTD != "magic" {
+		t.Errorf("MOTD = %q, want %q", cfg.MOTD, "magic")
+	}
+}
+
+func TestSave_SwitchingCompressionRemovesStaleFile(t *testing.T) {
+	s := newTestStorage(t)
+	s.SetCompressSaves("config")
+	if err := s.SaveConfig(config.DefaultConfig()); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+
+	s.SetCompressSaves("")
+	if err := s.SaveConfig(config.DefaultConfig()); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(s.dir, "config.json.gz")); !os.IsNotExist(err) {
+		t.Error("expected config.json.gz to be removed after saving uncompressed")
+	}
+	if _, err := os.Stat(filepath.Join(s.dir, "config.json")); err != nil {
+		t.Errorf("expected plain config.json: %v", err)
+	}
+}
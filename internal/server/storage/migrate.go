@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/go-theft-craft/server/internal/server/player"
+)
+
+// PlayerSchemaVersion is the current layout version of player files. Files
+// without a schema_version key are version 0.
+const PlayerSchemaVersion = 1
+
+// playerMigrations upgrades player data by one schema version each; entry i
+// converts version i to version i+1.
+var playerMigrations = []func(pd *PlayerData){
+	// v1 adds health, food and saturation; older players without them load
+	// at full vitals, and values already saved are kept.
+	func(pd *PlayerData) {
+		if pd.Health == nil {
+			health := float32(player.MaxHealth)
+			pd.Health = &health
+		}
+		if pd.Food == nil {
+			food := int32(player.MaxFood)
+			pd.Food = &food
+		}
+		if pd.Saturation == nil {
+			saturation := float32(player.DefaultSaturation)
+			pd.Saturation = &saturation
+		}
+	},
+}
+
+// migratePlayer upgrades pd to PlayerSchemaVersion. Files written by a newer
+// server are rejected rather than silently losing fields on the next save.
+func migratePlayer(pd *PlayerData) error {
+	if pd.SchemaVersion > PlayerSchemaVersion {
+		return fmt.Errorf("player schema version %d is newer than supported version %d", pd.SchemaVersion, PlayerSchemaVersion)
+	}
+	for pd.SchemaVersion < PlayerSchemaVersion {
+		playerMigrations[pd.SchemaVersion](pd)
+		pd.SchemaVersion++
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-theft-craft/server/internal/server/player"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+)
+
+func writePlayerFile(t *testing.T, s *Storage, uuid, content string) string {
+	t.Helper()
+	path := filepath.Join(s.dir, "players", uuid+".json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPlayer_MigratesV0(t *testing.T) {
+	s := newTestStorage(t)
+	path := writePlayerFile(t, s, "v0-uuid", `{"uuid":"v0-uuid","username":"Bob","position":{"x":3.5,"y":4,"z":1.5},"gamemode":0}`)
+
+	pd, err := s.LoadPlayer("v0-uuid")
+	if err != nil || pd == nil {
+		t.Fatalf("LoadPlayer: %v, %v", pd, err)
+	}
+	if pd.SchemaVersion != PlayerSchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", pd.SchemaVersion, PlayerSchemaVersion)
+	}
+	if pd.Health == nil || *pd.Health != player.MaxHealth {
+		t.Errorf("Health = %v, want %v", pd.Health, player.MaxHealth)
+	}
+	if pd.Position.X != 3.5 || pd.Username != "Bob" {
+		t.Errorf("migration changed existing fields: %+v", pd)
+	}
+
+	// Saving the restored player writes the current schema version.
+	p := player.NewPlayer(1, pd.UUID, [16]byte{1}, pd.Username, nil, func(mcnet.Packet) error { return nil })
+	if err := s.SavePlayer(p); err != nil {
+		t.Fatalf("SavePlayer: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw struct {
+		SchemaVersion *int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if raw.SchemaVersion == nil || *raw.SchemaVersion != PlayerSchemaVersion {
+		t.Errorf("saved schema_version = %v, want %d", raw.SchemaVersion, PlayerSchemaVersion)
+	}
+}
+
+func TestLoadPlayer_MigratesV0KeepsSavedVitals(t *testing.T) {
+	s := newTestStorage(t)
+	writePlayerFile(t, s, "v0-vitals", `{"uuid":"v0-vitals","username":"Bob","health":6.5,"food":11}`)
+
+	pd, err := s.LoadPlayer("v0-vitals")
+	if err != nil || pd == nil {
+		t.Fatalf("LoadPlayer: %v, %v", pd, err)
+	}
+	if pd.Health == nil || *pd.Health != 6.5 {
+		t.Errorf("Health = %v, want the saved 6.5", pd.Health)
+	}
+	if pd.Food == nil || *pd.Food != 11 {
+		t.Errorf("Food = %v, want the saved 11", pd.Food)
+	}
+	if pd.Saturation == nil || *pd.Saturation != player.DefaultSaturation {
+		t.Errorf("Saturation = %v, want the default %v", pd.Saturation, player.DefaultSaturation)
+	}
+}
+
+func TestLoadPlayer_CurrentUnchanged(t *testing.T) {
+	s := newTestStorage(t)
+	p := player.NewPlayer(1, "cur-uuid", [16]byte{1}, "Alice", nil, func(mcnet.Packet) error { return nil })
+	p.SetHealth(4)
+	p.SetFood(9, 1)
+	if err := s.SavePlayer(p); err != nil {
+		t.Fatalf("SavePlayer: %v", err)
+	}
+
+	pd, err := s.LoadPlayer(p.UUID)
+	if err != nil || pd == nil {
+		t.Fatalf("LoadPlayer: %v, %v", pd, err)
+	}
+	if want := PlayerDataFromPlayer(p); !reflect.DeepEqual(pd, want) {
+		t.Errorf("LoadPlayer = %+v, want %+v", pd, want)
+	}
+}
+
+func TestLoadPlayer_RejectsNewerSchema(t *testing.T) {
+	s := newTestStorage(t)
+	writePlayerFile(t, s, "new-uuid", `{"schema_version":99,"uuid":"new-uuid"}`)
+
+	if pd, err := s.LoadPlayer("new-uuid"); err == nil {
+		t.Errorf("LoadPlayer = %+v, want error for newer schema", pd)
+	}
+}
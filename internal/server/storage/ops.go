@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// OpEntry is a single operator in ops.json.
+type OpEntry struct {
+	UUID string `json:"uuid"`
+	Name string `json:"name"`
+}
+
+// LoadOps reads ops.json and caches the list for Ops. A missing file is an
+// empty operator list.
+func (s *Storage) LoadOps() ([]OpEntry, error) {
+	path := filepath.Join(s.dir, "ops.json")
+	data, err := s.readData(KindConfig, path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read ops: %w", err)
+	}
+
+	var ops []OpEntry
+	if err == nil {
+		if err := json.Unmarshal(data, &ops); err != nil {
+			return nil, fmt.Errorf("parse ops: %w", err)
+		}
+	}
+
+	s.opsMu.Lock()
+	s.ops, s.opsLoaded = ops, true
+	s.opsMu.Unlock()
+	return slices.Clone(ops), nil
+}
+
+// Ops returns the operator list, reading ops.json only the first time.
+func (s *Storage) Ops() ([]OpEntry, error) {
+	s.opsMu.Lock()
+	if s.opsLoaded {
+		defer s.opsMu.Unlock()
+		return slices.Clone(s.ops), nil
+	}
+	s.opsMu.Unlock()
+	return s.LoadOps()
+}
+
+// SaveOps writes the operator list to ops.json atomically and caches it for
+// Ops.
+func (s *Storage) SaveOps(ops []OpEntry) error {
+	if ops == nil {
+		ops = []OpEntry{}
+	}
+	path := filepath.Join(s.dir, "ops.json")
+	if err := s.atomicWriteJSON(KindConfig, path, ops); err != nil {
+		return err
+	}
+
+	s.opsMu.Lock()
+	s.ops, s.opsLoaded = slices.Clone(ops), true
+	s.opsMu.Unlock()
+	return nil
+}
+
+// IsOp reports whether the player with the given UUID is in ops.
+func IsOp(ops []OpEntry, uuid string) bool {
+	for _, op := range ops {
+		if op.UUID == uuid {
+			return true
+		}
+	}
+	return false
+}
+
+// FindOp returns the index of the operator with the given username, compared
+// case-insensitively, or -1.
+func FindOp(ops []OpEntry, name string) int {
+	for i, op := range ops {
+		if strings.EqualFold(op.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
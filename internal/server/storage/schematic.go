@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/go-theft-craft/server/pkg/world"
+)
+
+// schematicExt is the file extension of saved schematics.
+const schematicExt = ".schematic"
+
+// ErrInvalidSchematicName is returned for names that are not safe file names.
+var ErrInvalidSchematicName = errors.New("schematic names may only contain letters, digits, '-' and '_' (max 32)")
+
+var schematicNameRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// schematicPath returns the file path of the named schematic.
+func (s *Storage) schematicPath(name string) (string, error) {
+	if !schematicNameRe.MatchString(name) {
+		return "", ErrInvalidSchematicName
+	}
+	return filepath.Join(s.dir, "schematics", name+schematicExt), nil
+}
+
+// SaveSchematic writes a schematic to schematics/<name>.schematic.
+func (s *Storage) SaveSchematic(name string, sch *world.Schematic) error {
+	path, err := s.schematicPath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create schematics dir: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := sch.Encode(&buf); err != nil {
+		return fmt.Errorf("encode schematic %s: %w", name, err)
+	}
+	return atomicWrite(path, buf.Bytes())
+}
+
+// LoadSchematic reads schematics/<name>.schematic. A missing file returns an
+// error satisfying os.IsNotExist.
+func (s *Storage) LoadSchematic(name string) (*world.Schematic, error) {
+	path, err := s.schematicPath(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sch, err := world.DecodeSchematic(f)
+	if err != nil {
+		return nil, fmt.Errorf("load schematic %s: %w", name, err)
+	}
+	return sch, nil
+}
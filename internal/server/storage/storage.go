@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/go-theft-craft/server/internal/server/config"
 	"github.com/go-theft-craft/server/internal/server/player"
@@ -16,8 +17,24 @@ import (
 
 // Storage handles file-based persistence for config, world, and player data.
 type Storage struct {
-	dir string
-	log *slog.Logger
+	dir      string
+	log      *slog.Logger
+	compress map[string]bool // file kinds written gzip-compressed
+
+	// Operator list cached from ops.json (protected by opsMu).
+	opsMu     sync.Mutex
+	ops       []OpEntry
+	opsLoaded bool
+
+	// Ban list cached from banned-players.json (protected by bansMu).
+	bansMu     sync.Mutex
+	bans       []BanEntry
+	bansLoaded bool
+
+	// Named warps cached from warps.json (protected by warpsMu).
+	warpsMu     sync.Mutex
+	warps       map[string]PositionData
+	warpsLoaded bool
 }
 
 // New creates a new Storage rooted at dir, creating subdirectories as needed.
@@ -38,7 +55,7 @@ func New(dir string, log *slog.Logger) (*Storage, error) {
 // LoadConfig reads config.json into cfg. If the file does not exist, cfg is unchanged.
 func (s *Storage) LoadConfig(cfg *config.Config) error {
 	path := filepath.Join(s.dir, "config.json")
-	data, err := os.ReadFile(path)
+	data, err := s.readData(KindConfig, path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil
@@ -55,13 +72,14 @@ func (s *Storage) LoadConfig(cfg *config.Config) error {
 // SaveConfig writes cfg to config.json atomically.
 func (s *Storage) SaveConfig(cfg *config.Config) error {
 	path := filepath.Join(s.dir, "config.json")
-	return s.atomicWriteJSON(path, cfg)
+	return s.atomicWriteJSON(KindConfig, path, cfg)
 }
 
-// LoadWorld reads world.json and restores world-level state (time).
+// LoadWorld reads world.json and restores world-level state (time, spawn
+// point, world border and game rules).
 func (s *Storage) LoadWorld(w *world.World) error {
 	path := filepath.Join(s.dir, "world", "world.json")
-	data, err := os.ReadFile(path)
+	data, err := s.readData(KindWorld, path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil
@@ -75,27 +93,48 @@ func (s *Storage) LoadWorld(w *world.World) error {
 	}
 
 	w.SetTime(wd.Age, wd.TimeOfDay)
+	if wd.Spawn != nil {
+		w.SetSpawn(world.BlockPos{X: wd.Spawn.X, Y: wd.Spawn.Y, Z: wd.Spawn.Z})
+	}
+	if wd.Border != nil {
+		b := w.Border()
+		b.CenterX, b.CenterZ, b.Diameter = wd.Border.CenterX, wd.Border.CenterZ, wd.Border.Diameter
+		w.SetBorder(b)
+	}
+	for name, value := range wd.GameRules {
+		if err := w.SetGameRule(name, value); err != nil {
+			s.log.Warn("ignoring saved game rule", "error", err)
+		}
+	}
 	s.log.Info("loaded world data", "age", wd.Age, "timeOfDay", wd.TimeOfDay)
 	return nil
 }
 
-// SaveWorld writes world-level state (time) to world.json atomically.
+// SaveWorld writes world-level state (time, spawn point, world border and
+// game rules) to world.json atomically.
 func (s *Storage) SaveWorld(w *world.World) error {
 	age, timeOfDay := w.GetTime()
 	wd := WorldData{
 		Age:       age,
 		TimeOfDay: timeOfDay,
 	}
+	if pos, ok := w.SpawnPoint(); ok {
+		wd.Spawn = &SpawnData{X: pos.X, Y: pos.Y, Z: pos.Z}
+	}
+	if b, ok := w.CustomBorder(); ok {
+		wd.Border = &BorderData{CenterX: b.CenterX, CenterZ: b.CenterZ, Diameter: b.Diameter}
+	}
+	if rules := w.GameRules(); len(rules) > 0 {
+		wd.GameRules = rules
+	}
 
 	path := filepath.Join(s.dir, "world", "world.json")
-	return s.atomicWriteJSON(path, &wd)
+	return s.atomicWriteJSON(KindWorld, path, &wd)
 }
 
 // HasSavedWorld returns true if the world was previously saved to disk.
 func (s *Storage) HasSavedWorld() bool {
-	path := filepath.Join(s.dir, "world", "world.json")
-	_, err := os.Stat(path)
-	return err == nil
+	return exists(filepath.Join(s.dir, "world", "world.json"))
 }
 
 // SaveBlockOverrides writes the block overrides map to world/overrides.json.
@@ -109,13 +148,13 @@ func (s *Storage) SaveBlockOverrides(w *world.World) error {
 	}
 
 	path := filepath.Join(s.dir, "world", "overrides.json")
-	return s.atomicWriteJSON(path, entries)
+	return s.atomicWriteJSON(KindWorld, path, entries)
 }
 
 // LoadBlockOverrides reads world/overrides.json and restores block overrides.
 func (s *Storage) LoadBlockOverrides(w *world.World) error {
 	path := filepath.Join(s.dir, "world", "overrides.json")
-	data, err := os.ReadFile(path)
+	data, err := s.readData(KindWorld, path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil
@@ -138,44 +177,226 @@ func (s *Storage) LoadBlockOverrides(w *world.World) error {
 	return nil
 }
 
+// SaveSigns writes the text of every sign to world/signs.json, so signs
+// survive even when their chunk is not loaded at save time.
+func (s *Storage) SaveSigns(w *world.World) error {
+	signs := w.Signs()
+	entries := make([]SignEntry, 0, len(signs))
+	for pos, lines := range signs {
+		entries = append(entries, SignEntry{X: pos.X, Y: pos.Y, Z: pos.Z, Lines: lines})
+	}
+
+	path := filepath.Join(s.dir, "world", "signs.json")
+	return s.atomicWriteJSON(KindWorld, path, entries)
+}
+
+// LoadSigns reads world/signs.json and restores sign text.
+func (s *Storage) LoadSigns(w *world.World) error {
+	path := filepath.Join(s.dir, "world", "signs.json")
+	data, err := s.readData(KindWorld, path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("read signs: %w", err)
+	}
+
+	var entries []SignEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("parse signs: %w", err)
+	}
+
+	signs := make(map[world.BlockPos][4]string, len(entries))
+	for _, e := range entries {
+		signs[world.BlockPos{X: e.X, Y: e.Y, Z: e.Z}] = e.Lines
+	}
+
+	w.SetSigns(signs)
+	s.log.Info("loaded signs", "count", len(signs))
+	return nil
+}
+
+// SaveBiomeOverrides writes the biome overrides map to world/biomes.json.
+func (s *Storage) SaveBiomeOverrides(w *world.World) error {
+	overrides := w.GetBiomeOverrides()
+	entries := make([]BiomeOverrideEntry, 0, len(overrides))
+	for pos, biome := range overrides {
+		entries = append(entries, BiomeOverrideEntry{X: pos.X, Z: pos.Z, Biome: biome})
+	}
+
+	path := filepath.Join(s.dir, "world", "biomes.json")
+	return s.atomicWriteJSON(KindWorld, path, entries)
+}
+
+// LoadBiomeOverrides reads world/biomes.json and restores biome overrides.
+func (s *Storage) LoadBiomeOverrides(w *world.World) error {
+	path := filepath.Join(s.dir, "world", "biomes.json")
+	data, err := s.readData(KindWorld, path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("read biome overrides: %w", err)
+	}
+
+	var entries []BiomeOverrideEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("parse biome overrides: %w", err)
+	}
+
+	overrides := make(map[world.ColumnPos]byte, len(entries))
+	for _, e := range entries {
+		overrides[world.ColumnPos{X: e.X, Z: e.Z}] = e.Biome
+	}
+
+	w.SetBiomeOverrides(overrides)
+	s.log.Info("loaded biome overrides", "count", len(overrides))
+	return nil
+}
+
+// SaveContainers writes the contents of every container block to
+// world/containers.json.
+func (s *Storage) SaveContainers(m *player.Manager) error {
+	contents := m.ContainerContents()
+	entries := make([]ContainerEntry, 0, len(contents))
+	for pos, slots := range contents {
+		e := ContainerEntry{X: pos.X, Y: pos.Y, Z: pos.Z, Slots: make([]SlotData, len(slots))}
+		for i, sl := range slots {
+			e.Slots[i] = SlotDataFromSlot(sl)
+		}
+		entries = append(entries, e)
+	}
+
+	path := filepath.Join(s.dir, "world", "containers.json")
+	return s.atomicWriteJSON(KindWorld, path, entries)
+}
+
+// LoadContainers reads world/containers.json and restores container contents.
+func (s *Storage) LoadContainers(m *player.Manager) error {
+	path := filepath.Join(s.dir, "world", "containers.json")
+	data, err := s.readData(KindWorld, path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("read containers: %w", err)
+	}
+
+	var entries []ContainerEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("parse containers: %w", err)
+	}
+
+	contents := make(map[world.BlockPos][]player.Slot, len(entries))
+	for _, e := range entries {
+		slots := make([]player.Slot, len(e.Slots))
+		for i, sd := range e.Slots {
+			slots[i] = sd.Slot()
+		}
+		contents[world.BlockPos{X: e.X, Y: e.Y, Z: e.Z}] = slots
+	}
+
+	m.SetContainerContents(contents)
+	s.log.Info("loaded containers", "count", len(contents))
+	return nil
+}
+
+// SaveFurnaces writes the contents and smelting progress of every furnace to
+// world/furnaces.json.
+func (s *Storage) SaveFurnaces(m *player.Manager) error {
+	contents := m.FurnaceContents()
+	entries := make([]FurnaceEntry, 0, len(contents))
+	for pos, st := range contents {
+		e := FurnaceEntry{
+			X: pos.X, Y: pos.Y, Z: pos.Z,
+			Slots:    make([]SlotData, len(st.Slots)),
+			BurnTime: st.BurnTime,
+			BurnMax:  st.BurnMax,
+			CookTime: st.CookTime,
+		}
+		for i, sl := range st.Slots {
+			e.Slots[i] = SlotDataFromSlot(sl)
+		}
+		entries = append(entries, e)
+	}
+
+	path := filepath.Join(s.dir, "world", "furnaces.json")
+	return s.atomicWriteJSON(KindWorld, path, entries)
+}
+
+// LoadFurnaces reads world/furnaces.json and restores furnaces.
+func (s *Storage) LoadFurnaces(m *player.Manager) error {
+	path := filepath.Join(s.dir, "world", "furnaces.json")
+	data, err := s.readData(KindWorld, path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("read furnaces: %w", err)
+	}
+
+	var entries []FurnaceEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("parse furnaces: %w", err)
+	}
+
+	contents := make(map[world.BlockPos]player.FurnaceState, len(entries))
+	for _, e := range entries {
+		st := player.FurnaceState{
+			Slots:    make([]player.Slot, len(e.Slots)),
+			BurnTime: e.BurnTime,
+			BurnMax:  e.BurnMax,
+			CookTime: e.CookTime,
+		}
+		for i, sd := range e.Slots {
+			st.Slots[i] = sd.Slot()
+		}
+		contents[world.BlockPos{X: e.X, Y: e.Y, Z: e.Z}] = st
+	}
+
+	m.SetFurnaceContents(contents)
+	s.log.Info("loaded furnaces", "count", len(contents))
+	return nil
+}
+
 // SaveWorldAnvil writes the world in Minecraft's Anvil region file format (.mca).
+// Only loaded chunks are written; chunks already saved in their regions are
+// kept.
 func (s *Storage) SaveWorldAnvil(w *world.World) error {
 	regionDir := filepath.Join(s.dir, "world", "region")
 	if err := os.MkdirAll(regionDir, 0o755); err != nil {
 		return fmt.Errorf("create region dir: %w", err)
 	}
 
+	// Changes made after this point stay marked unsaved.
+	count := w.ChangeCount()
+
 	// First pass: collect chunk positions under a single read lock.
-	// We must NOT call OverridesForChunk inside ForEachChunk — both acquire
+	// We must NOT call LitChunk inside ForEachChunk — both acquire
 	// w.mu.RLock, and a pending w.mu.Lock (from the tick loop) would cause
 	// the second RLock to deadlock.
-	type chunkEntry struct {
-		pos   gen.ChunkPos
-		chunk *gen.ChunkData
-	}
-	var chunks []chunkEntry
-	w.ForEachChunk(func(pos gen.ChunkPos, chunk *gen.ChunkData) {
-		chunks = append(chunks, chunkEntry{pos, chunk})
+	var positions []gen.ChunkPos
+	w.ForEachChunk(func(pos gen.ChunkPos, _ *gen.ChunkData) {
+		positions = append(positions, pos)
 	})
 
-	// Second pass: encode each chunk with its overrides (locks acquired sequentially).
+	// Second pass: encode each chunk with its overrides, lighting and signs
+	// applied (locks acquired sequentially).
 	type regionKey struct{ rx, rz int }
 	regions := make(map[regionKey]map[gen.ChunkPos][]byte)
 
-	for _, ce := range chunks {
-		overrides := w.OverridesForChunk(ce.pos.X, ce.pos.Z)
-
-		nbtData, err := anvil.EncodeChunkNBT(ce.pos.X, ce.pos.Z, ce.chunk, overrides)
+	for _, pos := range positions {
+		nbtData, err := s.encodeChunk(w, pos)
 		if err != nil {
-			s.log.Error("encode chunk NBT", "cx", ce.pos.X, "cz", ce.pos.Z, "error", err)
+			s.log.Error("encode chunk NBT", "cx", pos.X, "cz", pos.Z, "error", err)
 			continue
 		}
 
-		rk := regionKey{rx: ce.pos.X >> 5, rz: ce.pos.Z >> 5}
+		rk := regionKey{rx: pos.X >> 5, rz: pos.Z >> 5}
 		if regions[rk] == nil {
 			regions[rk] = make(map[gen.ChunkPos][]byte)
 		}
-		regions[rk][ce.pos] = nbtData
+		regions[rk][pos] = nbtData
 	}
 
 	for rk, chunks := range regions {
@@ -183,15 +404,64 @@ func (s *Storage) SaveWorldAnvil(w *world.World) error {
 			s.log.Error("save region", "rx", rk.rx, "rz", rk.rz, "error", err)
 			return err
 		}
+		for pos := range chunks {
+			w.MarkChunkSaved(pos.X, pos.Z, count)
+		}
+	}
+
+	return nil
+}
+
+// SaveChunk writes chunk (cx, cz) to its region file if it has changes that
+// were not saved yet. It is called before a chunk is unloaded.
+func (s *Storage) SaveChunk(w *world.World, cx, cz int) error {
+	count := w.ChangeCount()
+	if !w.ChunkChanged(cx, cz) {
+		return nil
 	}
 
+	pos := gen.ChunkPos{X: cx, Z: cz}
+	nbtData, err := s.encodeChunk(w, pos)
+	if err != nil {
+		return fmt.Errorf("encode chunk NBT: %w", err)
+	}
+	regionDir := filepath.Join(s.dir, "world", "region")
+	if err := anvil.SaveRegion(regionDir, cx>>5, cz>>5, map[gen.ChunkPos][]byte{pos: nbtData}); err != nil {
+		return err
+	}
+	w.MarkChunkSaved(cx, cz, count)
 	return nil
 }
 
-// LoadPlayer reads players/<uuid>.json and returns the data, or nil if not found.
+// encodeChunk encodes a chunk as Anvil NBT with its overrides, lighting and
+// signs applied.
+func (s *Storage) encodeChunk(w *world.World, pos gen.ChunkPos) ([]byte, error) {
+	chunk := w.LitChunk(pos.X, pos.Z)
+	for bpos, lines := range w.SignsInChunk(pos.X, pos.Z) {
+		chunk.Signs = append(chunk.Signs, gen.Sign{X: bpos.X, Y: bpos.Y, Z: bpos.Z, Lines: lines})
+	}
+	return anvil.EncodeChunkNBT(pos.X, pos.Z, chunk, nil)
+}
+
+// ChunkLoader returns a world.ChunkLoader that reads chunks saved by
+// SaveWorldAnvil. Chunks that cannot be read are logged and regenerated.
+func (s *Storage) ChunkLoader() world.ChunkLoader {
+	regionDir := filepath.Join(s.dir, "world", "region")
+	return func(cx, cz int) *gen.ChunkData {
+		chunk, err := anvil.LoadChunk(regionDir, cx, cz)
+		if err != nil {
+			s.log.Error("load saved chunk", "cx", cx, "cz", cz, "error", err)
+			return nil
+		}
+		return chunk
+	}
+}
+
+// LoadPlayer reads players/<uuid>.json, upgrades it to the current schema
+// version and returns the data, or nil if not found.
 func (s *Storage) LoadPlayer(uuid string) (*PlayerData, error) {
 	path := filepath.Join(s.dir, "players", uuid+".json")
-	data, err := os.ReadFile(path)
+	data, err := s.readData(KindPlayers, path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
@@ -203,6 +473,9 @@ func (s *Storage) LoadPlayer(uuid string) (*PlayerData, error) {
 	if err := json.Unmarshal(data, &pd); err != nil {
 		return nil, fmt.Errorf("parse player %s: %w", uuid, err)
 	}
+	if err := migratePlayer(&pd); err != nil {
+		return nil, fmt.Errorf("migrate player %s: %w", uuid, err)
+	}
 	return &pd, nil
 }
 
@@ -210,17 +483,39 @@ func (s *Storage) LoadPlayer(uuid string) (*PlayerData, error) {
 func (s *Storage) SavePlayer(p *player.Player) error {
 	pd := PlayerDataFromPlayer(p)
 	path := filepath.Join(s.dir, "players", p.UUID+".json")
-	return s.atomicWriteJSON(path, pd)
+	return s.atomicWriteJSON(KindPlayers, path, pd)
 }
 
 // atomicWriteJSON marshals v to JSON and writes it atomically using a temp file + rename.
-func (s *Storage) atomicWriteJSON(path string, v any) error {
+// Kinds configured for compression are written gzip-compressed to path+".gz";
+// the variant not written is removed so reads never see a stale copy.
+func (s *Storage) atomicWriteJSON(kind, path string, v any) error {
 	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshal json: %w", err)
 	}
 	data = append(data, '\n')
 
+	stale := path + gzipExt
+	if s.compress[kind] {
+		if data, err = gzipData(data); err != nil {
+			return fmt.Errorf("compress json: %w", err)
+		}
+		path, stale = stale, path
+	}
+
+	if err := atomicWrite(path, data); err != nil {
+		return err
+	}
+	if err := os.Remove(stale); err != nil && !os.IsNotExist(err) {
+		s.log.Warn("remove stale save file", "path", stale, "error", err)
+	}
+	return nil
+}
+
+// atomicWrite writes data to path through a temp file + rename, so readers
+// never see a partially written file.
+func atomicWrite(path string, data []byte) error {
 	tmp := path + ".tmp"
 	if err := os.WriteFile(tmp, data, 0o644); err != nil {
 		return fmt.Errorf("write temp file: %w", err)
@@ -0,0 +1,606 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-theft-craft/server/internal/server/player"
+	mcnet "github.com/go-theft-craft/server/pkg/protocol"
+	"github.com/go-theft-craft/server/pkg/world"
+	"github.com/go-theft-craft/server/pkg/world/gen"
+)
+
+func newTestStorage(t *testing.T) *Storage {
+	t.Helper()
+	s, err := New(t.TempDir(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func TestBiomeOverrides_SaveLoad(t *testing.T) {
+	s := newTestStorage(t)
+	w := world.NewWorld(gen.NewFlatGenerator(0))
+	w.SetBiome(5, 7, 2)
+	w.SetBiome(-40, 12, 12)
+
+	if err := s.SaveBiomeOverrides(w); err != nil {
+		t.Fatalf("SaveBiomeOverrides: %v", err)
+	}
+
+	loaded := world.NewWorld(gen.NewFlatGenerator(0))
+	if err := s.LoadBiomeOverrides(loaded); err != nil {
+		t.Fatalf("LoadBiomeOverrides: %v", err)
+	}
+	if got := loaded.GetBiome(5, 7); got != 2 {
+		t.Errorf("GetBiome(5, 7) = %d, want 2", got)
+	}
+	if got := loaded.GetBiome(-40, 12); got != 12 {
+		t.Errorf("GetBiome(-40, 12) = %d, want 12", got)
+	}
+}
+
+func TestSigns_SurviveTwoRestarts(t *testing.T) {
+	s := newTestStorage(t)
+	pos := world.BlockPos{X: 100, Y: 4, Z: -50}
+	lines := [4]string{"Welcome", "to", "spawn", ""}
+
+	w := world.NewWorld(gen.NewFlatGenerator(0))
+	w.SetBlock(pos.X, pos.Y, pos.Z, world.BlockStandingSign<<4)
+	w.SetSign(pos, lines)
+	if err := s.SaveSigns(w); err != nil {
+		t.Fatalf("SaveSigns: %v", err)
+	}
+
+	// The second session never loads the sign's chunk.
+	w = world.NewWorld(gen.NewFlatGenerator(0))
+	if err := s.LoadSigns(w); err != nil {
+		t.Fatalf("LoadSigns: %v", err)
+	}
+	if err := s.SaveSigns(w); err != nil {
+		t.Fatalf("SaveSigns: %v", err)
+	}
+
+	w = world.NewWorld(gen.NewFlatGenerator(0))
+	if err := s.LoadSigns(w); err != nil {
+		t.Fatalf("LoadSigns: %v", err)
+	}
+	if got, ok := w.Sign(pos); !ok || got != lines {
+		t.Errorf("Sign = %q, %v; want %q", got, ok, lines)
+	}
+}
+
+func TestBiomeOverrides_LoadMissingFile(t *testing.T) {
+	s := newTestStorage(t)
+	w := world.NewWorld(gen.NewFlatGenerator(0))
+	if err := s.LoadBiomeOverrides(w); err != nil {
+		t.Errorf("LoadBiomeOverrides without a file: %v", err)
+	}
+}
+
+func TestContainers_SaveLoad(t *testing.T) {
+	s := newTestStorage(t)
+	m := player.NewManager(8)
+	pos := world.BlockPos{X: 3, Y: 64, Z: -9}
+	m.Container(pos, 27).Set(4, player.Slot{BlockID: 1, ItemCount: 32}, 0)
+	m.Container(pos, 27).Set(26, player.Slot{BlockID: 276, ItemCount: 1, ItemDamage: 7}, 0)
+	m.Container(pos, 27).Set(13, player.Slot{BlockID: 278, ItemCount: 1, NBT: &player.ItemNBT{
+		DisplayName:  "Digger",
+		Enchantments: []player.Enchantment{{ID: 32, Level: 5}},
+	}}, 0)
+
+	if err := s.SaveContainers(m); err != nil {
+		t.Fatalf("SaveContainers: %v", err)
+	}
+
+	loaded := player.NewManager(8)
+	if err := s.LoadContainers(loaded); err != nil {
+		t.Fatalf("LoadContainers: %v", err)
+	}
+	want := m.ContainerContents()
+	if got := loaded.ContainerContents(); !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded containers = %v, want %v", got, want)
+	}
+}
+
+func TestFurnaces_SaveLoad(t *testing.T) {
+	s := newTestStorage(t)
+	m := player.NewManager(8)
+	f := m.Furnace(world.BlockPos{X: -1, Y: 70, Z: 4})
+	f.Set(player.FurnaceInput, player.Slot{BlockID: 15, ItemCount: 3}, 0)
+	f.Set(player.FurnaceFuel, player.Slot{BlockID: 263, ItemCount: 2}, 0)
+	for range 10 {
+		m.Tick()
+	}
+
+	if err := s.SaveFurnaces(m); err != nil {
+		t.Fatalf("SaveFurnaces: %v", err)
+	}
+
+	loaded := player.NewManager(8)
+	if err := s.LoadFurnaces(loaded); err != nil {
+		t.Fatalf("LoadFurnaces: %v", err)
+	}
+	want := m.FurnaceContents()
+	if got := loaded.FurnaceContents(); !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded furnaces = %+v, want %+v", got, want)
+	}
+}
+
+func TestSaveWorldAnvil_ConcurrentEdits(t *testing.T) {
+	s := newTestStorage(t)
+	w := world.NewWorld(gen.NewDefaultGenerator(7))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				x, z := (g*50+i)*5-500, i*3-75
+				w.GetOrGenerateChunk(x>>4, z>>4)
+				w.SetBlock(x, 100, z, 4<<4)
+			}
+		}(g)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 3; i++ {
+			if err := s.SaveWorldAnvil(w); err != nil {
+				t.Errorf("SaveWorldAnvil: %v", err)
+			}
+		}
+	}()
+	wg.Wait()
+
+	if err := s.SaveWorldAnvil(w); err != nil {
+		t.Fatalf("final SaveWorldAnvil: %v", err)
+	}
+	w.ForEachChunk(func(pos gen.ChunkPos, c *gen.ChunkData) {
+		if c.GetBlock(0, 0, 0) != 7<<4 {
+			t.Errorf("chunk %v saved without its bedrock floor", pos)
+		}
+	})
+}
+
+func TestSaveWorldAnvil_KeepsUnloadedChunks(t *testing.T) {
+	s := newTestStorage(t)
+	restart := func() *world.World {
+		w := world.NewWorld(gen.NewFlatGenerator(0))
+		w.SetChunkLoader(s.ChunkLoader())
+		return w
+	}
+	sign := world.BlockPos{X: 3, Y: 4, Z: 5}
+	lines := [4]string{"kept", "", "", ""}
+
+	w := restart()
+	w.GetOrGenerateChunk(1, 0)
+	w.SetBlock(sign.X, sign.Y, sign.Z, world.BlockStandingSign<<4)
+	w.SetSign(sign, lines)
+	if err := s.SaveWorldAnvil(w); err != nil {
+		t.Fatalf("SaveWorldAnvil: %v", err)
+	}
+
+	// The second session saves without loading the sign's chunk.
+	w = restart()
+	w.GetOrGenerateChunk(1, 0)
+	if err := s.SaveWorldAnvil(w); err != nil {
+		t.Fatalf("SaveWorldAnvil: %v", err)
+	}
+
+	w = restart()
+	if got := w.GetBlock(sign.X, sign.Y, sign.Z); got != world.BlockStandingSign<<4 {
+		t.Errorf("block at the sign = %#x, want a standing sign", got)
+	}
+	if got, ok := w.Sign(sign); !ok || got != lines {
+		t.Errorf("Sign = %q, %v; want %q", got, ok, lines)
+	}
+}
+
+func TestSchematic_SaveLoad(t *testing.T) {
+	s := newTestStorage(t)
+	sch := &world.Schematic{Width: 2, Height: 1, Length: 1, Blocks: []int32{1 << 4, 35<<4 | 14}}
+
+	if err := s.SaveSchematic("house", sch); err != nil {
+		t.Fatalf("SaveSchematic: %v", err)
+	}
+	got, err := s.LoadSchematic("house")
+	if err != nil {
+		t.Fatalf("LoadSchematic: %v", err)
+	}
+	if !reflect.DeepEqual(got, sch) {
+		t.Errorf("LoadSchematic = %+v, want %+v", got, sch)
+	}
+
+	if _, err := s.LoadSchematic("missing"); !os.IsNotExist(err) {
+		t.Errorf("LoadSchematic(missing) err = %v, want not-exist", err)
+	}
+	for _, name := range []string{"../escape", "", "a/b", "with space"} {
+		if err := s.SaveSchematic(name, sch); !errors.Is(err, ErrInvalidSchematicName) {
+			t.Errorf("SaveSchematic(%q) err = %v, want ErrInvalidSchematicName", name, err)
+		}
+	}
+}
+
+func TestPlayerVitals_SaveLoad(t *testing.T) {
+	s := newTestStorage(t)
+	p := player.NewPlayer(1, "vitals-uuid", [16]byte{1}, "Alice", nil, func(mcnet.Packet) error { return nil })
+	p.SetHealth(7.5)
+	p.SetFood(12, 3)
+
+	if err := s.SavePlayer(p); err != nil {
+		t.Fatalf("SavePlayer: %v", err)
+	}
+	pd, err := s.LoadPlayer(p.UUID)
+	if err != nil || pd == nil {
+		t.Fatalf("LoadPlayer: %v, %v", pd, err)
+	}
+
+	health, food, saturation := pd.Vitals()
+	if health != 7.5 || food != 12 || saturation != 3 {
+		t.Errorf("Vitals() = %v, %v, %v; want 7.5, 12, 3", health, food, saturation)
+	}
+}
+
+func TestPlayerInventoryNBT_SaveLoad(t *testing.T) {
+	s := newTestStorage(t)
+	p := player.NewPlayer(1, "nbt-uuid", [16]byte{1}, "Alice", nil, nil)
+	sword := player.Slot{BlockID: 276, ItemCount: 1, NBT: &player.ItemNBT{
+		DisplayName:  "Excalibur",
+		Enchantments: []player.Enchantment{{ID: 16, Level: 5}},
+	}}
+	p.Inventory.SetSlot(3, sword)
+	p.Inventory.SetArmor(3, player.Slot{BlockID: 310, ItemCount: 1, NBT: &player.ItemNBT{DisplayName: "Crown"}})
+
+	if err := s.SavePlayer(p); err != nil {
+		t.Fatalf("SavePlayer: %v", err)
+	}
+	pd, err := s.LoadPlayer("nbt-uuid")
+	if err != nil || pd == nil {
+		t.Fatalf("LoadPlayer: %v, %v", pd, err)
+	}
+	if got := pd.Inventory.Slots[3].Slot(); !reflect.DeepEqual(got, sword) {
+		t.Errorf("slot 3 = %+v, want %+v", got, sword)
+	}
+	if got := pd.Inventory.Armor[3].Slot(); got.NBT == nil || got.NBT.DisplayName != "Crown" {
+		t.Errorf("helmet = %+v, want one named Crown", got)
+	}
+}
+
+func TestPlayerVitals_LegacyFileLoadsFull(t *testing.T) {
+	s := newTestStorage(t)
+	legacy := `{"uuid":"old-uuid","username":"Bob","position":{"x":0.5,"y":4,"z":0.5},"gamemode":0}`
+	if err := os.WriteFile(filepath.Join(s.dir, "players", "old-uuid.json"), []byte(legacy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	pd, err := s.LoadPlayer("old-uuid")
+	if err != nil || pd == nil {
+		t.Fatalf("LoadPlayer: %v, %v", pd, err)
+	}
+	health, food, saturation := pd.Vitals()
+	if health != player.MaxHealth || food != player.MaxFood || saturation != player.DefaultSaturation {
+		t.Errorf("Vitals() = %v, %v, %v; want full vitals", health, food, saturation)
+	}
+}
+
+func TestWhitelist_SaveLoad(t *testing.T) {
+	s := newTestStorage(t)
+
+	names, err := s.LoadWhitelist()
+	if err != nil || len(names) != 0 {
+		t.Fatalf("LoadWhitelist on a fresh dir = %v, %v; want empty", names, err)
+	}
+
+	if err := s.SaveWhitelist(map[string]bool{"Alice": true, "bob": true}); err != nil {
+		t.Fatalf("SaveWhitelist: %v", err)
+	}
+	names, err = s.LoadWhitelist()
+	if err != nil {
+		t.Fatalf("LoadWhitelist: %v", err)
+	}
+	if want := map[string]bool{"alice": true, "bob": true}; !reflect.DeepEqual(names, want) {
+		t.Errorf("LoadWhitelist = %v, want %v", names, want)
+	}
+}
+
+func TestBans_SaveLoad(t *testing.T) {
+	s := newTestStorage(t)
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	bans := []BanEntry{
+		{UUID: "0000-uuid", Name: "Mallory", Reason: "griefing", Created: created},
+		{Name: "Eve", Created: created},
+	}
+
+	if err := s.SaveBans(bans); err != nil {
+		t.Fatalf("SaveBans: %v", err)
+	}
+	got, err := s.LoadBans()
+	if err != nil {
+		t.Fatalf("LoadBans: %v", err)
+	}
+	if !reflect.DeepEqual(got, bans) {
+		t.Errorf("LoadBans = %+v, want %+v", got, bans)
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, "banned-players.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"uuid": "0000-uuid"`, `"reason": "griefing"`, `"created": "2024-05-01T12:00:00Z"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("banned-players.json missing %s:\n%s", want, data)
+		}
+	}
+}
+
+func TestBans_CachedUntilSaved(t *testing.T) {
+	s := newTestStorage(t)
+	if err := s.SaveBans([]BanEntry{{Name: "Mallory"}}); err != nil {
+		t.Fatalf("SaveBans: %v", err)
+	}
+
+	// Edits made behind the server's back are not picked up by Bans.
+	path := filepath.Join(s.dir, "banned-players.json")
+	if err := os.WriteFile(path, []byte("[]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.Bans(); err != nil || len(got) != 1 {
+		t.Fatalf("Bans = %+v, %v; want the cached entry", got, err)
+	}
+
+	if err := s.SaveBans(nil); err != nil {
+		t.Fatalf("SaveBans: %v", err)
+	}
+	if got, err := s.Bans(); err != nil || len(got) != 0 {
+		t.Errorf("Bans after SaveBans = %+v, %v; want empty", got, err)
+	}
+}
+
+func TestFindBan(t *testing.T) {
+	bans := []BanEntry{{UUID: "u-1", Name: "Mallory"}}
+
+	if _, ok := FindBan(bans, "u-1", "Renamed"); !ok {
+		t.Error("ban should match by UUID")
+	}
+	if _, ok := FindBan(bans, "", "mallory"); !ok {
+		t.Error("ban should match by name, ignoring case")
+	}
+	if _, ok := FindBan(bans, "u-2", "Alice"); ok {
+		t.Error("unrelated player matched a ban")
+	}
+}
+
+func TestWorldSpawn_SaveLoad(t *testing.T) {
+	s := newTestStorage(t)
+	w := world.NewWorld(gen.NewFlatGenerator(0))
+	w.SetSpawn(world.BlockPos{X: 120, Y: 70, Z: -35})
+
+	if err := s.SaveWorld(w); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+
+	loaded := world.NewWorld(gen.NewFlatGenerator(0))
+	if err := s.LoadWorld(loaded); err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+	if pos, ok := loaded.SpawnPoint(); !ok || pos != (world.BlockPos{X: 120, Y: 70, Z: -35}) {
+		t.Errorf("spawn = %v (set %v), want (120, 70, -35)", pos, ok)
+	}
+}
+
+func TestWorldSpawn_LoadWithoutSpawn(t *testing.T) {
+	s := newTestStorage(t)
+	dir := filepath.Join(s.dir, "world")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "world.json"), []byte(`{"age":100,"time_of_day":6000}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := world.NewWorld(gen.NewFlatGenerator(0))
+	if err := s.LoadWorld(w); err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+	if _, ok := w.SpawnPoint(); ok {
+		t.Error("a world.json without a spawn should leave the spawn unset")
+	}
+	if got := w.Spawn(); got != (world.BlockPos{X: 0, Y: w.SpawnHeight(), Z: 0}) {
+		t.Errorf("Spawn() = %v, want the surface at the origin", got)
+	}
+}
+
+func TestWorldBorder_SaveLoad(t *testing.T) {
+	s := newTestStorage(t)
+	w := world.NewWorld(gen.NewFlatGenerator(0))
+	b := w.Border()
+	b.CenterX, b.CenterZ, b.Diameter = 100, -20, 250
+	w.SetBorder(b)
+
+	if err := s.SaveWorld(w); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+
+	loaded := world.NewWorld(gen.NewFlatGenerator(0))
+	if err := s.LoadWorld(loaded); err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+	if got := loaded.Border(); got != b {
+		t.Errorf("border = %+v, want %+v", got, b)
+	}
+}
+
+func TestWorldBorder_DefaultNotSaved(t *testing.T) {
+	s := newTestStorage(t)
+	w := world.NewWorld(gen.NewFlatGenerator(0))
+	w.SetDefaultBorder(world.RadiusBorder(10))
+	if err := s.SaveWorld(w); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+
+	// A restart with a different -world-radius gets the new radius.
+	loaded := world.NewWorld(gen.NewFlatGenerator(0))
+	loaded.SetDefaultBorder(world.RadiusBorder(20))
+	if err := s.LoadWorld(loaded); err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+	if got, want := loaded.Border(), world.RadiusBorder(20); got != want {
+		t.Errorf("border = %+v, want %+v", got, want)
+	}
+	if _, ok := loaded.CustomBorder(); ok {
+		t.Error("a border derived from the world radius should not be saved")
+	}
+}
+
+func TestGameRules_SaveLoad(t *testing.T) {
+	s := newTestStorage(t)
+	w := world.NewWorld(gen.NewFlatGenerator(0))
+	if err := w.SetGameRule(world.RuleKeepInventory, "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.SaveWorld(w); err != nil {
+		t.Fatalf("SaveWorld: %v", err)
+	}
+
+	loaded := world.NewWorld(gen.NewFlatGenerator(0))
+	if err := s.LoadWorld(loaded); err != nil {
+		t.Fatalf("LoadWorld: %v", err)
+	}
+	if !loaded.GameRuleBool(world.RuleKeepInventory) {
+		t.Error("keepInventory should survive a save and load")
+	}
+	if !loaded.GameRuleBool(world.RuleDoDaylightCycle) {
+		t.Error("unset rules should keep their defaults")
+	}
+}
+
+func TestPlayerHome_SaveLoad(t *testing.T) {
+	s := newTestStorage(t)
+	p := player.NewPlayer(1, "home-uuid", [16]byte{1}, "Alice", nil, nil)
+
+	if err := s.SavePlayer(p); err != nil {
+		t.Fatalf("SavePlayer: %v", err)
+	}
+	pd, err := s.LoadPlayer("home-uuid")
+	if err != nil {
+		t.Fatalf("LoadPlayer: %v", err)
+	}
+	if pd.Home != nil {
+		t.Errorf("home = %+v for a player without one, want nil", pd.Home)
+	}
+
+	p.SetHome(player.Position{X: 12.5, Y: 64, Z: -3.5, Yaw: 90})
+	if err := s.SavePlayer(p); err != nil {
+		t.Fatalf("SavePlayer: %v", err)
+	}
+	pd, err = s.LoadPlayer("home-uuid")
+	if err != nil {
+		t.Fatalf("LoadPlayer: %v", err)
+	}
+	want := PositionData{X: 12.5, Y: 64, Z: -3.5, Yaw: 90}
+	if pd.Home == nil || *pd.Home != want {
+		t.Errorf("home = %+v, want %+v", pd.Home, want)
+	}
+}
+
+func TestWarps_SaveLoad(t *testing.T) {
+	s := newTestStorage(t)
+	warps, err := s.LoadWarps()
+	if err != nil || len(warps) != 0 {
+		t.Fatalf("LoadWarps without a file = %v, %v; want empty", warps, err)
+	}
+
+	want := map[string]PositionData{
+		"market": {X: 100.5, Y: 64, Z: -20.5, Yaw: 180},
+		"mine":   {X: -3, Y: 12, Z: 7},
+	}
+	if err := s.SaveWarps(want); err != nil {
+		t.Fatalf("SaveWarps: %v", err)
+	}
+	got, err := s.LoadWarps()
+	if err != nil {
+		t.Fatalf("LoadWarps: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("warps = %+v, want %+v", got, want)
+	}
+}
+
+func TestWarps_WriteThroughCache(t *testing.T) {
+	s := newTestStorage(t)
+	if err := s.SetWarp("Market", PositionData{X: 1, Y: 64, Z: 2}); err != nil {
+		t.Fatalf("SetWarp: %v", err)
+	}
+	if err := s.SetWarp("mine", PositionData{X: -3, Y: 12, Z: 7}); err != nil {
+		t.Fatalf("SetWarp: %v", err)
+	}
+	if found, err := s.DeleteWarp("MINE"); !found || err != nil {
+		t.Fatalf("DeleteWarp = %v, %v; want true, nil", found, err)
+	}
+
+	want := map[string]PositionData{"market": {X: 1, Y: 64, Z: 2}}
+	if got, err := s.LoadWarps(); err != nil || !reflect.DeepEqual(got, want) {
+		t.Fatalf("warps.json holds %+v, %v; want %+v", got, err, want)
+	}
+
+	// Later reads come from memory, not the file.
+	if err := os.Remove(filepath.Join(s.dir, "warps.json")); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.Warps(); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Warps = %+v, %v; want %+v", got, err, want)
+	}
+}
+
+func TestPlayerExperience_SaveLoad(t *testing.T) {
+	s := newTestStorage(t)
+	p := player.NewPlayer(1, "xp-uuid", [16]byte{1}, "Alice", nil, nil)
+	p.AddExperience(20)
+
+	if err := s.SavePlayer(p); err != nil {
+		t.Fatalf("SavePlayer: %v", err)
+	}
+	pd, err := s.LoadPlayer("xp-uuid")
+	if err != nil {
+		t.Fatalf("LoadPlayer: %v", err)
+	}
+	if pd.XPLevel != 2 || pd.XPPoints != 4 || pd.XPTotal != 20 {
+		t.Errorf("experience = level %d, %d points, total %d; want level 2, 4 points, total 20",
+			pd.XPLevel, pd.XPPoints, pd.XPTotal)
+	}
+}
+
+func TestOps_SaveLoad(t *testing.T) {
+	s := newTestStorage(t)
+
+	ops := []OpEntry{{UUID: "alice-uuid", Name: "Alice"}, {UUID: "bob-uuid", Name: "Bob"}}
+	if err := s.SaveOps(ops); err != nil {
+		t.Fatalf("SaveOps: %v", err)
+	}
+	got, err := s.LoadOps()
+	if err != nil {
+		t.Fatalf("LoadOps: %v", err)
+	}
+	if !reflect.DeepEqual(got, ops) {
+		t.Errorf("LoadOps = %+v, want %+v", got, ops)
+	}
+	if !IsOp(got, "bob-uuid") || IsOp(got, "carol-uuid") {
+		t.Error("IsOp does not match by UUID")
+	}
+	if FindOp(got, "ALICE") != 0 || FindOp(got, "Carol") != -1 {
+		t.Error("FindOp does not match names case-insensitively")
+	}
+}
@@ -6,11 +6,43 @@ import (
 
 // PlayerData is the serializable representation of a player's state.
 type PlayerData struct {
+	SchemaVersion int `json:"schema_version"`
+
 	UUID      string        `json:"uuid"`
 	Username  string        `json:"username"`
 	Position  PositionData  `json:"position"`
 	GameMode  uint8         `json:"gamemode"`
 	Inventory InventoryData `json:"inventory"`
+
+	// Vitals are pointers so that files saved before they were recorded
+	// load as full health and food instead of zero.
+	Health     *float32 `json:"health,omitempty"`
+	Food       *int32   `json:"food,omitempty"`
+	Saturation *float32 `json:"saturation,omitempty"`
+
+	// Home is the position saved by /sethome; nil if none was set.
+	Home *PositionData `json:"home,omitempty"`
+
+	// Experience level, points toward the next level and total points.
+	XPLevel  int32 `json:"xp_level,omitempty"`
+	XPPoints int32 `json:"xp_points,omitempty"`
+	XPTotal  int32 `json:"xp_total,omitempty"`
+}
+
+// Vitals returns the saved health, food and saturation, defaulting missing
+// values to those of a new player.
+func (pd *PlayerData) Vitals() (health float32, food int32, saturation float32) {
+	health, food, saturation = player.MaxHealth, player.MaxFood, player.DefaultSaturation
+	if pd.Health != nil {
+		health = *pd.Health
+	}
+	if pd.Food != nil {
+		food = *pd.Food
+	}
+	if pd.Saturation != nil {
+		saturation = *pd.Saturation
+	}
+	return health, food, saturation
 }
 
 // PositionData holds a player's world position and orientation.
@@ -31,15 +63,80 @@ type InventoryData struct {
 
 // SlotData is the serializable representation of an inventory slot.
 type SlotData struct {
-	BlockID    int16 `json:"block_id"`
-	ItemCount  int8  `json:"item_count"`
-	ItemDamage int16 `json:"item_damage"`
+	BlockID    int16    `json:"block_id"`
+	ItemCount  int8     `json:"item_count"`
+	ItemDamage int16    `json:"item_damage"`
+	NBT        *NBTData `json:"nbt,omitempty"`
+}
+
+// NBTData is the serializable representation of an item's enchantments and
+// display name.
+type NBTData struct {
+	DisplayName  string            `json:"display_name,omitempty"`
+	Enchantments []EnchantmentData `json:"enchantments,omitempty"`
+}
+
+// EnchantmentData is a single enchantment on an item.
+type EnchantmentData struct {
+	ID    int16 `json:"id"`
+	Level int16 `json:"level"`
+}
+
+// SlotDataFromSlot converts an inventory slot for serialization.
+func SlotDataFromSlot(s player.Slot) SlotData {
+	sd := SlotData{BlockID: s.BlockID, ItemCount: s.ItemCount, ItemDamage: s.ItemDamage}
+	if !s.NBT.IsEmpty() {
+		sd.NBT = &NBTData{DisplayName: s.NBT.DisplayName}
+		for _, e := range s.NBT.Enchantments {
+			sd.NBT.Enchantments = append(sd.NBT.Enchantments, EnchantmentData{ID: e.ID, Level: e.Level})
+		}
+	}
+	return sd
+}
+
+// Slot converts the saved slot back into an inventory slot.
+func (sd SlotData) Slot() player.Slot {
+	s := player.Slot{BlockID: sd.BlockID, ItemCount: sd.ItemCount, ItemDamage: sd.ItemDamage}
+	if sd.NBT != nil {
+		s.NBT = &player.ItemNBT{DisplayName: sd.NBT.DisplayName}
+		for _, e := range sd.NBT.Enchantments {
+			s.NBT.Enchantments = append(s.NBT.Enchantments, player.Enchantment{ID: e.ID, Level: e.Level})
+		}
+	}
+	return s
 }
 
 // WorldData holds world-level metadata for persistence.
 type WorldData struct {
 	Age       int64 `json:"age"`
 	TimeOfDay int64 `json:"time_of_day"`
+
+	// Spawn is the world spawn set by /setworldspawn; nil in worlds
+	// saved without one.
+	Spawn *SpawnData `json:"spawn,omitempty"`
+
+	// Border is the world border set with /worldborder; nil in worlds
+	// saved without one, which keep the border derived from the world
+	// radius.
+	Border *BorderData `json:"border,omitempty"`
+
+	// GameRules holds the game rules changed with /gamerule; rules not
+	// listed keep their defaults.
+	GameRules map[string]string `json:"game_rules,omitempty"`
+}
+
+// BorderData is the center and width of the world border.
+type BorderData struct {
+	CenterX  float64 `json:"center_x"`
+	CenterZ  float64 `json:"center_z"`
+	Diameter float64 `json:"diameter"`
+}
+
+// SpawnData is the block position of the world spawn.
+type SpawnData struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	Z int `json:"z"`
 }
 
 // BlockOverrideEntry is a single block override for JSON serialization.
@@ -50,14 +147,55 @@ type BlockOverrideEntry struct {
 	StateID int32 `json:"state_id"`
 }
 
+// SignEntry is the text of a single sign for JSON serialization.
+type SignEntry struct {
+	X     int       `json:"x"`
+	Y     int       `json:"y"`
+	Z     int       `json:"z"`
+	Lines [4]string `json:"lines"`
+}
+
+// BiomeOverrideEntry is a single biome column override for JSON serialization.
+type BiomeOverrideEntry struct {
+	X     int  `json:"x"`
+	Z     int  `json:"z"`
+	Biome byte `json:"biome"`
+}
+
+// ContainerEntry is the inventory of a single container block for JSON
+// serialization. Empty slots have a BlockID of -1.
+type ContainerEntry struct {
+	X     int        `json:"x"`
+	Y     int        `json:"y"`
+	Z     int        `json:"z"`
+	Slots []SlotData `json:"slots"`
+}
+
+// FurnaceEntry is the inventory and smelting progress of a single furnace
+// for JSON serialization.
+type FurnaceEntry struct {
+	X        int        `json:"x"`
+	Y        int        `json:"y"`
+	Z        int        `json:"z"`
+	Slots    []SlotData `json:"slots"`
+	BurnTime int        `json:"burn_time"`
+	BurnMax  int        `json:"burn_max"`
+	CookTime int        `json:"cook_time"`
+}
+
 // PlayerDataFromPlayer extracts serializable data from a runtime Player.
 func PlayerDataFromPlayer(p *player.Player) *PlayerData {
 	pos := p.GetPosition()
 	inv := p.Inventory
+	health := p.GetHealth()
+	food, saturation := p.GetFood()
+
+	xpLevel, xpPoints, xpTotal := p.Experience()
 
 	pd := &PlayerData{
-		UUID:     p.UUID,
-		Username: p.Username,
+		SchemaVersion: PlayerSchemaVersion,
+		UUID:          p.UUID,
+		Username:      p.Username,
 		Position: PositionData{
 			X:     pos.X,
 			Y:     pos.Y,
@@ -69,22 +207,24 @@ func PlayerDataFromPlayer(p *player.Player) *PlayerData {
 		Inventory: InventoryData{
 			HeldSlot: inv.GetHeldSlot(),
 		},
+		Health:     &health,
+		Food:       &food,
+		Saturation: &saturation,
+		XPLevel:    xpLevel,
+		XPPoints:   xpPoints,
+		XPTotal:    xpTotal,
+	}
+
+	if home, ok := p.Home(); ok {
+		pd.Home = &PositionData{X: home.X, Y: home.Y, Z: home.Z, Yaw: home.Yaw, Pitch: home.Pitch}
 	}
 
 	inv.ReadSlots(func(slots [36]player.Slot, armor [4]player.Slot) {
 		for i, s := range slots {
-			pd.Inventory.Slots[i] = SlotData{
-				BlockID:    s.BlockID,
-				ItemCount:  s.ItemCount,
-				ItemDamage: s.ItemDamage,
-			}
+			pd.Inventory.Slots[i] = SlotDataFromSlot(s)
 		}
 		for i, s := range armor {
-			pd.Inventory.Armor[i] = SlotData{
-				BlockID:    s.BlockID,
-				ItemCount:  s.ItemCount,
-				ItemDamage: s.ItemDamage,
-			}
+			pd.Inventory.Armor[i] = SlotDataFromSlot(s)
 		}
 	})
 
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadWarps reads warps.json and caches the warps for Warps, keyed by
+// lowercased name. A missing file means there are no warps.
+func (s *Storage) LoadWarps() (map[string]PositionData, error) {
+	s.warpsMu.Lock()
+	defer s.warpsMu.Unlock()
+	if err := s.loadWarpsLocked(); err != nil {
+		return nil, err
+	}
+	return maps.Clone(s.warps), nil
+}
+
+// loadWarpsLocked reads warps.json into the cache. The caller holds warpsMu.
+func (s *Storage) loadWarpsLocked() error {
+	path := filepath.Join(s.dir, "warps.json")
+	data, err := s.readData(KindConfig, path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read warps: %w", err)
+	}
+
+	var raw map[string]PositionData
+	if err == nil {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return fmt.Errorf("parse warps: %w", err)
+		}
+	}
+	warps := make(map[string]PositionData, len(raw))
+	for name, pos := range raw {
+		warps[strings.ToLower(name)] = pos
+	}
+	s.warps, s.warpsLoaded = warps, true
+	return nil
+}
+
+// Warps returns the named warp positions, reading warps.json only the first
+// time.
+func (s *Storage) Warps() (map[string]PositionData, error) {
+	s.warpsMu.Lock()
+	defer s.warpsMu.Unlock()
+	if !s.warpsLoaded {
+		if err := s.loadWarpsLocked(); err != nil {
+			return nil, err
+		}
+	}
+	return maps.Clone(s.warps), nil
+}
+
+// SaveWarps writes the named warp positions to warps.json atomically and
+// caches them for Warps.
+func (s *Storage) SaveWarps(warps map[string]PositionData) error {
+	s.warpsMu.Lock()
+	defer s.warpsMu.Unlock()
+	return s.saveWarpsLocked(maps.Clone(warps))
+}
+
+// saveWarpsLocked writes warps to warps.json and, once written, caches
+// them. The caller holds warpsMu.
+func (s *Storage) saveWarpsLocked(warps map[string]PositionData) error {
+	path := filepath.Join(s.dir, "warps.json")
+	if err := s.atomicWriteJSON(KindConfig, path, warps); err != nil {
+		return err
+	}
+	s.warps, s.warpsLoaded = warps, true
+	return nil
+}
+
+// SetWarp adds or moves the warp name and writes the warps through to
+// warps.json.
+func (s *Storage) SetWarp(name string, pos PositionData) error {
+	s.warpsMu.Lock()
+	defer s.warpsMu.Unlock()
+	if !s.warpsLoaded {
+		if err := s.loadWarpsLocked(); err != nil {
+			return err
+		}
+	}
+	warps := maps.Clone(s.warps)
+	warps[strings.ToLower(name)] = pos
+	return s.saveWarpsLocked(warps)
+}
+
+// DeleteWarp removes the warp name and writes the warps through to
+// warps.json. It reports whether the warp existed.
+func (s *Storage) DeleteWarp(name string) (bool, error) {
+	s.warpsMu.Lock()
+	defer s.warpsMu.Unlock()
+	if !s.warpsLoaded {
+		if err := s.loadWarpsLocked(); err != nil {
+			return false, err
+		}
+	}
+	name = strings.ToLower(name)
+	if _, ok := s.warps[name]; !ok {
+		return false, nil
+	}
+	warps := maps.Clone(s.warps)
+	delete(warps, name)
+	return true, s.saveWarpsLocked(warps)
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// LoadWhitelist reads whitelist.json and returns the set of lowercased
+// usernames it lists. A missing file is an empty whitelist.
+func (s *Storage) LoadWhitelist() (map[string]bool, error) {
+	path := filepath.Join(s.dir, "whitelist.json")
+	data, err := s.readData(KindConfig, path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]bool{}, nil
+		}
+		return nil, fmt.Errorf("read whitelist: %w", err)
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("parse whitelist: %w", err)
+	}
+	names := make(map[string]bool, len(list))
+	for _, name := range list {
+		names[strings.ToLower(name)] = true
+	}
+	return names, nil
+}
+
+// SaveWhitelist writes the usernames in names to whitelist.json atomically,
+// lowercased and sorted.
+func (s *Storage) SaveWhitelist(names map[string]bool) error {
+	list := make([]string, 0, len(names))
+	for name, ok := range names {
+		if ok {
+			list = append(list, strings.ToLower(name))
+		}
+	}
+	slices.Sort(list)
+	list = slices.Compact(list)
+
+	path := filepath.Join(s.dir, "whitelist.json")
+	return s.atomicWriteJSON(KindConfig, path, list)
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"sync"
+	"time"
+)
+
+// Tick timing targets and smoothing for /tps.
+const (
+	tickInterval   = 50 * time.Millisecond
+	targetTPS      = 20
+	tickStatsAlpha = 0.05 // weight of the newest sample in the moving averages
+)
+
+// tickStats keeps exponential moving averages of the real time between ticks
+// and the time spent running each tick.
+type tickStats struct {
+	mu       sync.Mutex
+	interval float64 // seconds between tick starts
+	work     float64 // seconds spent inside tick
+}
+
+func newTickStats() *tickStats {
+	return &tickStats{interval: tickInterval.Seconds()}
+}
+
+// record adds one tick that started interval after the previous one and took
+// work to run.
+func (ts *tickStats) record(interval, work time.Duration) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.interval += tickStatsAlpha * (interval.Seconds() - ts.interval)
+	ts.work += tickStatsAlpha * (work.Seconds() - ts.work)
+}
+
+// get returns the ticks per second, capped at the target rate, and the
+// average milliseconds spent per tick.
+func (ts *tickStats) get() (tps, msPerTick float64) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.interval > 0 {
+		tps = min(1/ts.interval, targetTPS)
+	}
+	return tps, ts.work * 1000
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTickStats(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		work     time.Duration
+		wantTPS  float64
+		wantMS   float64
+	}{
+		{"on time", 50 * time.Millisecond, 5 * time.Millisecond, 20, 5},
+		{"lagging", 100 * time.Millisecond, 90 * time.Millisecond, 10, 90},
+		{"catching up", 40 * time.Millisecond, 1 * time.Millisecond, 20, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTickStats()
+			for range 500 {
+				ts.record(tt.interval, tt.work)
+			}
+			tps, ms := ts.get()
+			if math.Abs(tps-tt.wantTPS) > 0.01 || math.Abs(ms-tt.wantMS) > 0.01 {
+				t.Errorf("get() = %.3f TPS, %.3f ms; want %.1f TPS, %.1f ms", tps, ms, tt.wantTPS, tt.wantMS)
+			}
+		})
+	}
+}
+
+func TestTickStats_SmoothsSpikes(t *testing.T) {
+	ts := newTickStats()
+	for range 100 {
+		ts.record(tickInterval, time.Millisecond)
+	}
+	ts.record(time.Second, 950*time.Millisecond)
+
+	tps, _ := ts.get()
+	if tps >= targetTPS || tps < 10 {
+		t.Errorf("TPS after a single one-second tick = %.2f, want a dip between 10 and 20", tps)
+	}
+}
@@ -1,6 +1,9 @@
 package gamedata
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+)
 
 var versions = map[string]func() *GameData{}
 
@@ -21,5 +24,6 @@ func RegisteredVersions() []string {
 	for name := range versions {
 		names = append(names, name)
 	}
+	sort.Strings(names)
 	return names
 }
@@ -0,0 +1,144 @@
+// Code generated by cmd/codegen; DO NOT EDIT.
+package pc_1_8
+
+// Packet IDs, grouped by protocol phase and direction.
+
+// Handshaking phase, client to server.
+const (
+	HandshakingServerboundSetProtocol          int32 = 0x00
+	HandshakingServerboundLegacyServerListPing int32 = 0xFE
+)
+
+// Status phase, server to client.
+const (
+	StatusClientboundServerInfo int32 = 0x00
+	StatusClientboundPing       int32 = 0x01
+)
+
+// Status phase, client to server.
+const (
+	StatusServerboundPingStart int32 = 0x00
+	StatusServerboundPing      int32 = 0x01
+)
+
+// Login phase, server to client.
+const (
+	LoginClientboundDisconnect      int32 = 0x00
+	LoginClientboundEncryptionBegin int32 = 0x01
+	LoginClientboundSuccess         int32 = 0x02
+	LoginClientboundCompress        int32 = 0x03
+)
+
+// Login phase, client to server.
+const (
+	LoginServerboundLoginStart      int32 = 0x00
+	LoginServerboundEncryptionBegin int32 = 0x01
+)
+
+// Play phase, server to client.
+const (
+	PlayClientboundKeepAlive                  int32 = 0x00
+	PlayClientboundLogin                      int32 = 0x01
+	PlayClientboundChat                       int32 = 0x02
+	PlayClientboundUpdateTime                 int32 = 0x03
+	PlayClientboundEntityEquipment            int32 = 0x04
+	PlayClientboundSpawnPosition              int32 = 0x05
+	PlayClientboundUpdateHealth               int32 = 0x06
+	PlayClientboundRespawn                    int32 = 0x07
+	PlayClientboundPosition                   int32 = 0x08
+	PlayClientboundHeldItemSlot               int32 = 0x09
+	PlayClientboundBed                        int32 = 0x0A
+	PlayClientboundAnimation                  int32 = 0x0B
+	PlayClientboundNamedEntitySpawn           int32 = 0x0C
+	PlayClientboundCollect                    int32 = 0x0D
+	PlayClientboundSpawnEntity                int32 = 0x0E
+	PlayClientboundSpawnEntityLiving          int32 = 0x0F
+	PlayClientboundSpawnEntityPainting        int32 = 0x10
+	PlayClientboundSpawnEntityExperienceOrb   int32 = 0x11
+	PlayClientboundEntityVelocity             int32 = 0x12
+	PlayClientboundEntityDestroy              int32 = 0x13
+	PlayClientboundEntity                     int32 = 0x14
+	PlayClientboundRelEntityMove              int32 = 0x15
+	PlayClientboundEntityLook                 int32 = 0x16
+	PlayClientboundEntityMoveLook             int32 = 0x17
+	PlayClientboundEntityTeleport             int32 = 0x18
+	PlayClientboundEntityHeadRotation         int32 = 0x19
+	PlayClientboundEntityStatus               int32 = 0x1A
+	PlayClientboundAttachEntity               int32 = 0x1B
+	PlayClientboundEntityMetadata             int32 = 0x1C
+	PlayClientboundEntityEffect               int32 = 0x1D
+	PlayClientboundRemoveEntityEffect         int32 = 0x1E
+	PlayClientboundExperience                 int32 = 0x1F
+	PlayClientboundUpdateAttributes           int32 = 0x20
+	PlayClientboundMapChunk                   int32 = 0x21
+	PlayClientboundMultiBlockChange           int32 = 0x22
+	PlayClientboundBlockChange                int32 = 0x23
+	PlayClientboundBlockAction                int32 = 0x24
+	PlayClientboundBlockBreakAnimation        int32 = 0x25
+	PlayClientboundMapChunkBulk               int32 = 0x26
+	PlayClientboundExplosion                  int32 = 0x27
+	PlayClientboundWorldEvent                 int32 = 0x28
+	PlayClientboundNamedSoundEffect           int32 = 0x29
+	PlayClientboundWorldParticles             int32 = 0x2A
+	PlayClientboundGameStateChange            int32 = 0x2B
+	PlayClientboundSpawnEntityWeather         int32 = 0x2C
+	PlayClientboundOpenWindow                 int32 = 0x2D
+	PlayClientboundCloseWindow                int32 = 0x2E
+	PlayClientboundSetSlot                    int32 = 0x2F
+	PlayClientboundWindowItems                int32 = 0x30
+	PlayClientboundCraftProgressBar           int32 = 0x31
+	PlayClientboundTransaction                int32 = 0x32
+	PlayClientboundUpdateSign                 int32 = 0x33
+	PlayClientboundMap                        int32 = 0x34
+	PlayClientboundTileEntityData             int32 = 0x35
+	PlayClientboundOpenSignEntity             int32 = 0x36
+	PlayClientboundStatistics                 int32 = 0x37
+	PlayClientboundPlayerInfo                 int32 = 0x38
+	PlayClientboundAbilities                  int32 = 0x39
+	PlayClientboundTabComplete                int32 = 0x3A
+	PlayClientboundScoreboardObjective        int32 = 0x3B
+	PlayClientboundScoreboardScore            int32 = 0x3C
+	PlayClientboundScoreboardDisplayObjective int32 = 0x3D
+	PlayClientboundScoreboardTeam             int32 = 0x3E
+	PlayClientboundCustomPayload              int32 = 0x3F
+	PlayClientboundKickDisconnect             int32 = 0x40
+	PlayClientboundDifficulty                 int32 = 0x41
+	PlayClientboundCombatEvent                int32 = 0x42
+	PlayClientboundCamera                     int32 = 0x43
+	PlayClientboundWorldBorder                int32 = 0x44
+	PlayClientboundTitle                      int32 = 0x45
+	PlayClientboundSetCompression             int32 = 0x46
+	PlayClientboundPlayerlistHeader           int32 = 0x47
+	PlayClientboundResourcePackSend           int32 = 0x48
+	PlayClientboundUpdateEntityNBT            int32 = 0x49
+)
+
+// Play phase, client to server.
+const (
+	PlayServerboundKeepAlive           int32 = 0x00
+	PlayServerboundChat                int32 = 0x01
+	PlayServerboundUseEntity           int32 = 0x02
+	PlayServerboundFlying              int32 = 0x03
+	PlayServerboundPosition            int32 = 0x04
+	PlayServerboundLook                int32 = 0x05
+	PlayServerboundPositionLook        int32 = 0x06
+	PlayServerboundBlockDig            int32 = 0x07
+	PlayServerboundBlockPlace          int32 = 0x08
+	PlayServerboundHeldItemSlot        int32 = 0x09
+	PlayServerboundArmAnimation        int32 = 0x0A
+	PlayServerboundEntityAction        int32 = 0x0B
+	PlayServerboundSteerVehicle        int32 = 0x0C
+	PlayServerboundCloseWindow         int32 = 0x0D
+	PlayServerboundWindowClick         int32 = 0x0E
+	PlayServerboundTransaction         int32 = 0x0F
+	PlayServerboundSetCreativeSlot     int32 = 0x10
+	PlayServerboundEnchantItem         int32 = 0x11
+	PlayServerboundUpdateSign          int32 = 0x12
+	PlayServerboundAbilities           int32 = 0x13
+	PlayServerboundTabComplete         int32 = 0x14
+	PlayServerboundSettings            int32 = 0x15
+	PlayServerboundClientCommand       int32 = 0x16
+	PlayServerboundCustomPayload       int32 = 0x17
+	PlayServerboundSpectate            int32 = 0x18
+	PlayServerboundResourcePackReceive int32 = 0x19
+)
@@ -1,6 +1,8 @@
 // Code generated by cmd/codegen; DO NOT EDIT.
 package pc_1_8
 
+import "github.com/go-theft-craft/server/pkg/item"
+
 type AbilitiesCB struct {
 	Flags        int8    `mc:"i8"`
 	FlyingSpeed  float32 `mc:"f32"`
@@ -76,7 +78,12 @@ type BlockDig struct {
 func (BlockDig) PacketID() int32 { return 0x07 }
 
 type BlockPlace struct {
-	Data []byte `mc:"rest"`
+	Location  int64     `mc:"position"`
+	Direction int8      `mc:"i8"`
+	HeldItem  item.Slot `mc:"slot"`
+	CursorX   int8      `mc:"i8"`
+	CursorY   int8      `mc:"i8"`
+	CursorZ   int8      `mc:"i8"`
 }
 
 func (BlockPlace) PacketID() int32 { return 0x08 }
@@ -208,7 +215,7 @@ type EntityAction struct {
 func (EntityAction) PacketID() int32 { return 0x0B }
 
 type EntityDestroy struct {
-	Data []byte `mc:"rest"`
+	EntityIDs []int32 `mc:"array,varint,varint"`
 }
 
 func (EntityDestroy) PacketID() int32 { return 0x13 }
@@ -224,7 +231,9 @@ type EntityEffect struct {
 func (EntityEffect) PacketID() int32 { return 0x1D }
 
 type EntityEquipment struct {
-	Data []byte `mc:"rest"`
+	EntityID int32     `mc:"varint"`
+	Slot     int16     `mc:"i16"`
+	Item     item.Slot `mc:"slot"`
 }
 
 func (EntityEquipment) PacketID() int32 { return 0x04 }
@@ -575,7 +584,8 @@ type SetCompression struct {
 func (SetCompression) PacketID() int32 { return 0x46 }
 
 type SetCreativeSlot struct {
-	Data []byte `mc:"rest"`
+	Slot int16     `mc:"i16"`
+	Item item.Slot `mc:"slot"`
 }
 
 func (SetCreativeSlot) PacketID() int32 { return 0x10 }
@@ -590,7 +600,9 @@ type SetProtocol struct {
 func (SetProtocol) PacketID() int32 { return 0x00 }
 
 type SetSlot struct {
-	Data []byte `mc:"rest"`
+	WindowID int8      `mc:"i8"`
+	Slot     int16     `mc:"i16"`
+	Item     item.Slot `mc:"slot"`
 }
 
 func (SetSlot) PacketID() int32 { return 0x2F }
@@ -680,13 +692,14 @@ type Success struct {
 func (Success) PacketID() int32 { return 0x02 }
 
 type TabCompleteCB struct {
-	Data []byte `mc:"rest"`
+	Matches []string `mc:"array,varint,string"`
 }
 
 func (TabCompleteCB) PacketID() int32 { return 0x3A }
 
 type TabCompleteSB struct {
-	Data []byte `mc:"rest"`
+	Text  string `mc:"string"`
+	Block *int64 `mc:"option,position"`
 }
 
 func (TabCompleteSB) PacketID() int32 { return 0x14 }
@@ -773,13 +786,19 @@ type UseEntity struct {
 func (UseEntity) PacketID() int32 { return 0x02 }
 
 type WindowClick struct {
-	Data []byte `mc:"rest"`
+	WindowID    uint8     `mc:"u8"`
+	Slot        int16     `mc:"i16"`
+	MouseButton int8      `mc:"i8"`
+	Action      int16     `mc:"i16"`
+	Mode        int8      `mc:"i8"`
+	Item        item.Slot `mc:"slot"`
 }
 
 func (WindowClick) PacketID() int32 { return 0x0E }
 
 type WindowItems struct {
-	Data []byte `mc:"rest"`
+	WindowID uint8       `mc:"u8"`
+	Items    []item.Slot `mc:"array,i16,slot"`
 }
 
 func (WindowItems) PacketID() int32 { return 0x30 }
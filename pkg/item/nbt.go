@@ -0,0 +1,111 @@
+package item
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"sync/atomic"
+
+	"github.com/go-theft-craft/server/pkg/world/nbt"
+)
+
+// Enchantment is a single enchantment on an item.
+type Enchantment struct {
+	ID    int16
+	Level int16
+}
+
+// NBT is the optional tag data carried by a slot.
+type NBT struct {
+	DisplayName  string
+	Enchantments []Enchantment
+}
+
+// IsEmpty returns true if the tag would serialize to an empty compound.
+func (n *NBT) IsEmpty() bool {
+	return n == nil || (n.DisplayName == "" && len(n.Enchantments) == 0)
+}
+
+// Equal reports whether two tags hold the same data; a nil tag equals an
+// empty one.
+func (n *NBT) Equal(o *NBT) bool {
+	if n.IsEmpty() || o.IsEmpty() {
+		return n.IsEmpty() == o.IsEmpty()
+	}
+	return n.DisplayName == o.DisplayName && slices.Equal(n.Enchantments, o.Enchantments)
+}
+
+// nbtDisabled makes WriteSlot omit item NBT for clients that cannot parse it.
+var nbtDisabled atomic.Bool
+
+// SetNBTEnabled controls whether WriteSlot includes item NBT.
+func SetNBTEnabled(enabled bool) {
+	nbtDisabled.Store(!enabled)
+}
+
+// writeNBT writes the slot's NBT compound, or the 0x00 end tag when there
+// is nothing to send.
+func writeNBT(w io.Writer, n *NBT) error {
+	if n.IsEmpty() || nbtDisabled.Load() {
+		_, err := w.Write([]byte{nbt.TagEnd})
+		return err
+	}
+
+	var buf bytes.Buffer
+	nw := nbt.NewWriter(&buf)
+	nw.BeginCompound("")
+	if len(n.Enchantments) > 0 {
+		nw.BeginList("ench", nbt.TagCompound, int32(len(n.Enchantments)))
+		for _, e := range n.Enchantments {
+			nw.WriteShort("id", e.ID)
+			nw.WriteShort("lvl", e.Level)
+			nw.EndCompound()
+		}
+	}
+	if n.DisplayName != "" {
+		nw.BeginCompound("display")
+		nw.WriteString("Name", n.DisplayName)
+		nw.EndCompound()
+	}
+	nw.EndCompound()
+	if err := nw.Err(); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// maxNBTArrayLen bounds arrays and lists in a slot's NBT. Enchantments and
+// lore never come close; anything longer is rejected before it is read.
+const maxNBTArrayLen = 1024
+
+// ReadNBT decodes a slot's NBT compound, keeping the enchantments and
+// display name. It returns nil when the tag holds neither.
+func ReadNBT(r io.Reader) (*NBT, error) {
+	root, err := nbt.DecodeCompoundLimit(r, maxNBTArrayLen)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &NBT{}
+	if ench, ok := root["ench"].([]any); ok {
+		for _, e := range ench {
+			m, ok := e.(map[string]any)
+			if !ok {
+				continue
+			}
+			id, _ := m["id"].(int16)
+			lvl, _ := m["lvl"].(int16)
+			n.Enchantments = append(n.Enchantments, Enchantment{ID: id, Level: lvl})
+		}
+	}
+	if display, ok := root["display"].(map[string]any); ok {
+		n.DisplayName, _ = display["Name"].(string)
+	}
+
+	if n.IsEmpty() {
+		return nil, nil
+	}
+	return n, nil
+}
@@ -0,0 +1,136 @@
+package item
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/go-theft-craft/server/pkg/world/nbt"
+)
+
+// excaliburNBT is the tag of a sword named Excalibur with Sharpness V, as
+// vanilla writes it, spelled out tag by tag.
+var excaliburNBT = []byte{
+	0x0A, 0x00, 0x00, // compound ""
+	0x09, 0x00, 0x04, 'e', 'n', 'c', 'h', 0x0A, 0x00, 0x00, 0x00, 0x01, // list "ench" of 1 compound
+	0x02, 0x00, 0x02, 'i', 'd', 0x00, 0x10, // short "id" = 16
+	0x02, 0x00, 0x03, 'l', 'v', 'l', 0x00, 0x05, // short "lvl" = 5
+	0x00,                                                // end of enchantment
+	0x0A, 0x00, 0x07, 'd', 'i', 's', 'p', 'l', 'a', 'y', // compound "display"
+	0x08, 0x00, 0x04, 'N', 'a', 'm', 'e', 0x00, 0x09, // string "Name", 9 bytes
+	'E', 'x', 'c', 'a', 'l', 'i', 'b', 'u', 'r',
+	0x00, // end of display
+	0x00, // end of root
+}
+
+func TestWriteSlotWithEnchantmentNBT(t *testing.T) {
+	var buf bytes.Buffer
+	slot := Slot{BlockID: 276, ItemCount: 1, NBT: &NBT{
+		DisplayName:  "Excalibur",
+		Enchantments: []Enchantment{{ID: 16, Level: 5}}, // sharpness V
+	}}
+	if err := WriteSlot(&buf, slot); err != nil {
+		t.Fatalf("WriteSlot error: %v", err)
+	}
+
+	data := buf.Bytes()
+	if len(data) < 6 || data[5] != nbt.TagCompound {
+		t.Fatalf("expected NBT compound after slot header, got % X", data)
+	}
+	if !bytes.Equal(data[5:], excaliburNBT) {
+		t.Errorf("slot NBT = % X, want % X", data[5:], excaliburNBT)
+	}
+}
+
+func TestWriteSlotEmptyNBTWritesTerminator(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSlot(&buf, Slot{BlockID: 1, ItemCount: 64, NBT: &NBT{}}); err != nil {
+		t.Fatalf("WriteSlot error: %v", err)
+	}
+	data := buf.Bytes()
+	if len(data) != 6 || data[5] != 0x00 {
+		t.Errorf("expected 6 bytes ending in 0x00, got % X", data)
+	}
+}
+
+func TestWriteSlotNBTDisabled(t *testing.T) {
+	SetNBTEnabled(false)
+	t.Cleanup(func() { SetNBTEnabled(true) })
+
+	var buf bytes.Buffer
+	slot := Slot{BlockID: 276, ItemCount: 1, NBT: &NBT{DisplayName: "Excalibur"}}
+	if err := WriteSlot(&buf, slot); err != nil {
+		t.Fatalf("WriteSlot error: %v", err)
+	}
+	data := buf.Bytes()
+	if len(data) != 6 || data[5] != 0x00 {
+		t.Errorf("expected NBT to be omitted, got % X", data)
+	}
+}
+
+func TestReadNBTRoundTrip(t *testing.T) {
+	want := &NBT{
+		DisplayName:  "Excalibur",
+		Enchantments: []Enchantment{{ID: 16, Level: 5}, {ID: 34, Level: 3}},
+	}
+	var buf bytes.Buffer
+	if err := writeNBT(&buf, want); err != nil {
+		t.Fatalf("writeNBT error: %v", err)
+	}
+
+	got, err := ReadNBT(&buf)
+	if err != nil {
+		t.Fatalf("ReadNBT error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadNBT = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadNBTFromBytes(t *testing.T) {
+	got, err := ReadNBT(bytes.NewReader(excaliburNBT))
+	if err != nil {
+		t.Fatalf("ReadNBT error: %v", err)
+	}
+	if got == nil || got.DisplayName != "Excalibur" || len(got.Enchantments) != 1 ||
+		got.Enchantments[0].ID != 16 || got.Enchantments[0].Level != 5 {
+		t.Errorf("ReadNBT = %+v, want Excalibur with enchantment 16 level 5", got)
+	}
+}
+
+func TestReadNBTWithoutKnownTags(t *testing.T) {
+	var buf bytes.Buffer
+	nw := nbt.NewWriter(&buf)
+	nw.BeginCompound("")
+	nw.WriteInt("RepairCost", 2)
+	nw.BeginList("ench", nbt.TagCompound, 0)
+	nw.EndCompound()
+
+	got, err := ReadNBT(&buf)
+	if err != nil {
+		t.Fatalf("ReadNBT error: %v", err)
+	}
+	if got != nil {
+		t.Errorf("ReadNBT = %+v, want nil", got)
+	}
+}
+
+func TestNBTEqual(t *testing.T) {
+	sharp := &NBT{Enchantments: []Enchantment{{ID: 16, Level: 5}}}
+	tests := []struct {
+		a, b *NBT
+		want bool
+	}{
+		{nil, nil, true},
+		{nil, &NBT{}, true},
+		{sharp, &NBT{Enchantments: []Enchantment{{ID: 16, Level: 5}}}, true},
+		{sharp, nil, false},
+		{sharp, &NBT{Enchantments: []Enchantment{{ID: 16, Level: 4}}}, false},
+		{&NBT{DisplayName: "A"}, &NBT{DisplayName: "B"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Equal(tt.b); got != tt.want {
+			t.Errorf("%+v.Equal(%+v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Package item holds inventory slots and their wire encoding, shared by the
+// packet codec and the server.
+package item
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-theft-craft/server/pkg/world/nbt"
+)
+
+// MaxNBTSize bounds the NBT a client may attach to a slot.
+const MaxNBTSize = 32 * 1024
+
+// ErrNBTTooLarge is returned by ReadSlot when a slot's NBT exceeds MaxNBTSize.
+var ErrNBTTooLarge = errors.New("slot nbt too large")
+
+// Slot represents a Minecraft inventory slot.
+type Slot struct {
+	BlockID    int16 // -1 = empty
+	ItemCount  int8
+	ItemDamage int16
+	NBT        *NBT // optional enchantments / display name
+}
+
+// EmptySlot is a convenience value for an empty slot.
+var EmptySlot = Slot{BlockID: -1}
+
+// IsEmpty returns true if the slot contains no item.
+func (s Slot) IsEmpty() bool {
+	return s.BlockID == -1
+}
+
+// WriteSlot writes a slot in the Minecraft protocol format.
+func WriteSlot(w io.Writer, s Slot) error {
+	if err := binary.Write(w, binary.BigEndian, s.BlockID); err != nil {
+		return err
+	}
+	if s.BlockID == -1 {
+		return nil
+	}
+	if err := binary.Write(w, binary.BigEndian, s.ItemCount); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, s.ItemDamage); err != nil {
+		return err
+	}
+	return writeNBT(w, s.NBT)
+}
+
+// ReadSlot reads a slot in the Minecraft protocol format. Enchantments and
+// the display name are kept from the slot's NBT; other tags are dropped.
+func ReadSlot(r io.Reader) (Slot, error) {
+	var blockID int16
+	if err := binary.Read(r, binary.BigEndian, &blockID); err != nil {
+		return Slot{}, fmt.Errorf("read slot block id: %w", err)
+	}
+	if blockID == -1 {
+		return EmptySlot, nil
+	}
+
+	s := Slot{BlockID: blockID}
+	if err := binary.Read(r, binary.BigEndian, &s.ItemCount); err != nil {
+		return Slot{}, fmt.Errorf("read slot count: %w", err)
+	}
+	if err := binary.Read(r, binary.BigEndian, &s.ItemDamage); err != nil {
+		return Slot{}, fmt.Errorf("read slot damage: %w", err)
+	}
+
+	// A 0x00 tag means no NBT follows; anything else starts a compound.
+	var tag [1]byte
+	if _, err := io.ReadFull(r, tag[:]); err != nil {
+		return Slot{}, fmt.Errorf("read slot nbt tag: %w", err)
+	}
+	if tag[0] == nbt.TagEnd {
+		return s, nil
+	}
+
+	lr := &io.LimitedReader{R: io.MultiReader(bytes.NewReader(tag[:]), r), N: MaxNBTSize + 1}
+	n, err := ReadNBT(lr)
+	if lr.N <= 0 {
+		return Slot{}, fmt.Errorf("%w: exceeds %d bytes", ErrNBTTooLarge, MaxNBTSize)
+	}
+	if err != nil {
+		return Slot{}, fmt.Errorf("read slot nbt: %w", err)
+	}
+	s.NBT = n
+	return s, nil
+}
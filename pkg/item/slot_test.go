@@ -0,0 +1,77 @@
+package item
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-theft-craft/server/pkg/world/nbt"
+)
+
+func TestReadSlotRoundTrip(t *testing.T) {
+	for _, want := range []Slot{
+		EmptySlot,
+		{BlockID: 1, ItemCount: 64},
+		{BlockID: 276, ItemCount: 1, ItemDamage: 12, NBT: &NBT{
+			DisplayName:  "Excalibur",
+			Enchantments: []Enchantment{{ID: 16, Level: 5}},
+		}},
+	} {
+		var buf bytes.Buffer
+		if err := WriteSlot(&buf, want); err != nil {
+			t.Fatalf("WriteSlot(%+v): %v", want, err)
+		}
+		got, err := ReadSlot(&buf)
+		if err != nil {
+			t.Fatalf("ReadSlot(%+v): %v", want, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ReadSlot = %+v, want %+v", got, want)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("ReadSlot left %d bytes unread", buf.Len())
+		}
+	}
+}
+
+func TestReadSlotDecodesVanillaNBT(t *testing.T) {
+	data := append([]byte{0x01, 0x14, 0x01, 0x00, 0x00}, excaliburNBT...) // diamond sword x1, damage 0
+	got, err := ReadSlot(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadSlot error: %v", err)
+	}
+	want := Slot{BlockID: 276, ItemCount: 1, NBT: &NBT{
+		DisplayName:  "Excalibur",
+		Enchantments: []Enchantment{{ID: 16, Level: 5}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadSlot = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadSlotRejectsOversizedNBT(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write([]byte{0x00, 0x01, 0x01, 0x00, 0x00}) // stone x1, damage 0
+	nw := nbt.NewWriter(&buf)
+	nw.BeginCompound("")
+	nw.WriteString("Padding", string(make([]byte, MaxNBTSize)))
+	nw.EndCompound()
+
+	if _, err := ReadSlot(&buf); !errors.Is(err, ErrNBTTooLarge) {
+		t.Fatalf("ReadSlot error = %v, want ErrNBTTooLarge", err)
+	}
+}
+
+func TestReadSlotRejectsLongNBTArray(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write([]byte{0x00, 0x01, 0x01, 0x00, 0x00}) // stone x1, damage 0
+	nw := nbt.NewWriter(&buf)
+	nw.BeginCompound("")
+	nw.WriteIntArray("Data", make([]int32, maxNBTArrayLen+1))
+	nw.EndCompound()
+
+	if _, err := ReadSlot(&buf); !errors.Is(err, nbt.ErrInvalid) {
+		t.Fatalf("ReadSlot error = %v, want nbt.ErrInvalid", err)
+	}
+}
@@ -0,0 +1,119 @@
+package protocol
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+)
+
+// maxPacketSize bounds both the framed and the decompressed size of a packet.
+const maxPacketSize = 1 << 21 // 2MB
+
+// ReadCompressedPacket reads a packet in the framing used once compression is
+// enabled: packet length, data length, then the packet ID and data. A data
+// length of 0 means the rest is not compressed; otherwise it is the size of
+// the zlib-compressed ID and data and must be at least threshold.
+func ReadCompressedPacket(r io.Reader, threshold int) (packetID int32, data []byte, err error) {
+	length, _, err := ReadVarInt(r)
+	if err != nil {
+		return 0, nil, fmt.Errorf("read packet length: %w", err)
+	}
+	if length < 1 {
+		return 0, nil, fmt.Errorf("%w: packet length too small: %d", ErrMalformedPacket, length)
+	}
+	if length > maxPacketSize {
+		return 0, nil, fmt.Errorf("%w: packet too large: %d bytes", ErrMalformedPacket, length)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, fmt.Errorf("read packet payload: %w", err)
+	}
+
+	buf := bytes.NewReader(payload)
+	dataLength, _, err := ReadVarInt(buf)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: read data length: %w", ErrMalformedPacket, err)
+	}
+
+	body := payload[len(payload)-buf.Len():]
+	if dataLength != 0 {
+		if int(dataLength) < threshold {
+			return 0, nil, fmt.Errorf("%w: compressed packet of %d bytes is below threshold %d", ErrMalformedPacket, dataLength, threshold)
+		}
+		if dataLength > maxPacketSize {
+			return 0, nil, fmt.Errorf("%w: decompressed packet too large: %d bytes", ErrMalformedPacket, dataLength)
+		}
+		if body, err = inflate(body, int(dataLength)); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	br := bytes.NewReader(body)
+	packetID, _, err = ReadVarInt(br)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: read packet ID: %w", ErrMalformedPacket, err)
+	}
+	return packetID, body[len(body)-br.Len():], nil
+}
+
+// inflate decompresses a zlib stream that must decode to exactly size bytes.
+func inflate(compressed []byte, size int) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("%w: open zlib stream: %w", ErrMalformedPacket, err)
+	}
+	defer zr.Close()
+
+	out := make([]byte, size)
+	if _, err := io.ReadFull(zr, out); err != nil {
+		return nil, fmt.Errorf("%w: decompress packet: %w", ErrMalformedPacket, err)
+	}
+	if n, _ := zr.Read(make([]byte, 1)); n != 0 {
+		return nil, fmt.Errorf("%w: decompressed packet longer than %d bytes", ErrMalformedPacket, size)
+	}
+	return out, nil
+}
+
+// WriteCompressedPacket writes a packet in the compressed framing. The packet
+// ID and data are zlib-compressed when together they are at least threshold
+// bytes, and sent uncompressed with a data length of 0 otherwise.
+func WriteCompressedPacket(w io.Writer, packetID int32, data []byte, threshold int) error {
+	var body bytes.Buffer
+	if _, err := WriteVarInt(&body, packetID); err != nil {
+		return fmt.Errorf("write packet ID: %w", err)
+	}
+	body.Write(data)
+
+	dataLength := 0
+	payload := body.Bytes()
+	if body.Len() >= threshold {
+		dataLength = body.Len()
+		var zbuf bytes.Buffer
+		zw := zlib.NewWriter(&zbuf)
+		if _, err := zw.Write(payload); err != nil {
+			return fmt.Errorf("compress packet: %w", err)
+		}
+		if err := zw.Close(); err != nil {
+			return fmt.Errorf("compress packet: %w", err)
+		}
+		payload = zbuf.Bytes()
+	}
+
+	totalLen := VarIntSize(int32(dataLength)) + len(payload)
+	var buf bytes.Buffer
+	buf.Grow(VarIntSize(int32(totalLen)) + totalLen)
+	if _, err := WriteVarInt(&buf, int32(totalLen)); err != nil {
+		return fmt.Errorf("write packet length: %w", err)
+	}
+	if _, err := WriteVarInt(&buf, int32(dataLength)); err != nil {
+		return fmt.Errorf("write data length: %w", err)
+	}
+	buf.Write(payload)
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("flush packet: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package protocol
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestCompressedPacket_LargeRoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte("chunk data "), 1000)
+
+	var buf bytes.Buffer
+	if err := WriteCompressedPacket(&buf, 0x21, data, 256); err != nil {
+		t.Fatalf("WriteCompressedPacket: %v", err)
+	}
+	if buf.Len() >= len(data) {
+		t.Errorf("framed size %d, want smaller than %d bytes of data", buf.Len(), len(data))
+	}
+
+	// The data length field holds the uncompressed ID + data size.
+	r := bytes.NewReader(buf.Bytes())
+	_, _, _ = ReadVarInt(r)
+	if dataLength, _, _ := ReadVarInt(r); int(dataLength) != 1+len(data) {
+		t.Errorf("data length = %d, want %d", dataLength, 1+len(data))
+	}
+
+	id, got, err := ReadCompressedPacket(&buf, 256)
+	if err != nil {
+		t.Fatalf("ReadCompressedPacket: %v", err)
+	}
+	if id != 0x21 || !bytes.Equal(got, data) {
+		t.Errorf("round trip = 0x%02X, %d bytes; want 0x21, %d bytes", id, len(got), len(data))
+	}
+}
+
+func TestCompressedPacket_SmallUncompressed(t *testing.T) {
+	data := []byte{0x00, 0x00, 0x00, 0x2A}
+
+	var buf bytes.Buffer
+	if err := WriteCompressedPacket(&buf, 0x00, data, 256); err != nil {
+		t.Fatalf("WriteCompressedPacket: %v", err)
+	}
+
+	// packet length, data length 0, packet ID, data
+	want := append([]byte{byte(2 + len(data)), 0x00, 0x00}, data...)
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("framed = %X, want %X", buf.Bytes(), want)
+	}
+
+	id, got, err := ReadCompressedPacket(&buf, 256)
+	if err != nil {
+		t.Fatalf("ReadCompressedPacket: %v", err)
+	}
+	if id != 0x00 || !bytes.Equal(got, data) {
+		t.Errorf("round trip = 0x%02X, %X; want 0x00, %X", id, got, data)
+	}
+}
+
+func TestReadCompressedPacket_RejectsBelowThreshold(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCompressedPacket(&buf, 0x01, []byte("tiny"), 1); err != nil {
+		t.Fatalf("WriteCompressedPacket: %v", err)
+	}
+
+	if _, _, err := ReadCompressedPacket(&buf, 256); !errors.Is(err, ErrMalformedPacket) {
+		t.Errorf("ReadCompressedPacket error = %v, want ErrMalformedPacket", err)
+	}
+}
@@ -0,0 +1,6 @@
+package protocol
+
+import "errors"
+
+// ErrMalformedPacket indicates packet framing or field data that cannot be decoded.
+var ErrMalformedPacket = errors.New("malformed packet")
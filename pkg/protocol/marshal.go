@@ -59,7 +59,9 @@ func Unmarshal(data []byte, p Packet) error {
 
 		val, err := ReadField(r, tag)
 		if err != nil {
-			return fmt.Errorf("unmarshal field %s: %w", field.Name, err)
+			// The payload is already in memory, so any read failure means the
+			// packet itself is truncated or invalid.
+			return fmt.Errorf("%w: unmarshal field %s: %w", ErrMalformedPacket, field.Name, err)
 		}
 
 		fv := v.Field(i)
@@ -1,7 +1,11 @@
 package protocol
 
 import (
+	"bytes"
+	"reflect"
 	"testing"
+
+	"github.com/go-theft-craft/server/pkg/item"
 )
 
 type testPacket struct {
@@ -104,3 +108,106 @@ func TestMarshalRest(t *testing.T) {
 		t.Errorf("Data mismatch: got %x, want %x", decoded.Data, original.Data)
 	}
 }
+
+type testCompositePacket struct {
+	IDs   []int32  `mc:"array,varint,varint"`
+	Names []string `mc:"array,i16,string"`
+	Block *int64   `mc:"option,position"`
+	Skip  *bool    `mc:"option,bool"`
+}
+
+func (testCompositePacket) PacketID() int32 { return 0x02 }
+
+func TestMarshalArrayAndOption(t *testing.T) {
+	block := int64(42)
+	original := &testCompositePacket{
+		IDs:   []int32{1, 300, -1},
+		Names: []string{"a", "bc"},
+		Block: &block,
+	}
+
+	data, err := Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	decoded := &testCompositePacket{}
+	if err := Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded.IDs, original.IDs) {
+		t.Errorf("IDs = %v, want %v", decoded.IDs, original.IDs)
+	}
+	if !reflect.DeepEqual(decoded.Names, original.Names) {
+		t.Errorf("Names = %v, want %v", decoded.Names, original.Names)
+	}
+	if decoded.Block == nil || *decoded.Block != 42 {
+		t.Errorf("Block = %v, want 42", decoded.Block)
+	}
+	if decoded.Skip != nil {
+		t.Errorf("Skip = %v, want absent", *decoded.Skip)
+	}
+}
+
+func TestUnmarshalArrayRejectsNegativeLength(t *testing.T) {
+	var buf bytes.Buffer
+	_, _ = WriteVarInt(&buf, -1)
+
+	if err := Unmarshal(buf.Bytes(), &testCompositePacket{}); err == nil {
+		t.Fatal("expected error for a negative array length")
+	}
+}
+
+type testSlotPacket struct {
+	Slot  int16       `mc:"i16"`
+	Item  item.Slot   `mc:"slot"`
+	Items []item.Slot `mc:"array,i16,slot"`
+}
+
+func (testSlotPacket) PacketID() int32 { return 0x03 }
+
+func TestMarshalSlot(t *testing.T) {
+	sword := item.Slot{BlockID: 276, ItemCount: 1, ItemDamage: 7, NBT: &item.NBT{
+		DisplayName:  "Excalibur",
+		Enchantments: []item.Enchantment{{ID: 16, Level: 5}},
+	}}
+	original := &testSlotPacket{
+		Slot:  36,
+		Item:  sword,
+		Items: []item.Slot{item.EmptySlot, {BlockID: 1, ItemCount: 64}, sword},
+	}
+
+	data, err := Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	decoded := &testSlotPacket{}
+	if err := Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, original) {
+		t.Errorf("decoded = %+v, want %+v", decoded, original)
+	}
+}
+
+func TestMarshalEmptySlot(t *testing.T) {
+	data, err := Marshal(&testSlotPacket{Item: item.EmptySlot})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	// i16 slot, i16 -1 item and an empty i16-prefixed array.
+	want := []byte{0x00, 0x00, 0xFF, 0xFF, 0x00, 0x00}
+	if !bytes.Equal(data, want) {
+		t.Fatalf("data = % X, want % X", data, want)
+	}
+
+	decoded := &testSlotPacket{}
+	if err := Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !decoded.Item.IsEmpty() || decoded.Item.NBT != nil {
+		t.Errorf("item = %+v, want empty", decoded.Item)
+	}
+}
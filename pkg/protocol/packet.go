@@ -5,6 +5,11 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/go-theft-craft/server/pkg/item"
 )
 
 type Packet interface {
@@ -17,10 +22,10 @@ func ReadRawPacket(r io.Reader) (packetID int32, data []byte, err error) {
 		return 0, nil, fmt.Errorf("read packet length: %w", err)
 	}
 	if length < 1 {
-		return 0, nil, fmt.Errorf("packet length too small: %d", length)
+		return 0, nil, fmt.Errorf("%w: packet length too small: %d", ErrMalformedPacket, length)
 	}
-	if length > 1<<21 { // 2MB max
-		return 0, nil, fmt.Errorf("packet too large: %d bytes", length)
+	if length > maxPacketSize {
+		return 0, nil, fmt.Errorf("%w: packet too large: %d bytes", ErrMalformedPacket, length)
 	}
 
 	payload := make([]byte, length)
@@ -31,7 +36,7 @@ func ReadRawPacket(r io.Reader) (packetID int32, data []byte, err error) {
 	buf := bytes.NewReader(payload)
 	packetID, _, err = ReadVarInt(buf)
 	if err != nil {
-		return 0, nil, fmt.Errorf("read packet ID: %w", err)
+		return 0, nil, fmt.Errorf("%w: read packet ID: %w", ErrMalformedPacket, err)
 	}
 
 	remaining := make([]byte, buf.Len())
@@ -84,7 +89,15 @@ func ReadPacket(r io.Reader, p Packet) error {
 	return Unmarshal(data, p)
 }
 
+// WriteField writes val using the encoding named by tag. Besides the scalar
+// tags ("slot" writes an item.Slot), "array,<count>,<elem>" writes a slice prefixed by its length encoded
+// as <count>, and "option,<elem>" writes a bool followed by the pointed-to
+// value when the pointer is non-nil.
 func WriteField(w io.Writer, tag string, val any) error {
+	if kind, rest, ok := strings.Cut(tag, ","); ok {
+		return writeCompositeField(w, kind, rest, val)
+	}
+
 	switch tag {
 	case "varint":
 		_, err := WriteVarInt(w, val.(int32))
@@ -125,6 +138,8 @@ func WriteField(w io.Writer, tag string, val any) error {
 	case "bytearray":
 		_, err := WriteByteArray(w, val.([]byte))
 		return err
+	case "slot":
+		return item.WriteSlot(w, val.(item.Slot))
 	case "rest":
 		_, err := w.Write(val.([]byte))
 		return err
@@ -133,7 +148,12 @@ func WriteField(w io.Writer, tag string, val any) error {
 	}
 }
 
+// ReadField reads a value encoded as tag; see WriteField for the tags.
 func ReadField(r io.Reader, tag string) (any, error) {
+	if kind, rest, ok := strings.Cut(tag, ","); ok {
+		return readCompositeField(r, kind, rest)
+	}
+
 	switch tag {
 	case "varint":
 		v, _, err := ReadVarInt(r)
@@ -167,9 +187,152 @@ func ReadField(r io.Reader, tag string) (any, error) {
 		return ReadUUID(r)
 	case "bytearray":
 		return ReadByteArray(r)
+	case "slot":
+		return item.ReadSlot(r)
 	case "rest":
 		return io.ReadAll(r)
 	default:
 		return nil, fmt.Errorf("unknown field tag: %q", tag)
 	}
 }
+
+// fieldTypes maps scalar tags to the Go type ReadField returns for them.
+var fieldTypes = map[string]reflect.Type{
+	"varint":    reflect.TypeFor[int32](),
+	"varlong":   reflect.TypeFor[int64](),
+	"i8":        reflect.TypeFor[int8](),
+	"u8":        reflect.TypeFor[uint8](),
+	"i16":       reflect.TypeFor[int16](),
+	"u16":       reflect.TypeFor[uint16](),
+	"i32":       reflect.TypeFor[int32](),
+	"i64":       reflect.TypeFor[int64](),
+	"f32":       reflect.TypeFor[float32](),
+	"f64":       reflect.TypeFor[float64](),
+	"bool":      reflect.TypeFor[bool](),
+	"string":    reflect.TypeFor[string](),
+	"position":  reflect.TypeFor[int64](),
+	"uuid":      reflect.TypeFor[[16]byte](),
+	"bytearray": reflect.TypeFor[[]byte](),
+	"slot":      reflect.TypeFor[item.Slot](),
+}
+
+func writeCompositeField(w io.Writer, kind, rest string, val any) error {
+	switch kind {
+	case "array":
+		countTag, elemTag, ok := strings.Cut(rest, ",")
+		if !ok {
+			return fmt.Errorf("array tag %q: missing element type", rest)
+		}
+		v := reflect.ValueOf(val)
+		if v.Kind() != reflect.Slice {
+			return fmt.Errorf("array field: expected slice, got %T", val)
+		}
+		count, err := arrayCount(countTag, v.Len())
+		if err != nil {
+			return err
+		}
+		if err := WriteField(w, countTag, count); err != nil {
+			return fmt.Errorf("write array length: %w", err)
+		}
+		for i := range v.Len() {
+			if err := WriteField(w, elemTag, v.Index(i).Interface()); err != nil {
+				return fmt.Errorf("write array element %d: %w", i, err)
+			}
+		}
+		return nil
+	case "option":
+		v := reflect.ValueOf(val)
+		if v.Kind() != reflect.Pointer {
+			return fmt.Errorf("option field: expected pointer, got %T", val)
+		}
+		if err := WriteField(w, "bool", !v.IsNil()); err != nil {
+			return err
+		}
+		if v.IsNil() {
+			return nil
+		}
+		return WriteField(w, rest, v.Elem().Interface())
+	default:
+		return fmt.Errorf("unknown field tag: %q", kind+","+rest)
+	}
+}
+
+func readCompositeField(r io.Reader, kind, rest string) (any, error) {
+	switch kind {
+	case "array":
+		countTag, elemTag, ok := strings.Cut(rest, ",")
+		if !ok {
+			return nil, fmt.Errorf("array tag %q: missing element type", rest)
+		}
+		elemType, ok := fieldTypes[elemTag]
+		if !ok {
+			return nil, fmt.Errorf("unknown array element tag: %q", elemTag)
+		}
+		raw, err := ReadField(r, countTag)
+		if err != nil {
+			return nil, fmt.Errorf("read array length: %w", err)
+		}
+		count := reflect.ValueOf(raw)
+		if !count.CanInt() {
+			return nil, fmt.Errorf("array length tag %q is not an integer", countTag)
+		}
+		n := count.Int()
+		if n < 0 || n > maxPacketSize {
+			return nil, fmt.Errorf("invalid array length %d", n)
+		}
+		// Grow as elements arrive so a bogus length cannot force a huge
+		// allocation up front.
+		slice := reflect.MakeSlice(reflect.SliceOf(elemType), 0, int(min(n, 64)))
+		for i := range n {
+			elem, err := ReadField(r, elemTag)
+			if err != nil {
+				return nil, fmt.Errorf("read array element %d: %w", i, err)
+			}
+			slice = reflect.Append(slice, reflect.ValueOf(elem))
+		}
+		return slice.Interface(), nil
+	case "option":
+		elemType, ok := fieldTypes[rest]
+		if !ok {
+			return nil, fmt.Errorf("unknown option element tag: %q", rest)
+		}
+		present, err := ReadBool(r)
+		if err != nil {
+			return nil, err
+		}
+		ptr := reflect.New(elemType)
+		if !present {
+			return reflect.Zero(ptr.Type()).Interface(), nil
+		}
+		elem, err := ReadField(r, rest)
+		if err != nil {
+			return nil, err
+		}
+		ptr.Elem().Set(reflect.ValueOf(elem))
+		return ptr.Interface(), nil
+	default:
+		return nil, fmt.Errorf("unknown field tag: %q", kind+","+rest)
+	}
+}
+
+// arrayCount converts a slice length to the Go type of the count tag.
+func arrayCount(tag string, n int) (any, error) {
+	limits := map[string]int{"varint": math.MaxInt32, "i32": math.MaxInt32, "i16": math.MaxInt16, "i8": math.MaxInt8, "u8": math.MaxUint8}
+	limit, ok := limits[tag]
+	if !ok {
+		return nil, fmt.Errorf("unsupported array length tag: %q", tag)
+	}
+	if n > limit {
+		return nil, fmt.Errorf("array of %d elements does not fit a %s length", n, tag)
+	}
+	switch tag {
+	case "i16":
+		return int16(n), nil
+	case "i8":
+		return int8(n), nil
+	case "u8":
+		return uint8(n), nil
+	default:
+		return int32(n), nil
+	}
+}
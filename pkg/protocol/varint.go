@@ -24,7 +24,7 @@ func ReadVarInt(r io.Reader) (int32, int, error) {
 		}
 
 		if numRead >= 5 {
-			return 0, numRead, fmt.Errorf("VarInt too long")
+			return 0, numRead, fmt.Errorf("%w: VarInt too long", ErrMalformedPacket)
 		}
 	}
 
@@ -86,7 +86,7 @@ func ReadVarLong(r io.Reader) (int64, int, error) {
 		}
 
 		if numRead >= 10 {
-			return 0, numRead, fmt.Errorf("VarLong too long")
+			return 0, numRead, fmt.Errorf("%w: VarLong too long", ErrMalformedPacket)
 		}
 	}
 
@@ -139,7 +139,7 @@ func ReadString(r io.Reader) (string, error) {
 		return "", fmt.Errorf("read string length: %w", err)
 	}
 	if length < 0 || length > 32767*4 {
-		return "", fmt.Errorf("string length out of range: %d", length)
+		return "", fmt.Errorf("%w: string length out of range: %d", ErrMalformedPacket, length)
 	}
 	buf := make([]byte, length)
 	if _, err := io.ReadFull(r, buf); err != nil {
@@ -163,7 +163,7 @@ func ReadByteArray(r io.Reader) ([]byte, error) {
 		return nil, fmt.Errorf("read byte array length: %w", err)
 	}
 	if length < 0 {
-		return nil, fmt.Errorf("negative byte array length: %d", length)
+		return nil, fmt.Errorf("%w: negative byte array length: %d", ErrMalformedPacket, length)
 	}
 	buf := make([]byte, length)
 	if _, err := io.ReadFull(r, buf); err != nil {
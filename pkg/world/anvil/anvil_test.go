@@ -4,13 +4,16 @@ import (
 	"bytes"
 	"compress/zlib"
 	"encoding/binary"
+	"errors"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/go-theft-craft/server/pkg/world"
 	"github.com/go-theft-craft/server/pkg/world/gen"
+	"github.com/go-theft-craft/server/pkg/world/nbt"
 )
 
 func TestSetNibble(t *testing.T) {
@@ -244,3 +247,148 @@ func TestSaveRegionMultipleChunks(t *testing.T) {
 		t.Fatalf("region file too small: %d bytes (expected at least %d)", info.Size(), minSize)
 	}
 }
+
+func TestSaveAndLoadRegion(t *testing.T) {
+	dir := t.TempDir()
+
+	chunk := &gen.ChunkData{}
+	chunk.SetBlock(0, 0, 0, 0x10)    // stone
+	chunk.SetBlock(5, 70, 9, 0x12C5) // block ID 300, meta 5
+	chunk.SetBlock(15, 255, 15, 0x23)
+	chunk.SetBiome(3, 4, 12)
+
+	overrides := map[world.BlockPos]int32{
+		{X: 33, Y: 10, Z: -30}: 0x30, // dirt in an otherwise empty section
+	}
+	nbtData, err := EncodeChunkNBT(1, -1, chunk, overrides)
+	if err != nil {
+		t.Fatalf("encode chunk: %v", err)
+	}
+	if err := SaveRegion(dir, 0, -1, map[gen.ChunkPos][]byte{{X: 1, Z: -1}: nbtData}); err != nil {
+		t.Fatalf("SaveRegion failed: %v", err)
+	}
+
+	// The saved chunk has the override baked in.
+	chunk.SetBlock(1, 10, 2, 0x30)
+
+	chunks, err := LoadRegion(dir, 0, -1)
+	if err != nil {
+		t.Fatalf("LoadRegion failed: %v", err)
+	}
+	if len(chunks) != 1 {
+		t.Fatalf("LoadRegion returned %d chunks, want 1", len(chunks))
+	}
+	assertChunkEqual(t, chunks[gen.ChunkPos{X: 1, Z: -1}], chunk)
+
+	loaded, err := LoadChunk(dir, 1, -1)
+	if err != nil {
+		t.Fatalf("LoadChunk failed: %v", err)
+	}
+	assertChunkEqual(t, loaded, chunk)
+}
+
+func TestSaveRegionKeepsOtherChunks(t *testing.T) {
+	dir := t.TempDir()
+	save := func(cx int, state uint16) {
+		chunk := &gen.ChunkData{}
+		chunk.SetBlock(0, 0, 0, state)
+		nbtData, err := EncodeChunkNBT(cx, 0, chunk, nil)
+		if err != nil {
+			t.Fatalf("encode chunk: %v", err)
+		}
+		if err := SaveRegion(dir, 0, 0, map[gen.ChunkPos][]byte{{X: cx, Z: 0}: nbtData}); err != nil {
+			t.Fatalf("SaveRegion failed: %v", err)
+		}
+	}
+	save(0, 0x10)
+	save(1, 0x20)
+	save(0, 0x30) // replaces chunk 0 only
+
+	chunks, err := LoadRegion(dir, 0, 0)
+	if err != nil {
+		t.Fatalf("LoadRegion failed: %v", err)
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("LoadRegion returned %d chunks, want 2", len(chunks))
+	}
+	if got := chunks[gen.ChunkPos{X: 0, Z: 0}].GetBlock(0, 0, 0); got != 0x30 {
+		t.Errorf("chunk (0,0) block = 0x%X, want 0x30", got)
+	}
+	if got := chunks[gen.ChunkPos{X: 1, Z: 0}].GetBlock(0, 0, 0); got != 0x20 {
+		t.Errorf("chunk (1,0) block = 0x%X, want 0x20", got)
+	}
+}
+
+func TestLoadChunkNotSaved(t *testing.T) {
+	dir := t.TempDir()
+
+	if c, err := LoadChunk(dir, 0, 0); c != nil || err != nil {
+		t.Fatalf("LoadChunk without region = (%v, %v), want (nil, nil)", c, err)
+	}
+	if _, err := LoadRegion(dir, 0, 0); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("LoadRegion without region: err = %v, want ErrNotExist", err)
+	}
+
+	nbtData, err := EncodeChunkNBT(0, 0, &gen.ChunkData{}, nil)
+	if err != nil {
+		t.Fatalf("encode chunk: %v", err)
+	}
+	if err := SaveRegion(dir, 0, 0, map[gen.ChunkPos][]byte{{X: 0, Z: 0}: nbtData}); err != nil {
+		t.Fatalf("SaveRegion failed: %v", err)
+	}
+	if c, err := LoadChunk(dir, 1, 0); c != nil || err != nil {
+		t.Fatalf("LoadChunk of missing chunk = (%v, %v), want (nil, nil)", c, err)
+	}
+}
+
+// assertChunkEqual compares the blocks and biomes of two chunks.
+func assertChunkEqual(t *testing.T, got, want *gen.ChunkData) {
+	t.Helper()
+	if got == nil {
+		t.Fatal("chunk is nil")
+	}
+	for x := 0; x < 16; x++ {
+		for y := 0; y < 256; y++ {
+			for z := 0; z < 16; z++ {
+				if g, w := got.GetBlock(x, y, z), want.GetBlock(x, y, z); g != w {
+					t.Fatalf("block at (%d,%d,%d) = 0x%X, want 0x%X", x, y, z, g, w)
+				}
+			}
+		}
+	}
+	if got.Biomes != want.Biomes {
+		t.Error("biomes differ")
+	}
+}
+
+func TestEncodeChunkNBT_Signs(t *testing.T) {
+	chunk := &gen.ChunkData{}
+	chunk.SetBlock(2, 5, 3, world.BlockStandingSign<<4)
+	chunk.Signs = []gen.Sign{{X: 18, Y: 5, Z: 3, Lines: [4]string{"Welcome", "to \"spawn\"", "", "!"}}}
+
+	data, err := EncodeChunkNBT(1, 0, chunk, nil)
+	if err != nil {
+		t.Fatalf("EncodeChunkNBT failed: %v", err)
+	}
+
+	root, err := nbt.DecodeCompound(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode nbt: %v", err)
+	}
+	tiles, _ := root["Level"].(map[string]any)["TileEntities"].([]any)
+	if len(tiles) != 1 {
+		t.Fatalf("TileEntities has %d entries, want 1", len(tiles))
+	}
+	te := tiles[0].(map[string]any)
+	if te["id"] != "Sign" || te["x"] != int32(18) || te["Text1"] != `{"text":"Welcome"}` {
+		t.Errorf("tile entity = %v, want a sign at x=18 with chat JSON text", te)
+	}
+
+	_, _, decoded, err := DecodeChunkNBT(data)
+	if err != nil {
+		t.Fatalf("DecodeChunkNBT failed: %v", err)
+	}
+	if len(decoded.Signs) != 1 || decoded.Signs[0] != chunk.Signs[0] {
+		t.Errorf("decoded signs = %+v, want %+v", decoded.Signs, chunk.Signs)
+	}
+}
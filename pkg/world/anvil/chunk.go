@@ -2,6 +2,8 @@ package anvil
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 
 	"github.com/go-theft-craft/server/pkg/world"
 	"github.com/go-theft-craft/server/pkg/world/gen"
@@ -90,7 +92,8 @@ func EncodeChunkNBT(cx, cz int, chunk *gen.ChunkData, overrides map[world.BlockP
 			setNibble(data, i, meta)
 		}
 
-		w.BeginCompound("")
+		// List elements have no tag header; each section is just its tags
+		// followed by an End tag.
 		w.WriteTagByte("Y", byte(secY))
 		w.WriteByteArray("Blocks", blocks)
 
@@ -118,18 +121,18 @@ func EncodeChunkNBT(cx, cz int, chunk *gen.ChunkData, overrides map[world.BlockP
 
 		w.WriteByteArray("Data", data)
 
-		// Full brightness.
-		light := make([]byte, 2048)
-		for i := range light {
-			light[i] = 0xFF
-		}
-		w.WriteByteArray("BlockLight", light)
-
-		skyLight := make([]byte, 2048)
-		for i := range skyLight {
-			skyLight[i] = 0xFF
+		// Computed light if available, otherwise full brightness.
+		if sec != nil && chunk.Lit {
+			w.WriteByteArray("BlockLight", sec.BlockLight[:])
+			w.WriteByteArray("SkyLight", sec.SkyLight[:])
+		} else {
+			light := make([]byte, 2048)
+			for i := range light {
+				light[i] = 0xFF
+			}
+			w.WriteByteArray("BlockLight", light)
+			w.WriteByteArray("SkyLight", light)
 		}
-		w.WriteByteArray("SkyLight", skyLight)
 
 		w.EndCompound()
 	}
@@ -141,6 +144,19 @@ func EncodeChunkNBT(cx, cz int, chunk *gen.ChunkData, overrides map[world.BlockP
 	heightMap := computeHeightMap(chunk, overrides)
 	w.WriteIntArray("HeightMap", heightMap)
 
+	// Tile entities: only signs carry state.
+	w.BeginList("TileEntities", nbt.TagCompound, int32(len(chunk.Signs)))
+	for _, sign := range chunk.Signs {
+		w.WriteString("id", "Sign")
+		w.WriteInt("x", int32(sign.X))
+		w.WriteInt("y", int32(sign.Y))
+		w.WriteInt("z", int32(sign.Z))
+		for i, line := range sign.Lines {
+			w.WriteString(fmt.Sprintf("Text%d", i+1), signLineJSON(line))
+		}
+		w.EndCompound()
+	}
+
 	w.EndCompound() // Level
 	w.EndCompound() // root
 
@@ -151,6 +167,15 @@ func EncodeChunkNBT(cx, cz int, chunk *gen.ChunkData, overrides map[world.BlockP
 	return buf.Bytes(), nil
 }
 
+// signLineJSON encodes a line of sign text as the chat component vanilla
+// stores in Text1-Text4.
+func signLineJSON(line string) string {
+	data, _ := json.Marshal(struct {
+		Text string `json:"text"`
+	}{line})
+	return string(data)
+}
+
 // setNibble sets a 4-bit value at the given block index in a nibble array.
 func setNibble(arr []byte, index int, val byte) {
 	byteIdx := index / 2
@@ -0,0 +1,222 @@
+package anvil
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/go-theft-craft/server/internal/server/chat"
+	"github.com/go-theft-craft/server/pkg/world/gen"
+	"github.com/go-theft-craft/server/pkg/world/nbt"
+)
+
+const compressionGzip = 1
+
+// regionPath returns the path of region (rx, rz) in dir.
+func regionPath(dir string, rx, rz int) string {
+	return filepath.Join(dir, fmt.Sprintf("r.%d.%d.mca", rx, rz))
+}
+
+// LoadRegion reads every chunk stored in region (rx, rz). It returns an error
+// wrapping fs.ErrNotExist if the region was never saved.
+func LoadRegion(dir string, rx, rz int) (map[gen.ChunkPos]*gen.ChunkData, error) {
+	data, err := os.ReadFile(regionPath(dir, rx, rz))
+	if err != nil {
+		return nil, fmt.Errorf("read region file: %w", err)
+	}
+	if len(data) < headerSectors*sectorSize {
+		return nil, fmt.Errorf("region file (%d,%d) is truncated", rx, rz)
+	}
+
+	chunks := make(map[gen.ChunkPos]*gen.ChunkData)
+	for idx := 0; idx < 1024; idx++ {
+		entry := binary.BigEndian.Uint32(data[idx*4:])
+		if entry == 0 {
+			continue
+		}
+		start, end := int(entry>>8)*sectorSize, int(entry>>8+entry&0xFF)*sectorSize
+		if end > len(data) {
+			return nil, fmt.Errorf("chunk %d of region (%d,%d) lies past the end of the file", idx, rx, rz)
+		}
+
+		chunk, err := decodeStoredChunk(data[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("chunk %d of region (%d,%d): %w", idx, rx, rz, err)
+		}
+		chunks[gen.ChunkPos{X: rx*32 + idx%32, Z: rz*32 + idx/32}] = chunk
+	}
+	return chunks, nil
+}
+
+// LoadChunk reads chunk (cx, cz) from its region file without decoding the
+// rest of the region. It returns nil if the chunk was never saved.
+func LoadChunk(dir string, cx, cz int) (*gen.ChunkData, error) {
+	f, err := os.Open(regionPath(dir, cx>>5, cz>>5))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open region file: %w", err)
+	}
+	defer f.Close()
+
+	var loc [4]byte
+	if _, err := f.ReadAt(loc[:], int64((cx&31)+(cz&31)*32)*4); err != nil {
+		return nil, fmt.Errorf("read chunk location: %w", err)
+	}
+	entry := binary.BigEndian.Uint32(loc[:])
+	if entry == 0 {
+		return nil, nil
+	}
+
+	sectors := make([]byte, int(entry&0xFF)*sectorSize)
+	if _, err := f.ReadAt(sectors, int64(entry>>8)*sectorSize); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("read chunk (%d,%d): %w", cx, cz, err)
+	}
+	chunk, err := decodeStoredChunk(sectors)
+	if err != nil {
+		return nil, fmt.Errorf("chunk (%d,%d): %w", cx, cz, err)
+	}
+	return chunk, nil
+}
+
+// decodeStoredChunk decompresses and decodes a chunk's sectors: a length,
+// a compression type and the compressed NBT.
+func decodeStoredChunk(sectors []byte) (*gen.ChunkData, error) {
+	if len(sectors) < 5 {
+		return nil, fmt.Errorf("chunk header is truncated")
+	}
+	length := int(binary.BigEndian.Uint32(sectors))
+	if length < 1 || length > len(sectors)-4 {
+		return nil, fmt.Errorf("chunk length %d out of range", length)
+	}
+	compressed := bytes.NewReader(sectors[5 : 4+length])
+
+	var r io.Reader
+	switch sectors[4] {
+	case compressionZlib:
+		zr, err := zlib.NewReader(compressed)
+		if err != nil {
+			return nil, fmt.Errorf("open zlib stream: %w", err)
+		}
+		defer zr.Close()
+		r = zr
+	case compressionGzip:
+		gr, err := gzip.NewReader(compressed)
+		if err != nil {
+			return nil, fmt.Errorf("open gzip stream: %w", err)
+		}
+		defer gr.Close()
+		r = gr
+	default:
+		return nil, fmt.Errorf("unknown compression type %d", sectors[4])
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("decompress chunk: %w", err)
+	}
+	_, _, chunk, err := DecodeChunkNBT(data)
+	return chunk, err
+}
+
+// DecodeChunkNBT reconstructs a chunk from MC 1.8 NBT format, as written by
+// EncodeChunkNBT. Light and height maps are not read; they are recomputed
+// from the blocks.
+func DecodeChunkNBT(data []byte) (cx, cz int, chunk *gen.ChunkData, err error) {
+	root, err := nbt.DecodeCompound(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("decode chunk nbt: %w", err)
+	}
+	level, ok := root["Level"].(map[string]any)
+	if !ok {
+		return 0, 0, nil, fmt.Errorf("chunk nbt has no Level compound")
+	}
+	x, okX := level["xPos"].(int32)
+	z, okZ := level["zPos"].(int32)
+	if !okX || !okZ {
+		return 0, 0, nil, fmt.Errorf("chunk nbt has no position")
+	}
+
+	chunk = &gen.ChunkData{}
+	sections, _ := level["Sections"].([]any)
+	for _, s := range sections {
+		sec, ok := s.(map[string]any)
+		if !ok {
+			continue
+		}
+		if err := decodeSection(chunk, sec); err != nil {
+			return 0, 0, nil, err
+		}
+	}
+	if biomes, ok := level["Biomes"].([]byte); ok && len(biomes) == len(chunk.Biomes) {
+		copy(chunk.Biomes[:], biomes)
+	}
+	tiles, _ := level["TileEntities"].([]any)
+	for _, t := range tiles {
+		if sign, ok := decodeSign(t); ok {
+			chunk.Signs = append(chunk.Signs, sign)
+		}
+	}
+	return int(x), int(z), chunk, nil
+}
+
+// decodeSign reads a sign tile entity; other tile entities are skipped.
+func decodeSign(t any) (gen.Sign, bool) {
+	te, ok := t.(map[string]any)
+	if !ok || te["id"] != "Sign" {
+		return gen.Sign{}, false
+	}
+	x, okX := te["x"].(int32)
+	y, okY := te["y"].(int32)
+	z, okZ := te["z"].(int32)
+	if !okX || !okY || !okZ {
+		return gen.Sign{}, false
+	}
+	sign := gen.Sign{X: int(x), Y: int(y), Z: int(z)}
+	for i := range sign.Lines {
+		raw, _ := te[fmt.Sprintf("Text%d", i+1)].(string)
+		sign.Lines[i] = chat.PlainText(raw)
+	}
+	return sign, true
+}
+
+// decodeSection copies one section's Blocks, Add and Data arrays into chunk.
+func decodeSection(chunk *gen.ChunkData, sec map[string]any) error {
+	y, _ := sec["Y"].(byte)
+	blocks, _ := sec["Blocks"].([]byte)
+	meta, _ := sec["Data"].([]byte)
+	if y > 15 || len(blocks) != 4096 || len(meta) != 2048 {
+		return fmt.Errorf("malformed section %d", y)
+	}
+	add, _ := sec["Add"].([]byte)
+	if add != nil && len(add) != 2048 {
+		return fmt.Errorf("malformed Add array in section %d", y)
+	}
+
+	s := &gen.Section{}
+	for i := 0; i < 4096; i++ {
+		id := uint16(blocks[i])
+		if add != nil {
+			id |= uint16(getNibble(add, i)) << 8
+		}
+		s.Blocks[i] = id<<4 | uint16(getNibble(meta, i))
+	}
+	chunk.Sections[y] = s
+	return nil
+}
+
+// getNibble returns the 4-bit value at the given block index in a nibble array.
+func getNibble(arr []byte, index int) byte {
+	if index%2 == 0 {
+		return arr[index/2] & 0x0F
+	}
+	return arr[index/2] >> 4
+}
@@ -4,9 +4,10 @@ import (
 	"bytes"
 	"compress/zlib"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
-	"path/filepath"
 	"time"
 
 	"github.com/go-theft-craft/server/pkg/world/gen"
@@ -18,20 +19,23 @@ const (
 	compressionZlib = 2
 )
 
-// SaveRegion writes all provided chunks to a .mca region file.
-// chunks maps chunk positions to their uncompressed NBT data.
+// SaveRegion writes chunks to a .mca region file. chunks maps chunk
+// positions to their uncompressed NBT data. Chunks already stored in the
+// region that chunks does not replace are written back unchanged, so a save
+// of the loaded chunks keeps the rest of the region.
 func SaveRegion(dir string, rx, rz int, chunks map[gen.ChunkPos][]byte) error {
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return fmt.Errorf("create region dir: %w", err)
 	}
 
-	// Compress all chunks with zlib.
-	type chunkEntry struct {
-		index      int
-		compressed []byte
+	// stored is a chunk's payload as it sits in the file: a length, a
+	// compression type and the compressed NBT.
+	stored, timestamps, err := readRegionEntries(dir, rx, rz)
+	if err != nil {
+		return err
 	}
-	entries := make([]chunkEntry, 0, len(chunks))
 
+	now := uint32(time.Now().Unix())
 	for pos, nbtData := range chunks {
 		var cbuf bytes.Buffer
 		zw, err := zlib.NewWriterLevel(&cbuf, zlib.DefaultCompression)
@@ -45,54 +49,45 @@ func SaveRegion(dir string, rx, rz int, chunks map[gen.ChunkPos][]byte) error {
 			return fmt.Errorf("close zlib writer: %w", err)
 		}
 
+		payload := make([]byte, 5, 5+cbuf.Len())
+		binary.BigEndian.PutUint32(payload[0:4], uint32(cbuf.Len())+1) // +1 for compression byte
+		payload[4] = compressionZlib
 		idx := (pos.X & 31) + (pos.Z&31)*32
-		entries = append(entries, chunkEntry{index: idx, compressed: cbuf.Bytes()})
+		stored[idx] = append(payload, cbuf.Bytes()...)
+		binary.BigEndian.PutUint32(timestamps[idx*4:], now)
 	}
 
 	// Build the file content.
 	locations := make([]byte, sectorSize)
-	timestamps := make([]byte, sectorSize)
-	now := uint32(time.Now().Unix())
 
-	// Each chunk's data: 4 bytes length + 1 byte compression type + compressed data,
-	// padded to sector boundary.
+	// Each chunk's data is padded to a sector boundary.
 	var dataBuf bytes.Buffer
 	currentSector := uint32(headerSectors)
 
-	for i := range entries {
-		e := &entries[i]
-
-		// Chunk payload: length (4 bytes) + compression (1 byte) + compressed NBT.
-		payloadLen := uint32(len(e.compressed)) + 1 // +1 for compression byte
-		totalLen := 4 + payloadLen                  // 4 for the length field itself
-		sectorCount := (totalLen + sectorSize - 1) / sectorSize
+	for idx, payload := range stored {
+		if payload == nil {
+			continue
+		}
+		sectorCount := (uint32(len(payload)) + sectorSize - 1) / sectorSize
 
 		// Write location entry: (offset << 8) | sectorCount
-		off := e.index * 4
+		off := idx * 4
 		binary.BigEndian.PutUint32(locations[off:off+4],
 			(currentSector<<8)|uint32(sectorCount&0xFF))
 
-		// Write timestamp.
-		binary.BigEndian.PutUint32(timestamps[off:off+4], now)
-
-		// Write chunk data to buffer.
-		var header [5]byte
-		binary.BigEndian.PutUint32(header[0:4], payloadLen)
-		header[4] = compressionZlib
-		dataBuf.Write(header[:])
-		dataBuf.Write(e.compressed)
+		dataBuf.Write(payload)
 
 		// Pad to sector boundary.
 		paddedSize := int(sectorCount) * sectorSize
-		if pad := paddedSize - int(totalLen); pad > 0 {
+		if pad := paddedSize - len(payload); pad > 0 {
 			dataBuf.Write(make([]byte, pad))
 		}
 
-		currentSector += uint32(sectorCount)
+		currentSector += sectorCount
 	}
 
 	// Write the file atomically.
-	path := filepath.Join(dir, fmt.Sprintf("r.%d.%d.mca", rx, rz))
+	path := regionPath(dir, rx, rz)
 	tmp := path + ".tmp"
 
 	f, err := os.Create(tmp)
@@ -122,3 +117,38 @@ func SaveRegion(dir string, rx, rz int, chunks map[gen.ChunkPos][]byte) error {
 
 	return nil
 }
+
+// readRegionEntries returns the stored payload of every chunk in region
+// (rx, rz), indexed by its position in the region, and the region's
+// timestamp table. A region that was never saved has no entries.
+func readRegionEntries(dir string, rx, rz int) (stored [1024][]byte, timestamps []byte, err error) {
+	timestamps = make([]byte, sectorSize)
+	data, err := os.ReadFile(regionPath(dir, rx, rz))
+	if errors.Is(err, fs.ErrNotExist) {
+		return stored, timestamps, nil
+	}
+	if err != nil {
+		return stored, nil, fmt.Errorf("read region file: %w", err)
+	}
+	if len(data) < headerSectors*sectorSize {
+		return stored, nil, fmt.Errorf("region file (%d,%d) is truncated", rx, rz)
+	}
+	copy(timestamps, data[sectorSize:2*sectorSize])
+
+	for idx := range stored {
+		entry := binary.BigEndian.Uint32(data[idx*4:])
+		if entry == 0 {
+			continue
+		}
+		start := int(entry>>8) * sectorSize
+		if start+4 > len(data) {
+			return stored, nil, fmt.Errorf("chunk %d of region (%d,%d) lies past the end of the file", idx, rx, rz)
+		}
+		end := start + 4 + int(binary.BigEndian.Uint32(data[start:]))
+		if end > len(data) || end > start+int(entry&0xFF)*sectorSize {
+			return stored, nil, fmt.Errorf("chunk %d of region (%d,%d) lies past its sectors", idx, rx, rz)
+		}
+		stored[idx] = data[start:end]
+	}
+	return stored, timestamps, nil
+}
@@ -0,0 +1,68 @@
+package world
+
+// ColumnPos identifies a block column by its X and Z coordinates.
+type ColumnPos struct {
+	X, Z int
+}
+
+// GetBiome returns the biome ID at the given block column, taking overrides
+// into account.
+func (w *World) GetBiome(x, z int) byte {
+	w.mu.RLock()
+	b, ok := w.biomes[ColumnPos{x, z}]
+	w.mu.RUnlock()
+	if ok {
+		return b
+	}
+	c := w.GetOrGenerateChunk(x>>4, z>>4)
+	return c.Biomes[(z&0xF)*16+(x&0xF)]
+}
+
+// SetBiome overrides the biome of a block column. Setting the generated
+// biome removes the override. Clients see the change on the next chunk send.
+func (w *World) SetBiome(x, z int, biome byte) {
+	c := w.GetOrGenerateChunk(x>>4, z>>4)
+	base := c.Biomes[(z&0xF)*16+(x&0xF)]
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	pos := ColumnPos{x, z}
+	if biome == base {
+		delete(w.biomes, pos)
+	} else {
+		w.biomes[pos] = biome
+	}
+}
+
+// GetBiomeOverrides returns a copy of all biome overrides (used for persistence).
+func (w *World) GetBiomeOverrides() map[ColumnPos]byte {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	result := make(map[ColumnPos]byte, len(w.biomes))
+	for k, v := range w.biomes {
+		result[k] = v
+	}
+	return result
+}
+
+// SetBiomeOverrides replaces all biome overrides (used when loading from storage).
+func (w *World) SetBiomeOverrides(overrides map[ColumnPos]byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.biomes = overrides
+}
+
+// applyBiomeOverrides writes biome overrides for chunk (cx, cz) into biomes.
+func (w *World) applyBiomeOverrides(biomes *[256]byte, cx, cz int) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	for pos, b := range w.biomes {
+		if pos.X>>4 != cx || pos.Z>>4 != cz {
+			continue
+		}
+		biomes[(pos.Z&0xF)*16+(pos.X&0xF)] = b
+	}
+}
@@ -0,0 +1,37 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/go-theft-craft/server/pkg/world/gen"
+)
+
+// encodedBiome returns the biome byte for column (x, z) in an encoded chunk.
+func encodedBiome(w *World, x, z int) byte {
+	data := w.EncodeChunk(x>>4, z>>4).ChunkData
+	biomes := data[len(data)-biomeBytes:]
+	return biomes[(z&0xF)*16+(x&0xF)]
+}
+
+func TestSetBiome_ChangesEncodedChunk(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	base := w.GetBiome(20, -3)
+
+	w.SetBiome(20, -3, 2) // desert
+
+	if got := w.GetBiome(20, -3); got != 2 {
+		t.Errorf("GetBiome = %d, want 2", got)
+	}
+	if got := encodedBiome(w, 20, -3); got != 2 {
+		t.Errorf("encoded biome = %d, want 2", got)
+	}
+	if got := encodedBiome(w, 21, -3); got != base {
+		t.Errorf("neighbouring column biome = %d, want unchanged %d", got, base)
+	}
+
+	// Restoring the generated biome removes the override.
+	w.SetBiome(20, -3, base)
+	if n := len(w.GetBiomeOverrides()); n != 0 {
+		t.Errorf("expected no overrides after restore, got %d", n)
+	}
+}
@@ -0,0 +1,137 @@
+package world
+
+import "math"
+
+// Border defaults, as in vanilla.
+const (
+	// DefaultBorderDiameter is wide enough that players never reach it.
+	DefaultBorderDiameter = 60000000
+
+	defaultBorderWarningBlocks = 5
+	defaultBorderWarningTime   = 15 // seconds
+
+	// BorderDamageBuffer is how far past the border players may stand
+	// unharmed; beyond it they take BorderDamagePerBlock for every block.
+	BorderDamageBuffer   = 5.0
+	BorderDamagePerBlock = 0.2
+)
+
+// Border is a square world border centered on (CenterX, CenterZ).
+type Border struct {
+	CenterX, CenterZ float64
+	Diameter         float64
+
+	// WarningBlocks is the distance at which clients tint the screen red.
+	WarningBlocks int32
+	// WarningTime is how many seconds of warning a shrinking border gives.
+	WarningTime int32
+}
+
+// DefaultBorder returns the border of an unbounded world.
+func DefaultBorder() Border {
+	return Border{
+		Diameter:      DefaultBorderDiameter,
+		WarningBlocks: defaultBorderWarningBlocks,
+		WarningTime:   defaultBorderWarningTime,
+	}
+}
+
+// RadiusBorder returns the border around the chunks within radius of the
+// origin chunk.
+func RadiusBorder(radius int) Border {
+	b := DefaultBorder()
+	b.CenterX, b.CenterZ = 8, 8
+	b.Diameter = float64((2*radius + 1) * 16)
+	return b
+}
+
+// Bounds returns the minimum and maximum X and Z inside the border.
+func (b Border) Bounds() (minX, minZ, maxX, maxZ float64) {
+	r := b.Diameter / 2
+	return b.CenterX - r, b.CenterZ - r, b.CenterX + r, b.CenterZ + r
+}
+
+// Contains reports whether (x, z) is inside the border.
+func (b Border) Contains(x, z float64) bool {
+	minX, minZ, maxX, maxZ := b.Bounds()
+	return x >= minX && x < maxX && z >= minZ && z < maxZ
+}
+
+// ContainsBlock reports whether the block column at (x, z) overlaps the
+// inside of the border.
+func (b Border) ContainsBlock(x, z int) bool {
+	minX, minZ, maxX, maxZ := b.Bounds()
+	return float64(x+1) > minX && float64(x) < maxX && float64(z+1) > minZ && float64(z) < maxZ
+}
+
+// ContainsChunk reports whether any part of chunk (cx, cz) is inside the
+// border.
+func (b Border) ContainsChunk(cx, cz int) bool {
+	minX, minZ, maxX, maxZ := b.Bounds()
+	x, z := float64(cx*16), float64(cz*16)
+	return x < maxX && x+16 > minX && z < maxZ && z+16 > minZ
+}
+
+// Within reports whether b lies entirely inside o.
+func (b Border) Within(o Border) bool {
+	minX, minZ, maxX, maxZ := b.Bounds()
+	oMinX, oMinZ, oMaxX, oMaxZ := o.Bounds()
+	return minX >= oMinX && minZ >= oMinZ && maxX <= oMaxX && maxZ <= oMaxZ
+}
+
+// Clamp returns the closest point to (x, z) inside the border.
+func (b Border) Clamp(x, z float64) (float64, float64) {
+	minX, minZ, maxX, maxZ := b.Bounds()
+	clamp := func(v, lo, hi float64) float64 {
+		if v < lo {
+			return lo
+		}
+		if v >= hi {
+			return hi - 0.01
+		}
+		return v
+	}
+	return clamp(x, minX, maxX), clamp(z, minZ, maxZ)
+}
+
+// DistanceOutside returns how far (x, z) is outside the border, or 0 inside.
+func (b Border) DistanceOutside(x, z float64) float64 {
+	minX, minZ, maxX, maxZ := b.Bounds()
+	dx := math.Max(math.Max(minX-x, x-maxX), 0)
+	dz := math.Max(math.Max(minZ-z, z-maxZ), 0)
+	return math.Max(dx, dz)
+}
+
+// Border returns the world border: the one set with SetBorder, or else the
+// default border.
+func (w *World) Border() Border {
+	if b, ok := w.CustomBorder(); ok {
+		return b
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.defaultBorder
+}
+
+// CustomBorder returns the border set with SetBorder and whether one was set.
+func (w *World) CustomBorder() (Border, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.border, w.borderSet
+}
+
+// SetBorder replaces the world border, as /worldborder does.
+func (w *World) SetBorder(b Border) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.border = b
+	w.borderSet = true
+}
+
+// SetDefaultBorder sets the border used while none is set with SetBorder,
+// normally the one derived from the world radius.
+func (w *World) SetDefaultBorder(b Border) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.defaultBorder = b
+}
@@ -0,0 +1,50 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/go-theft-craft/server/pkg/world/gen"
+)
+
+func TestRadiusBorder_MatchesChunkRadius(t *testing.T) {
+	b := RadiusBorder(2)
+	minX, minZ, maxX, maxZ := b.Bounds()
+	if minX != -32 || minZ != -32 || maxX != 48 || maxZ != 48 {
+		t.Errorf("bounds = (%v, %v)-(%v, %v), want (-32, -32)-(48, 48)", minX, minZ, maxX, maxZ)
+	}
+}
+
+func TestBorder_ClampAndDistance(t *testing.T) {
+	b := DefaultBorder()
+	b.Diameter = 100 // -50 to 50
+
+	if !b.Contains(49, -50) || b.Contains(50, 0) {
+		t.Error("Contains should include the minimum edge and exclude the maximum")
+	}
+	if x, z := b.Clamp(-80, 10); x != -50 || z != 10 {
+		t.Errorf("Clamp(-80, 10) = (%v, %v), want (-50, 10)", x, z)
+	}
+	if d := b.DistanceOutside(60, -53); d != 10 {
+		t.Errorf("DistanceOutside(60, -53) = %v, want 10", d)
+	}
+	if d := b.DistanceOutside(0, 0); d != 0 {
+		t.Errorf("DistanceOutside inside = %v, want 0", d)
+	}
+}
+
+func TestBorder_ContainsBlock(t *testing.T) {
+	b := RadiusBorder(0) // 0 to 16
+	if !b.ContainsBlock(0, 15) || !b.ContainsBlock(15, 0) {
+		t.Error("blocks in the border chunk should be inside")
+	}
+	if b.ContainsBlock(-1, 0) || b.ContainsBlock(0, 16) {
+		t.Error("blocks next to the border chunk should be outside")
+	}
+}
+
+func TestWorld_DefaultBorder(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	if b := w.Border(); b.Diameter != DefaultBorderDiameter {
+		t.Errorf("diameter = %v, want %v", b.Diameter, DefaultBorderDiameter)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 
 	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	"github.com/go-theft-craft/server/pkg/world/gen"
 )
 
 const (
@@ -12,9 +13,10 @@ const (
 	biomeBytes        = 256              // 16×16 biome IDs
 )
 
-// EncodeChunk encodes a ChunkData into a MapChunk packet, applying any block overrides.
+// EncodeChunk encodes a ChunkData into a MapChunk packet, applying any block
+// overrides and computed lighting.
 func (w *World) EncodeChunk(cx, cz int) pkt.MapChunk {
-	chunk := w.GetOrGenerateChunk(cx, cz)
+	chunk := w.LitChunk(cx, cz)
 
 	// Determine which sections are non-nil.
 	var bitMap uint16
@@ -52,33 +54,36 @@ func (w *World) EncodeChunk(cx, cz int) pkt.MapChunk {
 				binary.LittleEndian.PutUint16(blocks[idx*2:], sec.Blocks[idx])
 			}
 		}
-		// Apply overrides for this section.
-		w.applyOverrides(blocks, cx, cz, i)
 		data = append(data, blocks...)
 	}
 
-	// Block light: all 0xFF (full light) for each section.
+	// Block light, then sky light, for each active section. Without game
+	// data the chunk is unlit and sent at full brightness.
 	fullLight := make([]byte, sectionLightBytes)
 	for i := range fullLight {
 		fullLight[i] = 0xFF
 	}
-	for i := 0; i < 16; i++ {
-		if bitMap&(1<<uint(i)) == 0 {
-			continue
-		}
-		data = append(data, fullLight...)
-	}
-
-	// Sky light: all 0xFF for each section.
-	for i := 0; i < 16; i++ {
-		if bitMap&(1<<uint(i)) == 0 {
-			continue
+	for _, sky := range []bool{false, true} {
+		for i := 0; i < 16; i++ {
+			if bitMap&(1<<uint(i)) == 0 {
+				continue
+			}
+			sec := chunk.Sections[i]
+			switch {
+			case sec == nil || !chunk.Lit:
+				data = append(data, fullLight...)
+			case sky:
+				data = append(data, sec.SkyLight[:]...)
+			default:
+				data = append(data, sec.BlockLight[:]...)
+			}
 		}
-		data = append(data, fullLight...)
 	}
 
 	// Biome data.
-	data = append(data, chunk.Biomes[:]...)
+	biomes := chunk.Biomes
+	w.applyBiomeOverrides(&biomes, cx, cz)
+	data = append(data, biomes[:]...)
 
 	return pkt.MapChunk{
 		X:         int32(cx),
@@ -89,24 +94,89 @@ func (w *World) EncodeChunk(cx, cz int) pkt.MapChunk {
 	}
 }
 
-// applyOverrides writes block overrides into the section's block data.
-func (w *World) applyOverrides(blocks []byte, cx, cz, sectionIdx int) {
+// LitChunk returns a copy of chunk (cx, cz) with block overrides applied and,
+// when game data is attached, lighting computed from the chunk and its loaded
+// neighbors. The result is cached until a block in or next to the chunk
+// changes; its sections are shared with the cache and must not be modified.
+func (w *World) LitChunk(cx, cz int) *gen.ChunkData {
+	pos := gen.ChunkPos{X: cx, Z: cz}
+	base := w.GetOrGenerateChunk(cx, cz)
+
 	w.mu.RLock()
-	defer w.mu.RUnlock()
+	if c, ok := w.chunks[pos]; ok {
+		base = c // the chunk may have been regenerated since
+	}
+	cached, ok := w.lit[pos]
+	gd := w.gameData
+	epoch := w.lightEpoch
+	w.mu.RUnlock()
+	if ok {
+		cp := *cached
+		return &cp
+	}
 
-	baseY := sectionIdx * 16
-	for pos, stateID := range w.blocks {
-		pcx, pcz := pos.X>>4, pos.Z>>4
-		if pcx != cx || pcz != cz {
-			continue
+	chunk := w.withOverrides(cx, cz, base)
+	if gd != nil && gd.Blocks != nil {
+		var around gen.Neighbors
+		for dx := -1; dx <= 1; dx++ {
+			for dz := -1; dz <= 1; dz++ {
+				if dx == 0 && dz == 0 {
+					continue
+				}
+				w.mu.RLock()
+				n, ok := w.chunks[gen.ChunkPos{X: cx + dx, Z: cz + dz}]
+				w.mu.RUnlock()
+				if ok {
+					around[dx+1][dz+1] = w.withOverrides(cx+dx, cz+dz, n)
+				}
+			}
 		}
-		if pos.Y < baseY || pos.Y >= baseY+16 {
-			continue
+		chunk.ComputeLighting(gd.Blocks, around)
+	}
+
+	// A block changed while lighting was computed; return the result
+	// without caching it.
+	w.mu.Lock()
+	if w.lightEpoch == epoch {
+		w.lit[pos] = chunk
+	}
+	w.mu.Unlock()
+
+	cp := *chunk
+	return &cp
+}
+
+// withOverrides returns a copy of base, the terrain of chunk (cx, cz), with
+// its block overrides applied.
+func (w *World) withOverrides(cx, cz int, base *gen.ChunkData) *gen.ChunkData {
+	chunk := &gen.ChunkData{Biomes: base.Biomes}
+	for i, sec := range base.Sections {
+		if sec != nil {
+			cp := *sec
+			chunk.Sections[i] = &cp
 		}
-		lx := pos.X & 0xF
-		ly := pos.Y & 0xF
-		lz := pos.Z & 0xF
-		idx := (ly*256 + lz*16 + lx) * 2
-		binary.LittleEndian.PutUint16(blocks[idx:], uint16(stateID))
 	}
+	for pos, stateID := range w.OverridesForChunk(cx, cz) {
+		if pos.Y >= 0 && pos.Y < 256 {
+			chunk.SetBlock(pos.X&0xF, pos.Y, pos.Z&0xF, uint16(stateID))
+		}
+	}
+	return chunk
+}
+
+// invalidateLight drops the cached lighting of chunk (cx, cz) and of its
+// neighbors, whose light it can reach. The caller holds mu.
+func (w *World) invalidateLight(cx, cz int) {
+	for dx := -1; dx <= 1; dx++ {
+		for dz := -1; dz <= 1; dz++ {
+			delete(w.lit, gen.ChunkPos{X: cx + dx, Z: cz + dz})
+		}
+	}
+	w.lightEpoch++
+}
+
+// resetLight drops all cached lighting. The caller holds mu.
+func (w *World) resetLight() {
+	clear(w.lit)
+	w.lightEpoch++
 }
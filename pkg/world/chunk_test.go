@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"testing"
 
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
 	"github.com/go-theft-craft/server/pkg/world/gen"
 )
 
@@ -91,3 +92,29 @@ func TestEncodeChunkDefaultGenerator(t *testing.T) {
 		t.Error("chunk data should not be empty")
 	}
 }
+
+// blockLightAt returns the block light at local (x, y, z) of a lit chunk.
+func blockLightAt(c *gen.ChunkData, x, y, z int) byte {
+	i := (y&0xF)*256 + z*16 + x
+	return c.Sections[y>>4].BlockLight[i/2] >> (uint(i%2) * 4) & 0xF
+}
+
+func TestLitChunkCachedUntilNeighborChanges(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	w.SetGameData(pkt.New())
+	w.GetOrGenerateChunk(1, 0)
+
+	first := w.LitChunk(0, 0)
+	if w.LitChunk(0, 0).Sections[0] != first.Sections[0] {
+		t.Error("LitChunk recomputed an unchanged chunk")
+	}
+
+	w.SetBlock(17, 5, 8, 50<<4|5) // torch two blocks east of chunk (0, 0)
+	lit := w.LitChunk(0, 0)
+	if lit.Sections[0] == first.Sections[0] {
+		t.Fatal("LitChunk kept its cache after a neighbor changed")
+	}
+	if got := blockLightAt(lit, 15, 5, 8); got != 12 {
+		t.Errorf("block light beside the neighbor's torch = %d, want 12", got)
+	}
+}
@@ -0,0 +1,80 @@
+package world
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Game rule names.
+const (
+	RuleDoDaylightCycle = "doDaylightCycle"
+	RuleKeepInventory   = "keepInventory"
+)
+
+// defaultGameRules holds every known game rule and its vanilla default. Only
+// rules the server acts on are listed; mobs neither drop loot nor change
+// blocks yet, so doMobLoot and mobGriefing are not accepted.
+var defaultGameRules = map[string]string{
+	RuleDoDaylightCycle: "true",
+	RuleKeepInventory:   "false",
+}
+
+// GameRuleNames returns the names of all known game rules, sorted.
+func GameRuleNames() []string {
+	names := make([]string, 0, len(defaultGameRules))
+	for name := range defaultGameRules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GameRule returns the value of a game rule and whether the rule exists.
+// Rules that were never set report their default.
+func (w *World) GameRule(name string) (string, bool) {
+	def, ok := defaultGameRules[name]
+	if !ok {
+		return "", false
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if v, set := w.gameRules[name]; set {
+		return v, true
+	}
+	return def, true
+}
+
+// GameRuleBool returns a boolean game rule. Unknown rules are false.
+func (w *World) GameRuleBool(name string) bool {
+	v, _ := w.GameRule(name)
+	b, _ := strconv.ParseBool(v)
+	return b
+}
+
+// SetGameRule sets a known game rule. Boolean rules only accept "true" or
+// "false".
+func (w *World) SetGameRule(name, value string) error {
+	if _, ok := defaultGameRules[name]; !ok {
+		return fmt.Errorf("unknown game rule %q", name)
+	}
+	if value != "true" && value != "false" {
+		return fmt.Errorf("invalid value %q for game rule %s", value, name)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.gameRules[name] = value
+	return nil
+}
+
+// GameRules returns a copy of the game rules that were set (used for
+// persistence).
+func (w *World) GameRules() map[string]string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	rules := make(map[string]string, len(w.gameRules))
+	for k, v := range w.gameRules {
+		rules[k] = v
+	}
+	return rules
+}
@@ -0,0 +1,74 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/go-theft-craft/server/pkg/world/gen"
+)
+
+func TestGameRule_Defaults(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+
+	if !w.GameRuleBool(RuleDoDaylightCycle) {
+		t.Error("doDaylightCycle should default to true")
+	}
+	if w.GameRuleBool(RuleKeepInventory) {
+		t.Error("keepInventory should default to false")
+	}
+	if _, ok := w.GameRule("noSuchRule"); ok {
+		t.Error("unknown rule reported as existing")
+	}
+}
+
+func TestSetGameRule_Validates(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+
+	if err := w.SetGameRule("noSuchRule", "true"); err == nil {
+		t.Error("setting an unknown rule should fail")
+	}
+	if err := w.SetGameRule(RuleKeepInventory, "yes"); err == nil {
+		t.Error("setting a boolean rule to a non-boolean should fail")
+	}
+	if err := w.SetGameRule(RuleKeepInventory, "true"); err != nil {
+		t.Fatalf("SetGameRule: %v", err)
+	}
+	if !w.GameRuleBool(RuleKeepInventory) {
+		t.Error("keepInventory should be true after setting it")
+	}
+	if rules := w.GameRules(); len(rules) != 1 || rules[RuleKeepInventory] != "true" {
+		t.Errorf("GameRules() = %v, want only keepInventory", rules)
+	}
+}
+
+func TestWorldTick_DaylightCycleOff(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	w.SetTime(0, 6000)
+	if err := w.SetGameRule(RuleDoDaylightCycle, "false"); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 100 {
+		w.Tick()
+	}
+	age, tod := w.GetTime()
+	if age != 100 || tod != 6000 {
+		t.Errorf("after 100 ticks = (%d, %d), want (100, 6000)", age, tod)
+	}
+	if _, ct := w.ClientTime(); ct != -6000 {
+		t.Errorf("client time = %d, want -6000 to stop the sun", ct)
+	}
+
+	w.SetTimeOfDay(0)
+	if _, ct := w.ClientTime(); ct != -1 {
+		t.Errorf("client time at 0 = %d, want -1", ct)
+	}
+}
+
+func TestSetGameRule_RejectsRulesWithoutEffect(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	for _, name := range []string{"doMobLoot", "mobGriefing"} {
+		if err := w.SetGameRule(name, "false"); err == nil {
+			t.Errorf("SetGameRule(%q) succeeded, want an unknown rule error", name)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package gen
 
+import "math"
+
 // Biome IDs matching Minecraft 1.8 protocol.
 const (
 	biomeOcean      byte = 0
@@ -11,28 +13,58 @@ const (
 	biomeDesert     byte = 2
 	biomeJungle     byte = 21
 	biomeMountains  byte = 3 // extreme hills
+	biomeRiver      byte = 7
 	biomeBeach      byte = 16
 	biomeTundra     byte = 12
 )
 
+// River tuning, in units of the river noise. Rivers follow the zero line of
+// a low-frequency noise field: columns within riverWidth of it form the
+// channel, and the next riverBankWidth form its sloping banks.
+const (
+	riverScale     = 384.0
+	riverWidth     = 0.02
+	riverBankWidth = 0.03
+	riverDepth     = 3 // channel depth below sea level at its center
+)
+
 // BiomeGenerator selects biomes using temperature/rainfall noise fields.
 type BiomeGenerator struct {
-	tempNoise *NoiseGenerator
-	rainNoise *NoiseGenerator
-	terrain   *NoiseGenerator
+	tempNoise  *NoiseGenerator
+	rainNoise  *NoiseGenerator
+	terrain    *NoiseGenerator
+	riverNoise *NoiseGenerator
 }
 
 // NewBiomeGenerator creates a BiomeGenerator from a seed.
 func NewBiomeGenerator(seed int64) *BiomeGenerator {
 	return &BiomeGenerator{
-		tempNoise: NewNoiseGenerator(seed + 100),
-		rainNoise: NewNoiseGenerator(seed + 200),
-		terrain:   NewNoiseGenerator(seed),
+		tempNoise:  NewNoiseGenerator(seed + 100),
+		rainNoise:  NewNoiseGenerator(seed + 200),
+		terrain:    NewNoiseGenerator(seed),
+		riverNoise: NewNoiseGenerator(seed + 300),
 	}
 }
 
-// BiomeAt returns the biome ID at the given world block coordinates.
+// BiomeAt returns the biome ID at the given world block coordinates. Rivers
+// cut across every land biome but stop at the ocean.
 func (bg *BiomeGenerator) BiomeAt(bx, bz int) byte {
+	biome := bg.landBiomeAt(bx, bz)
+	if biome != biomeOcean && bg.riverAt(bx, bz) < riverWidth+riverBankWidth {
+		return biomeRiver
+	}
+	return biome
+}
+
+// riverAt returns how far a column is from the center of a river, in river
+// noise units. Zero is the middle of the channel.
+func (bg *BiomeGenerator) riverAt(bx, bz int) float64 {
+	return math.Abs(bg.riverNoise.OctaveNoise2D(float64(bx)/riverScale, float64(bz)/riverScale, 3, 0.5))
+}
+
+// landBiomeAt returns the biome at the given world block coordinates
+// ignoring rivers.
+func (bg *BiomeGenerator) landBiomeAt(bx, bz int) byte {
 	// Sample temperature and rainfall at large scale.
 	tx := float64(bx) / 512.0
 	tz := float64(bz) / 512.0
@@ -1,56 +1,65 @@
 package gen
 
-// DefaultGenerator produces vanilla-like terrain with biomes, caves, ores, and trees.
+// DefaultGenerator produces vanilla-like terrain with biomes, caves,
+// ravines, ores, lakes, trees, and villages.
 type DefaultGenerator struct {
 	terrain  *NoiseGenerator
 	detail   *NoiseGenerator
 	biomeGen *BiomeGenerator
 	caveGen  *CaveGenerator
+	ravines  *RavineGenerator
 	oreGen   *OreGenerator
+	lakeGen  *LakeGenerator
 	treeGen  *TreeGenerator
+	villages *StructureGenerator
 }
 
 // NewDefaultGenerator creates a DefaultGenerator from a seed.
 func NewDefaultGenerator(seed int64) *DefaultGenerator {
-	return &DefaultGenerator{
+	g := &DefaultGenerator{
 		terrain:  NewNoiseGenerator(seed),
 		detail:   NewNoiseGenerator(seed + 1),
 		biomeGen: NewBiomeGenerator(seed),
 		caveGen:  NewCaveGenerator(seed),
 		oreGen:   NewOreGenerator(seed),
+		lakeGen:  NewLakeGenerator(seed),
 		treeGen:  NewTreeGenerator(seed),
 	}
+	g.ravines = NewRavineGenerator(seed, g.HeightAt)
+	g.villages = NewStructureGenerator(seed, g.HeightAt, g.biomeGen.BiomeAt)
+	return g
 }
 
 func (g *DefaultGenerator) Generate(chunkX, chunkZ int) *ChunkData {
 	c := &ChunkData{}
 
 	// Pass 1: compute heightmap and fill terrain + biomes.
-	var heights [16][16]int
+	biomes, heights := g.columns(chunkX, chunkZ)
 	for x := 0; x < 16; x++ {
 		for z := 0; z < 16; z++ {
-			bx := chunkX*16 + x
-			bz := chunkZ*16 + z
-
-			biome := g.biomeGen.BiomeAt(bx, bz)
-			c.SetBiome(x, z, biome)
-
-			height := g.terrainHeight(bx, bz, biome)
-			heights[x][z] = height
-
-			g.fillColumn(c, x, z, height, biome)
+			c.SetBiome(x, z, biomes[x][z])
+			g.fillColumn(c, x, z, heights[x][z], biomes[x][z])
 		}
 	}
 
 	// Pass 2: carve caves.
 	g.caveGen.Carve(c, chunkX, chunkZ, &heights)
 
-	// Pass 3: place ores.
+	// Pass 3: carve ravines.
+	g.ravines.Carve(c, chunkX, chunkZ)
+
+	// Pass 4: place ores.
 	g.oreGen.Place(c, chunkX, chunkZ, &heights)
 
-	// Pass 4: place trees and vegetation.
+	// Pass 5: place lakes, before trees so none grow in the water.
+	g.lakeGen.Decorate(c, chunkX, chunkZ, &heights)
+
+	// Pass 6: place trees and vegetation.
 	g.treeGen.Decorate(c, chunkX, chunkZ, &heights)
 
+	// Pass 7: place villages.
+	g.villages.Place(c, chunkX, chunkZ)
+
 	return c
 }
 
@@ -59,9 +68,36 @@ func (g *DefaultGenerator) HeightAt(blockX, blockZ int) int {
 	return g.terrainHeight(blockX, blockZ, biome)
 }
 
+// HeightsFor returns the terrain heightmap of a chunk, indexed [x][z].
+func (g *DefaultGenerator) HeightsFor(chunkX, chunkZ int) [16][16]int {
+	_, heights := g.columns(chunkX, chunkZ)
+	return heights
+}
+
+// columns computes the biome and terrain height of every column in a chunk,
+// indexed [x][z].
+func (g *DefaultGenerator) columns(chunkX, chunkZ int) (biomes [16][16]byte, heights [16][16]int) {
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			bx := chunkX*16 + x
+			bz := chunkZ*16 + z
+
+			biome := g.biomeGen.BiomeAt(bx, bz)
+			biomes[x][z] = biome
+			heights[x][z] = g.terrainHeight(bx, bz, biome)
+		}
+	}
+	return biomes, heights
+}
+
 // terrainHeight computes the terrain height at a world block coordinate.
 // Different biomes scale noise amplitude differently.
 func (g *DefaultGenerator) terrainHeight(bx, bz int, biome byte) int {
+	if biome == biomeRiver {
+		land := g.terrainHeight(bx, bz, g.biomeGen.landBiomeAt(bx, bz))
+		return riverHeight(land, g.biomeGen.riverAt(bx, bz))
+	}
+
 	// Base terrain noise.
 	nx := float64(bx) / 128.0
 	nz := float64(bz) / 128.0
@@ -85,6 +121,21 @@ func (g *DefaultGenerator) terrainHeight(bx, bz int, biome byte) int {
 	return h
 }
 
+// riverHeight lowers the land height of a river column: the channel sits
+// just below sea level, deepest at its center, and the banks slope from sea
+// level back up to the surrounding land.
+func riverHeight(land int, r float64) int {
+	if r < riverWidth {
+		bed := seaLevel - 1 - int((1-r/riverWidth)*riverDepth)
+		return min(land, bed)
+	}
+	if land <= seaLevel {
+		return land
+	}
+	t := min((r-riverWidth)/riverBankWidth, 1)
+	return seaLevel + int(t*float64(land-seaLevel))
+}
+
 // biomeTerrainParams returns (amplitude, baseHeight) for terrain noise scaling.
 func biomeTerrainParams(biome byte) (amplitude, baseHeight float64) {
 	switch biome {
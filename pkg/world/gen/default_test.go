@@ -115,3 +115,19 @@ func TestDefaultGeneratorMultipleChunks(t *testing.T) {
 		}
 	}
 }
+
+func TestDefaultGeneratorHeightsForMatchesHeightAt(t *testing.T) {
+	g := NewDefaultGenerator(98765)
+
+	for _, pos := range []ChunkPos{{0, 0}, {-3, 7}, {12, -5}} {
+		heights := g.HeightsFor(pos.X, pos.Z)
+		for x := 0; x < 16; x++ {
+			for z := 0; z < 16; z++ {
+				want := g.HeightAt(pos.X*16+x, pos.Z*16+z)
+				if heights[x][z] != want {
+					t.Errorf("chunk %v column (%d,%d): HeightsFor = %d, HeightAt = %d", pos, x, z, heights[x][z], want)
+				}
+			}
+		}
+	}
+}
@@ -1,5 +1,13 @@
 package gen
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-theft-craft/server/pkg/gamedata"
+)
+
 const (
 	blockAir       = 0
 	blockStone     = 1
@@ -40,13 +48,30 @@ const (
 	seaLevel = 62
 )
 
-// FlatGenerator generates a classic superflat world:
-// bedrock at y=0, stone y=1..2, dirt y=3, grass y=4.
-type FlatGenerator struct{}
+// FlatGenerator generates a superflat world from a stack of layers, by
+// default the classic bedrock at y=0, stone y=1..2, dirt y=3, grass y=4.
+type FlatGenerator struct {
+	layers []uint16 // block states from y=0 upward
+}
 
-// NewFlatGenerator creates a FlatGenerator.
+// defaultFlatLayers is the classic superflat stack.
+var defaultFlatLayers = []uint16{
+	blockBedrock << 4,
+	blockStone << 4,
+	blockStone << 4,
+	blockDirt << 4,
+	blockGrass << 4,
+}
+
+// NewFlatGenerator creates a FlatGenerator with the default layers.
 func NewFlatGenerator(_ int64) *FlatGenerator {
-	return &FlatGenerator{}
+	return &FlatGenerator{layers: defaultFlatLayers}
+}
+
+// NewFlatGeneratorLayers creates a FlatGenerator that fills every column with
+// layers, given as block states from y=0 upward.
+func NewFlatGeneratorLayers(layers []uint16) *FlatGenerator {
+	return &FlatGenerator{layers: layers}
 }
 
 func (g *FlatGenerator) Generate(_, _ int) *ChunkData {
@@ -54,17 +79,55 @@ func (g *FlatGenerator) Generate(_, _ int) *ChunkData {
 
 	for x := 0; x < 16; x++ {
 		for z := 0; z < 16; z++ {
-			c.SetBlock(x, 0, z, blockBedrock<<4)
-			c.SetBlock(x, 1, z, blockStone<<4)
-			c.SetBlock(x, 2, z, blockStone<<4)
-			c.SetBlock(x, 3, z, blockDirt<<4)
-			c.SetBlock(x, 4, z, blockGrass<<4)
+			for y, state := range g.layers {
+				if state != blockAir {
+					c.SetBlock(x, y, z, state)
+				}
+			}
 			c.SetBiome(x, z, biomePlains)
 		}
 	}
 	return c
 }
 
+// HeightAt returns the Y of the top layer.
 func (g *FlatGenerator) HeightAt(_, _ int) int {
-	return 4 // top solid block is at y=4 (grass)
+	return len(g.layers) - 1
+}
+
+// HeightsFor returns the Y of the top layer for every column of a chunk.
+func (g *FlatGenerator) HeightsFor(_, _ int) [16][16]int {
+	return UniformHeights(g.HeightAt(0, 0))
+}
+
+// ParseFlatLayers parses a superflat preset such as
+// "minecraft:bedrock,3*minecraft:stone,2*minecraft:dirt,minecraft:grass" into
+// block states from y=0 upward. Each comma-separated token names a block,
+// with or without the "minecraft:" prefix, optionally preceded by a repeat
+// count.
+func ParseFlatLayers(preset string, blocks gamedata.BlockRegistry) ([]uint16, error) {
+	var layers []uint16
+	for _, token := range strings.Split(preset, ",") {
+		token = strings.TrimSpace(token)
+		count := 1
+		name := token
+		if n, rest, ok := strings.Cut(token, "*"); ok {
+			v, err := strconv.Atoi(n)
+			if err != nil || v < 1 {
+				return nil, fmt.Errorf("layer %q: invalid count", token)
+			}
+			count, name = v, rest
+		}
+		block, ok := blocks.ByName(strings.TrimPrefix(name, "minecraft:"))
+		if !ok {
+			return nil, fmt.Errorf("layer %q: unknown block", token)
+		}
+		if len(layers)+count > 256 {
+			return nil, fmt.Errorf("layers exceed the world height of 256")
+		}
+		for range count {
+			layers = append(layers, uint16(block.ID)<<4)
+		}
+	}
+	return layers, nil
 }
@@ -0,0 +1,78 @@
+package gen
+
+import (
+	"reflect"
+	"testing"
+
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+)
+
+func TestParseFlatLayers(t *testing.T) {
+	blocks := pkt.New().Blocks
+	tests := []struct {
+		preset string
+		want   []uint16
+	}{
+		{"minecraft:bedrock", []uint16{blockBedrock << 4}},
+		{
+			"minecraft:bedrock,3*minecraft:stone,2*minecraft:dirt,minecraft:grass",
+			[]uint16{blockBedrock << 4, blockStone << 4, blockStone << 4, blockStone << 4, blockDirt << 4, blockDirt << 4, blockGrass << 4},
+		},
+		{"bedrock, 2*sand", []uint16{blockBedrock << 4, blockSand << 4, blockSand << 4}},
+	}
+	for _, tt := range tests {
+		got, err := ParseFlatLayers(tt.preset, blocks)
+		if err != nil {
+			t.Errorf("ParseFlatLayers(%q): %v", tt.preset, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseFlatLayers(%q) = %v, want %v", tt.preset, got, tt.want)
+		}
+	}
+}
+
+func TestParseFlatLayersInvalid(t *testing.T) {
+	blocks := pkt.New().Blocks
+	for _, preset := range []string{
+		"",
+		"minecraft:bedrock,,minecraft:grass",
+		"minecraft:notablock",
+		"0*minecraft:stone",
+		"x*minecraft:stone",
+		"257*minecraft:stone",
+	} {
+		if layers, err := ParseFlatLayers(preset, blocks); err == nil {
+			t.Errorf("ParseFlatLayers(%q) = %v, want an error", preset, layers)
+		}
+	}
+}
+
+func TestFlatGeneratorCustomLayers(t *testing.T) {
+	layers, err := ParseFlatLayers("minecraft:bedrock,2*minecraft:dirt,minecraft:sand", pkt.New().Blocks)
+	if err != nil {
+		t.Fatalf("ParseFlatLayers: %v", err)
+	}
+	g := NewFlatGeneratorLayers(layers)
+	c := g.Generate(3, -2)
+
+	want := []uint16{blockBedrock << 4, blockDirt << 4, blockDirt << 4, blockSand << 4, blockAir}
+	for y, state := range want {
+		if got := c.GetBlock(7, y, 9); got != state {
+			t.Errorf("block at y=%d = %d, want %d", y, got, state)
+		}
+	}
+	if h := g.HeightAt(0, 0); h != 3 {
+		t.Errorf("HeightAt = %d, want 3", h)
+	}
+}
+
+func TestFlatGeneratorDefaultLayers(t *testing.T) {
+	c := NewFlatGenerator(0).Generate(0, 0)
+	want := []uint16{blockBedrock << 4, blockStone << 4, blockStone << 4, blockDirt << 4, blockGrass << 4, blockAir}
+	for y, state := range want {
+		if got := c.GetBlock(0, y, 0); got != state {
+			t.Errorf("block at y=%d = %d, want %d", y, got, state)
+		}
+	}
+}
@@ -4,21 +4,50 @@ package gen
 type ChunkPos struct{ X, Z int }
 
 // Section holds block data for a 16×16×16 vertical slice of a chunk.
-// Index = y*256 + z*16 + x, value = blockID<<4 | metadata.
+// Index = y*256 + z*16 + x, value = blockID<<4 | metadata. The light arrays
+// hold one nibble per block in the same order, filled by ComputeLighting.
 type Section struct {
-	Blocks [4096]uint16
+	Blocks     [4096]uint16
+	BlockLight [2048]byte
+	SkyLight   [2048]byte
 }
 
 // ChunkData holds the generated terrain for one chunk column.
 type ChunkData struct {
 	Sections [16]*Section // nil = all-air
 	Biomes   [256]byte    // index = z*16 + x → biome ID
+	Lit      bool         // light arrays are filled
+
+	// Signs are the sign tile entities saved with the chunk. The world
+	// keeps sign text itself and takes them over when it loads the chunk.
+	Signs []Sign
+}
+
+// Sign is the text of a sign at absolute block coordinates.
+type Sign struct {
+	X, Y, Z int
+	Lines   [4]string
 }
 
 // Generator produces chunk data deterministically from a seed.
 type Generator interface {
 	Generate(chunkX, chunkZ int) *ChunkData
 	HeightAt(blockX, blockZ int) int
+	// HeightsFor returns the terrain height of every column in a chunk,
+	// indexed [x][z], matching HeightAt for each column.
+	HeightsFor(chunkX, chunkZ int) [16][16]int
+}
+
+// UniformHeights returns a chunk heightmap with every column at height, for
+// generators whose terrain is level.
+func UniformHeights(height int) [16][16]int {
+	var heights [16][16]int
+	for x := range heights {
+		for z := range heights[x] {
+			heights[x][z] = height
+		}
+	}
+	return heights
 }
 
 // SetBlock sets a block state at the given local coordinates within the chunk.
@@ -0,0 +1,131 @@
+package gen
+
+// Lake tuning. A chunk gets a surface water lake with probability
+// 1/waterLakeChance and an underground lava lake with probability
+// 1/lavaLakeChance.
+const (
+	waterLakeChance = 16
+	lavaLakeChance  = 8
+
+	lakeMinCenter = 5 // lake centers stay this far inside the chunk
+	lakeMaxRadius = 4 // so the lens and its seal never leave the chunk
+	lavaLakeMaxY  = 40
+)
+
+// LakeGenerator places small water lakes in the surface and lava lakes deep
+// underground.
+type LakeGenerator struct {
+	seed int64
+}
+
+// NewLakeGenerator creates a LakeGenerator from a seed.
+func NewLakeGenerator(seed int64) *LakeGenerator {
+	return &LakeGenerator{seed: seed}
+}
+
+// lake is a lens-shaped body of liquid centered on x, z in chunk-local
+// coordinates, with its liquid surface at y.
+type lake struct {
+	x, z   int
+	rx, rz int // horizontal radii
+	y      int
+	depth  int
+	liquid uint16
+}
+
+// Decorate places any lakes the chunk has. It runs before trees, which then
+// find no grass to grow on where the water is.
+func (lg *LakeGenerator) Decorate(c *ChunkData, chunkX, chunkZ int, heights *[16][16]int) {
+	rng := newChunkRNG(lg.seed, chunkX, chunkZ, 800)
+
+	if rng.nextN(waterLakeChance) == 0 {
+		l := newLake(rng, blockWater<<4)
+		// The lake's surface sits at the lowest ground it covers, so the
+		// water never stands above its banks.
+		l.y = 255
+		l.forEachColumn(func(x, z, _ int) {
+			l.y = min(l.y, heights[x][z])
+		})
+		if l.y > seaLevel && l.y < 250 {
+			l.place(c, heights)
+		}
+	}
+
+	if rng.nextN(lavaLakeChance) == 0 {
+		l := newLake(rng, blockLava<<4)
+		l.y = 8 + rng.nextN(lavaLakeMaxY-8)
+		if l.y+8 < heights[l.x][l.z] {
+			l.place(c, nil)
+		}
+	}
+}
+
+func newLake(rng *chunkRNG, liquid uint16) lake {
+	span := 16 - 2*lakeMinCenter
+	return lake{
+		x:      lakeMinCenter + rng.nextN(span),
+		z:      lakeMinCenter + rng.nextN(span),
+		rx:     3 + rng.nextN(lakeMaxRadius-2),
+		rz:     3 + rng.nextN(lakeMaxRadius-2),
+		depth:  2 + rng.nextN(3),
+		liquid: liquid,
+	}
+}
+
+// forEachColumn calls fn for every column inside the lens with the liquid
+// depth there, deepest at the center and thinning toward the rim.
+func (l lake) forEachColumn(fn func(x, z, depth int)) {
+	for x := l.x - l.rx; x <= l.x+l.rx; x++ {
+		for z := l.z - l.rz; z <= l.z+l.rz; z++ {
+			nx := float64(x-l.x) / float64(l.rx)
+			nz := float64(z-l.z) / float64(l.rz)
+			r2 := nx*nx + nz*nz
+			if r2 >= 1 {
+				continue
+			}
+			fn(x, z, max(int(float64(l.depth)*(1-r2)+0.5), 1))
+		}
+	}
+}
+
+// place fills the lens with liquid, clears the ground above it and seals
+// any air around it so it cannot spill into caves. heights is the surface
+// to clear down to, or nil for a lake with an air pocket above it
+// underground.
+func (l lake) place(c *ChunkData, heights *[16][16]int) {
+	inLens := make(map[[2]int]int)
+	l.forEachColumn(func(x, z, depth int) {
+		inLens[[2]int{x, z}] = depth
+	})
+
+	for col, depth := range inLens {
+		x, z := col[0], col[1]
+		for y := l.y - depth + 1; y <= l.y; y++ {
+			setIfInBounds(c, x, y, z, l.liquid)
+		}
+		top := l.y + 2
+		if heights != nil {
+			top = heights[x][z]
+		}
+		for y := l.y + 1; y <= top; y++ {
+			setIfInBounds(c, x, y, z, blockAir)
+		}
+
+		// Seal the bottom.
+		if bottom := l.y - depth; c.GetBlock(x, bottom, z) == blockAir {
+			setIfInBounds(c, x, bottom, z, blockStone<<4)
+		}
+		// Seal the sides next to liquid.
+		for _, d := range [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			nx, nz := x+d[0], z+d[1]
+			if _, ok := inLens[[2]int{nx, nz}]; ok {
+				continue
+			}
+			for y := l.y - depth + 1; y <= l.y; y++ {
+				if nx >= 0 && nx < 16 && nz >= 0 && nz < 16 && c.GetBlock(nx, y, nz) == blockAir {
+					setIfInBounds(c, nx, y, nz, blockStone<<4)
+				}
+			}
+		}
+	}
+}
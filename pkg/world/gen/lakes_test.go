@@ -0,0 +1,91 @@
+package gen
+
+import "testing"
+
+// grassChunk returns a chunk of stone with a grass surface at y=70.
+func grassChunk() (*ChunkData, [16][16]int) {
+	c := stoneChunk()
+	var heights [16][16]int
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			for y := 66; y < 70; y++ {
+				c.SetBlock(x, y, z, blockDirt<<4)
+			}
+			c.SetBlock(x, 70, z, blockGrass<<4)
+			for y := 71; y <= 100; y++ {
+				c.SetBlock(x, y, z, blockAir)
+			}
+			heights[x][z] = 70
+		}
+	}
+	return c, heights
+}
+
+// findWaterLake returns the first chunk near the origin that gets a water lake.
+func findWaterLake(t *testing.T, lg *LakeGenerator) (int, int) {
+	t.Helper()
+	for cx := 0; cx < 32; cx++ {
+		if newChunkRNG(lg.seed, cx, 0, 800).nextN(waterLakeChance) == 0 {
+			return cx, 0
+		}
+	}
+	t.Fatal("no water lake near the origin")
+	return 0, 0
+}
+
+func TestLakeFormsSealedPool(t *testing.T) {
+	lg := NewLakeGenerator(42)
+	cx, cz := findWaterLake(t, lg)
+	c, heights := grassChunk()
+	lg.Decorate(c, cx, cz, &heights)
+
+	var water [][3]int
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			for y := 60; y <= 71; y++ {
+				if c.GetBlock(x, y, z) == blockWater<<4 {
+					water = append(water, [3]int{x, y, z})
+				}
+			}
+		}
+	}
+	if len(water) < 9 {
+		t.Fatalf("lake holds %d water blocks, want at least 9", len(water))
+	}
+
+	for _, w := range water {
+		x, y, z := w[0], w[1], w[2]
+		if y != 70 && c.GetBlock(x, y+1, z) != blockWater<<4 {
+			t.Errorf("water at (%d, %d, %d) is not covered by water or open to the sky", x, y, z)
+		}
+		if y == 70 && c.GetBlock(x, 71, z) != blockAir {
+			t.Errorf("lake surface at (%d, %d) is covered", x, z)
+		}
+		if c.GetBlock(x, y-1, z) == blockAir {
+			t.Errorf("water at (%d, %d, %d) has air below it", x, y, z)
+		}
+	}
+}
+
+func TestLakeSkipsLowGround(t *testing.T) {
+	lg := NewLakeGenerator(42)
+	cx, cz := findWaterLake(t, lg)
+	c := stoneChunk()
+	var heights [16][16]int
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			heights[x][z] = seaLevel
+		}
+	}
+	lg.Decorate(c, cx, cz, &heights)
+
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			for y := 40; y <= 100; y++ {
+				if c.GetBlock(x, y, z) == blockWater<<4 {
+					t.Fatalf("water lake placed at sea level at (%d, %d, %d)", x, y, z)
+				}
+			}
+		}
+	}
+}
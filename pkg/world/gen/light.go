@@ -0,0 +1,187 @@
+package gen
+
+import "github.com/go-theft-craft/server/pkg/gamedata"
+
+// maxLight is the brightest light level.
+const maxLight = 15
+
+// Lighting is computed over the chunk and its eight neighbors, so light
+// from a torch or an opening next to a chunk border reaches across it.
+const (
+	regionWidth = 3 * 16
+	regionArea  = regionWidth * regionWidth
+	regionSize  = regionArea * 256
+)
+
+// Neighbors holds the chunks around a chunk, indexed [dx+1][dz+1]. The
+// center entry is ignored, and nil entries are chunks that are not loaded.
+type Neighbors [3][3]*ChunkData
+
+// lightProps caches how block IDs emit and absorb light.
+type lightProps struct {
+	blocks gamedata.BlockRegistry
+	known  [4096]bool
+	emit   [4096]int8
+	filter [4096]int8
+}
+
+// lookup returns the light emitted and absorbed by a block state. Unknown
+// blocks are treated as air.
+func (p *lightProps) lookup(state uint16) (emit, filter int) {
+	id := state >> 4
+	if !p.known[id] {
+		p.known[id] = true
+		if b, ok := p.blocks.ByID(int(id)); ok {
+			p.emit[id] = int8(b.EmitLight)
+			p.filter[id] = int8(b.FilterLight)
+		}
+	}
+	return int(p.emit[id]), int(p.filter[id])
+}
+
+// lightRegion is a chunk and its neighbors, addressed by region
+// coordinates in which the center chunk starts at (16, 16).
+type lightRegion struct {
+	chunks [3][3]*ChunkData
+	props  *lightProps
+}
+
+// block returns the block state at region (x, y, z) and whether its chunk
+// is loaded.
+func (r *lightRegion) block(x, y, z int) (uint16, bool) {
+	c := r.chunks[x>>4][z>>4]
+	if c == nil {
+		return 0, false
+	}
+	return c.GetBlock(x&0xF, y, z&0xF), true
+}
+
+// ComputeLighting fills the BlockLight and SkyLight arrays of every section.
+// Skylight falls straight down from the top of each column, dimmed only by
+// blocks that filter light, and block light starts at blocks with EmitLight;
+// both then spread to neighbors, losing at least one level per block. Light
+// is spread through the loaded chunks in around as well, so it crosses
+// chunk borders; chunks that are not loaded pass no light.
+func (c *ChunkData) ComputeLighting(blocks gamedata.BlockRegistry, around Neighbors) {
+	r := &lightRegion{chunks: around, props: &lightProps{blocks: blocks}}
+	r.chunks[1][1] = c
+
+	sky := make([]byte, regionSize)
+	block := make([]byte, regionSize)
+	var queue []int
+
+	top := -1
+	for _, row := range r.chunks {
+		for _, n := range row {
+			if n != nil {
+				top = max(top, n.topY())
+			}
+		}
+	}
+
+	for x := 0; x < regionWidth; x++ {
+		for z := 0; z < regionWidth; z++ {
+			if r.chunks[x>>4][z>>4] == nil {
+				continue
+			}
+			level := maxLight
+			for y := 255; y >= 0; y-- {
+				i := y*regionArea + z*regionWidth + x
+				if y > top {
+					sky[i] = maxLight
+					continue
+				}
+				state, _ := r.block(x, y, z)
+				emit, filter := r.props.lookup(state)
+				level = max(level-filter, 0)
+				sky[i] = byte(level)
+				if emit > 0 {
+					block[i] = byte(emit)
+					queue = append(queue, i)
+				}
+			}
+		}
+	}
+	r.spreadLight(block, queue)
+
+	// Above the highest block every cell has full skylight, so nothing
+	// there can brighten a neighbor.
+	queue = queue[:0]
+	for i, level := range sky[:min(top+2, 256)*regionArea] {
+		if level > 1 {
+			queue = append(queue, i)
+		}
+	}
+	r.spreadLight(sky, queue)
+
+	for sec, s := range c.Sections {
+		if s == nil {
+			continue
+		}
+		for i := 0; i < 4096; i++ {
+			x, y, z := i&0xF, sec*16+i>>8, (i>>4)&0xF
+			j := y*regionArea + (z+16)*regionWidth + x + 16
+			setNibble(s.BlockLight[:], i, block[j])
+			setNibble(s.SkyLight[:], i, sky[j])
+		}
+	}
+	c.Lit = true
+}
+
+// spreadLight floods light outward from the queued cells. A neighbor takes
+// the source level minus its own filter, and at least one.
+func (r *lightRegion) spreadLight(light []byte, queue []int) {
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		level := int(light[i])
+		if level <= 1 {
+			continue
+		}
+
+		x, y, z := i%regionWidth, i/regionArea, (i/regionWidth)%regionWidth
+		for _, n := range [6][3]int{{x + 1, y, z}, {x - 1, y, z}, {x, y + 1, z}, {x, y - 1, z}, {x, y, z + 1}, {x, y, z - 1}} {
+			nx, ny, nz := n[0], n[1], n[2]
+			if nx < 0 || nx >= regionWidth || ny < 0 || ny > 255 || nz < 0 || nz >= regionWidth {
+				continue
+			}
+			state, ok := r.block(nx, ny, nz)
+			if !ok {
+				continue
+			}
+			_, filter := r.props.lookup(state)
+			next := level - max(filter, 1)
+			j := ny*regionArea + nz*regionWidth + nx
+			if next > int(light[j]) {
+				light[j] = byte(next)
+				queue = append(queue, j)
+			}
+		}
+	}
+}
+
+// topY returns the Y of the highest non-air block in the chunk, or -1.
+func (c *ChunkData) topY() int {
+	for sec := 15; sec >= 0; sec-- {
+		s := c.Sections[sec]
+		if s == nil {
+			continue
+		}
+		for i := 4095; i >= 0; i-- {
+			if s.Blocks[i] != 0 {
+				return sec*16 + (i >> 8)
+			}
+		}
+	}
+	return -1
+}
+
+// setNibble stores a 4-bit value at index in a nibble array; even indices
+// use the low half of a byte.
+func setNibble(arr []byte, index int, val byte) {
+	if index%2 == 0 {
+		arr[index/2] = arr[index/2]&0xF0 | val&0x0F
+	} else {
+		arr[index/2] = arr[index/2]&0x0F | val<<4
+	}
+}
@@ -0,0 +1,120 @@
+package gen
+
+import (
+	"testing"
+
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+)
+
+const (
+	stateStone = blockStone << 4
+	stateTorch = 50<<4 | 5 // standing torch
+)
+
+// lightAt returns the block and sky light at local (x, y, z).
+func lightAt(c *ChunkData, x, y, z int) (block, sky byte) {
+	s := c.Sections[y>>4]
+	i := (y&0xF)*256 + z*16 + x
+	shift := uint(i%2) * 4
+	return s.BlockLight[i/2] >> shift & 0xF, s.SkyLight[i/2] >> shift & 0xF
+}
+
+// stoneFloor returns a chunk filled with stone from y=0 to y=4.
+func stoneFloor() *ChunkData {
+	c := &ChunkData{}
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			for y := 0; y <= 4; y++ {
+				c.SetBlock(x, y, z, stateStone)
+			}
+		}
+	}
+	return c
+}
+
+func TestTorchLightGradient(t *testing.T) {
+	c := stoneFloor()
+	c.SetBlock(4, 5, 8, stateTorch)
+	c.ComputeLighting(pkt.New().Blocks, Neighbors{})
+
+	if !c.Lit {
+		t.Fatal("chunk not marked lit")
+	}
+	for d := 0; d <= 10; d++ {
+		block, _ := lightAt(c, 4+d, 5, 8)
+		if want := byte(14 - d); block != want {
+			t.Errorf("block light %d blocks from torch = %d, want %d", d, block, want)
+		}
+	}
+	if block, _ := lightAt(c, 4, 4, 8); block != 0 {
+		t.Errorf("block light inside stone = %d, want 0", block)
+	}
+}
+
+func TestOverhangReducesSkylight(t *testing.T) {
+	c := stoneFloor()
+	for x := 0; x < 8; x++ {
+		for z := 0; z < 16; z++ {
+			c.SetBlock(x, 9, z, stateStone)
+		}
+	}
+	c.ComputeLighting(pkt.New().Blocks, Neighbors{})
+
+	if _, sky := lightAt(c, 12, 5, 8); sky != 15 {
+		t.Errorf("open sky light = %d, want 15", sky)
+	}
+	if _, sky := lightAt(c, 7, 5, 8); sky != 14 {
+		t.Errorf("sky light at overhang edge = %d, want 14", sky)
+	}
+	if _, sky := lightAt(c, 2, 5, 8); sky != 9 {
+		t.Errorf("sky light deep under overhang = %d, want 9", sky)
+	}
+	if _, sky := lightAt(c, 2, 10, 8); sky != 15 {
+		t.Errorf("sky light on top of overhang = %d, want 15", sky)
+	}
+}
+
+func TestEnclosedCaveIsDark(t *testing.T) {
+	c := stoneFloor()
+	c.SetBlock(8, 2, 8, 0)
+	c.SetBlock(9, 2, 8, 0)
+	c.ComputeLighting(pkt.New().Blocks, Neighbors{})
+
+	block, sky := lightAt(c, 8, 2, 8)
+	if block != 0 || sky != 0 {
+		t.Errorf("cave light = (%d, %d), want dark", block, sky)
+	}
+}
+
+func TestTorchLightCrossesChunkBorder(t *testing.T) {
+	c := stoneFloor()
+	west := stoneFloor()
+	west.SetBlock(14, 5, 8, stateTorch)
+	c.ComputeLighting(pkt.New().Blocks, Neighbors{0: {1: west}})
+
+	// The torch is two blocks west of local x=0.
+	if block, _ := lightAt(c, 0, 5, 8); block != 12 {
+		t.Errorf("block light across the border = %d, want 12", block)
+	}
+	if block, _ := lightAt(c, 3, 5, 8); block != 9 {
+		t.Errorf("block light 5 blocks from the torch = %d, want 9", block)
+	}
+}
+
+func TestSkylightEntersThroughNeighborShaft(t *testing.T) {
+	c := stoneFloor()
+	c.SetBlock(0, 2, 8, 0)
+	west := stoneFloor()
+	for y := 2; y <= 4; y++ {
+		west.SetBlock(15, y, 8, 0)
+	}
+
+	c.ComputeLighting(pkt.New().Blocks, Neighbors{})
+	if _, sky := lightAt(c, 0, 2, 8); sky != 0 {
+		t.Errorf("sky light without the neighbor = %d, want 0", sky)
+	}
+	c.ComputeLighting(pkt.New().Blocks, Neighbors{0: {1: west}})
+	if _, sky := lightAt(c, 0, 2, 8); sky != 14 {
+		t.Errorf("sky light beside the neighbor's shaft = %d, want 14", sky)
+	}
+}
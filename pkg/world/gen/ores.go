@@ -96,3 +96,8 @@ func (r *chunkRNG) nextN(n int) int {
 	}
 	return v
 }
+
+// nextFloat returns a value in [0, 1).
+func (r *chunkRNG) nextFloat() float64 {
+	return float64(r.nextN(1<<24)) / (1 << 24)
+}
@@ -0,0 +1,144 @@
+package gen
+
+import "math"
+
+// Ravine tuning. A chunk starts a ravine with probability 1/ravineChance;
+// a ravine can reach ravineRange chunks from the chunk it starts in.
+const (
+	ravineChance    = 50
+	ravineRange     = 8
+	ravineMinY      = 1  // lowest carved layer, just above the bedrock floor
+	ravineLavaLevel = 10 // carved blocks below this fill with lava
+)
+
+// RavineGenerator carves long, narrow and deep canyons.
+type RavineGenerator struct {
+	seed     int64
+	heightAt func(blockX, blockZ int) int // terrain height, for finding water
+}
+
+// NewRavineGenerator creates a RavineGenerator from a seed. heightAt gives
+// the terrain height of any column, so that ravines can stay clear of the
+// sea and rivers in neighboring chunks as well as the one being carved.
+func NewRavineGenerator(seed int64, heightAt func(blockX, blockZ int) int) *RavineGenerator {
+	return &RavineGenerator{seed: seed + 500, heightAt: heightAt}
+}
+
+// ravineStep is one point along a ravine's path with the horizontal and
+// vertical radii carved around it, in world block coordinates.
+type ravineStep struct {
+	x, y, z float64
+	hr, vr  float64
+}
+
+// Carve removes the parts of any nearby ravine that fall inside the chunk.
+// Each ravine's path depends only on the seed and the chunk it starts in,
+// so neighboring chunks carve matching halves.
+func (rg *RavineGenerator) Carve(c *ChunkData, chunkX, chunkZ int) {
+	// Neighboring steps overlap, so remember the heights already looked up.
+	heights := make(map[[2]int]int)
+	height := func(x, z int) int {
+		h, ok := heights[[2]int{x, z}]
+		if !ok {
+			h = rg.heightAt(x, z)
+			heights[[2]int{x, z}] = h
+		}
+		return h
+	}
+
+	for sx := chunkX - ravineRange; sx <= chunkX+ravineRange; sx++ {
+		for sz := chunkZ - ravineRange; sz <= chunkZ+ravineRange; sz++ {
+			path, ok := rg.ravineFrom(sx, sz)
+			if !ok {
+				continue
+			}
+			for _, step := range path {
+				carveRavineStep(c, chunkX, chunkZ, step, height)
+			}
+		}
+	}
+}
+
+// ravineFrom returns the path of the ravine starting in chunk sx, sz, or
+// false if the chunk starts none.
+func (rg *RavineGenerator) ravineFrom(sx, sz int) ([]ravineStep, bool) {
+	rng := newChunkRNG(rg.seed, sx, sz, 700)
+	if rng.nextN(ravineChance) != 0 {
+		return nil, false
+	}
+
+	x := float64(sx*16 + rng.nextN(16))
+	y := float64(20 + rng.nextN(rng.nextN(40)+8))
+	z := float64(sz*16 + rng.nextN(16))
+	yaw := rng.nextFloat() * 2 * math.Pi
+	pitch := (rng.nextFloat() - 0.5) / 4
+	width := (rng.nextFloat()*2 + rng.nextFloat()) * 2
+	length := ravineRange*16 - 16 - rng.nextN(28)
+
+	var yawDrift, pitchDrift float64
+	path := make([]ravineStep, 0, length)
+	for i := range length {
+		hr := 1.5 + math.Sin(float64(i)*math.Pi/float64(length))*width
+		path = append(path, ravineStep{x: x, y: y, z: z, hr: hr, vr: hr * 3})
+
+		x += math.Cos(yaw) * math.Cos(pitch)
+		y += math.Sin(pitch)
+		z += math.Sin(yaw) * math.Cos(pitch)
+
+		pitch = pitch*0.7 + pitchDrift*0.05
+		yaw += yawDrift * 0.1
+		pitchDrift = pitchDrift*0.8 + (rng.nextFloat()-rng.nextFloat())*rng.nextFloat()*2
+		yawDrift = yawDrift*0.5 + (rng.nextFloat()-rng.nextFloat())*rng.nextFloat()*4
+	}
+	return path, true
+}
+
+// carveRavineStep clears the ellipsoid around one path step within the
+// chunk. Steps that would open into water are skipped so oceans and rivers
+// do not hang over air. Water is judged from the terrain height over the
+// whole step rather than the chunk's blocks, so every chunk the step
+// touches makes the same choice.
+func carveRavineStep(c *ChunkData, chunkX, chunkZ int, s ravineStep, height func(x, z int) int) {
+	ox, oz := chunkX*16, chunkZ*16
+	x0, x1 := int(math.Floor(s.x-s.hr)), int(math.Floor(s.x+s.hr))
+	z0, z1 := int(math.Floor(s.z-s.hr)), int(math.Floor(s.z+s.hr))
+	minX, maxX := max(x0-ox, 0), min(x1-ox, 15)
+	minZ, maxZ := max(z0-oz, 0), min(z1-oz, 15)
+	minY := max(int(math.Floor(s.y-s.vr)), ravineMinY)
+	maxY := min(int(math.Floor(s.y+s.vr)), 254)
+	if minX > maxX || minZ > maxZ || minY > maxY {
+		return
+	}
+
+	for x := x0; x <= x1; x++ {
+		for z := z0; z <= z1; z++ {
+			// Terrain below sea level is flooded from above its surface
+			// up to sea level.
+			if h := height(x, z); h < seaLevel && h < maxY+1 && seaLevel >= minY {
+				return
+			}
+		}
+	}
+
+	inside := func(x, y, z int) bool {
+		dx := (float64(x+ox) + 0.5 - s.x) / s.hr
+		dy := (float64(y) + 0.5 - s.y) / s.vr
+		dz := (float64(z+oz) + 0.5 - s.z) / s.hr
+		return dx*dx+dy*dy+dz*dz < 1
+	}
+
+	for x := minX; x <= maxX; x++ {
+		for z := minZ; z <= maxZ; z++ {
+			for y := minY; y <= maxY; y++ {
+				if !inside(x, y, z) || c.GetBlock(x, y, z) == blockBedrock<<4 {
+					continue
+				}
+				if y < ravineLavaLevel {
+					c.SetBlock(x, y, z, blockLava<<4)
+				} else {
+					c.SetBlock(x, y, z, blockAir<<4)
+				}
+			}
+		}
+	}
+}
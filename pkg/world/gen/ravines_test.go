@@ -0,0 +1,123 @@
+package gen
+
+import (
+	"math"
+	"testing"
+)
+
+// stoneChunk returns a chunk of solid stone up to y=100 over a bedrock floor.
+func stoneChunk() *ChunkData {
+	c := &ChunkData{}
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			c.SetBlock(x, 0, z, blockBedrock<<4)
+			for y := 1; y <= 100; y++ {
+				c.SetBlock(x, y, z, blockStone<<4)
+			}
+		}
+	}
+	return c
+}
+
+// dryHeight is a terrain height above sea level everywhere, matching
+// stoneChunk.
+func dryHeight(_, _ int) int { return 100 }
+
+// findRavine returns the first ravine starting near the origin.
+func findRavine(t *testing.T, rg *RavineGenerator) []ravineStep {
+	t.Helper()
+	for sx := -16; sx <= 16; sx++ {
+		for sz := -16; sz <= 16; sz++ {
+			if path, ok := rg.ravineFrom(sx, sz); ok {
+				return path
+			}
+		}
+	}
+	t.Fatal("no ravine starts near the origin")
+	return nil
+}
+
+func TestRavineCarvesTallAirColumn(t *testing.T) {
+	rg := NewRavineGenerator(42, dryHeight)
+	path := findRavine(t, rg)
+
+	// The ravine is widest and deepest halfway along its path.
+	mid := path[len(path)/2]
+	bx, bz := int(math.Floor(mid.x)), int(math.Floor(mid.z))
+	cx, cz := floorDiv(bx, 16), floorDiv(bz, 16)
+	c := stoneChunk()
+	rg.Carve(c, cx, cz)
+
+	longest, run := 0, 0
+	for y := 1; y <= 100; y++ {
+		if c.GetBlock(bx-cx*16, y, bz-cz*16) == blockAir {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	if longest < 12 {
+		t.Errorf("longest air column at (%d, %d) spans %d layers, want at least 12", bx, bz, longest)
+	}
+}
+
+func TestRavineDeterministic(t *testing.T) {
+	path := findRavine(t, NewRavineGenerator(42, dryHeight))
+	mid := path[len(path)/2]
+	cx, cz := floorDiv(int(mid.x), 16), floorDiv(int(mid.z), 16)
+
+	a, b := stoneChunk(), stoneChunk()
+	NewRavineGenerator(42, dryHeight).Carve(a, cx, cz)
+	rg := NewRavineGenerator(42, dryHeight)
+	rg.Carve(stoneChunk(), cx+1, cz) // carving a neighbor first changes nothing
+	rg.Carve(b, cx, cz)
+
+	for i := range a.Sections {
+		if a.Sections[i] != nil && a.Sections[i].Blocks != b.Sections[i].Blocks {
+			t.Fatalf("section %d differs between runs", i)
+		}
+	}
+}
+
+func TestRavineKeepsOutOfWater(t *testing.T) {
+	// Terrain below sea level everywhere: every step would open into water.
+	rg := NewRavineGenerator(42, func(_, _ int) int { return 1 })
+	path := findRavine(t, rg)
+	mid := path[len(path)/2]
+	cx, cz := floorDiv(int(mid.x), 16), floorDiv(int(mid.z), 16)
+
+	c := stoneChunk()
+	before := *c.Sections[2]
+	rg.Carve(c, cx, cz)
+	if c.Sections[2].Blocks != before.Blocks {
+		t.Error("ravine carved under the sea")
+	}
+}
+
+func TestRavineStepAtChunkBorderSeesNeighborWater(t *testing.T) {
+	// A step straddling the border between chunks 0 and 1, with the sea
+	// only on the chunk 1 side.
+	step := ravineStep{x: 16, y: 40, z: 8, hr: 3, vr: 9}
+	wetEast := func(x, _ int) int {
+		if x >= 16 {
+			return 30
+		}
+		return 100
+	}
+
+	for _, cx := range []int{0, 1} {
+		c := stoneChunk()
+		before := *c.Sections[2]
+		carveRavineStep(c, cx, 0, step, wetEast)
+		if c.Sections[2].Blocks != before.Blocks {
+			t.Errorf("chunk %d carved a step that opens into water in chunk 1", cx)
+		}
+	}
+
+	c := stoneChunk()
+	carveRavineStep(c, 0, 0, step, dryHeight)
+	if got := c.GetBlock(15, 40, 8); got != blockAir {
+		t.Errorf("dry step left block %d at the border, want air", got)
+	}
+}
@@ -0,0 +1,39 @@
+package gen
+
+import "testing"
+
+func TestRiverCrossesLine(t *testing.T) {
+	g := NewDefaultGenerator(42)
+
+	rivers := 0
+	for bx := 0; bx < 4096; bx++ {
+		if g.biomeGen.BiomeAt(bx, 0) != biomeRiver || g.biomeGen.riverAt(bx, 0) >= riverWidth {
+			continue
+		}
+		rivers++
+		if h := g.HeightAt(bx, 0); h >= seaLevel {
+			t.Fatalf("river channel at x=%d has height %d, want below sea level %d", bx, h, seaLevel)
+		}
+	}
+	if rivers == 0 {
+		t.Error("no river channel along z=0 for x in [0, 4096)")
+	}
+}
+
+func TestRiverHeightSlopesUpBanks(t *testing.T) {
+	land := seaLevel + 20
+	prev := riverHeight(land, 0)
+	if prev >= seaLevel {
+		t.Fatalf("river center height = %d, want below sea level", prev)
+	}
+	for r := 0.0; r <= riverWidth+riverBankWidth; r += 0.005 {
+		h := riverHeight(land, r)
+		if h < prev {
+			t.Fatalf("height at r=%.3f = %d, lower than %d closer to the center", r, h, prev)
+		}
+		prev = h
+	}
+	if h := riverHeight(land, riverWidth+riverBankWidth); h != land {
+		t.Errorf("height at the outer bank = %d, want the land height %d", h, land)
+	}
+}
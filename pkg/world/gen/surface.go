@@ -33,6 +33,19 @@ func applySurface(c *ChunkData, x, z, height int, biome byte) {
 			c.SetBlock(x, height-4, z, blockSandstone<<4)
 		}
 
+	case biomeRiver:
+		// Gravel on the river bed, sand on its banks.
+		top := uint16(blockSand << 4)
+		if height < seaLevel {
+			top = blockGravel << 4
+		}
+		for y := height; y > height-2 && y > 3; y-- {
+			c.SetBlock(x, y, z, top)
+		}
+		for y := height - 2; y > height-4 && y > 3; y-- {
+			c.SetBlock(x, y, z, blockSand<<4)
+		}
+
 	case biomeMountains:
 		// Stone with thin dirt/grass cap above tree line, normal below.
 		if height > 100 {
@@ -45,7 +45,7 @@ func treesForBiome(biome byte) int {
 	switch biome {
 	case biomeDesert:
 		return 0
-	case biomeOcean, biomeBeach:
+	case biomeOcean, biomeBeach, biomeRiver:
 		return 0
 	case biomePlains, biomeSavanna:
 		return 1
@@ -113,7 +113,7 @@ func (tg *TreeGenerator) placeOak(c *ChunkData, x, baseY, z int, rng *chunkRNG)
 					continue
 				}
 				// Skip corners for round shape on wider layers.
-				if radius == 2 && abs(dx) == 2 && abs(dz) == 2 && rng.nextN(2) == 0 {
+				if radius == 2 && Abs(dx) == 2 && Abs(dz) == 2 && rng.nextN(2) == 0 {
 					continue
 				}
 				if c.GetBlock(lx, y, lz) == 0 {
@@ -152,7 +152,7 @@ func (tg *TreeGenerator) placeBirch(c *ChunkData, x, baseY, z int, rng *chunkRNG
 				if dx == 0 && dz == 0 && dy < trunkHeight-(leafBase-baseY) {
 					continue
 				}
-				if radius == 2 && abs(dx) == 2 && abs(dz) == 2 && rng.nextN(2) == 0 {
+				if radius == 2 && Abs(dx) == 2 && Abs(dz) == 2 && rng.nextN(2) == 0 {
 					continue
 				}
 				if c.GetBlock(lx, y, lz) == 0 {
@@ -269,7 +269,8 @@ func setIfInBounds(c *ChunkData, x, y, z int, state uint16) {
 	}
 }
 
-func abs(x int) int {
+// Abs returns the absolute value of x.
+func Abs(x int) int {
 	if x < 0 {
 		return -x
 	}
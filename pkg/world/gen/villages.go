@@ -0,0 +1,251 @@
+package gen
+
+// Blocks used by village pieces.
+const (
+	blockCobblestone = 4
+	blockPlanks      = 5
+	blockFence       = 85
+	blockGlassPane   = 102
+	blockOakDoor     = 64
+	blockTorch       = 50
+
+	doorUpperHalf = 0x8
+)
+
+// Villages are planned once per region of villageRegionChunks x
+// villageRegionChunks chunks. The village center stays villageMargin chunks
+// away from the region edge, so a village never reaches into a neighboring
+// region.
+const (
+	villageRegionChunks = 32
+	villageMargin       = 4
+	villageRarity       = 2 // one region in villageRarity attempts a village
+
+	houseSize  = 5
+	houseRoof  = 4 // roof height above the floor
+	wellSize   = 4
+	maxBuildUp = 8 // deepest foundation filled under a piece
+)
+
+// Door facings (1.8 door metadata).
+const (
+	facingEast = iota
+	facingSouth
+	facingWest
+	facingNorth
+)
+
+// StructureGenerator places villages on plains and savanna. Each village is
+// planned from the seed and its region alone, so every chunk it overlaps
+// builds its own part without writing outside the chunk.
+type StructureGenerator struct {
+	seed     int64
+	heightAt func(bx, bz int) int
+	biomeAt  func(bx, bz int) byte
+}
+
+// NewStructureGenerator creates a StructureGenerator from a seed. heightAt
+// and biomeAt report the terrain of any column, including columns in chunks
+// that have not been generated.
+func NewStructureGenerator(seed int64, heightAt func(bx, bz int) int, biomeAt func(bx, bz int) byte) *StructureGenerator {
+	return &StructureGenerator{seed: seed, heightAt: heightAt, biomeAt: biomeAt}
+}
+
+// village is the layout of one village in world block coordinates.
+type village struct {
+	well   structurePiece
+	houses []structurePiece
+}
+
+// structurePiece is a building with its minimum corner at x, z and its floor
+// at y. door is the facing of the wall holding the door (houses only).
+type structurePiece struct {
+	x, y, z int
+	door    int
+}
+
+// Place builds the parts of any village that fall inside the chunk.
+func (sg *StructureGenerator) Place(c *ChunkData, chunkX, chunkZ int) {
+	v, ok := sg.villageIn(floorDiv(chunkX, villageRegionChunks), floorDiv(chunkZ, villageRegionChunks))
+	if !ok {
+		return
+	}
+	w := chunkWriter{c: c, ox: chunkX * 16, oz: chunkZ * 16}
+	if w.overlaps(v.well.x, v.well.z, wellSize) {
+		sg.buildWell(w, v.well)
+	}
+	for _, h := range v.houses {
+		if w.overlaps(h.x, h.z, houseSize) {
+			sg.buildHouse(w, h)
+		}
+	}
+}
+
+// villageIn plans the village of a region, or returns false if the region
+// has none.
+func (sg *StructureGenerator) villageIn(regionX, regionZ int) (village, bool) {
+	rng := newChunkRNG(sg.seed, regionX, regionZ, 900)
+	if rng.nextN(villageRarity) != 0 {
+		return village{}, false
+	}
+
+	span := villageRegionChunks - 2*villageMargin
+	cx := (regionX*villageRegionChunks+villageMargin+rng.nextN(span))*16 + 8
+	cz := (regionZ*villageRegionChunks+villageMargin+rng.nextN(span))*16 + 8
+	switch sg.biomeAt(cx, cz) {
+	case biomePlains, biomeSavanna:
+	default:
+		return village{}, false
+	}
+	y := sg.heightAt(cx, cz)
+	if y <= seaLevel || y+houseRoof >= 255 {
+		return village{}, false
+	}
+
+	v := village{well: structurePiece{x: cx - wellSize/2, y: y, z: cz - wellSize/2}}
+
+	// Up to one house on each side of the well, door facing the well.
+	sides := []struct{ dx, dz, door int }{
+		{1, 0, facingWest},
+		{-1, 0, facingEast},
+		{0, 1, facingNorth},
+		{0, -1, facingSouth},
+	}
+	for _, s := range sides {
+		if rng.nextN(4) == 0 {
+			continue
+		}
+		dist := 7 + rng.nextN(4)
+		hx := cx + s.dx*dist - houseSize/2
+		hz := cz + s.dz*dist - houseSize/2
+		hy := sg.heightAt(hx+houseSize/2, hz+houseSize/2)
+		if hy <= seaLevel || hy+houseRoof >= 255 {
+			continue
+		}
+		v.houses = append(v.houses, structurePiece{x: hx, y: hy, z: hz, door: s.door})
+	}
+	return v, true
+}
+
+// buildWell places a cobblestone well with water inside and a roof on
+// fence posts.
+func (sg *StructureGenerator) buildWell(w chunkWriter, p structurePiece) {
+	sg.foundation(w, p, wellSize)
+	for dx := 0; dx < wellSize; dx++ {
+		for dz := 0; dz < wellSize; dz++ {
+			x, z := p.x+dx, p.z+dz
+			edge := dx == 0 || dz == 0 || dx == wellSize-1 || dz == wellSize-1
+			corner := (dx == 0 || dx == wellSize-1) && (dz == 0 || dz == wellSize-1)
+
+			w.set(x, p.y, z, blockCobblestone<<4)
+			if edge {
+				w.set(x, p.y+1, z, blockCobblestone<<4)
+			} else {
+				w.set(x, p.y, z, blockWater<<4)
+				w.set(x, p.y+1, z, blockAir)
+			}
+			for y := p.y + 2; y <= p.y+3; y++ {
+				if corner {
+					w.set(x, y, z, blockFence<<4)
+				} else {
+					w.set(x, y, z, blockAir)
+				}
+			}
+			w.set(x, p.y+4, z, blockCobblestone<<4)
+		}
+	}
+}
+
+// buildHouse places a small plank house with log corners, a door, glass
+// windows and a torch inside.
+func (sg *StructureGenerator) buildHouse(w chunkWriter, p structurePiece) {
+	sg.foundation(w, p, houseSize)
+	last := houseSize - 1
+	for dx := 0; dx < houseSize; dx++ {
+		for dz := 0; dz < houseSize; dz++ {
+			x, z := p.x+dx, p.z+dz
+			wall := dx == 0 || dz == 0 || dx == last || dz == last
+			corner := (dx == 0 || dx == last) && (dz == 0 || dz == last)
+
+			w.set(x, p.y, z, blockCobblestone<<4)
+			for y := p.y + 1; y < p.y+houseRoof; y++ {
+				switch {
+				case corner:
+					w.set(x, y, z, blockLog<<4|logOak)
+				case wall && y == p.y+2 && (dx == houseSize/2 || dz == houseSize/2):
+					w.set(x, y, z, blockGlassPane<<4)
+				case wall:
+					w.set(x, y, z, blockPlanks<<4)
+				default:
+					w.set(x, y, z, blockAir)
+				}
+			}
+			w.set(x, p.y+houseRoof, z, blockPlanks<<4)
+		}
+	}
+
+	// The door replaces the window in the middle of its wall.
+	mid := houseSize / 2
+	dx, dz := mid, mid
+	switch p.door {
+	case facingEast:
+		dx = last
+	case facingWest:
+		dx = 0
+	case facingSouth:
+		dz = last
+	case facingNorth:
+		dz = 0
+	}
+	w.set(p.x+dx, p.y+1, p.z+dz, blockOakDoor<<4|uint16(p.door))
+	w.set(p.x+dx, p.y+2, p.z+dz, blockOakDoor<<4|doorUpperHalf)
+	w.set(p.x+mid, p.y+3, p.z+mid, blockTorch<<4|5) // standing torch
+}
+
+// foundation fills cobblestone under a piece down to the terrain, so pieces
+// on uneven ground do not float, and clears terrain above the floor.
+func (sg *StructureGenerator) foundation(w chunkWriter, p structurePiece, size int) {
+	for x := p.x; x < p.x+size; x++ {
+		for z := p.z; z < p.z+size; z++ {
+			if !w.contains(x, z) {
+				continue
+			}
+			ground := sg.heightAt(x, z)
+			for y := p.y - 1; y > ground && y >= p.y-maxBuildUp; y-- {
+				w.set(x, y, z, blockCobblestone<<4)
+			}
+			for y := p.y + 1; y <= min(ground, 255); y++ {
+				w.set(x, y, z, blockAir)
+			}
+		}
+	}
+}
+
+// chunkWriter writes blocks given in world coordinates into a chunk, skipping
+// any that fall outside it.
+type chunkWriter struct {
+	c      *ChunkData
+	ox, oz int // world coordinates of the chunk's minimum corner
+}
+
+func (w chunkWriter) contains(x, z int) bool {
+	return x >= w.ox && x < w.ox+16 && z >= w.oz && z < w.oz+16
+}
+
+// overlaps reports whether a size x size square at x, z reaches the chunk.
+func (w chunkWriter) overlaps(x, z, size int) bool {
+	return x < w.ox+16 && x+size > w.ox && z < w.oz+16 && z+size > w.oz
+}
+
+func (w chunkWriter) set(x, y, z int, state uint16) {
+	setIfInBounds(w.c, x-w.ox, y, z-w.oz, state)
+}
+
+// floorDiv divides rounding toward negative infinity.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
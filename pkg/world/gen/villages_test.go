@@ -0,0 +1,128 @@
+package gen
+
+import "testing"
+
+// findVillage returns the first village planned in the regions around the
+// origin.
+func findVillage(t *testing.T, sg *StructureGenerator) village {
+	t.Helper()
+	for rx := -4; rx <= 4; rx++ {
+		for rz := -4; rz <= 4; rz++ {
+			if v, ok := sg.villageIn(rx, rz); ok {
+				return v
+			}
+		}
+	}
+	t.Fatal("no village planned near the origin")
+	return village{}
+}
+
+func TestVillageGeneratedInExpectedChunk(t *testing.T) {
+	g := NewDefaultGenerator(42)
+	v := findVillage(t, g.villages)
+
+	if b := g.biomeGen.BiomeAt(v.well.x+wellSize/2, v.well.z+wellSize/2); b != biomePlains && b != biomeSavanna {
+		t.Errorf("village center biome = %d, want plains or savanna", b)
+	}
+
+	// The well's corner and the water inside it are in the chunk holding
+	// the corner.
+	cx, cz := floorDiv(v.well.x, 16), floorDiv(v.well.z, 16)
+	c := g.Generate(cx, cz)
+	lx, lz := v.well.x-cx*16, v.well.z-cz*16
+	if got := c.GetBlock(lx, v.well.y+1, lz); got != blockCobblestone<<4 {
+		t.Errorf("well rim at (%d, %d, %d) = %d, want cobblestone", v.well.x, v.well.y+1, v.well.z, got)
+	}
+
+	wx, wz := v.well.x+1, v.well.z+1
+	c = g.Generate(floorDiv(wx, 16), floorDiv(wz, 16))
+	if got := c.GetBlock(wx-floorDiv(wx, 16)*16, v.well.y, wz-floorDiv(wz, 16)*16); got != blockWater<<4 {
+		t.Errorf("well water at (%d, %d, %d) = %d, want water", wx, v.well.y, wz, got)
+	}
+}
+
+func TestVillageHousesHaveDoors(t *testing.T) {
+	// Seed 42 plans a village with two houses in region (-3, 3).
+	g := NewDefaultGenerator(42)
+	v, ok := g.villages.villageIn(-3, 3)
+	if !ok {
+		t.Fatal("no village planned in region (-3, 3)")
+	}
+	if len(v.houses) != 2 {
+		t.Fatalf("village has %d houses, want 2", len(v.houses))
+	}
+
+	for _, h := range v.houses {
+		doors := 0
+		for x := h.x; x < h.x+houseSize; x++ {
+			for z := h.z; z < h.z+houseSize; z++ {
+				cx, cz := floorDiv(x, 16), floorDiv(z, 16)
+				c := g.Generate(cx, cz)
+				if c.GetBlock(x-cx*16, h.y+1, z-cz*16)>>4 == blockOakDoor {
+					doors++
+				}
+			}
+		}
+		if doors != 1 {
+			t.Errorf("house at (%d, %d, %d) has %d doors, want 1", h.x, h.y, h.z, doors)
+		}
+	}
+}
+
+func TestVillageDeterministicAcrossChunks(t *testing.T) {
+	g := NewDefaultGenerator(42)
+	v := findVillage(t, g.villages)
+	cx, cz := floorDiv(v.well.x, 16), floorDiv(v.well.z, 16)
+
+	a := g.Generate(cx, cz)
+	// Generating a neighbor first must not change this chunk.
+	g2 := NewDefaultGenerator(42)
+	g2.Generate(cx+1, cz)
+	b := g2.Generate(cx, cz)
+	for i := range a.Sections {
+		if (a.Sections[i] == nil) != (b.Sections[i] == nil) {
+			t.Fatalf("section %d nil mismatch", i)
+		}
+		if a.Sections[i] != nil && a.Sections[i].Blocks != b.Sections[i].Blocks {
+			t.Fatalf("section %d differs", i)
+		}
+	}
+}
+
+func TestNoVillagesOutsidePlains(t *testing.T) {
+	heightAt := func(_, _ int) int { return 70 }
+	for _, biome := range []byte{biomeOcean, biomeForest, biomeDesert} {
+		sg := NewStructureGenerator(42, heightAt, func(_, _ int) byte { return biome })
+		for rx := -4; rx <= 4; rx++ {
+			for rz := -4; rz <= 4; rz++ {
+				if _, ok := sg.villageIn(rx, rz); ok {
+					t.Fatalf("biome %d: village planned in region (%d, %d)", biome, rx, rz)
+				}
+			}
+		}
+	}
+
+	sg := NewStructureGenerator(42, heightAt, func(_, _ int) byte { return biomePlains })
+	findVillage(t, sg)
+}
+
+func TestNoVillagesBelowSeaLevel(t *testing.T) {
+	sg := NewStructureGenerator(42, func(_, _ int) int { return seaLevel - 5 }, func(_, _ int) byte { return biomePlains })
+	for rx := -4; rx <= 4; rx++ {
+		for rz := -4; rz <= 4; rz++ {
+			if _, ok := sg.villageIn(rx, rz); ok {
+				t.Fatalf("village planned under water in region (%d, %d)", rx, rz)
+			}
+		}
+	}
+}
+
+func TestFloorDiv(t *testing.T) {
+	for _, tt := range []struct{ a, b, want int }{
+		{0, 32, 0}, {31, 32, 0}, {32, 32, 1}, {-1, 32, -1}, {-32, 32, -1}, {-33, 32, -2},
+	} {
+		if got := floorDiv(tt.a, tt.b); got != tt.want {
+			t.Errorf("floorDiv(%d, %d) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
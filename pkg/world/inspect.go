@@ -0,0 +1,67 @@
+package world
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-theft-craft/server/pkg/gamedata"
+	"github.com/go-theft-craft/server/pkg/world/gen"
+)
+
+// SetGameData attaches game data used to resolve block names.
+func (w *World) SetGameData(gd *gamedata.GameData) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.gameData = gd
+	w.resetLight()
+}
+
+// LoadedChunkPositions returns the positions of all generated chunks,
+// sorted by X then Z.
+func (w *World) LoadedChunkPositions() []gen.ChunkPos {
+	w.mu.RLock()
+	positions := make([]gen.ChunkPos, 0, len(w.chunks))
+	for pos := range w.chunks {
+		positions = append(positions, pos)
+	}
+	w.mu.RUnlock()
+
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].X != positions[j].X {
+			return positions[i].X < positions[j].X
+		}
+		return positions[i].Z < positions[j].Z
+	})
+	return positions
+}
+
+// OverrideCount returns the number of block overrides on top of generated terrain.
+func (w *World) OverrideCount() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return len(w.blocks)
+}
+
+// BlockStateName returns the name of the block at the given position, with
+// ":<meta>" appended for non-zero metadata. Without game data, or for unknown
+// block IDs, the numeric "<id>:<meta>" form is returned.
+func (w *World) BlockStateName(x, y, z int) string {
+	state := w.GetBlock(x, y, z)
+	id, meta := int(state>>4), int(state&0xF)
+
+	w.mu.RLock()
+	gd := w.gameData
+	w.mu.RUnlock()
+
+	if gd == nil || gd.Blocks == nil {
+		return fmt.Sprintf("%d:%d", id, meta)
+	}
+	b, ok := gd.Blocks.ByID(id)
+	if !ok {
+		return fmt.Sprintf("%d:%d", id, meta)
+	}
+	if meta != 0 {
+		return fmt.Sprintf("%s:%d", b.Name, meta)
+	}
+	return b.Name
+}
@@ -0,0 +1,71 @@
+package world
+
+import (
+	"testing"
+
+	pkt "github.com/go-theft-craft/server/pkg/gamedata/versions/pc_1_8"
+	"github.com/go-theft-craft/server/pkg/world/gen"
+)
+
+func TestLoadedChunkPositions(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	if got := len(w.LoadedChunkPositions()); got != 0 {
+		t.Fatalf("new world has %d loaded chunks, want 0", got)
+	}
+
+	w.GetOrGenerateChunk(1, 0)
+	w.GetOrGenerateChunk(-1, 2)
+	_ = w.GetBlock(5, 4, 5) // chunk (0,0)
+
+	got := w.LoadedChunkPositions()
+	want := []gen.ChunkPos{{X: -1, Z: 2}, {X: 0, Z: 0}, {X: 1, Z: 0}}
+	if len(got) != len(want) {
+		t.Fatalf("LoadedChunkPositions() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("LoadedChunkPositions()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestOverrideCount(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+
+	w.SetBlock(0, 10, 0, 1<<4)
+	w.SetBlock(1, 10, 0, 1<<4)
+	if got := w.OverrideCount(); got != 2 {
+		t.Errorf("OverrideCount() = %d, want 2", got)
+	}
+
+	// Restoring the base state removes the override.
+	w.SetBlock(0, 10, 0, 0)
+	if got := w.OverrideCount(); got != 1 {
+		t.Errorf("OverrideCount() after restore = %d, want 1", got)
+	}
+	if got := len(w.GetBlockOverrides()); got != w.OverrideCount() {
+		t.Errorf("GetBlockOverrides() has %d entries, OverrideCount() = %d", got, w.OverrideCount())
+	}
+}
+
+func TestBlockStateName(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+
+	// Without game data, names fall back to numeric form.
+	if got := w.BlockStateName(0, 4, 0); got != "2:0" {
+		t.Errorf("BlockStateName without game data = %q, want %q", got, "2:0")
+	}
+
+	w.SetGameData(pkt.New())
+	if got := w.BlockStateName(0, 4, 0); got != "grass" {
+		t.Errorf("BlockStateName(0,4,0) = %q, want %q", got, "grass")
+	}
+	if got := w.BlockStateName(0, 10, 0); got != "air" {
+		t.Errorf("BlockStateName(0,10,0) = %q, want %q", got, "air")
+	}
+
+	w.SetBlock(0, 10, 0, 35<<4|14) // red wool
+	if got := w.BlockStateName(0, 10, 0); got != "wool:14" {
+		t.Errorf("BlockStateName after SetBlock = %q, want %q", got, "wool:14")
+	}
+}
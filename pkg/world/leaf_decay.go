@@ -0,0 +1,67 @@
+package world
+
+// Block IDs involved in leaf decay.
+const (
+	blockLog     = 17
+	blockLeaves  = 18
+	blockLeaves2 = 161
+	blockLog2    = 162
+)
+
+// leafDecayRadius is how far, in blocks along each axis, a leaf looks for a
+// log that keeps it alive.
+const leafDecayRadius = 4
+
+// leafNoDecay is the metadata bit set on leaves that never decay.
+const leafNoDecay = 0x4
+
+func isLeaves(state int32) bool {
+	id := state >> 4
+	return id == blockLeaves || id == blockLeaves2
+}
+
+// IsLog reports whether a block state is a log of either wood family.
+func IsLog(state int32) bool {
+	id := state >> 4
+	return id == blockLog || id == blockLog2
+}
+
+// ScheduleLeafDecay queues a decay check for every leaf within
+// leafDecayRadius of (x, y, z). It is called after a log there is removed;
+// leaves left without a log nearby are removed by TickScheduled.
+func (w *World) ScheduleLeafDecay(x, y, z int) {
+	var leaves []BlockPos
+	for dx := -leafDecayRadius; dx <= leafDecayRadius; dx++ {
+		for dy := -leafDecayRadius; dy <= leafDecayRadius; dy++ {
+			for dz := -leafDecayRadius; dz <= leafDecayRadius; dz++ {
+				if isLeaves(w.GetBlock(x+dx, y+dy, z+dz)) {
+					leaves = append(leaves, BlockPos{X: x + dx, Y: y + dy, Z: z + dz})
+				}
+			}
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, pos := range leaves {
+		w.requestUpdate(pos)
+	}
+}
+
+// leafShouldDecay reports whether the leaf at pos has no log within
+// leafDecayRadius. Leaves with the no-decay bit never decay.
+func (w *World) leafShouldDecay(pos BlockPos, state int32) bool {
+	if state&leafNoDecay != 0 {
+		return false
+	}
+	for dx := -leafDecayRadius; dx <= leafDecayRadius; dx++ {
+		for dy := -leafDecayRadius; dy <= leafDecayRadius; dy++ {
+			for dz := -leafDecayRadius; dz <= leafDecayRadius; dz++ {
+				if IsLog(w.GetBlock(pos.X+dx, pos.Y+dy, pos.Z+dz)) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package world
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/go-theft-craft/server/pkg/world/gen"
+)
+
+// runScheduled ticks the world until no scheduled updates remain and returns
+// every block they removed.
+func runScheduled(t *testing.T, w *World, rng *rand.Rand) []BlockBreak {
+	t.Helper()
+	var broken []BlockBreak
+	for i := 0; i <= maxScheduledDelay+1; i++ {
+		w.Tick()
+		broken = append(broken, w.TickScheduled(rng)...)
+	}
+	if n := w.ScheduledCount(); n != 0 {
+		t.Fatalf("ScheduledCount = %d after max delay, want 0", n)
+	}
+	return broken
+}
+
+func TestIsolatedLeafDecays(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	w.SetBlock(0, 40, 0, blockLog<<4)
+	w.SetBlock(1, 40, 0, blockLeaves<<4|2)
+
+	w.SetBlock(0, 40, 0, 0)
+	w.ScheduleLeafDecay(0, 40, 0)
+	if n := w.ScheduledCount(); n != 1 {
+		t.Fatalf("ScheduledCount = %d, want 1", n)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	w.Tick()
+	if broken := w.TickScheduled(rng); len(broken) != 0 {
+		t.Fatalf("leaf decayed without delay: %v", broken)
+	}
+
+	broken := runScheduled(t, w, rng)
+	want := BlockBreak{Pos: BlockPos{X: 1, Y: 40, Z: 0}, State: blockLeaves<<4 | 2}
+	if len(broken) != 1 || broken[0] != want {
+		t.Fatalf("broken = %v, want [%v]", broken, want)
+	}
+	if got := w.GetBlock(1, 40, 0); got != 0 {
+		t.Errorf("leaf block = %d after decay, want air", got)
+	}
+}
+
+func TestLeafNearLogSurvives(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	w.SetBlock(0, 40, 0, blockLog<<4)
+	w.SetBlock(4, 40, 0, blockLeaves<<4)
+	w.SetBlock(-3, 40, 0, blockLeaves<<4|leafNoDecay)
+
+	// A log elsewhere in range keeps the first leaf; the second never decays.
+	w.ScheduleLeafDecay(1, 40, 0)
+	if n := w.ScheduledCount(); n != 2 {
+		t.Fatalf("ScheduledCount = %d, want 2", n)
+	}
+	if broken := runScheduled(t, w, rand.New(rand.NewSource(1))); len(broken) != 0 {
+		t.Fatalf("broken = %v, want none", broken)
+	}
+}
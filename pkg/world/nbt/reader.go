@@ -0,0 +1,218 @@
+package nbt
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Limits that keep a malformed or hostile file from exhausting memory.
+const (
+	maxArrayLen = 1 << 24 // elements in a byte array, int array or list
+	maxDepth    = 512     // nesting of compounds and lists
+)
+
+// ErrInvalid is returned for data that is not well-formed NBT.
+var ErrInvalid = errors.New("invalid nbt")
+
+// Read decodes a named root compound and returns its name and contents.
+// Tag values are decoded to byte, int16, int32, int64, float32, float64,
+// []byte, string, []any, map[string]any and []int32.
+func Read(r io.Reader) (string, map[string]any, error) {
+	return read(&decoder{r: r, maxLen: maxArrayLen})
+}
+
+// DecodeCompound decodes a root compound and returns its contents, ignoring
+// the root name. Item tags and chunk data use an unnamed root.
+func DecodeCompound(r io.Reader) (map[string]any, error) {
+	_, root, err := Read(r)
+	return root, err
+}
+
+// DecodeCompoundLimit is like DecodeCompound but rejects any array or list
+// longer than maxLen elements. Use it for NBT received from clients.
+func DecodeCompoundLimit(r io.Reader, maxLen int) (map[string]any, error) {
+	_, root, err := read(&decoder{r: r, maxLen: min(maxLen, maxArrayLen)})
+	return root, err
+}
+
+func read(d *decoder) (string, map[string]any, error) {
+	tagType, err := d.byte()
+	if err != nil {
+		return "", nil, err
+	}
+	if tagType != TagCompound {
+		return "", nil, fmt.Errorf("%w: root tag %d is not a compound", ErrInvalid, tagType)
+	}
+	name, err := d.string()
+	if err != nil {
+		return "", nil, err
+	}
+	v, err := d.payload(TagCompound, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	return name, v.(map[string]any), nil
+}
+
+type decoder struct {
+	r      io.Reader
+	maxLen int // longest array or list accepted
+	buf    [8]byte
+}
+
+func (d *decoder) read(n int) ([]byte, error) {
+	if _, err := io.ReadFull(d.r, d.buf[:n]); err != nil {
+		return nil, fmt.Errorf("read nbt: %w", err)
+	}
+	return d.buf[:n], nil
+}
+
+func (d *decoder) byte() (byte, error) {
+	b, err := d.read(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (d *decoder) int32() (int32, error) {
+	b, err := d.read(4)
+	if err != nil {
+		return 0, err
+	}
+	return int32(binary.BigEndian.Uint32(b)), nil
+}
+
+func (d *decoder) length() (int, error) {
+	n, err := d.int32()
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 || int(n) > d.maxLen {
+		return 0, fmt.Errorf("%w: length %d out of range", ErrInvalid, n)
+	}
+	return int(n), nil
+}
+
+func (d *decoder) string() (string, error) {
+	b, err := d.read(2)
+	if err != nil {
+		return "", err
+	}
+	s := make([]byte, binary.BigEndian.Uint16(b))
+	if _, err := io.ReadFull(d.r, s); err != nil {
+		return "", fmt.Errorf("read nbt string: %w", err)
+	}
+	return string(s), nil
+}
+
+func (d *decoder) payload(tagType byte, depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("%w: nesting deeper than %d", ErrInvalid, maxDepth)
+	}
+
+	switch tagType {
+	case TagByte:
+		return d.byte()
+	case TagShort:
+		b, err := d.read(2)
+		if err != nil {
+			return nil, err
+		}
+		return int16(binary.BigEndian.Uint16(b)), nil
+	case TagInt:
+		return d.int32()
+	case TagLong:
+		b, err := d.read(8)
+		if err != nil {
+			return nil, err
+		}
+		return int64(binary.BigEndian.Uint64(b)), nil
+	case TagFloat:
+		b, err := d.read(4)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(b)), nil
+	case TagDouble:
+		b, err := d.read(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case TagByteArray:
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		// Grow with the data actually read rather than trusting the
+		// declared length, so a short input cannot force a large allocation.
+		v, err := io.ReadAll(io.LimitReader(d.r, int64(n)))
+		if err != nil {
+			return nil, fmt.Errorf("read nbt byte array: %w", err)
+		}
+		if len(v) < n {
+			return nil, fmt.Errorf("read nbt byte array: %w", io.ErrUnexpectedEOF)
+		}
+		return v, nil
+	case TagString:
+		return d.string()
+	case TagList:
+		elemType, err := d.byte()
+		if err != nil {
+			return nil, err
+		}
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		list := make([]any, 0, min(n, 1024))
+		for i := 0; i < n; i++ {
+			v, err := d.payload(elemType, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	case TagCompound:
+		m := make(map[string]any)
+		for {
+			childType, err := d.byte()
+			if err != nil {
+				return nil, err
+			}
+			if childType == TagEnd {
+				return m, nil
+			}
+			name, err := d.string()
+			if err != nil {
+				return nil, err
+			}
+			v, err := d.payload(childType, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			m[name] = v
+		}
+	case TagIntArray:
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		v := make([]int32, 0, min(n, 1024))
+		for i := 0; i < n; i++ {
+			x, err := d.int32()
+			if err != nil {
+				return nil, err
+			}
+			v = append(v, x)
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown tag type %d", ErrInvalid, tagType)
+	}
+}
@@ -0,0 +1,211 @@
+package nbt
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestReadRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.BeginCompound("root")
+	w.WriteTagByte("b", 7)
+	w.WriteShort("s", -3)
+	w.WriteInt("i", 1<<20)
+	w.WriteLong("l", -1<<40)
+	w.WriteFloat("f", 1.5)
+	w.WriteDouble("d", -2.25)
+	w.WriteByteArray("ba", []byte{1, 2, 3})
+	w.WriteString("str", "hello")
+	w.WriteIntArray("ia", []int32{4, -5})
+	w.BeginList("list", TagInt, 2)
+	w.putInt32(10)
+	w.putInt32(11)
+	w.BeginCompound("nested")
+	w.WriteString("name", "inner")
+	w.EndCompound()
+	w.EndCompound()
+	if err := w.Err(); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	name, root, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if name != "root" {
+		t.Errorf("root name = %q, want %q", name, "root")
+	}
+	want := map[string]any{
+		"b":      byte(7),
+		"s":      int16(-3),
+		"i":      int32(1 << 20),
+		"l":      int64(-1 << 40),
+		"f":      float32(1.5),
+		"d":      float64(-2.25),
+		"ba":     []byte{1, 2, 3},
+		"str":    "hello",
+		"ia":     []int32{4, -5},
+		"list":   []any{int32(10), int32(11)},
+		"nested": map[string]any{"name": "inner"},
+	}
+	if !reflect.DeepEqual(root, want) {
+		t.Errorf("Read = %#v\nwant %#v", root, want)
+	}
+}
+
+func TestReadRejectsInvalid(t *testing.T) {
+	tests := map[string][]byte{
+		"non-compound root": {TagInt, 0, 0, 0, 0, 0, 1},
+		"unknown tag":       {TagCompound, 0, 0, 42, 0, 0},
+		"negative length":   {TagCompound, 0, 0, TagByteArray, 0, 1, 'a', 0xFF, 0xFF, 0xFF, 0xFF},
+		"truncated":         {TagCompound, 0, 0, TagInt, 0, 1, 'a', 0, 0},
+	}
+	for name, data := range tests {
+		if _, _, err := Read(bytes.NewReader(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		} else if name != "truncated" && !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: err = %v, want ErrInvalid", name, err)
+		}
+	}
+}
+
+func TestReadShortInputDoesNotPreallocate(t *testing.T) {
+	// A byte array claiming the maximum length, followed by only a few bytes.
+	data := []byte{TagCompound, 0, 0, TagByteArray, 0, 1, 'a', 0x01, 0x00, 0x00, 0x00, 1, 2, 3}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, _, err := Read(bytes.NewReader(data))
+	runtime.ReadMemStats(&after)
+
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Read error = %v, want io.ErrUnexpectedEOF", err)
+	}
+	if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 1<<20 {
+		t.Errorf("Read allocated %d bytes for a %d-byte input", alloc, len(data))
+	}
+}
+
+func TestDecodeCompoundLimit(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.BeginCompound("")
+	w.WriteIntArray("ia", make([]int32, 5))
+	w.EndCompound()
+	data := buf.Bytes()
+
+	if _, err := DecodeCompoundLimit(bytes.NewReader(data), 5); err != nil {
+		t.Errorf("DecodeCompoundLimit at the limit: %v", err)
+	}
+	if _, err := DecodeCompoundLimit(bytes.NewReader(data), 4); !errors.Is(err, ErrInvalid) {
+		t.Errorf("DecodeCompoundLimit over the limit: err = %v, want ErrInvalid", err)
+	}
+}
+
+// decodeWritten wraps the tags written by fn in an unnamed root compound and
+// decodes them.
+func decodeWritten(t *testing.T, fn func(w *Writer)) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.BeginCompound("")
+	fn(w)
+	w.EndCompound()
+	if err := w.Err(); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	root, err := DecodeCompound(&buf)
+	if err != nil {
+		t.Fatalf("DecodeCompound: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("%d bytes left after decoding", buf.Len())
+	}
+	return root
+}
+
+func TestDecodeRoundTrip(t *testing.T) {
+	tests := map[string]struct {
+		write func(w *Writer)
+		want  map[string]any
+	}{
+		"byte": {
+			func(w *Writer) { w.WriteTagByte("test", 42) },
+			map[string]any{"test": byte(42)},
+		},
+		"int": {
+			func(w *Writer) { w.WriteInt("x", 12345) },
+			map[string]any{"x": int32(12345)},
+		},
+		"byte array": {
+			func(w *Writer) { w.WriteByteArray("ba", []byte{1, 2, 3}) },
+			map[string]any{"ba": []byte{1, 2, 3}},
+		},
+		"string": {
+			func(w *Writer) { w.WriteString("name", "Steve") },
+			map[string]any{"name": "Steve"},
+		},
+		"compound": {
+			func(w *Writer) {
+				w.BeginCompound("c")
+				w.WriteTagByte("a", 1)
+				w.EndCompound()
+			},
+			map[string]any{"c": map[string]any{"a": byte(1)}},
+		},
+		"int array": {
+			func(w *Writer) { w.WriteIntArray("ia", []int32{1, -2, 3}) },
+			map[string]any{"ia": []int32{1, -2, 3}},
+		},
+		"list of compounds": {
+			func(w *Writer) {
+				w.BeginList("ench", TagCompound, 2)
+				w.WriteShort("id", 16)
+				w.WriteShort("lvl", 5)
+				w.EndCompound()
+				w.WriteShort("id", 34)
+				w.WriteShort("lvl", 3)
+				w.EndCompound()
+			},
+			map[string]any{"ench": []any{
+				map[string]any{"id": int16(16), "lvl": int16(5)},
+				map[string]any{"id": int16(34), "lvl": int16(3)},
+			}},
+		},
+		"empty list": {
+			func(w *Writer) { w.BeginList("items", TagCompound, 0) },
+			map[string]any{"items": []any{}},
+		},
+		"long": {
+			func(w *Writer) { w.WriteLong("L", 0x123456789ABCDEF0) },
+			map[string]any{"L": int64(0x123456789ABCDEF0)},
+		},
+		"nested compound": {
+			func(w *Writer) {
+				w.BeginCompound("Level")
+				w.WriteInt("xPos", 3)
+				w.BeginCompound("inner")
+				w.BeginList("empty", TagEnd, 0)
+				w.EndCompound()
+				w.EndCompound()
+			},
+			map[string]any{"Level": map[string]any{
+				"xPos":  int32(3),
+				"inner": map[string]any{"empty": []any{}},
+			}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := decodeWritten(t, tt.write); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decoded %#v\nwant %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -77,7 +77,8 @@ func (w *Writer) writeTagHeader(tagType byte, name string) {
 	}
 }
 
-// BeginCompound writes a compound tag header. Use name="" for list elements.
+// BeginCompound writes a compound tag header; use name="" for the root.
+// Compounds inside a list have no header: write their tags, then EndCompound.
 func (w *Writer) BeginCompound(name string) {
 	w.writeTagHeader(TagCompound, name)
 }
@@ -0,0 +1,71 @@
+package world
+
+import (
+	"math/rand"
+
+	"github.com/go-theft-craft/server/pkg/world/gen"
+)
+
+// DefaultRandomTickSpeed is the vanilla number of random ticks per chunk
+// section per game tick.
+const DefaultRandomTickSpeed = 3
+
+// cropGrowthChance is the 1-in-N chance that a randomly ticked crop advances
+// one growth stage.
+const cropGrowthChance = 4
+
+// Crop block IDs that grow through metadata stages 0-7.
+const (
+	blockWheat    = 59
+	blockCarrots  = 141
+	blockPotatoes = 142
+	maxCropStage  = 7
+)
+
+// RandomTick picks speed random blocks in every section of each given chunk
+// and applies their random-tick behavior, returning the resulting block
+// changes. Chunks that have not been generated are skipped. A speed of zero
+// disables random ticks.
+func (w *World) RandomTick(chunks []gen.ChunkPos, rng *rand.Rand, speed int) []BlockUpdate {
+	if speed <= 0 {
+		return nil
+	}
+
+	var updates []BlockUpdate
+	for _, cp := range chunks {
+		w.mu.RLock()
+		_, loaded := w.chunks[cp]
+		w.mu.RUnlock()
+		if !loaded {
+			continue
+		}
+
+		for sec := 0; sec < 16; sec++ {
+			for i := 0; i < speed; i++ {
+				x := cp.X*16 + rng.Intn(16)
+				y := sec*16 + rng.Intn(16)
+				z := cp.Z*16 + rng.Intn(16)
+				if u, ok := w.randomTickBlock(x, y, z, rng); ok {
+					updates = append(updates, u)
+				}
+			}
+		}
+	}
+	return updates
+}
+
+// randomTickBlock applies the random-tick behavior of the block at (x, y, z).
+func (w *World) randomTickBlock(x, y, z int, rng *rand.Rand) (BlockUpdate, bool) {
+	state := w.GetBlock(x, y, z)
+	switch state >> 4 {
+	case blockWheat, blockCarrots, blockPotatoes:
+		stage := state & 0xF
+		if stage >= maxCropStage || rng.Intn(cropGrowthChance) != 0 {
+			return BlockUpdate{}, false
+		}
+		next := state + 1
+		w.SetBlock(x, y, z, next)
+		return BlockUpdate{Pos: BlockPos{x, y, z}, State: next}, true
+	}
+	return BlockUpdate{}, false
+}
@@ -0,0 +1,68 @@
+package world
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/go-theft-craft/server/pkg/world/gen"
+)
+
+// cropGrowth plants a full layer of wheat in chunk (0,0), runs ticks random
+// ticks at the given speed, and returns the total number of growth stages gained.
+func cropGrowth(t *testing.T, speed, ticks int) int {
+	t.Helper()
+	w := NewWorld(gen.NewFlatGenerator(0))
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			w.SetBlock(x, 5, z, blockWheat<<4)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	chunks := []gen.ChunkPos{{X: 0, Z: 0}}
+	for i := 0; i < ticks; i++ {
+		w.RandomTick(chunks, rng, speed)
+	}
+
+	total := 0
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			state := w.GetBlock(x, 5, z)
+			if state>>4 != blockWheat {
+				t.Fatalf("block at (%d,5,%d) = %d, want wheat", x, z, state)
+			}
+			total += int(state & 0xF)
+		}
+	}
+	return total
+}
+
+func TestRandomTickSpeedZeroHaltsGrowth(t *testing.T) {
+	if got := cropGrowth(t, 0, 1000); got != 0 {
+		t.Errorf("growth with randomTickSpeed=0 = %d, want 0", got)
+	}
+}
+
+func TestRandomTickSpeedAcceleratesGrowth(t *testing.T) {
+	normal := cropGrowth(t, DefaultRandomTickSpeed, 1000)
+	fast := cropGrowth(t, 30, 1000)
+
+	if normal == 0 {
+		t.Fatal("expected crops to grow at the default speed")
+	}
+	if fast <= normal {
+		t.Errorf("growth at speed 30 = %d, want more than default (%d)", fast, normal)
+	}
+}
+
+func TestRandomTickStopsAtMaxStage(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	w.SetBlock(0, 5, 0, blockWheat<<4|maxCropStage)
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		if _, ok := w.randomTickBlock(0, 5, 0, rng); ok {
+			t.Fatal("fully grown wheat should not advance")
+		}
+	}
+}
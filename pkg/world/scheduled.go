@@ -0,0 +1,98 @@
+package world
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+)
+
+// Bounds of the random delay, in ticks, before a scheduled block update runs.
+const (
+	minScheduledDelay = 20
+	maxScheduledDelay = 100
+)
+
+// BlockBreak describes a block removed by world simulation.
+type BlockBreak struct {
+	Pos   BlockPos
+	State int32 // state before removal
+}
+
+// requestUpdate queues the block at pos for a scheduled update. The delay is
+// drawn when the request is picked up by TickScheduled. Callers must hold
+// w.mu.
+func (w *World) requestUpdate(pos BlockPos) {
+	if _, ok := w.scheduled[pos]; ok {
+		return
+	}
+	w.requested[pos] = struct{}{}
+}
+
+// TickScheduled gives newly requested block updates a random delay and runs
+// the ones that are due, returning the blocks they removed. It is called once
+// per game tick after Tick.
+func (w *World) TickScheduled(rng *rand.Rand) []BlockBreak {
+	w.mu.Lock()
+	for _, pos := range sortedPositions(w.requested) {
+		w.scheduled[pos] = w.age + minScheduledDelay + int64(rng.Intn(maxScheduledDelay-minScheduledDelay+1))
+	}
+	clear(w.requested)
+
+	var due []BlockPos
+	for pos, at := range w.scheduled {
+		if at <= w.age {
+			due = append(due, pos)
+		}
+	}
+	for _, pos := range due {
+		delete(w.scheduled, pos)
+	}
+	w.mu.Unlock()
+
+	slices.SortFunc(due, compareBlockPos)
+	var broken []BlockBreak
+	for _, pos := range due {
+		if b, ok := w.runScheduledUpdate(pos); ok {
+			broken = append(broken, b)
+		}
+	}
+	return broken
+}
+
+// runScheduledUpdate applies the scheduled-update behavior of the block at pos.
+func (w *World) runScheduledUpdate(pos BlockPos) (BlockBreak, bool) {
+	state := w.GetBlock(pos.X, pos.Y, pos.Z)
+	if isLeaves(state) && w.leafShouldDecay(pos, state) {
+		w.SetBlock(pos.X, pos.Y, pos.Z, 0)
+		return BlockBreak{Pos: pos, State: state}, true
+	}
+	return BlockBreak{}, false
+}
+
+// ScheduledCount returns the number of block updates waiting to run.
+func (w *World) ScheduledCount() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return len(w.scheduled) + len(w.requested)
+}
+
+// sortedPositions returns the keys of set in a stable order, so updates drawn
+// from a seeded random source are reproducible.
+func sortedPositions(set map[BlockPos]struct{}) []BlockPos {
+	positions := make([]BlockPos, 0, len(set))
+	for pos := range set {
+		positions = append(positions, pos)
+	}
+	slices.SortFunc(positions, compareBlockPos)
+	return positions
+}
+
+func compareBlockPos(a, b BlockPos) int {
+	if c := cmp.Compare(a.X, b.X); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Y, b.Y); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Z, b.Z)
+}
@@ -0,0 +1,139 @@
+package world
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/go-theft-craft/server/pkg/world/nbt"
+)
+
+// Schematic is a cuboid of block states that can be saved and pasted
+// elsewhere. Blocks is indexed (y*Length+z)*Width+x, as in the MCEdit
+// .schematic format.
+type Schematic struct {
+	Width, Height, Length int
+	Blocks                []int32 // block state IDs (id<<4 | metadata)
+}
+
+// Volume returns the number of blocks in the schematic.
+func (s *Schematic) Volume() int {
+	return s.Width * s.Height * s.Length
+}
+
+func (s *Schematic) index(x, y, z int) int {
+	return (y*s.Length+z)*s.Width + x
+}
+
+// ExportSchematic copies the blocks of the cuboid between two corners
+// (inclusive, in any order) into a schematic.
+func (w *World) ExportSchematic(x1, y1, z1, x2, y2, z2 int) *Schematic {
+	minX, maxX := min(x1, x2), max(x1, x2)
+	minY, maxY := min(y1, y2), max(y1, y2)
+	minZ, maxZ := min(z1, z2), max(z1, z2)
+
+	s := &Schematic{Width: maxX - minX + 1, Height: maxY - minY + 1, Length: maxZ - minZ + 1}
+	s.Blocks = make([]int32, s.Volume())
+	for y := 0; y < s.Height; y++ {
+		for z := 0; z < s.Length; z++ {
+			for x := 0; x < s.Width; x++ {
+				s.Blocks[s.index(x, y, z)] = w.GetBlock(minX+x, minY+y, minZ+z)
+			}
+		}
+	}
+	return s
+}
+
+// PasteSchematic places the schematic with its minimum corner at (x, y, z),
+// skipping blocks outside the world border or outside the heights 0 to
+// height-1. It returns the positions whose block state changed.
+func (w *World) PasteSchematic(s *Schematic, x, y, z, height int) []BlockPos {
+	border := w.Border()
+	var changed []BlockPos
+	for dy := 0; dy < s.Height; dy++ {
+		by := y + dy
+		if by < 0 || by >= height {
+			continue
+		}
+		for dz := 0; dz < s.Length; dz++ {
+			for dx := 0; dx < s.Width; dx++ {
+				bx, bz := x+dx, z+dz
+				if !border.ContainsBlock(bx, bz) {
+					continue
+				}
+				state := s.Blocks[s.index(dx, dy, dz)]
+				if w.GetBlock(bx, by, bz) == state {
+					continue
+				}
+				w.SetBlock(bx, by, bz, state)
+				changed = append(changed, BlockPos{bx, by, bz})
+			}
+		}
+	}
+	return changed
+}
+
+// Encode writes the schematic as gzip-compressed NBT in the MCEdit format.
+func (s *Schematic) Encode(out io.Writer) error {
+	ids := make([]byte, len(s.Blocks))
+	data := make([]byte, len(s.Blocks))
+	for i, state := range s.Blocks {
+		ids[i] = byte(state >> 4)
+		data[i] = byte(state & 0xF)
+	}
+
+	zw := gzip.NewWriter(out)
+	w := nbt.NewWriter(zw)
+	w.BeginCompound("Schematic")
+	w.WriteShort("Width", int16(s.Width))
+	w.WriteShort("Height", int16(s.Height))
+	w.WriteShort("Length", int16(s.Length))
+	w.WriteString("Materials", "Alpha")
+	w.WriteByteArray("Blocks", ids)
+	w.WriteByteArray("Data", data)
+	w.BeginList("Entities", nbt.TagCompound, 0)
+	w.BeginList("TileEntities", nbt.TagCompound, 0)
+	w.EndCompound()
+	if err := w.Err(); err != nil {
+		return fmt.Errorf("write schematic: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("compress schematic: %w", err)
+	}
+	return nil
+}
+
+// DecodeSchematic reads a gzip-compressed MCEdit schematic.
+func DecodeSchematic(r io.Reader) (*Schematic, error) {
+	zr, err := gzip.NewReader(bufio.NewReader(r))
+	if err != nil {
+		return nil, fmt.Errorf("open schematic: %w", err)
+	}
+	defer zr.Close()
+
+	_, root, err := nbt.Read(zr)
+	if err != nil {
+		return nil, fmt.Errorf("read schematic: %w", err)
+	}
+
+	width, okW := root["Width"].(int16)
+	height, okH := root["Height"].(int16)
+	length, okL := root["Length"].(int16)
+	ids, okB := root["Blocks"].([]byte)
+	data, okD := root["Data"].([]byte)
+	if !okW || !okH || !okL || !okB || !okD {
+		return nil, fmt.Errorf("%w: schematic is missing required tags", nbt.ErrInvalid)
+	}
+	s := &Schematic{Width: int(width), Height: int(height), Length: int(length)}
+	if s.Width <= 0 || s.Height <= 0 || s.Length <= 0 || len(ids) != s.Volume() || len(data) != s.Volume() {
+		return nil, fmt.Errorf("%w: schematic size %dx%dx%d does not match its %d blocks",
+			nbt.ErrInvalid, s.Width, s.Height, s.Length, len(ids))
+	}
+
+	s.Blocks = make([]int32, s.Volume())
+	for i := range s.Blocks {
+		s.Blocks[i] = int32(ids[i])<<4 | int32(data[i]&0xF)
+	}
+	return s, nil
+}
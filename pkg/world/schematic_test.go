@@ -0,0 +1,76 @@
+package world
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-theft-craft/server/pkg/world/gen"
+)
+
+func TestSchematicExportEncodePaste(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	w.SetBlock(2, 10, 3, 1<<4)    // stone
+	w.SetBlock(3, 11, 4, 35<<4|5) // lime wool
+	w.SetBlock(4, 12, 5, 4<<4)    // cobblestone
+
+	// Corners in reverse order are normalized.
+	sch := w.ExportSchematic(4, 12, 5, 2, 10, 3)
+	if sch.Width != 3 || sch.Height != 3 || sch.Length != 3 {
+		t.Fatalf("size = %dx%dx%d, want 3x3x3", sch.Width, sch.Height, sch.Length)
+	}
+
+	var buf bytes.Buffer
+	if err := sch.Encode(&buf); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	decoded, err := DecodeSchematic(&buf)
+	if err != nil {
+		t.Fatalf("DecodeSchematic: %v", err)
+	}
+
+	changed := w.PasteSchematic(decoded, 100, 20, 100, 256)
+	if len(changed) != 3 {
+		t.Errorf("PasteSchematic changed %d blocks, want 3", len(changed))
+	}
+	for y := 0; y < 3; y++ {
+		for z := 0; z < 3; z++ {
+			for x := 0; x < 3; x++ {
+				want := w.GetBlock(2+x, 10+y, 3+z)
+				if got := w.GetBlock(100+x, 20+y, 100+z); got != want {
+					t.Errorf("block (%d,%d,%d) = %d, want %d", x, y, z, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestPasteSchematic_ClipsToBorderAndHeight(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	b := DefaultBorder()
+	b.Diameter = 20 // -10 to 10
+	w.SetBorder(b)
+
+	sch := &Schematic{Width: 2, Height: 2, Length: 1, Blocks: []int32{1 << 4, 1 << 4, 1 << 4, 1 << 4}}
+	changed := w.PasteSchematic(sch, 9, 99, 0, 100)
+	if len(changed) != 1 || changed[0] != (BlockPos{9, 99, 0}) {
+		t.Errorf("PasteSchematic changed %v, want only (9, 99, 0)", changed)
+	}
+	if got := w.GetBlock(10, 99, 0); got != 0 {
+		t.Errorf("block outside the border = %d, want air", got)
+	}
+	if got := w.GetBlock(9, 100, 0); got != 0 {
+		t.Errorf("block above the height limit = %d, want air", got)
+	}
+}
+
+func TestDecodeSchematic_RejectsMismatchedSize(t *testing.T) {
+	// Three columns wide but only two blocks of data.
+	sch := &Schematic{Width: 3, Height: 1, Length: 1, Blocks: []int32{1 << 4, 1 << 4}}
+	var buf bytes.Buffer
+	if err := sch.Encode(&buf); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if _, err := DecodeSchematic(&buf); err == nil {
+		t.Error("expected an error for a schematic whose size does not match its blocks")
+	}
+}
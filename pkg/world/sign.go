@@ -0,0 +1,88 @@
+package world
+
+import "github.com/go-theft-craft/server/pkg/world/gen"
+
+// Block IDs of signs.
+const (
+	BlockStandingSign = 63
+	BlockWallSign     = 68
+)
+
+// IsSign reports whether a block state is a standing or wall sign.
+func IsSign(state int32) bool {
+	id := state >> 4
+	return id == BlockStandingSign || id == BlockWallSign
+}
+
+// Sign returns the text of the sign at pos and whether it has any.
+func (w *World) Sign(pos BlockPos) ([4]string, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	lines, ok := w.signs[signChunk(pos)][pos]
+	return lines, ok
+}
+
+// SetSign stores the text of the sign at pos.
+func (w *World) SetSign(pos BlockPos, lines [4]string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.storeSign(pos, lines)
+}
+
+// storeSign records the text of the sign at pos. The caller holds mu.
+func (w *World) storeSign(pos BlockPos, lines [4]string) {
+	cpos := signChunk(pos)
+	if w.signs[cpos] == nil {
+		w.signs[cpos] = make(map[BlockPos][4]string)
+	}
+	w.signs[cpos][pos] = lines
+}
+
+// deleteSign removes the sign at pos. The caller holds mu.
+func (w *World) deleteSign(pos BlockPos) {
+	cpos := signChunk(pos)
+	delete(w.signs[cpos], pos)
+	if len(w.signs[cpos]) == 0 {
+		delete(w.signs, cpos)
+	}
+}
+
+// signChunk returns the chunk holding pos.
+func signChunk(pos BlockPos) gen.ChunkPos {
+	return gen.ChunkPos{X: pos.X >> 4, Z: pos.Z >> 4}
+}
+
+// SignsInChunk returns the text of every sign in chunk (cx, cz).
+func (w *World) SignsInChunk(cx, cz int) map[BlockPos][4]string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	signs := w.signs[gen.ChunkPos{X: cx, Z: cz}]
+	result := make(map[BlockPos][4]string, len(signs))
+	for pos, lines := range signs {
+		result[pos] = lines
+	}
+	return result
+}
+
+// Signs returns the text of every sign in the world (used for persistence).
+func (w *World) Signs() map[BlockPos][4]string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	result := make(map[BlockPos][4]string)
+	for _, signs := range w.signs {
+		for pos, lines := range signs {
+			result[pos] = lines
+		}
+	}
+	return result
+}
+
+// SetSigns stores the text of each sign in signs (used when loading from
+// storage).
+func (w *World) SetSigns(signs map[BlockPos][4]string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for pos, lines := range signs {
+		w.storeSign(pos, lines)
+	}
+}
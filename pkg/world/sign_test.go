@@ -0,0 +1,57 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/go-theft-craft/server/pkg/world/gen"
+)
+
+func TestSign_SetAndGet(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	pos := BlockPos{X: 20, Y: 5, Z: -3}
+	lines := [4]string{"Hello", "", "world", "!"}
+
+	if _, ok := w.Sign(pos); ok {
+		t.Fatal("new world should have no sign text")
+	}
+	w.SetBlock(pos.X, pos.Y, pos.Z, BlockStandingSign<<4)
+	w.SetSign(pos, lines)
+
+	if got, ok := w.Sign(pos); !ok || got != lines {
+		t.Errorf("Sign = %q (%v), want %q", got, ok, lines)
+	}
+	signs := w.SignsInChunk(1, -1)
+	if len(signs) != 1 || signs[pos] != lines {
+		t.Errorf("SignsInChunk(1, -1) = %v, want the sign", signs)
+	}
+	if signs := w.SignsInChunk(0, 0); len(signs) != 0 {
+		t.Errorf("SignsInChunk(0, 0) = %v, want none", signs)
+	}
+}
+
+func TestSign_RemovedWithBlock(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	pos := BlockPos{X: 1, Y: 5, Z: 1}
+	w.SetBlock(pos.X, pos.Y, pos.Z, BlockWallSign<<4|2)
+	w.SetSign(pos, [4]string{"text"})
+
+	w.SetBlock(pos.X, pos.Y, pos.Z, 0)
+	if _, ok := w.Sign(pos); ok {
+		t.Error("breaking the sign should remove its text")
+	}
+}
+
+func TestSign_AdoptedFromLoadedChunk(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	w.SetChunkLoader(func(cx, cz int) *gen.ChunkData {
+		if cx != 0 || cz != 0 {
+			return nil
+		}
+		return &gen.ChunkData{Signs: []gen.Sign{{X: 3, Y: 5, Z: 4, Lines: [4]string{"saved"}}}}
+	})
+
+	w.GetOrGenerateChunk(0, 0)
+	if got, ok := w.Sign(BlockPos{X: 3, Y: 5, Z: 4}); !ok || got[0] != "saved" {
+		t.Errorf("Sign = %q (%v), want the saved text", got, ok)
+	}
+}
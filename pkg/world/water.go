@@ -0,0 +1,203 @@
+package world
+
+// Water block IDs. Metadata 0 is a source, 1-7 is the horizontal distance
+// from one, and 8 marks falling water.
+const (
+	blockFlowingWater = 8
+	blockWater        = 9
+
+	maxWaterLevel = 7
+	waterFalling  = 8
+)
+
+// flowTickDelay is how many ticks water waits before spreading one block.
+const flowTickDelay = 5
+
+// maxFlowUpdatesPerTick bounds how many queued water blocks are processed in
+// one tick; the rest wait for the next tick.
+const maxFlowUpdatesPerTick = 256
+
+// flowUpdate is a water block waiting to be processed at world age due.
+type flowUpdate struct {
+	pos BlockPos
+	due int64
+}
+
+func isWater(state int32) bool {
+	id := state >> 4
+	return id == blockFlowingWater || id == blockWater
+}
+
+// horizontal lists the offsets of a block's four horizontal neighbors.
+var horizontal = [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+
+// ScheduleFlow queues a water update for the block at (x, y, z) and each of
+// its neighbors that holds water. It is called after a block there is placed
+// or removed, so that new water spreads and water next to a gap flows in.
+func (w *World) ScheduleFlow(x, y, z int) {
+	origin := BlockPos{x, y, z}
+	neighbors := adjacent(origin)
+	var water []BlockPos
+	for _, pos := range append(neighbors[:], origin) {
+		if isWater(w.GetBlock(pos.X, pos.Y, pos.Z)) {
+			water = append(water, pos)
+		}
+	}
+	if len(water) == 0 {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, pos := range water {
+		w.queueFlow(pos)
+	}
+}
+
+// queueFlow adds pos to the flow queue unless it is already waiting.
+// Callers must hold w.mu.
+func (w *World) queueFlow(pos BlockPos) {
+	if w.flowQueued[pos] {
+		return
+	}
+	w.flowQueued[pos] = true
+	w.flowQueue = append(w.flowQueue, flowUpdate{pos: pos, due: w.age + flowTickDelay})
+}
+
+// TickFlow processes the queued water blocks that are due, up to
+// maxFlowUpdatesPerTick, and returns the resulting block changes. It is
+// called once per game tick after Tick.
+func (w *World) TickFlow() []BlockUpdate {
+	w.mu.Lock()
+	n := 0
+	for n < len(w.flowQueue) && n < maxFlowUpdatesPerTick && w.flowQueue[n].due <= w.age {
+		n++
+	}
+	batch := make([]BlockPos, n)
+	for i, u := range w.flowQueue[:n] {
+		batch[i] = u.pos
+		delete(w.flowQueued, u.pos)
+	}
+	w.flowQueue = w.flowQueue[n:]
+	w.mu.Unlock()
+
+	var updates []BlockUpdate
+	for _, pos := range batch {
+		updates = append(updates, w.flowWater(pos)...)
+	}
+	return updates
+}
+
+// flowWater settles the water block at pos: flowing water takes the level its
+// neighbors feed it, or dries up, and then water spreads down into air, or
+// sideways when it rests on a solid block or is a source. Water does not
+// spread past the world border.
+func (w *World) flowWater(pos BlockPos) []BlockUpdate {
+	state := w.GetBlock(pos.X, pos.Y, pos.Z)
+	if !isWater(state) {
+		return nil
+	}
+
+	border := w.Border()
+	var updates []BlockUpdate
+	set := func(p BlockPos, s int32) {
+		w.SetBlock(p.X, p.Y, p.Z, s)
+		updates = append(updates, BlockUpdate{Pos: p, State: s})
+		w.mu.Lock()
+		w.queueFlow(p)
+		w.mu.Unlock()
+	}
+
+	level := int(state & 0xF)
+	if level != 0 {
+		want := w.fedLevel(pos)
+		if want < 0 {
+			set(pos, 0)
+			w.queueWaterNeighbors(pos)
+			return updates
+		}
+		if want != level {
+			set(pos, flowingWater(want))
+			w.queueWaterNeighbors(pos)
+			level = want
+		}
+	}
+
+	below := BlockPos{pos.X, pos.Y - 1, pos.Z}
+	belowState := w.GetBlock(below.X, below.Y, below.Z)
+	if pos.Y > 0 && belowState == 0 {
+		set(below, flowingWater(waterFalling))
+		return updates
+	}
+	if level != 0 && (pos.Y == 0 || isWater(belowState)) {
+		return updates
+	}
+
+	next := level + 1
+	if level >= waterFalling {
+		next = 1
+	}
+	if next > maxWaterLevel {
+		return updates
+	}
+	for _, d := range horizontal {
+		n := BlockPos{pos.X + d[0], pos.Y, pos.Z + d[1]}
+		if border.ContainsBlock(n.X, n.Z) && w.GetBlock(n.X, n.Y, n.Z) == 0 {
+			set(n, flowingWater(next))
+		}
+	}
+	return updates
+}
+
+// fedLevel returns the level flowing water at pos should have given its
+// neighbors, or -1 if nothing feeds it.
+func (w *World) fedLevel(pos BlockPos) int {
+	if isWater(w.GetBlock(pos.X, pos.Y+1, pos.Z)) {
+		return waterFalling
+	}
+
+	best := -1
+	for _, d := range horizontal {
+		s := w.GetBlock(pos.X+d[0], pos.Y, pos.Z+d[1])
+		if !isWater(s) {
+			continue
+		}
+		l := int(s & 0xF)
+		if l >= waterFalling {
+			l = 0
+		}
+		if best < 0 || l+1 < best {
+			best = l + 1
+		}
+	}
+	if best > maxWaterLevel {
+		return -1
+	}
+	return best
+}
+
+// queueWaterNeighbors queues the water neighbors of pos so they can react to
+// its change.
+func (w *World) queueWaterNeighbors(pos BlockPos) {
+	for _, n := range adjacent(pos) {
+		if isWater(w.GetBlock(n.X, n.Y, n.Z)) {
+			w.mu.Lock()
+			w.queueFlow(n)
+			w.mu.Unlock()
+		}
+	}
+}
+
+// adjacent returns the six blocks sharing a face with pos.
+func adjacent(pos BlockPos) [6]BlockPos {
+	return [6]BlockPos{
+		{pos.X, pos.Y + 1, pos.Z}, {pos.X, pos.Y - 1, pos.Z},
+		{pos.X + 1, pos.Y, pos.Z}, {pos.X - 1, pos.Y, pos.Z},
+		{pos.X, pos.Y, pos.Z + 1}, {pos.X, pos.Y, pos.Z - 1},
+	}
+}
+
+// flowingWater returns the flowing water state with the given level.
+func flowingWater(level int) int32 {
+	return blockFlowingWater<<4 | int32(level)
+}
@@ -0,0 +1,139 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/go-theft-craft/server/pkg/world/gen"
+)
+
+// settleFlow ticks the world until the flow queue is empty.
+func settleFlow(t *testing.T, w *World) {
+	t.Helper()
+	for i := 0; len(w.flowQueue) > 0; i++ {
+		if i > 1000 {
+			t.Fatalf("water still flowing after %d ticks", i)
+		}
+		w.Tick()
+		w.TickFlow()
+	}
+}
+
+func TestWaterSourceSpreadsOnFlatPlane(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	w.SetBlock(0, 5, 0, blockWater<<4)
+	w.ScheduleFlow(0, 5, 0)
+	settleFlow(t, w)
+
+	for x := -9; x <= 9; x++ {
+		for z := -9; z <= 9; z++ {
+			dist := gen.Abs(x) + gen.Abs(z)
+			got := w.GetBlock(x, 5, z)
+			var want int32
+			switch {
+			case dist == 0:
+				want = blockWater << 4
+			case dist <= maxWaterLevel:
+				want = flowingWater(dist)
+			}
+			if got != want {
+				t.Fatalf("block at (%d,5,%d) = %d, want %d", x, z, got, want)
+			}
+		}
+	}
+	if got := w.GetBlock(0, 4, 0); got != 2<<4 {
+		t.Errorf("ground under source = %d, want grass", got)
+	}
+}
+
+func TestWaterFallsThenSpreads(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	w.SetBlock(0, 9, 0, blockWater<<4)
+	w.SetBlock(0, 8, 0, 1<<4) // stone ledge under the source
+	w.SetBlock(1, 8, 0, 0)
+	w.ScheduleFlow(0, 9, 0)
+	settleFlow(t, w)
+
+	if got := w.GetBlock(1, 9, 0); got != flowingWater(1) {
+		t.Fatalf("beside source = %d, want level 1", got)
+	}
+	for y := 5; y <= 8; y++ {
+		if got := w.GetBlock(1, y, 0); got != flowingWater(waterFalling) {
+			t.Fatalf("column at y=%d = %d, want falling water", y, got)
+		}
+	}
+	if got := w.GetBlock(2, 5, 0); got != flowingWater(1) {
+		t.Errorf("beside landing = %d, want level 1", got)
+	}
+}
+
+func TestRemovingBlockNextToWaterReflows(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	for _, d := range horizontal {
+		w.SetBlock(d[0], 5, d[1], 1<<4)
+	}
+	w.SetBlock(0, 5, 0, blockWater<<4)
+	w.ScheduleFlow(0, 5, 0)
+	settleFlow(t, w)
+	if got := w.GetBlock(2, 5, 0); got != 0 {
+		t.Fatalf("water escaped the walls: %d", got)
+	}
+
+	w.SetBlock(1, 5, 0, 0)
+	w.ScheduleFlow(1, 5, 0)
+	settleFlow(t, w)
+	if got := w.GetBlock(1, 5, 0); got != flowingWater(1) {
+		t.Errorf("gap = %d, want level 1", got)
+	}
+	if got := w.GetBlock(2, 5, 0); got != flowingWater(2) {
+		t.Errorf("past gap = %d, want level 2", got)
+	}
+}
+
+func TestFlowingWaterDriesWithoutSource(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	w.SetBlock(0, 5, 0, blockWater<<4)
+	w.ScheduleFlow(0, 5, 0)
+	settleFlow(t, w)
+
+	w.SetBlock(0, 5, 0, 0)
+	w.ScheduleFlow(0, 5, 0)
+	settleFlow(t, w)
+	for x := -8; x <= 8; x++ {
+		if got := w.GetBlock(x, 5, 0); got != 0 {
+			t.Fatalf("block at (%d,5,0) = %d after source removed, want air", x, got)
+		}
+	}
+}
+
+func TestTickFlowCapsUpdatesPerTick(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	for x := 0; x < maxFlowUpdatesPerTick+10; x++ {
+		w.SetBlock(x*20, 5, 0, blockWater<<4)
+		w.ScheduleFlow(x*20, 5, 0)
+	}
+	for i := 0; i < flowTickDelay; i++ {
+		w.Tick()
+	}
+
+	w.TickFlow()
+	if got := len(w.flowQueue) - 4*maxFlowUpdatesPerTick; got != 10 {
+		t.Errorf("sources left waiting = %d, want 10", got)
+	}
+}
+
+func TestWaterStopsAtBorder(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	b := w.Border()
+	b.Diameter = 6 // -3 to 3
+	w.SetBorder(b)
+	w.SetBlock(0, 5, 0, blockWater<<4)
+	w.ScheduleFlow(0, 5, 0)
+	settleFlow(t, w)
+
+	if got := w.GetBlock(2, 5, 0); got != flowingWater(2) {
+		t.Errorf("block inside the border = %d, want flowing water", got)
+	}
+	if got := w.GetBlock(3, 5, 0); got != 0 {
+		t.Errorf("water spread past the border: %d", got)
+	}
+}
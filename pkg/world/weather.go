@@ -0,0 +1,288 @@
+package world
+
+import (
+	"math/rand"
+
+	"github.com/go-theft-craft/server/pkg/world/gen"
+)
+
+// Block states used by weather accumulation.
+const (
+	stateGrass     int32 = 2 << 4
+	stateWater     int32 = 9 << 4 // stationary water source
+	stateSnowLayer int32 = 78 << 4
+	stateIce       int32 = 79 << 4
+)
+
+// maxWeatherUpdatesPerTick bounds how many blocks weather may change in one tick.
+const maxWeatherUpdatesPerTick = 32
+
+// weatherColumnChance is the 1-in-N chance that a ticked chunk has one column
+// checked for snow or ice, matching vanilla's per-chunk weather roll.
+const weatherColumnChance = 16
+
+// BlockUpdate describes a block state change made by world simulation.
+type BlockUpdate struct {
+	Pos   BlockPos
+	State int32
+}
+
+// weatherBlock records a block placed by weather so it can be reverted.
+type weatherBlock struct {
+	original int32
+	placed   int32
+}
+
+// IsSnowyBiome reports whether precipitation in the biome falls as snow.
+func IsSnowyBiome(biome byte) bool {
+	switch biome {
+	case 10, // frozen ocean
+		11,  // frozen river
+		12,  // ice plains (tundra)
+		13,  // ice mountains
+		26,  // cold beach
+		30,  // cold taiga (snowy taiga)
+		31,  // cold taiga hills
+		140: // ice plains spikes
+		return true
+	}
+	return false
+}
+
+// Weather is the world's precipitation state.
+type Weather int
+
+const (
+	WeatherClear Weather = iota
+	WeatherRain
+	WeatherThunder
+)
+
+// String returns the weather's command name.
+func (wt Weather) String() string {
+	switch wt {
+	case WeatherRain:
+		return "rain"
+	case WeatherThunder:
+		return "thunder"
+	default:
+		return "clear"
+	}
+}
+
+// ParseWeather parses a weather command name.
+func ParseWeather(s string) (Weather, bool) {
+	for _, wt := range []Weather{WeatherClear, WeatherRain, WeatherThunder} {
+		if wt.String() == s {
+			return wt, true
+		}
+	}
+	return WeatherClear, false
+}
+
+// Vanilla weather cycle lengths in ticks: clear skies last 10 to 160
+// minutes, rain 10 to 20 minutes.
+const (
+	clearWeatherMin = 12000
+	clearWeatherMax = 192000
+	rainWeatherMin  = 12000
+	rainWeatherMax  = 24000
+
+	// thunderChance is the 1-in-N chance that rain arrives as a thunderstorm.
+	thunderChance = 4
+)
+
+// SetRaining starts or stops rain. Stopping rain also ends any thunderstorm.
+func (w *World) SetRaining(raining bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.raining = raining
+	if !raining {
+		w.thundering = false
+	}
+}
+
+// Weather returns the current weather.
+func (w *World) Weather() Weather {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.weatherLocked()
+}
+
+func (w *World) weatherLocked() Weather {
+	switch {
+	case w.thundering:
+		return WeatherThunder
+	case w.raining:
+		return WeatherRain
+	default:
+		return WeatherClear
+	}
+}
+
+// SetWeather changes the weather for the given number of ticks, after which
+// the random cycle resumes. A non-positive duration lets the cycle pick one.
+func (w *World) SetWeather(wt Weather, duration int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.raining = wt != WeatherClear
+	w.thundering = wt == WeatherThunder
+	w.weatherTicks = max(duration, 0)
+}
+
+// TickWeatherCycle advances the random weather cycle by one tick. It reports
+// the new weather and true when the weather changed.
+func (w *World) TickWeatherCycle(rng *rand.Rand) (Weather, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	cur := w.weatherLocked()
+	if w.weatherTicks <= 0 {
+		w.weatherTicks = weatherDuration(cur, rng)
+		return cur, false
+	}
+	w.weatherTicks--
+	if w.weatherTicks > 0 {
+		return cur, false
+	}
+
+	next := WeatherClear
+	if cur == WeatherClear {
+		next = WeatherRain
+		if rng.Intn(thunderChance) == 0 {
+			next = WeatherThunder
+		}
+	}
+	w.raining = next != WeatherClear
+	w.thundering = next == WeatherThunder
+	w.weatherTicks = weatherDuration(next, rng)
+	return next, true
+}
+
+// weatherDuration picks how many ticks wt lasts before the cycle moves on.
+func weatherDuration(wt Weather, rng *rand.Rand) int64 {
+	if wt == WeatherClear {
+		return clearWeatherMin + rng.Int63n(clearWeatherMax-clearWeatherMin)
+	}
+	return rainWeatherMin + rng.Int63n(rainWeatherMax-rainWeatherMin)
+}
+
+// IsRaining reports whether it is currently raining.
+func (w *World) IsRaining() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.raining
+}
+
+// TickWeather applies one tick of weather to the given chunks and returns the
+// resulting block changes. While raining, snowy biomes gain snow layers on
+// exposed grass and ice on exposed water. Otherwise, blocks placed by weather
+// melt back to their original state. At most maxWeatherUpdatesPerTick blocks
+// change per call.
+func (w *World) TickWeather(chunks []gen.ChunkPos, rng *rand.Rand) []BlockUpdate {
+	if !w.IsRaining() {
+		return w.meltWeatherBlocks(maxWeatherUpdatesPerTick)
+	}
+
+	var updates []BlockUpdate
+	for _, cp := range chunks {
+		if len(updates) >= maxWeatherUpdatesPerTick {
+			break
+		}
+		if rng.Intn(weatherColumnChance) != 0 {
+			continue
+		}
+		x := cp.X*16 + rng.Intn(16)
+		z := cp.Z*16 + rng.Intn(16)
+		if u, ok := w.weatherColumn(x, z); ok {
+			updates = append(updates, u)
+		}
+	}
+	return updates
+}
+
+// weatherColumn applies rain to the topmost block of a single column.
+func (w *World) weatherColumn(x, z int) (BlockUpdate, bool) {
+	y := w.TopBlockY(x, z)
+	if y < 0 {
+		return BlockUpdate{}, false
+	}
+	top := w.GetBlock(x, y, z)
+
+	if !IsSnowyBiome(w.GetBiome(x, z)) {
+		return w.revertWeatherBlock(BlockPos{x, y, z})
+	}
+
+	switch {
+	case top == stateWater:
+		return w.placeWeatherBlock(BlockPos{x, y, z}, top, stateIce), true
+	case top == stateGrass && y < 255:
+		return w.placeWeatherBlock(BlockPos{x, y + 1, z}, 0, stateSnowLayer), true
+	}
+	return BlockUpdate{}, false
+}
+
+// TopBlockY returns the Y of the highest non-air block in a column, or -1.
+func (w *World) TopBlockY(x, z int) int {
+	for y := 255; y >= 0; y-- {
+		if w.GetBlock(x, y, z) != 0 {
+			return y
+		}
+	}
+	return -1
+}
+
+func (w *World) placeWeatherBlock(pos BlockPos, original, placed int32) BlockUpdate {
+	w.SetBlock(pos.X, pos.Y, pos.Z, placed)
+
+	w.mu.Lock()
+	if prev, ok := w.weatherBlocks[pos]; ok {
+		original = prev.original
+	}
+	w.weatherBlocks[pos] = weatherBlock{original: original, placed: placed}
+	w.mu.Unlock()
+
+	return BlockUpdate{Pos: pos, State: placed}
+}
+
+// revertWeatherBlock restores a weather-placed block at pos, if any.
+func (w *World) revertWeatherBlock(pos BlockPos) (BlockUpdate, bool) {
+	w.mu.Lock()
+	wb, ok := w.weatherBlocks[pos]
+	if ok {
+		delete(w.weatherBlocks, pos)
+	}
+	w.mu.Unlock()
+
+	// Leave the block alone if something else changed it since.
+	if !ok || w.GetBlock(pos.X, pos.Y, pos.Z) != wb.placed {
+		return BlockUpdate{}, false
+	}
+	w.SetBlock(pos.X, pos.Y, pos.Z, wb.original)
+	return BlockUpdate{Pos: pos, State: wb.original}, true
+}
+
+// meltWeatherBlocks reverts up to limit weather-placed blocks.
+func (w *World) meltWeatherBlocks(limit int) []BlockUpdate {
+	w.mu.RLock()
+	if len(w.weatherBlocks) == 0 {
+		w.mu.RUnlock()
+		return nil
+	}
+	positions := make([]BlockPos, 0, limit)
+	for pos := range w.weatherBlocks {
+		if len(positions) >= limit {
+			break
+		}
+		positions = append(positions, pos)
+	}
+	w.mu.RUnlock()
+
+	var updates []BlockUpdate
+	for _, pos := range positions {
+		if u, ok := w.revertWeatherBlock(pos); ok {
+			updates = append(updates, u)
+		}
+	}
+	return updates
+}
@@ -0,0 +1,115 @@
+package world
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/go-theft-craft/server/pkg/world/gen"
+)
+
+// biomeGenerator is a flat generator with a fixed biome and a surface block at y=4.
+type biomeGenerator struct {
+	biome   byte
+	surface uint16
+}
+
+func (g biomeGenerator) Generate(_, _ int) *gen.ChunkData {
+	c := &gen.ChunkData{}
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			c.SetBlock(x, 0, z, 7<<4)
+			c.SetBlock(x, 1, z, 1<<4)
+			c.SetBlock(x, 2, z, 1<<4)
+			c.SetBlock(x, 3, z, 3<<4)
+			c.SetBlock(x, 4, z, g.surface)
+			c.SetBiome(x, z, g.biome)
+		}
+	}
+	return c
+}
+
+func (g biomeGenerator) HeightAt(_, _ int) int         { return 4 }
+func (biomeGenerator) HeightsFor(_, _ int) [16][16]int { return gen.UniformHeights(4) }
+
+// tickWeatherN runs n weather ticks over chunk (0,0).
+func tickWeatherN(w *World, n int) []BlockUpdate {
+	rng := rand.New(rand.NewSource(1))
+	chunks := []gen.ChunkPos{{X: 0, Z: 0}}
+	var all []BlockUpdate
+	for i := 0; i < n; i++ {
+		all = append(all, w.TickWeather(chunks, rng)...)
+	}
+	return all
+}
+
+func TestTickWeatherSnowOnGrass(t *testing.T) {
+	w := NewWorld(biomeGenerator{biome: 12, surface: uint16(stateGrass)})
+	w.SetRaining(true)
+
+	updates := tickWeatherN(w, 500)
+	if len(updates) == 0 {
+		t.Fatal("expected snow layers after raining in tundra")
+	}
+	for _, u := range updates {
+		if u.State != stateSnowLayer || u.Pos.Y != 5 {
+			t.Errorf("unexpected update %+v, want snow layer at y=5", u)
+		}
+		if got := w.GetBlock(u.Pos.X, u.Pos.Y, u.Pos.Z); got != stateSnowLayer {
+			t.Errorf("GetBlock at %+v = %d, want snow layer", u.Pos, got)
+		}
+	}
+}
+
+func TestTickWeatherNoSnowInWarmBiome(t *testing.T) {
+	w := NewWorld(biomeGenerator{biome: 1, surface: uint16(stateGrass)})
+	w.SetRaining(true)
+
+	if updates := tickWeatherN(w, 500); len(updates) != 0 {
+		t.Errorf("expected no weather updates in plains, got %d", len(updates))
+	}
+}
+
+func TestTickWeatherIceMeltsWhenClear(t *testing.T) {
+	w := NewWorld(biomeGenerator{biome: 12, surface: uint16(stateWater)})
+	w.SetRaining(true)
+
+	frozen := tickWeatherN(w, 500)
+	if len(frozen) == 0 {
+		t.Fatal("expected ice to form on exposed water")
+	}
+	pos := frozen[0].Pos
+	if got := w.GetBlock(pos.X, pos.Y, pos.Z); got != stateIce {
+		t.Fatalf("GetBlock at %+v = %d, want ice", pos, got)
+	}
+
+	w.SetRaining(false)
+	tickWeatherN(w, 100)
+
+	for _, u := range frozen {
+		if got := w.GetBlock(u.Pos.X, u.Pos.Y, u.Pos.Z); got != stateWater {
+			t.Errorf("GetBlock at %+v after clearing = %d, want water", u.Pos, got)
+		}
+	}
+	if len(w.GetBlockOverrides()) != 0 {
+		t.Error("expected no block overrides once weather blocks melted")
+	}
+}
+
+func TestWeatherCycle_CommandDurationExpires(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	rng := rand.New(rand.NewSource(1))
+	w.SetWeather(WeatherThunder, 5)
+
+	for i := 1; i < 5; i++ {
+		if _, changed := w.TickWeatherCycle(rng); changed {
+			t.Fatalf("weather changed after %d ticks, want 5", i)
+		}
+	}
+	wt, changed := w.TickWeatherCycle(rng)
+	if !changed || wt != WeatherClear {
+		t.Fatalf("after 5 ticks weather = %v (changed %v), want clear", wt, changed)
+	}
+	if w.IsRaining() {
+		t.Error("still raining after the storm cleared")
+	}
+}
@@ -3,6 +3,7 @@ package world
 import (
 	"sync"
 
+	"github.com/go-theft-craft/server/pkg/gamedata"
 	"github.com/go-theft-craft/server/pkg/world/gen"
 )
 
@@ -16,24 +17,103 @@ type World struct {
 	mu        sync.RWMutex
 	blocks    map[BlockPos]int32
 	generator gen.Generator
-	chunks    map[gen.ChunkPos]*gen.ChunkData
+	chunks    map[gen.ChunkPos]*gen.ChunkData // fully generated chunks only
+	gameData  *gamedata.GameData              // optional; used for block names
+
+	// Optional source of saved chunks, consulted before the generator
+	// (protected by mu).
+	loader ChunkLoader
+
+	// Chunks being generated, closed when generation completes (protected by mu).
+	generating map[gen.ChunkPos]chan struct{}
+
+	// Chunks around spawn that are never unloaded, and how far around the
+	// spawn chunk they reach (protected by mu).
+	spawnChunks      map[gen.ChunkPos]bool
+	spawnChunkRadius int
 
 	// Time tracking (protected by mu).
 	age       int64 // total ticks since world creation
 	timeOfDay int64 // 0-23999 cycle; negative = frozen
+
+	// Game rules changed from their defaults (protected by mu).
+	gameRules map[string]string
+
+	// World spawn set by /setworldspawn (protected by mu); unset means
+	// the surface at (0, 0).
+	spawn    BlockPos
+	spawnSet bool
+
+	// Sign text by chunk and position (protected by mu). Entries are
+	// removed when the sign block is replaced.
+	signs map[gen.ChunkPos]map[BlockPos][4]string
+
+	// World border (protected by mu): the border set with /worldborder,
+	// or, when unset, the default border of the world's size.
+	border        Border
+	borderSet     bool
+	defaultBorder Border
+
+	// Lit copies of chunks returned by LitChunk (protected by mu), and a
+	// counter bumped whenever cached lighting is invalidated, so a result
+	// computed across an invalidation is not cached.
+	lit        map[gen.ChunkPos]*gen.ChunkData
+	lightEpoch uint64
+
+	// Block changes not yet saved to the chunk's region (protected by mu):
+	// the value of changeCount at each chunk's latest change.
+	changed     map[gen.ChunkPos]uint64
+	changeCount uint64
+
+	// Biome overrides per block column (protected by mu).
+	biomes map[ColumnPos]byte
+
+	// Weather state (protected by mu).
+	raining       bool
+	weatherBlocks map[BlockPos]weatherBlock // snow/ice placed by weather
+
+	// Weather cycle (protected by mu): whether the rain is a thunderstorm
+	// and how many ticks remain until the weather changes (0 = not yet
+	// rolled).
+	thundering   bool
+	weatherTicks int64
+
+	// Scheduled block updates (protected by mu): requested updates wait for
+	// a delay, scheduled ones map to the world age they run at.
+	requested map[BlockPos]struct{}
+	scheduled map[BlockPos]int64
+
+	// Water blocks waiting to flow, in the order they were queued
+	// (protected by mu).
+	flowQueue  []flowUpdate
+	flowQueued map[BlockPos]bool
 }
 
 // NewWorld creates a new World with the given generator.
 func NewWorld(generator gen.Generator) *World {
 	return &World{
-		blocks:    make(map[BlockPos]int32),
-		generator: generator,
-		chunks:    make(map[gen.ChunkPos]*gen.ChunkData),
+		blocks:        make(map[BlockPos]int32),
+		generator:     generator,
+		chunks:        make(map[gen.ChunkPos]*gen.ChunkData),
+		lit:           make(map[gen.ChunkPos]*gen.ChunkData),
+		changed:       make(map[gen.ChunkPos]uint64),
+		generating:    make(map[gen.ChunkPos]chan struct{}),
+		spawnChunks:   make(map[gen.ChunkPos]bool),
+		biomes:        make(map[ColumnPos]byte),
+		gameRules:     make(map[string]string),
+		defaultBorder: DefaultBorder(),
+		signs:         make(map[gen.ChunkPos]map[BlockPos][4]string),
+		weatherBlocks: make(map[BlockPos]weatherBlock),
+		requested:     make(map[BlockPos]struct{}),
+		scheduled:     make(map[BlockPos]int64),
+		flowQueued:    make(map[BlockPos]bool),
 	}
 }
 
 // GetOrGenerateChunk returns the ChunkData for the given chunk coordinates,
-// generating and caching it if needed.
+// generating and caching it if needed. A chunk is only published to the cache
+// once generation has completed; concurrent callers asking for a chunk that is
+// still being generated wait for it instead of generating it again.
 func (w *World) GetOrGenerateChunk(cx, cz int) *gen.ChunkData {
 	pos := gen.ChunkPos{X: cx, Z: cz}
 
@@ -44,19 +124,65 @@ func (w *World) GetOrGenerateChunk(cx, cz int) *gen.ChunkData {
 	}
 	w.mu.RUnlock()
 
-	c := w.generator.Generate(cx, cz)
-
 	w.mu.Lock()
 	// Double-check after acquiring write lock.
 	if existing, ok := w.chunks[pos]; ok {
 		w.mu.Unlock()
 		return existing
 	}
+	if done, ok := w.generating[pos]; ok {
+		w.mu.Unlock()
+		<-done
+		return w.GetOrGenerateChunk(cx, cz)
+	}
+	done := make(chan struct{})
+	w.generating[pos] = done
+	w.mu.Unlock()
+
+	c := w.loadChunk(cx, cz)
+	if c == nil {
+		c = w.generator.Generate(cx, cz)
+	}
+
+	w.mu.Lock()
+	for _, sign := range c.Signs {
+		bpos := BlockPos{sign.X, sign.Y, sign.Z}
+		if _, ok := w.signs[pos][bpos]; !ok {
+			w.storeSign(bpos, sign.Lines)
+		}
+	}
+	c.Signs = nil
 	w.chunks[pos] = c
+	w.invalidateLight(cx, cz)
+	delete(w.generating, pos)
 	w.mu.Unlock()
+	close(done)
 	return c
 }
 
+// ChunkLoader returns a previously saved chunk, or nil if (cx, cz) has not
+// been saved.
+type ChunkLoader func(cx, cz int) *gen.ChunkData
+
+// SetChunkLoader makes chunks that are not cached come from load when it has
+// them, and from the generator otherwise.
+func (w *World) SetChunkLoader(load ChunkLoader) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.loader = load
+}
+
+// loadChunk returns chunk (cx, cz) from the chunk loader, or nil.
+func (w *World) loadChunk(cx, cz int) *gen.ChunkData {
+	w.mu.RLock()
+	load := w.loader
+	w.mu.RUnlock()
+	if load == nil {
+		return nil
+	}
+	return load(cx, cz)
+}
+
 // GetBlock returns the block state ID at the given position.
 // Checks overrides first, then falls back to the generated chunk.
 func (w *World) GetBlock(x, y, z int) int32 {
@@ -84,6 +210,25 @@ func (w *World) GetBlock(x, y, z int) int32 {
 	return int32(c.GetBlock(lx, y, lz))
 }
 
+// LoadedBlock is like GetBlock but never generates or loads a chunk: it
+// reports false when the block's chunk is not in memory.
+func (w *World) LoadedBlock(x, y, z int) (int32, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	c, ok := w.chunks[gen.ChunkPos{X: x >> 4, Z: z >> 4}]
+	if !ok {
+		return 0, false
+	}
+	if s, ok := w.blocks[BlockPos{x, y, z}]; ok {
+		return s, true
+	}
+	if y < 0 || y >= 256 {
+		return 0, true
+	}
+	return int32(c.GetBlock(x&0xF, y, z&0xF)), true
+}
+
 // SetBlock stores a block state override.
 func (w *World) SetBlock(x, y, z int, stateID int32) {
 	// Ensure the chunk is generated so we know the base state.
@@ -105,9 +250,49 @@ func (w *World) SetBlock(x, y, z int, stateID int32) {
 	} else {
 		w.blocks[bpos] = stateID
 	}
+	if !IsSign(stateID) {
+		w.deleteSign(bpos)
+	}
+	w.invalidateLight(cx, cz)
+	w.markChanged(cx, cz)
 }
 
-// ForEachChunk calls fn for each generated chunk under a read lock.
+// markChanged records a change to chunk (cx, cz). The caller holds mu.
+func (w *World) markChanged(cx, cz int) {
+	w.changeCount++
+	w.changed[gen.ChunkPos{X: cx, Z: cz}] = w.changeCount
+}
+
+// ChangeCount returns a counter that grows with every block change. Read it
+// before encoding chunks for saving and pass it to MarkChunkSaved.
+func (w *World) ChangeCount() uint64 {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.changeCount
+}
+
+// ChunkChanged reports whether chunk (cx, cz) has block changes that have
+// not been marked saved.
+func (w *World) ChunkChanged(cx, cz int) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	_, ok := w.changed[gen.ChunkPos{X: cx, Z: cz}]
+	return ok
+}
+
+// MarkChunkSaved records that chunk (cx, cz) was saved with every change up
+// to count, as returned by ChangeCount before the chunk was encoded.
+func (w *World) MarkChunkSaved(cx, cz int, count uint64) {
+	pos := gen.ChunkPos{X: cx, Z: cz}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.changed[pos] <= count {
+		delete(w.changed, pos)
+	}
+}
+
+// ForEachChunk calls fn for each generated chunk under a read lock. Chunks
+// still being generated are skipped.
 func (w *World) ForEachChunk(fn func(pos gen.ChunkPos, chunk *gen.ChunkData)) {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
@@ -131,6 +316,39 @@ func (w *World) OverridesForChunk(cx, cz int) map[BlockPos]int32 {
 	return result
 }
 
+// RegenerateChunk discards the cached terrain of chunk (cx, cz) together with
+// the block, biome and weather overrides and signs inside it, and runs the
+// generator again. It returns the number of block overrides removed.
+func (w *World) RegenerateChunk(cx, cz int) int {
+	c := w.generator.Generate(cx, cz)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	removed := 0
+	for pos := range w.blocks {
+		if pos.X>>4 == cx && pos.Z>>4 == cz {
+			delete(w.blocks, pos)
+			removed++
+		}
+	}
+	for pos := range w.weatherBlocks {
+		if pos.X>>4 == cx && pos.Z>>4 == cz {
+			delete(w.weatherBlocks, pos)
+		}
+	}
+	for pos := range w.biomes {
+		if pos.X>>4 == cx && pos.Z>>4 == cz {
+			delete(w.biomes, pos)
+		}
+	}
+	delete(w.signs, gen.ChunkPos{X: cx, Z: cz})
+	w.chunks[gen.ChunkPos{X: cx, Z: cz}] = c
+	w.invalidateLight(cx, cz)
+	w.markChanged(cx, cz)
+	return removed
+}
+
 // PreGenerateRadius generates all chunks within the given radius centered on (0,0).
 func (w *World) PreGenerateRadius(radius int) int {
 	count := 0
@@ -143,19 +361,122 @@ func (w *World) PreGenerateRadius(radius int) int {
 	return count
 }
 
+// PreGenerateSpawnChunks generates all chunks inside the world border within
+// radius of the chunk holding the world spawn and keeps them resident, so
+// that UnloadChunk never drops them. They follow the spawn when SetSpawn
+// moves it. It returns the number of chunks pinned.
+func (w *World) PreGenerateSpawnChunks(radius int) int {
+	w.mu.Lock()
+	w.spawnChunkRadius = radius
+	w.mu.Unlock()
+	return w.pinSpawnChunks()
+}
+
+// pinSpawnChunks generates the chunks around the current spawn and makes
+// them the pinned spawn chunks, releasing any pinned before.
+func (w *World) pinSpawnChunks() int {
+	w.mu.RLock()
+	radius := w.spawnChunkRadius
+	w.mu.RUnlock()
+	spawn, border := w.Spawn(), w.Border()
+
+	pinned := make(map[gen.ChunkPos]bool)
+	for cx := spawn.X>>4 - radius; cx <= spawn.X>>4+radius; cx++ {
+		for cz := spawn.Z>>4 - radius; cz <= spawn.Z>>4+radius; cz++ {
+			if !border.ContainsChunk(cx, cz) {
+				continue
+			}
+			w.GetOrGenerateChunk(cx, cz)
+			pinned[gen.ChunkPos{X: cx, Z: cz}] = true
+		}
+	}
+
+	w.mu.Lock()
+	w.spawnChunks = pinned
+	w.mu.Unlock()
+	return len(pinned)
+}
+
+// IsSpawnChunk reports whether chunk (cx, cz) is kept loaded around spawn.
+func (w *World) IsSpawnChunk(cx, cz int) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.spawnChunks[gen.ChunkPos{X: cx, Z: cz}]
+}
+
+// UnloadChunk drops the cached terrain of chunk (cx, cz) to free memory. The
+// chunk is reloaded or regenerated on next access; block, biome and weather overrides are
+// stored separately and are unaffected. Spawn chunks are never unloaded.
+// It returns whether the chunk was removed.
+func (w *World) UnloadChunk(cx, cz int) bool {
+	pos := gen.ChunkPos{X: cx, Z: cz}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.spawnChunks[pos] {
+		return false
+	}
+	if _, ok := w.chunks[pos]; !ok {
+		return false
+	}
+	delete(w.chunks, pos)
+	delete(w.lit, pos)
+	return true
+}
+
 // SpawnHeight returns the terrain height at spawn (0, 0) + 1 for the player to stand on.
 func (w *World) SpawnHeight() int {
 	return w.generator.HeightAt(0, 0) + 1
 }
 
-// Tick advances the world age by one tick and, if timeOfDay is non-negative,
-// advances it within the 0-23999 range. Returns the new age and timeOfDay.
+// Spawn returns the block position players spawn at: the configured spawn
+// point if one was set, otherwise the surface above (0, 0).
+func (w *World) Spawn() BlockPos {
+	if pos, ok := w.SpawnPoint(); ok {
+		return pos
+	}
+	return BlockPos{0, w.SpawnHeight(), 0}
+}
+
+// SpawnPoint returns the configured spawn point and whether one was set.
+func (w *World) SpawnPoint() (BlockPos, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.spawn, w.spawnSet
+}
+
+// SetSpawn sets the world spawn point, moving the spawn chunks with it once
+// they are pinned.
+func (w *World) SetSpawn(pos BlockPos) {
+	w.mu.Lock()
+	w.spawn = pos
+	w.spawnSet = true
+	pinned := len(w.spawnChunks) > 0
+	w.mu.Unlock()
+
+	if pinned {
+		w.pinSpawnChunks()
+	}
+}
+
+// HeightsFor returns the generated terrain height of every column in chunk
+// (cx, cz), indexed [x][z]. It ignores block overrides.
+func (w *World) HeightsFor(cx, cz int) [16][16]int {
+	return w.generator.HeightsFor(cx, cz)
+}
+
+// Tick advances the world age by one tick and, if timeOfDay is non-negative
+// and the doDaylightCycle rule is on, advances it within the 0-23999 range.
+// Returns the new age and timeOfDay.
 func (w *World) Tick() (age, timeOfDay int64) {
+	cycle := w.GameRuleBool(RuleDoDaylightCycle)
+
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
 	w.age++
-	if w.timeOfDay >= 0 {
+	if w.timeOfDay >= 0 && cycle {
 		w.timeOfDay = (w.timeOfDay + 1) % 24000
 	}
 	return w.age, w.timeOfDay
@@ -168,6 +489,20 @@ func (w *World) GetTime() (age, timeOfDay int64) {
 	return w.age, w.timeOfDay
 }
 
+// ClientTime returns the world age and the time of day to send to clients.
+// The time of day is negative whenever the sun should stand still, either
+// because time is frozen or because doDaylightCycle is off.
+func (w *World) ClientTime() (age, timeOfDay int64) {
+	age, timeOfDay = w.GetTime()
+	if timeOfDay >= 0 && !w.GameRuleBool(RuleDoDaylightCycle) {
+		timeOfDay = -timeOfDay
+		if timeOfDay == 0 {
+			timeOfDay = -1 // -0 would not freeze the client
+		}
+	}
+	return age, timeOfDay
+}
+
 // SetTimeOfDay sets the time of day (0-23999, or negative to freeze).
 func (w *World) SetTimeOfDay(t int64) {
 	w.mu.Lock()
@@ -200,4 +535,5 @@ func (w *World) SetBlockOverrides(overrides map[BlockPos]int32) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	w.blocks = overrides
+	w.resetLight()
 }
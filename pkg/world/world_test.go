@@ -1,6 +1,8 @@
 package world
 
 import (
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/go-theft-craft/server/pkg/world/gen"
@@ -57,10 +59,7 @@ func TestWorldSetBlockRemovesRedundantOverride(t *testing.T) {
 	// Set air at y=10 (which is already air) — should not store an override.
 	w.SetBlock(0, 10, 0, 0)
 
-	w.mu.RLock()
-	_, exists := w.blocks[BlockPos{0, 10, 0}]
-	w.mu.RUnlock()
-	if exists {
+	if w.OverrideCount() != 0 {
 		t.Error("setting air at y=10 should not create an override")
 	}
 }
@@ -83,13 +82,13 @@ func TestPreGenerateRadius(t *testing.T) {
 	}
 
 	// Verify all 25 chunks are cached (no generation needed on second access).
+	loaded := make(map[gen.ChunkPos]bool)
+	for _, pos := range w.LoadedChunkPositions() {
+		loaded[pos] = true
+	}
 	for cx := -2; cx <= 2; cx++ {
 		for cz := -2; cz <= 2; cz++ {
-			pos := gen.ChunkPos{X: cx, Z: cz}
-			w.mu.RLock()
-			_, ok := w.chunks[pos]
-			w.mu.RUnlock()
-			if !ok {
+			if !loaded[gen.ChunkPos{X: cx, Z: cz}] {
 				t.Errorf("chunk (%d,%d) not pre-generated", cx, cz)
 			}
 		}
@@ -166,3 +165,192 @@ func TestWorldDefaultGenerator(t *testing.T) {
 		t.Errorf("SpawnHeight() = %d, want between 5 and 255", height)
 	}
 }
+func TestUnloadChunk_SpawnChunksStayLoaded(t *testing.T) {
+	g := &countingGenerator{Generator: gen.NewFlatGenerator(0), calls: make(map[gen.ChunkPos]int)}
+	w := NewWorld(g)
+	if n := w.PreGenerateSpawnChunks(1); n != 9 {
+		t.Errorf("PreGenerateSpawnChunks(1) returned %d, want 9", n)
+	}
+	w.GetOrGenerateChunk(5, 5)
+	w.SetBlock(80, 10, 80, 4<<4) // chunk (5, 5)
+
+	if w.UnloadChunk(1, -1) {
+		t.Error("spawn chunk (1, -1) was unloaded")
+	}
+	if !w.UnloadChunk(5, 5) {
+		t.Error("chunk (5, 5) was not unloaded")
+	}
+	if w.UnloadChunk(5, 5) {
+		t.Error("unloading an absent chunk reported success")
+	}
+
+	// The unloaded chunk is regenerated on access and keeps its overrides.
+	if got := w.GetBlock(80, 10, 80); got != 4<<4 {
+		t.Errorf("GetBlock(80,10,80) = %d after unload, want override", got)
+	}
+	w.GetOrGenerateChunk(5, 5)
+	if got := g.calls[gen.ChunkPos{X: 5, Z: 5}]; got != 2 {
+		t.Errorf("generator called %d times for (5, 5), want 2", got)
+	}
+	if got := g.calls[gen.ChunkPos{X: 1, Z: -1}]; got != 1 {
+		t.Errorf("generator called %d times for spawn chunk, want 1", got)
+	}
+}
+
+// countingGenerator wraps a generator and records Generate calls per chunk.
+type countingGenerator struct {
+	gen.Generator
+	calls map[gen.ChunkPos]int
+}
+
+func (g *countingGenerator) Generate(cx, cz int) *gen.ChunkData {
+	g.calls[gen.ChunkPos{X: cx, Z: cz}]++
+	return g.Generator.Generate(cx, cz)
+}
+
+func TestPreGenerateSpawnChunks_CenteredOnSpawn(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	w.SetSpawn(BlockPos{X: 200, Y: 4, Z: -40}) // chunk (12, -3)
+	w.PreGenerateSpawnChunks(1)
+
+	if !w.IsSpawnChunk(12, -3) || !w.IsSpawnChunk(13, -2) || !w.IsSpawnChunk(11, -4) {
+		t.Error("chunks around the spawn chunk (12, -3) are not pinned")
+	}
+	if w.IsSpawnChunk(0, 0) {
+		t.Error("chunk (0, 0) is pinned although the spawn is elsewhere")
+	}
+
+	w.SetSpawn(BlockPos{X: 0, Y: 4, Z: 0})
+	if !w.IsSpawnChunk(0, 0) || w.IsSpawnChunk(12, -3) {
+		t.Error("spawn chunks did not follow the spawn to (0, 0)")
+	}
+}
+
+func TestPreGenerateSpawnChunks_InsideBorder(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	w.SetDefaultBorder(RadiusBorder(1))
+
+	if n := w.PreGenerateSpawnChunks(3); n != 9 {
+		t.Errorf("PreGenerateSpawnChunks(3) pinned %d chunks, want the 9 inside the border", n)
+	}
+	if w.IsSpawnChunk(2, 0) {
+		t.Error("chunk (2, 0) outside the border was pinned")
+	}
+}
+
+func TestRegenerateChunk(t *testing.T) {
+	g := &countingGenerator{Generator: gen.NewFlatGenerator(0), calls: make(map[gen.ChunkPos]int)}
+	w := NewWorld(g)
+	w.SetBlock(3, 10, 5, 4<<4)  // chunk (0, 0)
+	w.SetBlock(20, 10, 5, 4<<4) // chunk (1, 0)
+	w.SetBiome(2, 2, 2)
+
+	if removed := w.RegenerateChunk(0, 0); removed != 1 {
+		t.Errorf("RegenerateChunk removed %d overrides, want 1", removed)
+	}
+
+	if got := g.calls[gen.ChunkPos{X: 0, Z: 0}]; got != 2 {
+		t.Errorf("generator called %d times for (0, 0), want 2", got)
+	}
+	if got := w.GetBlock(3, 10, 5); got != 0 {
+		t.Errorf("GetBlock(3,10,5) = %d after regenerate, want 0", got)
+	}
+	if got := w.GetBiome(2, 2); got != 1 {
+		t.Errorf("GetBiome(2,2) = %d after regenerate, want generated plains (1)", got)
+	}
+	if got := w.GetBlock(20, 10, 5); got != 4<<4 {
+		t.Errorf("override outside the chunk was touched: GetBlock(20,10,5) = %d", got)
+	}
+}
+
+// blockingGenerator holds Generate until release is closed.
+type blockingGenerator struct {
+	gen.Generator
+	started chan struct{}
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (g *blockingGenerator) Generate(cx, cz int) *gen.ChunkData {
+	if g.calls.Add(1) == 1 {
+		close(g.started)
+	}
+	<-g.release
+	return g.Generator.Generate(cx, cz)
+}
+
+func TestGetOrGenerateChunk_InFlightChunkHidden(t *testing.T) {
+	g := &blockingGenerator{
+		Generator: gen.NewFlatGenerator(0),
+		started:   make(chan struct{}),
+		release:   make(chan struct{}),
+	}
+	w := NewWorld(g)
+
+	var wg sync.WaitGroup
+	results := make([]*gen.ChunkData, 2)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0] = w.GetOrGenerateChunk(0, 0)
+	}()
+	<-g.started
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[1] = w.GetOrGenerateChunk(0, 0)
+	}()
+
+	w.ForEachChunk(func(pos gen.ChunkPos, _ *gen.ChunkData) {
+		t.Errorf("ForEachChunk visited chunk %v while it was still generating", pos)
+	})
+
+	close(g.release)
+	wg.Wait()
+
+	if n := g.calls.Load(); n != 1 {
+		t.Errorf("generator called %d times, want 1", n)
+	}
+	if results[0] == nil || results[0] != results[1] {
+		t.Error("concurrent callers should receive the same chunk")
+	}
+}
+
+func TestChunkLoaderPreferredOverGenerator(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	saved := &gen.ChunkData{}
+	saved.SetBlock(0, 4, 0, 1<<4) // stone where the flat world has grass
+	w.SetChunkLoader(func(cx, cz int) *gen.ChunkData {
+		if cx == 0 && cz == 0 {
+			return saved
+		}
+		return nil
+	})
+
+	if got := w.GetBlock(0, 4, 0); got != 1<<4 {
+		t.Errorf("saved chunk block = %d, want stone", got)
+	}
+	if got := w.GetBlock(16, 4, 0); got != 2<<4 {
+		t.Errorf("unsaved chunk block = %d, want generated grass", got)
+	}
+}
+
+func TestMarkChunkSaved_KeepsLaterChanges(t *testing.T) {
+	w := NewWorld(gen.NewFlatGenerator(0))
+	if w.ChunkChanged(0, 0) {
+		t.Fatal("a generated chunk counts as changed")
+	}
+	w.SetBlock(1, 5, 1, 4<<4)
+	count := w.ChangeCount()
+	w.SetBlock(2, 5, 1, 4<<4) // changed while the chunk was being saved
+
+	w.MarkChunkSaved(0, 0, count)
+	if !w.ChunkChanged(0, 0) {
+		t.Error("a change made after the save was marked saved")
+	}
+	w.MarkChunkSaved(0, 0, w.ChangeCount())
+	if w.ChunkChanged(0, 0) {
+		t.Error("chunk still changed after saving every change")
+	}
+}